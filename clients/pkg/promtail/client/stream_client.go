@@ -0,0 +1,195 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+var (
+	ErrStreamClientStopped = errors.New("stream client stopped")
+
+	// errPushCanceled fails the pushes pending on a stream that was reset because a push was canceled while sending.
+	errPushCanceled = errors.New("stream reset by a push canceled while sending")
+)
+
+// StreamPushError is returned by StreamClient.Push when the distributor rejected a batch.
+type StreamPushError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *StreamPushError) Error() string {
+	return fmt.Sprintf("server returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// StreamClient pushes batches over the gRPC streaming push API of the distributors.
+// It is safe for concurrent use, and pipelines the batches of concurrent pushes over a single stream,
+// which is reopened on the next push when it failed.
+type StreamClient struct {
+	client   logproto.StreamPusherClient
+	tenantID string
+
+	// sendLock serializes the sends, since a stream doesn't support concurrent sends.
+	// It is a channel rather than a mutex, so that waiting pushes can give up when their context is done.
+	sendLock chan struct{}
+
+	mtx     sync.Mutex
+	stream  logproto.StreamPusher_PushStreamClient
+	cancel  context.CancelFunc
+	nextID  uint64
+	pending map[uint64]chan error
+	stopped bool
+}
+
+// NewStreamClient makes a new StreamClient pushing on behalf of the tenant over the connection.
+// An empty tenantID doesn't send a tenant, for distributors with auth disabled.
+func NewStreamClient(conn *grpc.ClientConn, tenantID string) *StreamClient {
+	return &StreamClient{
+		client:   logproto.NewStreamPusherClient(conn),
+		tenantID: tenantID,
+		sendLock: make(chan struct{}, 1),
+		pending:  map[uint64]chan error{},
+	}
+}
+
+// Push sends the request as a batch over the stream, and waits for its acknowledgement.
+// Rejected batches return a *StreamPushError with the status code of the push.
+func (c *StreamClient) Push(ctx context.Context, req *logproto.PushRequest) error {
+	ack := make(chan error, 1)
+
+	select {
+	case c.sendLock <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	c.mtx.Lock()
+	stream, err := c.getStream()
+	if err != nil {
+		c.mtx.Unlock()
+		<-c.sendLock
+		return err
+	}
+	id := c.nextID
+	c.nextID++
+	c.pending[id] = ack
+	c.mtx.Unlock()
+
+	// Send blocks while the distributor applies backpressure, which holds back the concurrent pushes too.
+	// A push whose context is done while sending resets the stream, which unblocks the send.
+	sent := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.mtx.Lock()
+			c.resetStream(stream, errPushCanceled)
+			c.mtx.Unlock()
+		case <-sent:
+		}
+	}()
+	err = stream.Send(&logproto.PushStreamRequest{BatchID: id, Request: req})
+	close(sent)
+	if err != nil {
+		c.mtx.Lock()
+		c.resetStream(stream, err)
+		c.mtx.Unlock()
+	}
+	<-c.sendLock
+
+	if ctx.Err() != nil && err != nil {
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-ack:
+		return err
+	case <-ctx.Done():
+		c.mtx.Lock()
+		delete(c.pending, id)
+		c.mtx.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Stop closes the stream, and fails the pushes waiting for their acknowledgement.
+func (c *StreamClient) Stop() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.stopped = true
+	if c.stream != nil {
+		c.resetStream(c.stream, ErrStreamClientStopped)
+	}
+}
+
+// getStream returns the current stream, or opens a new one. Must hold mtx.
+func (c *StreamClient) getStream() (logproto.StreamPusher_PushStreamClient, error) {
+	if c.stopped {
+		return nil, ErrStreamClientStopped
+	}
+	if c.stream != nil {
+		return c.stream, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if c.tenantID != "" {
+		var err error
+		ctx, err = user.InjectIntoGRPCRequest(user.InjectOrgID(ctx, c.tenantID))
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	stream, err := c.client.PushStream(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	c.stream, c.cancel = stream, cancel
+	go c.receiveAcks(stream)
+	return stream, nil
+}
+
+// receiveAcks delivers the acknowledgements of the stream to the pushes waiting for them.
+func (c *StreamClient) receiveAcks(stream logproto.StreamPusher_PushStreamClient) {
+	for {
+		resp, err := stream.Recv()
+		c.mtx.Lock()
+		if err != nil {
+			c.resetStream(stream, err)
+			c.mtx.Unlock()
+			return
+		}
+		ack, ok := c.pending[resp.BatchID]
+		delete(c.pending, resp.BatchID)
+		c.mtx.Unlock()
+
+		if !ok {
+			continue
+		}
+		if resp.Code/100 != 2 {
+			ack <- &StreamPushError{StatusCode: int(resp.Code), Message: resp.Error}
+			continue
+		}
+		ack <- nil
+	}
+}
+
+// resetStream closes the stream if it is the current one, and fails its pending pushes with err.
+// Must hold mtx.
+func (c *StreamClient) resetStream(stream logproto.StreamPusher_PushStreamClient, err error) {
+	if c.stream != stream {
+		return
+	}
+	c.cancel()
+	c.stream, c.cancel = nil, nil
+	for id, ack := range c.pending {
+		ack <- err
+		delete(c.pending, id)
+	}
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/middleware"
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// fakeStreamPusher acknowledges the batches of the streams it receives,
+// and rejects those without streams.
+type fakeStreamPusher struct {
+	mtx     sync.Mutex
+	tenants []string
+	batches int
+}
+
+func (p *fakeStreamPusher) PushStream(stream logproto.StreamPusher_PushStreamServer) error {
+	tenantID, err := user.ExtractOrgID(stream.Context())
+	if err != nil {
+		return err
+	}
+	p.mtx.Lock()
+	p.tenants = append(p.tenants, tenantID)
+	p.mtx.Unlock()
+
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+		p.mtx.Lock()
+		p.batches++
+		p.mtx.Unlock()
+
+		ack := &logproto.PushStreamResponse{BatchID: req.BatchID, Code: http.StatusOK}
+		if len(req.Request.Streams) == 0 {
+			ack.Code, ack.Error = http.StatusBadRequest, "no streams"
+		}
+		if err := stream.Send(ack); err != nil {
+			return err
+		}
+	}
+}
+
+func newStreamPusherConn(t *testing.T, pusher logproto.StreamPusherServer) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.StreamInterceptor(middleware.StreamServerUserHeaderInterceptor))
+	logproto.RegisterStreamPusherServer(server, pusher)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestStreamClient_Push(t *testing.T) {
+	pusher := &fakeStreamPusher{}
+	c := NewStreamClient(newStreamPusherConn(t, pusher), "tenant-1")
+
+	req := &logproto.PushRequest{Streams: []logproto.Stream{{
+		Labels:  `{app="foo"}`,
+		Entries: []logproto.Entry{{Line: "line"}},
+	}}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, c.Push(context.Background(), req))
+		}()
+	}
+	wg.Wait()
+
+	err := c.Push(context.Background(), &logproto.PushRequest{})
+	require.Equal(t, &StreamPushError{StatusCode: http.StatusBadRequest, Message: "no streams"}, err)
+
+	c.Stop()
+	require.Equal(t, ErrStreamClientStopped, c.Push(context.Background(), req))
+
+	pusher.mtx.Lock()
+	defer pusher.mtx.Unlock()
+	require.Equal(t, []string{"tenant-1"}, pusher.tenants, "expected all batches to be pushed over a single stream")
+	require.Equal(t, 11, pusher.batches)
+}
+
+// blockingStreamPusher never receives the batches of its streams, which applies backpressure once
+// the flow control window of a stream is full.
+type blockingStreamPusher struct{}
+
+func (blockingStreamPusher) PushStream(stream logproto.StreamPusher_PushStreamServer) error {
+	<-stream.Context().Done()
+	return nil
+}
+
+func TestStreamClient_PushCanceledWhileSending(t *testing.T) {
+	c := NewStreamClient(newStreamPusherConn(t, blockingStreamPusher{}), "tenant-1")
+	defer c.Stop()
+
+	// The batch is larger than the flow control window, so sending it blocks.
+	req := &logproto.PushRequest{Streams: []logproto.Stream{{
+		Labels:  `{app="foo"}`,
+		Entries: []logproto.Entry{{Line: strings.Repeat("a", 1<<20)}},
+	}}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			require.Equal(t, context.DeadlineExceeded, c.Push(ctx, req))
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pushes blocked after their context was done")
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/distributor"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/loki"
+	"github.com/grafana/loki/pkg/storage"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/storage/stores/indexshipper/compactor/retention"
+	shipper_storage "github.com/grafana/loki/pkg/storage/stores/indexshipper/storage"
+	"github.com/grafana/loki/pkg/storage/stores/tsdb"
+	"github.com/grafana/loki/pkg/util/cfg"
+	util_log "github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/util/unmarshal"
+)
+
+func main() {
+	configFile := flag.String("config.file", "", "Loki config file describing the storage and schema to backfill into")
+	tenant := flag.String("tenant", "fake", "Tenant to backfill the streams for, default is `fake` for single tenant Loki")
+	workingDir := flag.String("working-dir", os.TempDir(), "Directory used to build the index files before they are uploaded")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <file>...\n\nEach file holds a push request in the JSON format of the /loki/api/v1/push endpoint.\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *configFile == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var config loki.ConfigWrapper
+	args := []string{"-config.file=" + *configFile}
+	if err := cfg.DynamicUnmarshal(&config, args, flag.NewFlagSet("config-file-loader", flag.ContinueOnError)); err != nil {
+		fmt.Fprintf(os.Stderr, "failed parsing config: %v\n", err)
+		os.Exit(1)
+	}
+	if err := config.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed validating config: %v\n", err)
+		os.Exit(1)
+	}
+
+	backfiller, err := newBackfiller(config.Config, filepath.Join(*workingDir, "loki-backfill"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed creating backfiller: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx := context.Background()
+	for _, file := range flag.Args() {
+		req, err := readPushRequest(file)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed reading %s: %v\n", file, err)
+			os.Exit(1)
+		}
+
+		if err := backfiller.Backfill(ctx, *tenant, req.Streams); err != nil {
+			fmt.Fprintf(os.Stderr, "failed backfilling %s: %v\n", file, err)
+			os.Exit(1)
+		}
+		fmt.Printf("backfilled %d streams from %s\n", len(req.Streams), file)
+	}
+}
+
+// newBackfiller creates a backfiller writing chunks with the chunk client of every schema period and index files to
+// the shared store of the TSDB shipper. Streams are validated with the limits of the tenant, including the overrides
+// of the runtime config, and the retention of the compactor when it is enabled.
+func newBackfiller(config loki.Config, workingDir string) (*tsdb.Backfiller, error) {
+	encoding, err := chunkenc.ParseEncoding(config.Ingester.ChunkEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := loki.LoadOverrides(config)
+	if err != nil {
+		return nil, err
+	}
+	validator, err := distributor.NewValidator(overrides)
+	if err != nil {
+		return nil, err
+	}
+	var retentionLimits retention.Limits
+	if config.CompactorConfig.RetentionEnabled {
+		retentionLimits = overrides
+	}
+
+	clientMetrics := storage.NewClientMetrics()
+	chunkClients := make([]client.Client, 0, len(config.SchemaConfig.Configs))
+	for _, p := range config.SchemaConfig.Configs {
+		objectStoreType := p.ObjectType
+		if objectStoreType == "" {
+			objectStoreType = p.IndexType
+		}
+		chunkClientReg := prometheus.WrapRegistererWith(
+			prometheus.Labels{"component": "chunk-store-" + p.From.String()}, prometheus.DefaultRegisterer)
+
+		chunkClient, err := storage.NewChunkClient(objectStoreType, config.StorageConfig, config.SchemaConfig, clientMetrics, chunkClientReg)
+		if err != nil {
+			return nil, err
+		}
+		chunkClients = append(chunkClients, chunkClient)
+	}
+
+	objectClient, err := storage.NewObjectClient(config.StorageConfig.TSDBShipperConfig.SharedStoreType, config.StorageConfig, clientMetrics)
+	if err != nil {
+		return nil, err
+	}
+	indexClient := shipper_storage.NewIndexStorageClient(objectClient, config.StorageConfig.TSDBShipperConfig.SharedStoreKeyPrefix)
+
+	return tsdb.NewBackfiller(tsdb.BackfillConfig{
+		Encoding:        encoding,
+		BlockSize:       config.Ingester.BlockSize,
+		TargetChunkSize: config.Ingester.TargetChunkSize,
+		WorkingDir:      workingDir,
+	}, config.SchemaConfig, chunkClients, indexClient, validator, retentionLimits, util_log.Logger)
+}
+
+func readPushRequest(file string) (logproto.PushRequest, error) {
+	var req logproto.PushRequest
+
+	f, err := os.Open(file)
+	if err != nil {
+		return req, err
+	}
+	defer f.Close()
+
+	err = unmarshal.DecodePushRequest(f, &req)
+	return req, err
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql/log"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/pkg/push"
 	"github.com/grafana/loki/pkg/storage/chunk"
 	"github.com/grafana/loki/pkg/util/filter"
 	util_log "github.com/grafana/loki/pkg/util/log"
@@ -31,6 +32,8 @@ const (
 	chunkFormatV1
 	chunkFormatV2
 	chunkFormatV3
+	// chunkFormatV4 stores the structured metadata of each entry after its line.
+	chunkFormatV4
 
 	DefaultChunkFormat = chunkFormatV3 // the currently used chunk format
 
@@ -43,7 +46,7 @@ const (
 	defaultBlockSize = 256 * 1024
 )
 
-var HeadBlockFmts = []HeadBlockFmt{OrderedHeadBlockFmt, UnorderedHeadBlockFmt}
+var HeadBlockFmts = []HeadBlockFmt{OrderedHeadBlockFmt, UnorderedHeadBlockFmt, UnorderedWithMetadataHeadBlockFmt}
 
 type HeadBlockFmt byte
 
@@ -55,6 +58,8 @@ func (f HeadBlockFmt) String() string {
 		return "ordered"
 	case f == UnorderedHeadBlockFmt:
 		return "unordered"
+	case f == UnorderedWithMetadataHeadBlockFmt:
+		return "unordered with structured metadata"
 	default:
 		return fmt.Sprintf("unknown: %v", byte(f))
 	}
@@ -65,10 +70,18 @@ func (f HeadBlockFmt) NewBlock() HeadBlock {
 	case f < UnorderedHeadBlockFmt:
 		return &headBlock{}
 	default:
-		return newUnorderedHeadBlock()
+		return newUnorderedHeadBlock(f)
 	}
 }
 
+// ChunkFormat returns the chunk format that is used for chunks with the given head block format.
+func ChunkFormat(f HeadBlockFmt) byte {
+	if f < UnorderedWithMetadataHeadBlockFmt {
+		return DefaultChunkFormat
+	}
+	return chunkFormatV4
+}
+
 const (
 	_ HeadBlockFmt = iota
 	// placeholders to start splitting chunk formats vs head block
@@ -77,6 +90,7 @@ const (
 	_
 	OrderedHeadBlockFmt
 	UnorderedHeadBlockFmt
+	UnorderedWithMetadataHeadBlockFmt
 )
 
 var magicNumber = uint32(0x12EE56A)
@@ -111,7 +125,7 @@ type MemChunk struct {
 	// Current in-mem block being appended to.
 	head HeadBlock
 
-	// the chunk format default to v2
+	// the chunk format default to v3, v4 when storing structured metadata
 	format   byte
 	encoding Encoding
 	headFmt  HeadBlockFmt
@@ -159,12 +173,14 @@ func (hb *headBlock) Reset() {
 
 func (hb *headBlock) Bounds() (int64, int64) { return hb.mint, hb.maxt }
 
-func (hb *headBlock) Append(ts int64, line string) error {
+// Append adds an entry to the head block. The ordered head block does not support structured metadata,
+// so the metadata is dropped.
+func (hb *headBlock) Append(ts int64, line string, _ labels.Labels) error {
 	if !hb.IsEmpty() && hb.maxt > ts {
 		return ErrOutOfOrder
 	}
 
-	hb.entries = append(hb.entries, entry{ts, line})
+	hb.entries = append(hb.entries, entry{t: ts, s: line})
 	if hb.mint == 0 || hb.mint > ts {
 		hb.mint = ts
 	}
@@ -174,7 +190,7 @@ func (hb *headBlock) Append(ts int64, line string) error {
 	return nil
 }
 
-func (hb *headBlock) Serialise(pool WriterPool) ([]byte, error) {
+func (hb *headBlock) Serialise(pool WriterPool, format byte) ([]byte, error) {
 	inBuf := serializeBytesBufferPool.Get().(*bytes.Buffer)
 	defer func() {
 		inBuf.Reset()
@@ -186,13 +202,7 @@ func (hb *headBlock) Serialise(pool WriterPool) ([]byte, error) {
 	compressedWriter := pool.GetWriter(outBuf)
 	defer pool.PutWriter(compressedWriter)
 	for _, logEntry := range hb.entries {
-		n := binary.PutVarint(encBuf, logEntry.t)
-		inBuf.Write(encBuf[:n])
-
-		n = binary.PutUvarint(encBuf, uint64(len(logEntry.s)))
-		inBuf.Write(encBuf[:n])
-
-		inBuf.WriteString(logEntry.s)
+		writeEntry(inBuf, encBuf, logEntry.t, logEntry.s, logEntry.metadata, format)
 	}
 
 	if _, err := compressedWriter.Write(inBuf.Bytes()); err != nil {
@@ -315,19 +325,45 @@ func (hb *headBlock) Convert(version HeadBlockFmt) (HeadBlock, error) {
 	if version < UnorderedHeadBlockFmt {
 		return hb, nil
 	}
-	out := newUnorderedHeadBlock()
+	out := version.NewBlock()
 
 	for _, e := range hb.entries {
-		if err := out.Append(e.t, e.s); err != nil {
+		if err := out.Append(e.t, e.s, nil); err != nil {
 			return nil, err
 		}
 	}
 	return out, nil
 }
 
+// writeEntry appends the uncompressed encoding of an entry to a block: the timestamp, the length prefixed line
+// and, for chunk format v4+, the number of structured metadata pairs followed by their length prefixed names and values.
+func writeEntry(buf *bytes.Buffer, encBuf []byte, ts int64, line string, metadata labels.Labels, format byte) {
+	n := binary.PutVarint(encBuf, ts)
+	buf.Write(encBuf[:n])
+
+	n = binary.PutUvarint(encBuf, uint64(len(line)))
+	buf.Write(encBuf[:n])
+	buf.WriteString(line)
+
+	if format < chunkFormatV4 {
+		return
+	}
+	n = binary.PutUvarint(encBuf, uint64(len(metadata)))
+	buf.Write(encBuf[:n])
+	for _, l := range metadata {
+		n = binary.PutUvarint(encBuf, uint64(len(l.Name)))
+		buf.Write(encBuf[:n])
+		buf.WriteString(l.Name)
+		n = binary.PutUvarint(encBuf, uint64(len(l.Value)))
+		buf.Write(encBuf[:n])
+		buf.WriteString(l.Value)
+	}
+}
+
 type entry struct {
-	t int64
-	s string
+	t        int64
+	s        string
+	metadata labels.Labels
 }
 
 // NewMemChunk returns a new in-mem chunk.
@@ -337,7 +373,7 @@ func NewMemChunk(enc Encoding, head HeadBlockFmt, blockSize, targetSize int) *Me
 		targetSize: targetSize, // Desired chunk size in compressed bytes
 		blocks:     []block{},
 
-		format: DefaultChunkFormat,
+		format: ChunkFormat(head),
 		head:   head.NewBlock(),
 
 		encoding: enc,
@@ -366,7 +402,7 @@ func NewByteChunk(b []byte, blockSize, targetSize int) (*MemChunk, error) {
 	switch version {
 	case chunkFormatV1:
 		bc.encoding = EncGZIP
-	case chunkFormatV2, chunkFormatV3:
+	case chunkFormatV2, chunkFormatV3, chunkFormatV4:
 		// format v2+ has a byte for block encoding.
 		enc := Encoding(db.byte())
 		if db.err() != nil {
@@ -401,7 +437,7 @@ func NewByteChunk(b []byte, blockSize, targetSize int) (*MemChunk, error) {
 
 		// Read offset and length.
 		blk.offset = db.uvarint()
-		if version == chunkFormatV3 {
+		if version >= chunkFormatV3 {
 			blk.uncompressedSize = db.uvarint()
 		}
 		l := db.uvarint()
@@ -460,7 +496,7 @@ func (c *MemChunk) BytesSize() int {
 		size += binary.MaxVarintLen64 // mint
 		size += binary.MaxVarintLen64 // maxt
 		size += binary.MaxVarintLen32 // offset
-		if c.format == chunkFormatV3 {
+		if c.format >= chunkFormatV3 {
 			size += binary.MaxVarintLen32 // uncompressed size
 		}
 		size += binary.MaxVarintLen32 // len(b)
@@ -534,7 +570,7 @@ func (c *MemChunk) WriteTo(w io.Writer) (int64, error) {
 		eb.putVarint64(b.mint)
 		eb.putVarint64(b.maxt)
 		eb.putUvarint(b.offset)
-		if c.format == chunkFormatV3 {
+		if c.format >= chunkFormatV3 {
 			eb.putUvarint(b.uncompressedSize)
 		}
 		eb.putUvarint(len(b.b))
@@ -599,6 +635,16 @@ func MemchunkFromCheckpoint(chk, head []byte, desired HeadBlockFmt, blockSize in
 	return mc, nil
 }
 
+// ReplayHeadBlockFmt returns the unordered head block format to replay the checkpointed chunk with.
+// Only chunks whose format stores structured metadata keep it in their head block.
+func ReplayHeadBlockFmt(chk []byte) HeadBlockFmt {
+	// The format version follows the 4 bytes of the magic number.
+	if len(chk) > 4 && chk[4] >= chunkFormatV4 {
+		return UnorderedWithMetadataHeadBlockFmt
+	}
+	return UnorderedHeadBlockFmt
+}
+
 // Encoding implements Chunk.
 func (c *MemChunk) Encoding() Encoding {
 	return c.encoding
@@ -626,7 +672,7 @@ func (c *MemChunk) SpaceFor(e *logproto.Entry) bool {
 	if c.targetSize > 0 {
 		// This is looking to see if the uncompressed lines will fit which is not
 		// a great check, but it will guarantee we are always under the target size
-		newHBSize := c.head.UncompressedSize() + len(e.Line)
+		newHBSize := c.head.UncompressedSize() + len(e.Line) + metadataSize(e.StructuredMetadata)
 		return (c.cutBlockSize + newHBSize) < c.targetSize
 	}
 	// if targetSize is not defined, default to the original behavior of fixed blocks per chunk
@@ -674,7 +720,11 @@ func (c *MemChunk) Append(entry *logproto.Entry) error {
 		return ErrOutOfOrder
 	}
 
-	if err := c.head.Append(entryTimestamp, entry.Line); err != nil {
+	var metadata labels.Labels
+	if c.format >= chunkFormatV4 {
+		metadata = logproto.FromStructuredMetadataToLabels(entry.StructuredMetadata)
+	}
+	if err := c.head.Append(entryTimestamp, entry.Line, metadata); err != nil {
 		return err
 	}
 
@@ -731,6 +781,9 @@ func (c *MemChunk) ConvertHead(desired HeadBlockFmt) error {
 		c.head = newH
 	}
 	c.headFmt = desired
+	if len(c.blocks) == 0 {
+		c.format = ChunkFormat(desired)
+	}
 	return nil
 }
 
@@ -740,7 +793,7 @@ func (c *MemChunk) cut() error {
 		return nil
 	}
 
-	b, err := c.head.Serialise(getWriterPool(c.encoding))
+	b, err := c.head.Serialise(getWriterPool(c.encoding), c.format)
 	if err != nil {
 		return err
 	}
@@ -797,7 +850,7 @@ func (c *MemChunk) Iterator(ctx context.Context, mintT, maxtT time.Time, directi
 		}
 		lastMax = b.maxt
 
-		blockItrs = append(blockItrs, encBlock{c.encoding, b}.Iterator(ctx, pipeline))
+		blockItrs = append(blockItrs, encBlock{c.encoding, c.format, b}.Iterator(ctx, pipeline))
 	}
 
 	if !c.head.IsEmpty() {
@@ -871,7 +924,7 @@ func (c *MemChunk) SampleIterator(ctx context.Context, from, through time.Time,
 			ordered = false
 		}
 		lastMax = b.maxt
-		its = append(its, encBlock{c.encoding, b}.SampleIterator(ctx, extractor))
+		its = append(its, encBlock{c.encoding, c.format, b}.SampleIterator(ctx, extractor))
 	}
 
 	if !c.head.IsEmpty() {
@@ -903,7 +956,7 @@ func (c *MemChunk) Blocks(mintT, maxtT time.Time) []Block {
 
 	for _, b := range c.blocks {
 		if maxt >= b.mint && b.maxt >= mint {
-			blocks = append(blocks, encBlock{c.encoding, b})
+			blocks = append(blocks, encBlock{c.encoding, c.format, b})
 		}
 	}
 	return blocks
@@ -955,7 +1008,8 @@ func (c *MemChunk) Rebound(start, end time.Time, filter filter.Func) (Chunk, err
 // then allows us to bind a decoding context to a block when requested, but otherwise helps reduce the
 // chances of chunk<>block encoding drift in the codebase as the latter is parameterized by the former.
 type encBlock struct {
-	enc Encoding
+	enc    Encoding
+	format byte
 	block
 }
 
@@ -963,14 +1017,14 @@ func (b encBlock) Iterator(ctx context.Context, pipeline log.StreamPipeline) ite
 	if len(b.b) == 0 {
 		return iter.NoopIterator
 	}
-	return newEntryIterator(ctx, getReaderPool(b.enc), b.b, pipeline)
+	return newEntryIterator(ctx, getReaderPool(b.enc), b.b, b.format, pipeline)
 }
 
 func (b encBlock) SampleIterator(ctx context.Context, extractor log.StreamSampleExtractor) iter.SampleIterator {
 	if len(b.b) == 0 {
 		return iter.NoopIterator
 	}
-	return newSampleIterator(ctx, getReaderPool(b.enc), b.b, extractor)
+	return newSampleIterator(ctx, getReaderPool(b.enc), b.b, b.format, extractor)
 }
 
 func (b block) Offset() int {
@@ -1009,7 +1063,7 @@ func (hb *headBlock) Iterator(ctx context.Context, direction logproto.Direction,
 			return
 		}
 		stats.AddHeadChunkBytes(int64(len(e.s)))
-		newLine, parsedLbs, matches := pipeline.ProcessString(e.t, e.s)
+		newLine, parsedLbs, matches := pipeline.ProcessString(e.t, e.s, e.metadata...)
 		if !matches {
 			return
 		}
@@ -1024,8 +1078,9 @@ func (hb *headBlock) Iterator(ctx context.Context, direction logproto.Direction,
 			streams[labels] = stream
 		}
 		stream.Entries = append(stream.Entries, logproto.Entry{
-			Timestamp: time.Unix(0, e.t),
-			Line:      newLine,
+			Timestamp:          time.Unix(0, e.t),
+			Line:               newLine,
+			StructuredMetadata: structuredMetadata(e.metadata),
 		})
 	}
 
@@ -1060,7 +1115,7 @@ func (hb *headBlock) SampleIterator(ctx context.Context, mint, maxt int64, extra
 
 	for _, e := range hb.entries {
 		stats.AddHeadChunkBytes(int64(len(e.s)))
-		value, parsedLabels, ok := extractor.ProcessString(e.t, e.s)
+		value, parsedLabels, ok := extractor.ProcessString(e.t, e.s, e.metadata...)
 		if !ok {
 			continue
 		}
@@ -1111,6 +1166,7 @@ func unsafeGetBytes(s string) []byte {
 
 type bufferedIterator struct {
 	origBytes []byte
+	format    byte
 	stats     *stats.Context
 
 	reader io.Reader
@@ -1125,15 +1181,19 @@ type bufferedIterator struct {
 	currLine []byte // the current line, this is the same as the buffer but sliced the the line size.
 	currTs   int64
 
+	metadataBuf  []byte        // The buffer for the names and values of the structured metadata.
+	currMetadata labels.Labels // the structured metadata of the current entry, only read for chunk format v4+.
+
 	closed bool
 }
 
-func newBufferedIterator(ctx context.Context, pool ReaderPool, b []byte) *bufferedIterator {
+func newBufferedIterator(ctx context.Context, pool ReaderPool, b []byte, format byte) *bufferedIterator {
 	stats := stats.FromContext(ctx)
 	stats.AddCompressedBytes(int64(len(b)))
 	return &bufferedIterator{
 		stats:     stats,
 		origBytes: b,
+		format:    format,
 		reader:    nil, // will be initialized later
 		pool:      pool,
 	}
@@ -1163,6 +1223,14 @@ func (si *bufferedIterator) Next() bool {
 	si.stats.AddDecompressedBytes(int64(len(line)) + 2*binary.MaxVarintLen64)
 	si.stats.AddDecompressedLines(1)
 
+	si.currMetadata = si.currMetadata[:0]
+	if si.format >= chunkFormatV4 {
+		if !si.readMetadata() {
+			si.Close()
+			return false
+		}
+	}
+
 	si.currTs = ts
 	si.currLine = line
 	return true
@@ -1234,6 +1302,101 @@ func (si *bufferedIterator) moveNext() (int64, []byte, bool) {
 	return ts, si.buf[:lineSize], true
 }
 
+// readMetadata reads the structured metadata that follows the line of an entry: the number of pairs followed
+// by the length prefixed name and value of each pair.
+func (si *bufferedIterator) readMetadata() bool {
+	n, ok := si.readUvarint()
+	if !ok {
+		return false
+	}
+	if n == 0 {
+		return true
+	}
+
+	sizes := make([]int, 2*n)
+	size := 0
+	// The names and values are read into a single buffer that is shared by the labels of the entry.
+	// The buffer is never reused, since the labels may be retained by the caller.
+	si.metadataBuf = si.metadataBuf[:0]
+	for i := range sizes {
+		l, ok := si.readUvarint()
+		if !ok {
+			return false
+		}
+		if l >= maxLineLength {
+			si.err = fmt.Errorf("structured metadata too long %d, maximum %d", l, maxLineLength)
+			return false
+		}
+		sizes[i] = int(l)
+		if cap(si.metadataBuf) < size+int(l) {
+			buf := make([]byte, size, 2*(size+int(l)))
+			copy(buf, si.metadataBuf)
+			si.metadataBuf = buf
+		}
+		si.metadataBuf = si.metadataBuf[:size+int(l)]
+		if !si.readFull(si.metadataBuf[size:]) {
+			return false
+		}
+		size += int(l)
+	}
+	si.stats.AddDecompressedBytes(int64(size))
+
+	strs := string(si.metadataBuf)
+	for i := 0; i < len(sizes); i += 2 {
+		name := strs[:sizes[i]]
+		strs = strs[sizes[i]:]
+		value := strs[:sizes[i+1]]
+		strs = strs[sizes[i+1]:]
+		si.currMetadata = append(si.currMetadata, labels.Label{Name: name, Value: value})
+	}
+	return true
+}
+
+// readUvarint reads a single uvarint, first from what is left in the read buffer and then from the reader.
+func (si *bufferedIterator) readUvarint() (uint64, bool) {
+	for {
+		v, w := binary.Uvarint(si.readBuf[:si.readBufValid])
+		if w > 0 {
+			si.readBufValid = copy(si.readBuf[:], si.readBuf[w:si.readBufValid])
+			return v, true
+		}
+		if w < 0 || si.readBufValid == len(si.readBuf) {
+			si.err = fmt.Errorf("invalid data in chunk")
+			return 0, false
+		}
+		n, err := si.reader.Read(si.readBuf[si.readBufValid:])
+		si.readBufValid += n
+		if err != nil && (err != io.EOF || n == 0) {
+			if err == io.EOF {
+				err = fmt.Errorf("invalid data in chunk")
+			}
+			si.err = err
+			return 0, false
+		}
+	}
+}
+
+// readFull fills b, first from what is left in the read buffer and then from the reader.
+func (si *bufferedIterator) readFull(b []byte) bool {
+	n := copy(b, si.readBuf[:si.readBufValid])
+	si.readBufValid = copy(si.readBuf[:], si.readBuf[n:si.readBufValid])
+	for n < len(b) {
+		r, err := si.reader.Read(b[n:])
+		n += r
+		if err != nil {
+			if err == io.EOF && r != 0 {
+				continue
+			}
+			if err == io.EOF {
+				err = fmt.Errorf("invalid data in chunk")
+			}
+			si.err = err
+			return false
+		}
+	}
+	return true
+}
+
 func (si *bufferedIterator) Error() error { return si.err }
 
 func (si *bufferedIterator) Close() error {
@@ -1255,11 +1418,13 @@ func (si *bufferedIterator) close() {
 		si.buf = nil
 	}
 	si.origBytes = nil
+	si.metadataBuf = nil
+	si.currMetadata = nil
 }
 
-func newEntryIterator(ctx context.Context, pool ReaderPool, b []byte, pipeline log.StreamPipeline) iter.EntryIterator {
+func newEntryIterator(ctx context.Context, pool ReaderPool, b []byte, format byte, pipeline log.StreamPipeline) iter.EntryIterator {
 	return &entryBufferedIterator{
-		bufferedIterator: newBufferedIterator(ctx, pool, b),
+		bufferedIterator: newBufferedIterator(ctx, pool, b, format),
 		pipeline:         pipeline,
 	}
 }
@@ -1282,21 +1447,22 @@ func (e *entryBufferedIterator) StreamHash() uint64 { return e.pipeline.BaseLabe
 
 func (e *entryBufferedIterator) Next() bool {
 	for e.bufferedIterator.Next() {
-		newLine, lbs, matches := e.pipeline.Process(e.currTs, e.currLine)
+		newLine, lbs, matches := e.pipeline.Process(e.currTs, e.currLine, e.currMetadata...)
 		if !matches {
 			continue
 		}
 		e.cur.Timestamp = time.Unix(0, e.currTs)
 		e.cur.Line = string(newLine)
+		e.cur.StructuredMetadata = structuredMetadata(e.currMetadata)
 		e.currLabels = lbs
 		return true
 	}
 	return false
 }
 
-func newSampleIterator(ctx context.Context, pool ReaderPool, b []byte, extractor log.StreamSampleExtractor) iter.SampleIterator {
+func newSampleIterator(ctx context.Context, pool ReaderPool, b []byte, format byte, extractor log.StreamSampleExtractor) iter.SampleIterator {
 	it := &sampleBufferedIterator{
-		bufferedIterator: newBufferedIterator(ctx, pool, b),
+		bufferedIterator: newBufferedIterator(ctx, pool, b, format),
 		extractor:        extractor,
 	}
 	return it
@@ -1313,7 +1479,7 @@ type sampleBufferedIterator struct {
 
 func (e *sampleBufferedIterator) Next() bool {
 	for e.bufferedIterator.Next() {
-		val, labels, ok := e.extractor.Process(e.currTs, e.currLine)
+		val, labels, ok := e.extractor.Process(e.currTs, e.currLine, e.currMetadata...)
 		if !ok {
 			continue
 		}
@@ -1332,3 +1498,20 @@ func (e *sampleBufferedIterator) StreamHash() uint64 { return e.extractor.BaseLa
 func (e *sampleBufferedIterator) Sample() logproto.Sample {
 	return e.cur
 }
+
+// structuredMetadata copies the structured metadata of an entry into a new slice, so that it can be retained.
+func structuredMetadata(metadata labels.Labels) push.LabelsAdapter {
+	if len(metadata) == 0 {
+		return nil
+	}
+	return logproto.FromLabelsToStructuredMetadata(metadata.Copy())
+}
+
+// metadataSize returns the number of bytes the names and values of the structured metadata occupy.
+func metadataSize(metadata push.LabelsAdapter) int {
+	size := 0
+	for _, l := range metadata {
+		size += len(l.Name) + len(l.Value)
+	}
+	return size
+}
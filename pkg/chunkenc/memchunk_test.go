@@ -24,6 +24,7 @@ import (
 	"github.com/grafana/loki/pkg/logql/log"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/pkg/push"
 	"github.com/grafana/loki/pkg/storage/chunk"
 )
 
@@ -332,6 +333,87 @@ func TestRoundtripV3(t *testing.T) {
 	}
 }
 
+func TestReplayHeadBlockFmt(t *testing.T) {
+	for _, tc := range []struct {
+		headFmt  HeadBlockFmt
+		expected HeadBlockFmt
+	}{
+		{OrderedHeadBlockFmt, UnorderedHeadBlockFmt},
+		{UnorderedHeadBlockFmt, UnorderedHeadBlockFmt},
+		{UnorderedWithMetadataHeadBlockFmt, UnorderedWithMetadataHeadBlockFmt},
+	} {
+		t.Run(tc.headFmt.String(), func(t *testing.T) {
+			var chk, head bytes.Buffer
+			c := NewMemChunk(EncSnappy, tc.headFmt, testBlockSize, testTargetSize)
+			require.NoError(t, c.SerializeForCheckpointTo(&chk, &head))
+			require.Equal(t, tc.expected, ReplayHeadBlockFmt(chk.Bytes()))
+		})
+	}
+	require.Equal(t, UnorderedHeadBlockFmt, ReplayHeadBlockFmt(nil))
+}
+
+func TestMemChunk_StructuredMetadata(t *testing.T) {
+	c := NewMemChunk(EncSnappy, UnorderedWithMetadataHeadBlockFmt, testBlockSize, testTargetSize)
+	require.Equal(t, chunkFormatV4, c.format)
+
+	for i := 0; i < 10; i++ {
+		entry := &logproto.Entry{
+			Timestamp: time.Unix(0, int64(i)),
+			Line:      fmt.Sprintf("line %d", i),
+		}
+		if i%2 == 0 {
+			entry.StructuredMetadata = push.LabelsAdapter{
+				{Name: "trace_id", Value: fmt.Sprintf("trace-%d", i%4)},
+				{Name: "user_id", Value: "42"},
+			}
+		}
+		require.NoError(t, c.Append(entry))
+		// cut a block in the middle, so that entries are read from both the blocks and the head block.
+		if i == 4 {
+			require.NoError(t, c.cut())
+		}
+	}
+
+	var chk, head bytes.Buffer
+	require.NoError(t, c.SerializeForCheckpointTo(&chk, &head))
+	restored, err := MemchunkFromCheckpoint(chk.Bytes(), head.Bytes(), UnorderedWithMetadataHeadBlockFmt, testBlockSize, testTargetSize)
+	require.NoError(t, err)
+	require.NoError(t, restored.Close())
+
+	b, err := restored.Bytes()
+	require.NoError(t, err)
+	fromBytes, err := NewByteChunk(b, testBlockSize, testTargetSize)
+	require.NoError(t, err)
+
+	for name, chk := range map[string]*MemChunk{"memchunk": c, "bytechunk": fromBytes} {
+		t.Run(name, func(t *testing.T) {
+			expr, err := syntax.ParseLogSelector(`{app="foo"} | trace_id="trace-0"`, true)
+			require.NoError(t, err)
+			p, err := expr.Pipeline()
+			require.NoError(t, err)
+
+			it, err := chk.Iterator(context.Background(), time.Unix(0, 0), time.Unix(0, 10), logproto.FORWARD, p.ForStream(labels.Labels{{Name: "app", Value: "foo"}}))
+			require.NoError(t, err)
+			var lines []string
+			for it.Next() {
+				require.Equal(t, `{app="foo"}`, it.Labels())
+				require.Equal(t, push.LabelsAdapter{{Name: "trace_id", Value: "trace-0"}, {Name: "user_id", Value: "42"}}, it.Entry().StructuredMetadata)
+				lines = append(lines, it.Entry().Line)
+			}
+			require.NoError(t, it.Close())
+			require.Equal(t, []string{"line 0", "line 4", "line 8"}, lines)
+
+			sampleIt := chk.SampleIterator(context.Background(), time.Unix(0, 0), time.Unix(0, 10), countExtractor)
+			var samples int
+			for sampleIt.Next() {
+				samples++
+			}
+			require.NoError(t, sampleIt.Close())
+			require.Equal(t, 10, samples)
+		})
+	}
+}
+
 func TestSerialization(t *testing.T) {
 	for _, f := range HeadBlockFmts {
 		for _, enc := range testEncoding {
@@ -714,10 +796,10 @@ func BenchmarkWrite(b *testing.B) {
 type nomatchPipeline struct{}
 
 func (nomatchPipeline) BaseLabels() log.LabelsResult { return log.EmptyLabelsResult }
-func (nomatchPipeline) Process(_ int64, line []byte) ([]byte, log.LabelsResult, bool) {
+func (nomatchPipeline) Process(_ int64, line []byte, _ ...labels.Label) ([]byte, log.LabelsResult, bool) {
 	return line, nil, false
 }
-func (nomatchPipeline) ProcessString(_ int64, line string) (string, log.LabelsResult, bool) {
+func (nomatchPipeline) ProcessString(_ int64, line string, _ ...labels.Label) (string, log.LabelsResult, bool) {
 	return line, nil, false
 }
 
@@ -830,7 +912,7 @@ func BenchmarkHeadBlockIterator(b *testing.B) {
 			h := headBlock{}
 
 			for i := 0; i < j; i++ {
-				if err := h.Append(int64(i), "this is the append string"); err != nil {
+				if err := h.Append(int64(i), "this is the append string", nil); err != nil {
 					b.Fatal(err)
 				}
 			}
@@ -854,7 +936,7 @@ func BenchmarkHeadBlockSampleIterator(b *testing.B) {
 			h := headBlock{}
 
 			for i := 0; i < j; i++ {
-				if err := h.Append(int64(i), "this is the append string"); err != nil {
+				if err := h.Append(int64(i), "this is the append string", nil); err != nil {
 					b.Fatal(err)
 				}
 			}
@@ -0,0 +1,245 @@
+package distributor
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+type CardinalityGuardConfig struct {
+	Window           time.Duration `yaml:"window"`
+	MaxTrackedValues int           `yaml:"max_tracked_values"`
+	UpdateInterval   time.Duration `yaml:"update_interval"`
+}
+
+func (cfg *CardinalityGuardConfig) RegisterFlagsWithPrefix(prefix string, fs *flag.FlagSet) {
+	fs.DurationVar(&cfg.Window, prefix+".window", time.Hour, "The time window in which the distinct values of each label of a tenant are counted by the cardinality guard.")
+	fs.IntVar(&cfg.MaxTrackedValues, prefix+".max-tracked-values", 10000, "The maximum number of distinct values that are tracked per label and tenant. It bounds the memory of the cardinality guard, and must be larger than the cardinality_guard_max_label_values limits. Limits and runtime overrides that are not lower are rejected.")
+	fs.DurationVar(&cfg.UpdateInterval, prefix+".update-interval", time.Minute, "The interval on which the cardinality guard expires label values and updates its metrics.")
+}
+
+// ValidateMaxLabelValues validates the cardinality_guard_max_label_values limit of a tenant. A label can only exceed
+// the limit if the cardinality guard tracks more values per label than the limit.
+func (cfg *CardinalityGuardConfig) ValidateMaxLabelValues(maxLabelValues int) error {
+	if maxLabelValues > 0 && maxLabelValues >= cfg.MaxTrackedValues {
+		return fmt.Errorf("cardinality_guard_max_label_values (%d) must be lower than -distributor.cardinality-guard.max-tracked-values (%d)", maxLabelValues, cfg.MaxTrackedValues)
+	}
+	return nil
+}
+
+// LabelCardinality is the number of distinct values of a label of a tenant within the window of the cardinality guard.
+type LabelCardinality struct {
+	Name      string `json:"name"`
+	Values    int    `json:"values"`
+	Offending bool   `json:"offending"`
+}
+
+// tenantCardinality tracks, per label name, when each label value (by hash) of a tenant was last seen.
+type tenantCardinality struct {
+	mtx    sync.Mutex
+	labels map[string]map[uint64]int64
+	// removed is set once the tenant is removed from the guard, after which its values must not be observed.
+	removed bool
+}
+
+// cardinalityGuard tracks the label value churn of the tenants whose streams it guards,
+// and reports the labels that have more distinct values than the limit of the tenant.
+type cardinalityGuard struct {
+	services.Service
+
+	window           time.Duration
+	maxTrackedValues int
+	limits           Limits
+
+	mtx     sync.RWMutex
+	tenants map[string]*tenantCardinality
+
+	labelValues *prometheus.GaugeVec
+	lines       *prometheus.CounterVec
+}
+
+func newCardinalityGuard(cfg CardinalityGuardConfig, limits Limits, registerer prometheus.Registerer) *cardinalityGuard {
+	g := &cardinalityGuard{
+		window:           cfg.Window,
+		maxTrackedValues: cfg.MaxTrackedValues,
+		limits:           limits,
+		tenants:          map[string]*tenantCardinality{},
+		labelValues: promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "loki",
+			Name:      "distributor_cardinality_guard_label_values",
+			Help:      "The number of distinct values of the labels that exceed the cardinality guard limit of a tenant.",
+		}, []string{"tenant", "label"}),
+		lines: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "distributor_cardinality_guard_lines_total",
+			Help:      "The total number of lines whose offending labels were stripped or which were rejected by the cardinality guard.",
+		}, []string{"tenant", "label", "action"}),
+	}
+	g.Service = services.NewTimerService(cfg.UpdateInterval, nil, g.iteration, nil).WithName("cardinality guard")
+	return g
+}
+
+func (g *cardinalityGuard) iteration(_ context.Context) error {
+	g.expire(time.Now())
+	return nil
+}
+
+// observe records the label values of a stream of the tenant and returns the names of the labels of the stream
+// that have more than maxValues distinct values.
+func (g *cardinalityGuard) observe(tenantID string, lbs labels.Labels, now time.Time, maxValues int) []string {
+	t := g.lockTenant(tenantID)
+	defer t.mtx.Unlock()
+
+	var offending []string
+	for _, l := range lbs {
+		values, ok := t.labels[l.Name]
+		if !ok {
+			values = map[uint64]int64{}
+			t.labels[l.Name] = values
+		}
+
+		h := xxhash.Sum64String(l.Value)
+		if _, ok := values[h]; ok || len(values) < g.maxTrackedValues {
+			values[h] = now.UnixNano()
+			if !ok && len(values) == maxValues+1 {
+				level.Warn(util_log.Logger).Log("msg", "label exceeds the cardinality guard limit", "tenant", tenantID, "label", l.Name, "limit", maxValues, "window", g.window)
+			}
+		}
+		if len(values) > maxValues {
+			offending = append(offending, l.Name)
+		}
+	}
+	return offending
+}
+
+// lockTenant returns the locked tenant, which has not been removed from the guard.
+func (g *cardinalityGuard) lockTenant(tenantID string) *tenantCardinality {
+	for {
+		t := g.tenant(tenantID)
+		t.mtx.Lock()
+		if !t.removed {
+			return t
+		}
+		t.mtx.Unlock()
+	}
+}
+
+func (g *cardinalityGuard) tenant(tenantID string) *tenantCardinality {
+	g.mtx.RLock()
+	t, ok := g.tenants[tenantID]
+	g.mtx.RUnlock()
+	if ok {
+		return t
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if t, ok = g.tenants[tenantID]; !ok {
+		t = &tenantCardinality{labels: map[string]map[uint64]int64{}}
+		g.tenants[tenantID] = t
+	}
+	return t
+}
+
+// expire removes the label values that were not seen within the window,
+// and updates the metrics of the offending labels.
+//
+// Each tenant is only locked while its own values are expired, so that pushes of the other tenants are not blocked.
+func (g *cardinalityGuard) expire(now time.Time) {
+	oldest := now.Add(-g.window).UnixNano()
+
+	g.mtx.RLock()
+	tenants := make(map[string]*tenantCardinality, len(g.tenants))
+	for tenantID, t := range g.tenants {
+		tenants[tenantID] = t
+	}
+	g.mtx.RUnlock()
+
+	for tenantID, t := range tenants {
+		if g.expireTenant(tenantID, t, oldest) {
+			g.removeTenant(tenantID, t)
+		}
+	}
+}
+
+// expireTenant expires the values of the tenant, and returns whether the tenant has no values left.
+func (g *cardinalityGuard) expireTenant(tenantID string, t *tenantCardinality, oldest int64) bool {
+	maxValues := g.limits.CardinalityGuardMaxLabelValues(tenantID)
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for name, values := range t.labels {
+		for h, lastSeen := range values {
+			if lastSeen < oldest {
+				delete(values, h)
+			}
+		}
+		if maxValues > 0 && len(values) > maxValues {
+			g.labelValues.WithLabelValues(tenantID, name).Set(float64(len(values)))
+		} else {
+			g.labelValues.DeleteLabelValues(tenantID, name)
+		}
+		if len(values) == 0 || maxValues <= 0 {
+			delete(t.labels, name)
+		}
+	}
+	return len(t.labels) == 0
+}
+
+// removeTenant removes the tenant if it still has no values, as they might have been observed since it was expired.
+func (g *cardinalityGuard) removeTenant(tenantID string, t *tenantCardinality) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if g.tenants[tenantID] == t && len(t.labels) == 0 {
+		t.removed = true
+		delete(g.tenants, tenantID)
+	}
+}
+
+// topLabels returns up to limit labels of the tenant with the most distinct values.
+func (g *cardinalityGuard) topLabels(tenantID string, limit int) []LabelCardinality {
+	g.mtx.RLock()
+	t, ok := g.tenants[tenantID]
+	g.mtx.RUnlock()
+	if !ok {
+		return []LabelCardinality{}
+	}
+
+	maxValues := g.limits.CardinalityGuardMaxLabelValues(tenantID)
+	t.mtx.Lock()
+	result := make([]LabelCardinality, 0, len(t.labels))
+	for name, values := range t.labels {
+		result = append(result, LabelCardinality{
+			Name:      name,
+			Values:    len(values),
+			Offending: maxValues > 0 && len(values) > maxValues,
+		})
+	}
+	t.mtx.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Values != result[j].Values {
+			return result[i].Values > result[j].Values
+		}
+		return result[i].Name < result[j].Name
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
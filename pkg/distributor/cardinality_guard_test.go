@@ -0,0 +1,152 @@
+package distributor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func newTestCardinalityGuard(t *testing.T, maxLabelValues int) *cardinalityGuard {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.CardinalityGuardMaxLabelValues = maxLabelValues
+	overrides, err := validation.NewOverrides(*limits, nil)
+	require.NoError(t, err)
+
+	return newCardinalityGuard(CardinalityGuardConfig{Window: time.Hour, MaxTrackedValues: 5, UpdateInterval: time.Minute}, overrides, prometheus.NewPedanticRegistry())
+}
+
+func TestCardinalityGuard(t *testing.T) {
+	g := newTestCardinalityGuard(t, 2)
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		offending := g.observe("tenant", labels.FromStrings("app", "foo", "request_id", fmt.Sprint(i)), now, 2)
+		if i < 2 {
+			require.Empty(t, offending)
+		} else {
+			require.Equal(t, []string{"request_id"}, offending)
+		}
+	}
+	// Seen values are not counted twice, and the number of tracked values is bounded.
+	for i := 0; i < 10; i++ {
+		g.observe("tenant", labels.FromStrings("app", "foo", "request_id", fmt.Sprint(i)), now.Add(time.Minute), 2)
+	}
+	require.Empty(t, g.observe("other", labels.FromStrings("request_id", "0"), now, 2))
+
+	require.Equal(t, []LabelCardinality{
+		{Name: "request_id", Values: 5, Offending: true},
+		{Name: "app", Values: 1},
+	}, g.topLabels("tenant", 10))
+	require.Len(t, g.topLabels("tenant", 1), 1)
+	require.Empty(t, g.topLabels("unknown", 10))
+
+	g.expire(now.Add(time.Hour + time.Second))
+	require.Equal(t, 5.0, testutil.ToFloat64(g.labelValues.WithLabelValues("tenant", "request_id")))
+
+	// The values seen first expire after the window.
+	g.expire(now.Add(2 * time.Hour))
+	require.Empty(t, g.topLabels("tenant", 10))
+	require.Empty(t, g.topLabels("other", 10))
+	require.Equal(t, 0, testutil.CollectAndCount(g.labelValues))
+}
+
+func TestCardinalityGuard_RemovedTenant(t *testing.T) {
+	g := newTestCardinalityGuard(t, 2)
+	now := time.Now()
+
+	g.observe("tenant", labels.FromStrings("app", "foo"), now, 2)
+	removed := g.tenant("tenant")
+	g.expire(now.Add(2 * time.Hour))
+	require.True(t, removed.removed)
+
+	// Values observed after the tenant was removed are tracked by a new tenant.
+	g.observe("tenant", labels.FromStrings("app", "bar"), now, 2)
+	require.NotSame(t, removed, g.tenant("tenant"))
+	require.Equal(t, []LabelCardinality{{Name: "app", Values: 1}}, g.topLabels("tenant", 10))
+}
+
+func TestCardinalityGuardConfig_ValidateMaxLabelValues(t *testing.T) {
+	cfg := CardinalityGuardConfig{MaxTrackedValues: 100}
+	require.NoError(t, cfg.ValidateMaxLabelValues(0))
+	require.NoError(t, cfg.ValidateMaxLabelValues(99))
+	require.Error(t, cfg.ValidateMaxLabelValues(100))
+	require.Error(t, cfg.ValidateMaxLabelValues(1000))
+}
+
+func TestDistributor_CardinalityGuard(t *testing.T) {
+	for _, tc := range []struct {
+		action         string
+		expectedLabels []string
+		expectedErr    bool
+	}{
+		{
+			action:         validation.CardinalityGuardReport,
+			expectedLabels: []string{`{app="foo", request_id="0"}`, `{app="foo", request_id="1"}`, `{app="foo", request_id="2"}`},
+		},
+		{
+			action:         validation.CardinalityGuardStrip,
+			expectedLabels: []string{`{app="foo", request_id="0"}`, `{app="foo", request_id="1"}`, `{app="foo"}`},
+		},
+		{
+			action:         validation.CardinalityGuardReject,
+			expectedLabels: []string{`{app="foo", request_id="0"}`, `{app="foo", request_id="1"}`},
+			expectedErr:    true,
+		},
+	} {
+		t.Run(tc.action, func(t *testing.T) {
+			limits := &validation.Limits{}
+			flagext.DefaultValues(limits)
+			limits.EnforceMetricName = false
+			limits.CardinalityGuardMaxLabelValues = 2
+			limits.CardinalityGuardAction = tc.action
+
+			ingester := &mockIngester{}
+			distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+			for i := 0; i < 3; i++ {
+				_, err := distributors[0].Push(ctx, makeWriteRequestWithLabels(1, 10, []string{fmt.Sprintf(`{app="foo", request_id="%d"}`, i)}))
+				if tc.expectedErr && i == 2 {
+					resp, ok := httpgrpc.HTTPResponseFromError(err)
+					require.True(t, ok)
+					require.Equal(t, http.StatusBadRequest, int(resp.Code))
+					continue
+				}
+				require.NoError(t, err)
+			}
+
+			pushed := map[string]struct{}{}
+			ingester.mu.Lock()
+			for _, req := range ingester.pushed {
+				pushed[req.Streams[0].Labels] = struct{}{}
+			}
+			ingester.mu.Unlock()
+			labels := make([]string, 0, len(pushed))
+			for l := range pushed {
+				labels = append(labels, l)
+			}
+			sort.Strings(labels)
+			require.Equal(t, tc.expectedLabels, labels)
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/distributor/cardinality?limit=1", nil)
+			distributors[0].CardinalityHandler(rec, req.WithContext(user.InjectOrgID(req.Context(), "test")))
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.JSONEq(t, fmt.Sprintf(`{"limit":2,"action":%q,"labels":[{"name":"request_id","values":3,"offending":true}]}`, tc.action), rec.Body.String())
+		})
+	}
+}
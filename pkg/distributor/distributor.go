@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/dskit/limiter"
 	"github.com/grafana/dskit/ring"
 	ring_client "github.com/grafana/dskit/ring/client"
+	"github.com/grafana/dskit/runtimeconfig"
 	"github.com/grafana/dskit/services"
 	"github.com/grafana/dskit/tenant"
 	lru "github.com/hashicorp/golang-lru"
@@ -31,6 +32,7 @@ import (
 	"go.uber.org/atomic"
 
 	"github.com/grafana/loki/pkg/distributor/clientpool"
+	"github.com/grafana/loki/pkg/distributor/ingestrules"
 	"github.com/grafana/loki/pkg/distributor/shardstreams"
 	"github.com/grafana/loki/pkg/ingester/client"
 	"github.com/grafana/loki/pkg/logproto"
@@ -61,12 +63,27 @@ type Config struct {
 	factory ring_client.PoolFactory `yaml:"-"`
 
 	RateStore RateStoreConfig `yaml:"rate_store"`
+
+	CardinalityGuard CardinalityGuardConfig `yaml:"cardinality_guard"`
+
+	QuotaStore QuotaStoreConfig `yaml:"quota_store"`
+
+	PushStream PushStreamConfig `yaml:"push_stream"`
+
+	MaxRecvMsgSize int `yaml:"max_recv_msg_size"`
+
+	SplunkTokens SplunkTokensConfig `yaml:"splunk_tokens"`
 }
 
 // RegisterFlags registers distributor-related flags.
 func (cfg *Config) RegisterFlags(fs *flag.FlagSet) {
 	cfg.DistributorRing.RegisterFlags(fs)
 	cfg.RateStore.RegisterFlagsWithPrefix("distributor.rate-store", fs)
+	cfg.CardinalityGuard.RegisterFlagsWithPrefix("distributor.cardinality-guard", fs)
+	cfg.QuotaStore.RegisterFlagsWithPrefix("distributor.quota-store", fs)
+	cfg.PushStream.RegisterFlagsWithPrefix("distributor.push-stream", fs)
+	fs.IntVar(&cfg.MaxRecvMsgSize, "distributor.max-recv-msg-size", 100<<20, "The maximum size in bytes of the decompressed body of an OTLP push request.")
+	cfg.SplunkTokens.RegisterFlagsWithPrefix("distributor.splunk-tokens.", fs)
 }
 
 // RateStore manages the ingestion rate of streams, populated by data fetched from ingesters.
@@ -86,8 +103,11 @@ type Distributor struct {
 	validator        *Validator
 	pool             *ring_client.Pool
 
-	rateStore    RateStore
-	shardTracker *ShardTracker
+	rateStore        RateStore
+	shardTracker     *ShardTracker
+	cardinalityGuard *cardinalityGuard
+	quotaStore       *quotaStore
+	splunkTokens     *runtimeconfig.Manager
 
 	// The global rate limiter requires a distributors ring to count
 	// the number of healthy instances.
@@ -105,6 +125,7 @@ type Distributor struct {
 	ingesterAppendFailures *prometheus.CounterVec
 	replicationFactor      prometheus.Gauge
 	streamShardCount       prometheus.Counter
+	ingestRulesMetrics     *ingestrules.Metrics
 }
 
 // New a distributor creates.
@@ -195,6 +216,7 @@ func New(
 			Name:      "stream_sharding_count",
 			Help:      "Total number of times the distributor has sharded streams",
 		}),
+		ingestRulesMetrics: ingestrules.NewMetrics(registerer),
 	}
 	d.replicationFactor.Set(float64(ingestersRing.ReplicationFactor()))
 	rfStats.Set(int64(ingestersRing.ReplicationFactor()))
@@ -213,7 +235,29 @@ func New(
 	)
 	d.rateStore = rs
 
-	servs = append(servs, d.pool, rs)
+	d.cardinalityGuard = newCardinalityGuard(cfg.CardinalityGuard, overrides, registerer)
+	d.quotaStore = newQuotaStore(
+		cfg.QuotaStore,
+		ingestersRing,
+		clientpool.NewPool(
+			clientCfg.PoolConfig,
+			ingestersRing,
+			internalFactory,
+			util_log.Logger,
+		),
+		overrides,
+		registerer,
+	)
+
+	servs = append(servs, d.pool, rs, d.cardinalityGuard, d.quotaStore)
+
+	d.splunkTokens, err = newSplunkTokensManager(cfg.SplunkTokens, registerer)
+	if err != nil {
+		return nil, errors.Wrap(err, "create splunk tokens manager")
+	}
+	if d.splunkTokens != nil {
+		servs = append(servs, d.splunkTokens)
+	}
 	d.subservices, err = services.NewManager(servs...)
 	if err != nil {
 		return nil, errors.Wrap(err, "services manager")
@@ -272,15 +316,70 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 		return &logproto.PushResponse{}, nil
 	}
 
+	var routed map[string][]logproto.Stream
+	req.Streams, routed = ingestrules.Apply(d.validator.Limits.IngestRules(tenantID), tenantID, req.Streams, time.Now(), d.ingestRulesMetrics)
+
+	// The streams of every tenant, including the streams that ingest rules route to other tenants, are validated
+	// and checked against the limits of their tenant before any of them is sent, so that a push that exceeds the
+	// limits of one of the tenants isn't written for the others. The limits that are already charged to the tenants
+	// checked before the failing one are not refunded. Sending isn't all-or-nothing: the streams are sent one tenant
+	// after the other, and a tenant whose streams fail to be written leaves the streams of the tenants sent before it
+	// written. The rules of the target tenants are not applied to routed streams.
+	tenantIDs := make([]string, 0, len(routed))
+	for target := range routed {
+		tenantIDs = append(tenantIDs, target)
+	}
+	sort.Strings(tenantIDs)
+
+	push, err := d.preparePush(ctx, tenantID, req.Streams)
+	if err != nil {
+		return nil, err
+	}
+	pushes := []*preparedPush{push}
+	for _, target := range tenantIDs {
+		push, err := d.preparePush(ctx, target, routed[target])
+		if err != nil {
+			return nil, err
+		}
+		pushes = append(pushes, push)
+	}
+
+	var validationErr error
+	for _, push := range pushes {
+		if err := d.sendPush(ctx, push); err != nil {
+			return nil, err
+		}
+		if push.validationErr != nil {
+			validationErr = push.validationErr
+		}
+	}
+	return &logproto.PushResponse{}, validationErr
+}
+
+// preparedPush holds the streams of a tenant that passed validation and the limits of the tenant,
+// and the keys of the ingesters they are sent to.
+type preparedPush struct {
+	tenantID string
+	streams  []streamTracker
+	keys     []uint32
+	// validationErr is the last error of the entries and streams that failed validation and were dropped.
+	validationErr error
+}
+
+// preparePush validates the streams of the tenant and checks them against its rate limit and quota.
+func (d *Distributor) preparePush(ctx context.Context, tenantID string, reqStreams []logproto.Stream) (*preparedPush, error) {
 	// First we flatten out the request into a list of samples.
 	// We use the heuristic of 1 sample per TS to size the array.
 	// We also work out the hash value at the same time.
-	streams := make([]streamTracker, 0, len(req.Streams))
-	keys := make([]uint32, 0, len(req.Streams))
+	streams := make([]streamTracker, 0, len(reqStreams))
+	keys := make([]uint32, 0, len(reqStreams))
 	validatedLineSize := 0
 	validatedLineCount := 0
 
-	var validationErr error
+	var (
+		validationErr error
+		err           error
+	)
 	validationContext := d.validator.getValidationContextForTime(time.Now(), tenantID)
 
 	func() {
@@ -291,14 +390,25 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 				sp.LogKV("event", "finished to validate request")
 			}()
 		}
-		for _, stream := range req.Streams {
+		for _, stream := range reqStreams {
 			// Return early if stream does not contain any entries
 			if len(stream.Entries) == 0 {
 				continue
 			}
 
 			// Truncate first so subsequent steps have consistent line lengths
-			d.truncateLines(validationContext, &stream)
+			truncateLines(validationContext, &stream)
+
+			if err := d.guardCardinality(validationContext, &stream); err != nil {
+				validationErr = err
+				validation.DiscardedSamples.WithLabelValues(validation.CardinalityGuard, tenantID).Add(float64(len(stream.Entries)))
+				bytes := 0
+				for _, e := range stream.Entries {
+					bytes += len(e.Line)
+				}
+				validation.DiscardedBytes.WithLabelValues(validation.CardinalityGuard, tenantID).Add(float64(bytes))
+				continue
+			}
 
 			stream.Labels, stream.Hash, err = d.parseStreamLabels(validationContext, stream.Labels, &stream)
 			if err != nil {
@@ -353,9 +463,11 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 		}
 	}()
 
+	push := &preparedPush{tenantID: tenantID, streams: streams, keys: keys, validationErr: validationErr}
+
 	// Return early if none of the streams contained entries
 	if len(streams) == 0 {
-		return &logproto.PushResponse{}, validationErr
+		return push, nil
 	}
 
 	now := time.Now()
@@ -366,6 +478,21 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 		return nil, httpgrpc.Errorf(http.StatusTooManyRequests, validation.RateLimitedErrorMsg, tenantID, int(d.ingestionRateLimiter.Limit(now, tenantID)), validatedLineCount, validatedLineSize)
 	}
 
+	if err := d.quotaStore.allow(tenantID, validatedLineSize, now); err != nil {
+		validation.DiscardedSamples.WithLabelValues(validation.QuotaExceeded, tenantID).Add(float64(validatedLineCount))
+		validation.DiscardedBytes.WithLabelValues(validation.QuotaExceeded, tenantID).Add(float64(validatedLineSize))
+		return nil, err
+	}
+	return push, nil
+}
+
+// sendPush sends the streams of the prepared push to their ingesters.
+func (d *Distributor) sendPush(ctx context.Context, push *preparedPush) error {
+	if len(push.streams) == 0 {
+		return nil
+	}
+	tenantID, streams, keys := push.tenantID, push.streams, push.keys
+
 	const maxExpectedReplicationSet = 5 // typical replication factor 3 plus one for inactive plus one for luck
 	var descs [maxExpectedReplicationSet]ring.InstanceDesc
 
@@ -396,7 +523,7 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 		}
 		return nil
 	}(); err != nil {
-		return nil, err
+		return err
 	}
 
 	tracker := pushTracker{
@@ -418,11 +545,11 @@ func (d *Distributor) Push(ctx context.Context, req *logproto.PushRequest) (*log
 	}
 	select {
 	case err := <-tracker.err:
-		return nil, err
+		return err
 	case <-tracker.done:
-		return &logproto.PushResponse{}, validationErr
+		return nil
 	case <-ctx.Done():
-		return nil, ctx.Err()
+		return ctx.Err()
 	}
 }
 
@@ -537,7 +664,7 @@ func maxT(t1, t2 time.Time) time.Time {
 	return t1
 }
 
-func (d *Distributor) truncateLines(vContext validationContext, stream *logproto.Stream) {
+func truncateLines(vContext validationContext, stream *logproto.Stream) {
 	if !vContext.maxLineSizeTruncate {
 		return
 	}
@@ -637,6 +764,35 @@ func (d *Distributor) parseStreamLabels(vContext validationContext, key string,
 	return lsVal, lsHash, nil
 }
 
+// guardCardinality records the label values of the stream in the cardinality guard when it is enabled for the tenant,
+// and strips the offending labels from the stream or rejects it, depending on the action of the tenant.
+func (d *Distributor) guardCardinality(vContext validationContext, stream *logproto.Stream) error {
+	if vContext.cardinalityGuardMaxLabelValues <= 0 {
+		return nil
+	}
+	ls, err := syntax.ParseLabels(stream.Labels)
+	if err != nil {
+		// Invalid labels are rejected by the validation.
+		return nil
+	}
+
+	offending := d.cardinalityGuard.observe(vContext.userID, ls, time.Now(), vContext.cardinalityGuardMaxLabelValues)
+	if len(offending) == 0 {
+		return nil
+	}
+	switch vContext.cardinalityGuardAction {
+	case validation.CardinalityGuardStrip:
+		for _, name := range offending {
+			d.cardinalityGuard.lines.WithLabelValues(vContext.userID, name, validation.CardinalityGuardStrip).Add(float64(len(stream.Entries)))
+		}
+		stream.Labels = labels.NewBuilder(ls).Del(offending...).Labels(nil).String()
+	case validation.CardinalityGuardReject:
+		d.cardinalityGuard.lines.WithLabelValues(vContext.userID, offending[0], validation.CardinalityGuardReject).Add(float64(len(stream.Entries)))
+		return httpgrpc.Errorf(http.StatusBadRequest, validation.CardinalityGuardErrorMsg, stream.Labels, offending[0], vContext.cardinalityGuardMaxLabelValues)
+	}
+	return nil
+}
+
 // shardCountFor returns the right number of shards to be used by the given stream.
 //
 // It first checks if the number of shards is present in the shard store. If it isn't it will calculate it
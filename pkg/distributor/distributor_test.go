@@ -27,6 +27,7 @@ import (
 	"github.com/weaveworks/common/user"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health/grpc_health_v1"
+	"gopkg.in/yaml.v2"
 
 	"github.com/grafana/loki/pkg/ingester"
 	"github.com/grafana/loki/pkg/ingester/client"
@@ -466,6 +467,103 @@ func Test_TruncateLogLines(t *testing.T) {
 	})
 }
 
+func Test_IngestRulesOnPush(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.EnforceMetricName = false
+	require.NoError(t, yaml.Unmarshal([]byte(`
+ingest_rules:
+  - name: drop-debug
+    drop:
+      expression: debug
+  - name: route-audit
+    selector: '{app="audit"}'
+    tenant:
+      value: audit
+`), limits))
+	require.NoError(t, limits.Validate())
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	request := makeWriteRequestWithLabels(2, 10, []string{`{app="api"}`, `{app="audit"}`})
+	request.Streams[0].Entries[0].Line = "debug line"
+	_, err := distributors[0].Push(ctx, request)
+	require.NoError(t, err)
+
+	// The routed stream is pushed in a separate request for the audit tenant.
+	entries := map[string]int{}
+	ingester.mu.Lock()
+	for _, req := range ingester.pushed {
+		require.Len(t, req.Streams, 1)
+		entries[req.Streams[0].Labels] = len(req.Streams[0].Entries)
+	}
+	ingester.mu.Unlock()
+	require.Equal(t, map[string]int{`{app="api"}`: 1, `{app="audit"}`: 2}, entries)
+}
+
+func Test_IngestRulesOnPush_LimitsAllOrNothing(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.EnforceMetricName = false
+	limits.IngestionQuotaDaily = 100
+	require.NoError(t, yaml.Unmarshal([]byte(`
+ingest_rules:
+  - name: route-audit
+    selector: '{app="audit"}'
+    tenant:
+      value: audit
+`), limits))
+	require.NoError(t, limits.Validate())
+
+	ingester := &mockIngester{}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	// The streams of the tenant are within its quota, but the routed stream exceeds the quota of the audit tenant.
+	request := makeWriteRequestWithLabels(1, 10, []string{`{app="api"}`})
+	request.Streams = append(request.Streams, makeWriteRequestWithLabels(1, 200, []string{`{app="audit"}`}).Streams...)
+	_, err := distributors[0].Push(ctx, request)
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, int32(http.StatusTooManyRequests), resp.Code)
+	require.Contains(t, string(resp.Body), "audit")
+
+	ingester.mu.Lock()
+	defer ingester.mu.Unlock()
+	require.Empty(t, ingester.pushed)
+}
+
+func Test_IngestRulesOnPush_RoutedSendFails(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.EnforceMetricName = false
+	require.NoError(t, yaml.Unmarshal([]byte(`
+ingest_rules:
+  - name: route-audit
+    selector: '{app="audit"}'
+    tenant:
+      value: audit
+`), limits))
+	require.NoError(t, limits.Validate())
+
+	ingester := &mockIngester{failTenant: "audit"}
+	distributors, _ := prepare(t, 1, 5, limits, func(addr string) (ring_client.PoolClient, error) { return ingester, nil })
+
+	request := makeWriteRequestWithLabels(1, 10, []string{`{app="api"}`, `{app="audit"}`})
+	_, err := distributors[0].Push(ctx, request)
+	require.Error(t, err)
+
+	// Sending isn't all-or-nothing: the streams of the tenant are sent before the
+	// routed streams and stay written when the ingesters fail to write the latter.
+	ingester.mu.Lock()
+	defer ingester.mu.Unlock()
+	require.NotEmpty(t, ingester.pushed)
+	for _, req := range ingester.pushed {
+		require.Len(t, req.Streams, 1)
+		require.Equal(t, `{app="api"}`, req.Streams[0].Labels)
+	}
+}
+
 func TestStreamShard(t *testing.T) {
 	// setup base stream.
 	baseStream := logproto.Stream{}
@@ -1120,8 +1218,10 @@ type mockIngester struct {
 
 	failAfter    time.Duration
 	succeedAfter time.Duration
-	mu           sync.Mutex
-	pushed       []*logproto.PushRequest
+	// failTenant is the tenant whose pushes fail.
+	failTenant string
+	mu         sync.Mutex
+	pushed     []*logproto.PushRequest
 }
 
 func (i *mockIngester) Push(ctx context.Context, in *logproto.PushRequest, opts ...grpc.CallOption) (*logproto.PushResponse, error) {
@@ -1132,6 +1232,11 @@ func (i *mockIngester) Push(ctx context.Context, in *logproto.PushRequest, opts
 	if i.succeedAfter > 0 {
 		time.Sleep(i.succeedAfter)
 	}
+	if i.failTenant != "" {
+		if tenantID, _ := user.ExtractOrgID(ctx); tenantID == i.failTenant {
+			return nil, fmt.Errorf("push request failed")
+		}
+	}
 
 	i.mu.Lock()
 	defer i.mu.Unlock()
@@ -2,10 +2,13 @@ package distributor
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-kit/log/level"
 	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/user"
 
 	"github.com/grafana/loki/pkg/util"
 
@@ -18,14 +21,123 @@ import (
 
 // PushHandler reads a snappy-compressed proto from the HTTP body.
 func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
+	d.pushHandler(w, r, push.ParseLokiRequest, writeNoContent, http.Error)
+}
+
+// OTLPPushHandler reads an OTLP logs export request from the HTTP body.
+func (d *Distributor) OTLPPushHandler(w http.ResponseWriter, r *http.Request) {
+	d.pushHandler(w, r, push.NewOTLPRequestParser(d.cfg.MaxRecvMsgSize), func(w http.ResponseWriter) {
+		push.WriteOTLPResponse(w, r)
+	}, http.Error)
+}
+
+// ElasticsearchBulkHandler reads an Elasticsearch bulk API request from the HTTP body
+// and responds with the result of each of its items, once they are pushed.
+func (d *Distributor) ElasticsearchBulkHandler(w http.ResponseWriter, r *http.Request) {
+	var bulk push.ElasticsearchBulk
+	d.pushHandler(w, r, bulk.Parse, bulk.WriteResponse, bulk.WriteError)
+}
+
+// SplunkEventHandler reads events of the Splunk HEC event endpoint from the HTTP body.
+func (d *Distributor) SplunkEventHandler(w http.ResponseWriter, r *http.Request) {
+	d.pushHandler(w, r, push.ParseSplunkEventRequest, push.WriteSplunkSuccess, http.Error)
+}
+
+// SplunkRawHandler reads raw events of the Splunk HEC raw endpoint from the HTTP body.
+func (d *Distributor) SplunkRawHandler(w http.ResponseWriter, r *http.Request) {
+	d.pushHandler(w, r, push.ParseSplunkRawRequest, push.WriteSplunkSuccess, http.Error)
+}
+
+// SplunkTokenAuthMiddleware authenticates requests to the Splunk HEC API by their HEC token,
+// and injects the tenant the token is configured for into the request context.
+func (d *Distributor) SplunkTokenAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := splunkToken(r)
+		if token == "" {
+			push.WriteSplunkResponse(w, http.StatusUnauthorized, "Token is required", 2)
+			return
+		}
+		tenantID, ok := d.splunkTenant(token)
+		if !ok {
+			push.WriteSplunkResponse(w, http.StatusForbidden, "Invalid token", 4)
+			return
+		}
+		ctx := user.InjectOrgID(r.Context(), tenantID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// splunkToken returns the HEC token of a request, which is passed as "Authorization: Splunk <token>" header.
+func splunkToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Splunk") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// splunkTenant returns the tenant the HEC token is configured for in the HEC tokens file.
+func (d *Distributor) splunkTenant(token string) (string, bool) {
+	if d.splunkTokens == nil {
+		return "", false
+	}
+	tokens, _ := d.splunkTokens.GetConfig().(splunkTokens)
+	return tokens.tenant(token)
+}
+
+// CardinalityHandler responds with the labels of the tenant with the most distinct values,
+// as seen by the cardinality guard of this distributor.
+func (d *Distributor) CardinalityHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	limit := 10
+	if s := r.FormValue("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil || limit < 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	util.WriteJSONResponse(w, struct {
+		Limit  int                `json:"limit"`
+		Action string             `json:"action"`
+		Labels []LabelCardinality `json:"labels"`
+	}{
+		Limit:  d.validator.Limits.CardinalityGuardMaxLabelValues(tenantID),
+		Action: d.validator.Limits.CardinalityGuardAction(tenantID),
+		Labels: d.cardinalityGuard.topLabels(tenantID, limit),
+	})
+}
+
+// QuotaHandler responds with the usage of the daily and monthly ingestion quotas of the tenant.
+func (d *Distributor) QuotaHandler(w http.ResponseWriter, r *http.Request) {
+	tenantID, err := tenant.TenantID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	util.WriteJSONResponse(w, d.quotaStore.status(tenantID, time.Now()))
+}
+
+func writeNoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pushHandler parses the request with the given parser and pushes it.
+// The response of a successful push is written by respond, errors are written by fail.
+func (d *Distributor) pushHandler(w http.ResponseWriter, r *http.Request, parser push.RequestParser, respond func(http.ResponseWriter), fail func(http.ResponseWriter, string, int)) {
 	logger := util_log.WithContext(r.Context(), util_log.Logger)
 	tenantID, err := tenant.TenantID(r.Context())
 	if err != nil {
 		level.Error(logger).Log("msg", "error getting tenant id", "err", err)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		fail(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	req, err := push.ParseRequest(logger, tenantID, r, d.tenantsRetention)
+	req, err := push.ParseRequest(logger, tenantID, r, d.tenantsRetention, d.validator.Limits, parser)
 	if err != nil {
 		if d.tenantConfigs.LogPushRequest(tenantID) {
 			level.Debug(logger).Log(
@@ -34,7 +146,7 @@ func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
 				"err", err,
 			)
 		}
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		fail(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -56,7 +168,7 @@ func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
 				"msg", "push request successful",
 			)
 		}
-		w.WriteHeader(http.StatusNoContent)
+		respond(w)
 		return
 	}
 
@@ -70,7 +182,7 @@ func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
 				"err", body,
 			)
 		}
-		http.Error(w, body, int(resp.Code))
+		fail(w, body, int(resp.Code))
 	} else {
 		if d.tenantConfigs.LogPushRequest(tenantID) {
 			level.Debug(logger).Log(
@@ -79,7 +191,7 @@ func (d *Distributor) PushHandler(w http.ResponseWriter, r *http.Request) {
 				"err", err.Error(),
 			)
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		fail(w, err.Error(), http.StatusInternalServerError)
 	}
 }
 
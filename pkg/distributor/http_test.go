@@ -1,12 +1,20 @@
 package distributor
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/grafana/dskit/flagext"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/pkg/validation"
@@ -54,3 +62,67 @@ func TestDistributorRingHandler(t *testing.T) {
 		require.NotContains(t, string(body), "<th>Instance ID</th>")
 	})
 }
+
+func TestSplunkTokenAuthMiddleware(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "tokens.yaml")
+	require.NoError(t, os.WriteFile(file, []byte("tenants:\n  tenant-1: [token-1]\n"), 0o600))
+	tokens, err := newSplunkTokensManager(SplunkTokensConfig{File: file, ReloadPeriod: time.Minute}, prometheus.NewRegistry())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), tokens))
+	defer func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), tokens))
+	}()
+
+	d := &Distributor{splunkTokens: tokens}
+	handler := d.SplunkTokenAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := tenant.TenantID(r.Context())
+		require.NoError(t, err)
+		_, _ = w.Write([]byte(tenantID))
+	}))
+
+	for _, tc := range []struct {
+		authorization string
+		expectedCode  int
+		expectedBody  string
+	}{
+		{"Splunk token-1", http.StatusOK, "tenant-1"},
+		{"splunk token-1", http.StatusOK, "tenant-1"},
+		{"Splunk token-2", http.StatusForbidden, `{"text":"Invalid token","code":4}` + "\n"},
+		{"Bearer token-1", http.StatusUnauthorized, `{"text":"Token is required","code":2}` + "\n"},
+		{"", http.StatusUnauthorized, `{"text":"Token is required","code":2}` + "\n"},
+	} {
+		t.Run(tc.authorization, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/services/collector/event", nil)
+			if tc.authorization != "" {
+				r.Header.Set("Authorization", tc.authorization)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			require.Equal(t, tc.expectedCode, w.Code)
+			require.Equal(t, tc.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestLoadSplunkTokens(t *testing.T) {
+	loaded, err := loadSplunkTokens(strings.NewReader("tenants:\n  tenant-1: [token-1, token-2]\n  tenant-2: [token-3]\n"))
+	require.NoError(t, err)
+	tokens := loaded.(splunkTokens)
+	for token, expected := range map[string]string{"token-1": "tenant-1", "token-2": "tenant-1", "token-3": "tenant-2"} {
+		tenantID, ok := tokens.tenant(token)
+		require.True(t, ok)
+		require.Equal(t, expected, tenantID)
+	}
+	_, ok := tokens.tenant("token-4")
+	require.False(t, ok)
+
+	_, err = loadSplunkTokens(strings.NewReader("tenants:\n  tenant-1: [token-1]\n  tenant-2: [token-1]\n"))
+	require.EqualError(t, err, "HEC token 0 of tenant tenant-2 is already configured for tenant tenant-1")
+
+	_, err = loadSplunkTokens(strings.NewReader("tenants:\n  tenant-1: ['']\n"))
+	require.Error(t, err)
+
+	loaded, err = loadSplunkTokens(strings.NewReader(""))
+	require.NoError(t, err)
+	require.Empty(t, loaded)
+}
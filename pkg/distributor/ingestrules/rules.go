@@ -0,0 +1,429 @@
+// Package ingestrules implements the per-tenant rules that the distributor applies to the streams of a push request
+// before they are validated. The rules fix the labels and lines of badly labelled data without changing the
+// agents that send it.
+package ingestrules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/ruler/util"
+	"github.com/grafana/loki/pkg/util/flagext"
+)
+
+// Rule is a single ingest rule. It applies exactly one action to the streams that match its selector.
+type Rule struct {
+	// Name identifies the rule in the metrics of dropped and rewritten lines.
+	Name string `yaml:"name" json:"name"`
+	// Selector restricts the rule to the streams that match it. The rule applies to all streams when empty.
+	Selector string `yaml:"selector,omitempty" json:"selector,omitempty"`
+
+	// RelabelConfigs rewrite the labels of a stream, like Prometheus relabeling.
+	// Streams are dropped when a relabel config drops them.
+	RelabelConfigs []*util.RelabelConfig `yaml:"relabel_configs,omitempty" json:"relabel_configs,omitempty"`
+	// Drop drops the lines that match all of its conditions.
+	Drop *DropStage `yaml:"drop,omitempty" json:"drop,omitempty"`
+	// Replace replaces all matches of a regular expression in the lines.
+	Replace *ReplaceStage `yaml:"replace,omitempty" json:"replace,omitempty"`
+	// Labels adds the named capture groups of a regular expression that matches a line as labels.
+	Labels *LabelsStage `yaml:"labels,omitempty" json:"labels,omitempty"`
+	// LabelDrop removes the given labels from the streams.
+	LabelDrop []string `yaml:"labeldrop,omitempty" json:"labeldrop,omitempty"`
+	// Tenant routes the streams to another tenant.
+	Tenant *TenantStage `yaml:"tenant,omitempty" json:"tenant,omitempty"`
+
+	matchers []*labels.Matcher
+	relabel  []*relabel.Config
+	regexp   *regexp.Regexp
+}
+
+// DropStage drops lines. All configured conditions must match for a line to be dropped.
+type DropStage struct {
+	// Expression is a regular expression that matches the lines to drop.
+	Expression string `yaml:"expression,omitempty" json:"expression,omitempty"`
+	// LongerThan drops lines that are longer than the given size.
+	LongerThan flagext.ByteSize `yaml:"longer_than,omitempty" json:"longer_than,omitempty"`
+	// OlderThan drops lines whose timestamp is older than the given duration.
+	OlderThan model.Duration `yaml:"older_than,omitempty" json:"older_than,omitempty"`
+}
+
+// ReplaceStage replaces all matches of the expression in a line with the replacement.
+// The replacement can reference capture groups with $1 or ${name}.
+type ReplaceStage struct {
+	Expression string `yaml:"expression" json:"expression"`
+	Replace    string `yaml:"replace" json:"replace"`
+}
+
+// LabelsStage adds the named capture groups of the expression as labels to the lines it matches.
+// Lines with different values form different streams.
+type LabelsStage struct {
+	Expression string `yaml:"expression" json:"expression"`
+}
+
+// TenantStage routes streams to another tenant, either a fixed one or the one in the value of a label.
+// The labels of a stream are set by the client that pushes it, so tenants taken from a label must be in Allowed.
+type TenantStage struct {
+	Value   string   `yaml:"value,omitempty" json:"value,omitempty"`
+	Label   string   `yaml:"label,omitempty" json:"label,omitempty"`
+	Allowed []string `yaml:"allowed,omitempty" json:"allowed,omitempty"`
+}
+
+func (t *TenantStage) allows(tenantID string) bool {
+	if t.Label == "" {
+		return true
+	}
+	for _, allowed := range t.Allowed {
+		if allowed == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate validates the rules and compiles their selectors and expressions.
+func Validate(rules []*Rule) error {
+	names := map[string]struct{}{}
+	for _, r := range rules {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("invalid ingest rule %q: %w", r.Name, err)
+		}
+		if _, ok := names[r.Name]; ok {
+			return fmt.Errorf("duplicate ingest rule name %q", r.Name)
+		}
+		names[r.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (r *Rule) validate() error {
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+
+	actions := 0
+	for _, set := range []bool{len(r.RelabelConfigs) > 0, r.Drop != nil, r.Replace != nil, r.Labels != nil, len(r.LabelDrop) > 0, r.Tenant != nil} {
+		if set {
+			actions++
+		}
+	}
+	if actions != 1 {
+		return errors.New("exactly one of relabel_configs, drop, replace, labels, labeldrop and tenant must be set")
+	}
+
+	r.matchers = nil
+	if r.Selector != "" {
+		matchers, err := syntax.ParseMatchers(r.Selector)
+		if err != nil {
+			return fmt.Errorf("invalid selector: %w", err)
+		}
+		r.matchers = matchers
+	}
+
+	var err error
+	switch {
+	case len(r.RelabelConfigs) > 0:
+		r.relabel, err = toRelabelConfigs(r.RelabelConfigs)
+	case r.Drop != nil:
+		if r.Drop.Expression == "" && r.Drop.LongerThan == 0 && r.Drop.OlderThan == 0 {
+			return errors.New("drop requires at least one of expression, longer_than and older_than")
+		}
+		r.regexp, err = compile(r.Drop.Expression)
+	case r.Replace != nil:
+		if r.Replace.Expression == "" {
+			return errors.New("replace requires an expression")
+		}
+		r.regexp, err = compile(r.Replace.Expression)
+	case r.Labels != nil:
+		r.regexp, err = compile(r.Labels.Expression)
+		if err == nil && (r.regexp == nil || len(r.regexp.SubexpNames()) < 2) {
+			return errors.New("labels requires an expression with named capture groups")
+		}
+		for _, name := range r.regexpNames() {
+			if !model.LabelName(name).IsValid() {
+				return fmt.Errorf("capture group %q is not a valid label name", name)
+			}
+		}
+	case r.Tenant != nil:
+		if (r.Tenant.Value == "") == (r.Tenant.Label == "") {
+			return errors.New("tenant requires exactly one of value and label")
+		}
+		if r.Tenant.Value != "" {
+			if len(r.Tenant.Allowed) > 0 {
+				return errors.New("tenant allowed is only supported with label")
+			}
+			err = tenant.ValidTenantID(r.Tenant.Value)
+		}
+		if r.Tenant.Label != "" && len(r.Tenant.Allowed) == 0 {
+			return errors.New("tenant with label requires the allowed tenants")
+		}
+		for _, allowed := range r.Tenant.Allowed {
+			if err := tenant.ValidTenantID(allowed); err != nil {
+				return fmt.Errorf("invalid allowed tenant %q: %w", allowed, err)
+			}
+		}
+	}
+	return err
+}
+
+func (r *Rule) regexpNames() []string {
+	if r.regexp == nil {
+		return nil
+	}
+	var names []string
+	for _, name := range r.regexp.SubexpNames() {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func compile(expr string) (*regexp.Regexp, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+	return re, nil
+}
+
+// toRelabelConfigs converts the util.RelabelConfig into relabel.Config, which validates them.
+func toRelabelConfigs(configs []*util.RelabelConfig) ([]*relabel.Config, error) {
+	relabelConfigs := make([]*relabel.Config, len(configs))
+	for i, config := range configs {
+		out, err := yaml.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		var rc relabel.Config
+		if err = yaml.Unmarshal(out, &rc); err != nil {
+			return nil, fmt.Errorf("invalid relabel config: %w", err)
+		}
+		relabelConfigs[i] = &rc
+	}
+	return relabelConfigs, nil
+}
+
+// Metrics count the lines that are dropped and rewritten by each rule.
+type Metrics struct {
+	linesDropped   *prometheus.CounterVec
+	linesRewritten *prometheus.CounterVec
+}
+
+func NewMetrics(registerer prometheus.Registerer) *Metrics {
+	return &Metrics{
+		linesDropped: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "distributor_ingest_rule_lines_dropped_total",
+			Help:      "The total number of lines dropped by ingest rules.",
+		}, []string{"tenant", "rule"}),
+		linesRewritten: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "distributor_ingest_rule_lines_rewritten_total",
+			Help:      "The total number of lines whose labels, content or tenant were changed by ingest rules.",
+		}, []string{"tenant", "rule"}),
+	}
+}
+
+// stream is a stream that is being processed by the rules.
+type stream struct {
+	labels  labels.Labels
+	tenant  string
+	entries []logproto.Entry
+}
+
+// Apply applies the rules in order to the streams of a push request of the tenant.
+// It returns the resulting streams of the tenant, and the streams that are routed to other tenants by tenant.
+// Streams whose labels cannot be parsed are passed on unchanged, so that they fail validation.
+func Apply(rules []*Rule, tenantID string, streams []logproto.Stream, now time.Time, metrics *Metrics) ([]logproto.Stream, map[string][]logproto.Stream) {
+	if len(rules) == 0 {
+		return streams, nil
+	}
+
+	var (
+		result  = make([]logproto.Stream, 0, len(streams))
+		routed  map[string][]logproto.Stream
+		pending []stream
+	)
+	for _, s := range streams {
+		lbs, err := syntax.ParseLabels(s.Labels)
+		if err != nil {
+			result = append(result, s)
+			continue
+		}
+		pending = append(pending, stream{labels: lbs, tenant: tenantID, entries: s.Entries})
+	}
+
+	for _, r := range rules {
+		next := pending[:0:0]
+		for _, s := range pending {
+			if !r.matches(s.labels) {
+				next = append(next, s)
+				continue
+			}
+			out, dropped, rewritten := r.apply(s, now)
+			next = append(next, out...)
+			if dropped > 0 {
+				metrics.linesDropped.WithLabelValues(tenantID, r.Name).Add(float64(dropped))
+			}
+			if rewritten > 0 {
+				metrics.linesRewritten.WithLabelValues(tenantID, r.Name).Add(float64(rewritten))
+			}
+		}
+		pending = next
+	}
+
+	for _, s := range pending {
+		if len(s.entries) == 0 {
+			continue
+		}
+		out := logproto.Stream{Labels: s.labels.String(), Entries: s.entries}
+		if s.tenant == tenantID {
+			result = append(result, out)
+			continue
+		}
+		if routed == nil {
+			routed = map[string][]logproto.Stream{}
+		}
+		routed[s.tenant] = append(routed[s.tenant], out)
+	}
+	return result, routed
+}
+
+func (r *Rule) matches(lbs labels.Labels) bool {
+	for _, m := range r.matchers {
+		if !m.Matches(lbs.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// apply applies the rule to a stream and returns the resulting streams
+// and the number of dropped and rewritten lines.
+func (r *Rule) apply(s stream, now time.Time) ([]stream, int, int) {
+	switch {
+	case r.relabel != nil:
+		lbs, keep := relabel.Process(s.labels, r.relabel...)
+		if !keep || len(lbs) == 0 {
+			return nil, len(s.entries), 0
+		}
+		if labels.Equal(lbs, s.labels) {
+			return []stream{s}, 0, 0
+		}
+		s.labels = lbs
+		return []stream{s}, 0, len(s.entries)
+
+	case len(r.LabelDrop) > 0:
+		b := labels.NewBuilder(s.labels)
+		b.Del(r.LabelDrop...)
+		lbs := b.Labels(nil)
+		if len(lbs) == len(s.labels) {
+			return []stream{s}, 0, 0
+		}
+		s.labels = lbs
+		return []stream{s}, 0, len(s.entries)
+
+	case r.Tenant != nil:
+		target := r.Tenant.Value
+		if r.Tenant.Label != "" {
+			target = s.labels.Get(r.Tenant.Label)
+		}
+		// Streams without a valid or allowed target tenant stay with their tenant.
+		if target == "" || target == s.tenant || tenant.ValidTenantID(target) != nil || !r.Tenant.allows(target) {
+			return []stream{s}, 0, 0
+		}
+		s.tenant = target
+		return []stream{s}, 0, len(s.entries)
+
+	case r.Drop != nil:
+		entries := make([]logproto.Entry, 0, len(s.entries))
+		for _, e := range s.entries {
+			if !r.drops(e, now) {
+				entries = append(entries, e)
+			}
+		}
+		dropped := len(s.entries) - len(entries)
+		s.entries = entries
+		return []stream{s}, dropped, 0
+
+	case r.Replace != nil:
+		rewritten := 0
+		entries := make([]logproto.Entry, len(s.entries))
+		for i, e := range s.entries {
+			if line := r.regexp.ReplaceAllString(e.Line, r.Replace.Replace); line != e.Line {
+				e.Line = line
+				rewritten++
+			}
+			entries[i] = e
+		}
+		s.entries = entries
+		return []stream{s}, 0, rewritten
+
+	case r.Labels != nil:
+		return r.splitByLabels(s)
+	}
+	return []stream{s}, 0, 0
+}
+
+func (r *Rule) drops(e logproto.Entry, now time.Time) bool {
+	if r.regexp != nil && !r.regexp.MatchString(e.Line) {
+		return false
+	}
+	if r.Drop.LongerThan > 0 && len(e.Line) <= int(r.Drop.LongerThan) {
+		return false
+	}
+	if r.Drop.OlderThan > 0 && !e.Timestamp.Before(now.Add(-time.Duration(r.Drop.OlderThan))) {
+		return false
+	}
+	return true
+}
+
+// splitByLabels adds the captured labels to each line, and groups the lines by their resulting labels.
+func (r *Rule) splitByLabels(s stream) ([]stream, int, int) {
+	var (
+		out       []stream
+		byLabels  = map[string]int{}
+		rewritten = 0
+		names     = r.regexp.SubexpNames()
+	)
+	for _, e := range s.entries {
+		lbs := s.labels
+		if match := r.regexp.FindStringSubmatch(e.Line); match != nil {
+			b := labels.NewBuilder(s.labels)
+			for i, name := range names {
+				if name != "" && match[i] != "" {
+					b.Set(name, match[i])
+				}
+			}
+			if next := b.Labels(nil); !labels.Equal(next, s.labels) {
+				lbs = next
+				rewritten++
+			}
+		}
+
+		key := lbs.String()
+		idx, ok := byLabels[key]
+		if !ok {
+			idx = len(out)
+			byLabels[key] = idx
+			out = append(out, stream{labels: lbs, tenant: s.tenant})
+		}
+		out[idx].entries = append(out[idx].entries, e)
+	}
+	return out, 0, rewritten
+}
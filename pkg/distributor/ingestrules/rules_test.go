@@ -0,0 +1,134 @@
+package ingestrules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func parseRules(t *testing.T, s string) []*Rule {
+	t.Helper()
+	var rules []*Rule
+	require.NoError(t, yaml.UnmarshalStrict([]byte(s), &rules))
+	require.NoError(t, Validate(rules))
+	return rules
+}
+
+func TestValidate(t *testing.T) {
+	for name, s := range map[string]string{
+		"missing name":        `[{drop: {expression: foo}}]`,
+		"duplicate name":      `[{name: a, labeldrop: [foo]}, {name: a, labeldrop: [bar]}]`,
+		"no action":           `[{name: a}]`,
+		"two actions":         `[{name: a, labeldrop: [foo], drop: {expression: foo}}]`,
+		"invalid selector":    `[{name: a, selector: 'foo', labeldrop: [foo]}]`,
+		"empty drop":          `[{name: a, drop: {}}]`,
+		"invalid expression":  `[{name: a, replace: {expression: '(', replace: ''}}]`,
+		"no capture groups":   `[{name: a, labels: {expression: 'level=\w+'}}]`,
+		"tenant value, label": `[{name: a, tenant: {value: foo, label: bar}}]`,
+		"invalid tenant":      `[{name: a, tenant: {value: 'a/b'}}]`,
+		"label, no allowed":   `[{name: a, tenant: {label: bar}}]`,
+		"value, allowed":      `[{name: a, tenant: {value: foo, allowed: [foo]}}]`,
+		"invalid allowed":     `[{name: a, tenant: {label: bar, allowed: ['a/b']}}]`,
+		"invalid relabel":     `[{name: a, relabel_configs: [{action: replace, regex: '('}]}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var rules []*Rule
+			require.NoError(t, yaml.UnmarshalStrict([]byte(s), &rules))
+			require.Error(t, Validate(rules))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	now := time.Unix(1000, 0)
+	rules := parseRules(t, `
+- name: rename-job
+  relabel_configs:
+    - source_labels: [job]
+      target_label: service
+    - action: labeldrop
+      regex: job
+- name: drop-pod
+  labeldrop: [pod]
+- name: drop-health
+  selector: '{service="api"}'
+  drop:
+    expression: GET /health
+- name: drop-old
+  drop:
+    older_than: 1m
+- name: mask-password
+  replace:
+    expression: password=\S+
+    replace: password=***
+- name: extract-level
+  labels:
+    expression: level=(?P<level>\w+)
+- name: route-audit
+  selector: '{service="audit"}'
+  tenant:
+    value: audit
+`)
+
+	streams := []logproto.Stream{
+		{Labels: `{job="api", pod="api-0"}`, Entries: []logproto.Entry{
+			{Timestamp: now, Line: "GET /health 200"},
+			{Timestamp: now, Line: "level=info login password=secret"},
+			{Timestamp: now, Line: "level=error failed"},
+			{Timestamp: now.Add(-time.Hour), Line: "level=info old"},
+		}},
+		{Labels: `{job="audit"}`, Entries: []logproto.Entry{{Timestamp: now, Line: "user created"}}},
+		{Labels: `{invalid`, Entries: []logproto.Entry{{Timestamp: now, Line: "unchanged"}}},
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	metrics := NewMetrics(reg)
+	result, routed := Apply(rules, "tenant", streams, now, metrics)
+
+	require.Equal(t, []logproto.Stream{
+		{Labels: `{invalid`, Entries: []logproto.Entry{{Timestamp: now, Line: "unchanged"}}},
+		{Labels: `{level="info", service="api"}`, Entries: []logproto.Entry{{Timestamp: now, Line: "level=info login password=***"}}},
+		{Labels: `{level="error", service="api"}`, Entries: []logproto.Entry{{Timestamp: now, Line: "level=error failed"}}},
+	}, result)
+	require.Equal(t, map[string][]logproto.Stream{
+		"audit": {{Labels: `{service="audit"}`, Entries: []logproto.Entry{{Timestamp: now, Line: "user created"}}}},
+	}, routed)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.linesDropped.WithLabelValues("tenant", "drop-health")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.linesDropped.WithLabelValues("tenant", "drop-old")))
+	require.Equal(t, 5.0, testutil.ToFloat64(metrics.linesRewritten.WithLabelValues("tenant", "rename-job")))
+	require.Equal(t, 4.0, testutil.ToFloat64(metrics.linesRewritten.WithLabelValues("tenant", "drop-pod")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.linesRewritten.WithLabelValues("tenant", "mask-password")))
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.linesRewritten.WithLabelValues("tenant", "extract-level")))
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.linesRewritten.WithLabelValues("tenant", "route-audit")))
+}
+
+func TestApply_TenantFromLabel(t *testing.T) {
+	rules := parseRules(t, `[{name: route, tenant: {label: namespace, allowed: [team-a]}}]`)
+	streams := []logproto.Stream{
+		{Labels: `{namespace="team-a"}`, Entries: []logproto.Entry{{Line: "a"}}},
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Line: "b"}}},
+		{Labels: `{namespace="team-b"}`, Entries: []logproto.Entry{{Line: "c"}}},
+	}
+
+	result, routed := Apply(rules, "tenant", streams, time.Now(), NewMetrics(nil))
+	require.Equal(t, []logproto.Stream{
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Line: "b"}}},
+		{Labels: `{namespace="team-b"}`, Entries: []logproto.Entry{{Line: "c"}}},
+	}, result)
+	require.Len(t, routed, 1)
+	require.Equal(t, []logproto.Stream{{Labels: `{namespace="team-a"}`, Entries: []logproto.Entry{{Line: "a"}}}}, routed["team-a"])
+}
+
+func TestApply_NoRules(t *testing.T) {
+	streams := []logproto.Stream{{Labels: `{app="foo"}`}}
+	result, routed := Apply(nil, "tenant", streams, time.Now(), nil)
+	require.Equal(t, streams, result)
+	require.Nil(t, routed)
+}
@@ -3,7 +3,9 @@ package distributor
 import (
 	"time"
 
+	"github.com/grafana/loki/pkg/distributor/ingestrules"
 	"github.com/grafana/loki/pkg/distributor/shardstreams"
+	"github.com/grafana/loki/pkg/loghttp/push"
 	"github.com/grafana/loki/pkg/storage/stores/indexshipper/compactor/retention"
 )
 
@@ -22,9 +24,22 @@ type Limits interface {
 	RejectOldSamplesMaxAge(userID string) time.Duration
 
 	IncrementDuplicateTimestamps(userID string) bool
+	AllowStructuredMetadata(userID string) bool
+	MaxStructuredMetadataSize(userID string) int
 
 	ShardStreams(userID string) *shardstreams.Config
 	IngestionRateStrategy() string
 	IngestionRateBytes(userID string) float64
 	IngestionBurstSizeBytes(userID string) int
+	IngestionQuotaDaily(userID string) int
+	IngestionQuotaMonthly(userID string) int
+	IngestionQuotaWarningRatio(userID string) float64
+
+	OTLPConfig(userID string) push.OTLPConfig
+	ElasticsearchConfig(userID string) push.ElasticsearchConfig
+	SplunkConfig(userID string) push.SplunkConfig
+	IngestRules(userID string) []*ingestrules.Rule
+
+	CardinalityGuardMaxLabelValues(userID string) int
+	CardinalityGuardAction(userID string) string
 }
@@ -0,0 +1,107 @@
+package distributor
+
+import (
+	"context"
+	"flag"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/weaveworks/common/httpgrpc"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+type PushStreamConfig struct {
+	MaxInFlightBatches int `yaml:"max_in_flight_batches"`
+}
+
+func (cfg *PushStreamConfig) RegisterFlagsWithPrefix(prefix string, fs *flag.FlagSet) {
+	fs.IntVar(&cfg.MaxInFlightBatches, prefix+".max-in-flight-batches", 8, "The maximum number of batches of a gRPC push stream that are pushed concurrently. The distributor stops reading from the stream until one of them is acknowledged.")
+}
+
+// PushStream implements logproto.StreamPusherServer. Every batch received over the stream is pushed
+// like a Push request, and acknowledged with the status of its push once it completed.
+// Up to MaxInFlightBatches batches are pushed concurrently, further batches are only read once one
+// of them completed, which propagates the backpressure to the client through the gRPC flow control.
+func (d *Distributor) PushStream(stream logproto.StreamPusher_PushStreamServer) error {
+	ctx := stream.Context()
+	if _, err := tenant.TenantID(ctx); err != nil {
+		return err
+	}
+
+	maxInFlight := d.cfg.PushStream.MaxInFlightBatches
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+
+	var (
+		inFlight = make(chan struct{}, maxInFlight)
+		acks     = make(chan *logproto.PushStreamResponse, maxInFlight)
+		sendErr  = make(chan error, 1)
+		wg       sync.WaitGroup
+	)
+
+	// gRPC streams don't support concurrent sends, so a single goroutine acknowledges the batches.
+	go func() {
+		defer close(sendErr)
+		var err error
+		for ack := range acks {
+			if err == nil {
+				err = stream.Send(ack)
+			}
+		}
+		sendErr <- err
+	}()
+
+	var recvErr error
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				recvErr = err
+			}
+			break
+		}
+
+		select {
+		case inFlight <- struct{}{}:
+		case <-ctx.Done():
+			recvErr = ctx.Err()
+		}
+		if recvErr != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			acks <- d.pushBatch(ctx, req)
+			<-inFlight
+		}()
+	}
+
+	wg.Wait()
+	close(acks)
+	if err := <-sendErr; err != nil && recvErr == nil {
+		recvErr = err
+	}
+	return recvErr
+}
+
+// pushBatch pushes a batch of a push stream, and returns its acknowledgement.
+func (d *Distributor) pushBatch(ctx context.Context, req *logproto.PushStreamRequest) *logproto.PushStreamResponse {
+	ack := &logproto.PushStreamResponse{BatchID: req.BatchID, Code: http.StatusOK}
+	if req.Request == nil {
+		return ack
+	}
+
+	if _, err := d.Push(ctx, req.Request); err != nil {
+		ack.Code, ack.Error = http.StatusInternalServerError, err.Error()
+		if resp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+			ack.Code, ack.Error = resp.Code, string(resp.Body)
+		}
+	}
+	return ack
+}
@@ -0,0 +1,78 @@
+package distributor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+type fakePushStreamServer struct {
+	grpc.ServerStream
+
+	ctx  context.Context
+	reqs chan *logproto.PushStreamRequest
+
+	mtx  sync.Mutex
+	acks []*logproto.PushStreamResponse
+}
+
+func (s *fakePushStreamServer) Context() context.Context { return s.ctx }
+
+func (s *fakePushStreamServer) Recv() (*logproto.PushStreamRequest, error) {
+	req, ok := <-s.reqs
+	if !ok {
+		return nil, io.EOF
+	}
+	return req, nil
+}
+
+func (s *fakePushStreamServer) Send(ack *logproto.PushStreamResponse) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.acks = append(s.acks, ack)
+	return nil
+}
+
+func TestDistributor_PushStream(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	limits.EnforceMetricName = false
+
+	distributors, _ := prepare(t, 1, 5, limits, nil)
+
+	invalid := makeWriteRequestWithLabels(1, 10, []string{`{app="api"`})
+	stream := &fakePushStreamServer{ctx: ctx, reqs: make(chan *logproto.PushStreamRequest, 3)}
+	stream.reqs <- &logproto.PushStreamRequest{BatchID: 1, Request: makeWriteRequest(10, 10)}
+	stream.reqs <- &logproto.PushStreamRequest{BatchID: 2, Request: invalid}
+	stream.reqs <- &logproto.PushStreamRequest{BatchID: 3}
+	close(stream.reqs)
+
+	require.NoError(t, distributors[0].PushStream(stream))
+
+	codes := map[uint64]int32{}
+	for _, ack := range stream.acks {
+		codes[ack.BatchID] = ack.Code
+		if ack.Code != http.StatusOK {
+			require.NotEmpty(t, ack.Error)
+		}
+	}
+	require.Equal(t, map[uint64]int32{1: http.StatusOK, 2: http.StatusBadRequest, 3: http.StatusOK}, codes)
+}
+
+func TestDistributor_PushStreamRequiresTenant(t *testing.T) {
+	limits := &validation.Limits{}
+	flagext.DefaultValues(limits)
+	distributors, _ := prepare(t, 1, 5, limits, nil)
+
+	stream := &fakePushStreamServer{ctx: context.Background(), reqs: make(chan *logproto.PushStreamRequest)}
+	require.Error(t, distributors[0].PushStream(stream))
+}
@@ -0,0 +1,352 @@
+package distributor
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/weaveworks/common/httpgrpc"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/util"
+	util_log "github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+const (
+	quotaPeriodDaily   = "daily"
+	quotaPeriodMonthly = "monthly"
+)
+
+type QuotaStoreConfig struct {
+	UpdateInterval     time.Duration `yaml:"update_interval"`
+	IngesterReqTimeout time.Duration `yaml:"ingester_request_timeout"`
+}
+
+func (cfg *QuotaStoreConfig) RegisterFlagsWithPrefix(prefix string, fs *flag.FlagSet) {
+	fs.DurationVar(&cfg.UpdateInterval, prefix+".update-interval", 10*time.Second, "The interval on which distributors update the ingested volumes of the tenants with ingestion quotas from ingesters.")
+	fs.DurationVar(&cfg.IngesterReqTimeout, prefix+".ingester-request-timeout", 500*time.Millisecond, "Timeout for communication between distributors and any given ingester when updating ingested volumes.")
+}
+
+// QuotaStatus is the usage of the ingestion quotas of a tenant.
+type QuotaStatus struct {
+	Daily   *QuotaPeriodStatus `json:"daily,omitempty"`
+	Monthly *QuotaPeriodStatus `json:"monthly,omitempty"`
+}
+
+// QuotaPeriodStatus is the usage of the ingestion quota of a tenant in the current day or month.
+type QuotaPeriodStatus struct {
+	Limit     int64     `json:"limit"`
+	Used      int64     `json:"used"`
+	Remaining int64     `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+	Warning   bool      `json:"warning"`
+	Exceeded  bool      `json:"exceeded"`
+}
+
+// quotaUsage is the number of bytes a tenant ingested in a period.
+type quotaUsage struct {
+	start int64 // start of the period in unix seconds.
+	// cluster is the volume of the period that was last reported by the ingesters.
+	cluster int64
+	// local is the volume that this distributor accepted since.
+	local  int64
+	warned bool
+}
+
+func (u *quotaUsage) used() int64 {
+	return u.cluster + u.local
+}
+
+// reset starts a new period when the period of the usage has passed.
+func (u *quotaUsage) reset(start int64) {
+	if u.start != start {
+		*u = quotaUsage{start: start}
+	}
+}
+
+type tenantQuotaUsage struct {
+	daily, monthly quotaUsage
+}
+
+// quotaStore enforces the daily and monthly ingestion quotas of the tenants. The ingested volume of a tenant
+// across the cluster is periodically fetched from all ingesters, and added to the volume that this
+// distributor accepted since.
+type quotaStore struct {
+	services.Service
+
+	ring            ring.ReadRing
+	clientPool      poolClientFactory
+	limits          Limits
+	ingesterTimeout time.Duration
+
+	mtx     sync.Mutex
+	tenants map[string]*tenantQuotaUsage
+
+	usedBytes      *prometheus.GaugeVec
+	warnings       *prometheus.CounterVec
+	refreshFailure *prometheus.CounterVec
+}
+
+func newQuotaStore(cfg QuotaStoreConfig, r ring.ReadRing, cf poolClientFactory, l Limits, registerer prometheus.Registerer) *quotaStore {
+	s := &quotaStore{
+		ring:            r,
+		clientPool:      cf,
+		limits:          l,
+		ingesterTimeout: cfg.IngesterReqTimeout,
+		tenants:         map[string]*tenantQuotaUsage{},
+		usedBytes: promauto.With(registerer).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "loki",
+			Name:      "distributor_ingestion_quota_used_bytes",
+			Help:      "The number of bytes that tenants with an ingestion quota ingested in the current period.",
+		}, []string{"tenant", "period"}),
+		warnings: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "distributor_ingestion_quota_warnings_total",
+			Help:      "The total number of times that tenants exceeded the warning ratio of their ingestion quota.",
+		}, []string{"tenant", "period"}),
+		refreshFailure: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "distributor_ingestion_quota_refresh_failures_total",
+			Help:      "The total number of failed attempts to fetch the ingested volumes of tenants from ingesters.",
+		}, []string{"source"}),
+	}
+
+	s.Service = services.
+		NewTimerService(util.DurationWithJitter(cfg.UpdateInterval, 0.2), s.updateAllVolumes, s.updateAllVolumes, nil).
+		WithName("quota store")
+	return s
+}
+
+func hasQuota(l Limits, tenantID string) bool {
+	return l.IngestionQuotaDaily(tenantID) > 0 || l.IngestionQuotaMonthly(tenantID) > 0
+}
+
+// anyQuotaEnabled returns whether any tenant, or the default limits, has an ingestion quota.
+func (s *quotaStore) anyQuotaEnabled() bool {
+	for user := range s.limits.AllByUserID() {
+		if hasQuota(s.limits, user) {
+			return true
+		}
+	}
+	return hasQuota(s.limits, "fake")
+}
+
+func (s *quotaStore) updateAllVolumes(ctx context.Context) error {
+	if !s.anyQuotaEnabled() {
+		return nil
+	}
+
+	volumes, responded, err := s.getAllVolumes(ctx)
+	if err != nil {
+		level.Error(util_log.Logger).Log("msg", "error getting ingesters to update ingested volumes", "err", err)
+		s.refreshFailure.WithLabelValues("ring").Inc()
+		return nil // Don't fail the service because we have an error getting the ingesters once
+	}
+	if responded == 0 {
+		// Keep the last volumes rather than resetting them when no ingester responds.
+		return nil
+	}
+
+	s.updateVolumes(volumes, s.volumeScale(responded), time.Now())
+	return nil
+}
+
+// getAllVolumes returns the volumes of the tenants reported by all healthy ingesters, and the number
+// of ingesters that responded. Ingesters that fail to respond are skipped.
+func (s *quotaStore) getAllVolumes(ctx context.Context) ([]*logproto.TenantVolume, int, error) {
+	ingesters, err := s.ring.GetAllHealthy(ring.Read)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mtx       sync.Mutex
+		volumes   []*logproto.TenantVolume
+		responded int
+	)
+	for _, ing := range ingesters.Instances {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			resp, err := s.getVolumes(ctx, addr)
+			if err != nil {
+				level.Error(util_log.Logger).Log("msg", "unable to get ingested volumes from ingester", "ingester", addr, "err", err)
+				s.refreshFailure.WithLabelValues(addr).Inc()
+				return
+			}
+			mtx.Lock()
+			volumes = append(volumes, resp.TenantVolumes...)
+			responded++
+			mtx.Unlock()
+		}(ing.Addr)
+	}
+	wg.Wait()
+
+	return volumes, responded, nil
+}
+
+// volumeScale returns the factor that turns the sum of the volumes reported by the ingesters that
+// responded into an estimate of the volume ingested by the cluster. Each line is pushed to replication
+// factor ingesters, so the sum is divided by the replication factor. The volumes of the ingesters that
+// are unhealthy or failed to respond are missing from the sum, so it is extrapolated to all ingesters
+// of the ring, assuming that the streams are evenly spread across them.
+func (s *quotaStore) volumeScale(responded int) float64 {
+	rf, instances := s.ring.ReplicationFactor(), s.ring.InstancesCount()
+	if rf < 1 {
+		rf = 1
+	}
+	if instances < responded {
+		instances = responded
+	}
+	return float64(instances) / float64(responded*rf)
+}
+
+func (s *quotaStore) getVolumes(ctx context.Context, addr string) (*logproto.TenantVolumesResponse, error) {
+	client, err := s.clientPool.GetClientFor(addr)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.ingesterTimeout)
+	defer cancel()
+	return client.(logproto.TenantVolumesClient).GetTenantVolumes(ctx, &logproto.TenantVolumesRequest{})
+}
+
+// updateVolumes replaces the cluster volumes of the tenants with the sum of the volumes that the ingesters reported,
+// multiplied by the scale returned by volumeScale.
+func (s *quotaStore) updateVolumes(volumes []*logproto.TenantVolume, scale float64, now time.Time) {
+	day, month := quotaPeriods(now)
+	daily, monthly := map[string]int64{}, map[string]int64{}
+	for _, v := range volumes {
+		if v.Day == day {
+			daily[v.Tenant] += v.DayBytes
+		}
+		if v.Month == month {
+			monthly[v.Tenant] += v.MonthBytes
+		}
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for tenantID := range monthly {
+		if _, ok := s.tenants[tenantID]; !ok && hasQuota(s.limits, tenantID) {
+			s.tenants[tenantID] = &tenantQuotaUsage{}
+		}
+	}
+	for tenantID, usage := range s.tenants {
+		if !hasQuota(s.limits, tenantID) {
+			delete(s.tenants, tenantID)
+			s.usedBytes.DeleteLabelValues(tenantID, quotaPeriodDaily)
+			s.usedBytes.DeleteLabelValues(tenantID, quotaPeriodMonthly)
+			continue
+		}
+		usage.daily.reset(day)
+		usage.daily.cluster, usage.daily.local = int64(float64(daily[tenantID])*scale), 0
+		usage.monthly.reset(month)
+		usage.monthly.cluster, usage.monthly.local = int64(float64(monthly[tenantID])*scale), 0
+
+		s.usedBytes.WithLabelValues(tenantID, quotaPeriodDaily).Set(float64(usage.daily.used()))
+		s.usedBytes.WithLabelValues(tenantID, quotaPeriodMonthly).Set(float64(usage.monthly.used()))
+	}
+}
+
+// allow returns an error when pushing the bytes would exceed one of the ingestion quotas of the tenant,
+// and otherwise adds them to the usage of the tenant.
+func (s *quotaStore) allow(tenantID string, bytes int, now time.Time) error {
+	dailyLimit, monthlyLimit := s.limits.IngestionQuotaDaily(tenantID), s.limits.IngestionQuotaMonthly(tenantID)
+	if dailyLimit <= 0 && monthlyLimit <= 0 {
+		return nil
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	usage, ok := s.tenants[tenantID]
+	if !ok {
+		usage = &tenantQuotaUsage{}
+		s.tenants[tenantID] = usage
+	}
+	day, month := quotaPeriods(now)
+	usage.daily.reset(day)
+	usage.monthly.reset(month)
+
+	quotas := []struct {
+		period string
+		limit  int
+		usage  *quotaUsage
+	}{
+		{quotaPeriodDaily, dailyLimit, &usage.daily},
+		{quotaPeriodMonthly, monthlyLimit, &usage.monthly},
+	}
+	for _, q := range quotas {
+		if q.limit > 0 && q.usage.used()+int64(bytes) > int64(q.limit) {
+			return httpgrpc.Errorf(http.StatusTooManyRequests, validation.QuotaExceededErrorMsg, tenantID, q.period, q.limit, q.usage.used(), bytes)
+		}
+	}
+
+	ratio := s.limits.IngestionQuotaWarningRatio(tenantID)
+	for _, q := range quotas {
+		q.usage.local += int64(bytes)
+		if q.limit > 0 && ratio > 0 && !q.usage.warned && float64(q.usage.used()) >= ratio*float64(q.limit) {
+			q.usage.warned = true
+			s.warnings.WithLabelValues(tenantID, q.period).Inc()
+			level.Warn(util_log.Logger).Log("msg", "tenant exceeded the warning ratio of its ingestion quota", "tenant", tenantID, "period", q.period, "limit", q.limit, "used", q.usage.used())
+		}
+	}
+	return nil
+}
+
+// status returns the usage of the ingestion quotas of the tenant.
+func (s *quotaStore) status(tenantID string, now time.Time) QuotaStatus {
+	day, month := quotaPeriods(now)
+	ratio := s.limits.IngestionQuotaWarningRatio(tenantID)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	usage, ok := s.tenants[tenantID]
+	if !ok {
+		usage = &tenantQuotaUsage{}
+	}
+	usage.daily.reset(day)
+	usage.monthly.reset(month)
+
+	periodStatus := func(limit int, u quotaUsage, reset time.Time) *QuotaPeriodStatus {
+		if limit <= 0 {
+			return nil
+		}
+		status := &QuotaPeriodStatus{
+			Limit:    int64(limit),
+			Used:     u.used(),
+			Reset:    reset,
+			Warning:  ratio > 0 && float64(u.used()) >= ratio*float64(limit),
+			Exceeded: u.used() >= int64(limit),
+		}
+		if status.Remaining = status.Limit - status.Used; status.Remaining < 0 {
+			status.Remaining = 0
+		}
+		return status
+	}
+
+	dayStart, monthStart := time.Unix(day, 0).UTC(), time.Unix(month, 0).UTC()
+	return QuotaStatus{
+		Daily:   periodStatus(s.limits.IngestionQuotaDaily(tenantID), usage.daily, dayStart.AddDate(0, 0, 1)),
+		Monthly: periodStatus(s.limits.IngestionQuotaMonthly(tenantID), usage.monthly, monthStart.AddDate(0, 1, 0)),
+	}
+}
+
+// quotaPeriods returns the start of the current UTC day and month in unix seconds.
+func quotaPeriods(now time.Time) (int64, int64) {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Unix(),
+		time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Unix()
+}
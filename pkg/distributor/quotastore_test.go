@@ -0,0 +1,153 @@
+package distributor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/ring/client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
+	"google.golang.org/grpc"
+
+	client2 "github.com/grafana/loki/pkg/ingester/client"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+type fakeQuotaLimits struct {
+	Limits
+	daily, monthly int
+}
+
+func (l *fakeQuotaLimits) AllByUserID() map[string]*validation.Limits { return nil }
+func (l *fakeQuotaLimits) IngestionQuotaDaily(_ string) int           { return l.daily }
+func (l *fakeQuotaLimits) IngestionQuotaMonthly(_ string) int         { return l.monthly }
+func (l *fakeQuotaLimits) IngestionQuotaWarningRatio(_ string) float64 {
+	return 0.5
+}
+
+type fakeQuotaRing struct {
+	ring.ReadRing
+
+	replicationSet    ring.ReplicationSet
+	replicationFactor int
+	instances         int
+}
+
+func (r *fakeQuotaRing) GetAllHealthy(_ ring.Operation) (ring.ReplicationSet, error) {
+	return r.replicationSet, nil
+}
+
+func (r *fakeQuotaRing) ReplicationFactor() int { return r.replicationFactor }
+func (r *fakeQuotaRing) InstancesCount() int    { return r.instances }
+
+type fakeTenantVolumesClient struct {
+	resp *logproto.TenantVolumesResponse
+	err  error
+}
+
+func (c *fakeTenantVolumesClient) GetTenantVolumes(_ context.Context, _ *logproto.TenantVolumesRequest, _ ...grpc.CallOption) (*logproto.TenantVolumesResponse, error) {
+	return c.resp, c.err
+}
+
+func newVolumeClient(volumes ...*logproto.TenantVolume) client.PoolClient {
+	return client2.ClosableHealthAndIngesterClient{
+		TenantVolumesClient: &fakeTenantVolumesClient{resp: &logproto.TenantVolumesResponse{TenantVolumes: volumes}},
+	}
+}
+
+func TestQuotaStore(t *testing.T) {
+	now := time.Date(2023, time.May, 17, 10, 0, 0, 0, time.UTC)
+	day, month := quotaPeriods(now)
+	require.Equal(t, time.Date(2023, time.May, 17, 0, 0, 0, 0, time.UTC).Unix(), day)
+	require.Equal(t, time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC).Unix(), month)
+
+	r := &fakeQuotaRing{
+		replicationSet:    ring.ReplicationSet{Instances: []ring.InstanceDesc{{Addr: "ingester0"}, {Addr: "ingester1"}}},
+		replicationFactor: 2,
+		instances:         2,
+	}
+	cp := newFakeClientPool()
+	cp.clients = map[string]client.PoolClient{
+		"ingester0": newVolumeClient(
+			&logproto.TenantVolume{Tenant: "tenant", Day: day, DayBytes: 60, Month: month, MonthBytes: 300},
+		),
+		"ingester1": newVolumeClient(
+			&logproto.TenantVolume{Tenant: "tenant", Day: day, DayBytes: 60, Month: month, MonthBytes: 300},
+			// Volumes of past periods are ignored.
+			&logproto.TenantVolume{Tenant: "other", Day: day - 86400, DayBytes: 1000, Month: month, MonthBytes: 1000},
+		),
+	}
+	s := newQuotaStore(QuotaStoreConfig{IngesterReqTimeout: time.Second, UpdateInterval: time.Minute}, r, cp, &fakeQuotaLimits{daily: 100, monthly: 1000}, prometheus.NewPedanticRegistry())
+
+	// Without volumes from the ingesters, only the volume accepted by the distributor counts.
+	require.NoError(t, s.allow("tenant", 100, now))
+	require.Error(t, s.allow("tenant", 1, now))
+
+	volumes, responded, err := s.getAllVolumes(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, responded)
+	s.updateVolumes(volumes, s.volumeScale(responded), now)
+	require.Equal(t, QuotaStatus{
+		Daily:   &QuotaPeriodStatus{Limit: 100, Used: 60, Remaining: 40, Reset: time.Date(2023, time.May, 18, 0, 0, 0, 0, time.UTC), Warning: true},
+		Monthly: &QuotaPeriodStatus{Limit: 1000, Used: 300, Remaining: 700, Reset: time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)},
+	}, s.status("tenant", now))
+	require.Equal(t, int64(500), s.tenants["other"].monthly.used())
+	require.Equal(t, int64(0), s.tenants["other"].daily.used())
+
+	require.NoError(t, s.allow("tenant", 40, now))
+	err = s.allow("tenant", 1, now)
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusTooManyRequests, int(resp.Code))
+	require.True(t, s.status("tenant", now).Daily.Exceeded)
+
+	// The daily quota resets the next day, the monthly one keeps counting.
+	tomorrow := now.Add(24 * time.Hour)
+	require.NoError(t, s.allow("tenant", 1, tomorrow))
+	status := s.status("tenant", tomorrow)
+	require.Equal(t, int64(1), status.Daily.Used)
+	require.Equal(t, int64(341), status.Monthly.Used)
+}
+
+func TestQuotaStore_MissingIngester(t *testing.T) {
+	now := time.Date(2023, time.May, 17, 10, 0, 0, 0, time.UTC)
+	day, month := quotaPeriods(now)
+
+	// ingester2 fails to respond and ingester3 is unhealthy, so only half of the ingesters report their volumes.
+	r := &fakeQuotaRing{
+		replicationSet:    ring.ReplicationSet{Instances: []ring.InstanceDesc{{Addr: "ingester0"}, {Addr: "ingester1"}, {Addr: "ingester2"}}},
+		replicationFactor: 3,
+		instances:         4,
+	}
+	cp := newFakeClientPool()
+	cp.clients = map[string]client.PoolClient{
+		"ingester0": newVolumeClient(&logproto.TenantVolume{Tenant: "tenant", Day: day, DayBytes: 30, Month: month, MonthBytes: 300}),
+		"ingester1": newVolumeClient(&logproto.TenantVolume{Tenant: "tenant", Day: day, DayBytes: 30, Month: month, MonthBytes: 300}),
+		"ingester2": client2.ClosableHealthAndIngesterClient{TenantVolumesClient: &fakeTenantVolumesClient{err: errors.New("unavailable")}},
+	}
+	s := newQuotaStore(QuotaStoreConfig{IngesterReqTimeout: time.Second, UpdateInterval: time.Minute}, r, cp, &fakeQuotaLimits{daily: 100, monthly: 1000}, prometheus.NewPedanticRegistry())
+
+	volumes, responded, err := s.getAllVolumes(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, responded)
+	s.updateVolumes(volumes, s.volumeScale(responded), now)
+
+	// The volumes of the missing ingesters are extrapolated instead of being counted as zero.
+	status := s.status("tenant", now)
+	require.Equal(t, int64(40), status.Daily.Used)
+	require.Equal(t, int64(400), status.Monthly.Used)
+}
+
+func TestQuotaStore_Disabled(t *testing.T) {
+	s := newQuotaStore(QuotaStoreConfig{UpdateInterval: time.Minute}, &fakeQuotaRing{}, newFakeClientPool(), &fakeQuotaLimits{}, nil)
+	require.False(t, s.anyQuotaEnabled())
+	require.NoError(t, s.updateAllVolumes(context.Background()))
+	require.NoError(t, s.allow("tenant", 1<<30, time.Now()))
+	require.Equal(t, QuotaStatus{}, s.status("tenant", time.Now()))
+}
@@ -0,0 +1,87 @@
+package distributor
+
+import (
+	"crypto/sha256"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/grafana/dskit/runtimeconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v2"
+
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+// SplunkTokensConfig configures the file with the HEC tokens that authenticate requests to the Splunk HEC API.
+type SplunkTokensConfig struct {
+	File         string        `yaml:"file"`
+	ReloadPeriod time.Duration `yaml:"reload_period"`
+}
+
+// RegisterFlagsWithPrefix registers flags where every name is prefixed by
+// prefix. If prefix is a non-empty string, prefix should end with a period.
+func (cfg *SplunkTokensConfig) RegisterFlagsWithPrefix(prefix string, fs *flag.FlagSet) {
+	fs.StringVar(&cfg.File, prefix+"file", "", "YAML file with the HEC tokens of each tenant under 'tenants', which authenticate requests to the Splunk HEC API. Tokens must be unique across tenants. The file is meant to be mounted from a secret and is reloaded periodically. When authentication is enabled and no file is set, all requests to the Splunk HEC API are rejected.")
+	fs.DurationVar(&cfg.ReloadPeriod, prefix+"reload-period", 10*time.Second, "How often the HEC tokens file is reloaded.")
+}
+
+// splunkTokensFile is the content of the HEC tokens file.
+type splunkTokensFile struct {
+	Tenants map[string][]string `yaml:"tenants"`
+}
+
+// splunkTokens maps the SHA-256 hash of each HEC token to its tenant.
+// Only hashes are kept, so that looking up a token doesn't leak the configured tokens through timing.
+type splunkTokens map[[sha256.Size]byte]string
+
+func (t splunkTokens) tenant(token string) (string, bool) {
+	tenantID, ok := t[sha256.Sum256([]byte(token))]
+	return tenantID, ok
+}
+
+// loadSplunkTokens builds the tokens of the HEC tokens file. It implements runtimeconfig.Loader,
+// so a file with a token that is configured for several tenants is rejected and the previous tokens are kept.
+func loadSplunkTokens(r io.Reader) (interface{}, error) {
+	var file splunkTokensFile
+	decoder := yaml.NewDecoder(r)
+	decoder.SetStrict(true)
+	if err := decoder.Decode(&file); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	tenantIDs := make([]string, 0, len(file.Tenants))
+	for tenantID := range file.Tenants {
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	sort.Strings(tenantIDs)
+
+	tokens := splunkTokens{}
+	for _, tenantID := range tenantIDs {
+		for i, token := range file.Tenants[tenantID] {
+			if token == "" {
+				return nil, fmt.Errorf("HEC token %d of tenant %s is empty", i, tenantID)
+			}
+			key := sha256.Sum256([]byte(token))
+			if other, ok := tokens[key]; ok && other != tenantID {
+				return nil, fmt.Errorf("HEC token %d of tenant %s is already configured for tenant %s", i, tenantID, other)
+			}
+			tokens[key] = tenantID
+		}
+	}
+	return tokens, nil
+}
+
+// newSplunkTokensManager returns the manager that reloads the HEC tokens file, or nil if no file is configured.
+func newSplunkTokensManager(cfg SplunkTokensConfig, registerer prometheus.Registerer) (*runtimeconfig.Manager, error) {
+	if cfg.File == "" {
+		return nil, nil
+	}
+	return runtimeconfig.New(runtimeconfig.Config{
+		ReloadPeriod: cfg.ReloadPeriod,
+		LoadPath:     []string{cfg.File},
+		Loader:       loadSplunkTokens,
+	}, prometheus.WrapRegistererWithPrefix("loki_distributor_splunk_tokens_", registerer), util_log.Logger)
+}
@@ -6,10 +6,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/weaveworks/common/httpgrpc"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/validation"
 )
 
@@ -42,6 +44,12 @@ type validationContext struct {
 
 	incrementDuplicateTimestamps bool
 
+	allowStructuredMetadata   bool
+	maxStructuredMetadataSize int
+
+	cardinalityGuardMaxLabelValues int
+	cardinalityGuardAction         string
+
 	userID string
 }
 
@@ -57,6 +65,11 @@ func (v Validator) getValidationContextForTime(now time.Time, userID string) val
 		maxLabelNameLength:           v.MaxLabelNameLength(userID),
 		maxLabelValueLength:          v.MaxLabelValueLength(userID),
 		incrementDuplicateTimestamps: v.IncrementDuplicateTimestamps(userID),
+		allowStructuredMetadata:      v.AllowStructuredMetadata(userID),
+		maxStructuredMetadataSize:    v.MaxStructuredMetadataSize(userID),
+
+		cardinalityGuardMaxLabelValues: v.CardinalityGuardMaxLabelValues(userID),
+		cardinalityGuardAction:         v.CardinalityGuardAction(userID),
 	}
 }
 
@@ -91,9 +104,64 @@ func (v Validator) ValidateEntry(ctx validationContext, labels string, entry log
 		return httpgrpc.Errorf(http.StatusBadRequest, validation.LineTooLongErrorMsg, maxSize, labels, len(entry.Line))
 	}
 
+	if len(entry.StructuredMetadata) > 0 {
+		if !ctx.allowStructuredMetadata {
+			validation.DiscardedSamples.WithLabelValues(validation.DisallowedStructuredMetadata, ctx.userID).Inc()
+			validation.DiscardedBytes.WithLabelValues(validation.DisallowedStructuredMetadata, ctx.userID).Add(float64(len(entry.Line)))
+			return httpgrpc.Errorf(http.StatusBadRequest, validation.DisallowedStructuredMetadataErrorMsg, labels)
+		}
+
+		var size int
+		for _, l := range entry.StructuredMetadata {
+			// The structured metadata is exposed as labels in queries, so the names must be valid label names.
+			if !model.LabelName(l.Name).IsValid() {
+				validation.DiscardedSamples.WithLabelValues(validation.InvalidStructuredMetadata, ctx.userID).Inc()
+				validation.DiscardedBytes.WithLabelValues(validation.InvalidStructuredMetadata, ctx.userID).Add(float64(len(entry.Line)))
+				return httpgrpc.Errorf(http.StatusBadRequest, validation.InvalidStructuredMetadataErrorMsg, labels, l.Name)
+			}
+			size += len(l.Name) + len(l.Value)
+		}
+		if maxSize := ctx.maxStructuredMetadataSize; maxSize != 0 && size > maxSize {
+			validation.DiscardedSamples.WithLabelValues(validation.StructuredMetadataTooLarge, ctx.userID).Inc()
+			validation.DiscardedBytes.WithLabelValues(validation.StructuredMetadataTooLarge, ctx.userID).Add(float64(len(entry.Line)))
+			return httpgrpc.Errorf(http.StatusBadRequest, validation.StructuredMetadataTooLargeErrorMsg, labels, size, maxSize)
+		}
+	}
+
 	return nil
 }
 
+// ValidateBackfillStream validates a stream of the tenant that is written directly to storage with the limits that
+// apply to the streams pushed to the distributor, except that entries are not rejected for their age. Long lines are
+// truncated when the tenant truncates them. It returns the labels and the valid entries of the stream, and the error of
+// the last invalid entry.
+func (v Validator) ValidateBackfillStream(now time.Time, userID string, stream logproto.Stream) (labels.Labels, []logproto.Entry, error) {
+	ctx := v.getValidationContextForTime(now, userID)
+	ctx.rejectOldSample = false
+
+	ls, err := syntax.ParseLabels(stream.Labels)
+	if err != nil {
+		updateMetrics(validation.InvalidLabels, userID, stream)
+		return nil, nil, httpgrpc.Errorf(http.StatusBadRequest, validation.InvalidLabelsErrorMsg, stream.Labels, err)
+	}
+	if err := v.ValidateLabels(ctx, ls, stream); err != nil {
+		return nil, nil, err
+	}
+
+	truncateLines(ctx, &stream)
+
+	var lastErr error
+	entries := make([]logproto.Entry, 0, len(stream.Entries))
+	for _, entry := range stream.Entries {
+		if err := v.ValidateEntry(ctx, stream.Labels, entry); err != nil {
+			lastErr = err
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return ls, entries, lastErr
+}
+
 // Validate labels returns an error if the labels are invalid
 func (v Validator) ValidateLabels(ctx validationContext, ls labels.Labels, stream logproto.Stream) error {
 	if len(ls) == 0 {
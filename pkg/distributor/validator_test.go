@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/push"
 	"github.com/grafana/loki/pkg/validation"
 )
 
@@ -85,6 +86,47 @@ func TestValidator_ValidateEntry(t *testing.T) {
 			logproto.Entry{Timestamp: testTime, Line: "12345678901"},
 			httpgrpc.Errorf(http.StatusBadRequest, validation.LineTooLongErrorMsg, 10, testStreamLabels, 11),
 		},
+		{
+			"disallowed structured metadata",
+			"test",
+			nil,
+			logproto.Entry{Timestamp: testTime, Line: "test", StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "abc"}}},
+			httpgrpc.Errorf(http.StatusBadRequest, validation.DisallowedStructuredMetadataErrorMsg, testStreamLabels),
+		},
+		{
+			"valid structured metadata",
+			"test",
+			fakeLimits{
+				&validation.Limits{
+					AllowStructuredMetadata: true,
+				},
+			},
+			logproto.Entry{Timestamp: testTime, Line: "test", StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "abc"}}},
+			nil,
+		},
+		{
+			"invalid structured metadata name",
+			"test",
+			fakeLimits{
+				&validation.Limits{
+					AllowStructuredMetadata: true,
+				},
+			},
+			logproto.Entry{Timestamp: testTime, Line: "test", StructuredMetadata: push.LabelsAdapter{{Name: "trace.id", Value: "abc"}}},
+			httpgrpc.Errorf(http.StatusBadRequest, validation.InvalidStructuredMetadataErrorMsg, testStreamLabels, "trace.id"),
+		},
+		{
+			"structured metadata too large",
+			"test",
+			fakeLimits{
+				&validation.Limits{
+					AllowStructuredMetadata:   true,
+					MaxStructuredMetadataSize: 10,
+				},
+			},
+			logproto.Entry{Timestamp: testTime, Line: "test", StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "abc"}}},
+			httpgrpc.Errorf(http.StatusBadRequest, validation.StructuredMetadataTooLargeErrorMsg, testStreamLabels, 11, 10),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -209,3 +251,62 @@ func mustParseLabels(s string) labels.Labels {
 	}
 	return ls
 }
+
+func TestValidator_ValidateBackfillStream(t *testing.T) {
+	l := &validation.Limits{}
+	flagext.DefaultValues(l)
+	l.RejectOldSamples = true
+	l.RejectOldSamplesMaxAge = model.Duration(time.Hour)
+	l.MaxLineSize = 10
+	o, err := validation.NewOverrides(*l, nil)
+	assert.NoError(t, err)
+	v, err := NewValidator(o)
+	assert.NoError(t, err)
+
+	t.Run("old entries are accepted and invalid entries are dropped", func(t *testing.T) {
+		stream := logproto.Stream{
+			Labels: `{foo="bar"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: testTime.Add(-24 * time.Hour), Line: "old"},
+				{Timestamp: testTime.Add(-time.Minute), Line: "line too long"},
+				{Timestamp: testTime.Add(5 * time.Hour), Line: "new"},
+			},
+		}
+		ls, entries, err := v.ValidateBackfillStream(testTime, "test", stream)
+		assert.Equal(t, mustParseLabels(`{foo="bar"}`), ls)
+		assert.Equal(t, stream.Entries[:1], entries)
+		assert.Equal(t, httpgrpc.Errorf(http.StatusBadRequest, validation.TooFarInFutureErrorMsg, stream.Labels, testTime.Add(5*time.Hour).Format(timeFormat)), err)
+	})
+
+	t.Run("invalid labels drop the stream", func(t *testing.T) {
+		_, entries, err := v.ValidateBackfillStream(testTime, "test", logproto.Stream{
+			Labels:  `{foo=`,
+			Entries: []logproto.Entry{{Timestamp: testTime, Line: "line"}},
+		})
+		assert.Empty(t, entries)
+		assert.Error(t, err)
+
+		_, entries, err = v.ValidateBackfillStream(testTime, "test", logproto.Stream{
+			Labels:  `{}`,
+			Entries: []logproto.Entry{{Timestamp: testTime, Line: "line"}},
+		})
+		assert.Empty(t, entries)
+		assert.Equal(t, httpgrpc.Errorf(http.StatusBadRequest, validation.MissingLabelsErrorMsg), err)
+	})
+
+	t.Run("long lines are truncated when the tenant truncates them", func(t *testing.T) {
+		l := *l
+		l.MaxLineSizeTruncate = true
+		o, err := validation.NewOverrides(l, nil)
+		assert.NoError(t, err)
+		v, err := NewValidator(o)
+		assert.NoError(t, err)
+
+		_, entries, err := v.ValidateBackfillStream(testTime, "test", logproto.Stream{
+			Labels:  `{foo="bar"}`,
+			Entries: []logproto.Entry{{Timestamp: testTime, Line: "line too long"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []logproto.Entry{{Timestamp: testTime, Line: "line too l"}}, entries)
+	})
+}
@@ -0,0 +1,180 @@
+package aggregations
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/logqlmodel"
+)
+
+// Sample is the value of an aggregation over an interval. Its labels are the labels of its materialised stream.
+type Sample struct {
+	Labels    labels.Labels
+	Timestamp time.Time
+	Value     float64
+}
+
+// Accumulator accumulates the values that the aggregations of a tenant sample from the entries of one stream, in
+// intervals that end at multiples of the interval. It is not safe for concurrent use.
+type Accumulator struct {
+	labels     labels.Labels
+	streamHash string
+	interval   time.Duration
+	aggs       []*streamAggregation
+
+	// The end of the latest interval that was flushed. Entries in this or earlier intervals are dropped.
+	flushedThrough time.Time
+}
+
+type streamAggregation struct {
+	agg       *Aggregation
+	extractor log.StreamSampleExtractor
+	// Samples by the end of their interval and their labels.
+	samples map[int64]map[string]*Sample
+}
+
+// NewAccumulator returns an accumulator for the aggregations whose selector matches the labels of the stream, or nil
+// when none does. Materialised streams are never aggregated.
+func NewAccumulator(ls labels.Labels, interval time.Duration, aggs []*Aggregation) *Accumulator {
+	a := &Accumulator{
+		labels:     ls,
+		streamHash: strconv.FormatUint(ls.Hash(), 16),
+		interval:   interval,
+	}
+	return a.Update(aggs)
+}
+
+// Update returns the accumulator for the new aggregations of the tenant. It keeps the values accumulated for the
+// aggregations that didn't change, and returns nil when no aggregation matches the stream anymore.
+func (a *Accumulator) Update(aggs []*Aggregation) *Accumulator {
+	if a.labels.Has(NameLabel) || a.labels.Has(CoverageLabel) {
+		return nil
+	}
+
+	var updated []*streamAggregation
+	for _, agg := range aggs {
+		if agg.expr == nil || !matches(agg.matchers, a.labels) {
+			continue
+		}
+		if prev := a.aggregation(agg); prev != nil {
+			prev.agg = agg
+			updated = append(updated, prev)
+			continue
+		}
+		// Extractors cache state per stream and are not safe for concurrent use, so each stream gets its own.
+		extractor, err := agg.expr.Extractor()
+		if err != nil {
+			continue
+		}
+		updated = append(updated, &streamAggregation{
+			agg:       agg,
+			extractor: extractor.ForStream(a.labels),
+			samples:   map[int64]map[string]*Sample{},
+		})
+	}
+	if len(updated) == 0 {
+		return nil
+	}
+	a.aggs = updated
+	return a
+}
+
+func (a *Accumulator) aggregation(agg *Aggregation) *streamAggregation {
+	for _, sa := range a.aggs {
+		if sa.agg.Name == agg.Name && sa.agg.query == agg.query {
+			return sa
+		}
+	}
+	return nil
+}
+
+func matches(matchers []*labels.Matcher, ls labels.Labels) bool {
+	for _, m := range matchers {
+		if !m.Matches(ls.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Add samples the entries with the aggregations, and returns the number of entries dropped because their interval
+// was already flushed.
+func (a *Accumulator) Add(entries []logproto.Entry) int {
+	var late int
+	for _, e := range entries {
+		end := a.intervalEnd(e.Timestamp)
+		if !end.After(a.flushedThrough) {
+			late++
+			continue
+		}
+		for _, sa := range a.aggs {
+			value, lbs, ok := sa.extractor.ProcessString(e.Timestamp.UnixNano(), e.Line, logproto.FromStructuredMetadataToLabels(e.StructuredMetadata)...)
+			// Queries fail on samples with errors, so they can't be answered from them either.
+			if !ok || lbs.Labels().Has(logqlmodel.ErrorLabel) {
+				continue
+			}
+			sa.add(end, lbs, value, a.streamHash)
+		}
+	}
+	return late
+}
+
+// intervalEnd returns the end of the interval of the timestamp. Like the ranges of queries, intervals include their
+// end but not their start.
+func (a *Accumulator) intervalEnd(ts time.Time) time.Time {
+	end := ts.Truncate(a.interval)
+	if end.Before(ts) {
+		end = end.Add(a.interval)
+	}
+	return end
+}
+
+func (sa *streamAggregation) add(end time.Time, lbs log.LabelsResult, value float64, streamHash string) {
+	samples, ok := sa.samples[end.UnixNano()]
+	if !ok {
+		samples = map[string]*Sample{}
+		sa.samples[end.UnixNano()] = samples
+	}
+	s, ok := samples[lbs.String()]
+	if !ok {
+		b := labels.NewBuilder(lbs.Labels())
+		b.Set(NameLabel, sa.agg.Name)
+		b.Set(StreamLabel, streamHash)
+		s = &Sample{Labels: b.Labels(nil), Timestamp: end}
+		samples[lbs.String()] = s
+	}
+	s.Value += value
+}
+
+// Flush returns the samples of the intervals that end before or at until, ordered by time, and forgets them.
+func (a *Accumulator) Flush(until time.Time) []Sample {
+	var flushed []Sample
+	for _, sa := range a.aggs {
+		for end, samples := range sa.samples {
+			if time.Unix(0, end).After(until) {
+				continue
+			}
+			for _, s := range samples {
+				flushed = append(flushed, *s)
+			}
+			delete(sa.samples, end)
+		}
+	}
+	if end := until.Truncate(a.interval); end.After(a.flushedThrough) {
+		a.flushedThrough = end
+	}
+	sort.Slice(flushed, func(i, j int) bool {
+		return flushed[i].Timestamp.Before(flushed[j].Timestamp)
+	})
+	return flushed
+}
+
+// FormatValue formats the value of a sample as the line of its materialised entry.
+func FormatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,170 @@
+// Package aggregations implements continuous aggregations: metric queries of a tenant that the ingesters evaluate
+// on the entries they receive. Their results are written as streams of the tenant, which the query frontend reads
+// instead of the raw logs when a query contains the query of an aggregation.
+package aggregations
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+const (
+	// NameLabel is the label holding the name of the aggregation of a materialised stream.
+	NameLabel = "__aggregation__"
+	// StreamLabel is the label holding the hash of the labels of the stream that a materialised stream aggregates.
+	StreamLabel = "__aggregation_stream__"
+	// ReplicaLabel is the label holding the ID of the ingester that wrote a materialised stream. The replicas of a
+	// stream can receive different entries, so each writes streams of its own, and the rewritten queries keep the
+	// largest value of the replicas of each aggregated stream.
+	ReplicaLabel = "__aggregation_replica__"
+	// CoverageLabel is the label of the streams that record the intervals an aggregation was written for, holding
+	// the name of the aggregation. All ingesters write the same entries to them, which are deduplicated at query
+	// time, so an interval is missing only when no ingester wrote it.
+	CoverageLabel = "__aggregation_coverage__"
+
+	// valueLabel is the label that the rewritten queries extract the value of a materialised entry to.
+	valueLabel = "__aggregation_value__"
+)
+
+// Aggregation is a continuous aggregation of a tenant.
+type Aggregation struct {
+	// Name identifies the aggregation in the labels of its materialised streams.
+	Name string `yaml:"name" json:"name"`
+	// Query is the metric query that is evaluated continuously.
+	Query string `yaml:"query" json:"query"`
+	// From is the time from which the aggregation is materialised. Queries are only answered from the aggregation
+	// when their range starts after it.
+	From flagext.Time `yaml:"from,omitempty" json:"from,omitempty"`
+
+	expr          *syntax.VectorAggregationExpr
+	matchers      []*labels.Matcher
+	rangeInterval time.Duration
+	query         string
+	rewritten     string
+}
+
+// Validate validates the aggregations and parses their queries.
+func Validate(aggs []*Aggregation) error {
+	names := map[string]struct{}{}
+	for _, a := range aggs {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("invalid continuous aggregation %q: %w", a.Name, err)
+		}
+		if _, ok := names[a.Name]; ok {
+			return fmt.Errorf("duplicate continuous aggregation name %q", a.Name)
+		}
+		names[a.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (a *Aggregation) validate() error {
+	if a.Name == "" || !model.LabelValue(a.Name).IsValid() {
+		return errors.New("a valid name is required")
+	}
+
+	expr, err := syntax.ParseSampleExpr(a.Query)
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	vec, ok := expr.(*syntax.VectorAggregationExpr)
+	if !ok || vec.Operation != syntax.OpTypeSum {
+		return errors.New("the query must be a sum of a range aggregation")
+	}
+	rng, ok := vec.Left.(*syntax.RangeAggregationExpr)
+	if !ok || rng.Grouping != nil {
+		return errors.New("the query must be a sum of a range aggregation without grouping")
+	}
+	switch rng.Operation {
+	case syntax.OpRangeTypeCount, syntax.OpRangeTypeRate, syntax.OpRangeTypeBytes, syntax.OpRangeTypeBytesRate, syntax.OpRangeTypeSum:
+	default:
+		return fmt.Errorf("unsupported range aggregation %s, expected one of count_over_time, rate, bytes_over_time, bytes_rate or sum_over_time", rng.Operation)
+	}
+	if rng.Left.Offset != 0 {
+		return errors.New("the query must not have an offset")
+	}
+	if _, err := vec.Extractor(); err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+
+	a.expr = vec
+	a.matchers = rng.Left.Left.Matchers()
+	a.rangeInterval = rng.Left.Interval
+	a.query = vec.String()
+
+	rewritten, err := syntax.ParseSampleExpr(a.rewrite(rng.Operation))
+	if err != nil {
+		return fmt.Errorf("failed to rewrite query: %w", err)
+	}
+	a.rewritten = rewritten.String()
+	return nil
+}
+
+// rewrite returns the query that computes the aggregation from its materialised streams. The streams hold the sum
+// of the sampled values of each interval, so the sum over the range of the query is the result of the range
+// aggregation, divided by the range for rates. Replicas that missed entries have smaller sums, so the largest sum of
+// the replicas of each aggregated stream is used.
+func (a *Aggregation) rewrite(rangeOp string) string {
+	sum := "sum"
+	if g := a.expr.Grouping; g != nil && g.Without {
+		sum += fmt.Sprintf(" without (%s)", strings.Join(append(append([]string{}, g.Groups...), NameLabel, StreamLabel), ","))
+	} else if g != nil && len(g.Groups) > 0 {
+		sum += fmt.Sprintf(" by (%s)", strings.Join(g.Groups, ","))
+	}
+
+	q := fmt.Sprintf(`%s (max without (%s) (sum_over_time({%s=%q} | regexp "(?P<%s>.+)" | unwrap %s [%s])))`, sum, ReplicaLabel, NameLabel, a.Name, valueLabel, valueLabel, model.Duration(a.rangeInterval))
+	if rangeOp == syntax.OpRangeTypeRate || rangeOp == syntax.OpRangeTypeBytesRate {
+		q += fmt.Sprintf(" / %v", a.rangeInterval.Seconds())
+	}
+	return q
+}
+
+// CoverageQuery returns the query that counts the intervals in the range of the aggregation that were written.
+func (a *Aggregation) CoverageQuery() string {
+	return fmt.Sprintf(`count_over_time({%s=%q}[%s])`, CoverageLabel, a.Name, model.Duration(a.rangeInterval))
+}
+
+// Intervals returns the number of intervals that the range of the aggregation contains at least, wherever it ends.
+func (a *Aggregation) Intervals(interval time.Duration) int {
+	return int(a.rangeInterval / interval)
+}
+
+// Rewrite replaces the parts of the query that are the query of one of the aggregations with the query that reads
+// the materialised streams of the aggregation. Aggregations are only used when they were materialised from before
+// the start of the query minus their range. It returns the aggregations that were used, and false when none was.
+func Rewrite(aggs []*Aggregation, query string, start time.Time) (string, []*Aggregation, bool) {
+	if len(aggs) == 0 {
+		return query, nil, false
+	}
+	expr, err := syntax.ParseSampleExpr(query)
+	if err != nil {
+		return query, nil, false
+	}
+
+	// The string of an expression contains the strings of its sub-expressions.
+	rewritten := expr.String()
+	var used []*Aggregation
+	for _, a := range aggs {
+		if a.expr == nil || start.Add(-a.rangeInterval).Before(time.Time(a.From)) || !strings.Contains(rewritten, a.query) {
+			continue
+		}
+		rewritten = strings.ReplaceAll(rewritten, a.query, a.rewritten)
+		used = append(used, a)
+	}
+	if len(used) == 0 {
+		return query, nil, false
+	}
+
+	if _, err := syntax.ParseSampleExpr(rewritten); err != nil {
+		return query, nil, false
+	}
+	return rewritten, used, true
+}
@@ -0,0 +1,183 @@
+package aggregations
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		aggs []*Aggregation
+		err  string
+	}{
+		{name: "empty"},
+		{name: "rate", aggs: []*Aggregation{{Name: "nginx", Query: `sum by (status) (rate({app="nginx"} | logfmt [1m]))`}}},
+		{name: "sum over time", aggs: []*Aggregation{{Name: "latency", Query: `sum without (pod) (sum_over_time({app="nginx"} | logfmt | unwrap latency [5m]))`}}},
+		{name: "no name", aggs: []*Aggregation{{Query: `sum(rate({app="nginx"}[1m]))`}}, err: "a valid name is required"},
+		{name: "invalid query", aggs: []*Aggregation{{Name: "a", Query: `sum(rate({app="nginx"}`}}, err: "invalid query"},
+		{name: "not a sum", aggs: []*Aggregation{{Name: "a", Query: `max(rate({app="nginx"}[1m]))`}}, err: "must be a sum"},
+		{name: "unsupported range aggregation", aggs: []*Aggregation{{Name: "a", Query: `sum(max_over_time({app="nginx"} | logfmt | unwrap latency [1m]))`}}, err: "unsupported range aggregation"},
+		{name: "offset", aggs: []*Aggregation{{Name: "a", Query: `sum(rate({app="nginx"}[1m] offset 1h))`}}, err: "offset"},
+		{name: "duplicate", aggs: []*Aggregation{
+			{Name: "a", Query: `sum(rate({app="nginx"}[1m]))`},
+			{Name: "a", Query: `sum(rate({app="api"}[1m]))`},
+		}, err: "duplicate"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.aggs)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRewrite(t *testing.T) {
+	aggs := []*Aggregation{
+		{Name: "nginx", Query: `sum by (status) (rate({app="nginx"} | logfmt [1m]))`},
+		{Name: "api", Query: `sum(bytes_over_time({app="api"}[5m]))`, From: flagext.Time(time.Unix(3600, 0))},
+		{Name: "pods", Query: `sum without (pod) (count_over_time({app="api"}[1m]))`},
+	}
+	require.NoError(t, Validate(aggs))
+
+	start := time.Unix(7200, 0)
+	for _, tc := range []struct {
+		query    string
+		start    time.Time
+		expected string
+		used     []string
+	}{
+		{
+			query:    `sum by(status)(rate({app="nginx"}|logfmt[1m]))`,
+			expected: `(sum by (status)(max without (__aggregation_replica__)(sum_over_time({__aggregation__="nginx"} | regexp "(?P<__aggregation_value__>.+)" | unwrap __aggregation_value__[1m]))) / 60)`,
+			used:     []string{"nginx"},
+		},
+		{
+			query:    `sum by (status) (rate({app="nginx"} | logfmt [1m])) > 10`,
+			expected: `((sum by (status)(max without (__aggregation_replica__)(sum_over_time({__aggregation__="nginx"} | regexp "(?P<__aggregation_value__>.+)" | unwrap __aggregation_value__[1m]))) / 60) > 10)`,
+			used:     []string{"nginx"},
+		},
+		{
+			query:    `sum(bytes_over_time({app="api"}[5m]))`,
+			expected: `sum(max without (__aggregation_replica__)(sum_over_time({__aggregation__="api"} | regexp "(?P<__aggregation_value__>.+)" | unwrap __aggregation_value__[5m])))`,
+			used:     []string{"api"},
+		},
+		{
+			query:    `sum without (pod) (count_over_time({app="api"}[1m]))`,
+			expected: `sum without (pod,__aggregation__,__aggregation_stream__)(max without (__aggregation_replica__)(sum_over_time({__aggregation__="pods"} | regexp "(?P<__aggregation_value__>.+)" | unwrap __aggregation_value__[1m])))`,
+			used:     []string{"pods"},
+		},
+		// Before the aggregation is materialised.
+		{query: `sum(bytes_over_time({app="api"}[5m]))`, start: time.Unix(3600, 0)},
+		// Different queries.
+		{query: `sum by (status) (rate({app="nginx"} | logfmt [5m]))`},
+		{query: `sum by (status) (rate({app="nginx"} | json [1m]))`},
+		{query: `{app="nginx"}`},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			if tc.start.IsZero() {
+				tc.start = start
+			}
+			rewritten, used, ok := Rewrite(aggs, tc.query, tc.start)
+			if tc.expected == "" {
+				require.False(t, ok)
+				require.Equal(t, tc.query, rewritten)
+				require.Empty(t, used)
+				return
+			}
+			require.True(t, ok)
+			require.Equal(t, tc.expected, rewritten)
+			var names []string
+			for _, a := range used {
+				names = append(names, a.Name)
+			}
+			require.Equal(t, tc.used, names)
+		})
+	}
+}
+
+func TestAccumulator(t *testing.T) {
+	aggs := []*Aggregation{
+		{Name: "status", Query: `sum by (status) (count_over_time({app="nginx"} | logfmt [1m]))`},
+		{Name: "bytes", Query: `sum(bytes_over_time({app="nginx"}[1m]))`},
+		{Name: "api", Query: `sum(count_over_time({app="api"}[1m]))`},
+	}
+	require.NoError(t, Validate(aggs))
+
+	require.Nil(t, NewAccumulator(labels.FromStrings("app", "other"), 10*time.Second, aggs))
+	require.Nil(t, NewAccumulator(labels.FromStrings("app", "nginx", NameLabel, "status"), 10*time.Second, aggs), "expected materialised streams not to be aggregated")
+	require.Nil(t, NewAccumulator(labels.FromStrings("app", "nginx", CoverageLabel, "status"), 10*time.Second, aggs), "expected coverage streams not to be aggregated")
+
+	ls := labels.FromStrings("app", "nginx")
+	a := NewAccumulator(ls, 10*time.Second, aggs)
+	require.NotNil(t, a)
+	require.Len(t, a.aggs, 2)
+
+	late := a.Add([]logproto.Entry{
+		{Timestamp: time.Unix(1, 0), Line: "status=200"},
+		{Timestamp: time.Unix(10, 0), Line: "status=500"},
+		{Timestamp: time.Unix(11, 0), Line: "status=200"},
+		{Timestamp: time.Unix(12, 0), Line: "status=200"},
+	})
+	require.Equal(t, 0, late)
+
+	stream := StreamLabel
+	hash := a.streamHash
+	require.Equal(t, []Sample{
+		{Labels: labels.FromStrings(NameLabel, "bytes", stream, hash), Timestamp: time.Unix(10, 0), Value: 20},
+		{Labels: labels.FromStrings(NameLabel, "status", stream, hash, "status", "200"), Timestamp: time.Unix(10, 0), Value: 1},
+		{Labels: labels.FromStrings(NameLabel, "status", stream, hash, "status", "500"), Timestamp: time.Unix(10, 0), Value: 1},
+	}, sortSamples(a.Flush(time.Unix(15, 0))))
+
+	// Entries of flushed intervals are dropped.
+	late = a.Add([]logproto.Entry{
+		{Timestamp: time.Unix(5, 0), Line: "status=200"},
+		{Timestamp: time.Unix(20, 0), Line: "status=200"},
+	})
+	require.Equal(t, 1, late)
+
+	require.Equal(t, []Sample{
+		{Labels: labels.FromStrings(NameLabel, "bytes", stream, hash), Timestamp: time.Unix(20, 0), Value: 30},
+		{Labels: labels.FromStrings(NameLabel, "status", stream, hash, "status", "200"), Timestamp: time.Unix(20, 0), Value: 3},
+	}, sortSamples(a.Flush(time.Unix(25, 0))))
+
+	// Updates keep the values of the aggregations that didn't change.
+	a.Add([]logproto.Entry{{Timestamp: time.Unix(21, 0), Line: "status=200"}})
+	updated := []*Aggregation{
+		{Name: "status", Query: `sum by (status) (count_over_time({app="nginx"} | logfmt [1m]))`},
+		{Name: "bytes", Query: `sum(bytes_over_time({app="nginx"} |= "500" [1m]))`},
+	}
+	require.NoError(t, Validate(updated))
+	a = a.Update(updated)
+	require.Equal(t, []Sample{
+		{Labels: labels.FromStrings(NameLabel, "status", stream, hash, "status", "200"), Timestamp: time.Unix(30, 0), Value: 1},
+	}, a.Flush(time.Unix(30, 0)))
+
+	require.Nil(t, a.Update(nil))
+}
+
+func sortSamples(samples []Sample) []Sample {
+	sort.Slice(samples, func(i, j int) bool {
+		return labels.Compare(samples[i].Labels, samples[j].Labels) < 0
+	})
+	return samples
+}
+
+func TestCoverageQuery(t *testing.T) {
+	aggs := []*Aggregation{{Name: "nginx", Query: `sum(rate({app="nginx"}[1m]))`}}
+	require.NoError(t, Validate(aggs))
+
+	require.Equal(t, `count_over_time({__aggregation_coverage__="nginx"}[1m])`, aggs[0].CoverageQuery())
+	require.Equal(t, 4, aggs[0].Intervals(15*time.Second))
+	require.Equal(t, 2, aggs[0].Intervals(25*time.Second))
+}
@@ -0,0 +1,153 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/push"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	loki_util "github.com/grafana/loki/pkg/util"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+// backfillQueueLength is how many pushed streams can wait to be backfilled before pushes with entries to backfill
+// are rejected.
+const backfillQueueLength = 1024
+
+// backfiller writes the entries of streams that are older than the out-of-order window of their tenant directly to
+// storage, without keeping them in memory.
+type backfiller interface {
+	// enqueue queues the entries of the stream to be written to storage. It fails when the queue is full.
+	enqueue(tenant string, s *stream, entries []logproto.Entry) error
+}
+
+type backfillRequest struct {
+	tenant  string
+	stream  *stream
+	entries []logproto.Entry
+}
+
+// backfillQueue holds the entries that the backfill loop of the ingester writes to storage, so that pushes don't
+// wait for the store.
+type backfillQueue struct {
+	mtx      sync.Mutex
+	stopped  bool
+	requests chan backfillRequest
+	done     sync.WaitGroup
+}
+
+func newBackfillQueue() *backfillQueue {
+	return &backfillQueue{requests: make(chan backfillRequest, backfillQueueLength)}
+}
+
+func (q *backfillQueue) push(req backfillRequest) error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if q.stopped {
+		return ErrReadOnly
+	}
+	select {
+	case q.requests <- req:
+		return nil
+	default:
+		return httpgrpc.Errorf(http.StatusTooManyRequests, "too many entries waiting to be backfilled for user '%s', retry later", req.tenant)
+	}
+}
+
+// stop waits until the queued entries were written. Entries can't be queued anymore.
+func (q *backfillQueue) stop() {
+	q.mtx.Lock()
+	if !q.stopped {
+		q.stopped = true
+		close(q.requests)
+	}
+	q.mtx.Unlock()
+	q.done.Wait()
+}
+
+func (i *Ingester) enqueue(tenant string, s *stream, entries []logproto.Entry) error {
+	// The entries outlive the push request, whose buffers may be reused.
+	cloned := make([]logproto.Entry, len(entries))
+	for j, e := range entries {
+		cloned[j] = logproto.Entry{Timestamp: e.Timestamp, Line: strings.Clone(e.Line)}
+		for _, l := range e.StructuredMetadata {
+			cloned[j].StructuredMetadata = append(cloned[j].StructuredMetadata, push.LabelAdapter{Name: strings.Clone(l.Name), Value: strings.Clone(l.Value)})
+		}
+	}
+	return i.backfillQueue.push(backfillRequest{tenant: tenant, stream: s, entries: cloned})
+}
+
+func (i *Ingester) backfillLoop() {
+	defer i.backfillQueue.done.Done()
+
+	for req := range i.backfillQueue.requests {
+		ctx, cancel := context.WithTimeout(user.InjectOrgID(context.Background(), req.tenant), i.cfg.FlushOpTimeout)
+		if err := i.backfill(ctx, req); err != nil {
+			i.metrics.backfillFailedEntries.WithLabelValues(req.tenant).Add(float64(len(req.entries)))
+			level.Error(util_log.Logger).Log("msg", "failed to backfill entries", "tenant", req.tenant, "stream", req.stream.labelsString, "entries", len(req.entries), "err", err)
+		}
+		cancel()
+	}
+}
+
+// backfill cuts the entries of the stream into chunks of their own and writes them to the store. Every replica of a
+// stream receives the same entries and writes the same chunks, which the store deduplicates.
+func (i *Ingester) backfill(ctx context.Context, req backfillRequest) error {
+	entries := req.entries
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	labelsBuilder := labels.NewBuilder(req.stream.labels)
+	labelsBuilder.Set(nameLabel, logsValue)
+	metric := labelsBuilder.Labels(nil)
+
+	var chunks []*chunkenc.MemChunk
+	for j := range entries {
+		if len(chunks) == 0 || !chunks[len(chunks)-1].SpaceFor(&entries[j]) {
+			chunks = append(chunks, chunkenc.NewMemChunk(i.cfg.parsedEncoding, headBlockType(true, req.stream.structuredMetadata), i.cfg.BlockSize, i.cfg.TargetChunkSize))
+		}
+		if err := chunks[len(chunks)-1].Append(&entries[j]); err != nil {
+			return fmt.Errorf("backfill append: %w", err)
+		}
+	}
+
+	for _, c := range chunks {
+		if err := c.Close(); err != nil {
+			return fmt.Errorf("backfill chunk close: %w", err)
+		}
+		firstTime, lastTime := loki_util.RoundToMilliseconds(c.Bounds())
+		ch := chunk.NewChunk(
+			req.tenant, req.stream.fp, metric,
+			chunkenc.NewFacade(c, i.cfg.BlockSize, i.cfg.TargetChunkSize),
+			firstTime,
+			lastTime,
+		)
+		if err := i.encodeChunk(ctx, &ch, &chunkDesc{chunk: c}); err != nil {
+			return err
+		}
+		if err := i.flushChunk(ctx, &ch); err != nil {
+			return err
+		}
+	}
+
+	var bytes int
+	for _, e := range entries {
+		bytes += len(e.Line)
+	}
+	i.metrics.backfilledEntries.WithLabelValues(req.tenant).Add(float64(len(entries)))
+	i.metrics.backfilledBytes.WithLabelValues(req.tenant).Add(float64(bytes))
+	return nil
+}
@@ -0,0 +1,114 @@
+package ingester
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/ingester/client"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/runtime"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func TestIngesterBackfill(t *testing.T) {
+	l := defaultLimitsTestConfig()
+	l.OutOfOrderWindow = model.Duration(time.Minute)
+	l.OutOfOrderAction = validation.OutOfOrderBackfill
+	limits, err := validation.NewOverrides(l, nil)
+	require.NoError(t, err)
+
+	store := &mockStore{
+		chunks: map[string][]chunk.Chunk{},
+	}
+
+	i, err := New(defaultIngesterTestConfig(t), client.Config{}, store, limits, runtime.DefaultTenantConfigs(), nil)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), i))
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	now := time.Now()
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err = i.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{foo="bar"}`, Entries: []logproto.Entry{{Timestamp: now, Line: "new"}}},
+	}})
+	require.NoError(t, err)
+
+	_, err = i.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{foo="bar"}`, Entries: []logproto.Entry{
+			{Timestamp: now.Add(-30 * time.Second), Line: "recent"},
+			{Timestamp: now.Add(-2 * time.Hour), Line: "old 2"},
+			{Timestamp: now.Add(-3 * time.Hour), Line: "old 1"},
+		}},
+	}})
+	require.NoError(t, err)
+
+	// The old entries are written to the store in a chunk of their own, after the push returned.
+	var c chunk.Chunk
+	require.Eventually(t, func() bool {
+		store.mtx.Lock()
+		defer store.mtx.Unlock()
+		if len(store.chunks["test"]) == 0 {
+			return false
+		}
+		require.Len(t, store.chunks["test"], 1)
+		c = store.chunks["test"][0]
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, "test", c.UserID)
+	require.Equal(t, `{__name__="logs", foo="bar"}`, c.Metric.String())
+	require.Equal(t, model.TimeFromUnixNano(now.Add(-3*time.Hour).UnixNano()), c.From)
+
+	it, err := c.Data.(*chunkenc.Facade).LokiChunk().Iterator(ctx, now.Add(-4*time.Hour), now, logproto.FORWARD, log.NewNoopPipeline().ForStream(nil))
+	require.NoError(t, err)
+	var lines []string
+	for it.Next() {
+		lines = append(lines, it.Entry().Line)
+	}
+	require.NoError(t, it.Close())
+	require.Equal(t, []string{"old 1", "old 2"}, lines)
+
+	// Only the entries within the window are kept in memory.
+	inst, ok := i.getInstanceByID("test")
+	require.True(t, ok)
+	s, ok := inst.streams.Load(`{foo="bar"}`)
+	require.True(t, ok)
+	require.Equal(t, 2, s.chunks[0].chunk.Size())
+}
+
+func TestBackfillQueue(t *testing.T) {
+	q := newBackfillQueue()
+	for j := 0; j < backfillQueueLength; j++ {
+		require.NoError(t, q.push(backfillRequest{tenant: "test"}))
+	}
+
+	// Pushes are rejected with a retryable error while the queue is full.
+	err := q.push(backfillRequest{tenant: "test"})
+	require.Error(t, err)
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok)
+	require.Equal(t, int32(http.StatusTooManyRequests), resp.Code)
+
+	// Stopping waits for the queued requests, and rejects new ones.
+	q.done.Add(1)
+	var drained int
+	go func() {
+		defer q.done.Done()
+		for range q.requests {
+			drained++
+		}
+	}()
+	q.stop()
+	require.Equal(t, backfillQueueLength, drained)
+	require.Equal(t, ErrReadOnly, q.push(backfillRequest{tenant: "test"}))
+}
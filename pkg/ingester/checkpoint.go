@@ -103,7 +103,8 @@ func fromWireChunks(conf *Config, wireChunks []Chunk) ([]chunkDesc, error) {
 		// Always use Unordered headblocks during replay
 		// to ensure Loki can effectively replay an unordered-friendly
 		// WAL into a new configuration that disables unordered writes.
-		hbType := chunkenc.UnorderedHeadBlockFmt
+		// Chunks which store structured metadata keep it in their head block.
+		hbType := chunkenc.ReplayHeadBlockFmt(c.Data)
 		mc, err := chunkenc.MemchunkFromCheckpoint(c.Data, c.Head, hbType, conf.BlockSize, conf.TargetChunkSize)
 		if err != nil {
 			return nil, err
@@ -117,6 +118,15 @@ func fromWireChunks(conf *Config, wireChunks []Chunk) ([]chunkDesc, error) {
 
 // nolint:interfacer
 func decodeCheckpointRecord(rec []byte, s *Series) error {
+	if wal.RecordType(rec[0]) == wal.CompressedRecord {
+		// Decompressing returns a new []byte, so there is no need to copy it below.
+		decompressed, err := wal.Decompress(rec)
+		if err != nil {
+			return errors.Wrap(err, "decompress checkpoint record")
+		}
+		return unmarshalCheckpointRecord(decompressed, s)
+	}
+
 	// TODO(owen-d): reduce allocs
 	// The proto unmarshaling code will retain references to the underlying []byte it's passed
 	// in order to reduce allocs. This is harmful to us because when reading from a WAL, the []byte
@@ -124,10 +134,13 @@ func decodeCheckpointRecord(rec []byte, s *Series) error {
 	// Therefore, we copy it to avoid this problem.
 	cpy := make([]byte, len(rec))
 	copy(cpy, rec)
+	return unmarshalCheckpointRecord(cpy, s)
+}
 
-	switch wal.RecordType(cpy[0]) {
+func unmarshalCheckpointRecord(rec []byte, s *Series) error {
+	switch wal.RecordType(rec[0]) {
 	case wal.CheckpointRecord:
-		return proto.Unmarshal(cpy[1:], s)
+		return proto.Unmarshal(rec[1:], s)
 	default:
 		return errors.Errorf("unexpected record type: %d", rec[0])
 	}
@@ -307,8 +320,9 @@ type walLogger interface {
 }
 
 type WALCheckpointWriter struct {
-	metrics    *ingesterMetrics
-	segmentWAL *wlog.WL
+	metrics     *ingesterMetrics
+	segmentWAL  *wlog.WL
+	compression wal.Compression
 
 	checkpointWAL walLogger
 	lastSegment   int    // name of the last segment guaranteed to be covered by the checkpoint
@@ -375,6 +389,13 @@ func (w *WALCheckpointWriter) Write(s *Series) error {
 	if err != nil {
 		return err
 	}
+	w.metrics.checkpointUncompressedBytesTotal.Add(float64(len(b)))
+
+	if w.compression != wal.CompressionNone {
+		compressed := w.compression.Compress(b, recordBufferPool.Get(len(b)).([]byte)[:0])
+		recordBufferPool.Put(b)
+		b = compressed
+	}
 
 	w.recs = append(w.recs, b)
 	w.bufSize += len(b)
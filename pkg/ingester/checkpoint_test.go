@@ -133,6 +133,77 @@ func TestIngesterWAL(t *testing.T) {
 	ensureIngesterData(ctx, t, start, end, i)
 }
 
+func TestIngesterWALCompression(t *testing.T) {
+	walDir := t.TempDir()
+
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	newIngester := func(compression string) *Ingester {
+		ingesterConfig := defaultIngesterTestConfigWithWAL(t, walDir)
+		ingesterConfig.WAL.Compression = compression
+		require.NoError(t, ingesterConfig.WAL.Validate())
+
+		i, err := New(ingesterConfig, client.Config{}, &mockStore{chunks: map[string][]chunk.Chunk{}}, limits, runtime.DefaultTenantConfigs(), nil)
+		require.NoError(t, err)
+		require.Nil(t, services.StartAndAwaitRunning(context.Background(), i))
+		return i
+	}
+
+	i := newIngester("zstd")
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	req := logproto.PushRequest{
+		Streams: []logproto.Stream{
+			{
+				Labels: `{foo="bar",bar="baz1"}`,
+			},
+			{
+				Labels: `{foo="bar",bar="baz2"}`,
+			},
+		},
+	}
+
+	start := time.Now()
+	steps := 10
+	end := start.Add(time.Second * time.Duration(steps))
+
+	for i := 0; i < steps; i++ {
+		req.Streams[0].Entries = append(req.Streams[0].Entries, logproto.Entry{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Line:      fmt.Sprintf("line %d", i),
+		})
+		req.Streams[1].Entries = append(req.Streams[1].Entries, logproto.Entry{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Line:      fmt.Sprintf("line %d", i),
+		})
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	_, err = i.Push(ctx, &req)
+	require.NoError(t, err)
+
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+	expectCheckpoint(t, walDir, false, time.Second)
+
+	// The compression can change between restarts, as every record says how it was compressed.
+	i = newIngester("snappy")
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	// ensure we've recovered data from the zstd wal segments
+	ensureIngesterData(ctx, t, start, end, i)
+
+	// ensure we have written a snappy checkpoint
+	expectCheckpoint(t, walDir, true, 5*time.Second)
+	require.Nil(t, services.StopAndAwaitTerminated(context.Background(), i))
+
+	i = newIngester("none")
+	defer services.StopAndAwaitTerminated(context.Background(), i) //nolint:errcheck
+
+	// ensure we've recovered data from the compressed checkpoint+wal segments
+	ensureIngesterData(ctx, t, start, end, i)
+}
+
 func TestIngesterWALIgnoresStreamLimits(t *testing.T) {
 	walDir := t.TempDir()
 
@@ -35,6 +35,7 @@ type ClosableHealthAndIngesterClient struct {
 	logproto.QuerierClient
 	logproto.IngesterClient
 	logproto.StreamDataClient
+	logproto.TenantVolumesClient
 	grpc_health_v1.HealthClient
 	io.Closer
 }
@@ -79,12 +80,13 @@ func New(cfg Config, addr string) (HealthAndIngesterClient, error) {
 		return nil, err
 	}
 	return ClosableHealthAndIngesterClient{
-		PusherClient:     logproto.NewPusherClient(conn),
-		QuerierClient:    logproto.NewQuerierClient(conn),
-		IngesterClient:   logproto.NewIngesterClient(conn),
-		StreamDataClient: logproto.NewStreamDataClient(conn),
-		HealthClient:     grpc_health_v1.NewHealthClient(conn),
-		Closer:           conn,
+		PusherClient:        logproto.NewPusherClient(conn),
+		QuerierClient:       logproto.NewQuerierClient(conn),
+		IngesterClient:      logproto.NewIngesterClient(conn),
+		StreamDataClient:    logproto.NewStreamDataClient(conn),
+		TenantVolumesClient: logproto.NewTenantVolumesClient(conn),
+		HealthClient:        grpc_health_v1.NewHealthClient(conn),
+		Closer:              conn,
 	}, nil
 }
 
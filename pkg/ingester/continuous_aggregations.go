@@ -0,0 +1,257 @@
+package ingester
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/weaveworks/common/user"
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/loki/pkg/ingester/aggregations"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	util_log "github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+type ContinuousAggregationsConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	Delay    time.Duration `yaml:"delay"`
+}
+
+// RegisterFlags adds the flags required to config this to the given FlagSet
+func (cfg *ContinuousAggregationsConfig) RegisterFlags(f *flag.FlagSet) {
+	f.DurationVar(&cfg.Interval, "ingester.continuous-aggregations.interval", 15*time.Second, "Interval of the values that the ingester writes for the continuous aggregations of the tenants. Queries are answered exactly from the aggregations when their range and their step are multiples of it. The query frontend must be configured with the same value, as it checks that the aggregations cover the range of a query before answering it from them. 0 disables continuous aggregations.")
+	f.DurationVar(&cfg.Delay, "ingester.continuous-aggregations.delay", time.Minute, "How long after the end of an interval the ingester writes the values of the continuous aggregations for it. Entries that are received later are not aggregated.")
+}
+
+func (cfg *ContinuousAggregationsConfig) Validate() error {
+	if cfg.Interval < 0 {
+		return errors.Errorf("invalid continuous aggregations interval: %v", cfg.Interval)
+	}
+	if cfg.Delay < 0 {
+		return errors.Errorf("invalid continuous aggregations delay: %v", cfg.Delay)
+	}
+	return nil
+}
+
+func (i *Ingester) continuousAggregationsLoop() {
+	defer i.loopDone.Done()
+
+	ticker := time.NewTicker(i.cfg.ContinuousAggregations.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			now := time.Now()
+			until := now.Add(-i.cfg.ContinuousAggregations.Delay)
+			for _, instance := range i.getInstances() {
+				// The materialised streams count towards the volume of the tenant like the streams it pushes.
+				if bytes := instance.flushAggregations(now, until); bytes > 0 {
+					i.tenantVolumes.Add(instance.instanceID, int64(bytes), now)
+				}
+			}
+
+		case <-i.loopQuit:
+			return
+		}
+	}
+}
+
+// newAccumulator returns the accumulator of the continuous aggregations of the tenant for a new stream, or nil if
+// none of them aggregates the stream.
+func (i *instance) newAccumulator(ls labels.Labels) *aggregations.Accumulator {
+	i.aggregationsMtx.RLock()
+	defer i.aggregationsMtx.RUnlock()
+
+	if len(i.aggregations) == 0 || i.cfg.ContinuousAggregations.Interval <= 0 {
+		return nil
+	}
+	return aggregations.NewAccumulator(ls, i.cfg.ContinuousAggregations.Interval, i.aggregations)
+}
+
+// updateAggregations updates the accumulators of the streams when the continuous aggregations of the tenant changed.
+// New aggregations only cover the intervals that start after now, as the entries received before were not
+// accumulated.
+func (i *instance) updateAggregations(now time.Time) {
+	aggs := i.limiter.limits.ContinuousAggregations(i.instanceID)
+
+	i.aggregationsMtx.Lock()
+	defer i.aggregationsMtx.Unlock()
+
+	if sameAggregations(i.aggregations, aggs) {
+		return
+	}
+	i.aggregations = aggs
+
+	interval := i.cfg.ContinuousAggregations.Interval
+	covered := make(map[string]time.Time, len(aggs))
+	for _, agg := range aggs {
+		key := aggregationKey(agg)
+		if through, ok := i.aggregationsCovered[key]; ok {
+			covered[key] = through
+			continue
+		}
+		through := now.Truncate(interval)
+		if through.Before(now) {
+			through = through.Add(interval)
+		}
+		covered[key] = through
+	}
+	i.aggregationsCovered = covered
+
+	_ = i.streams.ForEach(func(s *stream) (bool, error) {
+		s.chunkMtx.Lock()
+		if s.aggregations == nil {
+			s.aggregations = aggregations.NewAccumulator(s.labels, interval, aggs)
+		} else {
+			s.aggregations = s.aggregations.Update(aggs)
+		}
+		s.chunkMtx.Unlock()
+		return true, nil
+	})
+}
+
+func sameAggregations(a, b []*aggregations.Aggregation) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || a[i].Query != b[i].Query || a[i].From != b[i].From {
+			return false
+		}
+	}
+	return true
+}
+
+// aggregationKey identifies an aggregation across updates; the values accumulated for an aggregation are reset when
+// its query changes.
+func aggregationKey(agg *aggregations.Aggregation) string {
+	return agg.Name + "\x00" + agg.Query
+}
+
+// flushAggregations writes the values of the continuous aggregations for the intervals that end before until to
+// the materialised streams of the tenant, along with the entries of the coverage streams that record these
+// intervals. It returns the number of bytes written.
+//
+// The values are only written when the materialised streams are within the label limits of the tenant and the
+// bytes are within its ingestion rate, which the distributor applies to the streams the tenant pushes. Otherwise
+// nothing is written, and the queries of the intervals are not answered from the aggregations.
+func (i *instance) flushAggregations(now, until time.Time) int {
+	i.updateAggregations(now)
+
+	i.aggregationsMtx.Lock()
+	if len(i.aggregations) == 0 {
+		i.aggregationsMtx.Unlock()
+		return 0
+	}
+	interval := i.cfg.ContinuousAggregations.Interval
+	replica := i.cfg.LifecyclerConfig.ID
+	streams := map[string]*logproto.Stream{}
+	for _, agg := range i.aggregations {
+		key := aggregationKey(agg)
+		var entries []logproto.Entry
+		through := i.aggregationsCovered[key]
+		for end := through.Add(interval); !end.After(until); end = end.Add(interval) {
+			entries = append(entries, logproto.Entry{Timestamp: end, Line: "1"})
+			through = end
+		}
+		i.aggregationsCovered[key] = through
+		if len(entries) > 0 {
+			ls := labels.FromStrings(aggregations.CoverageLabel, agg.Name).String()
+			streams[ls] = &logproto.Stream{Labels: ls, Entries: entries}
+		}
+	}
+	i.aggregationsMtx.Unlock()
+
+	var samples int
+	_ = i.streams.ForEach(func(s *stream) (bool, error) {
+		s.chunkMtx.Lock()
+		var flushed []aggregations.Sample
+		if s.aggregations != nil {
+			flushed = s.aggregations.Flush(until)
+		}
+		s.chunkMtx.Unlock()
+
+		for _, sample := range flushed {
+			ls := labels.NewBuilder(sample.Labels).Set(aggregations.ReplicaLabel, replica).Labels(nil).String()
+			stream, ok := streams[ls]
+			if !ok {
+				stream = &logproto.Stream{Labels: ls}
+				streams[ls] = stream
+			}
+			stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: sample.Timestamp, Line: aggregations.FormatValue(sample.Value)})
+		}
+		samples += len(flushed)
+		return true, nil
+	})
+	if len(streams) == 0 {
+		return 0
+	}
+
+	req := &logproto.PushRequest{Streams: make([]logproto.Stream, 0, len(streams))}
+	var entries, bytes int
+	for _, stream := range streams {
+		req.Streams = append(req.Streams, *stream)
+		entries += len(stream.Entries)
+		for _, e := range stream.Entries {
+			bytes += len(e.Line)
+		}
+	}
+	if reason, err := i.validateAggregations(req, bytes, now); err != nil {
+		validation.DiscardedSamples.WithLabelValues(reason, i.instanceID).Add(float64(entries))
+		validation.DiscardedBytes.WithLabelValues(reason, i.instanceID).Add(float64(bytes))
+		level.Warn(util_log.Logger).Log("msg", "dropped continuous aggregations", "tenant", i.instanceID, "err", err)
+		return 0
+	}
+	if err := i.Push(user.InjectOrgID(context.Background(), i.instanceID), req); err != nil {
+		level.Error(util_log.Logger).Log("msg", "failed to write continuous aggregations", "tenant", i.instanceID, "err", err)
+		return 0
+	}
+	i.metrics.continuousAggregationSamples.WithLabelValues(i.instanceID).Add(float64(samples))
+	return bytes
+}
+
+// validateAggregations applies the label limits and the ingestion rate of the tenant to the materialised streams,
+// and returns the reason to discard them. Each ingester allows the ingestion rate of the tenant for the streams it
+// materialises.
+func (i *instance) validateAggregations(req *logproto.PushRequest, bytes int, now time.Time) (string, error) {
+	limits := i.limiter.limits
+	maxLabelNames := limits.MaxLabelNamesPerSeries(i.instanceID)
+	maxNameLength, maxValueLength := limits.MaxLabelNameLength(i.instanceID), limits.MaxLabelValueLength(i.instanceID)
+	for _, stream := range req.Streams {
+		ls, err := syntax.ParseLabels(stream.Labels)
+		if err != nil {
+			return validation.InvalidLabels, fmt.Errorf(validation.InvalidLabelsErrorMsg, stream.Labels, err)
+		}
+		if len(ls) > maxLabelNames {
+			return validation.MaxLabelNamesPerSeries, fmt.Errorf(validation.MaxLabelNamesPerSeriesErrorMsg, stream.Labels, len(ls), maxLabelNames)
+		}
+		for _, l := range ls {
+			if len(l.Name) > maxNameLength {
+				return validation.LabelNameTooLong, fmt.Errorf(validation.LabelNameTooLongErrorMsg, stream.Labels, l.Name)
+			}
+			if len(l.Value) > maxValueLength {
+				return validation.LabelValueTooLong, fmt.Errorf(validation.LabelValueTooLongErrorMsg, stream.Labels, l.Value)
+			}
+		}
+	}
+
+	limit, burst := rate.Limit(limits.IngestionRateBytes(i.instanceID)), limits.IngestionBurstSizeBytes(i.instanceID)
+	if i.aggregationsLimiter == nil {
+		i.aggregationsLimiter = rate.NewLimiter(limit, burst)
+	} else {
+		i.aggregationsLimiter.SetLimitAt(now, limit)
+		i.aggregationsLimiter.SetBurstAt(now, burst)
+	}
+	if !i.aggregationsLimiter.AllowN(now, bytes) {
+		return validation.RateLimited, fmt.Errorf(validation.RateLimitedErrorMsg, i.instanceID, int(limit), len(req.Streams), bytes)
+	}
+	return "", nil
+}
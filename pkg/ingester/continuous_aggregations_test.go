@@ -0,0 +1,131 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/ingester/aggregations"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	loki_runtime "github.com/grafana/loki/pkg/runtime"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func TestInstanceFlushAggregations(t *testing.T) {
+	aggs := []*aggregations.Aggregation{
+		{Name: "status", Query: `sum by (status) (count_over_time({app="nginx"} | logfmt [1m]))`},
+	}
+	require.NoError(t, aggregations.Validate(aggs))
+
+	l := defaultLimitsTestConfig()
+	l.ContinuousAggregations = aggs
+	limits, err := validation.NewOverrides(l, nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+	cfg := defaultConfig()
+	cfg.LifecyclerConfig.ID = "ingester-1"
+	cfg.ContinuousAggregations.Interval = 10 * time.Second
+	inst, err := newInstance(cfg, defaultPeriodConfigs, "test", limiter, loki_runtime.DefaultTenantConfigs(), noopWAL{}, NilMetrics, &OnceSwitch{}, nil, NewStreamRateCalculator())
+	require.NoError(t, err)
+	inst.updateAggregations(time.Unix(0, 0))
+
+	err = inst.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{app="nginx"}`, Entries: []logproto.Entry{
+			{Timestamp: time.Unix(1, 0), Line: "status=200"},
+			{Timestamp: time.Unix(2, 0), Line: "status=200"},
+			{Timestamp: time.Unix(3, 0), Line: "status=500"},
+			{Timestamp: time.Unix(11, 0), Line: "status=200"},
+		}},
+		{Labels: `{app="api"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(1, 0), Line: "status=200"}}},
+	}})
+	require.NoError(t, err)
+
+	// The values and the coverage of the first interval are written.
+	require.Equal(t, 3, inst.flushAggregations(time.Unix(15, 0), time.Unix(15, 0)))
+
+	values, coverage := map[string][]logproto.Entry{}, []logproto.Entry{}
+	_ = inst.streams.ForEach(func(s *stream) (bool, error) {
+		if !s.labels.Has(aggregations.NameLabel) && !s.labels.Has(aggregations.CoverageLabel) {
+			return true, nil
+		}
+		it, err := s.Iterator(context.Background(), nil, time.Unix(0, 0), time.Unix(100, 0), logproto.FORWARD, log.NewNoopPipeline().ForStream(s.labels))
+		require.NoError(t, err)
+		for it.Next() {
+			if s.labels.Has(aggregations.CoverageLabel) {
+				coverage = append(coverage, it.Entry())
+				continue
+			}
+			values[s.labels.Get("status")] = append(values[s.labels.Get("status")], it.Entry())
+		}
+		require.NoError(t, it.Close())
+		if s.labels.Has(aggregations.CoverageLabel) {
+			require.Equal(t, "status", s.labels.Get(aggregations.CoverageLabel))
+			return true, nil
+		}
+		require.Equal(t, "status", s.labels.Get(aggregations.NameLabel))
+		require.Equal(t, "ingester-1", s.labels.Get(aggregations.ReplicaLabel))
+		require.Equal(t, "", s.labels.Get("app"))
+		return true, nil
+	})
+	require.Equal(t, map[string][]logproto.Entry{
+		"200": {{Timestamp: time.Unix(10, 0), Line: "2"}},
+		"500": {{Timestamp: time.Unix(10, 0), Line: "1"}},
+	}, values)
+	require.Equal(t, []logproto.Entry{{Timestamp: time.Unix(10, 0), Line: "1"}}, coverage)
+
+	// The materialised streams are not aggregated themselves, and the next interval is written once it is flushed.
+	require.Equal(t, 2, inst.flushAggregations(time.Unix(25, 0), time.Unix(25, 0)))
+	require.Equal(t, 5, inst.streams.Len())
+	s, ok := inst.streams.Load(`{app="nginx"}`)
+	require.True(t, ok)
+	require.Empty(t, s.aggregations.Flush(time.Unix(100, 0)))
+
+	// Removing the aggregations stops accumulating.
+	l.ContinuousAggregations = nil
+	limits, err = validation.NewOverrides(l, nil)
+	require.NoError(t, err)
+	inst.limiter = NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+	inst.updateAggregations(time.Unix(25, 0))
+	require.Nil(t, s.aggregations)
+}
+
+func TestInstanceFlushAggregationsLimits(t *testing.T) {
+	aggs := []*aggregations.Aggregation{
+		{Name: "status", Query: `sum by (status) (count_over_time({app="nginx"} | logfmt [1m]))`},
+	}
+	require.NoError(t, aggregations.Validate(aggs))
+
+	for name, update := range map[string]func(*validation.Limits){
+		"label names": func(l *validation.Limits) { l.MaxLabelNamesPerSeries = 3 },
+		"rate":        func(l *validation.Limits) { l.IngestionBurstSizeMB = 1e-6 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			l := defaultLimitsTestConfig()
+			l.ContinuousAggregations = aggs
+			update(&l)
+			limits, err := validation.NewOverrides(l, nil)
+			require.NoError(t, err)
+			limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+			cfg := defaultConfig()
+			cfg.LifecyclerConfig.ID = "ingester-1"
+			cfg.ContinuousAggregations.Interval = 10 * time.Second
+			inst, err := newInstance(cfg, defaultPeriodConfigs, "test", limiter, loki_runtime.DefaultTenantConfigs(), noopWAL{}, NilMetrics, &OnceSwitch{}, nil, NewStreamRateCalculator())
+			require.NoError(t, err)
+			inst.updateAggregations(time.Unix(0, 0))
+
+			err = inst.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
+				{Labels: `{app="nginx"}`, Entries: []logproto.Entry{{Timestamp: time.Unix(1, 0), Line: "status=200"}}},
+			}})
+			require.NoError(t, err)
+
+			// Neither the values nor the coverage of the interval are written.
+			require.Equal(t, 0, inst.flushAggregations(time.Unix(15, 0), time.Unix(15, 0)))
+			require.Equal(t, 1, inst.streams.Len())
+		})
+	}
+}
@@ -246,6 +246,9 @@ func (i *Ingester) removeFlushedChunks(instance *instance, stream *stream, mayRe
 
 		subtracted += stream.chunks[0].chunk.UncompressedSize()
 		stream.chunks[0].chunk = nil // erase reference so the chunk can be garbage-collected
+		if stream.chunks[0].spill != nil {
+			stream.chunks[0].spill.remove()
+		}
 		stream.chunks = stream.chunks[1:]
 	}
 	i.metrics.memoryChunks.Sub(float64(prevNumChunks - len(stream.chunks)))
@@ -287,6 +290,15 @@ func (i *Ingester) flushChunks(ctx context.Context, fp model.Fingerprint, labelP
 	sizePerTenant := i.metrics.chunkSizePerTenant.WithLabelValues(userID)
 	countPerTenant := i.metrics.chunksPerTenant.WithLabelValues(userID)
 
+	// closeChunk marks the chunks as flushing, so that they are not spilled while they are read.
+	defer func() {
+		chunkMtx.Lock()
+		defer chunkMtx.Unlock()
+		for _, c := range cs {
+			c.flushing = false
+		}
+	}()
+
 	for j, c := range cs {
 		if err := i.closeChunk(c, chunkMtx); err != nil {
 			return fmt.Errorf("chunk close for flushing: %w", err)
@@ -330,13 +342,15 @@ func (i *Ingester) markChunkAsFlushed(desc *chunkDesc, chunkMtx sync.Locker) {
 	desc.flushed = time.Now()
 }
 
-// closeChunk closes the given chunk while locking it to ensure that new blocks are cut before flushing.
+// closeChunk closes the given chunk while locking it to ensure that new blocks are cut before flushing,
+// and marks it as flushing so that it isn't replaced while it is read.
 //
 // If the chunk isn't closed, data in the head block isn't included.
 func (i *Ingester) closeChunk(desc *chunkDesc, chunkMtx sync.Locker) error {
 	chunkMtx.Lock()
 	defer chunkMtx.Unlock()
 
+	desc.flushing = true
 	return desc.chunk.Close()
 }
 
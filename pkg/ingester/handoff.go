@@ -0,0 +1,487 @@
+package ingester
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	tsdb_record "github.com/prometheus/prometheus/tsdb/record"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/ingester/client"
+	"github.com/grafana/loki/pkg/ingester/wal"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	lokiutil "github.com/grafana/loki/pkg/util"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+// handoffBatchSize is the size of the head chunk entries sent in a single handoff request.
+const handoffBatchSize = 1 << 20
+
+// handoffOwnersOp selects the owners of a stream as they were before the leaving ingester left the ring.
+var handoffOwnersOp = ring.NewOp([]ring.InstanceState{ring.ACTIVE, ring.LEAVING}, func(s ring.InstanceState) bool {
+	return s != ring.ACTIVE && s != ring.LEAVING
+})
+
+type HandoffConfig struct {
+	Enabled bool          `yaml:"enabled"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// RegisterFlags adds the flags required to config this to the given FlagSet
+func (cfg *HandoffConfig) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "ingester.handoff.enabled", false, "Enable handing off the in-memory streams of a leaving ingester to the ingesters owning them once it left the ring, instead of flushing them. The handoff runs on every shutdown, also when the WAL is enabled and doesn't flush on shutdown, and empties the WAL directory once it succeeded. When the handoff fails, the ingester shuts down as it would without handoff.")
+	f.DurationVar(&cfg.Timeout, "ingester.handoff.timeout", 5*time.Minute, "The maximum duration of the handoff of the streams of a leaving ingester.")
+}
+
+func (cfg *HandoffConfig) Validate() error {
+	if cfg.Enabled && cfg.Timeout <= 0 {
+		return errors.Errorf("invalid handoff timeout: %v", cfg.Timeout)
+	}
+	return nil
+}
+
+// HandoffStreams receives the streams of a leaving ingester that this ingester owns once it left the ring.
+// The leaving ingester is only acknowledged once the received streams were written to the WAL.
+func (i *Ingester) HandoffStreams(stream logproto.Ingester_HandoffStreamsServer) error {
+	logger := util_log.WithContext(stream.Context(), util_log.Logger)
+	// Prevent a shutdown from happening until the handoff is complete, so that the
+	// WAL isn't stopped while the received streams are written to it.
+	i.shutdownMtx.Lock()
+	defer i.shutdownMtx.Unlock()
+
+	if i.readonly {
+		return ErrReadOnly
+	}
+
+	fromIngesterID := ""
+	var chunksReceived, entriesReceived int
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if fromIngesterID == "" {
+			fromIngesterID = req.FromIngesterId
+			level.Info(logger).Log("msg", "processing HandoffStreams request", "from_ingester", fromIngesterID)
+
+			// Only accept streams from ingesters that left the ring, as this ingester
+			// must own the streams it receives.
+			if err := i.checkFromIngesterIsInLeavingState(stream.Context(), fromIngesterID); err != nil {
+				return errors.Wrap(err, "HandoffStreams: checkFromIngesterIsInLeavingState")
+			}
+		}
+		if req.Stream == nil {
+			return errors.New("received HandoffStreams request without stream")
+		}
+
+		ls, err := syntax.ParseLabels(req.Stream.Labels)
+		if err != nil {
+			return err
+		}
+		instance, err := i.GetOrCreateInstance(req.UserId)
+		if err != nil {
+			return err
+		}
+		if err := instance.consumeHandoff(stream.Context(), ls, req.Chunks, req.Stream.Entries); err != nil {
+			return err
+		}
+
+		chunksReceived += len(req.Chunks)
+		entriesReceived += len(req.Stream.Entries)
+		i.metrics.handoffReceivedChunks.Add(float64(len(req.Chunks)))
+		i.metrics.handoffReceivedEntries.Add(float64(len(req.Stream.Entries)))
+	}
+
+	if err := stream.SendAndClose(&logproto.HandoffStreamsResponse{}); err != nil {
+		level.Error(logger).Log("msg", "Error closing HandoffStreams stream", "from_ingester", fromIngesterID, "err", err)
+		return err
+	}
+	level.Info(logger).Log("msg", "Successfully received handed off streams", "from_ingester", fromIngesterID, "chunks_received", chunksReceived, "entries_received", entriesReceived)
+	return nil
+}
+
+// handoffOut hands off the streams of this leaving ingester to the ingesters owning them once it left the ring.
+// Streams for which this ingester was a replica only go to their new owners, the other replicas already have them.
+func (i *Ingester) handoffOut(ctx context.Context) error {
+	logger := util_log.WithContext(ctx, util_log.Logger)
+	ctx, cancel := context.WithTimeout(ctx, i.cfg.Handoff.Timeout)
+	defer cancel()
+
+	// The ring client reads the ring from the KV store of the lifecycler, which already shows this ingester as LEAVING.
+	// Streams are handed off to their healthy owners, there is no quorum to reach.
+	r, err := ring.NewWithStoreClientAndStrategy(i.cfg.LifecyclerConfig.RingConfig, "ingester", RingKey, i.lifecycler.KVStore, ring.NewIgnoreUnhealthyInstancesReplicationStrategy(), nil, util_log.Logger)
+	if err != nil {
+		return err
+	}
+	if err := services.StartAndAwaitRunning(ctx, r); err != nil {
+		return errors.Wrap(err, "starting ring client")
+	}
+	defer services.StopAndAwaitTerminated(context.Background(), r) //nolint:errcheck
+
+	h := &handoff{
+		ingester: i,
+		ctx:      user.InjectOrgID(ctx, "-1"),
+		targets:  map[string]logproto.Ingester_HandoffStreamsClient{},
+	}
+	defer h.close()
+
+	var streams int
+	for _, inst := range i.getInstances() {
+		err := inst.streams.ForEach(func(s *stream) (bool, error) {
+			addrs, err := h.owners(r, inst.instanceID, s)
+			if err != nil {
+				return false, err
+			}
+			if len(addrs) == 0 {
+				return true, nil
+			}
+			if err := h.send(addrs, inst.instanceID, s); err != nil {
+				return false, err
+			}
+			streams++
+			return true, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for addr, s := range h.targets {
+		if _, err := s.CloseAndRecv(); err != nil {
+			return errors.Wrapf(err, "CloseAndRecv from %s", addr)
+		}
+	}
+
+	for _, flushQueue := range i.flushQueues {
+		flushQueue.DiscardAndClose()
+	}
+	i.flushQueuesDone.Wait()
+
+	// The streams are part of the WAL of their new owners now, so they must not be replayed
+	// by this ingester if it ever comes back.
+	if i.cfg.WAL.Enabled {
+		if err := removeDirContents(i.cfg.WAL.Dir); err != nil {
+			level.Warn(logger).Log("msg", "failed to remove the WAL after handing off streams", "dir", i.cfg.WAL.Dir, "err", err)
+		}
+	}
+
+	level.Info(logger).Log("msg", "successfully handed off streams", "streams", streams, "to_ingesters", len(h.targets))
+	return nil
+}
+
+// handoff sends the streams of a leaving ingester over one HandoffStreams stream per receiving ingester.
+type handoff struct {
+	ingester *Ingester
+	ctx      context.Context
+
+	clients []client.HealthAndIngesterClient
+	targets map[string]logproto.Ingester_HandoffStreamsClient
+}
+
+// owners returns the addresses of the ingesters the stream is handed off to.
+func (h *handoff) owners(r ring.ReadRing, tenantID string, s *stream) ([]string, error) {
+	token := lokiutil.TokenFor(tenantID, s.labelsString)
+	after, err := r.Get(token, ring.Write, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	before, err := r.Get(token, handoffOwnersOp, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// A stream this ingester didn't own, for instance received while another ingester was leaving,
+	// goes to all its owners.
+	owned := before.Includes(h.ingester.lifecycler.Addr)
+	addrs := make([]string, 0, len(after.Instances))
+	for _, instance := range after.Instances {
+		if owned && before.Includes(instance.Addr) {
+			continue
+		}
+		addrs = append(addrs, instance.Addr)
+	}
+	return addrs, nil
+}
+
+// target returns the HandoffStreams stream to the ingester, or opens a new one.
+func (h *handoff) target(addr string) (logproto.Ingester_HandoffStreamsClient, error) {
+	if s, ok := h.targets[addr]; ok {
+		return s, nil
+	}
+
+	c, err := h.ingester.cfg.ingesterClientFactory(h.ingester.clientConfig, addr)
+	if err != nil {
+		return nil, err
+	}
+	h.clients = append(h.clients, c)
+
+	s, err := c.HandoffStreams(h.ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "HandoffStreams")
+	}
+	h.targets[addr] = s
+	return s, nil
+}
+
+func (h *handoff) send(addrs []string, tenantID string, s *stream) error {
+	reqs, numChunks, numEntries, err := handoffRequests(h.ctx, h.ingester.lifecycler.ID, tenantID, s)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range addrs {
+		target, err := h.target(addr)
+		if err != nil {
+			return err
+		}
+		for _, req := range reqs {
+			if err := target.Send(req); err != nil {
+				level.Error(util_log.WithContext(h.ctx, util_log.Logger)).Log("msg", "failed handing off stream to ingester", "to_ingester", addr, "err", err)
+				return err
+			}
+		}
+		h.ingester.metrics.handoffSentChunks.Add(float64(numChunks))
+		h.ingester.metrics.handoffSentEntries.Add(float64(numEntries))
+	}
+	return nil
+}
+
+func (h *handoff) close() {
+	for _, c := range h.clients {
+		lokiutil.LogErrorWithContext(h.ctx, "closing client", c.Close)
+	}
+}
+
+// handoffRequests returns the requests handing off the stream: one per chunk that was cut and not flushed yet,
+// and the entries of its head chunk in batches.
+func handoffRequests(ctx context.Context, fromIngesterID, tenantID string, s *stream) ([]*logproto.HandoffStreamsRequest, int, int, error) {
+	s.chunkMtx.Lock()
+	defer s.chunkMtx.Unlock()
+
+	newRequest := func() *logproto.HandoffStreamsRequest {
+		return &logproto.HandoffStreamsRequest{
+			FromIngesterId: fromIngesterID,
+			UserId:         tenantID,
+			Stream:         &logproto.Stream{Labels: s.labelsString},
+		}
+	}
+
+	var (
+		reqs                  []*logproto.HandoffStreamsRequest
+		numChunks, numEntries int
+	)
+	for idx, c := range s.chunks {
+		if !c.flushed.IsZero() {
+			continue
+		}
+
+		if idx == len(s.chunks)-1 && !c.closed {
+			headEntries, err := chunkEntries(ctx, c.chunk, s.labels)
+			if err != nil {
+				return nil, 0, 0, err
+			}
+			numEntries += len(headEntries)
+
+			req, size := newRequest(), 0
+			for _, e := range headEntries {
+				if size >= handoffBatchSize {
+					reqs = append(reqs, req)
+					req, size = newRequest(), 0
+				}
+				req.Stream.Entries = append(req.Stream.Entries, e)
+				size += len(e.Line)
+			}
+			if len(req.Stream.Entries) > 0 {
+				reqs = append(reqs, req)
+			}
+			continue
+		}
+
+		// Close the chunk first, writing any data in the head block to a new block.
+		if err := c.chunk.Close(); err != nil {
+			return nil, 0, 0, err
+		}
+		b, err := c.chunk.Bytes()
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		req := newRequest()
+		req.Chunks = []*logproto.Chunk{{Data: b}}
+		reqs = append(reqs, req)
+		numChunks++
+	}
+	return reqs, numChunks, numEntries, nil
+}
+
+// chunkEntries returns all the entries of the chunk in order.
+func chunkEntries(ctx context.Context, c *chunkenc.MemChunk, ls labels.Labels) ([]logproto.Entry, error) {
+	from, through := c.Bounds()
+	it, err := c.Iterator(ctx, from, through.Add(time.Nanosecond), logproto.FORWARD, log.NewNoopPipeline().ForStream(ls))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var entries []logproto.Entry
+	for it.Next() {
+		entries = append(entries, it.Entry())
+	}
+	return entries, it.Error()
+}
+
+// consumeHandoff adds the chunks and head chunk entries handed off by a leaving ingester to the stream,
+// and writes them to the WAL.
+func (i *instance) consumeHandoff(ctx context.Context, ls labels.Labels, chks []*logproto.Chunk, entries []logproto.Entry) error {
+	record := recordPool.GetRecord()
+	record.UserID = i.instanceID
+	defer recordPool.PutRecord(record)
+
+	fp := i.getHashForLabels(ls)
+	s, _, _ := i.streams.LoadOrStoreNewByFP(fp,
+		func() (*stream, error) {
+			s := i.createStreamByFP(ls, fp)
+			record.Series = append(record.Series, tsdb_record.RefSeries{
+				Ref:    chunks.HeadSeriesRef(fp),
+				Labels: s.labels,
+			})
+			s.chunkMtx.Lock()
+			return s, nil
+		},
+		func(s *stream) error {
+			s.chunkMtx.Lock()
+			return nil
+		},
+	)
+	err := s.consumeHandoff(ctx, chks, entries, record)
+	s.chunkMtx.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if record.IsEmpty() {
+		return nil
+	}
+	return i.wal.Log(record)
+}
+
+// consumeHandoff adds the chunks handed off by a leaving ingester to the stream, and appends the
+// handed off entries of its head chunk to the head chunk of the stream. Their entries are added to
+// the WAL record, so that they are recovered like pushed entries.
+// Must hold chunkMtx.
+func (s *stream) consumeHandoff(ctx context.Context, chks []*logproto.Chunk, entries []logproto.Entry, record *wal.Record) error {
+	prevNumChunks := len(s.chunks)
+	defer func() {
+		s.metrics.memoryChunks.Add(float64(len(s.chunks) - prevNumChunks))
+	}()
+
+	for _, chk := range chks {
+		c, err := chunkenc.NewByteChunk(chk.Data, s.cfg.BlockSize, s.cfg.TargetChunkSize)
+		if err != nil {
+			return err
+		}
+		es, err := chunkEntries(ctx, c, s.labels)
+		if err != nil {
+			return err
+		}
+		s.addHandoffChunk(c, es, record)
+	}
+
+	if len(entries) == 0 {
+		return nil
+	}
+	if len(s.chunks) == 0 {
+		s.chunks = append(s.chunks, chunkDesc{
+			chunk: s.NewChunk(),
+		})
+		s.metrics.chunksCreatedTotal.Inc()
+	}
+
+	// Entries older than the head chunk of a stream without unordered writes don't fit in it,
+	// so they get a chunk of their own.
+	if head := s.chunks[len(s.chunks)-1]; !s.unorderedWrites && !head.closed {
+		_, through := head.chunk.Bounds()
+		older := sort.Search(len(entries), func(i int) bool { return !entries[i].Timestamp.Before(through) })
+		if older > 0 {
+			c := s.NewChunk()
+			for idx := range entries[:older] {
+				if err := c.Append(&entries[idx]); err != nil {
+					return err
+				}
+			}
+			if err := c.Close(); err != nil {
+				return err
+			}
+			s.addHandoffChunk(c, entries[:older], record)
+			entries = entries[older:]
+		}
+	}
+
+	// The handed off entries already passed the limits on the leaving ingester.
+	_, stored, failed := s.storeEntries(ctx, entries)
+	if len(stored) > 0 {
+		record.AddEntries(uint64(s.fp), s.entryCt, stored...)
+	}
+	if len(failed) > 0 {
+		return failed[len(failed)-1].e
+	}
+	return nil
+}
+
+// addHandoffChunk adds the closed chunk before the head chunk of the stream.
+// Must hold chunkMtx.
+func (s *stream) addHandoffChunk(c *chunkenc.MemChunk, entries []logproto.Entry, record *wal.Record) {
+	desc := chunkDesc{
+		chunk:       c,
+		closed:      true,
+		lastUpdated: time.Now(),
+	}
+	if n := len(s.chunks); n > 0 && !s.chunks[n-1].closed {
+		head := s.chunks[n-1]
+		s.chunks[n-1] = desc
+		s.chunks = append(s.chunks, head)
+	} else {
+		s.chunks = append(s.chunks, desc)
+	}
+	s.metrics.chunksCreatedTotal.Inc()
+
+	for _, e := range entries {
+		if s.highestTs.Before(e.Timestamp) {
+			s.highestTs = e.Timestamp
+		}
+	}
+	if len(entries) > 0 {
+		s.entryCt += int64(len(entries))
+		record.AddEntries(uint64(s.fp), s.entryCt, entries...)
+	}
+}
+
+// removeDirContents removes everything in dir, but not dir itself which may be a mount point.
+func removeDirContents(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
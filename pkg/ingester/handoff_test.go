@@ -0,0 +1,268 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/grafana/dskit/kv/consul"
+	"github.com/grafana/dskit/ring"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/ingester/client"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func TestHandoffOut(t *testing.T) {
+	for _, withWAL := range []bool{false, true} {
+		t.Run(fmt.Sprintf("wal=%v", withWAL), func(t *testing.T) {
+			testHandoffOut(t, withWAL)
+		})
+	}
+}
+
+func testHandoffOut(t *testing.T, withWAL bool) {
+	f := newHandoffIngesterFactory(t)
+	walDir := t.TempDir()
+	store, ing := f.getHandoffIngester(t, func(cfg *Config) {
+		if withWAL {
+			// Streams are handed off on shutdown also when the WAL doesn't flush on shutdown.
+			cfg.WAL.Enabled = true
+			cfg.WAL.Dir = walDir
+			cfg.WAL.CheckpointDuration = time.Minute
+			cfg.WAL.FlushOnShutdown = false
+		}
+	})
+	_, ing2 := f.getHandoffIngester(t, nil)
+	defer services.StopAndAwaitTerminated(context.Background(), ing2) //nolint:errcheck
+
+	// Push enough entries to cut chunks, and keep some in the head chunks.
+	ctx := user.InjectOrgID(context.Background(), "test")
+	expected := map[string][]string{}
+	for _, ls := range []string{`{bar="baz1", foo="bar"}`, `{bar="baz2", foo="bar"}`} {
+		stream := logproto.Stream{Labels: ls}
+		for i := 0; i < 10; i++ {
+			line := fmt.Sprintf("line %d", i)
+			stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: time.Unix(int64(i), 0), Line: line})
+			expected[ls] = append(expected[ls], line)
+		}
+		_, err := ing.Push(ctx, &logproto.PushRequest{Streams: []logproto.Stream{stream}})
+		require.NoError(t, err)
+	}
+	if withWAL {
+		files, err := os.ReadDir(walDir)
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+	}
+
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), ing))
+
+	store.mtx.Lock()
+	require.Empty(t, store.chunks, "expected the handed off streams not to be flushed")
+	store.mtx.Unlock()
+
+	inst, ok := ing2.getInstanceByID("test")
+	require.True(t, ok)
+	require.Equal(t, 2, inst.streams.Len())
+
+	actual := map[string][]string{}
+	_ = inst.streams.ForEach(func(s *stream) (bool, error) {
+		require.Greater(t, len(s.chunks), 1, "expected the cut chunks to be handed off")
+		it, err := s.Iterator(context.Background(), nil, time.Unix(0, 0), time.Unix(100, 0), logproto.FORWARD, log.NewNoopPipeline().ForStream(s.labels))
+		require.NoError(t, err)
+		for it.Next() {
+			actual[s.labelsString] = append(actual[s.labelsString], it.Entry().Line)
+		}
+		require.NoError(t, it.Close())
+		return true, nil
+	})
+	require.Equal(t, expected, actual)
+
+	if withWAL {
+		// The handed off streams are not replayed from the WAL when the ingester restarts.
+		files, err := os.ReadDir(walDir)
+		require.NoError(t, err)
+		for _, f := range files {
+			require.Equal(t, tenantVolumesFile, f.Name())
+		}
+	}
+}
+
+// newHandoffIngesterFactory returns a factory whose ingesters use a ring of their own. The ring of
+// newTestIngesterFactory is shared by all tests, which would hand off streams to the ingesters of other tests.
+func newHandoffIngesterFactory(t *testing.T) *testIngesterFactory {
+	kvClient, closer := consul.NewInMemoryClient(ring.GetCodec(), gokitlog.NewNopLogger(), nil)
+	t.Cleanup(func() { closer.Close() })
+
+	return &testIngesterFactory{
+		t:         t,
+		store:     kvClient,
+		ingesters: make(map[string]*Ingester),
+	}
+}
+
+// getHandoffIngester returns a new ingester with stream handoff enabled and its store. Like the ingesters
+// of getIngester, it only has tiny chunks.
+func (f *testIngesterFactory) getHandoffIngester(t *testing.T, overrides func(*Config)) (*testStore, *Ingester) {
+	f.n++
+
+	cfg := defaultIngesterTestConfig(t)
+	cfg.MaxTransferRetries = 0
+	cfg.Handoff.Enabled = true
+	cfg.LifecyclerConfig.ID = fmt.Sprintf("localhost-%d", f.n)
+	cfg.LifecyclerConfig.RingConfig.KVStore.Mock = f.store
+	cfg.LifecyclerConfig.RingConfig.ReplicationFactor = 1
+	cfg.LifecyclerConfig.Addr = cfg.LifecyclerConfig.ID
+	cfg.BlockSize = 3
+	cfg.TargetChunkSize = 24
+	cfg.ChunkEncoding = chunkenc.EncNone.String()
+	if overrides != nil {
+		overrides(&cfg)
+	}
+
+	cfg.ingesterClientFactory = func(cfg client.Config, addr string) (client.HealthAndIngesterClient, error) {
+		ingester, ok := f.ingesters[addr]
+		if !ok {
+			return nil, fmt.Errorf("no ingester %s", addr)
+		}
+
+		return client.ClosableHealthAndIngesterClient{
+			IngesterClient: &testIngesterClient{t: f.t, i: ingester},
+			Closer:         io.NopCloser(nil),
+		}, nil
+	}
+
+	store, ing := newTestStore(f.t, cfg, nil)
+	f.ingesters[fmt.Sprintf("%s:0", cfg.LifecyclerConfig.ID)] = ing
+
+	// Streams are only handed off to ingesters that are active in the ring.
+	require.Eventually(t, func() bool {
+		return ing.lifecycler.GetState() == ring.ACTIVE
+	}, 5*time.Second, 10*time.Millisecond)
+	return store, ing
+}
+
+func TestStreamConsumeHandoff(t *testing.T) {
+	for _, unordered := range []bool{true, false} {
+		t.Run(fmt.Sprintf("unordered=%v", unordered), func(t *testing.T) {
+			limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+			require.NoError(t, err)
+			limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+			s := newStream(defaultConfig(), limiter, "fake", model.Fingerprint(0), labels.Labels{{Name: "foo", Value: "bar"}}, unordered, false, NewStreamRateCalculator(), NilMetrics)
+			_, err = s.Push(context.Background(), []logproto.Entry{{Timestamp: time.Unix(10, 0), Line: "pushed"}}, nil, 0, true, false, pushOptions{})
+			require.NoError(t, err)
+
+			// A chunk cut by the leaving ingester.
+			c := chunkenc.NewMemChunk(chunkenc.EncSnappy, chunkenc.UnorderedHeadBlockFmt, 1024, 0)
+			for i := 0; i < 3; i++ {
+				require.NoError(t, c.Append(&logproto.Entry{Timestamp: time.Unix(int64(i), 0), Line: "chunk"}))
+			}
+			require.NoError(t, c.Close())
+			b, err := c.Bytes()
+			require.NoError(t, err)
+
+			// And the entries of its head chunk, partly older than the head chunk of the stream.
+			head := []logproto.Entry{
+				{Timestamp: time.Unix(5, 0), Line: "head"},
+				{Timestamp: time.Unix(11, 0), Line: "head"},
+			}
+
+			record := recordPool.GetRecord()
+			s.chunkMtx.Lock()
+			err = s.consumeHandoff(context.Background(), []*logproto.Chunk{{Data: b}}, head, record)
+			s.chunkMtx.Unlock()
+			require.NoError(t, err)
+
+			require.False(t, s.chunks[len(s.chunks)-1].closed, "expected the head chunk to remain the last chunk")
+			for _, c := range s.chunks[:len(s.chunks)-1] {
+				require.True(t, c.closed)
+			}
+			require.Equal(t, int64(6), s.entryCt)
+			require.Equal(t, time.Unix(11, 0), s.highestTs)
+
+			var recorded int
+			for _, entries := range record.RefEntries {
+				recorded += len(entries.Entries)
+			}
+			require.Equal(t, 5, recorded, "expected the handed off entries to be written to the WAL")
+
+			it, err := s.Iterator(context.Background(), nil, time.Unix(0, 0), time.Unix(100, 0), logproto.FORWARD, log.NewNoopPipeline().ForStream(s.labels))
+			require.NoError(t, err)
+			var lines []string
+			for it.Next() {
+				lines = append(lines, it.Entry().Line)
+			}
+			require.NoError(t, it.Close())
+			require.Equal(t, []string{"chunk", "chunk", "chunk", "head", "pushed", "head"}, lines)
+		})
+	}
+}
+
+func (c *testIngesterClient) HandoffStreams(ctx context.Context, _ ...grpc.CallOption) (logproto.Ingester_HandoffStreamsClient, error) {
+	reqs := make(chan *logproto.HandoffStreamsRequest)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.i.HandoffStreams(&testHandoffStreamsServer{ctx: ctx, reqs: reqs})
+	}()
+	return &testHandoffStreamsClient{reqs: reqs, done: done}, nil
+}
+
+type testHandoffStreamsClient struct {
+	reqs chan *logproto.HandoffStreamsRequest
+	done chan error
+
+	grpc.ClientStream
+}
+
+func (c *testHandoffStreamsClient) Send(req *logproto.HandoffStreamsRequest) error {
+	c.reqs <- req
+	return nil
+}
+
+func (c *testHandoffStreamsClient) CloseAndRecv() (*logproto.HandoffStreamsResponse, error) {
+	close(c.reqs)
+	if err := <-c.done; err != nil {
+		return nil, err
+	}
+	return &logproto.HandoffStreamsResponse{}, nil
+}
+
+type testHandoffStreamsServer struct {
+	ctx  context.Context
+	reqs chan *logproto.HandoffStreamsRequest
+
+	grpc.ServerStream
+}
+
+func (s *testHandoffStreamsServer) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testHandoffStreamsServer) SendAndClose(*logproto.HandoffStreamsResponse) error {
+	return nil
+}
+
+func (s *testHandoffStreamsServer) Recv() (*logproto.HandoffStreamsRequest, error) {
+	select {
+	case req, ok := <-s.reqs:
+		if !ok {
+			return nil, io.EOF
+		}
+		return req, nil
+	case <-s.ctx.Done():
+		return nil, s.ctx.Err()
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
+	"github.com/weaveworks/common/httpgrpc"
 	"google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/grafana/loki/pkg/chunkenc"
@@ -97,6 +98,14 @@ type Config struct {
 
 	WAL WALConfig `yaml:"wal,omitempty" doc:"description=The ingester WAL (Write Ahead Log) records incoming logs and stores them on the local file systems in order to guarantee persistence of acknowledged data in the event of a process crash."`
 
+	MemoryPressure MemoryPressureConfig `yaml:"memory_pressure" doc:"description=Under memory pressure, the ingester spills cut chunks to local disk and rejects pushes before running out of memory."`
+
+	QueryLoadShedding QueryLoadSheddingConfig `yaml:"query_load_shedding" doc:"description=The ingester accounts for the memory held, the bytes read and the processing time of every query, and sheds queries to give priority to the write path. Shed queries fail with a retryable error, and the querier uses the other replicas."`
+
+	Handoff HandoffConfig `yaml:"handoff" doc:"description=On shutdown, a leaving ingester hands off its in-memory streams to the ingesters owning them once it left the ring, instead of flushing them."`
+
+	ContinuousAggregations ContinuousAggregationsConfig `yaml:"continuous_aggregations" doc:"description=The ingester evaluates the continuous aggregations of the tenants on the entries it receives, and periodically writes their values to streams of the tenants."`
+
 	ChunkFilterer chunk.RequestChunkFilterer `yaml:"-"`
 	// Optional wrapper that can be used to modify the behaviour of the ingester
 	Wrapper Wrapper `yaml:"-"`
@@ -104,12 +113,18 @@ type Config struct {
 	IndexShards int `yaml:"index_shards"`
 
 	MaxDroppedStreams int `yaml:"max_dropped_streams"`
+
+	DedupMaxLinesPerStream int `yaml:"dedup_max_lines_per_stream"`
 }
 
 // RegisterFlags registers the flags.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.LifecyclerConfig.RegisterFlags(f, util_log.Logger)
 	cfg.WAL.RegisterFlags(f)
+	cfg.MemoryPressure.RegisterFlags(f)
+	cfg.QueryLoadShedding.RegisterFlags(f)
+	cfg.Handoff.RegisterFlags(f)
+	cfg.ContinuousAggregations.RegisterFlags(f)
 
 	f.IntVar(&cfg.MaxTransferRetries, "ingester.max-transfer-retries", 0, "Number of times to try and transfer chunks before falling back to flushing. If set to 0 or negative value, transfers are disabled.")
 	f.IntVar(&cfg.ConcurrentFlushes, "ingester.concurrent-flushes", 32, "How many flushes can happen concurrently from each stream.")
@@ -128,6 +143,7 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.AutoForgetUnhealthy, "ingester.autoforget-unhealthy", false, "Forget about ingesters having heartbeat timestamps older than `ring.kvstore.heartbeat_timeout`. This is equivalent to clicking on the `/ring` `forget` button in the UI: the ingester is removed from the ring. This is a useful setting when you are sure that an unhealthy node won't return. An example is when not using stateful sets or the equivalent. Use `memberlist.rejoin_interval` > 0 to handle network partition cases when using a memberlist.")
 	f.IntVar(&cfg.IndexShards, "ingester.index-shards", index.DefaultIndexShards, "Shard factor used in the ingesters for the in process reverse index. This MUST be evenly divisible by ALL schema shard factors or Loki will not start.")
 	f.IntVar(&cfg.MaxDroppedStreams, "ingester.tailer.max-dropped-streams", 10, "Maximum number of dropped streams to keep in memory during tailing.")
+	f.IntVar(&cfg.DedupMaxLinesPerStream, "ingester.dedup-max-lines-per-stream", 10000, "Maximum number of distinct lines that each stream remembers to drop duplicates within the dedup_window of its tenant. Once a stream remembers that many lines, new lines are not remembered until old ones fall out of the window, so their duplicates are accepted. 0 to make unlimited.")
 }
 
 func (cfg *Config) Validate() error {
@@ -141,6 +157,26 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if err = cfg.MemoryPressure.Validate(); err != nil {
+		return err
+	}
+
+	if err = cfg.QueryLoadShedding.Validate(); err != nil {
+		return err
+	}
+
+	if err = cfg.Handoff.Validate(); err != nil {
+		return err
+	}
+
+	if err = cfg.ContinuousAggregations.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.MaxTransferRetries > 0 && cfg.Handoff.Enabled {
+		return errors.New("stream handoff replaces chunk transfers, please set ingester.max-transfer-retries to 0 to use it")
+	}
+
 	if cfg.MaxTransferRetries > 0 && cfg.WAL.Enabled {
 		return errors.New("the use of the write ahead log (WAL) is incompatible with chunk transfers. It's suggested to use the WAL. Please try setting ingester.max-transfer-retries to 0 to disable transfers")
 	}
@@ -174,6 +210,7 @@ type Interface interface {
 	logproto.PusherServer
 	logproto.QuerierServer
 	logproto.StreamDataServer
+	logproto.TenantVolumesServer
 
 	CheckReady(ctx context.Context) error
 	FlushHandler(w http.ResponseWriter, _ *http.Request)
@@ -182,6 +219,7 @@ type Interface interface {
 	LegacyShutdownHandler(w http.ResponseWriter, r *http.Request)
 	ShutdownHandler(w http.ResponseWriter, r *http.Request)
 	PrepareShutdown(w http.ResponseWriter, r *http.Request)
+	TopStreamsHandler(w http.ResponseWriter, r *http.Request)
 }
 
 // Ingester builds chunks for incoming log streams.
@@ -232,6 +270,15 @@ type Ingester struct {
 	chunkFilter chunk.RequestChunkFilterer
 
 	streamRateCalculator *StreamRateCalculator
+
+	tenantVolumes *TenantVolumes
+
+	backfillQueue *backfillQueue
+
+	// Only set when the memory pressure watermarks are configured.
+	memoryPressure *memoryPressureController
+
+	queryLoadShedder *queryLoadShedder
 }
 
 // New makes a new Ingester.
@@ -261,6 +308,8 @@ func New(cfg Config, clientConfig client.Config, store ChunkStore, limits Limits
 		flushOnShutdownSwitch: &OnceSwitch{},
 		terminateOnShutdown:   false,
 		streamRateCalculator:  NewStreamRateCalculator(),
+		tenantVolumes:         NewTenantVolumes(),
+		backfillQueue:         newBackfillQueue(),
 	}
 	i.replayController = newReplayController(metrics, cfg.WAL, &replayFlusher{i})
 
@@ -276,6 +325,27 @@ func New(cfg Config, clientConfig client.Config, store ChunkStore, limits Limits
 		}
 	}
 
+	if cfg.WAL.Enabled {
+		if err := i.tenantVolumes.Load(filepath.Join(cfg.WAL.Dir, tenantVolumesFile)); err != nil {
+			// The volumes are only used to enforce the ingestion quotas, so a corrupted file shouldn't prevent the ingester from starting.
+			level.Warn(util_log.Logger).Log("msg", "failed to load tenant volumes, counting from zero", "err", err)
+		}
+	}
+
+	if cfg.MemoryPressure.SpillWatermark > 0 {
+		// Chunks spilled before a restart are recovered from the WAL, so they can be removed.
+		if err := os.RemoveAll(cfg.MemoryPressure.SpillDirectory); err != nil {
+			return nil, fmt.Errorf("removing spill folder at %q: %w", cfg.MemoryPressure.SpillDirectory, err)
+		}
+		if err := os.MkdirAll(cfg.MemoryPressure.SpillDirectory, os.ModePerm); err != nil {
+			return nil, fmt.Errorf("creating spill folder at %q: %w", cfg.MemoryPressure.SpillDirectory, err)
+		}
+	}
+	if cfg.MemoryPressure.Enabled() {
+		i.memoryPressure = newMemoryPressureController(cfg.MemoryPressure, i.getInstances, metrics)
+	}
+	i.queryLoadShedder = newQueryLoadShedder(cfg.QueryLoadShedding, i.underBackpressure, metrics)
+
 	wal, err := newWAL(cfg.WAL, registerer, metrics, newIngesterSeriesIter(i))
 	if err != nil {
 		return nil, err
@@ -479,6 +549,20 @@ func (i *Ingester) starting(ctx context.Context) error {
 	// start our loop
 	i.loopDone.Add(1)
 	go i.loop()
+
+	if i.memoryPressure != nil {
+		i.loopDone.Add(1)
+		go i.memoryPressureLoop()
+	}
+
+	if i.cfg.ContinuousAggregations.Interval > 0 {
+		i.loopDone.Add(1)
+		go i.continuousAggregationsLoop()
+	}
+
+	// The backfill loop runs until the ingester stops accepting pushes.
+	i.backfillQueue.done.Add(1)
+	go i.backfillLoop()
 	return nil
 }
 
@@ -508,6 +592,7 @@ func (i *Ingester) running(ctx context.Context) error {
 // At this point, loop no longer runs, but flushers are still running.
 func (i *Ingester) stopping(_ error) error {
 	i.stopIncomingRequests()
+	i.backfillQueue.stop()
 	var errs errUtil.MultiError
 	errs.Add(i.wal.Stop())
 
@@ -524,6 +609,7 @@ func (i *Ingester) stopping(_ error) error {
 	i.flushQueuesDone.Wait()
 
 	i.streamRateCalculator.Stop()
+	i.saveTenantVolumes()
 
 	// In case the flag to terminate on shutdown is set or this instance is marked to release its resources,
 	// we need to mark the ingester service as "failed", so Loki will shut down entirely.
@@ -544,6 +630,24 @@ func (i *Ingester) loop() {
 		select {
 		case <-flushTicker.C:
 			i.sweepUsers(false, true)
+			i.saveTenantVolumes()
+
+		case <-i.loopQuit:
+			return
+		}
+	}
+}
+
+func (i *Ingester) memoryPressureLoop() {
+	defer i.loopDone.Done()
+
+	ticker := time.NewTicker(i.cfg.MemoryPressure.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			i.memoryPressure.check()
 
 		case <-i.loopQuit:
 			return
@@ -629,16 +733,33 @@ func (i *Ingester) Push(ctx context.Context, req *logproto.PushRequest) (*logpro
 		return nil, err
 	} else if i.readonly {
 		return nil, ErrReadOnly
+	} else if i.underBackpressure() {
+		return nil, httpgrpc.Errorf(http.StatusTooManyRequests, ErrMemoryPressure.Error())
 	}
+	defer i.queryLoadShedder.pushStarted()()
 
 	instance, err := i.GetOrCreateInstance(instanceID)
 	if err != nil {
 		return &logproto.PushResponse{}, err
 	}
 	err = instance.Push(ctx, req)
+	if err == nil {
+		var bytes int64
+		for _, s := range req.Streams {
+			for _, e := range s.Entries {
+				bytes += int64(len(e.Line))
+			}
+		}
+		i.tenantVolumes.Add(instanceID, bytes, time.Now())
+	}
 	return &logproto.PushResponse{}, err
 }
 
+// underBackpressure returns whether the heap of the ingester is above the memory pressure backpressure watermark.
+func (i *Ingester) underBackpressure() bool {
+	return i.memoryPressure != nil && i.memoryPressure.backpressure.Load()
+}
+
 // GetStreamRates returns a response containing all streams and their current rate
 // TODO: It might be nice for this to be human readable, eventually: Sort output and return labels, too?
 func (i *Ingester) GetStreamRates(_ context.Context, _ *logproto.StreamRatesRequest) (*logproto.StreamRatesResponse, error) {
@@ -651,12 +772,29 @@ func (i *Ingester) GetStreamRates(_ context.Context, _ *logproto.StreamRatesRequ
 			StreamHash:        r.StreamHash,
 			StreamHashNoShard: r.StreamHashNoShard,
 			Rate:              r.Rate,
+			EntriesRate:       r.EntriesRate,
 		})
 	}
 
 	return &logproto.StreamRatesResponse{StreamRates: rates}, nil
 }
 
+// saveTenantVolumes saves the tenant volumes to the WAL directory, so they survive a restart.
+func (i *Ingester) saveTenantVolumes() {
+	if !i.cfg.WAL.Enabled {
+		return
+	}
+	if err := i.tenantVolumes.Save(filepath.Join(i.cfg.WAL.Dir, tenantVolumesFile), time.Now()); err != nil {
+		level.Error(util_log.Logger).Log("msg", "failed to save tenant volumes", "err", err)
+	}
+}
+
+// GetTenantVolumes returns a response containing the bytes that each tenant pushed to this ingester
+// in the current day and month.
+func (i *Ingester) GetTenantVolumes(_ context.Context, _ *logproto.TenantVolumesRequest) (*logproto.TenantVolumesResponse, error) {
+	return &logproto.TenantVolumesResponse{TenantVolumes: i.tenantVolumes.Volumes(time.Now())}, nil
+}
+
 func (i *Ingester) GetOrCreateInstance(instanceID string) (*instance, error) { //nolint:revive
 	inst, ok := i.getInstanceByID(instanceID)
 	if ok {
@@ -672,6 +810,7 @@ func (i *Ingester) GetOrCreateInstance(instanceID string) (*instance, error) { /
 		if err != nil {
 			return nil, err
 		}
+		inst.backfiller = i
 		i.instances[instanceID] = inst
 		activeTenantsStats.Set(int64(len(i.instances)))
 	}
@@ -688,6 +827,12 @@ func (i *Ingester) Query(req *logproto.QueryRequest, queryServer logproto.Querie
 		return err
 	}
 
+	account, err := i.queryLoadShedder.admit()
+	if err != nil {
+		return err
+	}
+	defer account.done()
+
 	instance, err := i.GetOrCreateInstance(instanceID)
 	if err != nil {
 		return err
@@ -723,7 +868,7 @@ func (i *Ingester) Query(req *logproto.QueryRequest, queryServer logproto.Querie
 		batchLimit = -1
 	}
 
-	return sendBatches(ctx, it, queryServer, batchLimit)
+	return sendBatches(ctx, it, queryServer, batchLimit, account)
 }
 
 // QuerySample the ingesters for series from logs matching a set of matchers.
@@ -736,6 +881,12 @@ func (i *Ingester) QuerySample(req *logproto.SampleQueryRequest, queryServer log
 		return err
 	}
 
+	account, err := i.queryLoadShedder.admit()
+	if err != nil {
+		return err
+	}
+	defer account.done()
+
 	instance, err := i.GetOrCreateInstance(instanceID)
 	if err != nil {
 		return err
@@ -764,7 +915,7 @@ func (i *Ingester) QuerySample(req *logproto.SampleQueryRequest, queryServer log
 
 	defer errUtil.LogErrorWithContext(ctx, "closing iterator", it.Close)
 
-	return sendSampleBatches(ctx, it, queryServer)
+	return sendSampleBatches(ctx, it, queryServer, account)
 }
 
 // asyncStoreMaxLookBack returns a max look back period only if active index type is one of async index stores like `boltdb-shipper` and `tsdb`.
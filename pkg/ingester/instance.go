@@ -18,7 +18,9 @@ import (
 	tsdb_record "github.com/prometheus/prometheus/tsdb/record"
 	"github.com/weaveworks/common/httpgrpc"
 	"go.uber.org/atomic"
+	"golang.org/x/time/rate"
 
+	"github.com/grafana/loki/pkg/ingester/aggregations"
 	"github.com/grafana/loki/pkg/ingester/index"
 	"github.com/grafana/loki/pkg/ingester/wal"
 	"github.com/grafana/loki/pkg/iter"
@@ -103,6 +105,15 @@ type instance struct {
 
 	chunkFilter          chunk.RequestChunkFilterer
 	streamRateCalculator *StreamRateCalculator
+	backfiller           backfiller
+
+	// The continuous aggregations of the tenant that the accumulators of the streams were created for, and the end
+	// of the latest interval each was written through, by aggregationKey.
+	aggregations        []*aggregations.Aggregation
+	aggregationsCovered map[string]time.Time
+	aggregationsMtx     sync.RWMutex
+	// Only used by flushAggregations.
+	aggregationsLimiter *rate.Limiter
 }
 
 func newInstance(
@@ -183,6 +194,15 @@ func (i *instance) Push(ctx context.Context, req *logproto.PushRequest) error {
 	record.UserID = i.instanceID
 	defer recordPool.PutRecord(record)
 	rateLimitWholeStream := i.limiter.limits.ShardStreams(i.instanceID).Enabled
+	opts := pushOptions{
+		dedupWindow:      i.limiter.DedupWindow(i.instanceID),
+		outOfOrderWindow: i.limiter.limits.OutOfOrderWindow(i.instanceID),
+	}
+	if i.backfiller != nil && i.limiter.limits.OutOfOrderAction(i.instanceID) == validation.OutOfOrderBackfill {
+		opts.backfill = func(s *stream, entries []logproto.Entry) error {
+			return i.backfiller.enqueue(i.instanceID, s, entries)
+		}
+	}
 
 	var appendErr error
 	for _, reqStream := range req.Streams {
@@ -206,7 +226,7 @@ func (i *instance) Push(ctx context.Context, req *logproto.PushRequest) error {
 			continue
 		}
 
-		_, appendErr = s.Push(ctx, reqStream.Entries, record, 0, false, rateLimitWholeStream)
+		_, appendErr = s.Push(ctx, reqStream.Entries, record, 0, false, rateLimitWholeStream, opts)
 		s.chunkMtx.Unlock()
 	}
 
@@ -271,7 +291,8 @@ func (i *instance) createStream(pushReqStream logproto.Stream, record *wal.Recor
 	fp := i.getHashForLabels(labels)
 
 	sortedLabels := i.index.Add(logproto.FromLabelsToLabelAdapters(labels), fp)
-	s := newStream(i.cfg, i.limiter, i.instanceID, fp, sortedLabels, i.limiter.UnorderedWrites(i.instanceID), i.streamRateCalculator, i.metrics)
+	s := newStream(i.cfg, i.limiter, i.instanceID, fp, sortedLabels, i.limiter.UnorderedWrites(i.instanceID), i.limiter.AllowStructuredMetadata(i.instanceID), i.streamRateCalculator, i.metrics)
+	s.aggregations = i.newAccumulator(sortedLabels)
 
 	// record will be nil when replaying the wal (we don't want to rewrite wal entries as we replay them).
 	if record != nil {
@@ -303,7 +324,8 @@ func (i *instance) createStream(pushReqStream logproto.Stream, record *wal.Recor
 
 func (i *instance) createStreamByFP(ls labels.Labels, fp model.Fingerprint) *stream {
 	sortedLabels := i.index.Add(logproto.FromLabelsToLabelAdapters(ls), fp)
-	s := newStream(i.cfg, i.limiter, i.instanceID, fp, sortedLabels, i.limiter.UnorderedWrites(i.instanceID), i.streamRateCalculator, i.metrics)
+	s := newStream(i.cfg, i.limiter, i.instanceID, fp, sortedLabels, i.limiter.UnorderedWrites(i.instanceID), i.limiter.AllowStructuredMetadata(i.instanceID), i.streamRateCalculator, i.metrics)
+	s.aggregations = i.newAccumulator(sortedLabels)
 
 	i.streamsCreatedTotal.Inc()
 	memoryStreams.WithLabelValues(i.instanceID).Inc()
@@ -772,7 +794,9 @@ type QuerierQueryServer interface {
 	Send(res *logproto.QueryResponse) error
 }
 
-func sendBatches(ctx context.Context, i iter.EntryIterator, queryServer QuerierQueryServer, limit int32) error {
+// sendBatches sends the entries of the iterator in batches. The account, if any, accounts for every batch until it is
+// sent and can stop the query.
+func sendBatches(ctx context.Context, i iter.EntryIterator, queryServer QuerierQueryServer, limit int32, account *queryAccount) error {
 	stats := stats.FromContext(ctx)
 
 	// send until the limit is reached.
@@ -781,10 +805,14 @@ func sendBatches(ctx context.Context, i iter.EntryIterator, queryServer QuerierQ
 		if limit > 0 {
 			fetchSize = math.MinUint32(queryBatchSize, uint32(limit))
 		}
+		start := time.Now()
 		batch, batchSize, err := iter.ReadBatch(i, fetchSize)
 		if err != nil {
 			return err
 		}
+		if err := account.add(stats.Ingester(), int64(batch.Size()), time.Since(start)); err != nil {
+			return err
+		}
 
 		if limit > 0 {
 			limit -= int32(batchSize)
@@ -803,18 +831,23 @@ func sendBatches(ctx context.Context, i iter.EntryIterator, queryServer QuerierQ
 		if err := queryServer.Send(batch); err != nil && err != context.Canceled {
 			return err
 		}
+		account.sent()
 		stats.Reset()
 	}
 	return nil
 }
 
-func sendSampleBatches(ctx context.Context, it iter.SampleIterator, queryServer logproto.Querier_QuerySampleServer) error {
+func sendSampleBatches(ctx context.Context, it iter.SampleIterator, queryServer logproto.Querier_QuerySampleServer, account *queryAccount) error {
 	stats := stats.FromContext(ctx)
 	for !isDone(ctx) {
+		start := time.Now()
 		batch, size, err := iter.ReadSampleBatch(it, queryBatchSampleSize)
 		if err != nil {
 			return err
 		}
+		if err := account.add(stats.Ingester(), int64(batch.Size()), time.Since(start)); err != nil {
+			return err
+		}
 		if len(batch.Series) == 0 {
 			return nil
 		}
@@ -827,6 +860,7 @@ func sendSampleBatches(ctx context.Context, it iter.SampleIterator, queryServer
 		if err := queryServer.Send(batch); err != nil && err != context.Canceled {
 			return err
 		}
+		account.sent()
 
 		stats.Reset()
 
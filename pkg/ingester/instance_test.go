@@ -293,7 +293,7 @@ func setupTestStreams(t *testing.T) (*instance, time.Time, int) {
 	for _, testStream := range testStreams {
 		stream, err := instance.getOrCreateStream(testStream, recordPool.GetRecord())
 		require.NoError(t, err)
-		chunk := newStream(cfg, limiter, "fake", 0, nil, true, NewStreamRateCalculator(), NilMetrics).NewChunk()
+		chunk := newStream(cfg, limiter, "fake", 0, nil, true, false, NewStreamRateCalculator(), NilMetrics).NewChunk()
 		for _, entry := range testStream.Entries {
 			err = chunk.Append(&entry)
 			require.NoError(t, err)
@@ -546,7 +546,7 @@ func Benchmark_instance_addNewTailer(b *testing.B) {
 	lbs := makeRandomLabels()
 	b.Run("addTailersToNewStream", func(b *testing.B) {
 		for n := 0; n < b.N; n++ {
-			inst.addTailersToNewStream(newStream(nil, limiter, "fake", 0, lbs, true, NewStreamRateCalculator(), NilMetrics))
+			inst.addTailersToNewStream(newStream(nil, limiter, "fake", 0, lbs, true, false, NewStreamRateCalculator(), NilMetrics))
 		}
 	})
 }
@@ -601,7 +601,7 @@ func Test_Iterator(t *testing.T) {
 					return nil
 				},
 			),
-			int32(2)),
+			int32(2), nil),
 	)
 	require.Equal(t, 2, len(res.Streams))
 	// each entry translated into a unique stream
@@ -9,6 +9,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/grafana/loki/pkg/distributor/shardstreams"
+	"github.com/grafana/loki/pkg/ingester/aggregations"
 	"github.com/grafana/loki/pkg/validation"
 )
 
@@ -24,9 +25,19 @@ type RingCount interface {
 
 type Limits interface {
 	UnorderedWrites(userID string) bool
+	AllowStructuredMetadata(userID string) bool
 	MaxLocalStreamsPerUser(userID string) int
 	MaxGlobalStreamsPerUser(userID string) int
 	PerStreamRateLimit(userID string) validation.RateLimit
+	DedupWindow(userID string) time.Duration
+	OutOfOrderWindow(userID string) time.Duration
+	OutOfOrderAction(userID string) string
+	ContinuousAggregations(userID string) []*aggregations.Aggregation
+	MaxLabelNamesPerSeries(userID string) int
+	MaxLabelNameLength(userID string) int
+	MaxLabelValueLength(userID string) int
+	IngestionRateBytes(userID string) float64
+	IngestionBurstSizeBytes(userID string) int
 	ShardStreams(userID string) *shardstreams.Config
 }
 
@@ -76,6 +87,19 @@ func (l *Limiter) UnorderedWrites(userID string) bool {
 	return l.limits.UnorderedWrites(userID)
 }
 
+// DedupWindow returns the window within which duplicate lines of a stream are dropped.
+func (l *Limiter) DedupWindow(userID string) time.Duration {
+	// WAL replay should not drop previously ack'd writes.
+	if l.disabled {
+		return 0
+	}
+	return l.limits.DedupWindow(userID)
+}
+
+func (l *Limiter) AllowStructuredMetadata(userID string) bool {
+	return l.limits.AllowStructuredMetadata(userID)
+}
+
 // AssertMaxStreamsPerUser ensures limit has not been reached compared to the current
 // number of streams in input and returns an error if so.
 func (l *Limiter) AssertMaxStreamsPerUser(userID string, streams int) error {
@@ -0,0 +1,269 @@
+package ingester
+
+import (
+	"flag"
+	"os"
+	"runtime/metrics"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/tsdb/fileutil"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/util/flagext"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+type MemoryPressureConfig struct {
+	SpillWatermark        flagext.ByteSize `yaml:"spill_watermark"`
+	BackpressureWatermark flagext.ByteSize `yaml:"backpressure_watermark"`
+	SpillDirectory        string           `yaml:"spill_directory"`
+	CheckInterval         time.Duration    `yaml:"check_interval"`
+}
+
+// RegisterFlags adds the flags required to config this to the given FlagSet
+func (cfg *MemoryPressureConfig) RegisterFlags(f *flag.FlagSet) {
+	f.Var(&cfg.SpillWatermark, "ingester.memory-pressure.spill-watermark", "Heap size above which the ingester spills the cut chunks that are waiting to be flushed to the spill directory. Spilled chunks remain queryable and are read from disk. A unit suffix (KB, MB, GB) may be applied. 0 to disable.")
+	f.Var(&cfg.BackpressureWatermark, "ingester.memory-pressure.backpressure-watermark", "Heap size above which the ingester rejects pushes with a 429 status code until its heap is below it again. A unit suffix (KB, MB, GB) may be applied. 0 to disable.")
+	f.StringVar(&cfg.SpillDirectory, "ingester.memory-pressure.spill-dir", "spill", "Directory where chunks are spilled to under memory pressure. It is emptied on startup.")
+	f.DurationVar(&cfg.CheckInterval, "ingester.memory-pressure.check-interval", time.Second, "How often the ingester checks its heap size against the memory pressure watermarks.")
+}
+
+func (cfg *MemoryPressureConfig) Validate() error {
+	if !cfg.Enabled() {
+		return nil
+	}
+	if cfg.CheckInterval <= 0 {
+		return errors.Errorf("invalid memory pressure check interval: %v", cfg.CheckInterval)
+	}
+	if cfg.SpillWatermark > 0 && cfg.SpillDirectory == "" {
+		return errors.New("a memory pressure spill directory is required to spill chunks")
+	}
+	if cfg.SpillWatermark > 0 && cfg.BackpressureWatermark > 0 && cfg.BackpressureWatermark < cfg.SpillWatermark {
+		return errors.New("the memory pressure backpressure watermark must not be lower than the spill watermark")
+	}
+	return nil
+}
+
+func (cfg *MemoryPressureConfig) Enabled() bool {
+	return cfg.SpillWatermark > 0 || cfg.BackpressureWatermark > 0
+}
+
+// ErrMemoryPressure is returned by Push while the heap of the ingester is above the backpressure watermark.
+var ErrMemoryPressure = errors.New("ingester is under memory pressure, please retry later")
+
+// memoryPressureController checks the heap size of the ingester. Above the spill watermark it spills cut chunks
+// to disk, and above the backpressure watermark it rejects pushes.
+type memoryPressureController struct {
+	cfg       MemoryPressureConfig
+	instances func() []*instance
+	heap      func() (inUse uint64, numGC uint32)
+	metrics   *ingesterMetrics
+
+	backpressure atomic.Bool
+
+	// The bytes spilled since the last GC. They are still accounted for in the heap size until the next GC,
+	// so they are subtracted from it to not spill more than needed.
+	spilledSinceGC int64
+	lastNumGC      uint32
+}
+
+func newMemoryPressureController(cfg MemoryPressureConfig, instances func() []*instance, metrics *ingesterMetrics) *memoryPressureController {
+	return &memoryPressureController{
+		cfg:       cfg,
+		instances: instances,
+		heap:      readHeapInUse,
+		metrics:   metrics,
+	}
+}
+
+// readHeapInUse returns the bytes of the heap spans in use and the number of completed GC cycles.
+// It reads runtime/metrics, which unlike runtime.ReadMemStats doesn't stop the world.
+func readHeapInUse() (uint64, uint32) {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/heap/objects:bytes"},
+		{Name: "/memory/classes/heap/unused:bytes"},
+		{Name: "/gc/cycles/total:gc-cycles"},
+	}
+	metrics.Read(samples)
+
+	var values [3]uint64
+	for j, sample := range samples {
+		if sample.Value.Kind() == metrics.KindUint64 {
+			values[j] = sample.Value.Uint64()
+		}
+	}
+	return values[0] + values[1], uint32(values[2])
+}
+
+func (c *memoryPressureController) check() {
+	inUse, numGC := c.heap()
+	if numGC != c.lastNumGC {
+		c.lastNumGC, c.spilledSinceGC = numGC, 0
+	}
+	heap := int64(inUse) - c.spilledSinceGC
+
+	if c.cfg.BackpressureWatermark > 0 {
+		backpressure := heap > int64(c.cfg.BackpressureWatermark)
+		if c.backpressure.Swap(backpressure) != backpressure {
+			level.Warn(util_log.Logger).Log("msg", "ingester memory pressure backpressure changed", "enabled", backpressure, "heap", heap, "watermark", c.cfg.BackpressureWatermark)
+			if backpressure {
+				c.metrics.memoryPressureBackpressure.Set(1)
+			} else {
+				c.metrics.memoryPressureBackpressure.Set(0)
+			}
+		}
+	}
+
+	if c.cfg.SpillWatermark > 0 && heap > int64(c.cfg.SpillWatermark) {
+		spilled := c.spill(heap - int64(c.cfg.SpillWatermark))
+		c.spilledSinceGC += spilled
+	}
+}
+
+// spill spills cut chunks of all streams until at least the given number of bytes were spilled, and returns
+// the number of bytes spilled.
+func (c *memoryPressureController) spill(bytes int64) int64 {
+	var spilled int64
+	for _, instance := range c.instances() {
+		_ = instance.streams.ForEach(func(s *stream) (bool, error) {
+			n, err := s.spillChunks(c.cfg.SpillDirectory, bytes-spilled, c.metrics)
+			spilled += n
+			if err != nil {
+				level.Error(util_log.Logger).Log("msg", "failed to spill chunks", "tenant", instance.instanceID, "stream", s.labelsString, "err", err)
+				return false, err
+			}
+			return spilled < bytes, nil
+		})
+		if spilled >= bytes {
+			break
+		}
+	}
+	return spilled
+}
+
+// spilledChunk is the memory mapped file a chunk was spilled to. The mapping is released once
+// the chunk was removed from its stream and no query is reading it anymore.
+type spilledChunk struct {
+	mtx     sync.Mutex
+	file    *fileutil.MmapFile
+	refs    int
+	removed bool
+
+	metrics *ingesterMetrics
+}
+
+// spillChunk writes the chunk to a file in dir, and returns the chunk read back from its memory mapping.
+func spillChunk(dir string, c *chunkenc.MemChunk, blockSize, targetSize int, metrics *ingesterMetrics) (*chunkenc.MemChunk, *spilledChunk, error) {
+	// Close cuts the head block, so that it is part of the spilled bytes.
+	if err := c.Close(); err != nil {
+		return nil, nil, err
+	}
+	b, err := c.Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.CreateTemp(dir, "chunk-")
+	if err != nil {
+		return nil, nil, err
+	}
+	name := f.Name()
+	// The mapping keeps the data of the file until it is unmapped, so the file doesn't need to be kept around.
+	defer os.Remove(name)
+
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := fileutil.OpenMmapFile(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	mc, err := chunkenc.NewByteChunk(file.Bytes(), blockSize, targetSize)
+	if err != nil {
+		_ = file.Close()
+		return nil, nil, err
+	}
+
+	metrics.memoryPressureSpilledChunks.Inc()
+	metrics.memoryPressureSpilledBytes.Add(float64(len(b)))
+	return mc, &spilledChunk{file: file, metrics: metrics}, nil
+}
+
+// acquire prevents the mapping from being released while a query reads the chunk.
+func (c *spilledChunk) acquire() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.refs++
+}
+
+func (c *spilledChunk) release() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.refs--
+	c.closeIfUnused()
+}
+
+// remove releases the mapping once no query reads the chunk anymore.
+func (c *spilledChunk) remove() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.removed = true
+	c.closeIfUnused()
+}
+
+func (c *spilledChunk) closeIfUnused() {
+	if !c.removed || c.refs > 0 || c.file == nil {
+		return
+	}
+	c.metrics.memoryPressureSpilledBytes.Sub(float64(len(c.file.Bytes())))
+	if err := c.file.Close(); err != nil {
+		level.Warn(util_log.Logger).Log("msg", "failed to unmap spilled chunk", "err", err)
+	}
+	c.file = nil
+}
+
+// spilledEntryIterator releases the spilled chunk it reads once it is closed.
+type spilledEntryIterator struct {
+	iter.EntryIterator
+	release sync.Once
+	chunk   *spilledChunk
+}
+
+func newSpilledEntryIterator(it iter.EntryIterator, chunk *spilledChunk) iter.EntryIterator {
+	chunk.acquire()
+	return &spilledEntryIterator{EntryIterator: it, chunk: chunk}
+}
+
+func (it *spilledEntryIterator) Close() error {
+	err := it.EntryIterator.Close()
+	it.release.Do(it.chunk.release)
+	return err
+}
+
+// spilledSampleIterator releases the spilled chunk it reads once it is closed.
+type spilledSampleIterator struct {
+	iter.SampleIterator
+	release sync.Once
+	chunk   *spilledChunk
+}
+
+func newSpilledSampleIterator(it iter.SampleIterator, chunk *spilledChunk) iter.SampleIterator {
+	chunk.acquire()
+	return &spilledSampleIterator{SampleIterator: it, chunk: chunk}
+}
+
+func (it *spilledSampleIterator) Close() error {
+	err := it.SampleIterator.Close()
+	it.release.Do(it.chunk.release)
+	return err
+}
@@ -0,0 +1,179 @@
+package ingester
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func newSpillTestStream(t *testing.T) *stream {
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+	return newStream(
+		defaultConfig(),
+		limiter,
+		"fake",
+		model.Fingerprint(0),
+		labels.Labels{
+			{Name: "foo", Value: "bar"},
+		},
+		true,
+		false,
+		NewStreamRateCalculator(),
+		NilMetrics,
+	)
+}
+
+func TestStreamSpillChunks(t *testing.T) {
+	s := newSpillTestStream(t)
+	dir := t.TempDir()
+
+	var entries []logproto.Entry
+	for i := 0; i < 10; i++ {
+		entries = append(entries, logproto.Entry{Timestamp: time.Unix(int64(i), 0), Line: "line"})
+	}
+	_, err := s.Push(context.Background(), entries[:5], nil, 0, true, false, pushOptions{})
+	require.NoError(t, err)
+	s.cutChunk(context.Background())
+	_, err = s.Push(context.Background(), entries[5:], nil, 0, true, false, pushOptions{})
+	require.NoError(t, err)
+
+	spilled, err := s.spillChunks(dir, 1, NilMetrics)
+	require.NoError(t, err)
+	require.Greater(t, spilled, int64(0))
+	require.Len(t, s.chunks, 2)
+	require.NotNil(t, s.chunks[0].spill, "expected the cut chunk to be spilled")
+	require.Nil(t, s.chunks[1].spill, "expected the active chunk to remain in memory")
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, files, "expected the spilled files to only be kept by their mapping")
+
+	// Spilled chunks remain queryable, and are unmapped once removed and no longer queried.
+	it, err := s.Iterator(context.Background(), nil, time.Unix(0, 0), time.Unix(100, 0), logproto.FORWARD, log.NewNoopPipeline().ForStream(s.labels))
+	require.NoError(t, err)
+	spill := s.chunks[0].spill
+	spill.remove()
+	require.NotNil(t, spill.file, "expected the mapping to be kept while it is queried")
+	iterEq(t, entries, it)
+	require.NoError(t, it.Close())
+	require.Nil(t, spill.file)
+
+	// Chunks are only spilled once.
+	spilled, err = s.spillChunks(dir, 1, NilMetrics)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), spilled)
+}
+
+func TestStreamSpillChunksSkipsFlushingChunks(t *testing.T) {
+	s := newSpillTestStream(t)
+
+	_, err := s.Push(context.Background(), []logproto.Entry{{Timestamp: time.Unix(1, 0), Line: "line"}}, nil, 0, true, false, pushOptions{})
+	require.NoError(t, err)
+	s.cutChunk(context.Background())
+	s.chunks[0].reason = flushReasonFull
+	s.chunks[0].flushing = true
+
+	spilled, err := s.spillChunks(t.TempDir(), 1, NilMetrics)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), spilled)
+	require.Nil(t, s.chunks[0].spill)
+
+	// Chunks that are only queued for a flush are spilled.
+	s.chunks[0].flushing = false
+	spilled, err = s.spillChunks(t.TempDir(), 1, NilMetrics)
+	require.NoError(t, err)
+	require.Greater(t, spilled, int64(0))
+	require.NotNil(t, s.chunks[0].spill)
+}
+
+func TestFlushChunksMarksChunksAsFlushing(t *testing.T) {
+	s := newSpillTestStream(t)
+
+	_, err := s.Push(context.Background(), []logproto.Entry{{Timestamp: time.Unix(1, 0), Line: "line"}}, nil, 0, true, false, pushOptions{})
+	require.NoError(t, err)
+	s.cutChunk(context.Background())
+
+	// The flush fails after it closed the chunk, which releases it for spilling again.
+	i := &Ingester{cfg: *defaultConfig(), metrics: NilMetrics}
+	ctx, cancel := context.WithCancel(user.InjectOrgID(context.Background(), "fake"))
+	cancel()
+	require.Error(t, i.flushChunks(ctx, 0, s.labels, []*chunkDesc{&s.chunks[0]}, &s.chunkMtx))
+	require.False(t, s.chunks[0].flushing)
+
+	spilled, err := s.spillChunks(t.TempDir(), 1, NilMetrics)
+	require.NoError(t, err)
+	require.Greater(t, spilled, int64(0))
+}
+
+func TestReadHeapInUse(t *testing.T) {
+	inUse, _ := readHeapInUse()
+	require.Greater(t, inUse, uint64(0))
+}
+
+func TestMemoryPressureController(t *testing.T) {
+	s := newSpillTestStream(t)
+	_, err := s.Push(context.Background(), []logproto.Entry{{Timestamp: time.Unix(1, 0), Line: "line"}}, nil, 0, true, false, pushOptions{})
+	require.NoError(t, err)
+	s.cutChunk(context.Background())
+
+	inst := &instance{instanceID: "fake", streams: newStreamsMap()}
+	inst.streams.Store(s.labelsString, s)
+
+	var heap uint64
+	c := newMemoryPressureController(MemoryPressureConfig{
+		SpillWatermark:        100,
+		BackpressureWatermark: 1000,
+		SpillDirectory:        t.TempDir(),
+	}, func() []*instance { return []*instance{inst} }, NilMetrics)
+	c.heap = func() (uint64, uint32) { return heap, 1 }
+
+	heap = 50
+	c.check()
+	require.False(t, c.backpressure.Load())
+	require.Nil(t, s.chunks[0].spill)
+
+	heap = 2000
+	c.check()
+	require.True(t, c.backpressure.Load())
+	require.NotNil(t, s.chunks[0].spill)
+
+	heap = 500
+	c.check()
+	require.False(t, c.backpressure.Load())
+}
+
+func TestMemoryPressureConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		cfg  MemoryPressureConfig
+		err  bool
+	}{
+		{name: "disabled", cfg: MemoryPressureConfig{}},
+		{name: "valid", cfg: MemoryPressureConfig{SpillWatermark: 100, BackpressureWatermark: 200, SpillDirectory: "spill", CheckInterval: time.Second}},
+		{name: "no spill directory", cfg: MemoryPressureConfig{SpillWatermark: 100, CheckInterval: time.Second}, err: true},
+		{name: "no check interval", cfg: MemoryPressureConfig{BackpressureWatermark: 100}, err: true},
+		{name: "backpressure below spill", cfg: MemoryPressureConfig{SpillWatermark: 200, BackpressureWatermark: 100, SpillDirectory: "spill", CheckInterval: time.Second}, err: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
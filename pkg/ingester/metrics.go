@@ -25,6 +25,9 @@ type ingesterMetrics struct {
 	walLoggedBytesTotal     prometheus.Counter
 	walRecordsLogged        prometheus.Counter
 
+	walUncompressedBytesTotal        prometheus.Counter
+	checkpointUncompressedBytesTotal prometheus.Counter
+
 	recoveredStreamsTotal prometheus.Counter
 	recoveredChunksTotal  prometheus.Counter
 	recoveredEntriesTotal prometheus.Counter
@@ -59,6 +62,31 @@ type ingesterMetrics struct {
 	samplesPerChunk    prometheus.Histogram
 	blocksPerChunk     prometheus.Histogram
 	chunkCreatedStats  *usagestats.Counter
+
+	dedupSuppressedEntries *prometheus.CounterVec
+	dedupSuppressedBytes   *prometheus.CounterVec
+	dedupUntrackedLines    *prometheus.CounterVec
+
+	memoryPressureSpilledChunks prometheus.Counter
+	memoryPressureSpilledBytes  prometheus.Gauge
+	memoryPressureBackpressure  prometheus.Gauge
+
+	queriesShed            *prometheus.CounterVec
+	queryHeldBytes         prometheus.Gauge
+	queryBytes             prometheus.Histogram
+	queryProcessingSeconds prometheus.Histogram
+
+	handoffSentChunks      prometheus.Counter
+	handoffSentEntries     prometheus.Counter
+	handoffReceivedChunks  prometheus.Counter
+	handoffReceivedEntries prometheus.Counter
+
+	continuousAggregationSamples     *prometheus.CounterVec
+	continuousAggregationLateEntries *prometheus.CounterVec
+
+	backfilledEntries     *prometheus.CounterVec
+	backfilledBytes       *prometheus.CounterVec
+	backfillFailedEntries *prometheus.CounterVec
 }
 
 // setRecoveryBytesInUse bounds the bytes reports to >= 0.
@@ -136,6 +164,14 @@ func newIngesterMetrics(r prometheus.Registerer) *ingesterMetrics {
 			Name: "loki_ingester_wal_logged_bytes_total",
 			Help: "Total number of bytes written to disk for WAL records.",
 		}),
+		walUncompressedBytesTotal: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name: "loki_ingester_wal_uncompressed_bytes_total",
+			Help: "Total number of bytes of WAL records before compression. Divided by loki_ingester_wal_logged_bytes_total, it is the compression ratio of the WAL.",
+		}),
+		checkpointUncompressedBytesTotal: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name: "loki_ingester_checkpoint_uncompressed_bytes_total",
+			Help: "Total number of bytes of checkpoint records before compression. Divided by loki_ingester_checkpoint_logged_bytes_total, it is the compression ratio of checkpoints.",
+		}),
 		recoveredStreamsTotal: promauto.With(r).NewCounter(prometheus.CounterOpts{
 			Name: "loki_ingester_wal_recovered_streams_total",
 			Help: "Total number of streams recovered from the WAL.",
@@ -267,5 +303,104 @@ func newIngesterMetrics(r prometheus.Registerer) *ingesterMetrics {
 		}),
 
 		chunkCreatedStats: usagestats.NewCounter("ingester_chunk_created"),
+
+		dedupSuppressedEntries: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_dedup_suppressed_entries_total",
+			Help:      "The total number of entries dropped because their line already appeared in the stream within the dedup window.",
+		}, []string{"tenant"}),
+		dedupSuppressedBytes: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_dedup_suppressed_bytes_total",
+			Help:      "The total number of bytes of the entries dropped because their line already appeared in the stream within the dedup window.",
+		}, []string{"tenant"}),
+		dedupUntrackedLines: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_dedup_untracked_lines_total",
+			Help:      "The total number of lines that were not remembered for dedup because their stream already remembers the maximum number of lines.",
+		}, []string{"tenant"}),
+
+		memoryPressureSpilledChunks: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_memory_pressure_spilled_chunks_total",
+			Help:      "The total number of chunks spilled to disk under memory pressure.",
+		}),
+		memoryPressureSpilledBytes: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: "loki",
+			Name:      "ingester_memory_pressure_spilled_bytes",
+			Help:      "The size of the chunks currently spilled to disk.",
+		}),
+		memoryPressureBackpressure: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: "loki",
+			Name:      "ingester_memory_pressure_backpressure",
+			Help:      "Whether the ingester rejects pushes because its heap is above the backpressure watermark.",
+		}),
+		queriesShed: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_queries_shed_total",
+			Help:      "The total number of queries rejected or stopped by the ingester query load shedding.",
+		}, []string{"reason"}),
+		queryHeldBytes: promauto.With(r).NewGauge(prometheus.GaugeOpts{
+			Namespace: "loki",
+			Name:      "ingester_query_held_bytes",
+			Help:      "The number of bytes held in memory by the batches of the running queries which are not sent yet.",
+		}),
+		queryBytes: promauto.With(r).NewHistogram(prometheus.HistogramOpts{
+			Namespace: "loki",
+			Name:      "ingester_query_bytes",
+			Help:      "The number of bytes read from the chunks of the ingester per query.",
+			Buckets:   prometheus.ExponentialBuckets(1024, 4, 10),
+		}),
+		queryProcessingSeconds: promauto.With(r).NewHistogram(prometheus.HistogramOpts{
+			Namespace: "loki",
+			Name:      "ingester_query_processing_seconds",
+			Help:      "The time spent reading and filtering entries per query, excluding the time spent sending them to the querier.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		handoffSentChunks: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_handoff_sent_chunks_total",
+			Help:      "The total number of cut chunks handed off by this ingester whilst leaving.",
+		}),
+		handoffSentEntries: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_handoff_sent_entries_total",
+			Help:      "The total number of head chunk entries handed off by this ingester whilst leaving.",
+		}),
+		handoffReceivedChunks: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_handoff_received_chunks_total",
+			Help:      "The total number of cut chunks received from leaving ingesters.",
+		}),
+		handoffReceivedEntries: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_handoff_received_entries_total",
+			Help:      "The total number of head chunk entries received from leaving ingesters.",
+		}),
+		continuousAggregationSamples: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_continuous_aggregation_samples_total",
+			Help:      "The total number of continuous aggregation values written per tenant.",
+		}, []string{"tenant"}),
+		continuousAggregationLateEntries: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_continuous_aggregation_late_entries_total",
+			Help:      "The total number of entries per tenant that were not aggregated because the values of their interval were already written.",
+		}, []string{"tenant"}),
+		backfilledEntries: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_backfilled_entries_total",
+			Help:      "The total number of entries per tenant that were older than the out-of-order window and written directly to storage.",
+		}, []string{"tenant"}),
+		backfilledBytes: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_backfilled_bytes_total",
+			Help:      "The total number of bytes of the lines per tenant that were older than the out-of-order window and written directly to storage.",
+		}, []string{"tenant"}),
+		backfillFailedEntries: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "ingester_backfill_failed_entries_total",
+			Help:      "The total number of entries per tenant that were older than the out-of-order window and could not be written to storage.",
+		}, []string{"tenant"}),
 	}
 }
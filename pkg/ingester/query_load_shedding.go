@@ -0,0 +1,231 @@
+package ingester
+
+import (
+	"flag"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/weaveworks/common/httpgrpc"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/pkg/util/flagext"
+)
+
+type QueryLoadSheddingConfig struct {
+	MaxQueryBytes          flagext.ByteSize `yaml:"max_query_bytes"`
+	MaxQueryProcessingTime time.Duration    `yaml:"max_query_processing_time"`
+	MemoryBudget           flagext.ByteSize `yaml:"memory_budget"`
+	MaxInflightPushes      int              `yaml:"max_inflight_pushes"`
+}
+
+// RegisterFlags adds the flags required to config this to the given FlagSet
+func (cfg *QueryLoadSheddingConfig) RegisterFlags(f *flag.FlagSet) {
+	f.Var(&cfg.MaxQueryBytes, "ingester.query-load-shedding.max-query-bytes", "Maximum number of bytes a single query may read from the chunks of the ingester. Queries reading more fail. A unit suffix (KB, MB, GB) may be applied. 0 to disable.")
+	f.DurationVar(&cfg.MaxQueryProcessingTime, "ingester.query-load-shedding.max-query-processing-time", 0, "Maximum time a single query may spend reading and filtering the entries of the ingester, not counting the time spent sending them to the querier. Queries processing for longer fail. 0 to disable.")
+	f.Var(&cfg.MemoryBudget, "ingester.query-load-shedding.memory-budget", "Maximum number of bytes that all the running queries together may hold in memory, from the time a batch is read until it is sent to the querier. Above it, new queries are rejected and the query holding the most memory is stopped with a retryable error, so that the querier uses another replica. A unit suffix (KB, MB, GB) may be applied. 0 to disable.")
+	f.IntVar(&cfg.MaxInflightPushes, "ingester.query-load-shedding.max-inflight-pushes", 0, "Number of in-flight pushes above which the ingester gives priority to the write path: new queries are rejected and the query with the longest processing time is stopped with a retryable error. 0 to disable.")
+}
+
+func (cfg *QueryLoadSheddingConfig) Validate() error {
+	if cfg.MaxInflightPushes < 0 {
+		return errors.Errorf("invalid query load shedding max in-flight pushes: %d", cfg.MaxInflightPushes)
+	}
+	if cfg.MaxQueryProcessingTime < 0 {
+		return errors.Errorf("invalid query load shedding max query processing time: %s", cfg.MaxQueryProcessingTime)
+	}
+	return nil
+}
+
+var (
+	// ErrQueryLoadShed is returned by Query and QuerySample when the ingester sheds query load. It is retryable, either
+	// on another replica or later on the same one.
+	ErrQueryLoadShed = errors.New("ingester is shedding query load, please retry on another replica")
+
+	errQueryTooLarge     = "query exceeded the maximum number of bytes read from an ingester (limit: %s), please narrow the query"
+	errQueryTooExpensive = "query exceeded the maximum processing time on an ingester (limit: %s), please narrow the query"
+)
+
+// Reasons the queries are shed for.
+const (
+	shedReasonWritePriority     = "write_priority"
+	shedReasonMemoryPressure    = "memory_pressure"
+	shedReasonMemoryBudget      = "memory_budget"
+	shedReasonQueryTooLarge     = "query_too_large"
+	shedReasonQueryTooExpensive = "query_too_expensive"
+)
+
+// queryLoadShedder accounts for the memory held, the bytes read and the processing time of the queries of the
+// ingester, and sheds queries to protect the write path. Whilst the ingester is short of resources, new queries are
+// rejected and the running query consuming the most of the resource is stopped.
+type queryLoadShedder struct {
+	cfg          QueryLoadSheddingConfig
+	backpressure func() bool
+	metrics      *ingesterMetrics
+
+	inflightPushes atomic.Int64
+
+	mtx       sync.Mutex
+	accounts  map[*queryAccount]struct{}
+	heldBytes int64
+}
+
+func newQueryLoadShedder(cfg QueryLoadSheddingConfig, backpressure func() bool, metrics *ingesterMetrics) *queryLoadShedder {
+	return &queryLoadShedder{
+		cfg:          cfg,
+		backpressure: backpressure,
+		metrics:      metrics,
+		accounts:     map[*queryAccount]struct{}{},
+	}
+}
+
+// pushStarted records an in-flight push, the returned function must be called once it is done.
+func (s *queryLoadShedder) pushStarted() func() {
+	s.inflightPushes.Inc()
+	return func() { s.inflightPushes.Dec() }
+}
+
+// shouldShed returns the reason to shed the queries of the ingester for, if any. It must be called with mtx held.
+func (s *queryLoadShedder) shouldShed() string {
+	if s.cfg.MaxInflightPushes > 0 && s.inflightPushes.Load() > int64(s.cfg.MaxInflightPushes) {
+		return shedReasonWritePriority
+	}
+	if s.backpressure() {
+		return shedReasonMemoryPressure
+	}
+	if s.cfg.MemoryBudget > 0 && s.heldBytes > int64(s.cfg.MemoryBudget) {
+		return shedReasonMemoryBudget
+	}
+	return ""
+}
+
+// admit starts accounting for a new query, or rejects it if the ingester is shedding query load.
+func (s *queryLoadShedder) admit() (*queryAccount, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if reason := s.shouldShed(); reason != "" {
+		return nil, s.shed(reason)
+	}
+	a := &queryAccount{shedder: s}
+	s.accounts[a] = struct{}{}
+	return a, nil
+}
+
+// shedLargestConsumer marks the running query consuming the most of the resource the ingester is short of to be
+// stopped: the processing time when the write path needs priority, the memory held otherwise. Only one query is stopped
+// at a time, another one is picked once it is done if the ingester is still short. It must be called with mtx held.
+func (s *queryLoadShedder) shedLargestConsumer(reason string) {
+	var largest *queryAccount
+	for a := range s.accounts {
+		if a.shedReason != "" {
+			return
+		}
+		if largest == nil || a.consumesMore(largest, reason) {
+			largest = a
+		}
+	}
+	if largest != nil {
+		largest.shedReason = reason
+	}
+}
+
+func (s *queryLoadShedder) shed(reason string) error {
+	s.metrics.queriesShed.WithLabelValues(reason).Inc()
+	switch reason {
+	case shedReasonQueryTooLarge:
+		return httpgrpc.Errorf(http.StatusRequestEntityTooLarge, errQueryTooLarge, s.cfg.MaxQueryBytes)
+	case shedReasonQueryTooExpensive:
+		return httpgrpc.Errorf(http.StatusRequestEntityTooLarge, errQueryTooExpensive, s.cfg.MaxQueryProcessingTime)
+	}
+	return httpgrpc.Errorf(http.StatusServiceUnavailable, ErrQueryLoadShed.Error())
+}
+
+// queryAccount holds the resources used by a query. The memory held by a batch is reserved from the memory budget of
+// the ingester from the time it is read until it is sent. All the fields are guarded by the mtx of the shedder.
+type queryAccount struct {
+	shedder    *queryLoadShedder
+	heldBytes  int64
+	readBytes  int64
+	processing time.Duration
+	// the reason the query must stop for, when it was picked to be shed.
+	shedReason string
+}
+
+// consumesMore returns whether the query consumes more than the other one of the resource the ingester is short of.
+func (a *queryAccount) consumesMore(other *queryAccount, reason string) bool {
+	if reason == shedReasonWritePriority {
+		return a.processing > other.processing
+	}
+	if a.heldBytes != other.heldBytes {
+		return a.heldBytes > other.heldBytes
+	}
+	return a.readBytes > other.readBytes
+}
+
+// add accounts for a batch of the query which holds heldBytes until it is sent, with the statistics collected and the
+// time spent whilst reading it. It returns an error if the query must stop.
+func (a *queryAccount) add(batch stats.Ingester, heldBytes int64, processing time.Duration) error {
+	if a == nil {
+		return nil
+	}
+	s := a.shedder
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	a.readBytes += batch.Store.Chunk.HeadChunkBytes + batch.Store.Chunk.DecompressedBytes
+	a.processing += processing
+	s.heldBytes += heldBytes - a.heldBytes
+	a.heldBytes = heldBytes
+	s.metrics.queryHeldBytes.Set(float64(s.heldBytes))
+
+	if max := s.cfg.MaxQueryBytes; max > 0 && a.readBytes > int64(max) {
+		return s.shed(shedReasonQueryTooLarge)
+	}
+	if max := s.cfg.MaxQueryProcessingTime; max > 0 && a.processing > max {
+		return s.shed(shedReasonQueryTooExpensive)
+	}
+	if reason := s.shouldShed(); reason != "" {
+		s.shedLargestConsumer(reason)
+	}
+	if a.shedReason != "" {
+		return s.shed(a.shedReason)
+	}
+	return nil
+}
+
+// sent releases the memory held by the last batch of the query, once it was sent.
+func (a *queryAccount) sent() {
+	if a == nil {
+		return
+	}
+	s := a.shedder
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.release(a)
+}
+
+// done releases the memory held by the query and records its usage.
+func (a *queryAccount) done() {
+	if a == nil {
+		return
+	}
+	s := a.shedder
+	s.mtx.Lock()
+	s.release(a)
+	delete(s.accounts, a)
+	s.mtx.Unlock()
+
+	s.metrics.queryBytes.Observe(float64(a.readBytes))
+	s.metrics.queryProcessingSeconds.Observe(a.processing.Seconds())
+}
+
+// release releases the memory held by the query. It must be called with mtx held.
+func (s *queryLoadShedder) release(a *queryAccount) {
+	s.heldBytes -= a.heldBytes
+	a.heldBytes = 0
+	s.metrics.queryHeldBytes.Set(float64(s.heldBytes))
+}
@@ -0,0 +1,126 @@
+package ingester
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
+
+	"github.com/grafana/loki/pkg/logqlmodel/stats"
+)
+
+func batchStats(bytes int64) stats.Ingester {
+	return stats.Ingester{Store: stats.Store{Chunk: stats.Chunk{DecompressedBytes: bytes}}}
+}
+
+func requireHTTPStatus(t *testing.T, err error, code int) {
+	t.Helper()
+	resp, ok := httpgrpc.HTTPResponseFromError(err)
+	require.True(t, ok, "expected an httpgrpc error, got %v", err)
+	require.Equal(t, int32(code), resp.Code)
+}
+
+func TestQueryLoadShedder(t *testing.T) {
+	metrics := newIngesterMetrics(prometheus.NewRegistry())
+	backpressure := false
+	s := newQueryLoadShedder(QueryLoadSheddingConfig{
+		MaxQueryBytes:          100,
+		MaxQueryProcessingTime: time.Second,
+		MemoryBudget:           150,
+		MaxInflightPushes:      1,
+	}, func() bool { return backpressure }, metrics)
+
+	// a query exceeding the per query limits fails with a non retryable error.
+	a, err := s.admit()
+	require.NoError(t, err)
+	require.NoError(t, a.add(batchStats(60), 10, time.Millisecond))
+	a.sent()
+	requireHTTPStatus(t, a.add(batchStats(60), 10, time.Millisecond), http.StatusRequestEntityTooLarge)
+	a.done()
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.queriesShed.WithLabelValues(shedReasonQueryTooLarge)))
+
+	a, err = s.admit()
+	require.NoError(t, err)
+	require.NoError(t, a.add(batchStats(0), 10, 600*time.Millisecond))
+	requireHTTPStatus(t, a.add(batchStats(0), 10, 600*time.Millisecond), http.StatusRequestEntityTooLarge)
+	a.done()
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.queriesShed.WithLabelValues(shedReasonQueryTooExpensive)))
+	require.Equal(t, int64(0), s.heldBytes)
+
+	// the memory held by the batches is released once they are sent.
+	a, err = s.admit()
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, a.add(batchStats(0), 90, 0))
+		require.Equal(t, int64(90), s.heldBytes)
+		a.sent()
+		require.Equal(t, int64(0), s.heldBytes)
+	}
+	a.done()
+
+	// above the memory budget, new queries are rejected and only the query holding the most memory is stopped.
+	small, err := s.admit()
+	require.NoError(t, err)
+	large, err := s.admit()
+	require.NoError(t, err)
+	require.NoError(t, large.add(batchStats(0), 90, 0))
+	require.NoError(t, small.add(batchStats(0), 70, 0))
+	_, err = s.admit()
+	requireHTTPStatus(t, err, http.StatusServiceUnavailable)
+	small.sent()
+	require.NoError(t, small.add(batchStats(0), 70, 0))
+	requireHTTPStatus(t, large.add(batchStats(0), 90, 0), http.StatusServiceUnavailable)
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.queriesShed.WithLabelValues(shedReasonMemoryBudget)))
+	large.done()
+	small.sent()
+	require.NoError(t, small.add(batchStats(0), 70, 0))
+	small.done()
+	require.Equal(t, int64(0), s.heldBytes)
+	require.Empty(t, s.accounts)
+
+	// the write path is given priority over the query with the longest processing time.
+	fast, err := s.admit()
+	require.NoError(t, err)
+	slow, err := s.admit()
+	require.NoError(t, err)
+	require.NoError(t, slow.add(batchStats(0), 0, 500*time.Millisecond))
+	done1, done2 := s.pushStarted(), s.pushStarted()
+	_, err = s.admit()
+	requireHTTPStatus(t, err, http.StatusServiceUnavailable)
+	require.NoError(t, fast.add(batchStats(0), 0, time.Millisecond))
+	requireHTTPStatus(t, slow.add(batchStats(0), 0, time.Millisecond), http.StatusServiceUnavailable)
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.queriesShed.WithLabelValues(shedReasonWritePriority)))
+	slow.done()
+	done1()
+	done2()
+	require.NoError(t, fast.add(batchStats(0), 0, time.Millisecond))
+	fast.done()
+
+	// as well as under memory pressure.
+	backpressure = true
+	_, err = s.admit()
+	requireHTTPStatus(t, err, http.StatusServiceUnavailable)
+	require.Equal(t, 1.0, testutil.ToFloat64(metrics.queriesShed.WithLabelValues(shedReasonMemoryPressure)))
+	backpressure = false
+
+	a, err = s.admit()
+	require.NoError(t, err)
+	a.done()
+
+	// a nil account, used when the shedder isn't involved, is a no-op.
+	var nilAccount *queryAccount
+	require.NoError(t, nilAccount.add(batchStats(1000), 1000, 0))
+	nilAccount.sent()
+	nilAccount.done()
+}
+
+func TestQueryLoadSheddingConfig_Validate(t *testing.T) {
+	require.NoError(t, (&QueryLoadSheddingConfig{}).Validate())
+	require.NoError(t, (&QueryLoadSheddingConfig{MaxQueryBytes: 100, MemoryBudget: 10, MaxQueryProcessingTime: time.Second}).Validate())
+	require.Error(t, (&QueryLoadSheddingConfig{MaxQueryProcessingTime: -time.Second}).Validate())
+	require.Error(t, (&QueryLoadSheddingConfig{MaxInflightPushes: -1}).Validate())
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cespare/xxhash/v2"
 	"github.com/go-kit/log/level"
 	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
@@ -16,6 +17,7 @@ import (
 	"github.com/weaveworks/common/httpgrpc"
 
 	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/ingester/aggregations"
 	"github.com/grafana/loki/pkg/ingester/wal"
 	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logproto"
@@ -57,6 +59,15 @@ type stream struct {
 	// of accepted writes and for chunk synchronization.
 	highestTs time.Time
 
+	// hashes of the lines accepted by the stream within the dedup window, with the
+	// timestamp each line was last accepted at. It is nil when the dedup window is disabled.
+	dedupHashes   map[uint64]time.Time
+	dedupPrunedAt time.Time
+
+	// accumulates the values of the continuous aggregations of the tenant that aggregate
+	// the stream. It is nil when none does, and guarded by chunkMtx.
+	aggregations *aggregations.Accumulator
+
 	metrics *ingesterMetrics
 
 	tailers   map[uint32]*tailer
@@ -70,6 +81,7 @@ type stream struct {
 	entryCt int64
 
 	unorderedWrites      bool
+	structuredMetadata   bool
 	streamRateCalculator *StreamRateCalculator
 }
 
@@ -81,6 +93,27 @@ type chunkDesc struct {
 	reason  string
 
 	lastUpdated time.Time
+
+	// spill is set when the chunk was spilled to disk under memory pressure.
+	spill *spilledChunk
+	// flushing is set while a flush reads the chunk without holding the lock of its stream,
+	// so the chunk must not be spilled.
+	flushing bool
+}
+
+// pushOptions are the per-tenant configurations that apply to a push to a stream.
+type pushOptions struct {
+	// Entries whose line was accepted by the stream within this window of their
+	// timestamp are dropped. 0 disables it.
+	dedupWindow time.Duration
+	// How far behind the newest entry of the stream entries are accepted with
+	// unordered writes. Half of the max chunk age when 0, and at most the max
+	// chunk age.
+	outOfOrderWindow time.Duration
+	// Called with the entries that are older than the out-of-order window
+	// instead of rejecting them, when not nil. It fails when the entries can't
+	// be backfilled, in which case they are rejected with its error.
+	backfill func(s *stream, entries []logproto.Entry) error
 }
 
 type entryWithError struct {
@@ -88,7 +121,7 @@ type entryWithError struct {
 	e     error
 }
 
-func newStream(cfg *Config, limits RateLimiterStrategy, tenant string, fp model.Fingerprint, labels labels.Labels, unorderedWrites bool, streamRateCalculator *StreamRateCalculator, metrics *ingesterMetrics) *stream {
+func newStream(cfg *Config, limits RateLimiterStrategy, tenant string, fp model.Fingerprint, labels labels.Labels, unorderedWrites, structuredMetadata bool, streamRateCalculator *StreamRateCalculator, metrics *ingesterMetrics) *stream {
 	hashNoShard, _ := labels.HashWithoutLabels(make([]byte, 0, 1024), ShardLbName)
 	return &stream{
 		limiter:              NewStreamRateLimiter(limits, tenant, 10*time.Second),
@@ -103,7 +136,8 @@ func newStream(cfg *Config, limits RateLimiterStrategy, tenant string, fp model.
 		tenant:               tenant,
 		streamRateCalculator: streamRateCalculator,
 
-		unorderedWrites: unorderedWrites,
+		unorderedWrites:    unorderedWrites,
+		structuredMetadata: structuredMetadata,
 	}
 }
 
@@ -141,7 +175,7 @@ func (s *stream) setChunks(chunks []Chunk) (bytesAdded, entriesAdded int, err er
 }
 
 func (s *stream) NewChunk() *chunkenc.MemChunk {
-	return chunkenc.NewMemChunk(s.cfg.parsedEncoding, headBlockType(s.unorderedWrites), s.cfg.BlockSize, s.cfg.TargetChunkSize)
+	return chunkenc.NewMemChunk(s.cfg.parsedEncoding, headBlockType(s.unorderedWrites, s.structuredMetadata), s.cfg.BlockSize, s.cfg.TargetChunkSize)
 }
 
 func (s *stream) Push(
@@ -160,6 +194,7 @@ func (s *stream) Push(
 	lockChunk bool,
 	// Whether nor not to ingest all at once or not. It is a per-tenant configuration.
 	rateLimitWholeStream bool,
+	opts pushOptions,
 ) (int, error) {
 	if lockChunk {
 		s.chunkMtx.Lock()
@@ -178,10 +213,13 @@ func (s *stream) Push(
 		return 0, ErrEntriesExist
 	}
 
-	toStore, invalid := s.validateEntries(entries, isReplay, rateLimitWholeStream)
+	toStore, toBackfill, invalid := s.validateEntries(entries, isReplay, rateLimitWholeStream, opts)
 	if rateLimitWholeStream && hasRateLimitErr(invalid) {
 		return 0, errorForFailedEntries(s, invalid, len(entries))
 	}
+	if len(toBackfill) > 0 {
+		invalid = append(invalid, s.backfillEntries(toBackfill, opts.backfill)...)
+	}
 
 	prevNumChunks := len(s.chunks)
 	if prevNumChunks == 0 {
@@ -195,6 +233,13 @@ func (s *stream) Push(
 	bytesAdded, storedEntries, entriesWithErr := s.storeEntries(ctx, toStore)
 	s.recordAndSendToTailers(record, storedEntries)
 
+	// Entries replayed from the WAL are not aggregated again, as their values may have been written before the restart.
+	if s.aggregations != nil && !isReplay {
+		if late := s.aggregations.Add(storedEntries); late > 0 {
+			s.metrics.continuousAggregationLateEntries.WithLabelValues(s.tenant).Add(float64(late))
+		}
+	}
+
 	if len(s.chunks) != prevNumChunks {
 		s.metrics.memoryChunks.Add(float64(len(s.chunks) - prevNumChunks))
 	}
@@ -300,7 +345,7 @@ func (s *stream) storeEntries(ctx context.Context, entries []logproto.Entry) (in
 		defer sp.LogKV("event", "stream finished to store entries")
 	}
 
-	var bytesAdded, outOfOrderSamples, outOfOrderBytes int
+	var bytesAdded, outOfOrderSamples, outOfOrderBytes, dedupUntracked int
 
 	var invalid []entryWithError
 	storedEntries := make([]logproto.Entry, 0, len(entries))
@@ -326,25 +371,72 @@ func (s *stream) storeEntries(ctx context.Context, entries []logproto.Entry) (in
 		if s.highestTs.Before(entries[i].Timestamp) {
 			s.highestTs = entries[i].Timestamp
 		}
+		if s.dedupHashes != nil && !s.trackDedupLine(entries[i]) {
+			dedupUntracked++
+		}
 
 		bytesAdded += len(entries[i].Line)
 		storedEntries = append(storedEntries, entries[i])
 	}
 	s.reportMetrics(outOfOrderSamples, outOfOrderBytes, 0, 0)
+	if dedupUntracked > 0 {
+		s.metrics.dedupUntrackedLines.WithLabelValues(s.tenant).Add(float64(dedupUntracked))
+	}
 	return bytesAdded, storedEntries, invalid
 }
 
-func (s *stream) validateEntries(entries []logproto.Entry, isReplay, rateLimitWholeStream bool) ([]logproto.Entry, []entryWithError) {
+// backfillEntries hands the entries to backfill, and remembers their lines to drop their duplicates within the dedup
+// window like those of the stored entries. It returns the entries that could not be backfilled.
+func (s *stream) backfillEntries(entries []logproto.Entry, backfill func(s *stream, entries []logproto.Entry) error) []entryWithError {
+	if err := backfill(s, entries); err != nil {
+		invalid := make([]entryWithError, 0, len(entries))
+		for i := range entries {
+			invalid = append(invalid, entryWithError{&entries[i], err})
+		}
+		return invalid
+	}
+
+	var dedupUntracked int
+	for _, e := range entries {
+		if s.dedupHashes != nil && !s.trackDedupLine(e) {
+			dedupUntracked++
+		}
+	}
+	if dedupUntracked > 0 {
+		s.metrics.dedupUntrackedLines.WithLabelValues(s.tenant).Add(float64(dedupUntracked))
+	}
+	return nil
+}
+
+func (s *stream) validateEntries(entries []logproto.Entry, isReplay, rateLimitWholeStream bool, opts pushOptions) ([]logproto.Entry, []logproto.Entry, []entryWithError) {
 	var (
 		outOfOrderSamples, outOfOrderBytes   int
 		rateLimitedSamples, rateLimitedBytes int
+		dedupSamples, dedupBytes             int
 		validBytes, totalBytes               int
+		totalEntries                         int
 		failedEntriesWithError               []entryWithError
 		limit                                = s.limiter.lim.Limit()
 		lastLine                             = s.lastLine
 		highestTs                            = s.highestTs
 		toStore                              = make([]logproto.Entry, 0, len(entries))
+		toBackfill                           []logproto.Entry
+		batchHashes                          map[uint64]time.Time
+		dedupWindow                          = opts.dedupWindow
+		window                               = opts.outOfOrderWindow
 	)
+	// Chunks are cut once they span the max chunk age, so entries further behind the newest entry would need chunks
+	// that are already being flushed.
+	if window <= 0 {
+		window = s.cfg.MaxChunkAge / 2
+	} else if s.cfg.MaxChunkAge > 0 && window > s.cfg.MaxChunkAge {
+		window = s.cfg.MaxChunkAge
+	}
+
+	s.pruneDedupHashes(dedupWindow)
+	if s.dedupHashes != nil && !isReplay {
+		batchHashes = make(map[uint64]time.Time, len(entries))
+	}
 
 	for i := range entries {
 		// If this entry matches our last appended line's timestamp and contents,
@@ -360,7 +452,21 @@ func (s *stream) validateEntries(entries []logproto.Entry, isReplay, rateLimitWh
 		}
 
 		lineBytes := len(entries[i].Line)
+
+		// Drop the entry if the same line was accepted by the stream, or earlier
+		// in this push, within the dedup window of its timestamp.
+		var lineHash uint64
+		if batchHashes != nil {
+			lineHash = xxhash.Sum64String(entries[i].Line)
+			if s.isDuplicate(batchHashes, lineHash, entries[i].Timestamp, dedupWindow) || s.isDuplicate(s.dedupHashes, lineHash, entries[i].Timestamp, dedupWindow) {
+				dedupSamples++
+				dedupBytes += lineBytes
+				continue
+			}
+		}
+
 		totalBytes += lineBytes
+		totalEntries++
 
 		now := time.Now()
 		if !rateLimitWholeStream && !s.limiter.AllowN(now, len(entries[i].Line)) {
@@ -370,9 +476,17 @@ func (s *stream) validateEntries(entries []logproto.Entry, isReplay, rateLimitWh
 			continue
 		}
 
-		// The validity window for unordered writes is the highest timestamp present minus 1/2 * max-chunk-age.
-		cutoff := highestTs.Add(-s.cfg.MaxChunkAge / 2)
+		// The validity window for unordered writes is the highest timestamp present minus the out-of-order window,
+		// which defaults to 1/2 * max-chunk-age.
+		cutoff := highestTs.Add(-window)
 		if !isReplay && s.unorderedWrites && !highestTs.IsZero() && cutoff.After(entries[i].Timestamp) {
+			if opts.backfill != nil {
+				if batchHashes != nil {
+					batchHashes[lineHash] = entries[i].Timestamp
+				}
+				toBackfill = append(toBackfill, entries[i])
+				continue
+			}
 			failedEntriesWithError = append(failedEntriesWithError, entryWithError{&entries[i], chunkenc.ErrTooFarBehind(cutoff)})
 			outOfOrderSamples++
 			outOfOrderBytes += lineBytes
@@ -386,6 +500,9 @@ func (s *stream) validateEntries(entries []logproto.Entry, isReplay, rateLimitWh
 		if highestTs.Before(entries[i].Timestamp) {
 			highestTs = entries[i].Timestamp
 		}
+		if batchHashes != nil {
+			batchHashes[lineHash] = entries[i].Timestamp
+		}
 
 		toStore = append(toStore, entries[i])
 	}
@@ -404,9 +521,65 @@ func (s *stream) validateEntries(entries []logproto.Entry, isReplay, rateLimitWh
 		}
 	}
 
-	s.streamRateCalculator.Record(s.tenant, s.labelHash, s.labelHashNoShard, totalBytes)
+	if dedupSamples > 0 {
+		s.metrics.dedupSuppressedEntries.WithLabelValues(s.tenant).Add(float64(dedupSamples))
+		s.metrics.dedupSuppressedBytes.WithLabelValues(s.tenant).Add(float64(dedupBytes))
+	}
+
+	s.streamRateCalculator.Record(s.tenant, s.labelHash, s.labelHashNoShard, totalBytes, totalEntries)
 	s.reportMetrics(outOfOrderSamples, outOfOrderBytes, rateLimitedSamples, rateLimitedBytes)
-	return toStore, failedEntriesWithError
+	return toStore, toBackfill, failedEntriesWithError
+}
+
+// isDuplicate returns whether the line with the given hash was seen within the dedup window of ts.
+func (s *stream) isDuplicate(hashes map[uint64]time.Time, lineHash uint64, ts time.Time, dedupWindow time.Duration) bool {
+	seen, ok := hashes[lineHash]
+	if !ok {
+		return false
+	}
+	diff := ts.Sub(seen)
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= dedupWindow
+}
+
+// trackDedupLine remembers the line of the entry to drop its duplicates within the dedup window.
+// It returns false when the stream already remembers the maximum number of lines, in which case
+// the line is not remembered. Must hold chunkMtx.
+func (s *stream) trackDedupLine(entry logproto.Entry) bool {
+	lineHash := xxhash.Sum64String(entry.Line)
+	if _, ok := s.dedupHashes[lineHash]; !ok && s.cfg.DedupMaxLinesPerStream > 0 && len(s.dedupHashes) >= s.cfg.DedupMaxLinesPerStream {
+		return false
+	}
+	s.dedupHashes[lineHash] = entry.Timestamp
+	return true
+}
+
+// pruneDedupHashes resizes the dedup state of the stream to the given window,
+// forgetting the lines that fell out of the window relative to the highest timestamp of the stream.
+// Must hold chunkMtx.
+func (s *stream) pruneDedupHashes(dedupWindow time.Duration) {
+	if dedupWindow <= 0 {
+		s.dedupHashes = nil
+		return
+	}
+	if s.dedupHashes == nil {
+		s.dedupHashes = map[uint64]time.Time{}
+		s.dedupPrunedAt = s.highestTs
+		return
+	}
+	// Pruning walks the whole state, so only do it once per window.
+	if s.highestTs.Sub(s.dedupPrunedAt) < dedupWindow {
+		return
+	}
+	oldest := s.highestTs.Add(-dedupWindow)
+	for h, ts := range s.dedupHashes {
+		if ts.Before(oldest) {
+			delete(s.dedupHashes, h)
+		}
+	}
+	s.dedupPrunedAt = s.highestTs
 }
 
 func (s *stream) reportMetrics(outOfOrderSamples, outOfOrderBytes, rateLimitedSamples, rateLimitedBytes int) {
@@ -514,9 +687,15 @@ func (s *stream) Iterator(ctx context.Context, statsCtx *stats.Context, from, th
 
 		itr, err := c.chunk.Iterator(ctx, from, through, direction, pipeline)
 		if err != nil {
+			for _, it := range iterators {
+				_ = it.Close()
+			}
 			return nil, err
 		}
 		if itr != nil {
+			if c.spill != nil {
+				itr = newSpilledEntryIterator(itr, c.spill)
+			}
 			iterators = append(iterators, itr)
 		}
 	}
@@ -560,6 +739,9 @@ func (s *stream) SampleIterator(ctx context.Context, statsCtx *stats.Context, fr
 		lastMax = maxt
 
 		if itr := c.chunk.SampleIterator(ctx, from, through, extractor); itr != nil {
+			if c.spill != nil {
+				itr = newSpilledSampleIterator(itr, c.spill)
+			}
 			iterators = append(iterators, itr)
 		}
 	}
@@ -574,6 +756,35 @@ func (s *stream) SampleIterator(ctx context.Context, statsCtx *stats.Context, fr
 	return iter.NewSortSampleIterator(iterators), nil
 }
 
+// spillChunks spills the cut chunks of the stream that were not flushed yet to dir, until at least
+// the given number of bytes were spilled. Chunks that are queued for a flush are spilled too, as
+// they can wait in the queue for long, but not the ones a flush is reading. It returns the number
+// of bytes spilled.
+func (s *stream) spillChunks(dir string, bytes int64, metrics *ingesterMetrics) (int64, error) {
+	s.chunkMtx.Lock()
+	defer s.chunkMtx.Unlock()
+
+	var spilled int64
+	for j := range s.chunks {
+		c := &s.chunks[j]
+		if !c.closed || c.spill != nil || !c.flushed.IsZero() || c.flushing {
+			continue
+		}
+
+		size := int64(c.chunk.BytesSize())
+		mc, spill, err := spillChunk(dir, c.chunk, s.cfg.BlockSize, s.cfg.TargetChunkSize, metrics)
+		if err != nil {
+			return spilled, err
+		}
+		c.chunk, c.spill = mc, spill
+		spilled += size
+		if spilled >= bytes {
+			break
+		}
+	}
+	return spilled, nil
+}
+
 func (s *stream) addTailer(t *tailer) {
 	s.tailerMtx.Lock()
 	defer s.tailerMtx.Unlock()
@@ -585,8 +796,11 @@ func (s *stream) resetCounter() {
 	s.entryCt = 0
 }
 
-func headBlockType(unorderedWrites bool) chunkenc.HeadBlockFmt {
+func headBlockType(unorderedWrites, structuredMetadata bool) chunkenc.HeadBlockFmt {
 	if unorderedWrites {
+		if structuredMetadata {
+			return chunkenc.UnorderedWithMetadataHeadBlockFmt
+		}
 		return chunkenc.UnorderedHeadBlockFmt
 	}
 	return chunkenc.OrderedHeadBlockFmt
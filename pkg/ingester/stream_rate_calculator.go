@@ -79,6 +79,7 @@ func (c *StreamRateCalculator) updateRates() {
 					StreamHash:        streamRate.StreamHash,
 					StreamHashNoShard: streamRate.StreamHashNoShard,
 					Rate:              streamRate.Rate,
+					EntriesRate:       streamRate.EntriesRate,
 				})
 			}
 		}
@@ -100,7 +101,7 @@ func (c *StreamRateCalculator) Rates() []logproto.StreamRate {
 	return c.allRates
 }
 
-func (c *StreamRateCalculator) Record(tenant string, streamHash, streamHashNoShard uint64, bytes int) {
+func (c *StreamRateCalculator) Record(tenant string, streamHash, streamHashNoShard uint64, bytes, entries int) {
 	i := streamHash & uint64(c.size-1)
 
 	c.locks[i].Lock()
@@ -112,6 +113,7 @@ func (c *StreamRateCalculator) Record(tenant string, streamHash, streamHashNoSha
 	streamRate.StreamHashNoShard = streamHashNoShard
 	streamRate.Tenant = tenant
 	streamRate.Rate += int64(bytes)
+	streamRate.EntriesRate += int64(entries)
 	tenantMap[streamHash] = streamRate
 
 	c.samples[i][tenant] = tenantMap
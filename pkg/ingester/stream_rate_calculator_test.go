@@ -13,11 +13,11 @@ func TestStreamRateCalculator(t *testing.T) {
 	defer calc.Stop()
 
 	for i := 0; i < 100; i++ {
-		calc.Record("tenant 1", 1, 1, 100)
+		calc.Record("tenant 1", 1, 1, 100, 1)
 	}
 
 	for i := 0; i < 100; i++ {
-		calc.Record("tenant 2", 1, 1, 100)
+		calc.Record("tenant 2", 1, 1, 100, 1)
 	}
 
 	require.Eventually(t, func() bool {
@@ -27,8 +27,8 @@ func TestStreamRateCalculator(t *testing.T) {
 		})
 
 		if len(rates) > 1 {
-			return rates[0].Tenant == "tenant 1" && rates[0].Rate == 10000 &&
-				rates[1].Tenant == "tenant 2" && rates[1].Rate == 10000
+			return rates[0].Tenant == "tenant 1" && rates[0].Rate == 10000 && rates[0].EntriesRate == 100 &&
+				rates[1].Tenant == "tenant 2" && rates[1].Rate == 10000 && rates[1].EntriesRate == 100
 		}
 
 		return false
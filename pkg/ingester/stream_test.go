@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/require"
@@ -61,13 +62,14 @@ func TestMaxReturnedStreamsErrors(t *testing.T) {
 					{Name: "foo", Value: "bar"},
 				},
 				true,
+				false,
 				NewStreamRateCalculator(),
 				NilMetrics,
 			)
 
 			_, err := s.Push(context.Background(), []logproto.Entry{
 				{Timestamp: time.Unix(int64(numLogs), 0), Line: "log"},
-			}, recordPool.GetRecord(), 0, true, false)
+			}, recordPool.GetRecord(), 0, true, false, pushOptions{})
 			require.NoError(t, err)
 
 			newLines := make([]logproto.Entry, numLogs)
@@ -87,7 +89,7 @@ func TestMaxReturnedStreamsErrors(t *testing.T) {
 			fmt.Fprintf(&expected, "user 'fake', total ignored: %d out of %d", numLogs, numLogs)
 			expectErr := httpgrpc.Errorf(http.StatusBadRequest, expected.String())
 
-			_, err = s.Push(context.Background(), newLines, recordPool.GetRecord(), 0, true, false)
+			_, err = s.Push(context.Background(), newLines, recordPool.GetRecord(), 0, true, false, pushOptions{})
 			require.Error(t, err)
 			require.Equal(t, expectErr.Error(), err.Error())
 		})
@@ -108,6 +110,7 @@ func TestPushDeduplication(t *testing.T) {
 			{Name: "foo", Value: "bar"},
 		},
 		true,
+		false,
 		NewStreamRateCalculator(),
 		NilMetrics,
 	)
@@ -116,7 +119,7 @@ func TestPushDeduplication(t *testing.T) {
 		{Timestamp: time.Unix(1, 0), Line: "test"},
 		{Timestamp: time.Unix(1, 0), Line: "test"},
 		{Timestamp: time.Unix(1, 0), Line: "newer, better test"},
-	}, recordPool.GetRecord(), 0, true, false)
+	}, recordPool.GetRecord(), 0, true, false, pushOptions{})
 	require.NoError(t, err)
 	require.Len(t, s.chunks, 1)
 	require.Equal(t, s.chunks[0].chunk.Size(), 2,
@@ -124,6 +127,113 @@ func TestPushDeduplication(t *testing.T) {
 	require.Equal(t, len("test"+"newer, better test"), written)
 }
 
+func TestPushDeduplicationWindow(t *testing.T) {
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+	s := newStream(
+		defaultConfig(),
+		limiter,
+		"fake",
+		model.Fingerprint(0),
+		labels.Labels{
+			{Name: "foo", Value: "bar"},
+		},
+		true,
+		false,
+		NewStreamRateCalculator(),
+		NilMetrics,
+	)
+
+	written, err := s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(10, 0), Line: "test"},
+		{Timestamp: time.Unix(11, 0), Line: "test"},
+		{Timestamp: time.Unix(11, 0), Line: "other"},
+	}, recordPool.GetRecord(), 0, true, false, pushOptions{dedupWindow: 5 * time.Second})
+	require.NoError(t, err)
+	require.Equal(t, 2, s.chunks[0].chunk.Size(), "expected the duplicate within the window to be dropped")
+	require.Equal(t, len("test"+"other"), written)
+
+	// Duplicates of lines accepted by previous pushes are dropped too, also when they are older.
+	written, err = s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(8, 0), Line: "other"},
+		{Timestamp: time.Unix(15, 0), Line: "test"},
+		{Timestamp: time.Unix(16, 0), Line: "test"},
+	}, recordPool.GetRecord(), 0, true, false, pushOptions{dedupWindow: 5 * time.Second})
+	require.NoError(t, err)
+	require.Equal(t, 3, s.chunks[0].chunk.Size(), "expected only the line outside of the window to be appended")
+	require.Equal(t, len("test"), written)
+
+	// Disabling the window only drops exact duplicates.
+	written, err = s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(17, 0), Line: "test"},
+	}, recordPool.GetRecord(), 0, true, false, pushOptions{})
+	require.NoError(t, err)
+	require.Equal(t, len("test"), written)
+	require.Nil(t, s.dedupHashes)
+}
+
+func TestPushDeduplicationMaxLines(t *testing.T) {
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+	cfg := defaultConfig()
+	cfg.DedupMaxLinesPerStream = 2
+	s := newStream(
+		cfg,
+		limiter,
+		"max-lines",
+		model.Fingerprint(0),
+		labels.Labels{
+			{Name: "foo", Value: "bar"},
+		},
+		true,
+		false,
+		NewStreamRateCalculator(),
+		NilMetrics,
+	)
+
+	_, err = s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(10, 0), Line: "a"},
+		{Timestamp: time.Unix(11, 0), Line: "b"},
+		{Timestamp: time.Unix(12, 0), Line: "c"},
+	}, recordPool.GetRecord(), 0, true, false, pushOptions{dedupWindow: time.Minute})
+	require.NoError(t, err)
+	require.Len(t, s.dedupHashes, 2)
+	require.Equal(t, 1.0, testutil.ToFloat64(NilMetrics.dedupUntrackedLines.WithLabelValues("max-lines")))
+
+	// Only the duplicates of the remembered lines are dropped, and remembered lines are still refreshed.
+	written, err := s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: time.Unix(13, 0), Line: "a"},
+		{Timestamp: time.Unix(14, 0), Line: "c"},
+	}, recordPool.GetRecord(), 0, true, false, pushOptions{dedupWindow: time.Minute})
+	require.NoError(t, err)
+	require.Equal(t, len("c"), written)
+	require.Len(t, s.dedupHashes, 2)
+}
+
+func TestPruneDedupHashes(t *testing.T) {
+	s := &stream{highestTs: time.Unix(0, 0)}
+	s.pruneDedupHashes(time.Minute)
+	require.NotNil(t, s.dedupHashes)
+
+	s.dedupHashes[1] = time.Unix(0, 0)
+	s.dedupHashes[2] = time.Unix(50, 0)
+	s.highestTs = time.Unix(50, 0)
+	s.pruneDedupHashes(time.Minute)
+	require.Len(t, s.dedupHashes, 2, "expected no pruning within a window of the last one")
+
+	s.dedupHashes[3] = time.Unix(100, 0)
+	s.highestTs = time.Unix(100, 0)
+	s.pruneDedupHashes(time.Minute)
+	require.Equal(t, map[uint64]time.Time{2: time.Unix(50, 0), 3: time.Unix(100, 0)}, s.dedupHashes)
+
+	s.pruneDedupHashes(0)
+	require.Nil(t, s.dedupHashes)
+}
+
 func TestPushRejectOldCounter(t *testing.T) {
 	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
 	require.NoError(t, err)
@@ -138,6 +248,7 @@ func TestPushRejectOldCounter(t *testing.T) {
 			{Name: "foo", Value: "bar"},
 		},
 		true,
+		false,
 		NewStreamRateCalculator(),
 		NilMetrics,
 	)
@@ -147,7 +258,7 @@ func TestPushRejectOldCounter(t *testing.T) {
 		{Timestamp: time.Unix(1, 0), Line: "test"},
 		{Timestamp: time.Unix(1, 0), Line: "test"},
 		{Timestamp: time.Unix(1, 0), Line: "newer, better test"},
-	}, recordPool.GetRecord(), 0, true, false)
+	}, recordPool.GetRecord(), 0, true, false, pushOptions{})
 	require.NoError(t, err)
 	require.Len(t, s.chunks, 1)
 	require.Equal(t, s.chunks[0].chunk.Size(), 2,
@@ -156,13 +267,13 @@ func TestPushRejectOldCounter(t *testing.T) {
 	// fail to push with a counter <= the streams internal counter
 	_, err = s.Push(context.Background(), []logproto.Entry{
 		{Timestamp: time.Unix(1, 0), Line: "test"},
-	}, recordPool.GetRecord(), 2, true, false)
+	}, recordPool.GetRecord(), 2, true, false, pushOptions{})
 	require.Equal(t, ErrEntriesExist, err)
 
 	// succeed with a greater counter
 	_, err = s.Push(context.Background(), []logproto.Entry{
 		{Timestamp: time.Unix(1, 0), Line: "test"},
-	}, recordPool.GetRecord(), 3, true, false)
+	}, recordPool.GetRecord(), 3, true, false, pushOptions{})
 	require.Nil(t, err)
 
 }
@@ -233,6 +344,7 @@ func TestUnorderedPush(t *testing.T) {
 			{Name: "foo", Value: "bar"},
 		},
 		true,
+		false,
 		NewStreamRateCalculator(),
 		NilMetrics,
 	)
@@ -277,7 +389,7 @@ func TestUnorderedPush(t *testing.T) {
 		if x.cutBefore {
 			_ = s.cutChunk(context.Background())
 		}
-		written, err := s.Push(context.Background(), x.entries, recordPool.GetRecord(), 0, true, false)
+		written, err := s.Push(context.Background(), x.entries, recordPool.GetRecord(), 0, true, false, pushOptions{})
 		if x.err {
 			require.NotNil(t, err)
 		} else {
@@ -330,6 +442,7 @@ func TestPushRateLimit(t *testing.T) {
 			{Name: "foo", Value: "bar"},
 		},
 		true,
+		false,
 		NewStreamRateCalculator(),
 		NilMetrics,
 	)
@@ -339,7 +452,7 @@ func TestPushRateLimit(t *testing.T) {
 		{Timestamp: time.Unix(1, 0), Line: "aaaaaaaaab"},
 	}
 	// Counter should be 2 now since the first line will be deduped.
-	_, err = s.Push(context.Background(), entries, recordPool.GetRecord(), 0, true, true)
+	_, err = s.Push(context.Background(), entries, recordPool.GetRecord(), 0, true, true, pushOptions{})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), (&validation.ErrStreamRateLimit{RateLimit: l.PerStreamRateLimit, Labels: s.labelsString, Bytes: flagext.ByteSize(len(entries[1].Line))}).Error())
 }
@@ -364,6 +477,7 @@ func TestPushRateLimitAllOrNothing(t *testing.T) {
 			{Name: "foo", Value: "bar"},
 		},
 		true,
+		false,
 		NewStreamRateCalculator(),
 		NilMetrics,
 	)
@@ -374,7 +488,7 @@ func TestPushRateLimitAllOrNothing(t *testing.T) {
 	}
 
 	// Both entries have errors because rate limiting is done all at once
-	_, err = s.Push(context.Background(), entries, recordPool.GetRecord(), 0, true, true)
+	_, err = s.Push(context.Background(), entries, recordPool.GetRecord(), 0, true, true, pushOptions{})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), (&validation.ErrStreamRateLimit{RateLimit: l.PerStreamRateLimit, Labels: s.labelsString, Bytes: flagext.ByteSize(len(entries[0].Line))}).Error())
 	require.Contains(t, err.Error(), (&validation.ErrStreamRateLimit{RateLimit: l.PerStreamRateLimit, Labels: s.labelsString, Bytes: flagext.ByteSize(len(entries[1].Line))}).Error())
@@ -397,6 +511,7 @@ func TestReplayAppendIgnoresValidityWindow(t *testing.T) {
 			{Name: "foo", Value: "bar"},
 		},
 		true,
+		false,
 		NewStreamRateCalculator(),
 		NilMetrics,
 	)
@@ -408,7 +523,7 @@ func TestReplayAppendIgnoresValidityWindow(t *testing.T) {
 	}
 
 	// Push a first entry (it doesn't matter if we look like we're replaying or not)
-	_, err = s.Push(context.Background(), entries, nil, 1, true, false)
+	_, err = s.Push(context.Background(), entries, nil, 1, true, false, pushOptions{})
 	require.Nil(t, err)
 
 	// Create a sample outside the validity window
@@ -417,15 +532,105 @@ func TestReplayAppendIgnoresValidityWindow(t *testing.T) {
 	}
 
 	// Pretend it's not a replay, ensure we error
-	_, err = s.Push(context.Background(), entries, recordPool.GetRecord(), 0, true, false)
+	_, err = s.Push(context.Background(), entries, recordPool.GetRecord(), 0, true, false, pushOptions{})
 	require.NotNil(t, err)
 
 	// Now pretend it's a replay. The same write should succeed.
-	_, err = s.Push(context.Background(), entries, nil, 2, true, false)
+	_, err = s.Push(context.Background(), entries, nil, 2, true, false, pushOptions{})
 	require.Nil(t, err)
 
 }
 
+func TestOutOfOrderWindow(t *testing.T) {
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+	cfg := defaultConfig()
+	cfg.MaxChunkAge = time.Hour
+
+	s := newStream(
+		cfg,
+		limiter,
+		"fake",
+		model.Fingerprint(0),
+		labels.Labels{
+			{Name: "foo", Value: "bar"},
+		},
+		true,
+		false,
+		NewStreamRateCalculator(),
+		NilMetrics,
+	)
+
+	base := time.Now()
+	_, err = s.Push(context.Background(), []logproto.Entry{{Timestamp: base, Line: "1"}}, recordPool.GetRecord(), 0, true, false, pushOptions{})
+	require.NoError(t, err)
+
+	// Within half of the max chunk age by default, but not within a smaller window.
+	_, err = s.Push(context.Background(), []logproto.Entry{{Timestamp: base.Add(-20 * time.Minute), Line: "2"}}, recordPool.GetRecord(), 0, true, false, pushOptions{})
+	require.NoError(t, err)
+	_, err = s.Push(context.Background(), []logproto.Entry{{Timestamp: base.Add(-20 * time.Minute), Line: "3"}}, recordPool.GetRecord(), 0, true, false, pushOptions{outOfOrderWindow: 10 * time.Minute})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), chunkenc.ErrTooFarBehind(base.Add(-10*time.Minute)).Error())
+
+	// Not within half of the max chunk age, but within a larger window.
+	_, err = s.Push(context.Background(), []logproto.Entry{{Timestamp: base.Add(-40 * time.Minute), Line: "4"}}, recordPool.GetRecord(), 0, true, false, pushOptions{})
+	require.Error(t, err)
+	_, err = s.Push(context.Background(), []logproto.Entry{{Timestamp: base.Add(-40 * time.Minute), Line: "5"}}, recordPool.GetRecord(), 0, true, false, pushOptions{outOfOrderWindow: 2 * time.Hour})
+	require.NoError(t, err)
+
+	// The window is capped at the max chunk age.
+	_, err = s.Push(context.Background(), []logproto.Entry{{Timestamp: base.Add(-90 * time.Minute), Line: "5"}}, recordPool.GetRecord(), 0, true, false, pushOptions{outOfOrderWindow: 2 * time.Hour})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), chunkenc.ErrTooFarBehind(base.Add(-time.Hour)).Error())
+
+	// Entries older than the window are backfilled instead of rejected.
+	var backfilled []logproto.Entry
+	backfill := pushOptions{
+		outOfOrderWindow: 10 * time.Minute,
+		backfill: func(bs *stream, entries []logproto.Entry) error {
+			require.Equal(t, s, bs)
+			backfilled = append(backfilled, entries...)
+			return nil
+		},
+	}
+	_, err = s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: base.Add(-time.Minute), Line: "6"},
+		{Timestamp: base.Add(-time.Hour), Line: "7"},
+	}, recordPool.GetRecord(), 0, true, false, backfill)
+	require.NoError(t, err)
+	require.Equal(t, []logproto.Entry{{Timestamp: base.Add(-time.Hour), Line: "7"}}, backfilled)
+
+	// Backfilled lines are deduplicated like stored lines.
+	backfill.dedupWindow = time.Minute
+	_, err = s.Push(context.Background(), []logproto.Entry{
+		{Timestamp: base.Add(-50 * time.Minute), Line: "8"},
+		{Timestamp: base.Add(-50 * time.Minute).Add(time.Second), Line: "8"},
+	}, recordPool.GetRecord(), 0, true, false, backfill)
+	require.NoError(t, err)
+	_, err = s.Push(context.Background(), []logproto.Entry{{Timestamp: base.Add(-50 * time.Minute).Add(2 * time.Second), Line: "8"}}, recordPool.GetRecord(), 0, true, false, backfill)
+	require.NoError(t, err)
+	require.Equal(t, []logproto.Entry{{Timestamp: base.Add(-time.Hour), Line: "7"}, {Timestamp: base.Add(-50 * time.Minute), Line: "8"}}, backfilled)
+
+	// Entries that can't be backfilled are rejected with the error of the backfill.
+	backfill.backfill = func(*stream, []logproto.Entry) error {
+		return httpgrpc.Errorf(http.StatusTooManyRequests, "queue full")
+	}
+	_, err = s.Push(context.Background(), []logproto.Entry{{Timestamp: base.Add(-50 * time.Minute), Line: "9"}}, recordPool.GetRecord(), 0, true, false, backfill)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "queue full")
+
+	it, err := s.Iterator(context.Background(), nil, base.Add(-2*time.Hour), base.Add(time.Second), logproto.FORWARD, log.NewNoopPipeline().ForStream(s.labels))
+	require.NoError(t, err)
+	var lines []string
+	for it.Next() {
+		lines = append(lines, it.Entry().Line)
+	}
+	require.NoError(t, it.Close())
+	require.Equal(t, []string{"5", "2", "6", "1"}, lines)
+}
+
 func iterEq(t *testing.T, exp []logproto.Entry, got iter.EntryIterator) {
 	var i int
 	for got.Next() {
@@ -448,7 +653,7 @@ func Benchmark_PushStream(b *testing.B) {
 	require.NoError(b, err)
 	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
 
-	s := newStream(&Config{MaxChunkAge: 24 * time.Hour}, limiter, "fake", model.Fingerprint(0), ls, true, NewStreamRateCalculator(), NilMetrics)
+	s := newStream(&Config{MaxChunkAge: 24 * time.Hour}, limiter, "fake", model.Fingerprint(0), ls, true, false, NewStreamRateCalculator(), NilMetrics)
 	t, err := newTailer("foo", `{namespace="loki-dev"}`, &fakeTailServer{}, 10)
 	require.NoError(b, err)
 
@@ -463,7 +668,7 @@ func Benchmark_PushStream(b *testing.B) {
 
 	for n := 0; n < b.N; n++ {
 		rec := recordPool.GetRecord()
-		_, err := s.Push(ctx, e, rec, 0, true, false)
+		_, err := s.Push(ctx, e, rec, 0, true, false, pushOptions{})
 		require.NoError(b, err)
 		recordPool.PutRecord(rec)
 	}
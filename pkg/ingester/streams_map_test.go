@@ -25,6 +25,7 @@ func TestStreamsMap(t *testing.T) {
 				{Name: "foo", Value: "bar"},
 			},
 			true,
+			false,
 			NewStreamRateCalculator(),
 			NilMetrics,
 		),
@@ -37,6 +38,7 @@ func TestStreamsMap(t *testing.T) {
 				{Name: "bar", Value: "foo"},
 			},
 			true,
+			false,
 			NewStreamRateCalculator(),
 			NilMetrics,
 		),
@@ -0,0 +1,127 @@
+package ingester
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// tenantVolumesFile is the name of the file in the WAL directory that the volumes are saved to.
+const tenantVolumesFile = "tenant_volumes.json"
+
+// TenantVolumes counts the bytes of the log lines that the ingester received per tenant
+// in the current UTC day and month. The distributors sum them up across ingesters to
+// enforce the ingestion quotas of the tenants.
+//
+// When the WAL is enabled, the volumes are periodically saved to the WAL directory and
+// loaded again when the ingester restarts. The bytes received after the last save are
+// lost when the ingester crashes.
+type TenantVolumes struct {
+	mtx     sync.Mutex
+	volumes map[string]*logproto.TenantVolume
+}
+
+func NewTenantVolumes() *TenantVolumes {
+	return &TenantVolumes{volumes: map[string]*logproto.TenantVolume{}}
+}
+
+// Add adds the bytes of a push request of the tenant.
+func (v *TenantVolumes) Add(tenant string, bytes int64, now time.Time) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	volume, ok := v.volumes[tenant]
+	if !ok {
+		volume = &logproto.TenantVolume{Tenant: tenant}
+		v.volumes[tenant] = volume
+	}
+	resetPeriods(volume, now)
+	volume.DayBytes += bytes
+	volume.MonthBytes += bytes
+}
+
+// Volumes returns the volumes of all tenants in the current day and month.
+func (v *TenantVolumes) Volumes(now time.Time) []*logproto.TenantVolume {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	volumes := make([]*logproto.TenantVolume, 0, len(v.volumes))
+	for tenant, volume := range v.volumes {
+		resetPeriods(volume, now)
+		if volume.MonthBytes == 0 {
+			delete(v.volumes, tenant)
+			continue
+		}
+		volumes = append(volumes, &logproto.TenantVolume{
+			Tenant:     volume.Tenant,
+			Day:        volume.Day,
+			DayBytes:   volume.DayBytes,
+			Month:      volume.Month,
+			MonthBytes: volume.MonthBytes,
+		})
+	}
+	return volumes
+}
+
+// Save writes the volumes of the current day and month to the file at path.
+// The file is replaced atomically, so a crash leaves either the old or the new volumes.
+func (v *TenantVolumes) Save(path string, now time.Time) error {
+	volumes := v.Volumes(now)
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Tenant < volumes[j].Tenant })
+	data, err := json.Marshal(volumes)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load replaces the volumes with the ones saved to the file at path, if it exists.
+// Volumes of past days and months are reset when they are read.
+func (v *TenantVolumes) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var volumes []*logproto.TenantVolume
+	if err := json.Unmarshal(data, &volumes); err != nil {
+		return fmt.Errorf("parsing tenant volumes from %q: %w", path, err)
+	}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	v.volumes = make(map[string]*logproto.TenantVolume, len(volumes))
+	for _, volume := range volumes {
+		v.volumes[volume.Tenant] = volume
+	}
+	return nil
+}
+
+// resetPeriods resets the bytes of the volume when its day or month has passed.
+func resetPeriods(volume *logproto.TenantVolume, now time.Time) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).Unix()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).Unix()
+	if volume.Day != day {
+		volume.Day = day
+		volume.DayBytes = 0
+	}
+	if volume.Month != month {
+		volume.Month = month
+		volume.MonthBytes = 0
+	}
+}
@@ -0,0 +1,61 @@
+package ingester
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestTenantVolumes(t *testing.T) {
+	now := time.Date(2023, time.May, 31, 23, 0, 0, 0, time.UTC)
+	day := time.Date(2023, time.May, 31, 0, 0, 0, 0, time.UTC).Unix()
+	month := time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	v := NewTenantVolumes()
+	v.Add("tenant", 10, now)
+	v.Add("tenant", 5, now)
+	require.Equal(t, []*logproto.TenantVolume{
+		{Tenant: "tenant", Day: day, DayBytes: 15, Month: month, MonthBytes: 15},
+	}, v.Volumes(now))
+
+	// Both the day and the month pass at midnight.
+	next := now.Add(2 * time.Hour)
+	v.Add("tenant", 1, next)
+	require.Equal(t, []*logproto.TenantVolume{
+		{Tenant: "tenant", Day: next.Truncate(24 * time.Hour).Unix(), DayBytes: 1, Month: time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC).Unix(), MonthBytes: 1},
+	}, v.Volumes(next))
+
+	// Tenants without volume in the current month are removed.
+	require.Empty(t, v.Volumes(next.AddDate(0, 1, 0)))
+}
+
+func TestTenantVolumes_SaveLoad(t *testing.T) {
+	now := time.Date(2023, time.May, 31, 23, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), tenantVolumesFile)
+
+	// Nothing is loaded before the volumes were saved.
+	v := NewTenantVolumes()
+	require.NoError(t, v.Load(path))
+	require.Empty(t, v.Volumes(now))
+
+	v.Add("tenant", 10, now)
+	v.Add("other", 5, now)
+	require.NoError(t, v.Save(path, now))
+
+	loaded := NewTenantVolumes()
+	require.NoError(t, loaded.Load(path))
+	require.ElementsMatch(t, v.Volumes(now), loaded.Volumes(now))
+
+	// Loaded volumes keep counting, and reset in the next period.
+	loaded.Add("tenant", 1, now)
+	require.Contains(t, loaded.Volumes(now), &logproto.TenantVolume{Tenant: "tenant", Day: now.Truncate(24 * time.Hour).Unix(), DayBytes: 11, Month: time.Date(2023, time.May, 1, 0, 0, 0, 0, time.UTC).Unix(), MonthBytes: 11})
+	require.Empty(t, loaded.Volumes(now.AddDate(0, 1, 0)))
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	require.Error(t, NewTenantVolumes().Load(path))
+}
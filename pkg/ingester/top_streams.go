@@ -0,0 +1,141 @@
+package ingester
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/util"
+)
+
+// The fields by which top streams can be sorted, in descending order.
+const (
+	TopStreamsByBytesRate   = "bytes_rate"
+	TopStreamsByEntriesRate = "entries_rate"
+	TopStreamsByMemory      = "memory"
+	TopStreamsByChunks      = "chunks"
+
+	defaultTopStreamsLimit = 10
+)
+
+// ParseTopStreamsRequest parses the sort and limit parameters of a top streams HTTP request.
+func ParseTopStreamsRequest(r *http.Request) (*logproto.TopStreamsRequest, error) {
+	req := &logproto.TopStreamsRequest{
+		SortBy: r.FormValue("sort"),
+		Limit:  defaultTopStreamsLimit,
+	}
+	switch req.SortBy {
+	case "":
+		req.SortBy = TopStreamsByBytesRate
+	case TopStreamsByBytesRate, TopStreamsByEntriesRate, TopStreamsByMemory, TopStreamsByChunks:
+	default:
+		return nil, fmt.Errorf("invalid sort %q, expected one of %s, %s, %s or %s", req.SortBy, TopStreamsByBytesRate, TopStreamsByEntriesRate, TopStreamsByMemory, TopStreamsByChunks)
+	}
+
+	if l := r.FormValue("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid limit %q, expected a positive integer", l)
+		}
+		req.Limit = uint32(limit)
+	}
+	return req, nil
+}
+
+// SortTopStreams sorts the streams in descending order of the given field and truncates them to the limit.
+// Streams with the same value are ordered by their labels.
+func SortTopStreams(streams []*logproto.TopStream, sortBy string, limit uint32) []*logproto.TopStream {
+	sort.Slice(streams, func(i, j int) bool {
+		vi, vj := topStreamValue(streams[i], sortBy), topStreamValue(streams[j], sortBy)
+		if vi != vj {
+			return vi > vj
+		}
+		return streams[i].Labels < streams[j].Labels
+	})
+	if limit > 0 && uint32(len(streams)) > limit {
+		streams = streams[:limit]
+	}
+	return streams
+}
+
+func topStreamValue(s *logproto.TopStream, sortBy string) int64 {
+	switch sortBy {
+	case TopStreamsByEntriesRate:
+		return s.EntriesRate
+	case TopStreamsByMemory:
+		return s.MemoryBytes
+	case TopStreamsByChunks:
+		return s.Chunks
+	default:
+		return s.BytesRate
+	}
+}
+
+// GetTopStreams returns the streams of the tenant held by this ingester, sorted and limited as requested.
+func (i *Ingester) GetTopStreams(ctx context.Context, req *logproto.TopStreamsRequest) (*logproto.TopStreamsResponse, error) {
+	instanceID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, ok := i.getInstanceByID(instanceID)
+	if !ok {
+		return &logproto.TopStreamsResponse{}, nil
+	}
+	return &logproto.TopStreamsResponse{Streams: instance.topStreams(req, i.streamRateCalculator.Rates())}, nil
+}
+
+// TopStreamsHandler serves the top streams of the tenant held by this ingester.
+func (i *Ingester) TopStreamsHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := ParseTopStreamsRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := i.GetTopStreams(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	util.WriteJSONResponse(w, resp)
+}
+
+// topStreams returns the streams of the instance with their rates and the memory held by their chunks.
+func (i *instance) topStreams(req *logproto.TopStreamsRequest, rates []logproto.StreamRate) []*logproto.TopStream {
+	byHash := make(map[uint64]logproto.StreamRate)
+	for _, r := range rates {
+		if r.Tenant == i.instanceID {
+			byHash[r.StreamHash] = r
+		}
+	}
+
+	var streams []*logproto.TopStream
+	_ = i.streams.ForEach(func(s *stream) (bool, error) {
+		rate := byHash[s.labelHash]
+		top := &logproto.TopStream{
+			Labels:      s.labelsString,
+			BytesRate:   rate.Rate,
+			EntriesRate: rate.EntriesRate,
+		}
+
+		s.chunkMtx.RLock()
+		top.Chunks = int64(len(s.chunks))
+		for _, c := range s.chunks {
+			// Spilled chunks are read from disk.
+			if c.spill == nil {
+				top.MemoryBytes += int64(c.chunk.CompressedSize())
+			}
+		}
+		s.chunkMtx.RUnlock()
+
+		streams = append(streams, top)
+		return true, nil
+	})
+	return SortTopStreams(streams, req.SortBy, req.Limit)
+}
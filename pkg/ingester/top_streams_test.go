@@ -0,0 +1,76 @@
+package ingester
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	loki_runtime "github.com/grafana/loki/pkg/runtime"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+func TestInstanceTopStreams(t *testing.T) {
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+	limiter := NewLimiter(limits, NilMetrics, &ringCountMock{count: 1}, 1)
+
+	inst, err := newInstance(defaultConfig(), defaultPeriodConfigs, "test", limiter, loki_runtime.DefaultTenantConfigs(), noopWAL{}, NilMetrics, &OnceSwitch{}, nil, NewStreamRateCalculator())
+	require.NoError(t, err)
+
+	err = inst.Push(context.Background(), &logproto.PushRequest{Streams: []logproto.Stream{
+		{Labels: `{app="small"}`, Entries: entries(1, time.Unix(0, 0))},
+		{Labels: `{app="large"}`, Entries: entries(100, time.Unix(0, 0))},
+	}})
+	require.NoError(t, err)
+
+	// The small stream has the highest rates, the large one holds the most memory.
+	var rates []logproto.StreamRate
+	_ = inst.streams.ForEach(func(s *stream) (bool, error) {
+		rate := logproto.StreamRate{Tenant: "test", StreamHash: s.labelHash, Rate: 10, EntriesRate: 1}
+		if s.labelsString == `{app="small"}` {
+			rate.Rate, rate.EntriesRate = 1000, 100
+		}
+		rates = append(rates, rate, logproto.StreamRate{Tenant: "other", StreamHash: s.labelHash, Rate: 5000, EntriesRate: 500})
+		return true, nil
+	})
+
+	top := inst.topStreams(&logproto.TopStreamsRequest{SortBy: TopStreamsByBytesRate, Limit: 10}, rates)
+	require.Len(t, top, 2)
+	require.Equal(t, `{app="small"}`, top[0].Labels)
+	require.Equal(t, int64(1000), top[0].BytesRate)
+	require.Equal(t, int64(100), top[0].EntriesRate)
+	require.Equal(t, int64(1), top[0].Chunks)
+
+	top = inst.topStreams(&logproto.TopStreamsRequest{SortBy: TopStreamsByMemory, Limit: 1}, rates)
+	require.Len(t, top, 1)
+	require.Equal(t, `{app="large"}`, top[0].Labels)
+	require.Equal(t, int64(10), top[0].BytesRate)
+	require.Greater(t, top[0].MemoryBytes, int64(0))
+}
+
+func TestParseTopStreamsRequest(t *testing.T) {
+	for _, tc := range []struct {
+		query    string
+		expected *logproto.TopStreamsRequest
+	}{
+		{query: "", expected: &logproto.TopStreamsRequest{SortBy: TopStreamsByBytesRate, Limit: defaultTopStreamsLimit}},
+		{query: "sort=chunks&limit=3", expected: &logproto.TopStreamsRequest{SortBy: TopStreamsByChunks, Limit: 3}},
+		{query: "sort=unknown"},
+		{query: "limit=0"},
+		{query: "limit=abc"},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			req, err := ParseTopStreamsRequest(httptest.NewRequest("GET", "/ingester/streams/top?"+tc.query, nil))
+			if tc.expected == nil {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, req)
+		})
+	}
+}
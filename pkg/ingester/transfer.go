@@ -185,6 +185,9 @@ func (i *Ingester) stopIncomingRequests() {
 
 // TransferOut implements ring.Lifecycler.
 func (i *Ingester) TransferOut(ctx context.Context) error {
+	if i.cfg.Handoff.Enabled {
+		return i.handoffOut(ctx)
+	}
 	if i.cfg.MaxTransferRetries <= 0 {
 		return ring.ErrTransferDisabled
 	}
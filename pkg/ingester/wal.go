@@ -29,12 +29,18 @@ type WALConfig struct {
 	CheckpointDuration  time.Duration    `yaml:"checkpoint_duration"`
 	FlushOnShutdown     bool             `yaml:"flush_on_shutdown"`
 	ReplayMemoryCeiling flagext.ByteSize `yaml:"replay_memory_ceiling"`
+	Compression         string           `yaml:"compression"`
 }
 
 func (cfg *WALConfig) Validate() error {
 	if cfg.Enabled && cfg.CheckpointDuration < 1 {
 		return errors.Errorf("invalid checkpoint duration: %v", cfg.CheckpointDuration)
 	}
+	if cfg.Compression != "" {
+		if _, err := wal.ParseCompression(cfg.Compression); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -48,6 +54,7 @@ func (cfg *WALConfig) RegisterFlags(f *flag.FlagSet) {
 	// Need to set default here
 	cfg.ReplayMemoryCeiling = flagext.ByteSize(defaultCeiling)
 	f.Var(&cfg.ReplayMemoryCeiling, "ingester.wal-replay-memory-ceiling", "Maximum memory size the WAL may use during replay. After hitting this, it will flush data to storage before continuing. A unit suffix (KB, MB, GB) may be applied.")
+	f.StringVar(&cfg.Compression, "ingester.wal-compression", wal.CompressionNone.String(), "Compression of the records written to the WAL and to checkpoints. Supported values are: none, snappy and zstd. WALs and checkpoints written with any compression can be replayed.")
 }
 
 // WAL interface allows us to have a no-op WAL when the WAL is disabled.
@@ -66,10 +73,11 @@ func (noopWAL) Log(*wal.Record) error { return nil }
 func (noopWAL) Stop() error           { return nil }
 
 type walWrapper struct {
-	cfg        WALConfig
-	wal        *wlog.WL
-	metrics    *ingesterMetrics
-	seriesIter SeriesIter
+	cfg         WALConfig
+	compression wal.Compression
+	wal         *wlog.WL
+	metrics     *ingesterMetrics
+	seriesIter  SeriesIter
 
 	wait sync.WaitGroup
 	quit chan struct{}
@@ -81,17 +89,26 @@ func newWAL(cfg WALConfig, registerer prometheus.Registerer, metrics *ingesterMe
 		return noopWAL{}, nil
 	}
 
+	compression := wal.CompressionNone
+	if cfg.Compression != "" {
+		var err error
+		if compression, err = wal.ParseCompression(cfg.Compression); err != nil {
+			return nil, err
+		}
+	}
+
 	tsdbWAL, err := wlog.NewSize(util_log.Logger, registerer, cfg.Dir, walSegmentSize, false)
 	if err != nil {
 		return nil, err
 	}
 
 	w := &walWrapper{
-		cfg:        cfg,
-		quit:       make(chan struct{}),
-		wal:        tsdbWAL,
-		metrics:    metrics,
-		seriesIter: seriesIter,
+		cfg:         cfg,
+		compression: compression,
+		quit:        make(chan struct{}),
+		wal:         tsdbWAL,
+		metrics:     metrics,
+		seriesIter:  seriesIter,
 	}
 
 	return w, nil
@@ -110,33 +127,47 @@ func (w *walWrapper) Log(record *wal.Record) error {
 	case <-w.quit:
 		return nil
 	default:
-		buf := recordPool.GetBytes()
+		buf, compressed := recordPool.GetBytes(), recordPool.GetBytes()
 		defer func() {
 			recordPool.PutBytes(buf)
+			recordPool.PutBytes(compressed)
 		}()
 
 		// Always write series then entries.
 		if len(record.Series) > 0 {
 			*buf = record.EncodeSeries(*buf)
-			if err := w.wal.Log(*buf); err != nil {
+			if err := w.log(*buf, compressed); err != nil {
 				return err
 			}
-			w.metrics.walRecordsLogged.Inc()
-			w.metrics.walLoggedBytesTotal.Add(float64(len(*buf)))
 			*buf = (*buf)[:0]
+			*compressed = (*compressed)[:0]
 		}
 		if len(record.RefEntries) > 0 {
-			*buf = record.EncodeEntries(wal.CurrentEntriesRec, *buf)
-			if err := w.wal.Log(*buf); err != nil {
+			*buf = record.EncodeEntries(record.EntriesRecordType(), *buf)
+			if err := w.log(*buf, compressed); err != nil {
 				return err
 			}
-			w.metrics.walRecordsLogged.Inc()
-			w.metrics.walLoggedBytesTotal.Add(float64(len(*buf)))
 		}
 		return nil
 	}
 }
 
+// log compresses the record into compressed, if enabled, and writes it.
+func (w *walWrapper) log(rec []byte, compressed *[]byte) error {
+	logged := rec
+	if w.compression != wal.CompressionNone {
+		*compressed = w.compression.Compress(rec, *compressed)
+		logged = *compressed
+	}
+	if err := w.wal.Log(logged); err != nil {
+		return err
+	}
+	w.metrics.walRecordsLogged.Inc()
+	w.metrics.walLoggedBytesTotal.Add(float64(len(logged)))
+	w.metrics.walUncompressedBytesTotal.Add(float64(len(rec)))
+	return nil
+}
+
 func (w *walWrapper) Stop() error {
 	close(w.quit)
 	w.wait.Wait()
@@ -147,8 +178,9 @@ func (w *walWrapper) Stop() error {
 
 func (w *walWrapper) checkpointWriter() *WALCheckpointWriter {
 	return &WALCheckpointWriter{
-		metrics:    w.metrics,
-		segmentWAL: w.wal,
+		metrics:     w.metrics,
+		segmentWAL:  w.wal,
+		compression: w.compression,
 	}
 }
 
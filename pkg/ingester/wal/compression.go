@@ -0,0 +1,89 @@
+package wal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+// Compression is the compression of WAL and checkpoint records.
+type Compression byte
+
+const (
+	CompressionNone Compression = iota
+	CompressionSnappy
+	CompressionZstd
+)
+
+var supportedCompressions = []Compression{CompressionNone, CompressionSnappy, CompressionZstd}
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionSnappy:
+		return "snappy"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseCompression parses the name of a compression.
+func ParseCompression(s string) (Compression, error) {
+	names := make([]string, 0, len(supportedCompressions))
+	for _, c := range supportedCompressions {
+		if strings.EqualFold(c.String(), s) {
+			return c, nil
+		}
+		names = append(names, c.String())
+	}
+	return 0, fmt.Errorf("invalid WAL compression: %s, supported: %s", s, strings.Join(names, ", "))
+}
+
+var (
+	// EncodeAll and DecodeAll are safe for concurrent use.
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+)
+
+// Compress appends the compressed record to b, behind a CompressedRecord type header and the compression. Records
+// are returned unchanged without compression.
+func (c Compression) Compress(rec, b []byte) []byte {
+	switch c {
+	case CompressionSnappy:
+		b = append(b, byte(CompressedRecord), byte(c))
+		n := len(b)
+		b = append(b, make([]byte, snappy.MaxEncodedLen(len(rec)))...)
+		return b[:n+len(snappy.Encode(b[n:], rec))]
+	case CompressionZstd:
+		b = append(b, byte(CompressedRecord), byte(c))
+		return zstdEncoder.EncodeAll(rec, b)
+	default:
+		return rec
+	}
+}
+
+// Decompress returns the record that was compressed into rec. Records that aren't compressed are returned unchanged,
+// so WALs and checkpoints written without compression can still be replayed.
+func Decompress(rec []byte) ([]byte, error) {
+	if len(rec) == 0 || RecordType(rec[0]) != CompressedRecord {
+		return rec, nil
+	}
+	if len(rec) < 2 {
+		return nil, errors.New("compressed record without compression")
+	}
+
+	switch c := Compression(rec[1]); c {
+	case CompressionSnappy:
+		return snappy.Decode(nil, rec[2:])
+	case CompressionZstd:
+		return zstdDecoder.DecodeAll(rec[2:], nil)
+	default:
+		return nil, errors.Errorf("unknown record compression: %d", c)
+	}
+}
@@ -0,0 +1,61 @@
+package wal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func Test_Compression(t *testing.T) {
+	record := &Record{
+		entryIndexMap: make(map[uint64]int),
+		UserID:        "123",
+		RefEntries: []RefEntries{
+			{
+				Ref:     456,
+				Counter: 1,
+				Entries: []logproto.Entry{
+					{Timestamp: time.Unix(1000, 0), Line: "first line first line first line"},
+					{Timestamp: time.Unix(2000, 0), Line: "second line second line second line"},
+				},
+			},
+		},
+	}
+	buf := record.EncodeEntries(CurrentEntriesRec, nil)
+
+	for _, c := range supportedCompressions {
+		t.Run(c.String(), func(t *testing.T) {
+			parsed, err := ParseCompression(c.String())
+			require.NoError(t, err)
+			require.Equal(t, c, parsed)
+
+			compressed := c.Compress(buf, []byte("prefix"))
+			if c == CompressionNone {
+				require.Equal(t, buf, compressed)
+			} else {
+				require.Equal(t, "prefix", string(compressed[:6]))
+				compressed = compressed[6:]
+				require.Equal(t, CompressedRecord, RecordType(compressed[0]))
+				require.Less(t, len(compressed), len(buf))
+			}
+
+			decompressed, err := Decompress(compressed)
+			require.NoError(t, err)
+			require.Equal(t, buf, decompressed)
+
+			decoded := recordPool.GetRecord()
+			require.NoError(t, DecodeRecord(compressed, decoded))
+			decoded.entryIndexMap = record.entryIndexMap
+			require.Equal(t, record, decoded)
+		})
+	}
+
+	_, err := ParseCompression("lz4")
+	require.Error(t, err)
+
+	_, err = Decompress([]byte{byte(CompressedRecord), 42})
+	require.Error(t, err)
+}
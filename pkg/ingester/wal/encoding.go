@@ -8,6 +8,7 @@ import (
 	"github.com/prometheus/prometheus/tsdb/record"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/push"
 	"github.com/grafana/loki/pkg/util/encoding"
 )
 
@@ -25,10 +26,17 @@ const (
 	// WALRecordEntriesV2 is the type for the WAL record for samples with an
 	// additional counter value for use in replaying without the ordering constraint.
 	WALRecordEntriesV2
+	// WALRecordEntriesV3 is the type for the WAL record for samples with their
+	// structured metadata.
+	WALRecordEntriesV3
+	// CompressedRecord is the type for a compressed WAL or Checkpoint record. It is
+	// followed by the compression and the compressed record, including its type.
+	CompressedRecord
 )
 
 // The current type of Entries that this distribution writes.
 // Loki can read in a backwards compatible manner, but will write the newest variant.
+// Entries with structured metadata are written as WALRecordEntriesV3, see Record.EntriesRecordType.
 const CurrentEntriesRec = WALRecordEntriesV2
 
 // Record is a struct combining the series and samples record.
@@ -92,6 +100,20 @@ func (r *Record) EncodeSeries(b []byte) []byte {
 	return encoded
 }
 
+// EntriesRecordType returns the type of Entries record to write for the record.
+// WALRecordEntriesV3 is only used when an entry has structured metadata,
+// so that the WAL stays readable by versions which don't support it.
+func (r *Record) EntriesRecordType() RecordType {
+	for _, ref := range r.RefEntries {
+		for _, e := range ref.Entries {
+			if len(e.StructuredMetadata) > 0 {
+				return WALRecordEntriesV3
+			}
+		}
+	}
+	return CurrentEntriesRec
+}
+
 func (r *Record) EncodeEntries(version RecordType, b []byte) []byte {
 	buf := encoding.EncWith(b)
 	buf.PutByte(byte(version))
@@ -128,6 +150,14 @@ outer:
 			buf.PutVarint64(s.Timestamp.UnixNano() - first)
 			buf.PutUvarint(len(s.Line))
 			buf.PutString(s.Line)
+
+			if version >= WALRecordEntriesV3 {
+				buf.PutUvarint(len(s.StructuredMetadata))
+				for _, l := range s.StructuredMetadata {
+					buf.PutUvarintStr(l.Name)
+					buf.PutUvarintStr(l.Value)
+				}
+			}
 		}
 	}
 	return buf.Get()
@@ -158,9 +188,21 @@ func DecodeEntries(b []byte, version RecordType, rec *Record) error {
 			lineLength := dec.Uvarint()
 			line := dec.Bytes(lineLength)
 
+			var metadata push.LabelsAdapter
+			if version >= WALRecordEntriesV3 {
+				if n := dec.Uvarint(); n > 0 && dec.Err() == nil {
+					metadata = make(push.LabelsAdapter, n)
+					for i := range metadata {
+						metadata[i].Name = dec.UvarintStr()
+						metadata[i].Value = dec.UvarintStr()
+					}
+				}
+			}
+
 			refEntries.Entries = append(refEntries.Entries, logproto.Entry{
-				Timestamp: time.Unix(0, baseTime+timeOffset),
-				Line:      string(line),
+				Timestamp:          time.Unix(0, baseTime+timeOffset),
+				Line:               string(line),
+				StructuredMetadata: metadata,
 			})
 		}
 
@@ -192,10 +234,16 @@ func DecodeRecord(b []byte, walRec *Record) (err error) {
 	)
 
 	switch t {
+	case CompressedRecord:
+		decompressed, err := Decompress(b)
+		if err != nil {
+			return errors.Wrap(err, "decompress record")
+		}
+		return DecodeRecord(decompressed, walRec)
 	case WALRecordSeries:
 		userID = decbuf.UvarintStr()
 		rSeries, err = dec.Series(decbuf.B, walRec.Series)
-	case WALRecordEntriesV1, WALRecordEntriesV2:
+	case WALRecordEntriesV1, WALRecordEntriesV2, WALRecordEntriesV3:
 		userID = decbuf.UvarintStr()
 		err = DecodeEntries(decbuf.B, t, walRec)
 	default:
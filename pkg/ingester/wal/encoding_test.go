@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/push"
 )
 
 var (
@@ -132,6 +133,34 @@ func Test_Encoding_Entries(t *testing.T) {
 			},
 			version: WALRecordEntriesV2,
 		},
+		{
+			desc: "v3",
+			rec: &Record{
+				entryIndexMap: make(map[uint64]int),
+				UserID:        "123",
+				RefEntries: []RefEntries{
+					{
+						Ref:     456,
+						Counter: 1,
+						Entries: []logproto.Entry{
+							{
+								Timestamp: time.Unix(1000, 0),
+								Line:      "first",
+								StructuredMetadata: push.LabelsAdapter{
+									{Name: "trace_id", Value: "abc"},
+									{Name: "user_id", Value: "42"},
+								},
+							},
+							{
+								Timestamp: time.Unix(2000, 0),
+								Line:      "second",
+							},
+						},
+					},
+				},
+			},
+			version: WALRecordEntriesV3,
+		},
 	} {
 		decoded := recordPool.GetRecord()
 		buf := tc.rec.EncodeEntries(tc.version, nil)
@@ -142,6 +171,26 @@ func Test_Encoding_Entries(t *testing.T) {
 	}
 }
 
+func TestEntriesRecordType(t *testing.T) {
+	rec := &Record{
+		UserID: "123",
+		RefEntries: []RefEntries{
+			{Ref: 456, Entries: []logproto.Entry{{Timestamp: time.Unix(1000, 0), Line: "first"}}},
+		},
+	}
+	require.Equal(t, WALRecordEntriesV2, rec.EntriesRecordType())
+
+	rec.RefEntries = append(rec.RefEntries, RefEntries{
+		Ref: 789,
+		Entries: []logproto.Entry{{
+			Timestamp:          time.Unix(2000, 0),
+			Line:               "second",
+			StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "abc"}},
+		}},
+	})
+	require.Equal(t, WALRecordEntriesV3, rec.EntriesRecordType())
+}
+
 func Benchmark_EncodeEntries(b *testing.B) {
 	var entries []logproto.Entry
 	for i := int64(0); i < 10000; i++ {
@@ -44,7 +44,7 @@ type Client interface {
 	ListLabelNames(quiet bool, start, end time.Time) (*loghttp.LabelResponse, error)
 	ListLabelValues(name string, quiet bool, start, end time.Time) (*loghttp.LabelResponse, error)
 	Series(matchers []string, start, end time.Time, quiet bool) (*loghttp.SeriesResponse, error)
-	LiveTailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, quiet bool) (*websocket.Conn, error)
+	LiveTailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, cursor string, quiet bool) (*websocket.Conn, error)
 	GetOrgID() string
 }
 
@@ -148,8 +148,9 @@ func (c *DefaultClient) Series(matchers []string, start, end time.Time, quiet bo
 	return &seriesResponse, nil
 }
 
-// LiveTailQueryConn uses /api/prom/tail to set up a websocket connection and returns it
-func (c *DefaultClient) LiveTailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, quiet bool) (*websocket.Conn, error) {
+// LiveTailQueryConn uses /api/prom/tail to set up a websocket connection and returns it.
+// A non empty cursor resumes a previous tail after the entries it received.
+func (c *DefaultClient) LiveTailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, cursor string, quiet bool) (*websocket.Conn, error) {
 	params := util.NewQueryStringBuilder()
 	params.SetString("query", queryStr)
 	if delayFor != 0 {
@@ -157,6 +158,9 @@ func (c *DefaultClient) LiveTailQueryConn(queryStr string, delayFor time.Duratio
 	}
 	params.SetInt("limit", int64(limit))
 	params.SetInt("start", start.UnixNano())
+	if cursor != "" {
+		params.SetString("cursor", cursor)
+	}
 
 	return c.wsConnect(tailPath, params.Encode(), quiet)
 }
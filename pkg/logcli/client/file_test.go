@@ -188,7 +188,7 @@ func TestFileClient_Series(t *testing.T) {
 
 func TestFileClient_LiveTail(t *testing.T) {
 	c := newEmptyClient(t)
-	x, err := c.LiveTailQueryConn("", time.Second, 0, time.Now(), true)
+	x, err := c.LiveTailQueryConn("", time.Second, 0, time.Now(), "", true)
 	require.Error(t, err)
 	require.Nil(t, x)
 	assert.True(t, errors.Is(err, ErrNotSupported))
@@ -29,6 +29,7 @@ import (
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/loki"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
 	"github.com/grafana/loki/pkg/storage"
 	chunk "github.com/grafana/loki/pkg/storage/chunk/client"
 	"github.com/grafana/loki/pkg/storage/config"
@@ -135,6 +136,7 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 		if statistics {
 			q.printStats(resp.Data.Statistics)
 		}
+		q.printWarnings(resp.Warnings)
 		_, _ = q.printResult(resp.Data.Result, out, nil)
 	} else {
 		unlimited := q.Limit == 0
@@ -166,6 +168,7 @@ func (q *Query) DoQuery(c client.Client, out output.LogOutput, statistics bool)
 			if statistics {
 				q.printStats(resp.Data.Statistics)
 			}
+			q.printWarnings(resp.Warnings)
 
 			resultLength, lastEntry = q.printResult(resp.Data.Result, out, lastEntry)
 			// Was not a log stream query, or no results, no more batching
@@ -725,6 +728,13 @@ func (q *Query) printStats(stats stats.Result) {
 	stats.Log(kvLogger{Writer: writer})
 }
 
+// printWarnings prints the warnings of a partial result, which describe its missing time ranges and shards.
+func (q *Query) printWarnings(warnings []definitions.QueryWarning) {
+	for _, w := range warnings {
+		log.Println(color.YellowString("Partial result:"), w.Message)
+	}
+}
+
 func (q *Query) resultsDirection() logproto.Direction {
 	if q.Forward {
 		return logproto.FORWARD
@@ -602,7 +602,7 @@ func (t *testQueryClient) Series(matchers []string, from, through time.Time, qui
 	panic("implement me")
 }
 
-func (t *testQueryClient) LiveTailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, quiet bool) (*websocket.Conn, error) {
+func (t *testQueryClient) LiveTailQueryConn(queryStr string, delayFor time.Duration, limit int, start time.Time, cursor string, quiet bool) (*websocket.Conn, error) {
 	panic("implement me")
 }
 
@@ -21,7 +21,7 @@ import (
 
 // TailQuery connects to the Loki websocket endpoint and tails logs
 func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.LogOutput) {
-	conn, err := c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, q.Start, q.Quiet)
+	conn, err := c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, q.Start, "", q.Quiet)
 	if err != nil {
 		log.Fatalf("Tailing logs failed: %+v", err)
 	}
@@ -46,6 +46,8 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 
 	tailResponse := new(loghttp.TailResponse)
 	lastReceivedTimestamp := q.Start
+	// the cursor of the last response, used to resume the tail without missing or repeating entries.
+	lastCursor := ""
 
 	for {
 		err := unmarshal.ReadTailResponseJSON(tailResponse, conn)
@@ -70,7 +72,7 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 				})
 
 				for backoff.Ongoing() {
-					conn, err = c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, lastReceivedTimestamp, q.Quiet)
+					conn, err = c.LiveTailQueryConn(q.QueryString, delayFor, q.Limit, lastReceivedTimestamp, lastCursor, q.Quiet)
 					if err == nil {
 						break
 					}
@@ -119,6 +121,9 @@ func (q *Query) TailQuery(delayFor time.Duration, c client.Client, out output.Lo
 			}
 
 		}
+		if tailResponse.Cursor != "" {
+			lastCursor = tailResponse.Cursor
+		}
 		if len(tailResponse.DroppedStreams) != 0 {
 			log.Println("Server dropped following entries due to slow client")
 			for _, d := range tailResponse.DroppedStreams {
@@ -16,4 +16,5 @@ type DroppedEntry struct {
 type TailResponse struct {
 	Streams        []logproto.Stream `json:"streams"`
 	DroppedEntries []DroppedEntry    `json:"dropped_entries"`
+	Cursor         string            `json:"cursor,omitempty"`
 }
@@ -0,0 +1,392 @@
+package push
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// ElasticsearchVersion is the Elasticsearch version that is reported to clients of the bulk API.
+// Clients check it to choose the request format they send.
+const ElasticsearchVersion = "8.0.0"
+
+const (
+	esActionIndex  = "index"
+	esActionCreate = "create"
+	esActionUpdate = "update"
+	esActionDelete = "delete"
+
+	esMapperParsingException           = "mapper_parsing_exception"
+	esIllegalArgumentException         = "illegal_argument_exception"
+	esRejectedExecutionException       = "es_rejected_execution_exception"
+	esUnavailableShardsException       = "unavailable_shards_exception"
+	esActionRequestValidationException = "action_request_validation_exception"
+)
+
+// ElasticsearchConfig configures how documents that are pushed via the Elasticsearch bulk API are mapped to Loki streams.
+type ElasticsearchConfig struct {
+	LabelFields    flagext.StringSliceCSV `yaml:"label_fields" json:"label_fields"`
+	IndexLabel     string                 `yaml:"index_label" json:"index_label"`
+	TimestampField string                 `yaml:"timestamp_field" json:"timestamp_field"`
+	MessageField   string                 `yaml:"message_field" json:"message_field"`
+}
+
+// RegisterFlagsWithPrefix adds the flags required to config this to the given FlagSet.
+func (cfg *ElasticsearchConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	cfg.LabelFields = []string{"host.name", "service.name"}
+	f.Var(&cfg.LabelFields, prefix+"label-fields", "Comma-separated list of document fields that are turned into stream labels. Nested fields are addressed with dots, which are replaced by underscores in the label names.")
+	f.StringVar(&cfg.IndexLabel, prefix+"index-label", "index", "Name of the stream label that holds the index a document is written to. The index is not added to the labels when empty.")
+	f.StringVar(&cfg.TimestampField, prefix+"timestamp-field", "@timestamp", "Document field that holds the timestamp of the log line, either in RFC3339 format or as milliseconds since epoch. The time of the request is used for documents without it.")
+	f.StringVar(&cfg.MessageField, prefix+"message-field", "message", "Document field that holds the log line. The whole document is used as log line if it does not have this field.")
+}
+
+// ElasticsearchBulk parses requests to the Elasticsearch bulk API and keeps the result of each of their items,
+// which are reported back to the client after the request is pushed.
+//
+// The items that are pushed only get their result once the push returns, see WriteResponse and WriteError.
+type ElasticsearchBulk struct {
+	start   time.Time
+	parsed  bool
+	items   []map[string]*elasticsearchBulkItem
+	pending []*elasticsearchBulkItem
+}
+
+type elasticsearchBulkItem struct {
+	Index  string                  `json:"_index"`
+	ID     string                  `json:"_id,omitempty"`
+	Result string                  `json:"result,omitempty"`
+	Status int                     `json:"status"`
+	Error  *elasticsearchBulkError `json:"error,omitempty"`
+}
+
+type elasticsearchBulkError struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+type elasticsearchBulkResponse struct {
+	Took   int64                               `json:"took"`
+	Errors bool                                `json:"errors"`
+	Items  []map[string]*elasticsearchBulkItem `json:"items"`
+}
+
+type elasticsearchErrorResponse struct {
+	Error  elasticsearchBulkError `json:"error"`
+	Status int                    `json:"status"`
+}
+
+// Parse decodes the newline delimited actions and documents of a bulk request into a Loki push request.
+// It implements RequestParser.
+//
+// Only index and create actions are supported. Documents that are invalid, and update and delete actions,
+// are reported as failed items, which doesn't fail the whole request.
+func (b *ElasticsearchBulk) Parse(userID string, r *http.Request, body io.Reader, _ string, limits Limits) (*logproto.PushRequest, error) {
+	var cfg ElasticsearchConfig
+	if limits != nil {
+		cfg = limits.ElasticsearchConfig(userID)
+	}
+	b.start = time.Now()
+	return b.parse(bufio.NewReader(body), mux.Vars(r)["index"], cfg, b.start)
+}
+
+func (b *ElasticsearchBulk) parse(body *bufio.Reader, defaultIndex string, cfg ElasticsearchConfig, now time.Time) (*logproto.PushRequest, error) {
+	var (
+		req     = &logproto.PushRequest{}
+		streams = map[string]int{}
+	)
+
+	for {
+		line, err := readNDJSONLine(body)
+		if err == io.EOF {
+			b.parsed = true
+			return req, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var action map[string]struct {
+			Index string `json:"_index"`
+			ID    string `json:"_id"`
+		}
+		if err := json.Unmarshal(line, &action); err != nil || len(action) != 1 {
+			return nil, fmt.Errorf("malformed action/metadata line [%d], expected a single action", len(b.items)+1)
+		}
+
+		for name, meta := range action {
+			item := &elasticsearchBulkItem{Index: meta.Index, ID: meta.ID}
+			if item.Index == "" {
+				item.Index = defaultIndex
+			}
+			b.items = append(b.items, map[string]*elasticsearchBulkItem{name: item})
+
+			if name == esActionDelete {
+				item.fail(http.StatusBadRequest, esIllegalArgumentException, "delete actions are not supported")
+				continue
+			}
+
+			source, err := readNDJSONLine(body)
+			if err == io.EOF {
+				return nil, fmt.Errorf("missing document of action/metadata line [%d]", len(b.items))
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			switch name {
+			case esActionIndex, esActionCreate:
+			case esActionUpdate:
+				item.fail(http.StatusBadRequest, esIllegalArgumentException, "update actions are not supported")
+				continue
+			default:
+				return nil, fmt.Errorf("malformed action/metadata line [%d], unknown action %q", len(b.items), name)
+			}
+			if item.Index == "" {
+				item.fail(http.StatusBadRequest, esIllegalArgumentException, "index is missing")
+				continue
+			}
+
+			lbs, entry, err := documentToEntry(source, item.Index, cfg, now)
+			if err != nil {
+				item.fail(http.StatusBadRequest, esMapperParsingException, err.Error())
+				continue
+			}
+			b.pending = append(b.pending, item)
+
+			labels := lbs.String()
+			idx, ok := streams[labels]
+			if !ok {
+				idx = len(req.Streams)
+				streams[labels] = idx
+				req.Streams = append(req.Streams, logproto.Stream{
+					Labels: labels,
+					Hash:   uint64(lbs.Fingerprint()),
+				})
+			}
+			req.Streams[idx].Entries = append(req.Streams[idx].Entries, entry)
+		}
+	}
+}
+
+// WriteResponse writes the Elasticsearch bulk API response for the items of the successfully pushed request.
+func (b *ElasticsearchBulk) WriteResponse(w http.ResponseWriter) {
+	for _, item := range b.pending {
+		item.Result = "created"
+		item.Status = http.StatusCreated
+	}
+	b.write(w)
+}
+
+// WriteError writes the Elasticsearch error response for a request that failed with the given status code.
+//
+// If the request was parsed, the items that were pushed fail with the status code, so that clients retry
+// them on 429 and 5xx codes like for Elasticsearch. Requests that could not be parsed fail as a whole.
+// It has the signature of http.Error.
+func (b *ElasticsearchBulk) WriteError(w http.ResponseWriter, reason string, code int) {
+	reason = strings.TrimSpace(reason)
+	if !b.parsed {
+		w.Header().Set("Content-Type", applicationJSON)
+		w.Header().Set("X-Elastic-Product", "Elasticsearch")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(elasticsearchErrorResponse{
+			Error:  elasticsearchBulkError{Type: elasticsearchErrorType(code), Reason: reason},
+			Status: code,
+		})
+		return
+	}
+	for _, item := range b.pending {
+		item.fail(code, elasticsearchErrorType(code), reason)
+	}
+	b.write(w)
+}
+
+func (b *ElasticsearchBulk) write(w http.ResponseWriter) {
+	resp := elasticsearchBulkResponse{
+		Took:  time.Since(b.start).Milliseconds(),
+		Items: b.items,
+	}
+	for _, item := range b.items {
+		for _, result := range item {
+			resp.Errors = resp.Errors || result.Error != nil
+		}
+	}
+
+	w.Header().Set("Content-Type", applicationJSON)
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// elasticsearchErrorType returns the type of the error Elasticsearch reports for the status code.
+func elasticsearchErrorType(code int) string {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return esRejectedExecutionException
+	case code >= http.StatusInternalServerError:
+		return esUnavailableShardsException
+	case code == http.StatusBadRequest:
+		return esActionRequestValidationException
+	default:
+		return esIllegalArgumentException
+	}
+}
+
+// WriteElasticsearchInfo writes the response of the Elasticsearch root endpoint,
+// which clients request to detect the version of the cluster.
+func WriteElasticsearchInfo(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", applicationJSON)
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"name":         "loki",
+		"cluster_name": "loki",
+		"version": map[string]string{
+			"number":         ElasticsearchVersion,
+			"build_flavor":   "default",
+			"lucene_version": "9.0.0",
+		},
+		"tagline": "You Know, for Search",
+	})
+}
+
+func (i *elasticsearchBulkItem) fail(status int, typ, reason string) {
+	i.Status = status
+	i.Error = &elasticsearchBulkError{Type: typ, Reason: reason}
+}
+
+// readNDJSONLine returns the next non-empty line.
+func readNDJSONLine(r *bufio.Reader) ([]byte, error) {
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil && (err != io.EOF || len(line) == 0) {
+			return nil, err
+		}
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			return line, nil
+		}
+		if err == io.EOF {
+			return nil, err
+		}
+	}
+}
+
+func documentToEntry(source []byte, index string, cfg ElasticsearchConfig, now time.Time) (model.LabelSet, logproto.Entry, error) {
+	dec := json.NewDecoder(bytes.NewReader(source))
+	dec.UseNumber()
+	var doc map[string]interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, logproto.Entry{}, fmt.Errorf("failed to parse document: %w", err)
+	}
+
+	lbs := model.LabelSet{}
+	for _, field := range cfg.LabelFields {
+		if value, ok := documentField(doc, field); ok {
+			if s := fieldToString(value); s != "" {
+				lbs[model.LabelName(strutil.SanitizeLabelName(field))] = model.LabelValue(s)
+			}
+		}
+	}
+	if cfg.IndexLabel != "" {
+		lbs[model.LabelName(cfg.IndexLabel)] = model.LabelValue(index)
+	}
+	if len(lbs) == 0 {
+		lbs["service_name"] = unknownServiceName
+	}
+
+	entry := logproto.Entry{Timestamp: now, Line: string(source)}
+	if value, ok := documentField(doc, cfg.TimestampField); ok {
+		ts, err := parseDocumentTimestamp(value)
+		if err != nil {
+			return nil, logproto.Entry{}, fmt.Errorf("failed to parse field [%s]: %w", cfg.TimestampField, err)
+		}
+		entry.Timestamp = ts
+	}
+	if value, ok := documentField(doc, cfg.MessageField); ok {
+		entry.Line = fieldToString(value)
+	}
+	return lbs, entry, nil
+}
+
+// documentField returns the value of a field. The field is looked up by its full name first,
+// and otherwise as a path of nested objects separated by dots.
+func documentField(doc map[string]interface{}, field string) (interface{}, bool) {
+	if field == "" {
+		return nil, false
+	}
+	if value, ok := doc[field]; ok {
+		return value, value != nil
+	}
+	for i := strings.IndexByte(field, '.'); i > 0; i = nextDot(field, i) {
+		if nested, ok := doc[field[:i]].(map[string]interface{}); ok {
+			if value, ok := documentField(nested, field[i+1:]); ok {
+				return value, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func nextDot(s string, i int) int {
+	if j := strings.IndexByte(s[i+1:], '.'); j >= 0 {
+		return i + 1 + j
+	}
+	return -1
+}
+
+// fieldToString renders a field value as a string. Arrays and objects are rendered as JSON.
+func fieldToString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case nil:
+		return ""
+	default:
+		buf, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(buf)
+	}
+}
+
+// parseDocumentTimestamp parses dates in the formats Elasticsearch accepts by default,
+// which are RFC3339 and milliseconds since epoch.
+func parseDocumentTimestamp(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return ts, nil
+		}
+		if ms, err := json.Number(v).Int64(); err == nil {
+			return time.UnixMilli(ms), nil
+		}
+		return time.Time{}, fmt.Errorf("failed to parse date %q", v)
+	case json.Number:
+		ms, err := v.Int64()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.UnixMilli(ms), nil
+	default:
+		return time.Time{}, errors.New("date must be a string or a number")
+	}
+}
@@ -0,0 +1,166 @@
+package push
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+var testElasticsearchConfig = ElasticsearchConfig{
+	LabelFields:    []string{"host.name", "service.name"},
+	IndexLabel:     "index",
+	TimestampField: "@timestamp",
+	MessageField:   "message",
+}
+
+func TestElasticsearchBulkParse(t *testing.T) {
+	body := strings.Join([]string{
+		`{"index":{"_index":"filebeat","_id":"1"}}`,
+		`{"@timestamp":"2023-01-02T03:04:05.000000006Z","message":"hello","host":{"name":"node-1"}}`,
+		`{"create":{}}`,
+		`{"@timestamp":1672628645000,"message":"world","host.name":"node-1"}`,
+		``,
+		`{"index":{"_index":"app"}}`,
+		`{"level":"info","service":{"name":"api"}}`,
+		`{"delete":{"_index":"filebeat","_id":"1"}}`,
+		`{"update":{"_index":"filebeat","_id":"1"}}`,
+		`{"doc":{"message":"updated"}}`,
+		`{"index":{"_index":"filebeat"}}`,
+		`{"@timestamp":"yesterday","message":"invalid"}`,
+		`{"index":{"_index":"filebeat"}}`,
+		`not json`,
+	}, "\n")
+
+	var bulk ElasticsearchBulk
+	now := time.Unix(100, 0)
+	req, err := bulk.parse(bufio.NewReader(strings.NewReader(body)), "filebeat", testElasticsearchConfig, now)
+	require.NoError(t, err)
+
+	require.Len(t, req.Streams, 2)
+	require.Equal(t, `{host_name="node-1", index="filebeat"}`, req.Streams[0].Labels)
+	require.Equal(t, []logproto.Entry{
+		{Timestamp: time.Date(2023, 1, 2, 3, 4, 5, 6, time.UTC), Line: "hello"},
+		{Timestamp: time.UnixMilli(1672628645000), Line: "world"},
+	}, req.Streams[0].Entries)
+	require.Equal(t, `{index="app", service_name="api"}`, req.Streams[1].Labels)
+	require.Equal(t, []logproto.Entry{
+		{Timestamp: now, Line: `{"level":"info","service":{"name":"api"}}`},
+	}, req.Streams[1].Entries)
+
+	var statuses []int
+	for _, item := range bulk.items {
+		for _, result := range item {
+			statuses = append(statuses, result.Status)
+		}
+	}
+	// The pushed items have no result until the push returns.
+	require.Equal(t, []int{0, 0, 0, 400, 400, 400, 400}, statuses)
+	require.Len(t, bulk.pending, 3)
+	require.Equal(t, "1", bulk.items[0]["index"].ID)
+	require.Equal(t, "filebeat", bulk.items[1]["create"].Index)
+	require.Equal(t, esMapperParsingException, bulk.items[5]["index"].Error.Type)
+}
+
+func TestElasticsearchBulkParseMalformed(t *testing.T) {
+	for name, body := range map[string]string{
+		"invalid action":   "not json\n{}\n",
+		"multiple actions": `{"index":{},"create":{}}` + "\n{}\n",
+		"unknown action":   `{"upsert":{}}` + "\n{}\n",
+		"missing document": `{"index":{}}` + "\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			var bulk ElasticsearchBulk
+			_, err := bulk.parse(bufio.NewReader(strings.NewReader(body)), "filebeat", testElasticsearchConfig, time.Now())
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestElasticsearchBulkResponse(t *testing.T) {
+	body := `{"index":{}}` + "\n" + `{"message":"hello"}` + "\n" + `{"delete":{"_id":"1"}}` + "\n"
+	limits := fakeLimits{esCfg: testElasticsearchConfig}
+
+	var bulk ElasticsearchBulk
+	router := mux.NewRouter()
+	router.Path("/elasticsearch/{index}/_bulk").HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req, err := ParseRequest(util_log.Logger, "fake", r, nil, limits, bulk.Parse)
+		require.NoError(t, err)
+		require.Len(t, req.Streams, 1)
+		require.Equal(t, `{index="logs"}`, req.Streams[0].Labels)
+		bulk.WriteResponse(w)
+	})
+
+	r := httptest.NewRequest("POST", "/elasticsearch/logs/_bulk", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp elasticsearchBulkResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.True(t, resp.Errors)
+	require.Len(t, resp.Items, 2)
+	require.Equal(t, &elasticsearchBulkItem{Index: "logs", Result: "created", Status: http.StatusCreated}, resp.Items[0]["index"])
+	require.Equal(t, http.StatusBadRequest, resp.Items[1]["delete"].Status)
+	require.Equal(t, esIllegalArgumentException, resp.Items[1]["delete"].Error.Type)
+}
+
+func TestElasticsearchBulkWriteError(t *testing.T) {
+	body := `{"index":{}}` + "\n" + `{"message":"hello"}` + "\n" + `{"delete":{"_id":"1"}}` + "\n"
+
+	for _, tc := range []struct {
+		code      int
+		errorType string
+	}{
+		{http.StatusTooManyRequests, esRejectedExecutionException},
+		{http.StatusInternalServerError, esUnavailableShardsException},
+		{http.StatusBadRequest, esActionRequestValidationException},
+	} {
+		t.Run(http.StatusText(tc.code), func(t *testing.T) {
+			var bulk ElasticsearchBulk
+			_, err := bulk.parse(bufio.NewReader(strings.NewReader(body)), "logs", testElasticsearchConfig, time.Now())
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			bulk.WriteError(w, "push failed\n", tc.code)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp elasticsearchBulkResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.True(t, resp.Errors)
+			require.Len(t, resp.Items, 2)
+			require.Equal(t, &elasticsearchBulkItem{
+				Index:  "logs",
+				Status: tc.code,
+				Error:  &elasticsearchBulkError{Type: tc.errorType, Reason: "push failed"},
+			}, resp.Items[0]["index"])
+			require.Equal(t, esIllegalArgumentException, resp.Items[1]["delete"].Error.Type)
+		})
+	}
+
+	t.Run("malformed request", func(t *testing.T) {
+		var bulk ElasticsearchBulk
+		_, err := bulk.parse(bufio.NewReader(strings.NewReader(`{"index":{}}`+"\n")), "logs", testElasticsearchConfig, time.Now())
+		require.Error(t, err)
+
+		w := httptest.NewRecorder()
+		bulk.WriteError(w, err.Error(), http.StatusBadRequest)
+
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var resp elasticsearchErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		require.Equal(t, esActionRequestValidationException, resp.Error.Type)
+		require.Equal(t, err.Error(), resp.Error.Reason)
+	})
+}
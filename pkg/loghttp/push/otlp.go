@@ -0,0 +1,305 @@
+package push
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logfmt/logfmt"
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/util/strutil"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const (
+	applicationProtobuf = "application/x-protobuf"
+
+	// unknownServiceName is the service name OpenTelemetry SDKs use if none is configured.
+	// It is used as label of logs that have none of the configured attributes.
+	unknownServiceName = "unknown_service"
+)
+
+// OTLPConfig configures how logs that are pushed via OTLP are mapped to Loki streams.
+// The configured resource and scope attributes become stream labels, with their names sanitized
+// to valid label names. All other attributes are rendered into the log line.
+type OTLPConfig struct {
+	ResourceAttributesAsLabels flagext.StringSliceCSV `yaml:"resource_attributes_as_labels" json:"resource_attributes_as_labels"`
+	ScopeAttributesAsLabels    flagext.StringSliceCSV `yaml:"scope_attributes_as_labels" json:"scope_attributes_as_labels"`
+}
+
+// RegisterFlagsWithPrefix adds the flags required to config this to the given FlagSet.
+func (cfg *OTLPConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	cfg.ResourceAttributesAsLabels = []string{
+		"service.name",
+		"service.namespace",
+		"service.instance.id",
+		"deployment.environment",
+		"cloud.region",
+		"cloud.availability_zone",
+		"k8s.cluster.name",
+		"k8s.namespace.name",
+		"k8s.pod.name",
+		"k8s.container.name",
+	}
+	f.Var(&cfg.ResourceAttributesAsLabels, prefix+"resource-attributes-as-labels", "Comma-separated list of OTLP resource attributes that are turned into stream labels. Dots in attribute names are replaced by underscores.")
+	f.Var(&cfg.ScopeAttributesAsLabels, prefix+"scope-attributes-as-labels", "Comma-separated list of OTLP instrumentation scope attributes that are turned into stream labels. Dots in attribute names are replaced by underscores.")
+}
+
+// NewOTLPRequestParser returns a RequestParser that decodes an OTLP logs export request, either protobuf or JSON
+// encoded, into a Loki push request. Requests whose decompressed body is larger than maxRecvMsgSize are rejected.
+//
+// Log records that share the same labels form a stream. The line of an entry is the body of its log record,
+// followed by all attributes that did not become labels in logfmt format: the remaining resource and scope
+// attributes, the scope name and version, the severity, the trace and span ID and the attributes of the record.
+func NewOTLPRequestParser(maxRecvMsgSize int) RequestParser {
+	return func(userID string, r *http.Request, body io.Reader, contentType string, limits Limits) (*logproto.PushRequest, error) {
+		return parseOTLPRequest(userID, body, contentType, limits, maxRecvMsgSize)
+	}
+}
+
+func parseOTLPRequest(userID string, body io.Reader, contentType string, limits Limits, maxRecvMsgSize int) (*logproto.PushRequest, error) {
+	buf, err := io.ReadAll(io.LimitReader(body, int64(maxRecvMsgSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(buf) > maxRecvMsgSize {
+		return nil, fmt.Errorf("OTLP request too large: the decompressed body is larger than the limit of %d bytes", maxRecvMsgSize)
+	}
+
+	// An ExportLogsServiceRequest has the same encoding as LogsData.
+	var logs logsv1.LogsData
+	switch contentType {
+	case applicationProtobuf:
+		err = proto.Unmarshal(buf, &logs)
+	case applicationJSON:
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(buf, &logs)
+	default:
+		return nil, fmt.Errorf("Content-Type %q not supported, expected %s or %s", contentType, applicationProtobuf, applicationJSON)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode OTLP logs: %w", err)
+	}
+
+	var cfg OTLPConfig
+	if limits != nil {
+		cfg = limits.OTLPConfig(userID)
+	}
+	return otlpToPushRequest(&logs, cfg, time.Now()), nil
+}
+
+// WriteOTLPResponse writes the response of a successful OTLP logs export request, which is an
+// ExportLogsServiceResponse encoded like the request. Loki accepts all or none of the log records of a request,
+// so the response never holds a partial success and is an empty message.
+func WriteOTLPResponse(w http.ResponseWriter, r *http.Request) {
+	contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if contentType == applicationJSON {
+		w.Header().Set("Content-Type", applicationJSON)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("{}"))
+		return
+	}
+	w.Header().Set("Content-Type", applicationProtobuf)
+	w.WriteHeader(http.StatusOK)
+}
+
+func otlpToPushRequest(logs *logsv1.LogsData, cfg OTLPConfig, now time.Time) *logproto.PushRequest {
+	var (
+		req     = &logproto.PushRequest{}
+		streams = map[string]int{}
+	)
+
+	for _, rl := range logs.ResourceLogs {
+		resourceLabels := model.LabelSet{}
+		var resourceAttrs []*commonv1.KeyValue
+		if rl.Resource != nil {
+			resourceAttrs = attributesToLabels(rl.Resource.Attributes, cfg.ResourceAttributesAsLabels, resourceLabels)
+		}
+
+		for _, sl := range rl.ScopeLogs {
+			if len(sl.LogRecords) == 0 {
+				continue
+			}
+			lbs := resourceLabels.Clone()
+			var scopeAttrs []*commonv1.KeyValue
+			if sl.Scope != nil {
+				scopeAttrs = attributesToLabels(sl.Scope.Attributes, cfg.ScopeAttributesAsLabels, lbs)
+			}
+			if len(lbs) == 0 {
+				lbs["service_name"] = unknownServiceName
+			}
+			labels := lbs.String()
+
+			idx, ok := streams[labels]
+			if !ok {
+				idx = len(req.Streams)
+				streams[labels] = idx
+				req.Streams = append(req.Streams, logproto.Stream{
+					Labels: labels,
+					Hash:   uint64(lbs.Fingerprint()),
+				})
+			}
+
+			for _, record := range sl.LogRecords {
+				req.Streams[idx].Entries = append(req.Streams[idx].Entries, logproto.Entry{
+					Timestamp: recordTimestamp(record, now),
+					Line:      recordLine(record, sl.Scope, resourceAttrs, scopeAttrs),
+				})
+			}
+		}
+	}
+
+	return req
+}
+
+// attributesToLabels adds the attributes of the given names to the label set and returns the remaining attributes.
+func attributesToLabels(attrs []*commonv1.KeyValue, names []string, lbs model.LabelSet) []*commonv1.KeyValue {
+	if len(names) == 0 {
+		return attrs
+	}
+
+	remaining := make([]*commonv1.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		if !contains(names, attr.Key) {
+			remaining = append(remaining, attr)
+			continue
+		}
+		if value := anyValueToString(attr.Value); value != "" {
+			lbs[model.LabelName(strutil.SanitizeLabelName(attr.Key))] = model.LabelValue(value)
+		}
+	}
+	return remaining
+}
+
+func recordTimestamp(record *logsv1.LogRecord, now time.Time) time.Time {
+	switch {
+	case record.TimeUnixNano != 0:
+		return time.Unix(0, int64(record.TimeUnixNano))
+	case record.ObservedTimeUnixNano != 0:
+		return time.Unix(0, int64(record.ObservedTimeUnixNano))
+	default:
+		return now
+	}
+}
+
+func recordLine(record *logsv1.LogRecord, scope *commonv1.InstrumentationScope, resourceAttrs, scopeAttrs []*commonv1.KeyValue) string {
+	var attrs strings.Builder
+	enc := logfmt.NewEncoder(&attrs)
+	encode := func(key, value string) {
+		if value == "" {
+			return
+		}
+		// The keys are sanitized, so encoding cannot fail.
+		_ = enc.EncodeKeyval(strutil.SanitizeLabelName(key), value)
+	}
+	encodeAttributes := func(kvs []*commonv1.KeyValue) {
+		for _, attr := range kvs {
+			encode(attr.Key, anyValueToString(attr.Value))
+		}
+	}
+
+	encodeAttributes(resourceAttrs)
+	if scope != nil {
+		encode("scope_name", scope.Name)
+		encode("scope_version", scope.Version)
+	}
+	encodeAttributes(scopeAttrs)
+
+	severity := record.SeverityText
+	if severity == "" && record.SeverityNumber != logsv1.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED {
+		severity = strings.TrimPrefix(record.SeverityNumber.String(), "SEVERITY_NUMBER_")
+	}
+	encode("severity", severity)
+	if len(record.TraceId) > 0 {
+		encode("trace_id", hex.EncodeToString(record.TraceId))
+	}
+	if len(record.SpanId) > 0 {
+		encode("span_id", hex.EncodeToString(record.SpanId))
+	}
+	encodeAttributes(record.Attributes)
+
+	line := anyValueToString(record.Body)
+	switch {
+	case attrs.Len() == 0:
+		return line
+	case line == "":
+		return attrs.String()
+	default:
+		return line + " " + attrs.String()
+	}
+}
+
+// anyValueToString renders an attribute value or log body as a string. Arrays and maps are rendered as JSON.
+func anyValueToString(v *commonv1.AnyValue) string {
+	switch value := v.GetValue().(type) {
+	case *commonv1.AnyValue_StringValue:
+		return value.StringValue
+	case *commonv1.AnyValue_BoolValue:
+		return strconv.FormatBool(value.BoolValue)
+	case *commonv1.AnyValue_IntValue:
+		return strconv.FormatInt(value.IntValue, 10)
+	case *commonv1.AnyValue_DoubleValue:
+		return strconv.FormatFloat(value.DoubleValue, 'g', -1, 64)
+	case *commonv1.AnyValue_BytesValue:
+		return base64.StdEncoding.EncodeToString(value.BytesValue)
+	case *commonv1.AnyValue_ArrayValue, *commonv1.AnyValue_KvlistValue:
+		buf, err := json.Marshal(anyValueToInterface(v))
+		if err != nil {
+			return ""
+		}
+		return string(buf)
+	default:
+		return ""
+	}
+}
+
+func anyValueToInterface(v *commonv1.AnyValue) interface{} {
+	switch value := v.GetValue().(type) {
+	case *commonv1.AnyValue_StringValue:
+		return value.StringValue
+	case *commonv1.AnyValue_BoolValue:
+		return value.BoolValue
+	case *commonv1.AnyValue_IntValue:
+		return value.IntValue
+	case *commonv1.AnyValue_DoubleValue:
+		return value.DoubleValue
+	case *commonv1.AnyValue_BytesValue:
+		return value.BytesValue
+	case *commonv1.AnyValue_ArrayValue:
+		values := make([]interface{}, 0, len(value.ArrayValue.GetValues()))
+		for _, v := range value.ArrayValue.GetValues() {
+			values = append(values, anyValueToInterface(v))
+		}
+		return values
+	case *commonv1.AnyValue_KvlistValue:
+		values := make(map[string]interface{}, len(value.KvlistValue.GetValues()))
+		for _, kv := range value.KvlistValue.GetValues() {
+			values[kv.Key] = anyValueToInterface(kv.Value)
+		}
+		return values
+	default:
+		return nil
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package push
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	logsv1 "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/grafana/loki/pkg/logproto"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+type fakeLimits struct {
+	cfg       OTLPConfig
+	esCfg     ElasticsearchConfig
+	splunkCfg SplunkConfig
+}
+
+func (l fakeLimits) OTLPConfig(_ string) OTLPConfig {
+	return l.cfg
+}
+
+func (l fakeLimits) ElasticsearchConfig(_ string) ElasticsearchConfig {
+	return l.esCfg
+}
+
+func (l fakeLimits) SplunkConfig(_ string) SplunkConfig {
+	return l.splunkCfg
+}
+
+func stringValue(s string) *commonv1.AnyValue {
+	return &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: s}}
+}
+
+func keyValue(k string, v *commonv1.AnyValue) *commonv1.KeyValue {
+	return &commonv1.KeyValue{Key: k, Value: v}
+}
+
+func testLogs() *logsv1.LogsData {
+	return &logsv1.LogsData{
+		ResourceLogs: []*logsv1.ResourceLogs{
+			{
+				Resource: &resourcev1.Resource{Attributes: []*commonv1.KeyValue{
+					keyValue("service.name", stringValue("checkout")),
+					keyValue("k8s.pod.name", stringValue("checkout-0")),
+					keyValue("host.arch", stringValue("amd64")),
+				}},
+				ScopeLogs: []*logsv1.ScopeLogs{
+					{
+						Scope: &commonv1.InstrumentationScope{
+							Name:       "net/http",
+							Version:    "1.0",
+							Attributes: []*commonv1.KeyValue{keyValue("team", stringValue("payments"))},
+						},
+						LogRecords: []*logsv1.LogRecord{
+							{
+								TimeUnixNano:   uint64(time.Unix(10, 0).UnixNano()),
+								SeverityNumber: logsv1.SeverityNumber_SEVERITY_NUMBER_INFO,
+								Body:           stringValue("request served"),
+								TraceId:        []byte{0x01, 0x02},
+								Attributes: []*commonv1.KeyValue{
+									keyValue("http.status_code", &commonv1.AnyValue{Value: &commonv1.AnyValue_IntValue{IntValue: 200}}),
+									keyValue("tags", &commonv1.AnyValue{Value: &commonv1.AnyValue_ArrayValue{ArrayValue: &commonv1.ArrayValue{
+										Values: []*commonv1.AnyValue{stringValue("a"), stringValue("b")},
+									}}}),
+								},
+							},
+							{
+								ObservedTimeUnixNano: uint64(time.Unix(11, 0).UnixNano()),
+								SeverityText:         "error",
+								Body:                 stringValue("request failed"),
+							},
+						},
+					},
+					{
+						// Scopes without records don't create streams.
+						Scope: &commonv1.InstrumentationScope{Name: "empty"},
+					},
+				},
+			},
+			{
+				// Logs without any of the configured attributes.
+				ScopeLogs: []*logsv1.ScopeLogs{
+					{LogRecords: []*logsv1.LogRecord{{TimeUnixNano: uint64(time.Unix(12, 0).UnixNano()), Body: stringValue("hello")}}},
+				},
+			},
+		},
+	}
+}
+
+func TestOTLPToPushRequest(t *testing.T) {
+	cfg := OTLPConfig{
+		ResourceAttributesAsLabels: []string{"service.name", "k8s.pod.name"},
+		ScopeAttributesAsLabels:    []string{"team"},
+	}
+	req := otlpToPushRequest(testLogs(), cfg, time.Unix(100, 0))
+
+	require.Len(t, req.Streams, 2)
+	require.Equal(t, `{k8s_pod_name="checkout-0", service_name="checkout", team="payments"}`, req.Streams[0].Labels)
+	require.Equal(t, []logproto.Entry{
+		{
+			Timestamp: time.Unix(10, 0),
+			Line:      `request served host_arch=amd64 scope_name=net/http scope_version=1.0 severity=INFO trace_id=0102 http_status_code=200 tags="[\"a\",\"b\"]"`,
+		},
+		{
+			Timestamp: time.Unix(11, 0),
+			Line:      `request failed host_arch=amd64 scope_name=net/http scope_version=1.0 severity=error`,
+		},
+	}, req.Streams[0].Entries)
+
+	require.Equal(t, `{service_name="unknown_service"}`, req.Streams[1].Labels)
+	require.Equal(t, []logproto.Entry{{Timestamp: time.Unix(12, 0), Line: "hello"}}, req.Streams[1].Entries)
+}
+
+func TestParseOTLPRequest(t *testing.T) {
+	limits := fakeLimits{cfg: OTLPConfig{ResourceAttributesAsLabels: []string{"service.name"}}}
+
+	protoBody, err := proto.Marshal(testLogs())
+	require.NoError(t, err)
+	var gzipBody bytes.Buffer
+	gz := gzip.NewWriter(&gzipBody)
+	_, err = gz.Write(protoBody)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	jsonBody := `{"resourceLogs":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"checkout"}}]},` +
+		`"scopeLogs":[{"logRecords":[{"timeUnixNano":"10000000000","body":{"stringValue":"request served"}}]}]}]}`
+
+	for _, tc := range []struct {
+		name            string
+		body            []byte
+		contentType     string
+		contentEncoding string
+		expectedStreams int
+		valid           bool
+	}{
+		{"protobuf", protoBody, "application/x-protobuf", "", 2, true},
+		{"gzipped protobuf", gzipBody.Bytes(), "application/x-protobuf", "gzip", 2, true},
+		{"json", []byte(jsonBody), "application/json", "", 1, true},
+		{"invalid json", []byte(`{"resourceLogs":`), "application/json", "", 0, false},
+		{"unsupported content type", protoBody, "text/plain", "", 0, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/otlp/v1/logs", bytes.NewReader(tc.body))
+			r.Header.Set("Content-Type", tc.contentType)
+			if tc.contentEncoding != "" {
+				r.Header.Set("Content-Encoding", tc.contentEncoding)
+			}
+
+			req, err := ParseRequest(util_log.Logger, "fake", r, nil, limits, NewOTLPRequestParser(1<<20))
+			if !tc.valid {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, req.Streams, tc.expectedStreams)
+			require.Equal(t, `{service_name="checkout"}`, req.Streams[0].Labels)
+			require.Equal(t, time.Unix(10, 0), req.Streams[0].Entries[0].Timestamp)
+			require.Equal(t, "request served", req.Streams[0].Entries[0].Line[:len("request served")])
+		})
+	}
+}
+
+func TestParseOTLPRequest_TooLarge(t *testing.T) {
+	body, err := proto.Marshal(testLogs())
+	require.NoError(t, err)
+
+	r := httptest.NewRequest("POST", "/otlp/v1/logs", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-protobuf")
+	_, err = ParseRequest(util_log.Logger, "fake", r, nil, fakeLimits{}, NewOTLPRequestParser(len(body)-1))
+	require.ErrorContains(t, err, "too large")
+
+	r = httptest.NewRequest("POST", "/otlp/v1/logs", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-protobuf")
+	_, err = ParseRequest(util_log.Logger, "fake", r, nil, fakeLimits{}, NewOTLPRequestParser(len(body)))
+	require.NoError(t, err)
+}
+
+func TestWriteOTLPResponse(t *testing.T) {
+	for _, tc := range []struct {
+		contentType string
+		body        string
+	}{
+		{"application/x-protobuf", ""},
+		{"application/json", "{}"},
+		{"application/json; charset=utf-8", "{}"},
+	} {
+		t.Run(tc.contentType, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/otlp/v1/logs", nil)
+			r.Header.Set("Content-Type", tc.contentType)
+			w := httptest.NewRecorder()
+			WriteOTLPResponse(w, r)
+			require.Equal(t, http.StatusOK, w.Code)
+			require.Equal(t, tc.body, w.Body.String())
+		})
+	}
+}
@@ -51,7 +51,17 @@ type TenantsRetention interface {
 	RetentionPeriodFor(userID string, lbs labels.Labels) time.Duration
 }
 
-func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRetention TenantsRetention) (*logproto.PushRequest, error) {
+// Limits are the per-tenant settings that are used to parse push requests.
+type Limits interface {
+	OTLPConfig(userID string) OTLPConfig
+	ElasticsearchConfig(userID string) ElasticsearchConfig
+	SplunkConfig(userID string) SplunkConfig
+}
+
+// RequestParser decodes the (decompressed) body of a push request of the given content type.
+type RequestParser func(userID string, r *http.Request, body io.Reader, contentType string, limits Limits) (*logproto.PushRequest, error)
+
+func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRetention TenantsRetention, limits Limits, parser RequestParser) (*logproto.PushRequest, error) {
 	// Body
 	var body io.Reader
 	// bodySize should always reflect the compressed size of the request body
@@ -80,42 +90,25 @@ func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRete
 		return nil, fmt.Errorf("Content-Encoding %q not supported", contentEncoding)
 	}
 
-	contentType := r.Header.Get(contentType)
 	var (
 		entriesSize      int64
 		streamLabelsSize int64
 		totalEntries     int64
-		req              logproto.PushRequest
 	)
 
-	contentType, _ /* params */, err := mime.ParseMediaType(contentType)
-	if err != nil {
-		return nil, err
-	}
-
-	switch contentType {
-	case applicationJSON:
-
+	// Clients of the Splunk HEC API don't necessarily set a content type.
+	contentType := r.Header.Get(contentType)
+	if contentType != "" {
 		var err error
-
-		// todo once https://github.com/weaveworks/common/commit/73225442af7da93ec8f6a6e2f7c8aafaee3f8840 is in Loki.
-		// We can try to pass the body as bytes.buffer instead to avoid reading into another buffer.
-		if loghttp.GetVersion(r.RequestURI) == loghttp.VersionV1 {
-			err = unmarshal.DecodePushRequest(body, &req)
-		} else {
-			err = unmarshal2.DecodePushRequest(body, &req)
-		}
-
+		contentType, _ /* params */, err = mime.ParseMediaType(contentType)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-	default:
-		// When no content-type header is set or when it is set to
-		// `application/x-protobuf`: expect snappy compression.
-		if err := util.ParseProtoReader(r.Context(), body, int(r.ContentLength), math.MaxInt32, &req, util.RawSnappy); err != nil {
-			return nil, err
-		}
+	req, err := parser(userID, r, body, contentType, limits)
+	if err != nil {
+		return nil, err
 	}
 
 	mostRecentEntry := time.Unix(0, 0)
@@ -160,5 +153,37 @@ func ParseRequest(logger log.Logger, userID string, r *http.Request, tenantsRete
 		"totalSize", humanize.Bytes(uint64(entriesSize+streamLabelsSize)),
 		"mostRecentLagMs", time.Since(mostRecentEntry).Milliseconds(),
 	)
+	return req, nil
+}
+
+// ParseLokiRequest decodes a push request in the Loki push format, which is either JSON or snappy-compressed protobuf.
+func ParseLokiRequest(_ string, r *http.Request, body io.Reader, contentType string, _ Limits) (*logproto.PushRequest, error) {
+	var req logproto.PushRequest
+
+	switch contentType {
+	case applicationJSON:
+
+		var err error
+
+		// todo once https://github.com/weaveworks/common/commit/73225442af7da93ec8f6a6e2f7c8aafaee3f8840 is in Loki.
+		// We can try to pass the body as bytes.buffer instead to avoid reading into another buffer.
+		if loghttp.GetVersion(r.RequestURI) == loghttp.VersionV1 {
+			err = unmarshal.DecodePushRequest(body, &req)
+		} else {
+			err = unmarshal2.DecodePushRequest(body, &req)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		// When no content-type header is set or when it is set to
+		// `application/x-protobuf`: expect snappy compression.
+		if err := util.ParseProtoReader(r.Context(), body, int(r.ContentLength), math.MaxInt32, &req, util.RawSnappy); err != nil {
+			return nil, err
+		}
+	}
+
 	return &req, nil
 }
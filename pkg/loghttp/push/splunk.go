@@ -0,0 +1,258 @@
+package push
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logfmt/logfmt"
+	"github.com/grafana/dskit/flagext"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+// The HEC metadata of an event.
+const (
+	splunkIndex      = "index"
+	splunkSourcetype = "sourcetype"
+	splunkHost       = "host"
+	splunkSource     = "source"
+)
+
+// SplunkConfig configures how events that are pushed via the Splunk HTTP Event Collector (HEC) API are mapped to Loki streams.
+type SplunkConfig struct {
+	MetadataAsLabels flagext.StringSliceCSV `yaml:"metadata_as_labels" json:"metadata_as_labels"`
+	FieldsAsLabels   flagext.StringSliceCSV `yaml:"fields_as_labels" json:"fields_as_labels"`
+}
+
+// RegisterFlagsWithPrefix adds the flags required to config this to the given FlagSet.
+func (cfg *SplunkConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	cfg.MetadataAsLabels = []string{splunkIndex, splunkSourcetype, splunkHost}
+	f.Var(&cfg.MetadataAsLabels, prefix+"metadata-as-labels", "Comma-separated list of HEC event metadata that are turned into stream labels. Supported values are index, sourcetype, host and source.")
+	f.Var(&cfg.FieldsAsLabels, prefix+"fields-as-labels", "Comma-separated list of HEC indexed fields that are turned into stream labels. Invalid characters in field names are replaced by underscores.")
+}
+
+// splunkEvent is an event of the HEC event endpoint.
+type splunkEvent struct {
+	Time       json.Number            `json:"time"`
+	Host       string                 `json:"host"`
+	Source     string                 `json:"source"`
+	Sourcetype string                 `json:"sourcetype"`
+	Index      string                 `json:"index"`
+	Event      json.RawMessage        `json:"event"`
+	Fields     map[string]interface{} `json:"fields"`
+}
+
+// ParseSplunkEventRequest decodes a request to the HEC event endpoint into a Loki push request.
+// The body is a sequence of JSON event objects.
+//
+// The configured metadata and indexed fields of an event become stream labels.
+// The line of an entry is the event, followed by its remaining metadata and fields in logfmt format.
+// Metadata that is missing from an event is taken from the query parameters of the request.
+func ParseSplunkEventRequest(userID string, r *http.Request, body io.Reader, _ string, limits Limits) (*logproto.PushRequest, error) {
+	var cfg SplunkConfig
+	if limits != nil {
+		cfg = limits.SplunkConfig(userID)
+	}
+
+	var (
+		req      = &logproto.PushRequest{}
+		streams  = map[string]int{}
+		defaults = splunkEventDefaults(r)
+		now      = time.Now()
+		dec      = json.NewDecoder(body)
+	)
+	dec.UseNumber()
+
+	for {
+		event := defaults
+		event.Fields = nil
+		if err := dec.Decode(&event); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid data format: %w", err)
+		}
+		if len(event.Event) == 0 || bytes.Equal(event.Event, []byte("null")) {
+			return nil, errors.New("event field is required")
+		}
+		if bytes.Equal(event.Event, []byte(`""`)) {
+			return nil, errors.New("event field cannot be blank")
+		}
+
+		ts, err := parseSplunkTime(event.Time, now)
+		if err != nil {
+			return nil, err
+		}
+		line := string(event.Event)
+		var s string
+		if json.Unmarshal(event.Event, &s) == nil {
+			line = s
+		}
+		appendSplunkEntry(req, streams, cfg, event, logproto.Entry{Timestamp: ts, Line: line})
+	}
+
+	return req, nil
+}
+
+// ParseSplunkRawRequest decodes a request to the HEC raw endpoint into a Loki push request.
+// Each line of the body is an event, with the metadata taken from the query parameters of the request.
+func ParseSplunkRawRequest(userID string, r *http.Request, body io.Reader, _ string, limits Limits) (*logproto.PushRequest, error) {
+	var cfg SplunkConfig
+	if limits != nil {
+		cfg = limits.SplunkConfig(userID)
+	}
+
+	var (
+		req     = &logproto.PushRequest{}
+		streams = map[string]int{}
+		event   = splunkEventDefaults(r)
+		now     = time.Now()
+		reader  = bufio.NewReader(body)
+	)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if line = strings.TrimRight(line, "\r\n"); line != "" {
+			appendSplunkEntry(req, streams, cfg, event, logproto.Entry{Timestamp: now, Line: line})
+		}
+		if err == io.EOF {
+			return req, nil
+		}
+	}
+}
+
+// WriteSplunkSuccess writes the response of HEC endpoints for successful requests.
+func WriteSplunkSuccess(w http.ResponseWriter) {
+	WriteSplunkResponse(w, http.StatusOK, "Success", 0)
+}
+
+// WriteSplunkHealth writes the response of the HEC health endpoint.
+func WriteSplunkHealth(w http.ResponseWriter, _ *http.Request) {
+	WriteSplunkResponse(w, http.StatusOK, "HEC is healthy", 17)
+}
+
+// WriteSplunkResponse writes a HEC response with the given status, text and HEC status code.
+func WriteSplunkResponse(w http.ResponseWriter, status int, text string, code int) {
+	w.Header().Set("Content-Type", applicationJSON)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Text string `json:"text"`
+		Code int    `json:"code"`
+	}{text, code})
+}
+
+func splunkEventDefaults(r *http.Request) splunkEvent {
+	query := r.URL.Query()
+	return splunkEvent{
+		Host:       query.Get(splunkHost),
+		Source:     query.Get(splunkSource),
+		Sourcetype: query.Get(splunkSourcetype),
+		Index:      query.Get(splunkIndex),
+	}
+}
+
+func appendSplunkEntry(req *logproto.PushRequest, streams map[string]int, cfg SplunkConfig, event splunkEvent, entry logproto.Entry) {
+	lbs := model.LabelSet{}
+	var attrs strings.Builder
+	enc := logfmt.NewEncoder(&attrs)
+	encode := func(key, value string) {
+		if value == "" {
+			return
+		}
+		// The keys are sanitized, so encoding cannot fail.
+		_ = enc.EncodeKeyval(strutil.SanitizeLabelName(key), value)
+	}
+
+	for _, metadata := range []struct{ name, value string }{
+		{splunkIndex, event.Index},
+		{splunkSourcetype, event.Sourcetype},
+		{splunkHost, event.Host},
+		{splunkSource, event.Source},
+	} {
+		if contains(cfg.MetadataAsLabels, metadata.name) {
+			if metadata.value != "" {
+				lbs[model.LabelName(metadata.name)] = model.LabelValue(metadata.value)
+			}
+			continue
+		}
+		encode(metadata.name, metadata.value)
+	}
+
+	for _, name := range sortedKeys(event.Fields) {
+		value := fieldToString(event.Fields[name])
+		if contains(cfg.FieldsAsLabels, name) {
+			if value != "" {
+				lbs[model.LabelName(strutil.SanitizeLabelName(name))] = model.LabelValue(value)
+			}
+			continue
+		}
+		encode(name, value)
+	}
+
+	if len(lbs) == 0 {
+		lbs["service_name"] = unknownServiceName
+	}
+	if attrs.Len() > 0 {
+		entry.Line += " " + attrs.String()
+	}
+
+	labels := lbs.String()
+	idx, ok := streams[labels]
+	if !ok {
+		idx = len(req.Streams)
+		streams[labels] = idx
+		req.Streams = append(req.Streams, logproto.Stream{
+			Labels: labels,
+			Hash:   uint64(lbs.Fingerprint()),
+		})
+	}
+	req.Streams[idx].Entries = append(req.Streams[idx].Entries, entry)
+}
+
+// parseSplunkTime parses the time of an event, which is in seconds since epoch with an optional fraction.
+func parseSplunkTime(t json.Number, now time.Time) (time.Time, error) {
+	s := string(t)
+	if s == "" {
+		return now, nil
+	}
+
+	secs, frac, _ := strings.Cut(s, ".")
+	sec, err := strconv.ParseInt(secs, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", s)
+	}
+	var nsec int64
+	if frac != "" {
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		nsec, err = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q", s)
+		}
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,66 @@
+package push
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	util_log "github.com/grafana/loki/pkg/util/log"
+)
+
+var testSplunkConfig = SplunkConfig{
+	MetadataAsLabels: []string{"index", "sourcetype", "host"},
+	FieldsAsLabels:   []string{"cluster"},
+}
+
+func TestParseSplunkEventRequest(t *testing.T) {
+	body := `{"time":1426279439.123456789,"host":"web-1","source":"/var/log/app.log","sourcetype":"access","event":"GET /index.html 200"}` +
+		`{"time":"1426279440","index":"main","event":{"message":"hello","level":"info"},"fields":{"cluster":"eu-1","pod":"app-0"}}` + "\n" +
+		`{"event":"defaults"}`
+	limits := fakeLimits{splunkCfg: testSplunkConfig}
+
+	r := httptest.NewRequest("POST", "/services/collector/event?sourcetype=json&host=web-2", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	req, err := ParseRequest(util_log.Logger, "fake", r, nil, limits, ParseSplunkEventRequest)
+	require.NoError(t, err)
+
+	require.Len(t, req.Streams, 3)
+	require.Equal(t, `{host="web-1", sourcetype="access"}`, req.Streams[0].Labels)
+	require.Equal(t, []logproto.Entry{
+		{Timestamp: time.Unix(1426279439, 123456789), Line: `GET /index.html 200 source=/var/log/app.log`},
+	}, req.Streams[0].Entries)
+	require.Equal(t, `{cluster="eu-1", host="web-2", index="main", sourcetype="json"}`, req.Streams[1].Labels)
+	require.Equal(t, []logproto.Entry{
+		{Timestamp: time.Unix(1426279440, 0), Line: `{"message":"hello","level":"info"} pod=app-0`},
+	}, req.Streams[1].Entries)
+	require.Equal(t, `{host="web-2", sourcetype="json"}`, req.Streams[2].Labels)
+	require.Equal(t, "defaults", req.Streams[2].Entries[0].Line)
+
+	for _, invalid := range []string{
+		`{"event":"hello"`,
+		`{"time":1426279439}`,
+		`{"event":""}`,
+		`{"time":"yesterday","event":"hello"}`,
+	} {
+		r := httptest.NewRequest("POST", "/services/collector/event", strings.NewReader(invalid))
+		_, err := ParseRequest(util_log.Logger, "fake", r, nil, limits, ParseSplunkEventRequest)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestParseSplunkRawRequest(t *testing.T) {
+	limits := fakeLimits{splunkCfg: testSplunkConfig}
+	r := httptest.NewRequest("POST", "/services/collector/raw?host=web-1&source=app", strings.NewReader("first line\r\n\nsecond line"))
+
+	req, err := ParseRequest(util_log.Logger, "fake", r, nil, limits, ParseSplunkRawRequest)
+	require.NoError(t, err)
+	require.Len(t, req.Streams, 1)
+	require.Equal(t, `{host="web-1"}`, req.Streams[0].Labels)
+	require.Len(t, req.Streams[0].Entries, 2)
+	require.Equal(t, "first line source=app", req.Streams[0].Entries[0].Line)
+	require.Equal(t, "second line source=app", req.Streams[0].Entries[1].Line)
+}
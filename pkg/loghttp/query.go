@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
 )
 
 var (
@@ -35,6 +36,8 @@ const (
 type QueryResponse struct {
 	Status string            `json:"status"`
 	Data   QueryResponseData `json:"data"`
+	// Warnings describe the time ranges and shards that are missing from a partial result.
+	Warnings []definitions.QueryWarning `json:"warnings,omitempty"`
 }
 
 func (q *QueryResponse) UnmarshalJSON(data []byte) error {
@@ -48,6 +51,12 @@ func (q *QueryResponse) UnmarshalJSON(data []byte) error {
 				return err
 			}
 			q.Data = responseData
+		case "warnings":
+			var warnings []definitions.QueryWarning
+			if err := json.Unmarshal(value, &warnings); err != nil {
+				return err
+			}
+			q.Warnings = warnings
 		}
 		return nil
 	})
@@ -12,6 +12,7 @@ import (
 
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/pkg/push"
 )
 
 func TestParseRangeQuery(t *testing.T) {
@@ -221,6 +222,22 @@ func Test_QueryResponseUnmarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			Status: "ok",
+			Data: QueryResponseData{
+				ResultType: "streams",
+				Result: Streams{
+					Stream{
+						Labels: LabelSet{"foo": "bar"},
+						Entries: []Entry{
+							{Timestamp: time.Unix(0, 1), Line: "1", StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "abc"}, {Name: "user_id", Value: "1"}}},
+							{Timestamp: time.Unix(0, 2), Line: "2"},
+						},
+					},
+				},
+				Statistics: stats.Result{},
+			},
+		},
 		{
 			Status: "ok",
 			Data: QueryResponseData{
@@ -268,3 +285,16 @@ func Test_QueryResponseUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func Test_EntriesStructuredMetadataUnmarshal(t *testing.T) {
+	var actual []Entry
+	err := jsoniter.Unmarshal([]byte(`[["1","1",{"trace_id":"abc"}],["2","2"]]`), &actual)
+	require.NoError(t, err)
+	require.Equal(t, []Entry{
+		{Timestamp: time.Unix(0, 1), Line: "1", StructuredMetadata: push.LabelsAdapter{{Name: "trace_id", Value: "abc"}}},
+		{Timestamp: time.Unix(0, 2), Line: "2"},
+	}, actual)
+
+	err = jsoniter.Unmarshal([]byte(`[["1","1",{},"extra"]]`), &actual)
+	require.Error(t, err)
+}
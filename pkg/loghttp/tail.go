@@ -19,6 +19,8 @@ const (
 type TailResponse struct {
 	Streams        []Stream        `json:"streams,omitempty"`
 	DroppedStreams []DroppedStream `json:"dropped_entries,omitempty"`
+	// Cursor can be passed to a new tail request to resume the tail after the entries of this response.
+	Cursor string `json:"cursor,omitempty"`
 }
 
 // DroppedStream represents a dropped stream in tail call
@@ -66,7 +68,8 @@ func (s *DroppedStream) UnmarshalJSON(data []byte) error {
 func ParseTailQuery(r *http.Request) (*logproto.TailRequest, error) {
 	var err error
 	req := logproto.TailRequest{
-		Query: query(r),
+		Query:  query(r),
+		Cursor: r.Form.Get("cursor"),
 	}
 
 	req.Limit, err = limit(r)
@@ -39,6 +39,15 @@ func TestParseTailQuery(t *testing.T) {
 				Start:    time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC),
 				Limit:    1000,
 			}, false},
+		{"cursor",
+			&http.Request{
+				URL: mustParseURL(`?query={foo="bar"}&start=2017-06-10T21:42:24.760738998Z&limit=1000&cursor=abc`),
+			}, &logproto.TailRequest{
+				Query:  `{foo="bar"}`,
+				Start:  time.Date(2017, 06, 10, 21, 42, 24, 760738998, time.UTC),
+				Limit:  1000,
+				Cursor: "abc",
+			}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -17,6 +17,7 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/timestamp"
 
+	"github.com/grafana/loki/pkg/push"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
 	"github.com/grafana/loki/pkg/util"
 )
@@ -316,3 +317,15 @@ func (m *IndexStatsRequest) LogToSpan(sp opentracing.Span) {
 		otlog.String("end", timestamp.Time(m.GetEnd()).String()),
 	)
 }
+
+// FromStructuredMetadataToLabels casts the structured metadata of an entry to labels.Labels.
+// It uses unsafe, but as push.LabelAdapter == labels.Label this should be safe.
+func FromStructuredMetadataToLabels(m push.LabelsAdapter) labels.Labels {
+	return *(*labels.Labels)(unsafe.Pointer(&m))
+}
+
+// FromLabelsToStructuredMetadata casts labels.Labels to the structured metadata of an entry.
+// It uses unsafe, but as push.LabelAdapter == labels.Label this should be safe.
+func FromLabelsToStructuredMetadata(ls labels.Labels) push.LabelsAdapter {
+	return *(*push.LabelsAdapter)(unsafe.Pointer(&ls))
+}
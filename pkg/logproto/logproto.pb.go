@@ -14,8 +14,8 @@ import (
 	_ "github.com/gogo/protobuf/types"
 	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
 	stats "github.com/grafana/loki/pkg/logqlmodel/stats"
-	_ "github.com/grafana/loki/pkg/push"
 	github_com_grafana_loki_pkg_push "github.com/grafana/loki/pkg/push"
+	push "github.com/grafana/loki/pkg/push"
 	github_com_prometheus_common_model "github.com/prometheus/common/model"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
@@ -145,6 +145,7 @@ type StreamRate struct {
 	StreamHashNoShard uint64 `protobuf:"varint,2,opt,name=streamHashNoShard,proto3" json:"streamHashNoShard,omitempty"`
 	Rate              int64  `protobuf:"varint,3,opt,name=rate,proto3" json:"rate,omitempty"`
 	Tenant            string `protobuf:"bytes,4,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	EntriesRate       int64  `protobuf:"varint,5,opt,name=entriesRate,proto3" json:"entriesRate,omitempty"`
 }
 
 func (m *StreamRate) Reset()      { *m = StreamRate{} }
@@ -207,6 +208,278 @@ func (m *StreamRate) GetTenant() string {
 	return ""
 }
 
+func (m *StreamRate) GetEntriesRate() int64 {
+	if m != nil {
+		return m.EntriesRate
+	}
+	return 0
+}
+
+type TenantVolumesRequest struct {
+}
+
+func (m *TenantVolumesRequest) Reset()      { *m = TenantVolumesRequest{} }
+func (*TenantVolumesRequest) ProtoMessage() {}
+func (*TenantVolumesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{3}
+}
+func (m *TenantVolumesRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantVolumesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TenantVolumesRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TenantVolumesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantVolumesRequest.Merge(m, src)
+}
+func (m *TenantVolumesRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantVolumesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantVolumesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantVolumesRequest proto.InternalMessageInfo
+
+type TenantVolumesResponse struct {
+	TenantVolumes []*TenantVolume `protobuf:"bytes,1,rep,name=tenantVolumes,proto3" json:"tenantVolumes,omitempty"`
+}
+
+func (m *TenantVolumesResponse) Reset()      { *m = TenantVolumesResponse{} }
+func (*TenantVolumesResponse) ProtoMessage() {}
+func (*TenantVolumesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{4}
+}
+func (m *TenantVolumesResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantVolumesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TenantVolumesResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TenantVolumesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantVolumesResponse.Merge(m, src)
+}
+func (m *TenantVolumesResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantVolumesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantVolumesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantVolumesResponse proto.InternalMessageInfo
+
+func (m *TenantVolumesResponse) GetTenantVolumes() []*TenantVolume {
+	if m != nil {
+		return m.TenantVolumes
+	}
+	return nil
+}
+
+// TenantVolume is the number of bytes of log lines that an ingester received for a tenant in the current UTC day and month.
+type TenantVolume struct {
+	Tenant     string `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	Day        int64  `protobuf:"varint,2,opt,name=day,proto3" json:"day,omitempty"`
+	DayBytes   int64  `protobuf:"varint,3,opt,name=dayBytes,proto3" json:"dayBytes,omitempty"`
+	Month      int64  `protobuf:"varint,4,opt,name=month,proto3" json:"month,omitempty"`
+	MonthBytes int64  `protobuf:"varint,5,opt,name=monthBytes,proto3" json:"monthBytes,omitempty"`
+}
+
+func (m *TenantVolume) Reset()      { *m = TenantVolume{} }
+func (*TenantVolume) ProtoMessage() {}
+func (*TenantVolume) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{5}
+}
+func (m *TenantVolume) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TenantVolume) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TenantVolume.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TenantVolume) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TenantVolume.Merge(m, src)
+}
+func (m *TenantVolume) XXX_Size() int {
+	return m.Size()
+}
+func (m *TenantVolume) XXX_DiscardUnknown() {
+	xxx_messageInfo_TenantVolume.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TenantVolume proto.InternalMessageInfo
+
+func (m *TenantVolume) GetTenant() string {
+	if m != nil {
+		return m.Tenant
+	}
+	return ""
+}
+
+func (m *TenantVolume) GetDay() int64 {
+	if m != nil {
+		return m.Day
+	}
+	return 0
+}
+
+func (m *TenantVolume) GetDayBytes() int64 {
+	if m != nil {
+		return m.DayBytes
+	}
+	return 0
+}
+
+func (m *TenantVolume) GetMonth() int64 {
+	if m != nil {
+		return m.Month
+	}
+	return 0
+}
+
+func (m *TenantVolume) GetMonthBytes() int64 {
+	if m != nil {
+		return m.MonthBytes
+	}
+	return 0
+}
+
+type PushStreamRequest struct {
+	BatchID uint64            `protobuf:"varint,1,opt,name=batchID,proto3" json:"batchID,omitempty"`
+	Request *push.PushRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+}
+
+func (m *PushStreamRequest) Reset()      { *m = PushStreamRequest{} }
+func (*PushStreamRequest) ProtoMessage() {}
+func (*PushStreamRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{6}
+}
+func (m *PushStreamRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PushStreamRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PushStreamRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PushStreamRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushStreamRequest.Merge(m, src)
+}
+func (m *PushStreamRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *PushStreamRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushStreamRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushStreamRequest proto.InternalMessageInfo
+
+func (m *PushStreamRequest) GetBatchID() uint64 {
+	if m != nil {
+		return m.BatchID
+	}
+	return 0
+}
+
+func (m *PushStreamRequest) GetRequest() *push.PushRequest {
+	if m != nil {
+		return m.Request
+	}
+	return nil
+}
+
+type PushStreamResponse struct {
+	BatchID uint64 `protobuf:"varint,1,opt,name=batchID,proto3" json:"batchID,omitempty"`
+	// code is the HTTP status code of the push of the batch, 200 if it succeeded.
+	Code  int32  `protobuf:"varint,2,opt,name=code,proto3" json:"code,omitempty"`
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *PushStreamResponse) Reset()      { *m = PushStreamResponse{} }
+func (*PushStreamResponse) ProtoMessage() {}
+func (*PushStreamResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{7}
+}
+func (m *PushStreamResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *PushStreamResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_PushStreamResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *PushStreamResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushStreamResponse.Merge(m, src)
+}
+func (m *PushStreamResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *PushStreamResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushStreamResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushStreamResponse proto.InternalMessageInfo
+
+func (m *PushStreamResponse) GetBatchID() uint64 {
+	if m != nil {
+		return m.BatchID
+	}
+	return 0
+}
+
+func (m *PushStreamResponse) GetCode() int32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *PushStreamResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 type QueryRequest struct {
 	Selector  string    `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
 	Limit     uint32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
@@ -220,7 +493,7 @@ type QueryRequest struct {
 func (m *QueryRequest) Reset()      { *m = QueryRequest{} }
 func (*QueryRequest) ProtoMessage() {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{3}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{8}
 }
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -309,7 +582,7 @@ type SampleQueryRequest struct {
 func (m *SampleQueryRequest) Reset()      { *m = SampleQueryRequest{} }
 func (*SampleQueryRequest) ProtoMessage() {}
 func (*SampleQueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{4}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{9}
 }
 func (m *SampleQueryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -382,7 +655,7 @@ type Delete struct {
 func (m *Delete) Reset()      { *m = Delete{} }
 func (*Delete) ProtoMessage() {}
 func (*Delete) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{5}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{10}
 }
 func (m *Delete) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -440,7 +713,7 @@ type QueryResponse struct {
 func (m *QueryResponse) Reset()      { *m = QueryResponse{} }
 func (*QueryResponse) ProtoMessage() {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{6}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{11}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -484,7 +757,7 @@ type SampleQueryResponse struct {
 func (m *SampleQueryResponse) Reset()      { *m = SampleQueryResponse{} }
 func (*SampleQueryResponse) ProtoMessage() {}
 func (*SampleQueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{7}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{12}
 }
 func (m *SampleQueryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -531,7 +804,7 @@ type LabelRequest struct {
 func (m *LabelRequest) Reset()      { *m = LabelRequest{} }
 func (*LabelRequest) ProtoMessage() {}
 func (*LabelRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{8}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{13}
 }
 func (m *LabelRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -602,7 +875,7 @@ type LabelResponse struct {
 func (m *LabelResponse) Reset()      { *m = LabelResponse{} }
 func (*LabelResponse) ProtoMessage() {}
 func (*LabelResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{9}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{14}
 }
 func (m *LabelResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -647,7 +920,7 @@ type Sample struct {
 func (m *Sample) Reset()      { *m = Sample{} }
 func (*Sample) ProtoMessage() {}
 func (*Sample) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{10}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{15}
 }
 func (m *Sample) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -706,7 +979,7 @@ type LegacySample struct {
 func (m *LegacySample) Reset()      { *m = LegacySample{} }
 func (*LegacySample) ProtoMessage() {}
 func (*LegacySample) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{11}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{16}
 }
 func (m *LegacySample) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -758,7 +1031,7 @@ type Series struct {
 func (m *Series) Reset()      { *m = Series{} }
 func (*Series) ProtoMessage() {}
 func (*Series) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{12}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{17}
 }
 func (m *Series) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -813,12 +1086,14 @@ type TailRequest struct {
 	DelayFor uint32    `protobuf:"varint,3,opt,name=delayFor,proto3" json:"delayFor,omitempty"`
 	Limit    uint32    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
 	Start    time.Time `protobuf:"bytes,5,opt,name=start,proto3,stdtime" json:"start"`
+	// cursor of a previous tail to resume from, as returned in its responses.
+	Cursor string `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
 }
 
 func (m *TailRequest) Reset()      { *m = TailRequest{} }
 func (*TailRequest) ProtoMessage() {}
 func (*TailRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{13}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{18}
 }
 func (m *TailRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -875,6 +1150,13 @@ func (m *TailRequest) GetStart() time.Time {
 	return time.Time{}
 }
 
+func (m *TailRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
 type TailResponse struct {
 	Stream         *github_com_grafana_loki_pkg_push.Stream `protobuf:"bytes,1,opt,name=stream,proto3,customtype=github.com/grafana/loki/pkg/push.Stream" json:"stream,omitempty"`
 	DroppedStreams []*DroppedStream                         `protobuf:"bytes,2,rep,name=droppedStreams,proto3" json:"droppedStreams,omitempty"`
@@ -883,7 +1165,7 @@ type TailResponse struct {
 func (m *TailResponse) Reset()      { *m = TailResponse{} }
 func (*TailResponse) ProtoMessage() {}
 func (*TailResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{14}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{19}
 }
 func (m *TailResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -929,7 +1211,7 @@ type SeriesRequest struct {
 func (m *SeriesRequest) Reset()      { *m = SeriesRequest{} }
 func (*SeriesRequest) ProtoMessage() {}
 func (*SeriesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{15}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{20}
 }
 func (m *SeriesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -993,7 +1275,7 @@ type SeriesResponse struct {
 func (m *SeriesResponse) Reset()      { *m = SeriesResponse{} }
 func (*SeriesResponse) ProtoMessage() {}
 func (*SeriesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{16}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{21}
 }
 func (m *SeriesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1036,7 +1318,7 @@ type SeriesIdentifier struct {
 func (m *SeriesIdentifier) Reset()      { *m = SeriesIdentifier{} }
 func (*SeriesIdentifier) ProtoMessage() {}
 func (*SeriesIdentifier) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{17}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{22}
 }
 func (m *SeriesIdentifier) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1081,7 +1363,7 @@ type DroppedStream struct {
 func (m *DroppedStream) Reset()      { *m = DroppedStream{} }
 func (*DroppedStream) ProtoMessage() {}
 func (*DroppedStream) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{18}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{23}
 }
 func (m *DroppedStream) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1141,7 +1423,7 @@ type TimeSeriesChunk struct {
 func (m *TimeSeriesChunk) Reset()      { *m = TimeSeriesChunk{} }
 func (*TimeSeriesChunk) ProtoMessage() {}
 func (*TimeSeriesChunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{19}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{24}
 }
 func (m *TimeSeriesChunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1198,22 +1480,26 @@ func (m *TimeSeriesChunk) GetChunks() []*Chunk {
 	return nil
 }
 
-type LabelPair struct {
-	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+type HandoffStreamsRequest struct {
+	FromIngesterId string `protobuf:"bytes,1,opt,name=from_ingester_id,json=fromIngesterId,proto3" json:"from_ingester_id,omitempty"`
+	UserId         string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// The labels of the stream, and the entries of its head chunk.
+	Stream *github_com_grafana_loki_pkg_push.Stream `protobuf:"bytes,3,opt,name=stream,proto3,customtype=github.com/grafana/loki/pkg/push.Stream" json:"stream,omitempty"`
+	// Chunks of the stream that were cut by the leaving ingester.
+	Chunks []*Chunk `protobuf:"bytes,4,rep,name=chunks,proto3" json:"chunks,omitempty"`
 }
 
-func (m *LabelPair) Reset()      { *m = LabelPair{} }
-func (*LabelPair) ProtoMessage() {}
-func (*LabelPair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{20}
+func (m *HandoffStreamsRequest) Reset()      { *m = HandoffStreamsRequest{} }
+func (*HandoffStreamsRequest) ProtoMessage() {}
+func (*HandoffStreamsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{25}
 }
-func (m *LabelPair) XXX_Unmarshal(b []byte) error {
+func (m *HandoffStreamsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *LabelPair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *HandoffStreamsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_LabelPair.Marshal(b, m, deterministic)
+		return xxx_messageInfo_HandoffStreamsRequest.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1223,49 +1509,53 @@ func (m *LabelPair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 		return b[:n], nil
 	}
 }
-func (m *LabelPair) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LabelPair.Merge(m, src)
+func (m *HandoffStreamsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HandoffStreamsRequest.Merge(m, src)
 }
-func (m *LabelPair) XXX_Size() int {
+func (m *HandoffStreamsRequest) XXX_Size() int {
 	return m.Size()
 }
-func (m *LabelPair) XXX_DiscardUnknown() {
-	xxx_messageInfo_LabelPair.DiscardUnknown(m)
+func (m *HandoffStreamsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HandoffStreamsRequest.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_LabelPair proto.InternalMessageInfo
+var xxx_messageInfo_HandoffStreamsRequest proto.InternalMessageInfo
 
-func (m *LabelPair) GetName() string {
+func (m *HandoffStreamsRequest) GetFromIngesterId() string {
 	if m != nil {
-		return m.Name
+		return m.FromIngesterId
 	}
 	return ""
 }
 
-func (m *LabelPair) GetValue() string {
+func (m *HandoffStreamsRequest) GetUserId() string {
 	if m != nil {
-		return m.Value
+		return m.UserId
 	}
 	return ""
 }
 
-// LegacyLabelPair exists for backwards compatibility reasons and is deprecated. Do not use.
-type LegacyLabelPair struct {
-	Name  []byte `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+func (m *HandoffStreamsRequest) GetChunks() []*Chunk {
+	if m != nil {
+		return m.Chunks
+	}
+	return nil
 }
 
-func (m *LegacyLabelPair) Reset()      { *m = LegacyLabelPair{} }
-func (*LegacyLabelPair) ProtoMessage() {}
-func (*LegacyLabelPair) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{21}
+type HandoffStreamsResponse struct {
 }
-func (m *LegacyLabelPair) XXX_Unmarshal(b []byte) error {
+
+func (m *HandoffStreamsResponse) Reset()      { *m = HandoffStreamsResponse{} }
+func (*HandoffStreamsResponse) ProtoMessage() {}
+func (*HandoffStreamsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{26}
+}
+func (m *HandoffStreamsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
 }
-func (m *LegacyLabelPair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+func (m *HandoffStreamsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
 	if deterministic {
-		return xxx_messageInfo_LegacyLabelPair.Marshal(b, m, deterministic)
+		return xxx_messageInfo_HandoffStreamsResponse.Marshal(b, m, deterministic)
 	} else {
 		b = b[:cap(b)]
 		n, err := m.MarshalToSizedBuffer(b)
@@ -1275,14 +1565,103 @@ func (m *LegacyLabelPair) XXX_Marshal(b []byte, deterministic bool) ([]byte, err
 		return b[:n], nil
 	}
 }
-func (m *LegacyLabelPair) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_LegacyLabelPair.Merge(m, src)
+func (m *HandoffStreamsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HandoffStreamsResponse.Merge(m, src)
 }
-func (m *LegacyLabelPair) XXX_Size() int {
+func (m *HandoffStreamsResponse) XXX_Size() int {
 	return m.Size()
 }
-func (m *LegacyLabelPair) XXX_DiscardUnknown() {
-	xxx_messageInfo_LegacyLabelPair.DiscardUnknown(m)
+func (m *HandoffStreamsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HandoffStreamsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HandoffStreamsResponse proto.InternalMessageInfo
+
+type LabelPair struct {
+	Name  string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *LabelPair) Reset()      { *m = LabelPair{} }
+func (*LabelPair) ProtoMessage() {}
+func (*LabelPair) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{27}
+}
+func (m *LabelPair) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LabelPair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LabelPair.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LabelPair) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LabelPair.Merge(m, src)
+}
+func (m *LabelPair) XXX_Size() int {
+	return m.Size()
+}
+func (m *LabelPair) XXX_DiscardUnknown() {
+	xxx_messageInfo_LabelPair.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LabelPair proto.InternalMessageInfo
+
+func (m *LabelPair) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LabelPair) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+// LegacyLabelPair exists for backwards compatibility reasons and is deprecated. Do not use.
+type LegacyLabelPair struct {
+	Name  []byte `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *LegacyLabelPair) Reset()      { *m = LegacyLabelPair{} }
+func (*LegacyLabelPair) ProtoMessage() {}
+func (*LegacyLabelPair) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{28}
+}
+func (m *LegacyLabelPair) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *LegacyLabelPair) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_LegacyLabelPair.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *LegacyLabelPair) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LegacyLabelPair.Merge(m, src)
+}
+func (m *LegacyLabelPair) XXX_Size() int {
+	return m.Size()
+}
+func (m *LegacyLabelPair) XXX_DiscardUnknown() {
+	xxx_messageInfo_LegacyLabelPair.DiscardUnknown(m)
 }
 
 var xxx_messageInfo_LegacyLabelPair proto.InternalMessageInfo
@@ -1308,7 +1687,7 @@ type Chunk struct {
 func (m *Chunk) Reset()      { *m = Chunk{} }
 func (*Chunk) ProtoMessage() {}
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{22}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{29}
 }
 func (m *Chunk) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1350,7 +1729,7 @@ type TransferChunksResponse struct {
 func (m *TransferChunksResponse) Reset()      { *m = TransferChunksResponse{} }
 func (*TransferChunksResponse) ProtoMessage() {}
 func (*TransferChunksResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{23}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{30}
 }
 func (m *TransferChunksResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1385,7 +1764,7 @@ type TailersCountRequest struct {
 func (m *TailersCountRequest) Reset()      { *m = TailersCountRequest{} }
 func (*TailersCountRequest) ProtoMessage() {}
 func (*TailersCountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{24}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{31}
 }
 func (m *TailersCountRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1421,7 +1800,7 @@ type TailersCountResponse struct {
 func (m *TailersCountResponse) Reset()      { *m = TailersCountResponse{} }
 func (*TailersCountResponse) ProtoMessage() {}
 func (*TailersCountResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{25}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{32}
 }
 func (m *TailersCountResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1466,7 +1845,7 @@ type GetChunkIDsRequest struct {
 func (m *GetChunkIDsRequest) Reset()      { *m = GetChunkIDsRequest{} }
 func (*GetChunkIDsRequest) ProtoMessage() {}
 func (*GetChunkIDsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{26}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{33}
 }
 func (m *GetChunkIDsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1523,7 +1902,7 @@ type GetChunkIDsResponse struct {
 func (m *GetChunkIDsResponse) Reset()      { *m = GetChunkIDsResponse{} }
 func (*GetChunkIDsResponse) ProtoMessage() {}
 func (*GetChunkIDsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{27}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{34}
 }
 func (m *GetChunkIDsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1576,7 +1955,7 @@ type ChunkRef struct {
 func (m *ChunkRef) Reset()      { *m = ChunkRef{} }
 func (*ChunkRef) ProtoMessage() {}
 func (*ChunkRef) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{28}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{35}
 }
 func (m *ChunkRef) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1637,7 +2016,7 @@ type LabelValuesForMetricNameRequest struct {
 func (m *LabelValuesForMetricNameRequest) Reset()      { *m = LabelValuesForMetricNameRequest{} }
 func (*LabelValuesForMetricNameRequest) ProtoMessage() {}
 func (*LabelValuesForMetricNameRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{29}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{36}
 }
 func (m *LabelValuesForMetricNameRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1696,7 +2075,7 @@ type LabelNamesForMetricNameRequest struct {
 func (m *LabelNamesForMetricNameRequest) Reset()      { *m = LabelNamesForMetricNameRequest{} }
 func (*LabelNamesForMetricNameRequest) ProtoMessage() {}
 func (*LabelNamesForMetricNameRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{30}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{37}
 }
 func (m *LabelNamesForMetricNameRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1741,7 +2120,7 @@ type GetChunkRefRequest struct {
 func (m *GetChunkRefRequest) Reset()      { *m = GetChunkRefRequest{} }
 func (*GetChunkRefRequest) ProtoMessage() {}
 func (*GetChunkRefRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{31}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{38}
 }
 func (m *GetChunkRefRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1784,7 +2163,7 @@ type GetChunkRefResponse struct {
 func (m *GetChunkRefResponse) Reset()      { *m = GetChunkRefResponse{} }
 func (*GetChunkRefResponse) ProtoMessage() {}
 func (*GetChunkRefResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{32}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{39}
 }
 func (m *GetChunkRefResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1829,7 +2208,7 @@ type GetSeriesRequest struct {
 func (m *GetSeriesRequest) Reset()      { *m = GetSeriesRequest{} }
 func (*GetSeriesRequest) ProtoMessage() {}
 func (*GetSeriesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{33}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{40}
 }
 func (m *GetSeriesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1872,7 +2251,7 @@ type GetSeriesResponse struct {
 func (m *GetSeriesResponse) Reset()      { *m = GetSeriesResponse{} }
 func (*GetSeriesResponse) ProtoMessage() {}
 func (*GetSeriesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{34}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{41}
 }
 func (m *GetSeriesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1916,7 +2295,7 @@ type IndexSeries struct {
 func (m *IndexSeries) Reset()      { *m = IndexSeries{} }
 func (*IndexSeries) ProtoMessage() {}
 func (*IndexSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{35}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{42}
 }
 func (m *IndexSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1953,7 +2332,7 @@ type QueryIndexResponse struct {
 func (m *QueryIndexResponse) Reset()      { *m = QueryIndexResponse{} }
 func (*QueryIndexResponse) ProtoMessage() {}
 func (*QueryIndexResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{36}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{43}
 }
 func (m *QueryIndexResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2004,7 +2383,7 @@ type Row struct {
 func (m *Row) Reset()      { *m = Row{} }
 func (*Row) ProtoMessage() {}
 func (*Row) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{37}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{44}
 }
 func (m *Row) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2054,7 +2433,7 @@ type QueryIndexRequest struct {
 func (m *QueryIndexRequest) Reset()      { *m = QueryIndexRequest{} }
 func (*QueryIndexRequest) ProtoMessage() {}
 func (*QueryIndexRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{38}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{45}
 }
 func (m *QueryIndexRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2101,7 +2480,7 @@ type IndexQuery struct {
 func (m *IndexQuery) Reset()      { *m = IndexQuery{} }
 func (*IndexQuery) ProtoMessage() {}
 func (*IndexQuery) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{39}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{46}
 }
 func (m *IndexQuery) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2174,7 +2553,7 @@ type IndexStatsRequest struct {
 func (m *IndexStatsRequest) Reset()      { *m = IndexStatsRequest{} }
 func (*IndexStatsRequest) ProtoMessage() {}
 func (*IndexStatsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{40}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{47}
 }
 func (m *IndexStatsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2220,7 +2599,7 @@ type IndexStatsResponse struct {
 func (m *IndexStatsResponse) Reset()      { *m = IndexStatsResponse{} }
 func (*IndexStatsResponse) ProtoMessage() {}
 func (*IndexStatsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c28a5f14f1f4c79a, []int{41}
+	return fileDescriptor_c28a5f14f1f4c79a, []int{48}
 }
 func (m *IndexStatsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2277,11 +2656,185 @@ func (m *IndexStatsResponse) GetEntries() uint64 {
 	return 0
 }
 
+type TopStreamsRequest struct {
+	SortBy string `protobuf:"bytes,1,opt,name=sortBy,proto3" json:"sortBy,omitempty"`
+	Limit  uint32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+}
+
+func (m *TopStreamsRequest) Reset()      { *m = TopStreamsRequest{} }
+func (*TopStreamsRequest) ProtoMessage() {}
+func (*TopStreamsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{49}
+}
+func (m *TopStreamsRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TopStreamsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TopStreamsRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TopStreamsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopStreamsRequest.Merge(m, src)
+}
+func (m *TopStreamsRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *TopStreamsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopStreamsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopStreamsRequest proto.InternalMessageInfo
+
+func (m *TopStreamsRequest) GetSortBy() string {
+	if m != nil {
+		return m.SortBy
+	}
+	return ""
+}
+
+func (m *TopStreamsRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type TopStreamsResponse struct {
+	Streams []*TopStream `protobuf:"bytes,1,rep,name=streams,proto3" json:"streams"`
+}
+
+func (m *TopStreamsResponse) Reset()      { *m = TopStreamsResponse{} }
+func (*TopStreamsResponse) ProtoMessage() {}
+func (*TopStreamsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{50}
+}
+func (m *TopStreamsResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TopStreamsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TopStreamsResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TopStreamsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopStreamsResponse.Merge(m, src)
+}
+func (m *TopStreamsResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *TopStreamsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopStreamsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopStreamsResponse proto.InternalMessageInfo
+
+func (m *TopStreamsResponse) GetStreams() []*TopStream {
+	if m != nil {
+		return m.Streams
+	}
+	return nil
+}
+
+type TopStream struct {
+	Labels      string `protobuf:"bytes,1,opt,name=labels,proto3" json:"labels"`
+	BytesRate   int64  `protobuf:"varint,2,opt,name=bytesRate,proto3" json:"bytes_rate"`
+	EntriesRate int64  `protobuf:"varint,3,opt,name=entriesRate,proto3" json:"entries_rate"`
+	MemoryBytes int64  `protobuf:"varint,4,opt,name=memoryBytes,proto3" json:"memory_bytes"`
+	Chunks      int64  `protobuf:"varint,5,opt,name=chunks,proto3" json:"chunks"`
+}
+
+func (m *TopStream) Reset()      { *m = TopStream{} }
+func (*TopStream) ProtoMessage() {}
+func (*TopStream) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c28a5f14f1f4c79a, []int{51}
+}
+func (m *TopStream) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *TopStream) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_TopStream.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *TopStream) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopStream.Merge(m, src)
+}
+func (m *TopStream) XXX_Size() int {
+	return m.Size()
+}
+func (m *TopStream) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopStream.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopStream proto.InternalMessageInfo
+
+func (m *TopStream) GetLabels() string {
+	if m != nil {
+		return m.Labels
+	}
+	return ""
+}
+
+func (m *TopStream) GetBytesRate() int64 {
+	if m != nil {
+		return m.BytesRate
+	}
+	return 0
+}
+
+func (m *TopStream) GetEntriesRate() int64 {
+	if m != nil {
+		return m.EntriesRate
+	}
+	return 0
+}
+
+func (m *TopStream) GetMemoryBytes() int64 {
+	if m != nil {
+		return m.MemoryBytes
+	}
+	return 0
+}
+
+func (m *TopStream) GetChunks() int64 {
+	if m != nil {
+		return m.Chunks
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("logproto.Direction", Direction_name, Direction_value)
 	proto.RegisterType((*StreamRatesRequest)(nil), "logproto.StreamRatesRequest")
 	proto.RegisterType((*StreamRatesResponse)(nil), "logproto.StreamRatesResponse")
 	proto.RegisterType((*StreamRate)(nil), "logproto.StreamRate")
+	proto.RegisterType((*TenantVolumesRequest)(nil), "logproto.TenantVolumesRequest")
+	proto.RegisterType((*TenantVolumesResponse)(nil), "logproto.TenantVolumesResponse")
+	proto.RegisterType((*TenantVolume)(nil), "logproto.TenantVolume")
+	proto.RegisterType((*PushStreamRequest)(nil), "logproto.PushStreamRequest")
+	proto.RegisterType((*PushStreamResponse)(nil), "logproto.PushStreamResponse")
 	proto.RegisterType((*QueryRequest)(nil), "logproto.QueryRequest")
 	proto.RegisterType((*SampleQueryRequest)(nil), "logproto.SampleQueryRequest")
 	proto.RegisterType((*Delete)(nil), "logproto.Delete")
@@ -2300,6 +2853,8 @@ func init() {
 	proto.RegisterMapType((map[string]string)(nil), "logproto.SeriesIdentifier.LabelsEntry")
 	proto.RegisterType((*DroppedStream)(nil), "logproto.DroppedStream")
 	proto.RegisterType((*TimeSeriesChunk)(nil), "logproto.TimeSeriesChunk")
+	proto.RegisterType((*HandoffStreamsRequest)(nil), "logproto.HandoffStreamsRequest")
+	proto.RegisterType((*HandoffStreamsResponse)(nil), "logproto.HandoffStreamsResponse")
 	proto.RegisterType((*LabelPair)(nil), "logproto.LabelPair")
 	proto.RegisterType((*LegacyLabelPair)(nil), "logproto.LegacyLabelPair")
 	proto.RegisterType((*Chunk)(nil), "logproto.Chunk")
@@ -2322,144 +2877,172 @@ func init() {
 	proto.RegisterType((*IndexQuery)(nil), "logproto.IndexQuery")
 	proto.RegisterType((*IndexStatsRequest)(nil), "logproto.IndexStatsRequest")
 	proto.RegisterType((*IndexStatsResponse)(nil), "logproto.IndexStatsResponse")
+	proto.RegisterType((*TopStreamsRequest)(nil), "logproto.TopStreamsRequest")
+	proto.RegisterType((*TopStreamsResponse)(nil), "logproto.TopStreamsResponse")
+	proto.RegisterType((*TopStream)(nil), "logproto.TopStream")
 }
 
 func init() { proto.RegisterFile("pkg/logproto/logproto.proto", fileDescriptor_c28a5f14f1f4c79a) }
 
 var fileDescriptor_c28a5f14f1f4c79a = []byte{
-	// 2098 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x19, 0x4b, 0x6f, 0x1b, 0xc7,
-	0x99, 0x43, 0x2e, 0x29, 0xf2, 0x23, 0x25, 0xd1, 0x23, 0xc6, 0x66, 0x69, 0x9b, 0x94, 0x17, 0xa9,
-	0x2d, 0xd8, 0x0e, 0x59, 0x2b, 0x6d, 0xea, 0xd8, 0x4d, 0x0b, 0x53, 0x8a, 0x1d, 0xf9, 0x9d, 0x91,
-	0xeb, 0x02, 0x41, 0x03, 0x63, 0x45, 0x0e, 0x1f, 0x10, 0x97, 0x4b, 0xef, 0x0e, 0xeb, 0x08, 0xe8,
-	0xa1, 0xa7, 0xde, 0x02, 0xe4, 0x56, 0xf4, 0xd6, 0x43, 0x81, 0x16, 0x05, 0x7a, 0x29, 0xd0, 0x6b,
-	0xdb, 0x43, 0x81, 0xba, 0x37, 0xf7, 0x16, 0xf4, 0xc0, 0xd6, 0xf2, 0xa5, 0xd0, 0x29, 0xbf, 0xa0,
-	0x28, 0xe6, 0xb5, 0x3b, 0x5c, 0x51, 0x49, 0xe8, 0x1a, 0x08, 0x72, 0x11, 0x77, 0xbe, 0xf9, 0xe6,
-	0x9b, 0xef, 0xfd, 0x18, 0xc1, 0xc9, 0xd1, 0x6e, 0xb7, 0x31, 0xf0, 0xba, 0x23, 0xdf, 0x63, 0x5e,
-	0xf8, 0x51, 0x17, 0x7f, 0x71, 0x56, 0xaf, 0x2b, 0xa5, 0xae, 0xd7, 0xf5, 0x24, 0x0e, 0xff, 0x92,
-	0xfb, 0x95, 0x5a, 0xd7, 0xf3, 0xba, 0x03, 0xda, 0x10, 0xab, 0x9d, 0x71, 0xa7, 0xc1, 0xfa, 0x2e,
-	0x0d, 0x98, 0xe3, 0x8e, 0x14, 0xc2, 0xaa, 0xa2, 0xfe, 0x78, 0xe0, 0x7a, 0x6d, 0x3a, 0x68, 0x04,
-	0xcc, 0x61, 0x81, 0xfc, 0xab, 0x30, 0x56, 0x38, 0xc6, 0x68, 0x1c, 0xf4, 0xc4, 0x1f, 0x09, 0xb4,
-	0x4b, 0x80, 0xb7, 0x99, 0x4f, 0x1d, 0x97, 0x38, 0x8c, 0x06, 0x84, 0x3e, 0x1e, 0xd3, 0x80, 0xd9,
-	0x77, 0x60, 0x65, 0x0a, 0x1a, 0x8c, 0xbc, 0x61, 0x40, 0xf1, 0x5b, 0x90, 0x0f, 0x22, 0x70, 0x19,
-	0xad, 0xa6, 0xd6, 0xf2, 0xeb, 0xa5, 0x7a, 0x28, 0x4a, 0x74, 0x86, 0x98, 0x88, 0xf6, 0xcf, 0x11,
-	0x40, 0xb4, 0x87, 0xab, 0x00, 0x72, 0xf7, 0x3d, 0x27, 0xe8, 0x95, 0xd1, 0x2a, 0x5a, 0xb3, 0x88,
-	0x01, 0xc1, 0x17, 0xe1, 0x58, 0xb4, 0xba, 0xeb, 0x6d, 0xf7, 0x1c, 0xbf, 0x5d, 0x4e, 0x0a, 0xb4,
-	0xc3, 0x1b, 0x18, 0x83, 0xe5, 0x3b, 0x8c, 0x96, 0x53, 0xab, 0x68, 0x2d, 0x45, 0xc4, 0x37, 0x3e,
-	0x0e, 0x19, 0x46, 0x87, 0xce, 0x90, 0x95, 0xad, 0x55, 0xb4, 0x96, 0x23, 0x6a, 0x65, 0xff, 0x2d,
-	0x09, 0x85, 0xf7, 0xc7, 0xd4, 0xdf, 0x53, 0x82, 0xe2, 0x0a, 0x64, 0x03, 0x3a, 0xa0, 0x2d, 0xe6,
-	0xf9, 0x82, 0x91, 0x1c, 0x09, 0xd7, 0xb8, 0x04, 0xe9, 0x41, 0xdf, 0xed, 0x33, 0x71, 0xf5, 0x22,
-	0x91, 0x0b, 0x7c, 0x05, 0xd2, 0x01, 0x73, 0x7c, 0x26, 0xee, 0xcb, 0xaf, 0x57, 0xea, 0xd2, 0x30,
-	0x75, 0x6d, 0x98, 0xfa, 0x03, 0x6d, 0x98, 0x66, 0xf6, 0xe9, 0xa4, 0x96, 0xf8, 0xe4, 0x5f, 0x35,
-	0x44, 0xe4, 0x11, 0xfc, 0x16, 0xa4, 0xe8, 0xb0, 0x2d, 0x78, 0xfa, 0xb2, 0x27, 0xf9, 0x01, 0x7c,
-	0x09, 0x72, 0xed, 0xbe, 0x4f, 0x5b, 0xac, 0xef, 0x0d, 0xcb, 0xe9, 0x55, 0xb4, 0xb6, 0xb4, 0xbe,
-	0x12, 0x69, 0x7d, 0x53, 0x6f, 0x91, 0x08, 0x0b, 0x5f, 0x84, 0x4c, 0xc0, 0xd5, 0x13, 0x94, 0x17,
-	0x56, 0x53, 0x6b, 0xb9, 0x66, 0xe9, 0x60, 0x52, 0x2b, 0x4a, 0xc8, 0x45, 0xcf, 0xed, 0x33, 0xea,
-	0x8e, 0xd8, 0x1e, 0x51, 0x38, 0xf8, 0x3c, 0x2c, 0xb4, 0xe9, 0x80, 0x72, 0xa3, 0x66, 0x85, 0x51,
-	0x8b, 0x06, 0x79, 0xb1, 0x41, 0x34, 0xc2, 0x4d, 0x2b, 0x9b, 0x29, 0x2e, 0xd8, 0xff, 0x45, 0x80,
-	0xb7, 0x1d, 0x77, 0x34, 0xa0, 0x5f, 0x5a, 0x9f, 0xa1, 0xe6, 0x92, 0x2f, 0xad, 0xb9, 0xd4, 0xbc,
-	0x9a, 0x8b, 0xd4, 0x60, 0xcd, 0xa7, 0x86, 0xf4, 0x17, 0xa8, 0xc1, 0xbe, 0x0d, 0x19, 0x09, 0xfa,
-	0x22, 0x1f, 0x8a, 0x64, 0x4e, 0x69, 0x69, 0x8a, 0x91, 0x34, 0x29, 0xc1, 0xa7, 0xfd, 0x2b, 0x04,
-	0x8b, 0x4a, 0x91, 0x2a, 0xd6, 0x76, 0x60, 0x41, 0xfa, 0xba, 0x8e, 0xb3, 0x13, 0xf1, 0x38, 0xbb,
-	0xd6, 0x76, 0x46, 0x8c, 0xfa, 0xcd, 0xc6, 0xd3, 0x49, 0x0d, 0xfd, 0x73, 0x52, 0x3b, 0xd7, 0xed,
-	0xb3, 0xde, 0x78, 0xa7, 0xde, 0xf2, 0xdc, 0x46, 0xd7, 0x77, 0x3a, 0xce, 0xd0, 0x69, 0x0c, 0xbc,
-	0xdd, 0x7e, 0x43, 0xc7, 0xbd, 0x8e, 0x4f, 0x4d, 0x18, 0x5f, 0x10, 0xdc, 0xb1, 0x40, 0x59, 0x64,
-	0xb9, 0x2e, 0xd3, 0xc5, 0xd6, 0xb0, 0x4b, 0x03, 0x4e, 0xd9, 0xe2, 0xca, 0x24, 0x12, 0xc7, 0xfe,
-	0x29, 0xac, 0x4c, 0x19, 0x5c, 0xf1, 0x79, 0x19, 0x32, 0x01, 0xf5, 0xfb, 0x61, 0x3a, 0x30, 0x54,
-	0xb6, 0x2d, 0xe0, 0xcd, 0x25, 0xc5, 0x5f, 0x46, 0xae, 0x89, 0xc2, 0x9f, 0xef, 0xf6, 0xbf, 0x22,
-	0x28, 0xdc, 0x76, 0x76, 0xe8, 0x40, 0x7b, 0x1a, 0x06, 0x6b, 0xe8, 0xb8, 0x54, 0x69, 0x5c, 0x7c,
-	0xf3, 0xb0, 0xff, 0x89, 0x33, 0x18, 0x53, 0x49, 0x32, 0x4b, 0xd4, 0x6a, 0xde, 0x98, 0x45, 0x2f,
-	0x1d, 0xb3, 0x28, 0xf2, 0xbc, 0x12, 0xa4, 0x1f, 0x73, 0x45, 0x89, 0x78, 0xcd, 0x11, 0xb9, 0xb0,
-	0xcf, 0xc1, 0xa2, 0x92, 0x42, 0xa9, 0x2f, 0x62, 0x99, 0xab, 0x2f, 0xa7, 0x59, 0xb6, 0x5d, 0xc8,
-	0x48, 0x6d, 0xe3, 0xd7, 0x21, 0x17, 0xe6, 0x7a, 0x21, 0x6d, 0xaa, 0x99, 0x39, 0x98, 0xd4, 0x92,
-	0x2c, 0x20, 0xd1, 0x06, 0xae, 0x41, 0x5a, 0x9c, 0x14, 0x92, 0xa3, 0x66, 0xee, 0x60, 0x52, 0x93,
-	0x00, 0x22, 0x7f, 0xf0, 0x29, 0xb0, 0x7a, 0x3c, 0xdd, 0x72, 0x15, 0x58, 0xcd, 0xec, 0xc1, 0xa4,
-	0x26, 0xd6, 0x44, 0xfc, 0xb5, 0x6f, 0x40, 0xe1, 0x36, 0xed, 0x3a, 0xad, 0x3d, 0x75, 0x69, 0x49,
-	0x93, 0xe3, 0x17, 0x22, 0x4d, 0xe3, 0x0c, 0x14, 0xc2, 0x1b, 0x1f, 0xb9, 0x81, 0x72, 0xea, 0x7c,
-	0x08, 0xbb, 0x13, 0xd8, 0xbf, 0x44, 0xa0, 0xec, 0x8c, 0x6d, 0xc8, 0x0c, 0xb8, 0xac, 0x81, 0xb4,
-	0x51, 0x13, 0x0e, 0x26, 0x35, 0x05, 0x21, 0xea, 0x17, 0x5f, 0x85, 0x85, 0x40, 0xdc, 0xc8, 0x89,
-	0xc5, 0xdd, 0x47, 0x6c, 0x34, 0x97, 0xb9, 0x1b, 0x1c, 0x4c, 0x6a, 0x1a, 0x91, 0xe8, 0x0f, 0x5c,
-	0x9f, 0xaa, 0x23, 0x52, 0xb0, 0xa5, 0x83, 0x49, 0xcd, 0x80, 0x9a, 0x75, 0xc5, 0xfe, 0x05, 0x82,
-	0xfc, 0x03, 0xa7, 0x1f, 0xba, 0x50, 0x68, 0x22, 0x64, 0x98, 0x88, 0x87, 0x73, 0x9b, 0x0e, 0x9c,
-	0xbd, 0xeb, 0x9e, 0x2f, 0x68, 0x2e, 0x92, 0x70, 0x1d, 0x95, 0x04, 0x6b, 0x66, 0x49, 0x48, 0xcf,
-	0x9d, 0xd8, 0x6e, 0x5a, 0xd9, 0x64, 0x31, 0x65, 0xff, 0x1e, 0x41, 0x41, 0x72, 0xa6, 0xdc, 0xe2,
-	0xc7, 0x90, 0x91, 0x8c, 0x0b, 0xde, 0x3e, 0x27, 0xf8, 0x2f, 0xcc, 0x13, 0xf8, 0x8a, 0x26, 0xfe,
-	0x01, 0x2c, 0xb5, 0x7d, 0x6f, 0x34, 0xa2, 0xed, 0x6d, 0x95, 0x62, 0x92, 0xf1, 0x14, 0xb3, 0x69,
-	0xee, 0x93, 0x18, 0xba, 0xfd, 0x77, 0x04, 0x8b, 0x2a, 0x9a, 0x95, 0x2e, 0x43, 0x1d, 0xa0, 0x97,
-	0x4e, 0xee, 0xc9, 0x79, 0x93, 0xfb, 0x71, 0xc8, 0x74, 0x7d, 0x6f, 0x3c, 0x0a, 0xca, 0x29, 0x19,
-	0x3b, 0x72, 0x35, 0x5f, 0xd2, 0xb7, 0x6f, 0xc2, 0x92, 0x16, 0xe5, 0x88, 0x94, 0x56, 0x89, 0xa7,
-	0xb4, 0xad, 0x36, 0x1d, 0xb2, 0x7e, 0xa7, 0x1f, 0x26, 0x29, 0x85, 0x6f, 0x7f, 0x8c, 0xa0, 0x18,
-	0x47, 0xc1, 0xdf, 0x37, 0xe2, 0x80, 0x93, 0x3b, 0x7b, 0x34, 0xb9, 0xba, 0x48, 0x0e, 0xc1, 0xbb,
-	0x43, 0xe6, 0xef, 0xe9, 0x18, 0xa9, 0xbc, 0x0d, 0x79, 0x03, 0xcc, 0x8b, 0xc7, 0x2e, 0xd5, 0x3e,
-	0xcb, 0x3f, 0xa3, 0x60, 0x4d, 0x4a, 0x3f, 0x16, 0x8b, 0x2b, 0xc9, 0xcb, 0x88, 0x7b, 0xfc, 0xe2,
-	0x94, 0x25, 0xf1, 0x65, 0xb0, 0x3a, 0xbe, 0xe7, 0xce, 0x65, 0x26, 0x71, 0x02, 0x7f, 0x1b, 0x92,
-	0xcc, 0x9b, 0xcb, 0x48, 0x49, 0xe6, 0x71, 0x1b, 0x29, 0xe1, 0x53, 0xb2, 0x13, 0x93, 0x2b, 0xfb,
-	0x77, 0x08, 0x96, 0xf9, 0x19, 0xa9, 0x81, 0x8d, 0xde, 0x78, 0xb8, 0x8b, 0xd7, 0xa0, 0xc8, 0x6f,
-	0x7a, 0xd4, 0x57, 0x15, 0xe0, 0x51, 0xbf, 0xad, 0xc4, 0x5c, 0xe2, 0x70, 0x5d, 0x18, 0xb6, 0xda,
-	0xf8, 0x04, 0x2c, 0x8c, 0x03, 0x89, 0x20, 0x65, 0xce, 0xf0, 0xe5, 0x56, 0x1b, 0x5f, 0x30, 0xae,
-	0xe3, 0xba, 0x36, 0xda, 0x24, 0xa1, 0xc3, 0xfb, 0x4e, 0xdf, 0x0f, 0x93, 0xcf, 0x39, 0xc8, 0xb4,
-	0xf8, 0xc5, 0xd2, 0x4f, 0x78, 0x05, 0x0a, 0x91, 0x05, 0x43, 0x44, 0x6d, 0xdb, 0xdf, 0x81, 0x5c,
-	0x78, 0x7a, 0x66, 0xe1, 0x99, 0x69, 0x01, 0xfb, 0x2a, 0x2c, 0xcb, 0xa4, 0x3a, 0xfb, 0x70, 0x61,
-	0xd6, 0xe1, 0x82, 0x3e, 0x7c, 0x12, 0xd2, 0x52, 0x2b, 0x18, 0xac, 0xb6, 0xc3, 0x1c, 0x7d, 0x84,
-	0x7f, 0xdb, 0x65, 0x38, 0xfe, 0xc0, 0x77, 0x86, 0x41, 0x87, 0xfa, 0x02, 0x29, 0xf4, 0x5d, 0xfb,
-	0x35, 0x58, 0xe1, 0x89, 0x84, 0xfa, 0xc1, 0x86, 0x37, 0x1e, 0x32, 0xdd, 0xd0, 0x5f, 0x84, 0xd2,
-	0x34, 0x58, 0xb9, 0x7a, 0x09, 0xd2, 0x2d, 0x0e, 0x10, 0xd4, 0x17, 0x89, 0x5c, 0xd8, 0xbf, 0x46,
-	0x80, 0x6f, 0x50, 0x26, 0x48, 0x6f, 0x6d, 0x06, 0x46, 0x73, 0xe7, 0x3a, 0xac, 0xd5, 0xa3, 0x7e,
-	0xa0, 0x1b, 0x1d, 0xbd, 0xfe, 0x2a, 0x9a, 0x3b, 0xfb, 0x12, 0xac, 0x4c, 0x71, 0xa9, 0x64, 0xaa,
-	0x40, 0xb6, 0xa5, 0x60, 0xaa, 0xa8, 0x86, 0x6b, 0xfb, 0x0f, 0x49, 0xc8, 0x4a, 0xdb, 0xd2, 0x0e,
-	0xbe, 0x04, 0xf9, 0x0e, 0xf7, 0x35, 0x7f, 0xe4, 0xf7, 0x95, 0x0a, 0xac, 0xe6, 0xf2, 0xc1, 0xa4,
-	0x66, 0x82, 0x89, 0xb9, 0xc0, 0x6f, 0xc4, 0x1c, 0xaf, 0x59, 0xda, 0x9f, 0xd4, 0x32, 0x3f, 0xe4,
-	0xce, 0xb7, 0xc9, 0xcb, 0x9b, 0x70, 0xc3, 0xcd, 0xd0, 0x1d, 0x6f, 0xa9, 0x68, 0x13, 0x9d, 0x5e,
-	0xf3, 0xbb, 0x9c, 0xfd, 0x58, 0xbe, 0x1e, 0xf9, 0x9e, 0x4b, 0x59, 0x8f, 0x8e, 0x83, 0x46, 0xcb,
-	0x73, 0x5d, 0x6f, 0xd8, 0x10, 0xe3, 0x9b, 0x10, 0x9a, 0xd7, 0x68, 0x7e, 0x5c, 0x05, 0xe0, 0x03,
-	0x58, 0x60, 0x3d, 0xdf, 0x1b, 0x77, 0x7b, 0xa2, 0xfc, 0xa4, 0x9a, 0x57, 0xe6, 0xa7, 0xa7, 0x29,
-	0x10, 0xfd, 0x81, 0xcf, 0x70, 0x6d, 0xd1, 0xd6, 0x6e, 0x30, 0x76, 0x45, 0xfd, 0x5a, 0x6c, 0xa6,
-	0x0f, 0x26, 0x35, 0xf4, 0x06, 0x09, 0xc1, 0xf6, 0xc7, 0x49, 0xa8, 0x09, 0x17, 0x7e, 0x28, 0x7a,
-	0x93, 0xeb, 0x9e, 0x7f, 0x87, 0x32, 0xbf, 0xdf, 0xba, 0xeb, 0xb8, 0x54, 0xfb, 0x46, 0x0d, 0xf2,
-	0xae, 0x00, 0x3e, 0x32, 0x82, 0x03, 0xdc, 0x10, 0x0f, 0x9f, 0x06, 0x10, 0x61, 0x27, 0xf7, 0x65,
-	0x9c, 0xe4, 0x04, 0x44, 0x6c, 0x6f, 0x4c, 0x69, 0xaa, 0x31, 0xa7, 0x64, 0x4a, 0x43, 0x5b, 0x71,
-	0x0d, 0xcd, 0x4d, 0x27, 0x54, 0x8b, 0xe9, 0xeb, 0xe9, 0x69, 0x5f, 0xb7, 0xff, 0x81, 0xa0, 0x7a,
-	0x5b, 0x73, 0xfe, 0x92, 0xea, 0xd0, 0xf2, 0x26, 0x5f, 0x91, 0xbc, 0xa9, 0xff, 0x4f, 0x5e, 0xfb,
-	0x2f, 0x46, 0xc8, 0x13, 0xda, 0xd1, 0x72, 0x6c, 0x18, 0xe5, 0xe2, 0x55, 0xb0, 0x99, 0x7c, 0x85,
-	0x66, 0x49, 0xc5, 0xcc, 0xf2, 0x4e, 0x94, 0x0e, 0x84, 0x04, 0x2a, 0x1d, 0x9c, 0x05, 0xcb, 0xa7,
-	0x1d, 0x5d, 0x7c, 0x71, 0x3c, 0xc7, 0xd3, 0x0e, 0x11, 0xfb, 0xf6, 0x9f, 0x10, 0x14, 0x6f, 0x50,
-	0x36, 0xdd, 0xd6, 0x7c, 0x9d, 0xe4, 0x7f, 0x0f, 0x8e, 0x19, 0xfc, 0x2b, 0xe9, 0xdf, 0x8c, 0xf5,
-	0x32, 0xaf, 0x45, 0xf2, 0x6f, 0x0d, 0xdb, 0xf4, 0x23, 0x35, 0xa3, 0x4d, 0xb7, 0x31, 0xf7, 0x21,
-	0x6f, 0x6c, 0xe2, 0x6b, 0xb1, 0x06, 0x66, 0x56, 0x51, 0x6d, 0x96, 0x94, 0x4c, 0x72, 0x4a, 0x53,
-	0xed, 0x69, 0x58, 0xee, 0xb7, 0x01, 0x8b, 0xb1, 0x51, 0x90, 0x35, 0x33, 0xb5, 0x80, 0xde, 0x0a,
-	0xfb, 0x99, 0x70, 0x8d, 0xcf, 0x80, 0xe5, 0x7b, 0x4f, 0x74, 0x67, 0xba, 0x18, 0x5d, 0x49, 0xbc,
-	0x27, 0x44, 0x6c, 0xd9, 0x57, 0x21, 0x45, 0xbc, 0x27, 0xb8, 0x0a, 0xe0, 0x3b, 0xc3, 0x2e, 0x7d,
-	0x18, 0x0e, 0x2c, 0x05, 0x62, 0x40, 0x8e, 0xa8, 0xaf, 0x1b, 0x70, 0xcc, 0xe4, 0x48, 0x9a, 0xbb,
-	0x0e, 0x0b, 0x1c, 0xd8, 0x9f, 0xf5, 0xb8, 0x25, 0x10, 0xe5, 0xec, 0xab, 0x91, 0xb8, 0xcf, 0x40,
-	0x04, 0xc7, 0xa7, 0x20, 0xc7, 0x9c, 0x9d, 0x01, 0xbd, 0x1b, 0xc5, 0x7c, 0x04, 0xe0, 0xbb, 0x7c,
-	0xd6, 0x7a, 0x68, 0x34, 0x0a, 0x11, 0x00, 0x9f, 0x87, 0x62, 0xc4, 0xf3, 0x7d, 0x9f, 0x76, 0xfa,
-	0x1f, 0x09, 0x0b, 0x17, 0xc8, 0x21, 0x38, 0x5e, 0x83, 0xe5, 0x08, 0xb6, 0x2d, 0xca, 0xae, 0x25,
-	0x50, 0xe3, 0x60, 0xae, 0x1b, 0x21, 0xee, 0xbb, 0x8f, 0xc7, 0xce, 0x40, 0x24, 0xb2, 0x02, 0x31,
-	0x20, 0xf6, 0x9f, 0x11, 0x1c, 0x93, 0xa6, 0xe6, 0x53, 0xf6, 0xd7, 0xd1, 0xeb, 0x7f, 0x83, 0x00,
-	0x9b, 0x12, 0x28, 0xd7, 0xfa, 0xa6, 0xf9, 0x7c, 0xc2, 0xeb, 0x7a, 0x5e, 0x8c, 0x90, 0x12, 0x14,
-	0xbd, 0x80, 0xd8, 0x61, 0x0b, 0x28, 0xde, 0x17, 0xe5, 0x8c, 0x2a, 0x21, 0xba, 0xfb, 0xe3, 0xa3,
-	0xf5, 0xce, 0x1e, 0xa3, 0x81, 0x9a, 0x30, 0xc5, 0x68, 0x2d, 0x00, 0x44, 0xfe, 0xf0, 0xbb, 0xe8,
-	0x90, 0x09, 0xaf, 0xb1, 0xa2, 0xbb, 0x14, 0x88, 0xe8, 0x8f, 0xf3, 0x67, 0x21, 0x17, 0x3e, 0xd5,
-	0xe1, 0x3c, 0x2c, 0x5c, 0xbf, 0x47, 0x7e, 0x74, 0x8d, 0x6c, 0x16, 0x13, 0xb8, 0x00, 0xd9, 0xe6,
-	0xb5, 0x8d, 0x5b, 0x62, 0x85, 0xd6, 0xff, 0x68, 0x69, 0x2f, 0xf4, 0xf1, 0xf7, 0x20, 0x2d, 0x5d,
-	0xeb, 0x78, 0xe4, 0x88, 0xe6, 0x83, 0x5b, 0xe5, 0xc4, 0x21, 0xb8, 0x6a, 0x04, 0x13, 0xdf, 0x42,
-	0xf8, 0x2e, 0xe4, 0x05, 0x50, 0x0d, 0xf5, 0xa7, 0xe2, 0xb3, 0xf5, 0x14, 0xa5, 0xd3, 0x47, 0xec,
-	0x1a, 0xf4, 0xae, 0x40, 0x5a, 0x44, 0xb7, 0xc9, 0x8d, 0xf9, 0x28, 0x63, 0x72, 0x33, 0xf5, 0xcc,
-	0x61, 0x27, 0xf0, 0xdb, 0x60, 0xf1, 0x0e, 0x14, 0x1b, 0x09, 0xc8, 0x98, 0xc5, 0x2b, 0xc7, 0xe3,
-	0x60, 0xe3, 0xda, 0x77, 0xc2, 0x27, 0x85, 0x13, 0xf1, 0xd1, 0x49, 0x1f, 0x2f, 0x1f, 0xde, 0x08,
-	0x6f, 0xbe, 0x27, 0x67, 0x6b, 0xdd, 0xfb, 0xe2, 0xd3, 0xd3, 0x57, 0xc5, 0x5a, 0xe5, 0x4a, 0xf5,
-	0xa8, 0xed, 0x90, 0xe0, 0x6d, 0xc8, 0x1b, 0x7d, 0xa7, 0xa9, 0xd6, 0xc3, 0x4d, 0xb3, 0xa9, 0xd6,
-	0x19, 0xcd, 0xaa, 0x9d, 0xc0, 0x37, 0x20, 0xcb, 0xd3, 0x36, 0xf7, 0x5e, 0x7c, 0x32, 0x9e, 0x9d,
-	0x8d, 0xa8, 0xac, 0x9c, 0x9a, 0xbd, 0xa9, 0x09, 0xad, 0x7f, 0x08, 0x59, 0x3d, 0x22, 0xe1, 0xf7,
-	0x61, 0x69, 0x7a, 0x40, 0xc0, 0xdf, 0x30, 0xc4, 0x9a, 0x9e, 0xbb, 0x2a, 0xab, 0xc6, 0xd6, 0xec,
-	0xa9, 0x22, 0xb1, 0x86, 0xd6, 0x3f, 0xd4, 0x6f, 0xf8, 0x9b, 0x0e, 0x73, 0xf0, 0x3d, 0x58, 0x12,
-	0x5c, 0x87, 0x8f, 0xfc, 0x53, 0xde, 0x75, 0xe8, 0x3f, 0x0a, 0x53, 0xde, 0x75, 0xf8, 0x3f, 0x0b,
-	0x76, 0xa2, 0xf9, 0xc1, 0xb3, 0xe7, 0xd5, 0xc4, 0xa7, 0xcf, 0xab, 0x89, 0xcf, 0x9e, 0x57, 0xd1,
-	0xcf, 0xf6, 0xab, 0xe8, 0xb7, 0xfb, 0x55, 0xf4, 0x74, 0xbf, 0x8a, 0x9e, 0xed, 0x57, 0xd1, 0xbf,
-	0xf7, 0xab, 0xe8, 0x3f, 0xfb, 0xd5, 0xc4, 0x67, 0xfb, 0x55, 0xf4, 0xc9, 0x8b, 0x6a, 0xe2, 0xd9,
-	0x8b, 0x6a, 0xe2, 0xd3, 0x17, 0xd5, 0xc4, 0x07, 0xaf, 0x7f, 0xde, 0xd3, 0x87, 0xbe, 0x71, 0x27,
-	0x23, 0x7e, 0xde, 0xfc, 0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xf9, 0x75, 0x18, 0x31, 0x82, 0x19,
-	0x00, 0x00,
+	// 2500 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x19, 0x4d, 0x6f, 0x1b, 0xc7,
+	0x55, 0x43, 0x2e, 0x29, 0xf2, 0x91, 0x94, 0xa8, 0x11, 0x2d, 0xb3, 0x8c, 0x4c, 0x2a, 0x8b, 0xd4,
+	0x16, 0x6c, 0x47, 0x8c, 0x95, 0x36, 0x75, 0xec, 0xa4, 0x85, 0x29, 0xc5, 0xb6, 0xfc, 0xed, 0x91,
+	0xec, 0x14, 0x41, 0x53, 0x61, 0x45, 0x0e, 0x29, 0xc2, 0x5c, 0x2e, 0xbd, 0xbb, 0xac, 0x23, 0xa0,
+	0x87, 0x02, 0xbd, 0x15, 0x08, 0x90, 0x5b, 0xd1, 0x5b, 0x0f, 0x05, 0x1a, 0x14, 0x28, 0x0a, 0xf4,
+	0xd0, 0x63, 0xdb, 0x43, 0x81, 0xba, 0x37, 0xf7, 0x16, 0xf4, 0xc0, 0xd6, 0xf2, 0xa5, 0xd5, 0x29,
+	0xbf, 0xa0, 0x28, 0xe6, 0x6b, 0x77, 0x76, 0x45, 0x25, 0xa6, 0xeb, 0xa2, 0xc8, 0x85, 0x9c, 0xf7,
+	0x31, 0x6f, 0xde, 0x7b, 0xf3, 0xe6, 0xbd, 0x37, 0xb3, 0xf0, 0xca, 0xe0, 0x41, 0xa7, 0xde, 0x73,
+	0x3a, 0x03, 0xd7, 0xf1, 0x9d, 0x60, 0xb0, 0xc2, 0x7f, 0x71, 0x46, 0xc1, 0x95, 0x52, 0xc7, 0xe9,
+	0x38, 0x82, 0x87, 0x8d, 0x04, 0xbd, 0x52, 0xeb, 0x38, 0x4e, 0xa7, 0x47, 0xeb, 0x1c, 0xda, 0x19,
+	0xb6, 0xeb, 0x7e, 0xd7, 0xa6, 0x9e, 0x6f, 0xd9, 0x03, 0xc9, 0xb0, 0x24, 0xa5, 0x3f, 0xec, 0xd9,
+	0x4e, 0x8b, 0xf6, 0xea, 0x9e, 0x6f, 0xf9, 0x9e, 0xf8, 0x95, 0x1c, 0xf3, 0x8c, 0x63, 0x30, 0xf4,
+	0x76, 0xf9, 0x8f, 0x40, 0x9a, 0x25, 0xc0, 0x9b, 0xbe, 0x4b, 0x2d, 0x9b, 0x58, 0x3e, 0xf5, 0x08,
+	0x7d, 0x38, 0xa4, 0x9e, 0x6f, 0xde, 0x84, 0xf9, 0x08, 0xd6, 0x1b, 0x38, 0x7d, 0x8f, 0xe2, 0xb7,
+	0x20, 0xe7, 0x85, 0xe8, 0x32, 0x5a, 0x4a, 0x2e, 0xe7, 0x56, 0x4b, 0x2b, 0x81, 0x29, 0xe1, 0x1c,
+	0xa2, 0x33, 0x9a, 0x9f, 0x22, 0x80, 0x90, 0x86, 0xab, 0x00, 0x82, 0x7a, 0xd5, 0xf2, 0x76, 0xcb,
+	0x68, 0x09, 0x2d, 0x1b, 0x44, 0xc3, 0xe0, 0xb3, 0x30, 0x17, 0x42, 0xb7, 0x9c, 0xcd, 0x5d, 0xcb,
+	0x6d, 0x95, 0x13, 0x9c, 0xed, 0x30, 0x01, 0x63, 0x30, 0x5c, 0xcb, 0xa7, 0xe5, 0xe4, 0x12, 0x5a,
+	0x4e, 0x12, 0x3e, 0xc6, 0x0b, 0x90, 0xf6, 0x69, 0xdf, 0xea, 0xfb, 0x65, 0x63, 0x09, 0x2d, 0x67,
+	0x89, 0x84, 0xf0, 0x12, 0xe4, 0x68, 0xdf, 0x77, 0xbb, 0xd4, 0x63, 0x8a, 0x94, 0x53, 0x7c, 0x8a,
+	0x8e, 0x32, 0x17, 0xa0, 0xb4, 0xc5, 0x79, 0xef, 0x3b, 0xbd, 0xa1, 0x1d, 0x7a, 0xe4, 0x1e, 0x1c,
+	0x8b, 0xe1, 0xa5, 0x4f, 0xde, 0x81, 0x82, 0xaf, 0x13, 0xa4, 0x57, 0x16, 0x42, 0xaf, 0xe8, 0xf3,
+	0x48, 0x94, 0xd9, 0xfc, 0x09, 0x82, 0xbc, 0x4e, 0xd7, 0x34, 0x47, 0x11, 0xcd, 0x8b, 0x90, 0x6c,
+	0x59, 0x7b, 0xdc, 0x0b, 0x49, 0xc2, 0x86, 0xb8, 0x02, 0x99, 0x96, 0xb5, 0xd7, 0xd8, 0x63, 0x3b,
+	0x21, 0x6c, 0x0f, 0x60, 0x5c, 0x82, 0x94, 0xed, 0xf4, 0xfd, 0x5d, 0x6e, 0x7e, 0x92, 0x08, 0x80,
+	0xf9, 0x9d, 0x0f, 0xc4, 0x1c, 0x61, 0xbc, 0x86, 0x31, 0xbf, 0x0f, 0x73, 0x77, 0x86, 0xde, 0xae,
+	0xdc, 0x29, 0x61, 0x38, 0x2e, 0xc3, 0xf4, 0x8e, 0xe5, 0x37, 0x77, 0x37, 0xd6, 0xe5, 0x4e, 0x29,
+	0x10, 0xd7, 0x61, 0xda, 0x15, 0x4c, 0x5c, 0xad, 0xdc, 0xea, 0xb1, 0xd0, 0x66, 0x26, 0x47, 0x4a,
+	0x20, 0x8a, 0xcb, 0xfc, 0x2e, 0x60, 0x5d, 0xbe, 0x74, 0xe0, 0xd1, 0x0b, 0x60, 0x30, 0x9a, 0x4e,
+	0x8b, 0x72, 0xe9, 0x29, 0xc2, 0xc7, 0xcc, 0x32, 0xea, 0xba, 0x8e, 0xcb, 0x4d, 0xce, 0x12, 0x01,
+	0x98, 0x7f, 0x4e, 0x40, 0xfe, 0xee, 0x90, 0xba, 0x7b, 0x4a, 0xeb, 0x0a, 0x64, 0x3c, 0xda, 0xa3,
+	0x4d, 0xdf, 0x71, 0xa5, 0x23, 0x03, 0x98, 0x89, 0xe8, 0x75, 0xed, 0xae, 0xd0, 0xba, 0x40, 0x04,
+	0x80, 0x2f, 0x40, 0xca, 0xf3, 0x2d, 0xd7, 0xe7, 0x82, 0x73, 0xab, 0x95, 0x15, 0x71, 0xe0, 0x56,
+	0xd4, 0x81, 0x5b, 0xd9, 0x52, 0x07, 0xae, 0x91, 0x79, 0x3c, 0xaa, 0x4d, 0x7d, 0xf2, 0xf7, 0x1a,
+	0x22, 0x62, 0x0a, 0x7e, 0x0b, 0x92, 0xb4, 0xdf, 0x2a, 0x1b, 0x13, 0xcc, 0x64, 0x13, 0xf0, 0x39,
+	0xc8, 0xb6, 0xba, 0x2e, 0x6d, 0xfa, 0x5d, 0xa7, 0xcf, 0xf7, 0x63, 0x66, 0x75, 0x3e, 0xf4, 0xe1,
+	0xba, 0x22, 0x91, 0x90, 0x0b, 0x9f, 0x85, 0xb4, 0xc7, 0xc2, 0xde, 0x2b, 0x4f, 0x2f, 0x25, 0x97,
+	0xb3, 0x8d, 0xd2, 0xc1, 0xa8, 0x56, 0x14, 0x98, 0xb3, 0x8e, 0xdd, 0xf5, 0xa9, 0x3d, 0xf0, 0xf7,
+	0x88, 0xe4, 0xc1, 0xa7, 0x61, 0xba, 0x45, 0x7b, 0x94, 0x6d, 0x77, 0x86, 0x87, 0x65, 0x51, 0x13,
+	0xcf, 0x09, 0x44, 0x31, 0x5c, 0x33, 0x32, 0xe9, 0xe2, 0xb4, 0xf9, 0x6f, 0x04, 0x78, 0xd3, 0xb2,
+	0x07, 0x3d, 0xfa, 0xdc, 0xfe, 0x0c, 0x3c, 0x97, 0x78, 0x61, 0xcf, 0x25, 0x27, 0xf5, 0x5c, 0xe8,
+	0x06, 0x63, 0x32, 0x37, 0xa4, 0xbe, 0xc4, 0x0d, 0xe6, 0x0d, 0x48, 0x0b, 0xd4, 0x97, 0xc5, 0x50,
+	0x68, 0x73, 0x52, 0x59, 0x53, 0x0c, 0xad, 0x49, 0x72, 0x3d, 0xcd, 0x9f, 0x23, 0x28, 0x48, 0x47,
+	0xca, 0x70, 0xdf, 0x81, 0x69, 0x91, 0xc3, 0x54, 0xa6, 0x38, 0x1e, 0xcf, 0x9f, 0x97, 0x5a, 0xd6,
+	0xc0, 0xa7, 0x6e, 0xa3, 0xfe, 0x78, 0x54, 0x43, 0x7f, 0x1b, 0xd5, 0x4e, 0x75, 0xba, 0xfe, 0xee,
+	0x70, 0x67, 0xa5, 0xe9, 0xd8, 0xf5, 0x8e, 0x6b, 0xb5, 0xad, 0xbe, 0x55, 0xef, 0x39, 0x0f, 0xba,
+	0x75, 0x95, 0xcf, 0xe5, 0x3c, 0xa2, 0x04, 0xe3, 0x33, 0x5c, 0x3b, 0xdf, 0x93, 0x3b, 0x32, 0xbb,
+	0xc2, 0xa1, 0x95, 0x8d, 0x7e, 0x87, 0x7a, 0x4c, 0xb2, 0xc1, 0x9c, 0x49, 0x04, 0x8f, 0xf9, 0x43,
+	0x98, 0x8f, 0x6c, 0xb8, 0xd4, 0xf3, 0x3c, 0xa4, 0x3d, 0xca, 0xd2, 0x62, 0x19, 0xc5, 0x5d, 0xb6,
+	0xc9, 0xf1, 0x8d, 0x19, 0xa9, 0x5f, 0x5a, 0xc0, 0x44, 0xf2, 0x4f, 0xb6, 0xfa, 0x9f, 0x10, 0xe4,
+	0x6f, 0x58, 0x3b, 0xb4, 0xa7, 0x22, 0x0d, 0x83, 0xd1, 0xb7, 0x6c, 0x2a, 0x3d, 0xce, 0xc7, 0x2c,
+	0x29, 0xfe, 0xc0, 0xea, 0x0d, 0xa9, 0x10, 0x99, 0x21, 0x12, 0x9a, 0xf4, 0xcc, 0xa2, 0x17, 0x3e,
+	0xb3, 0x28, 0x8c, 0xbc, 0x12, 0xa4, 0x1e, 0x32, 0x47, 0xf1, 0xf3, 0x9a, 0x25, 0x02, 0x30, 0x4f,
+	0x41, 0x41, 0x5a, 0x21, 0xdd, 0x17, 0xaa, 0xcc, 0xdc, 0x97, 0x55, 0x2a, 0x9b, 0x36, 0xa4, 0x85,
+	0xb7, 0xf1, 0x6b, 0x90, 0x0d, 0x6a, 0x38, 0xb7, 0x36, 0xd9, 0x48, 0x1f, 0x8c, 0x6a, 0x09, 0xdf,
+	0x23, 0x21, 0x01, 0xd7, 0x20, 0xc5, 0x67, 0x72, 0xcb, 0x51, 0x23, 0x7b, 0x30, 0xaa, 0x09, 0x04,
+	0x11, 0x7f, 0x78, 0x11, 0x8c, 0x5d, 0x56, 0x46, 0x99, 0x0b, 0x8c, 0x46, 0xe6, 0x60, 0x54, 0xe3,
+	0x30, 0xe1, 0xbf, 0xe6, 0x15, 0xc8, 0xdf, 0xa0, 0x1d, 0xab, 0xb9, 0x27, 0x17, 0x2d, 0x29, 0x71,
+	0x6c, 0x41, 0xa4, 0x64, 0xbc, 0x0a, 0xf9, 0x60, 0xc5, 0x6d, 0xdb, 0x93, 0x41, 0x9d, 0x0b, 0x70,
+	0x37, 0x3d, 0xf3, 0x67, 0x08, 0xe4, 0x3e, 0x63, 0x13, 0xd2, 0x3d, 0x66, 0xab, 0x27, 0xf6, 0xa8,
+	0x01, 0x07, 0xa3, 0x9a, 0xc4, 0x10, 0xf9, 0x8f, 0x2f, 0xc2, 0xb4, 0xc7, 0x57, 0x64, 0xc2, 0xe2,
+	0xe1, 0xc3, 0x09, 0x8d, 0x59, 0x16, 0x06, 0x07, 0xa3, 0x9a, 0x62, 0x24, 0x6a, 0x80, 0x57, 0x22,
+	0xfd, 0x81, 0x30, 0x6c, 0xe6, 0x60, 0x54, 0xd3, 0xb0, 0x7a, 0xbf, 0x60, 0xfe, 0x06, 0x41, 0x6e,
+	0xcb, 0xea, 0x06, 0x21, 0x14, 0x6c, 0x11, 0xd2, 0xb6, 0x88, 0xd7, 0x4b, 0xda, 0xb3, 0xf6, 0x2e,
+	0xcb, 0xe2, 0x51, 0x20, 0x01, 0x1c, 0x96, 0x04, 0x63, 0x6c, 0x49, 0x48, 0x4d, 0x9e, 0xd8, 0x16,
+	0x20, 0xdd, 0x1c, 0xba, 0x9e, 0xe3, 0x96, 0xd3, 0xa2, 0x8e, 0x0b, 0xe8, 0x9a, 0x91, 0x49, 0x14,
+	0x93, 0xe6, 0xaf, 0x59, 0xd9, 0xb7, 0xba, 0x61, 0xb8, 0x7c, 0x0f, 0xd2, 0xc2, 0x20, 0xae, 0xf3,
+	0x17, 0x24, 0x85, 0x33, 0x93, 0x24, 0x04, 0x29, 0x13, 0x7f, 0x07, 0x66, 0x5a, 0xae, 0x33, 0x18,
+	0xd0, 0xd6, 0xa6, 0x4c, 0x3d, 0x89, 0x78, 0xea, 0x59, 0xd7, 0xe9, 0x24, 0xc6, 0x6e, 0xfe, 0x05,
+	0x41, 0x41, 0x9e, 0x72, 0xe9, 0xe3, 0xc0, 0x37, 0xe8, 0x85, 0x93, 0x7e, 0x62, 0xd2, 0xa4, 0xbf,
+	0x00, 0xe9, 0x8e, 0xeb, 0x0c, 0x07, 0xac, 0xdf, 0xe1, 0x67, 0x4a, 0x40, 0x93, 0x15, 0x03, 0xf3,
+	0x1a, 0xcc, 0x28, 0x53, 0x8e, 0x48, 0x75, 0x95, 0x78, 0xaa, 0xdb, 0x68, 0xd1, 0xbe, 0xdf, 0x6d,
+	0x77, 0x83, 0xe4, 0x25, 0xf9, 0xcd, 0x8f, 0x11, 0x14, 0xe3, 0x2c, 0xf8, 0xdb, 0xda, 0xf9, 0x60,
+	0xe2, 0x4e, 0x1e, 0x2d, 0x6e, 0x85, 0x27, 0x0d, 0xef, 0xbd, 0xbe, 0xef, 0xee, 0xa9, 0xb3, 0x53,
+	0x79, 0x1b, 0x72, 0x1a, 0x9a, 0x15, 0x95, 0x07, 0x54, 0xc5, 0x32, 0x1b, 0x86, 0x87, 0x38, 0x21,
+	0xe2, 0x9b, 0x03, 0x17, 0x12, 0xe7, 0x91, 0xf9, 0x53, 0x04, 0x85, 0xc8, 0x4e, 0xe2, 0xf3, 0x60,
+	0xb4, 0x5d, 0xc7, 0x9e, 0x68, 0x9b, 0xf8, 0x0c, 0xfc, 0x0d, 0x48, 0xf8, 0xce, 0x44, 0x9b, 0x94,
+	0xf0, 0x1d, 0xb6, 0x47, 0xd2, 0x78, 0xd1, 0xa0, 0x49, 0xc8, 0xfc, 0x15, 0x82, 0x59, 0x36, 0x47,
+	0x78, 0x60, 0x6d, 0x77, 0xd8, 0x7f, 0x80, 0x97, 0xa1, 0xc8, 0x56, 0xda, 0xee, 0xca, 0xca, 0xb0,
+	0xdd, 0x6d, 0x49, 0x33, 0x67, 0x18, 0x5e, 0x15, 0x8c, 0x8d, 0x16, 0x3e, 0x0e, 0xd3, 0x43, 0x4f,
+	0x30, 0x08, 0x9b, 0xd3, 0x0c, 0xdc, 0x68, 0xe1, 0x33, 0xda, 0x72, 0xcc, 0xd7, 0x5a, 0xfb, 0xc4,
+	0x7d, 0x78, 0xc7, 0xea, 0xba, 0x41, 0x52, 0x3a, 0x05, 0xe9, 0x26, 0x5b, 0x58, 0xc4, 0x09, 0xab,
+	0x4c, 0x01, 0x33, 0x57, 0x88, 0x48, 0xb2, 0xf9, 0x14, 0xc1, 0xb1, 0xab, 0x56, 0xbf, 0xe5, 0xb4,
+	0xdb, 0xf2, 0x04, 0xa8, 0xb0, 0x7f, 0x09, 0x2a, 0x87, 0x47, 0x3d, 0xf9, 0x3f, 0x38, 0xea, 0xcf,
+	0x6d, 0x63, 0x19, 0x16, 0xe2, 0x26, 0x8a, 0xe3, 0x60, 0x7e, 0x13, 0xb2, 0x81, 0xef, 0xc6, 0x96,
+	0xe3, 0xb1, 0xf1, 0x67, 0x5e, 0x84, 0x59, 0x51, 0x6a, 0xc6, 0x4f, 0xce, 0x8f, 0x9b, 0x9c, 0x57,
+	0x93, 0x5f, 0x81, 0x94, 0x88, 0x09, 0x0c, 0x46, 0xcb, 0xf2, 0x2d, 0x35, 0x85, 0x8d, 0x99, 0xaa,
+	0x5b, 0xae, 0xd5, 0xf7, 0xda, 0xd4, 0xe5, 0x4c, 0xa1, 0xaa, 0xc7, 0x60, 0x9e, 0xa5, 0x51, 0xea,
+	0x7a, 0x6b, 0xce, 0xb0, 0xef, 0xab, 0xcb, 0xda, 0x59, 0x28, 0x45, 0xd1, 0xf2, 0xa0, 0x97, 0x20,
+	0xd5, 0x64, 0x08, 0x2e, 0xbd, 0x40, 0x04, 0x60, 0xfe, 0x02, 0x01, 0xbe, 0x42, 0x7d, 0x2e, 0x7a,
+	0x63, 0xdd, 0xd3, 0x5a, 0x5e, 0x9b, 0x5d, 0x44, 0xa8, 0xeb, 0xa9, 0xf6, 0x4f, 0xc1, 0xff, 0x8f,
+	0x96, 0xd7, 0x3c, 0x07, 0xf3, 0x11, 0x2d, 0xa5, 0x4d, 0x15, 0xc8, 0x34, 0x25, 0x4e, 0xb6, 0x1a,
+	0x01, 0x6c, 0xfe, 0x36, 0x01, 0x19, 0xb1, 0xeb, 0xb4, 0x8d, 0xcf, 0x41, 0xae, 0xcd, 0xc2, 0xd6,
+	0x1d, 0xb8, 0x5d, 0xe9, 0x02, 0xa3, 0x31, 0x7b, 0x30, 0xaa, 0xe9, 0x68, 0xa2, 0x03, 0xf8, 0xf5,
+	0x58, 0x0c, 0x37, 0x4a, 0xfb, 0xa3, 0x5a, 0xfa, 0x1e, 0x8b, 0xe3, 0x75, 0x56, 0xf4, 0x79, 0x44,
+	0xaf, 0x07, 0x91, 0x7d, 0x5d, 0xe6, 0x1a, 0xde, 0xff, 0x36, 0xbe, 0xc5, 0xd4, 0x8f, 0x85, 0xf0,
+	0xc0, 0x75, 0x6c, 0xea, 0xef, 0xd2, 0xa1, 0x57, 0x6f, 0x3a, 0xb6, 0xed, 0xf4, 0xeb, 0xfc, 0xb1,
+	0x82, 0x1b, 0xcd, 0x3a, 0x17, 0x36, 0x5d, 0xa6, 0x9f, 0x2d, 0x98, 0xf6, 0x77, 0x5d, 0x67, 0xd8,
+	0x91, 0x97, 0xd8, 0xc6, 0x85, 0xc9, 0xe5, 0x29, 0x09, 0x44, 0x0d, 0xf0, 0xab, 0xcc, 0x5b, 0xb4,
+	0xf9, 0xc0, 0x1b, 0xda, 0xbc, 0xaa, 0x17, 0x1a, 0xa9, 0x83, 0x51, 0x0d, 0xbd, 0x4e, 0x02, 0xb4,
+	0xf9, 0x71, 0x02, 0x6a, 0x3c, 0x84, 0xef, 0xf3, 0x8e, 0xed, 0xb2, 0xe3, 0xde, 0xa4, 0xbe, 0xdb,
+	0x6d, 0xde, 0xb2, 0x6c, 0xaa, 0x62, 0xa3, 0x06, 0x39, 0x9b, 0x23, 0xb7, 0xb5, 0xc3, 0x01, 0x76,
+	0xc0, 0x87, 0x4f, 0x00, 0xf0, 0xa4, 0x23, 0xe8, 0xe2, 0x9c, 0x64, 0x39, 0x86, 0x93, 0xd7, 0x22,
+	0x9e, 0xaa, 0x4f, 0x68, 0x99, 0xf4, 0xd0, 0x46, 0xdc, 0x43, 0x13, 0xcb, 0x09, 0xdc, 0xa2, 0xc7,
+	0x7a, 0x2a, 0x1a, 0xeb, 0xe6, 0x5f, 0x11, 0x54, 0x6f, 0x28, 0xcd, 0x5f, 0xd0, 0x1d, 0xca, 0xde,
+	0xc4, 0x4b, 0xb2, 0x37, 0xf9, 0xdf, 0xd9, 0x6b, 0xfe, 0x51, 0x3b, 0xf2, 0x84, 0xb6, 0x95, 0x1d,
+	0x6b, 0x5a, 0xb1, 0x7c, 0x19, 0x6a, 0x26, 0x5e, 0xe2, 0xb6, 0x24, 0x63, 0xdb, 0xf2, 0x2e, 0xcc,
+	0x47, 0x2c, 0x90, 0xe9, 0xe0, 0x24, 0x18, 0x2e, 0x6d, 0xab, 0xd6, 0x03, 0xc7, 0xb3, 0x3f, 0x6d,
+	0x13, 0x4e, 0x37, 0x7f, 0x8f, 0xa0, 0x78, 0x85, 0xfa, 0xd1, 0xa6, 0xee, 0xab, 0x64, 0xff, 0x55,
+	0x98, 0xd3, 0xf4, 0x97, 0xd6, 0xbf, 0x19, 0xeb, 0xe4, 0xb4, 0x17, 0xa9, 0x8d, 0x7e, 0x8b, 0x7e,
+	0x24, 0x6f, 0xae, 0xd1, 0x26, 0xee, 0x0e, 0xe4, 0x34, 0x22, 0xbe, 0x14, 0x6b, 0xdf, 0xc6, 0xb5,
+	0x14, 0x8d, 0x92, 0xb4, 0x49, 0xdc, 0x5d, 0x65, 0xc5, 0x0e, 0x9a, 0x9d, 0x4d, 0xc0, 0xfc, 0x32,
+	0xcd, 0xc5, 0xea, 0x99, 0x9a, 0x63, 0xaf, 0x07, 0xdd, 0x5c, 0x00, 0xe3, 0x57, 0xc1, 0x70, 0x9d,
+	0x47, 0xaa, 0x2f, 0x2f, 0x84, 0x4b, 0x12, 0xe7, 0x11, 0xe1, 0x24, 0xf3, 0x22, 0x24, 0x89, 0xf3,
+	0x88, 0x3d, 0xe2, 0xb9, 0x56, 0xbf, 0x43, 0xef, 0x07, 0xd7, 0xb8, 0x3c, 0xd1, 0x30, 0x47, 0xd4,
+	0xd7, 0x35, 0x98, 0xd3, 0x35, 0x12, 0xdb, 0xbd, 0x02, 0xd3, 0x77, 0x87, 0xba, 0xbb, 0x4a, 0x31,
+	0x77, 0xf1, 0x29, 0x44, 0x31, 0xb1, 0x98, 0x81, 0x10, 0x8f, 0x17, 0x21, 0xeb, 0x5b, 0x3b, 0x3d,
+	0x7a, 0x2b, 0x3c, 0xf3, 0x21, 0x82, 0x51, 0xd9, 0x0d, 0xf4, 0xbe, 0xd6, 0x28, 0x84, 0x08, 0x7c,
+	0x1a, 0x8a, 0xa1, 0xce, 0x77, 0x5c, 0xda, 0xee, 0x7e, 0xc4, 0x77, 0x38, 0x4f, 0x0e, 0xe1, 0xf1,
+	0x32, 0xcc, 0x86, 0xb8, 0x4d, 0x5e, 0x76, 0x0d, 0xce, 0x1a, 0x47, 0x33, 0xdf, 0x70, 0x73, 0xdf,
+	0x7b, 0x38, 0xb4, 0x7a, 0x3c, 0x91, 0xe5, 0x89, 0x86, 0x31, 0xff, 0x80, 0x60, 0x4e, 0x6c, 0xb5,
+	0x6f, 0xf9, 0x5f, 0xc9, 0xa8, 0xff, 0x25, 0x02, 0xac, 0x5b, 0x20, 0x43, 0xeb, 0xeb, 0xfa, 0xa3,
+	0x12, 0xab, 0xeb, 0x39, 0x7e, 0xb1, 0x16, 0xa8, 0xf0, 0x5d, 0xc8, 0x0c, 0x9a, 0x43, 0xfe, 0x9a,
+	0x2e, 0x6e, 0xee, 0x02, 0xa3, 0xfa, 0x42, 0xf6, 0xe0, 0xb0, 0x13, 0xbc, 0x29, 0x1b, 0xe2, 0xc1,
+	0x81, 0x23, 0x88, 0xf8, 0x63, 0x6b, 0xc9, 0x07, 0xf3, 0xb2, 0x11, 0xae, 0x25, 0x51, 0x44, 0x0d,
+	0xcc, 0x4b, 0x30, 0xb7, 0xe5, 0x0c, 0x62, 0xed, 0xf3, 0x02, 0xa4, 0x3d, 0xc7, 0xf5, 0x1b, 0xea,
+	0x00, 0x48, 0x68, 0xfc, 0x93, 0xac, 0x79, 0x07, 0xb0, 0x2e, 0x42, 0xda, 0x7a, 0x21, 0xfe, 0x80,
+	0xa6, 0x1d, 0xd0, 0x80, 0x7d, 0xbc, 0x03, 0xcc, 0x7f, 0x21, 0xc8, 0x06, 0x3c, 0xcf, 0xf5, 0x90,
+	0x71, 0x16, 0xb2, 0xc2, 0x7a, 0xf6, 0xbd, 0x40, 0xec, 0x2c, 0x7f, 0x8a, 0xe0, 0xc8, 0x6d, 0xd7,
+	0xf2, 0x29, 0x09, 0x19, 0xf0, 0x6a, 0xf4, 0xfb, 0x82, 0x28, 0x53, 0xc5, 0x83, 0x51, 0x2d, 0x2f,
+	0xd1, 0x62, 0x86, 0xce, 0xc4, 0xe6, 0xd8, 0xd4, 0x76, 0x5c, 0xf9, 0x94, 0x6f, 0x84, 0x73, 0x04,
+	0x7a, 0x5b, 0x88, 0xd7, 0x99, 0xb4, 0x8d, 0xe4, 0xaf, 0xf8, 0xe3, 0x36, 0xf2, 0xf4, 0x49, 0xc8,
+	0x06, 0x2f, 0xc8, 0x38, 0x07, 0xd3, 0x97, 0x6f, 0x93, 0xf7, 0x2f, 0x91, 0xf5, 0xe2, 0x14, 0xce,
+	0x43, 0xa6, 0x71, 0x69, 0xed, 0x3a, 0x87, 0xd0, 0xea, 0x8f, 0x53, 0x2a, 0x0d, 0xb8, 0xf8, 0x1d,
+	0x48, 0x89, 0xb3, 0xad, 0x7d, 0xbe, 0xd0, 0xdf, 0x81, 0x2b, 0xc7, 0x0f, 0xe1, 0x65, 0x27, 0x3e,
+	0xf5, 0x06, 0xc2, 0xb7, 0x20, 0xc7, 0x91, 0xf2, 0xad, 0x69, 0x31, 0xfe, 0xe4, 0x13, 0x91, 0x74,
+	0xe2, 0x08, 0xaa, 0x26, 0xef, 0x02, 0xa4, 0x78, 0x7a, 0xd5, 0xb5, 0xd1, 0xdf, 0x0a, 0x2b, 0xc7,
+	0x0f, 0xe1, 0xd5, 0x6c, 0xfc, 0x36, 0x18, 0xec, 0x0a, 0x80, 0xb5, 0x0a, 0xa0, 0x3d, 0x11, 0x55,
+	0x16, 0xe2, 0x68, 0x6d, 0xd9, 0x77, 0x83, 0x97, 0xae, 0xe3, 0xf1, 0x9b, 0xbb, 0x9a, 0x5e, 0x3e,
+	0x4c, 0x08, 0x56, 0xbe, 0x0d, 0x79, 0xfd, 0xf2, 0x81, 0x4f, 0x44, 0x97, 0x8a, 0xdd, 0x55, 0x2a,
+	0xd5, 0xa3, 0xc8, 0x81, 0xc0, 0x1b, 0x90, 0xd3, 0x1a, 0x7f, 0xdd, 0xad, 0x87, 0x6f, 0x2d, 0x95,
+	0x13, 0x47, 0x50, 0x03, 0x69, 0x57, 0x20, 0xc3, 0xea, 0x26, 0x4b, 0x1f, 0xf8, 0x95, 0x78, 0x79,
+	0xd4, 0xd2, 0x62, 0x65, 0x71, 0x3c, 0x51, 0x53, 0xab, 0x70, 0x85, 0xfa, 0xe1, 0x01, 0xd5, 0xa5,
+	0x1d, 0x3a, 0xf9, 0x95, 0xc5, 0xf1, 0x44, 0x25, 0x6d, 0xf5, 0x77, 0x08, 0x32, 0xea, 0xf6, 0x8c,
+	0xef, 0xc2, 0x4c, 0xf4, 0xc2, 0x87, 0xbf, 0xa6, 0x4d, 0x8f, 0xbe, 0x22, 0x54, 0x96, 0x34, 0xd2,
+	0xf8, 0x5b, 0xe2, 0xd4, 0x32, 0xc2, 0xef, 0xc3, 0x4c, 0xf4, 0xba, 0x8b, 0x6b, 0xe1, 0xbc, 0xb1,
+	0x77, 0xfd, 0xca, 0xd2, 0xd1, 0x0c, 0xa1, 0xe0, 0xd5, 0x0f, 0xd5, 0xa7, 0xcd, 0x75, 0xcb, 0xb7,
+	0xf0, 0x6d, 0x98, 0xe1, 0xde, 0x0d, 0xbe, 0x7d, 0x46, 0x4e, 0xc1, 0xa1, 0x0f, 0xad, 0x95, 0x13,
+	0x47, 0x50, 0x03, 0xbf, 0xb4, 0xa1, 0x10, 0xf9, 0xee, 0x88, 0xef, 0xf1, 0xbe, 0x2d, 0x8a, 0xab,
+	0x8e, 0xff, 0xd8, 0x18, 0xac, 0x52, 0x3b, 0x92, 0x1e, 0xac, 0xf3, 0x21, 0xe4, 0x85, 0x02, 0xec,
+	0x0b, 0x1d, 0x75, 0xf1, 0x4d, 0x80, 0xf0, 0x5b, 0x9d, 0xbe, 0xb5, 0x87, 0xbe, 0x10, 0x56, 0x16,
+	0xc7, 0x13, 0x43, 0x1f, 0xbd, 0x81, 0x1a, 0x1f, 0x3c, 0x79, 0x5a, 0x9d, 0xfa, 0xec, 0x69, 0x75,
+	0xea, 0xf3, 0xa7, 0x55, 0xf4, 0xa3, 0xfd, 0x2a, 0xfa, 0x74, 0xbf, 0x8a, 0x1e, 0xef, 0x57, 0xd1,
+	0x93, 0xfd, 0x2a, 0xfa, 0xc7, 0x7e, 0x15, 0xfd, 0x73, 0xbf, 0x3a, 0xf5, 0xf9, 0x7e, 0x15, 0x7d,
+	0xf2, 0xac, 0x3a, 0xf5, 0xe4, 0x59, 0x75, 0xea, 0xb3, 0x67, 0xd5, 0xa9, 0x0f, 0x5e, 0xfb, 0xa2,
+	0xd7, 0x0f, 0xb5, 0xe8, 0x4e, 0x9a, 0xff, 0xbd, 0xf9, 0x9f, 0x01, 0x00, 0x4f, 0x2d, 0x92, 0x02,
+	0x60, 0x1f, 0x00, 0x00,
 }
 
 func (x Direction) String() string {
@@ -2550,16 +3133,19 @@ func (this *StreamRate) Equal(that interface{}) bool {
 	if this.Tenant != that1.Tenant {
 		return false
 	}
+	if this.EntriesRate != that1.EntriesRate {
+		return false
+	}
 	return true
 }
-func (this *QueryRequest) Equal(that interface{}) bool {
+func (this *TenantVolumesRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*QueryRequest)
+	that1, ok := that.(*TenantVolumesRequest)
 	if !ok {
-		that2, ok := that.(QueryRequest)
+		that2, ok := that.(TenantVolumesRequest)
 		if ok {
 			that1 = &that2
 		} else {
@@ -2571,19 +3157,162 @@ func (this *QueryRequest) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	if this.Selector != that1.Selector {
-		return false
-	}
-	if this.Limit != that1.Limit {
-		return false
+	return true
+}
+func (this *TenantVolumesResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if !this.Start.Equal(that1.Start) {
-		return false
+
+	that1, ok := that.(*TenantVolumesResponse)
+	if !ok {
+		that2, ok := that.(TenantVolumesResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	if !this.End.Equal(that1.End) {
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
 		return false
 	}
-	if this.Direction != that1.Direction {
+	if len(this.TenantVolumes) != len(that1.TenantVolumes) {
+		return false
+	}
+	for i := range this.TenantVolumes {
+		if !this.TenantVolumes[i].Equal(that1.TenantVolumes[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *TenantVolume) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TenantVolume)
+	if !ok {
+		that2, ok := that.(TenantVolume)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Tenant != that1.Tenant {
+		return false
+	}
+	if this.Day != that1.Day {
+		return false
+	}
+	if this.DayBytes != that1.DayBytes {
+		return false
+	}
+	if this.Month != that1.Month {
+		return false
+	}
+	if this.MonthBytes != that1.MonthBytes {
+		return false
+	}
+	return true
+}
+func (this *PushStreamRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*PushStreamRequest)
+	if !ok {
+		that2, ok := that.(PushStreamRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.BatchID != that1.BatchID {
+		return false
+	}
+	if !this.Request.Equal(that1.Request) {
+		return false
+	}
+	return true
+}
+func (this *PushStreamResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*PushStreamResponse)
+	if !ok {
+		that2, ok := that.(PushStreamResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.BatchID != that1.BatchID {
+		return false
+	}
+	if this.Code != that1.Code {
+		return false
+	}
+	if this.Error != that1.Error {
+		return false
+	}
+	return true
+}
+func (this *QueryRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryRequest)
+	if !ok {
+		that2, ok := that.(QueryRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Selector != that1.Selector {
+		return false
+	}
+	if this.Limit != that1.Limit {
+		return false
+	}
+	if !this.Start.Equal(that1.Start) {
+		return false
+	}
+	if !this.End.Equal(that1.End) {
+		return false
+	}
+	if this.Direction != that1.Direction {
 		return false
 	}
 	if len(this.Shards) != len(that1.Shards) {
@@ -2940,6 +3669,9 @@ func (this *TailRequest) Equal(that interface{}) bool {
 	if !this.Start.Equal(that1.Start) {
 		return false
 	}
+	if this.Cursor != that1.Cursor {
+		return false
+	}
 	return true
 }
 func (this *TailResponse) Equal(that interface{}) bool {
@@ -3152,6 +3884,69 @@ func (this *TimeSeriesChunk) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *HandoffStreamsRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*HandoffStreamsRequest)
+	if !ok {
+		that2, ok := that.(HandoffStreamsRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.FromIngesterId != that1.FromIngesterId {
+		return false
+	}
+	if this.UserId != that1.UserId {
+		return false
+	}
+	if that1.Stream == nil {
+		if this.Stream != nil {
+			return false
+		}
+	} else if !this.Stream.Equal(*that1.Stream) {
+		return false
+	}
+	if len(this.Chunks) != len(that1.Chunks) {
+		return false
+	}
+	for i := range this.Chunks {
+		if !this.Chunks[i].Equal(that1.Chunks[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *HandoffStreamsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*HandoffStreamsResponse)
+	if !ok {
+		that2, ok := that.(HandoffStreamsResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	return true
+}
 func (this *LabelPair) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -3791,6 +4586,98 @@ func (this *IndexStatsResponse) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *TopStreamsRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TopStreamsRequest)
+	if !ok {
+		that2, ok := that.(TopStreamsRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.SortBy != that1.SortBy {
+		return false
+	}
+	if this.Limit != that1.Limit {
+		return false
+	}
+	return true
+}
+func (this *TopStreamsResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TopStreamsResponse)
+	if !ok {
+		that2, ok := that.(TopStreamsResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Streams) != len(that1.Streams) {
+		return false
+	}
+	for i := range this.Streams {
+		if !this.Streams[i].Equal(that1.Streams[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *TopStream) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TopStream)
+	if !ok {
+		that2, ok := that.(TopStream)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Labels != that1.Labels {
+		return false
+	}
+	if this.BytesRate != that1.BytesRate {
+		return false
+	}
+	if this.EntriesRate != that1.EntriesRate {
+		return false
+	}
+	if this.MemoryBytes != that1.MemoryBytes {
+		return false
+	}
+	if this.Chunks != that1.Chunks {
+		return false
+	}
+	return true
+}
 func (this *StreamRatesRequest) GoString() string {
 	if this == nil {
 		return "nil"
@@ -3816,20 +4703,81 @@ func (this *StreamRate) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
+	s := make([]string, 0, 9)
 	s = append(s, "&logproto.StreamRate{")
 	s = append(s, "StreamHash: "+fmt.Sprintf("%#v", this.StreamHash)+",\n")
 	s = append(s, "StreamHashNoShard: "+fmt.Sprintf("%#v", this.StreamHashNoShard)+",\n")
 	s = append(s, "Rate: "+fmt.Sprintf("%#v", this.Rate)+",\n")
 	s = append(s, "Tenant: "+fmt.Sprintf("%#v", this.Tenant)+",\n")
+	s = append(s, "EntriesRate: "+fmt.Sprintf("%#v", this.EntriesRate)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
-func (this *QueryRequest) GoString() string {
+func (this *TenantVolumesRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 11)
+	s := make([]string, 0, 4)
+	s = append(s, "&logproto.TenantVolumesRequest{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TenantVolumesResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.TenantVolumesResponse{")
+	if this.TenantVolumes != nil {
+		s = append(s, "TenantVolumes: "+fmt.Sprintf("%#v", this.TenantVolumes)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TenantVolume) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&logproto.TenantVolume{")
+	s = append(s, "Tenant: "+fmt.Sprintf("%#v", this.Tenant)+",\n")
+	s = append(s, "Day: "+fmt.Sprintf("%#v", this.Day)+",\n")
+	s = append(s, "DayBytes: "+fmt.Sprintf("%#v", this.DayBytes)+",\n")
+	s = append(s, "Month: "+fmt.Sprintf("%#v", this.Month)+",\n")
+	s = append(s, "MonthBytes: "+fmt.Sprintf("%#v", this.MonthBytes)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PushStreamRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&logproto.PushStreamRequest{")
+	s = append(s, "BatchID: "+fmt.Sprintf("%#v", this.BatchID)+",\n")
+	if this.Request != nil {
+		s = append(s, "Request: "+fmt.Sprintf("%#v", this.Request)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PushStreamResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&logproto.PushStreamResponse{")
+	s = append(s, "BatchID: "+fmt.Sprintf("%#v", this.BatchID)+",\n")
+	s = append(s, "Code: "+fmt.Sprintf("%#v", this.Code)+",\n")
+	s = append(s, "Error: "+fmt.Sprintf("%#v", this.Error)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *QueryRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 11)
 	s = append(s, "&logproto.QueryRequest{")
 	s = append(s, "Selector: "+fmt.Sprintf("%#v", this.Selector)+",\n")
 	s = append(s, "Limit: "+fmt.Sprintf("%#v", this.Limit)+",\n")
@@ -3948,9 +4896,9 @@ func (this *Series) GoString() string {
 	s = append(s, "&logproto.Series{")
 	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
 	if this.Samples != nil {
-		vs := make([]*Sample, len(this.Samples))
+		vs := make([]Sample, len(this.Samples))
 		for i := range vs {
-			vs[i] = &this.Samples[i]
+			vs[i] = this.Samples[i]
 		}
 		s = append(s, "Samples: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
@@ -3962,12 +4910,13 @@ func (this *TailRequest) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
+	s := make([]string, 0, 9)
 	s = append(s, "&logproto.TailRequest{")
 	s = append(s, "Query: "+fmt.Sprintf("%#v", this.Query)+",\n")
 	s = append(s, "DelayFor: "+fmt.Sprintf("%#v", this.DelayFor)+",\n")
 	s = append(s, "Limit: "+fmt.Sprintf("%#v", this.Limit)+",\n")
 	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
+	s = append(s, "Cursor: "+fmt.Sprintf("%#v", this.Cursor)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -4004,9 +4953,9 @@ func (this *SeriesResponse) GoString() string {
 	s := make([]string, 0, 5)
 	s = append(s, "&logproto.SeriesResponse{")
 	if this.Series != nil {
-		vs := make([]*SeriesIdentifier, len(this.Series))
+		vs := make([]SeriesIdentifier, len(this.Series))
 		for i := range vs {
-			vs[i] = &this.Series[i]
+			vs[i] = this.Series[i]
 		}
 		s = append(s, "Series: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
@@ -4064,6 +5013,30 @@ func (this *TimeSeriesChunk) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *HandoffStreamsRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&logproto.HandoffStreamsRequest{")
+	s = append(s, "FromIngesterId: "+fmt.Sprintf("%#v", this.FromIngesterId)+",\n")
+	s = append(s, "UserId: "+fmt.Sprintf("%#v", this.UserId)+",\n")
+	s = append(s, "Stream: "+fmt.Sprintf("%#v", this.Stream)+",\n")
+	if this.Chunks != nil {
+		s = append(s, "Chunks: "+fmt.Sprintf("%#v", this.Chunks)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *HandoffStreamsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 4)
+	s = append(s, "&logproto.HandoffStreamsResponse{")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func (this *LabelPair) GoString() string {
 	if this == nil {
 		return "nil"
@@ -4229,9 +5202,9 @@ func (this *GetSeriesResponse) GoString() string {
 	s := make([]string, 0, 5)
 	s = append(s, "&logproto.GetSeriesResponse{")
 	if this.Series != nil {
-		vs := make([]*IndexSeries, len(this.Series))
+		vs := make([]IndexSeries, len(this.Series))
 		for i := range vs {
-			vs[i] = &this.Series[i]
+			vs[i] = this.Series[i]
 		}
 		s = append(s, "Series: "+fmt.Sprintf("%#v", vs)+",\n")
 	}
@@ -4323,6 +5296,43 @@ func (this *IndexStatsResponse) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *TopStreamsRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&logproto.TopStreamsRequest{")
+	s = append(s, "SortBy: "+fmt.Sprintf("%#v", this.SortBy)+",\n")
+	s = append(s, "Limit: "+fmt.Sprintf("%#v", this.Limit)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TopStreamsResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&logproto.TopStreamsResponse{")
+	if this.Streams != nil {
+		s = append(s, "Streams: "+fmt.Sprintf("%#v", this.Streams)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TopStream) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&logproto.TopStream{")
+	s = append(s, "Labels: "+fmt.Sprintf("%#v", this.Labels)+",\n")
+	s = append(s, "BytesRate: "+fmt.Sprintf("%#v", this.BytesRate)+",\n")
+	s = append(s, "EntriesRate: "+fmt.Sprintf("%#v", this.EntriesRate)+",\n")
+	s = append(s, "MemoryBytes: "+fmt.Sprintf("%#v", this.MemoryBytes)+",\n")
+	s = append(s, "Chunks: "+fmt.Sprintf("%#v", this.Chunks)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringLogproto(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -4354,6 +5364,8 @@ type QuerierClient interface {
 	// Note: this MUST be the same as the variant defined in
 	// indexgateway.proto on the IndexGateway service.
 	GetStats(ctx context.Context, in *IndexStatsRequest, opts ...grpc.CallOption) (*IndexStatsResponse, error)
+	// GetTopStreams returns the streams of a tenant that an ingester holds, ordered by their rates or their memory usage.
+	GetTopStreams(ctx context.Context, in *TopStreamsRequest, opts ...grpc.CallOption) (*TopStreamsResponse, error)
 }
 
 type querierClient struct {
@@ -4505,6 +5517,15 @@ func (c *querierClient) GetStats(ctx context.Context, in *IndexStatsRequest, opt
 	return out, nil
 }
 
+func (c *querierClient) GetTopStreams(ctx context.Context, in *TopStreamsRequest, opts ...grpc.CallOption) (*TopStreamsResponse, error) {
+	out := new(TopStreamsResponse)
+	err := c.cc.Invoke(ctx, "/logproto.Querier/GetTopStreams", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // QuerierServer is the server API for Querier service.
 type QuerierServer interface {
 	Query(*QueryRequest, Querier_QueryServer) error
@@ -4517,6 +5538,8 @@ type QuerierServer interface {
 	// Note: this MUST be the same as the variant defined in
 	// indexgateway.proto on the IndexGateway service.
 	GetStats(context.Context, *IndexStatsRequest) (*IndexStatsResponse, error)
+	// GetTopStreams returns the streams of a tenant that an ingester holds, ordered by their rates or their memory usage.
+	GetTopStreams(context.Context, *TopStreamsRequest) (*TopStreamsResponse, error)
 }
 
 // UnimplementedQuerierServer can be embedded to have forward compatible implementations.
@@ -4547,6 +5570,9 @@ func (*UnimplementedQuerierServer) GetChunkIDs(ctx context.Context, req *GetChun
 func (*UnimplementedQuerierServer) GetStats(ctx context.Context, req *IndexStatsRequest) (*IndexStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
 }
+func (*UnimplementedQuerierServer) GetTopStreams(ctx context.Context, req *TopStreamsRequest) (*TopStreamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTopStreams not implemented")
+}
 
 func RegisterQuerierServer(s *grpc.Server, srv QuerierServer) {
 	s.RegisterService(&_Querier_serviceDesc, srv)
@@ -4705,6 +5731,24 @@ func _Querier_GetStats_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Querier_GetTopStreams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopStreamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QuerierServer).GetTopStreams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/logproto.Querier/GetTopStreams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QuerierServer).GetTopStreams(ctx, req.(*TopStreamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Querier_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "logproto.Querier",
 	HandlerType: (*QuerierServer)(nil),
@@ -4729,6 +5773,10 @@ var _Querier_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetStats",
 			Handler:    _Querier_GetStats_Handler,
 		},
+		{
+			MethodName: "GetTopStreams",
+			Handler:    _Querier_GetTopStreams_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -4755,6 +5803,9 @@ var _Querier_serviceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type IngesterClient interface {
 	TransferChunks(ctx context.Context, opts ...grpc.CallOption) (Ingester_TransferChunksClient, error)
+	// HandoffStreams receives the streams of a leaving ingester that this ingester
+	// owns once it left the ring.
+	HandoffStreams(ctx context.Context, opts ...grpc.CallOption) (Ingester_HandoffStreamsClient, error)
 }
 
 type ingesterClient struct {
@@ -4799,9 +5850,46 @@ func (x *ingesterTransferChunksClient) CloseAndRecv() (*TransferChunksResponse,
 	return m, nil
 }
 
+func (c *ingesterClient) HandoffStreams(ctx context.Context, opts ...grpc.CallOption) (Ingester_HandoffStreamsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Ingester_serviceDesc.Streams[1], "/logproto.Ingester/HandoffStreams", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &ingesterHandoffStreamsClient{stream}
+	return x, nil
+}
+
+type Ingester_HandoffStreamsClient interface {
+	Send(*HandoffStreamsRequest) error
+	CloseAndRecv() (*HandoffStreamsResponse, error)
+	grpc.ClientStream
+}
+
+type ingesterHandoffStreamsClient struct {
+	grpc.ClientStream
+}
+
+func (x *ingesterHandoffStreamsClient) Send(m *HandoffStreamsRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *ingesterHandoffStreamsClient) CloseAndRecv() (*HandoffStreamsResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(HandoffStreamsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // IngesterServer is the server API for Ingester service.
 type IngesterServer interface {
 	TransferChunks(Ingester_TransferChunksServer) error
+	// HandoffStreams receives the streams of a leaving ingester that this ingester
+	// owns once it left the ring.
+	HandoffStreams(Ingester_HandoffStreamsServer) error
 }
 
 // UnimplementedIngesterServer can be embedded to have forward compatible implementations.
@@ -4811,6 +5899,9 @@ type UnimplementedIngesterServer struct {
 func (*UnimplementedIngesterServer) TransferChunks(srv Ingester_TransferChunksServer) error {
 	return status.Errorf(codes.Unimplemented, "method TransferChunks not implemented")
 }
+func (*UnimplementedIngesterServer) HandoffStreams(srv Ingester_HandoffStreamsServer) error {
+	return status.Errorf(codes.Unimplemented, "method HandoffStreams not implemented")
+}
 
 func RegisterIngesterServer(s *grpc.Server, srv IngesterServer) {
 	s.RegisterService(&_Ingester_serviceDesc, srv)
@@ -4842,6 +5933,32 @@ func (x *ingesterTransferChunksServer) Recv() (*TimeSeriesChunk, error) {
 	return m, nil
 }
 
+func _Ingester_HandoffStreams_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IngesterServer).HandoffStreams(&ingesterHandoffStreamsServer{stream})
+}
+
+type Ingester_HandoffStreamsServer interface {
+	SendAndClose(*HandoffStreamsResponse) error
+	Recv() (*HandoffStreamsRequest, error)
+	grpc.ServerStream
+}
+
+type ingesterHandoffStreamsServer struct {
+	grpc.ServerStream
+}
+
+func (x *ingesterHandoffStreamsServer) SendAndClose(m *HandoffStreamsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *ingesterHandoffStreamsServer) Recv() (*HandoffStreamsRequest, error) {
+	m := new(HandoffStreamsRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Ingester_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "logproto.Ingester",
 	HandlerType: (*IngesterServer)(nil),
@@ -4852,6 +5969,11 @@ var _Ingester_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Ingester_TransferChunks_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "HandoffStreams",
+			Handler:       _Ingester_HandoffStreams_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "pkg/logproto/logproto.proto",
 }
@@ -4928,191 +6050,214 @@ var _StreamData_serviceDesc = grpc.ServiceDesc{
 	Metadata: "pkg/logproto/logproto.proto",
 }
 
-func (m *StreamRatesRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+// TenantVolumesClient is the client API for TenantVolumes service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TenantVolumesClient interface {
+	// GetTenantVolumes returns the bytes that each tenant pushed to the ingester
+	// in the current day and month.
+	GetTenantVolumes(ctx context.Context, in *TenantVolumesRequest, opts ...grpc.CallOption) (*TenantVolumesResponse, error)
 }
 
-func (m *StreamRatesRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+type tenantVolumesClient struct {
+	cc *grpc.ClientConn
 }
 
-func (m *StreamRatesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	return len(dAtA) - i, nil
+func NewTenantVolumesClient(cc *grpc.ClientConn) TenantVolumesClient {
+	return &tenantVolumesClient{cc}
 }
 
-func (m *StreamRatesResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+func (c *tenantVolumesClient) GetTenantVolumes(ctx context.Context, in *TenantVolumesRequest, opts ...grpc.CallOption) (*TenantVolumesResponse, error) {
+	out := new(TenantVolumesResponse)
+	err := c.cc.Invoke(ctx, "/logproto.TenantVolumes/GetTenantVolumes", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return dAtA[:n], nil
+	return out, nil
 }
 
-func (m *StreamRatesResponse) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+// TenantVolumesServer is the server API for TenantVolumes service.
+type TenantVolumesServer interface {
+	// GetTenantVolumes returns the bytes that each tenant pushed to the ingester
+	// in the current day and month.
+	GetTenantVolumes(context.Context, *TenantVolumesRequest) (*TenantVolumesResponse, error)
 }
 
-func (m *StreamRatesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.StreamRates) > 0 {
-		for iNdEx := len(m.StreamRates) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.StreamRates[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
+// UnimplementedTenantVolumesServer can be embedded to have forward compatible implementations.
+type UnimplementedTenantVolumesServer struct {
 }
 
-func (m *StreamRate) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
+func (*UnimplementedTenantVolumesServer) GetTenantVolumes(ctx context.Context, req *TenantVolumesRequest) (*TenantVolumesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTenantVolumes not implemented")
 }
 
-func (m *StreamRate) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
+func RegisterTenantVolumesServer(s *grpc.Server, srv TenantVolumesServer) {
+	s.RegisterService(&_TenantVolumes_serviceDesc, srv)
 }
 
-func (m *StreamRate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Tenant) > 0 {
-		i -= len(m.Tenant)
-		copy(dAtA[i:], m.Tenant)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Tenant)))
-		i--
-		dAtA[i] = 0x22
-	}
-	if m.Rate != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Rate))
-		i--
-		dAtA[i] = 0x18
+func _TenantVolumes_GetTenantVolumes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TenantVolumesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	if m.StreamHashNoShard != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.StreamHashNoShard))
-		i--
-		dAtA[i] = 0x10
+	if interceptor == nil {
+		return srv.(TenantVolumesServer).GetTenantVolumes(ctx, in)
 	}
-	if m.StreamHash != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.StreamHash))
-		i--
-		dAtA[i] = 0x8
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/logproto.TenantVolumes/GetTenantVolumes",
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TenantVolumesServer).GetTenantVolumes(ctx, req.(*TenantVolumesRequest))
 	}
-	return dAtA[:n], nil
+	return interceptor(ctx, in, info, handler)
 }
 
-func (m *QueryRequest) MarshalTo(dAtA []byte) (int, error) {
+var _TenantVolumes_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "logproto.TenantVolumes",
+	HandlerType: (*TenantVolumesServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTenantVolumes",
+			Handler:    _TenantVolumes_GetTenantVolumes_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/logproto/logproto.proto",
+}
+
+// StreamPusherClient is the client API for StreamPusher service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type StreamPusherClient interface {
+	// PushStream pushes batches of streams over a long-lived stream. Every batch is
+	// acknowledged with a response carrying its batchID, not necessarily in order.
+	PushStream(ctx context.Context, opts ...grpc.CallOption) (StreamPusher_PushStreamClient, error)
+}
+
+type streamPusherClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewStreamPusherClient(cc *grpc.ClientConn) StreamPusherClient {
+	return &streamPusherClient{cc}
+}
+
+func (c *streamPusherClient) PushStream(ctx context.Context, opts ...grpc.CallOption) (StreamPusher_PushStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_StreamPusher_serviceDesc.Streams[0], "/logproto.StreamPusher/PushStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &streamPusherPushStreamClient{stream}
+	return x, nil
+}
+
+type StreamPusher_PushStreamClient interface {
+	Send(*PushStreamRequest) error
+	Recv() (*PushStreamResponse, error)
+	grpc.ClientStream
+}
+
+type streamPusherPushStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *streamPusherPushStreamClient) Send(m *PushStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *streamPusherPushStreamClient) Recv() (*PushStreamResponse, error) {
+	m := new(PushStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// StreamPusherServer is the server API for StreamPusher service.
+type StreamPusherServer interface {
+	// PushStream pushes batches of streams over a long-lived stream. Every batch is
+	// acknowledged with a response carrying its batchID, not necessarily in order.
+	PushStream(StreamPusher_PushStreamServer) error
+}
+
+// UnimplementedStreamPusherServer can be embedded to have forward compatible implementations.
+type UnimplementedStreamPusherServer struct {
+}
+
+func (*UnimplementedStreamPusherServer) PushStream(srv StreamPusher_PushStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method PushStream not implemented")
+}
+
+func RegisterStreamPusherServer(s *grpc.Server, srv StreamPusherServer) {
+	s.RegisterService(&_StreamPusher_serviceDesc, srv)
+}
+
+func _StreamPusher_PushStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StreamPusherServer).PushStream(&streamPusherPushStreamServer{stream})
+}
+
+type StreamPusher_PushStreamServer interface {
+	Send(*PushStreamResponse) error
+	Recv() (*PushStreamRequest, error)
+	grpc.ServerStream
+}
+
+type streamPusherPushStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *streamPusherPushStreamServer) Send(m *PushStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *streamPusherPushStreamServer) Recv() (*PushStreamRequest, error) {
+	m := new(PushStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _StreamPusher_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "logproto.StreamPusher",
+	HandlerType: (*StreamPusherServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PushStream",
+			Handler:       _StreamPusher_PushStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/logproto/logproto.proto",
+}
+
+func (m *StreamRatesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StreamRatesRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *QueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *StreamRatesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Deletes) > 0 {
-		for iNdEx := len(m.Deletes) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Deletes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x42
-		}
-	}
-	if len(m.Shards) > 0 {
-		for iNdEx := len(m.Shards) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Shards[iNdEx])
-			copy(dAtA[i:], m.Shards[iNdEx])
-			i = encodeVarintLogproto(dAtA, i, uint64(len(m.Shards[iNdEx])))
-			i--
-			dAtA[i] = 0x3a
-		}
-	}
-	if m.Direction != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Direction))
-		i--
-		dAtA[i] = 0x28
-	}
-	n1, err1 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
-	if err1 != nil {
-		return 0, err1
-	}
-	i -= n1
-	i = encodeVarintLogproto(dAtA, i, uint64(n1))
-	i--
-	dAtA[i] = 0x22
-	n2, err2 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
-	if err2 != nil {
-		return 0, err2
-	}
-	i -= n2
-	i = encodeVarintLogproto(dAtA, i, uint64(n2))
-	i--
-	dAtA[i] = 0x1a
-	if m.Limit != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Limit))
-		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.Selector) > 0 {
-		i -= len(m.Selector)
-		copy(dAtA[i:], m.Selector)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Selector)))
-		i--
-		dAtA[i] = 0xa
-	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SampleQueryRequest) Marshal() (dAtA []byte, err error) {
+func (m *StreamRatesResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5122,20 +6267,20 @@ func (m *SampleQueryRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SampleQueryRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *StreamRatesResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SampleQueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *StreamRatesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Deletes) > 0 {
-		for iNdEx := len(m.Deletes) - 1; iNdEx >= 0; iNdEx-- {
+	if len(m.StreamRates) > 0 {
+		for iNdEx := len(m.StreamRates) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.Deletes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.StreamRates[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -5143,45 +6288,13 @@ func (m *SampleQueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 				i = encodeVarintLogproto(dAtA, i, uint64(size))
 			}
 			i--
-			dAtA[i] = 0x2a
-		}
-	}
-	if len(m.Shards) > 0 {
-		for iNdEx := len(m.Shards) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Shards[iNdEx])
-			copy(dAtA[i:], m.Shards[iNdEx])
-			i = encodeVarintLogproto(dAtA, i, uint64(len(m.Shards[iNdEx])))
-			i--
-			dAtA[i] = 0x22
+			dAtA[i] = 0xa
 		}
 	}
-	n3, err3 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
-	if err3 != nil {
-		return 0, err3
-	}
-	i -= n3
-	i = encodeVarintLogproto(dAtA, i, uint64(n3))
-	i--
-	dAtA[i] = 0x1a
-	n4, err4 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
-	if err4 != nil {
-		return 0, err4
-	}
-	i -= n4
-	i = encodeVarintLogproto(dAtA, i, uint64(n4))
-	i--
-	dAtA[i] = 0x12
-	if len(m.Selector) > 0 {
-		i -= len(m.Selector)
-		copy(dAtA[i:], m.Selector)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Selector)))
-		i--
-		dAtA[i] = 0xa
-	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Delete) Marshal() (dAtA []byte, err error) {
+func (m *StreamRate) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5191,37 +6304,47 @@ func (m *Delete) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Delete) MarshalTo(dAtA []byte) (int, error) {
+func (m *StreamRate) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Delete) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *StreamRate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.End != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.End))
+	if m.EntriesRate != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.EntriesRate))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Tenant) > 0 {
+		i -= len(m.Tenant)
+		copy(dAtA[i:], m.Tenant)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Tenant)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Rate != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Rate))
 		i--
 		dAtA[i] = 0x18
 	}
-	if m.Start != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Start))
+	if m.StreamHashNoShard != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.StreamHashNoShard))
 		i--
 		dAtA[i] = 0x10
 	}
-	if len(m.Selector) > 0 {
-		i -= len(m.Selector)
-		copy(dAtA[i:], m.Selector)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Selector)))
+	if m.StreamHash != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.StreamHash))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *QueryResponse) Marshal() (dAtA []byte, err error) {
+func (m *TenantVolumesRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5231,34 +6354,47 @@ func (m *QueryResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *QueryResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *TenantVolumesRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *QueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TenantVolumesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	{
-		size, err := m.Stats.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintLogproto(dAtA, i, uint64(size))
+	return len(dAtA) - i, nil
+}
+
+func (m *TenantVolumesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	i--
-	dAtA[i] = 0x12
-	if len(m.Streams) > 0 {
-		for iNdEx := len(m.Streams) - 1; iNdEx >= 0; iNdEx-- {
+	return dAtA[:n], nil
+}
+
+func (m *TenantVolumesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TenantVolumesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.TenantVolumes) > 0 {
+		for iNdEx := len(m.TenantVolumes) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size := m.Streams[iNdEx].Size()
-				i -= size
-				if _, err := m.Streams[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+				size, err := m.TenantVolumes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
 					return 0, err
 				}
+				i -= size
 				i = encodeVarintLogproto(dAtA, i, uint64(size))
 			}
 			i--
@@ -5268,7 +6404,7 @@ func (m *QueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *SampleQueryResponse) Marshal() (dAtA []byte, err error) {
+func (m *TenantVolume) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5278,111 +6414,47 @@ func (m *SampleQueryResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SampleQueryResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *TenantVolume) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SampleQueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TenantVolume) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	{
-		size, err := m.Stats.MarshalToSizedBuffer(dAtA[:i])
-		if err != nil {
-			return 0, err
-		}
-		i -= size
-		i = encodeVarintLogproto(dAtA, i, uint64(size))
-	}
-	i--
-	dAtA[i] = 0x12
-	if len(m.Series) > 0 {
-		for iNdEx := len(m.Series) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size := m.Series[iNdEx].Size()
-				i -= size
-				if _, err := m.Series[iNdEx].MarshalTo(dAtA[i:]); err != nil {
-					return 0, err
-				}
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
-	return len(dAtA) - i, nil
-}
-
-func (m *LabelRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
-	}
-	return dAtA[:n], nil
-}
-
-func (m *LabelRequest) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *LabelRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Query)))
+	if m.MonthBytes != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.MonthBytes))
 		i--
-		dAtA[i] = 0x2a
+		dAtA[i] = 0x28
 	}
-	if m.End != nil {
-		n7, err7 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.End):])
-		if err7 != nil {
-			return 0, err7
-		}
-		i -= n7
-		i = encodeVarintLogproto(dAtA, i, uint64(n7))
+	if m.Month != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Month))
 		i--
-		dAtA[i] = 0x22
+		dAtA[i] = 0x20
 	}
-	if m.Start != nil {
-		n8, err8 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.Start):])
-		if err8 != nil {
-			return 0, err8
-		}
-		i -= n8
-		i = encodeVarintLogproto(dAtA, i, uint64(n8))
+	if m.DayBytes != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.DayBytes))
 		i--
-		dAtA[i] = 0x1a
+		dAtA[i] = 0x18
 	}
-	if m.Values {
-		i--
-		if m.Values {
-			dAtA[i] = 1
-		} else {
-			dAtA[i] = 0
-		}
+	if m.Day != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Day))
 		i--
 		dAtA[i] = 0x10
 	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Name)))
+	if len(m.Tenant) > 0 {
+		i -= len(m.Tenant)
+		copy(dAtA[i:], m.Tenant)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Tenant)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *LabelResponse) Marshal() (dAtA []byte, err error) {
+func (m *PushStreamRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5392,29 +6464,37 @@ func (m *LabelResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *LabelResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *PushStreamRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *LabelResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *PushStreamRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Values) > 0 {
-		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Values[iNdEx])
-			copy(dAtA[i:], m.Values[iNdEx])
-			i = encodeVarintLogproto(dAtA, i, uint64(len(m.Values[iNdEx])))
-			i--
-			dAtA[i] = 0xa
+	if m.Request != nil {
+		{
+			size, err := m.Request.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintLogproto(dAtA, i, uint64(size))
 		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.BatchID != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.BatchID))
+		i--
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Sample) Marshal() (dAtA []byte, err error) {
+func (m *PushStreamResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5424,36 +6504,37 @@ func (m *Sample) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Sample) MarshalTo(dAtA []byte) (int, error) {
+func (m *PushStreamResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Sample) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *PushStreamResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Hash != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Hash))
+	if len(m.Error) > 0 {
+		i -= len(m.Error)
+		copy(dAtA[i:], m.Error)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Error)))
 		i--
-		dAtA[i] = 0x18
+		dAtA[i] = 0x1a
 	}
-	if m.Value != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+	if m.Code != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Code))
 		i--
-		dAtA[i] = 0x11
+		dAtA[i] = 0x10
 	}
-	if m.Timestamp != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Timestamp))
+	if m.BatchID != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.BatchID))
 		i--
 		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *LegacySample) Marshal() (dAtA []byte, err error) {
+func (m *QueryRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5463,31 +6544,76 @@ func (m *LegacySample) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *LegacySample) MarshalTo(dAtA []byte) (int, error) {
+func (m *QueryRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *LegacySample) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *QueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.TimestampMs != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.TimestampMs))
+	if len(m.Deletes) > 0 {
+		for iNdEx := len(m.Deletes) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Deletes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.Shards) > 0 {
+		for iNdEx := len(m.Shards) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Shards[iNdEx])
+			copy(dAtA[i:], m.Shards[iNdEx])
+			i = encodeVarintLogproto(dAtA, i, uint64(len(m.Shards[iNdEx])))
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.Direction != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Direction))
+		i--
+		dAtA[i] = 0x28
+	}
+	n2, err2 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
+	if err2 != nil {
+		return 0, err2
+	}
+	i -= n2
+	i = encodeVarintLogproto(dAtA, i, uint64(n2))
+	i--
+	dAtA[i] = 0x22
+	n3, err3 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err3 != nil {
+		return 0, err3
+	}
+	i -= n3
+	i = encodeVarintLogproto(dAtA, i, uint64(n3))
+	i--
+	dAtA[i] = 0x1a
+	if m.Limit != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Limit))
 		i--
 		dAtA[i] = 0x10
 	}
-	if m.Value != 0 {
-		i -= 8
-		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+	if len(m.Selector) > 0 {
+		i -= len(m.Selector)
+		copy(dAtA[i:], m.Selector)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Selector)))
 		i--
-		dAtA[i] = 0x9
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Series) Marshal() (dAtA []byte, err error) {
+func (m *SampleQueryRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5497,25 +6623,20 @@ func (m *Series) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Series) MarshalTo(dAtA []byte) (int, error) {
+func (m *SampleQueryRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Series) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SampleQueryRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.StreamHash != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.StreamHash))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.Samples) > 0 {
-		for iNdEx := len(m.Samples) - 1; iNdEx >= 0; iNdEx-- {
+	if len(m.Deletes) > 0 {
+		for iNdEx := len(m.Deletes) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.Samples[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.Deletes[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -5523,20 +6644,45 @@ func (m *Series) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 				i = encodeVarintLogproto(dAtA, i, uint64(size))
 			}
 			i--
-			dAtA[i] = 0x12
+			dAtA[i] = 0x2a
 		}
 	}
-	if len(m.Labels) > 0 {
-		i -= len(m.Labels)
-		copy(dAtA[i:], m.Labels)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Labels)))
+	if len(m.Shards) > 0 {
+		for iNdEx := len(m.Shards) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Shards[iNdEx])
+			copy(dAtA[i:], m.Shards[iNdEx])
+			i = encodeVarintLogproto(dAtA, i, uint64(len(m.Shards[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	n4, err4 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
+	if err4 != nil {
+		return 0, err4
+	}
+	i -= n4
+	i = encodeVarintLogproto(dAtA, i, uint64(n4))
+	i--
+	dAtA[i] = 0x1a
+	n5, err5 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err5 != nil {
+		return 0, err5
+	}
+	i -= n5
+	i = encodeVarintLogproto(dAtA, i, uint64(n5))
+	i--
+	dAtA[i] = 0x12
+	if len(m.Selector) > 0 {
+		i -= len(m.Selector)
+		copy(dAtA[i:], m.Selector)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Selector)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *TailRequest) Marshal() (dAtA []byte, err error) {
+func (m *Delete) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5546,45 +6692,37 @@ func (m *TailRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TailRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *Delete) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TailRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Delete) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	n9, err9 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
-	if err9 != nil {
-		return 0, err9
-	}
-	i -= n9
-	i = encodeVarintLogproto(dAtA, i, uint64(n9))
-	i--
-	dAtA[i] = 0x2a
-	if m.Limit != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Limit))
+	if m.End != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.End))
 		i--
-		dAtA[i] = 0x20
+		dAtA[i] = 0x18
 	}
-	if m.DelayFor != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.DelayFor))
+	if m.Start != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Start))
 		i--
-		dAtA[i] = 0x18
+		dAtA[i] = 0x10
 	}
-	if len(m.Query) > 0 {
-		i -= len(m.Query)
-		copy(dAtA[i:], m.Query)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Query)))
+	if len(m.Selector) > 0 {
+		i -= len(m.Selector)
+		copy(dAtA[i:], m.Selector)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Selector)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *TailResponse) Marshal() (dAtA []byte, err error) {
+func (m *QueryResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5594,46 +6732,44 @@ func (m *TailResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TailResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *QueryResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TailResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *QueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.DroppedStreams) > 0 {
-		for iNdEx := len(m.DroppedStreams) - 1; iNdEx >= 0; iNdEx-- {
+	{
+		size, err := m.Stats.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
+		}
+		i -= size
+		i = encodeVarintLogproto(dAtA, i, uint64(size))
+	}
+	i--
+	dAtA[i] = 0x12
+	if len(m.Streams) > 0 {
+		for iNdEx := len(m.Streams) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.DroppedStreams[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
+				size := m.Streams[iNdEx].Size()
+				i -= size
+				if _, err := m.Streams[iNdEx].MarshalTo(dAtA[i:]); err != nil {
 					return 0, err
 				}
-				i -= size
 				i = encodeVarintLogproto(dAtA, i, uint64(size))
 			}
 			i--
-			dAtA[i] = 0x12
-		}
-	}
-	if m.Stream != nil {
-		{
-			size := m.Stream.Size()
-			i -= size
-			if _, err := m.Stream.MarshalTo(dAtA[i:]); err != nil {
-				return 0, err
-			}
-			i = encodeVarintLogproto(dAtA, i, uint64(size))
+			dAtA[i] = 0xa
 		}
-		i--
-		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SeriesRequest) Marshal() (dAtA []byte, err error) {
+func (m *SampleQueryResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5643,54 +6779,44 @@ func (m *SeriesRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SeriesRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *SampleQueryResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SeriesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SampleQueryResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Shards) > 0 {
-		for iNdEx := len(m.Shards) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Shards[iNdEx])
-			copy(dAtA[i:], m.Shards[iNdEx])
-			i = encodeVarintLogproto(dAtA, i, uint64(len(m.Shards[iNdEx])))
-			i--
-			dAtA[i] = 0x22
-		}
-	}
-	if len(m.Groups) > 0 {
-		for iNdEx := len(m.Groups) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Groups[iNdEx])
-			copy(dAtA[i:], m.Groups[iNdEx])
-			i = encodeVarintLogproto(dAtA, i, uint64(len(m.Groups[iNdEx])))
-			i--
-			dAtA[i] = 0x1a
+	{
+		size, err := m.Stats.MarshalToSizedBuffer(dAtA[:i])
+		if err != nil {
+			return 0, err
 		}
+		i -= size
+		i = encodeVarintLogproto(dAtA, i, uint64(size))
 	}
-	n11, err11 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
-	if err11 != nil {
-		return 0, err11
-	}
-	i -= n11
-	i = encodeVarintLogproto(dAtA, i, uint64(n11))
 	i--
 	dAtA[i] = 0x12
-	n12, err12 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
-	if err12 != nil {
-		return 0, err12
+	if len(m.Series) > 0 {
+		for iNdEx := len(m.Series) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Series[iNdEx].Size()
+				i -= size
+				if _, err := m.Series[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	i -= n12
-	i = encodeVarintLogproto(dAtA, i, uint64(n12))
-	i--
-	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func (m *SeriesResponse) Marshal() (dAtA []byte, err error) {
+func (m *LabelRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5700,34 +6826,64 @@ func (m *SeriesResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SeriesResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *LabelRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SeriesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *LabelRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Series) > 0 {
-		for iNdEx := len(m.Series) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Series[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Query)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.End != nil {
+		n8, err8 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.End):])
+		if err8 != nil {
+			return 0, err8
+		}
+		i -= n8
+		i = encodeVarintLogproto(dAtA, i, uint64(n8))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Start != nil {
+		n9, err9 := github_com_gogo_protobuf_types.StdTimeMarshalTo(*m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(*m.Start):])
+		if err9 != nil {
+			return 0, err9
+		}
+		i -= n9
+		i = encodeVarintLogproto(dAtA, i, uint64(n9))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if m.Values {
+		i--
+		if m.Values {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
 		}
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *SeriesIdentifier) Marshal() (dAtA []byte, err error) {
+func (m *LabelResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5737,31 +6893,21 @@ func (m *SeriesIdentifier) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *SeriesIdentifier) MarshalTo(dAtA []byte) (int, error) {
+func (m *LabelResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SeriesIdentifier) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *LabelResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Labels) > 0 {
-		for k := range m.Labels {
-			v := m.Labels[k]
-			baseI := i
-			i -= len(v)
-			copy(dAtA[i:], v)
-			i = encodeVarintLogproto(dAtA, i, uint64(len(v)))
-			i--
-			dAtA[i] = 0x12
-			i -= len(k)
-			copy(dAtA[i:], k)
-			i = encodeVarintLogproto(dAtA, i, uint64(len(k)))
-			i--
-			dAtA[i] = 0xa
-			i = encodeVarintLogproto(dAtA, i, uint64(baseI-i))
+	if len(m.Values) > 0 {
+		for iNdEx := len(m.Values) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Values[iNdEx])
+			copy(dAtA[i:], m.Values[iNdEx])
+			i = encodeVarintLogproto(dAtA, i, uint64(len(m.Values[iNdEx])))
 			i--
 			dAtA[i] = 0xa
 		}
@@ -5769,7 +6915,7 @@ func (m *SeriesIdentifier) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *DroppedStream) Marshal() (dAtA []byte, err error) {
+func (m *Sample) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5779,43 +6925,36 @@ func (m *DroppedStream) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *DroppedStream) MarshalTo(dAtA []byte) (int, error) {
+func (m *Sample) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *DroppedStream) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Sample) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Labels) > 0 {
-		i -= len(m.Labels)
-		copy(dAtA[i:], m.Labels)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Labels)))
+	if m.Hash != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Hash))
 		i--
-		dAtA[i] = 0x1a
+		dAtA[i] = 0x18
 	}
-	n13, err13 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.To, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.To):])
-	if err13 != nil {
-		return 0, err13
+	if m.Value != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
+		i--
+		dAtA[i] = 0x11
 	}
-	i -= n13
-	i = encodeVarintLogproto(dAtA, i, uint64(n13))
-	i--
-	dAtA[i] = 0x12
-	n14, err14 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.From, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.From):])
-	if err14 != nil {
-		return 0, err14
+	if m.Timestamp != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x8
 	}
-	i -= n14
-	i = encodeVarintLogproto(dAtA, i, uint64(n14))
-	i--
-	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func (m *TimeSeriesChunk) Marshal() (dAtA []byte, err error) {
+func (m *LegacySample) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5825,62 +6964,31 @@ func (m *TimeSeriesChunk) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TimeSeriesChunk) MarshalTo(dAtA []byte) (int, error) {
+func (m *LegacySample) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TimeSeriesChunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *LegacySample) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Chunks) > 0 {
-		for iNdEx := len(m.Chunks) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Chunks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x22
-		}
-	}
-	if len(m.Labels) > 0 {
-		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Labels[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x1a
-		}
-	}
-	if len(m.UserId) > 0 {
-		i -= len(m.UserId)
-		copy(dAtA[i:], m.UserId)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.UserId)))
+	if m.TimestampMs != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.TimestampMs))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x10
 	}
-	if len(m.FromIngesterId) > 0 {
-		i -= len(m.FromIngesterId)
-		copy(dAtA[i:], m.FromIngesterId)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.FromIngesterId)))
+	if m.Value != 0 {
+		i -= 8
+		encoding_binary.LittleEndian.PutUint64(dAtA[i:], uint64(math.Float64bits(float64(m.Value))))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x9
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *LabelPair) Marshal() (dAtA []byte, err error) {
+func (m *Series) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5890,34 +6998,46 @@ func (m *LabelPair) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *LabelPair) MarshalTo(dAtA []byte) (int, error) {
+func (m *Series) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *LabelPair) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Series) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Value) > 0 {
-		i -= len(m.Value)
-		copy(dAtA[i:], m.Value)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Value)))
+	if m.StreamHash != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.StreamHash))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x18
 	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Name)))
+	if len(m.Samples) > 0 {
+		for iNdEx := len(m.Samples) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Samples[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.Labels) > 0 {
+		i -= len(m.Labels)
+		copy(dAtA[i:], m.Labels)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Labels)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *LegacyLabelPair) Marshal() (dAtA []byte, err error) {
+func (m *TailRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5927,34 +7047,52 @@ func (m *LegacyLabelPair) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *LegacyLabelPair) MarshalTo(dAtA []byte) (int, error) {
+func (m *TailRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *LegacyLabelPair) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TailRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Value) > 0 {
-		i -= len(m.Value)
-		copy(dAtA[i:], m.Value)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Value)))
+	if len(m.Cursor) > 0 {
+		i -= len(m.Cursor)
+		copy(dAtA[i:], m.Cursor)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Cursor)))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x32
 	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Name)))
+	n10, err10 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err10 != nil {
+		return 0, err10
+	}
+	i -= n10
+	i = encodeVarintLogproto(dAtA, i, uint64(n10))
+	i--
+	dAtA[i] = 0x2a
+	if m.Limit != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.DelayFor != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.DelayFor))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Query) > 0 {
+		i -= len(m.Query)
+		copy(dAtA[i:], m.Query)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Query)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Chunk) Marshal() (dAtA []byte, err error) {
+func (m *TailResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5964,27 +7102,46 @@ func (m *Chunk) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Chunk) MarshalTo(dAtA []byte) (int, error) {
+func (m *TailResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Chunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TailResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Data) > 0 {
-		i -= len(m.Data)
-		copy(dAtA[i:], m.Data)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Data)))
+	if len(m.DroppedStreams) > 0 {
+		for iNdEx := len(m.DroppedStreams) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.DroppedStreams[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if m.Stream != nil {
+		{
+			size := m.Stream.Size()
+			i -= size
+			if _, err := m.Stream.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintLogproto(dAtA, i, uint64(size))
+		}
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *TransferChunksResponse) Marshal() (dAtA []byte, err error) {
+func (m *SeriesRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -5994,20 +7151,54 @@ func (m *TransferChunksResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TransferChunksResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *SeriesRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TransferChunksResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SeriesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
+	if len(m.Shards) > 0 {
+		for iNdEx := len(m.Shards) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Shards[iNdEx])
+			copy(dAtA[i:], m.Shards[iNdEx])
+			i = encodeVarintLogproto(dAtA, i, uint64(len(m.Shards[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.Groups) > 0 {
+		for iNdEx := len(m.Groups) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Groups[iNdEx])
+			copy(dAtA[i:], m.Groups[iNdEx])
+			i = encodeVarintLogproto(dAtA, i, uint64(len(m.Groups[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	n12, err12 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
+	if err12 != nil {
+		return 0, err12
+	}
+	i -= n12
+	i = encodeVarintLogproto(dAtA, i, uint64(n12))
+	i--
+	dAtA[i] = 0x12
+	n13, err13 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err13 != nil {
+		return 0, err13
+	}
+	i -= n13
+	i = encodeVarintLogproto(dAtA, i, uint64(n13))
+	i--
+	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func (m *TailersCountRequest) Marshal() (dAtA []byte, err error) {
+func (m *SeriesResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6017,20 +7208,34 @@ func (m *TailersCountRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TailersCountRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *SeriesResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TailersCountRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SeriesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
+	if len(m.Series) > 0 {
+		for iNdEx := len(m.Series) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Series[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
 	return len(dAtA) - i, nil
 }
 
-func (m *TailersCountResponse) Marshal() (dAtA []byte, err error) {
+func (m *SeriesIdentifier) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6040,25 +7245,39 @@ func (m *TailersCountResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *TailersCountResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *SeriesIdentifier) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *TailersCountResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SeriesIdentifier) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Count != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Count))
-		i--
-		dAtA[i] = 0x8
+	if len(m.Labels) > 0 {
+		for k := range m.Labels {
+			v := m.Labels[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintLogproto(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintLogproto(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintLogproto(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0xa
+		}
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *GetChunkIDsRequest) Marshal() (dAtA []byte, err error) {
+func (m *DroppedStream) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6068,43 +7287,43 @@ func (m *GetChunkIDsRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GetChunkIDsRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *DroppedStream) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *GetChunkIDsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *DroppedStream) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	n15, err15 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
+	if len(m.Labels) > 0 {
+		i -= len(m.Labels)
+		copy(dAtA[i:], m.Labels)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Labels)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	n14, err14 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.To, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.To):])
+	if err14 != nil {
+		return 0, err14
+	}
+	i -= n14
+	i = encodeVarintLogproto(dAtA, i, uint64(n14))
+	i--
+	dAtA[i] = 0x12
+	n15, err15 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.From, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.From):])
 	if err15 != nil {
 		return 0, err15
 	}
 	i -= n15
 	i = encodeVarintLogproto(dAtA, i, uint64(n15))
 	i--
-	dAtA[i] = 0x1a
-	n16, err16 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
-	if err16 != nil {
-		return 0, err16
-	}
-	i -= n16
-	i = encodeVarintLogproto(dAtA, i, uint64(n16))
-	i--
-	dAtA[i] = 0x12
-	if len(m.Matchers) > 0 {
-		i -= len(m.Matchers)
-		copy(dAtA[i:], m.Matchers)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Matchers)))
-		i--
-		dAtA[i] = 0xa
-	}
+	dAtA[i] = 0xa
 	return len(dAtA) - i, nil
 }
 
-func (m *GetChunkIDsResponse) Marshal() (dAtA []byte, err error) {
+func (m *TimeSeriesChunk) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6114,79 +7333,62 @@ func (m *GetChunkIDsResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GetChunkIDsResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *TimeSeriesChunk) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *GetChunkIDsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TimeSeriesChunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.ChunkIDs) > 0 {
-		for iNdEx := len(m.ChunkIDs) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.ChunkIDs[iNdEx])
-			copy(dAtA[i:], m.ChunkIDs[iNdEx])
-			i = encodeVarintLogproto(dAtA, i, uint64(len(m.ChunkIDs[iNdEx])))
+	if len(m.Chunks) > 0 {
+		for iNdEx := len(m.Chunks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Chunks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
 			i--
-			dAtA[i] = 0xa
+			dAtA[i] = 0x22
 		}
 	}
-	return len(dAtA) - i, nil
-}
-
-func (m *ChunkRef) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalToSizedBuffer(dAtA[:size])
-	if err != nil {
-		return nil, err
+	if len(m.Labels) > 0 {
+		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Labels[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
 	}
-	return dAtA[:n], nil
-}
-
-func (m *ChunkRef) MarshalTo(dAtA []byte) (int, error) {
-	size := m.Size()
-	return m.MarshalToSizedBuffer(dAtA[:size])
-}
-
-func (m *ChunkRef) MarshalToSizedBuffer(dAtA []byte) (int, error) {
-	i := len(dAtA)
-	_ = i
-	var l int
-	_ = l
-	if m.Checksum != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Checksum))
-		i--
-		dAtA[i] = 0x28
-	}
-	if m.Through != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.From != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
-		i--
-		dAtA[i] = 0x18
-	}
-	if len(m.UserID) > 0 {
-		i -= len(m.UserID)
-		copy(dAtA[i:], m.UserID)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.UserID)))
+	if len(m.UserId) > 0 {
+		i -= len(m.UserId)
+		copy(dAtA[i:], m.UserId)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.UserId)))
 		i--
 		dAtA[i] = 0x12
 	}
-	if m.Fingerprint != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Fingerprint))
+	if len(m.FromIngesterId) > 0 {
+		i -= len(m.FromIngesterId)
+		copy(dAtA[i:], m.FromIngesterId)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.FromIngesterId)))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *LabelValuesForMetricNameRequest) Marshal() (dAtA []byte, err error) {
+func (m *HandoffStreamsRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6196,51 +7398,60 @@ func (m *LabelValuesForMetricNameRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *LabelValuesForMetricNameRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *HandoffStreamsRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *LabelValuesForMetricNameRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *HandoffStreamsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Matchers) > 0 {
-		i -= len(m.Matchers)
-		copy(dAtA[i:], m.Matchers)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Matchers)))
-		i--
-		dAtA[i] = 0x2a
-	}
-	if m.Through != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
-		i--
-		dAtA[i] = 0x20
+	if len(m.Chunks) > 0 {
+		for iNdEx := len(m.Chunks) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Chunks[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
 	}
-	if m.From != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
+	if m.Stream != nil {
+		{
+			size := m.Stream.Size()
+			i -= size
+			if _, err := m.Stream.MarshalTo(dAtA[i:]); err != nil {
+				return 0, err
+			}
+			i = encodeVarintLogproto(dAtA, i, uint64(size))
+		}
 		i--
-		dAtA[i] = 0x18
+		dAtA[i] = 0x1a
 	}
-	if len(m.LabelName) > 0 {
-		i -= len(m.LabelName)
-		copy(dAtA[i:], m.LabelName)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.LabelName)))
+	if len(m.UserId) > 0 {
+		i -= len(m.UserId)
+		copy(dAtA[i:], m.UserId)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.UserId)))
 		i--
 		dAtA[i] = 0x12
 	}
-	if len(m.MetricName) > 0 {
-		i -= len(m.MetricName)
-		copy(dAtA[i:], m.MetricName)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.MetricName)))
+	if len(m.FromIngesterId) > 0 {
+		i -= len(m.FromIngesterId)
+		copy(dAtA[i:], m.FromIngesterId)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.FromIngesterId)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *LabelNamesForMetricNameRequest) Marshal() (dAtA []byte, err error) {
+func (m *HandoffStreamsResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6250,37 +7461,20 @@ func (m *LabelNamesForMetricNameRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *LabelNamesForMetricNameRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *HandoffStreamsResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *LabelNamesForMetricNameRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *HandoffStreamsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Through != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
-		i--
-		dAtA[i] = 0x18
-	}
-	if m.From != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
-		i--
-		dAtA[i] = 0x10
-	}
-	if len(m.MetricName) > 0 {
-		i -= len(m.MetricName)
-		copy(dAtA[i:], m.MetricName)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.MetricName)))
-		i--
-		dAtA[i] = 0xa
-	}
 	return len(dAtA) - i, nil
 }
 
-func (m *GetChunkRefRequest) Marshal() (dAtA []byte, err error) {
+func (m *LabelPair) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6290,37 +7484,34 @@ func (m *GetChunkRefRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GetChunkRefRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *LabelPair) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *GetChunkRefRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *LabelPair) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Matchers) > 0 {
-		i -= len(m.Matchers)
-		copy(dAtA[i:], m.Matchers)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Matchers)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if m.Through != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Value)))
 		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x12
 	}
-	if m.From != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Name)))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *GetChunkRefResponse) Marshal() (dAtA []byte, err error) {
+func (m *LegacyLabelPair) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6330,34 +7521,34 @@ func (m *GetChunkRefResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GetChunkRefResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *LegacyLabelPair) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *GetChunkRefResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *LegacyLabelPair) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Refs) > 0 {
-		for iNdEx := len(m.Refs) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Refs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *GetSeriesRequest) Marshal() (dAtA []byte, err error) {
+func (m *Chunk) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6367,37 +7558,27 @@ func (m *GetSeriesRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GetSeriesRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *Chunk) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *GetSeriesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Chunk) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Matchers) > 0 {
-		i -= len(m.Matchers)
-		copy(dAtA[i:], m.Matchers)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Matchers)))
-		i--
-		dAtA[i] = 0x1a
-	}
-	if m.Through != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
-		i--
-		dAtA[i] = 0x10
-	}
-	if m.From != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Data)))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *GetSeriesResponse) Marshal() (dAtA []byte, err error) {
+func (m *TransferChunksResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6407,34 +7588,20 @@ func (m *GetSeriesResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *GetSeriesResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *TransferChunksResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *GetSeriesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TransferChunksResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Series) > 0 {
-		for iNdEx := len(m.Series) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Series[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
 	return len(dAtA) - i, nil
 }
 
-func (m *IndexSeries) Marshal() (dAtA []byte, err error) {
+func (m *TailersCountRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6444,34 +7611,20 @@ func (m *IndexSeries) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *IndexSeries) MarshalTo(dAtA []byte) (int, error) {
+func (m *TailersCountRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *IndexSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TailersCountRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Labels) > 0 {
-		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size := m.Labels[iNdEx].Size()
-				i -= size
-				if _, err := m.Labels[iNdEx].MarshalTo(dAtA[i:]); err != nil {
-					return 0, err
-				}
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0xa
-		}
-	}
 	return len(dAtA) - i, nil
 }
 
-func (m *QueryIndexResponse) Marshal() (dAtA []byte, err error) {
+func (m *TailersCountResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6481,41 +7634,25 @@ func (m *QueryIndexResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *QueryIndexResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *TailersCountResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *QueryIndexResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *TailersCountResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Rows) > 0 {
-		for iNdEx := len(m.Rows) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Rows[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
-			i--
-			dAtA[i] = 0x12
-		}
-	}
-	if len(m.QueryKey) > 0 {
-		i -= len(m.QueryKey)
-		copy(dAtA[i:], m.QueryKey)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.QueryKey)))
+	if m.Count != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Count))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Row) Marshal() (dAtA []byte, err error) {
+func (m *GetChunkIDsRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6525,34 +7662,43 @@ func (m *Row) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Row) MarshalTo(dAtA []byte) (int, error) {
+func (m *GetChunkIDsRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Row) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *GetChunkIDsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Value) > 0 {
-		i -= len(m.Value)
-		copy(dAtA[i:], m.Value)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Value)))
-		i--
-		dAtA[i] = 0x12
+	n17, err17 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
+	if err17 != nil {
+		return 0, err17
 	}
-	if len(m.RangeValue) > 0 {
-		i -= len(m.RangeValue)
-		copy(dAtA[i:], m.RangeValue)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.RangeValue)))
+	i -= n17
+	i = encodeVarintLogproto(dAtA, i, uint64(n17))
+	i--
+	dAtA[i] = 0x1a
+	n18, err18 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err18 != nil {
+		return 0, err18
+	}
+	i -= n18
+	i = encodeVarintLogproto(dAtA, i, uint64(n18))
+	i--
+	dAtA[i] = 0x12
+	if len(m.Matchers) > 0 {
+		i -= len(m.Matchers)
+		copy(dAtA[i:], m.Matchers)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Matchers)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *QueryIndexRequest) Marshal() (dAtA []byte, err error) {
+func (m *GetChunkIDsResponse) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6562,26 +7708,21 @@ func (m *QueryIndexRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *QueryIndexRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *GetChunkIDsResponse) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *QueryIndexRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *GetChunkIDsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.Queries) > 0 {
-		for iNdEx := len(m.Queries) - 1; iNdEx >= 0; iNdEx-- {
-			{
-				size, err := m.Queries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
-				if err != nil {
-					return 0, err
-				}
-				i -= size
-				i = encodeVarintLogproto(dAtA, i, uint64(size))
-			}
+	if len(m.ChunkIDs) > 0 {
+		for iNdEx := len(m.ChunkIDs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.ChunkIDs[iNdEx])
+			copy(dAtA[i:], m.ChunkIDs[iNdEx])
+			i = encodeVarintLogproto(dAtA, i, uint64(len(m.ChunkIDs[iNdEx])))
 			i--
 			dAtA[i] = 0xa
 		}
@@ -6589,7 +7730,7 @@ func (m *QueryIndexRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *IndexQuery) Marshal() (dAtA []byte, err error) {
+func (m *ChunkRef) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6599,55 +7740,47 @@ func (m *IndexQuery) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *IndexQuery) MarshalTo(dAtA []byte) (int, error) {
+func (m *ChunkRef) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *IndexQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ChunkRef) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.ValueEqual) > 0 {
-		i -= len(m.ValueEqual)
-		copy(dAtA[i:], m.ValueEqual)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.ValueEqual)))
+	if m.Checksum != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Checksum))
 		i--
-		dAtA[i] = 0x2a
+		dAtA[i] = 0x28
 	}
-	if len(m.RangeValueStart) > 0 {
-		i -= len(m.RangeValueStart)
-		copy(dAtA[i:], m.RangeValueStart)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.RangeValueStart)))
+	if m.Through != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
 		i--
-		dAtA[i] = 0x22
+		dAtA[i] = 0x20
 	}
-	if len(m.RangeValuePrefix) > 0 {
-		i -= len(m.RangeValuePrefix)
-		copy(dAtA[i:], m.RangeValuePrefix)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.RangeValuePrefix)))
+	if m.From != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
 		i--
-		dAtA[i] = 0x1a
+		dAtA[i] = 0x18
 	}
-	if len(m.HashValue) > 0 {
-		i -= len(m.HashValue)
-		copy(dAtA[i:], m.HashValue)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.HashValue)))
+	if len(m.UserID) > 0 {
+		i -= len(m.UserID)
+		copy(dAtA[i:], m.UserID)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.UserID)))
 		i--
 		dAtA[i] = 0x12
 	}
-	if len(m.TableName) > 0 {
-		i -= len(m.TableName)
-		copy(dAtA[i:], m.TableName)
-		i = encodeVarintLogproto(dAtA, i, uint64(len(m.TableName)))
+	if m.Fingerprint != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Fingerprint))
 		i--
-		dAtA[i] = 0xa
+		dAtA[i] = 0x8
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *IndexStatsRequest) Marshal() (dAtA []byte, err error) {
+func (m *LabelValuesForMetricNameRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6657,12 +7790,12 @@ func (m *IndexStatsRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *IndexStatsRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *LabelValuesForMetricNameRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *IndexStatsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *LabelValuesForMetricNameRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -6672,22 +7805,36 @@ func (m *IndexStatsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		copy(dAtA[i:], m.Matchers)
 		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Matchers)))
 		i--
-		dAtA[i] = 0x1a
+		dAtA[i] = 0x2a
 	}
 	if m.Through != 0 {
 		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
 		i--
-		dAtA[i] = 0x10
+		dAtA[i] = 0x20
 	}
 	if m.From != 0 {
 		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0x18
+	}
+	if len(m.LabelName) > 0 {
+		i -= len(m.LabelName)
+		copy(dAtA[i:], m.LabelName)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.LabelName)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.MetricName) > 0 {
+		i -= len(m.MetricName)
+		copy(dAtA[i:], m.MetricName)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.MetricName)))
+		i--
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *IndexStatsResponse) Marshal() (dAtA []byte, err error) {
+func (m *LabelNamesForMetricNameRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -6697,511 +7844,620 @@ func (m *IndexStatsResponse) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *IndexStatsResponse) MarshalTo(dAtA []byte) (int, error) {
+func (m *LabelNamesForMetricNameRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *IndexStatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *LabelNamesForMetricNameRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Entries != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Entries))
-		i--
-		dAtA[i] = 0x20
-	}
-	if m.Bytes != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Bytes))
+	if m.Through != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
 		i--
 		dAtA[i] = 0x18
 	}
-	if m.Chunks != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Chunks))
+	if m.From != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
 		i--
 		dAtA[i] = 0x10
 	}
-	if m.Streams != 0 {
-		i = encodeVarintLogproto(dAtA, i, uint64(m.Streams))
+	if len(m.MetricName) > 0 {
+		i -= len(m.MetricName)
+		copy(dAtA[i:], m.MetricName)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.MetricName)))
 		i--
-		dAtA[i] = 0x8
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintLogproto(dAtA []byte, offset int, v uint64) int {
-	offset -= sovLogproto(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
-	}
-	dAtA[offset] = uint8(v)
-	return base
-}
-func (m *StreamRatesRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *GetChunkRefRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	var l int
-	_ = l
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *StreamRatesResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.StreamRates) > 0 {
-		for _, e := range m.StreamRates {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
-		}
-	}
-	return n
+func (m *GetChunkRefRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *StreamRate) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *GetChunkRefRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.StreamHash != 0 {
-		n += 1 + sovLogproto(uint64(m.StreamHash))
-	}
-	if m.StreamHashNoShard != 0 {
-		n += 1 + sovLogproto(uint64(m.StreamHashNoShard))
+	if len(m.Matchers) > 0 {
+		i -= len(m.Matchers)
+		copy(dAtA[i:], m.Matchers)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Matchers)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	if m.Rate != 0 {
-		n += 1 + sovLogproto(uint64(m.Rate))
+	if m.Through != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
+		i--
+		dAtA[i] = 0x10
 	}
-	l = len(m.Tenant)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if m.From != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
+		i--
+		dAtA[i] = 0x8
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *QueryRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *GetChunkRefResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *GetChunkRefResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetChunkRefResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Selector)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	if m.Limit != 0 {
-		n += 1 + sovLogproto(uint64(m.Limit))
-	}
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
-	n += 1 + l + sovLogproto(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
-	n += 1 + l + sovLogproto(uint64(l))
-	if m.Direction != 0 {
-		n += 1 + sovLogproto(uint64(m.Direction))
-	}
-	if len(m.Shards) > 0 {
-		for _, s := range m.Shards {
-			l = len(s)
-			n += 1 + l + sovLogproto(uint64(l))
-		}
-	}
-	if len(m.Deletes) > 0 {
-		for _, e := range m.Deletes {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
+	if len(m.Refs) > 0 {
+		for iNdEx := len(m.Refs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Refs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SampleQueryRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Selector)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
-	n += 1 + l + sovLogproto(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
-	n += 1 + l + sovLogproto(uint64(l))
-	if len(m.Shards) > 0 {
-		for _, s := range m.Shards {
-			l = len(s)
-			n += 1 + l + sovLogproto(uint64(l))
-		}
-	}
-	if len(m.Deletes) > 0 {
-		for _, e := range m.Deletes {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
-		}
+func (m *GetSeriesRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *Delete) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *GetSeriesRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetSeriesRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Selector)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if len(m.Matchers) > 0 {
+		i -= len(m.Matchers)
+		copy(dAtA[i:], m.Matchers)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Matchers)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	if m.Start != 0 {
-		n += 1 + sovLogproto(uint64(m.Start))
+	if m.Through != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
+		i--
+		dAtA[i] = 0x10
 	}
-	if m.End != 0 {
-		n += 1 + sovLogproto(uint64(m.End))
+	if m.From != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
+		i--
+		dAtA[i] = 0x8
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *QueryResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Streams) > 0 {
-		for _, e := range m.Streams {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
-		}
+func (m *GetSeriesResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	l = m.Stats.Size()
-	n += 1 + l + sovLogproto(uint64(l))
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *SampleQueryResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *GetSeriesResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *GetSeriesResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
 	if len(m.Series) > 0 {
-		for _, e := range m.Series {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
+		for iNdEx := len(m.Series) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Series[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	l = m.Stats.Size()
-	n += 1 + l + sovLogproto(uint64(l))
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *LabelRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	if m.Values {
-		n += 2
-	}
-	if m.Start != nil {
-		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.Start)
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	if m.End != nil {
-		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.End)
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+func (m *IndexSeries) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *LabelResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *IndexSeries) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *IndexSeries) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Values) > 0 {
-		for _, s := range m.Values {
-			l = len(s)
-			n += 1 + l + sovLogproto(uint64(l))
+	if len(m.Labels) > 0 {
+		for iNdEx := len(m.Labels) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size := m.Labels[iNdEx].Size()
+				i -= size
+				if _, err := m.Labels[iNdEx].MarshalTo(dAtA[i:]); err != nil {
+					return 0, err
+				}
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *Sample) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Timestamp != 0 {
-		n += 1 + sovLogproto(uint64(m.Timestamp))
-	}
-	if m.Value != 0 {
-		n += 9
-	}
-	if m.Hash != 0 {
-		n += 1 + sovLogproto(uint64(m.Hash))
+func (m *QueryIndexResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *LegacySample) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Value != 0 {
-		n += 9
-	}
-	if m.TimestampMs != 0 {
-		n += 1 + sovLogproto(uint64(m.TimestampMs))
-	}
-	return n
+func (m *QueryIndexResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Series) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *QueryIndexResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Labels)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	if len(m.Samples) > 0 {
-		for _, e := range m.Samples {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
+	if len(m.Rows) > 0 {
+		for iNdEx := len(m.Rows) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Rows[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x12
 		}
 	}
-	if m.StreamHash != 0 {
-		n += 1 + sovLogproto(uint64(m.StreamHash))
+	if len(m.QueryKey) > 0 {
+		i -= len(m.QueryKey)
+		copy(dAtA[i:], m.QueryKey)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.QueryKey)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *TailRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Query)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	if m.DelayFor != 0 {
-		n += 1 + sovLogproto(uint64(m.DelayFor))
-	}
-	if m.Limit != 0 {
-		n += 1 + sovLogproto(uint64(m.Limit))
+func (m *Row) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
-	n += 1 + l + sovLogproto(uint64(l))
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *TailResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if m.Stream != nil {
-		l = m.Stream.Size()
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	if len(m.DroppedStreams) > 0 {
-		for _, e := range m.DroppedStreams {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
-		}
-	}
-	return n
+func (m *Row) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *SeriesRequest) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *Row) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
-	n += 1 + l + sovLogproto(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
-	n += 1 + l + sovLogproto(uint64(l))
-	if len(m.Groups) > 0 {
-		for _, s := range m.Groups {
-			l = len(s)
-			n += 1 + l + sovLogproto(uint64(l))
-		}
+	if len(m.Value) > 0 {
+		i -= len(m.Value)
+		copy(dAtA[i:], m.Value)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Value)))
+		i--
+		dAtA[i] = 0x12
 	}
-	if len(m.Shards) > 0 {
-		for _, s := range m.Shards {
-			l = len(s)
-			n += 1 + l + sovLogproto(uint64(l))
-		}
+	if len(m.RangeValue) > 0 {
+		i -= len(m.RangeValue)
+		copy(dAtA[i:], m.RangeValue)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.RangeValue)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SeriesResponse) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	if len(m.Series) > 0 {
-		for _, e := range m.Series {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
-		}
+func (m *QueryIndexRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *SeriesIdentifier) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *QueryIndexRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryIndexRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Labels) > 0 {
-		for k, v := range m.Labels {
-			_ = k
-			_ = v
-			mapEntrySize := 1 + len(k) + sovLogproto(uint64(len(k))) + 1 + len(v) + sovLogproto(uint64(len(v)))
-			n += mapEntrySize + 1 + sovLogproto(uint64(mapEntrySize))
+	if len(m.Queries) > 0 {
+		for iNdEx := len(m.Queries) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Queries[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
 		}
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *DroppedStream) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.From)
-	n += 1 + l + sovLogproto(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.To)
-	n += 1 + l + sovLogproto(uint64(l))
-	l = len(m.Labels)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+func (m *IndexQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	return n
+	return dAtA[:n], nil
 }
 
-func (m *TimeSeriesChunk) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+func (m *IndexQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *IndexQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.FromIngesterId)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if len(m.ValueEqual) > 0 {
+		i -= len(m.ValueEqual)
+		copy(dAtA[i:], m.ValueEqual)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.ValueEqual)))
+		i--
+		dAtA[i] = 0x2a
 	}
-	l = len(m.UserId)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if len(m.RangeValueStart) > 0 {
+		i -= len(m.RangeValueStart)
+		copy(dAtA[i:], m.RangeValueStart)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.RangeValueStart)))
+		i--
+		dAtA[i] = 0x22
 	}
-	if len(m.Labels) > 0 {
-		for _, e := range m.Labels {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
-		}
+	if len(m.RangeValuePrefix) > 0 {
+		i -= len(m.RangeValuePrefix)
+		copy(dAtA[i:], m.RangeValuePrefix)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.RangeValuePrefix)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	if len(m.Chunks) > 0 {
-		for _, e := range m.Chunks {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
-		}
+	if len(m.HashValue) > 0 {
+		i -= len(m.HashValue)
+		copy(dAtA[i:], m.HashValue)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.HashValue)))
+		i--
+		dAtA[i] = 0x12
 	}
-	return n
+	if len(m.TableName) > 0 {
+		i -= len(m.TableName)
+		copy(dAtA[i:], m.TableName)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.TableName)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *LabelPair) Size() (n int) {
-	if m == nil {
-		return 0
-	}
-	var l int
-	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+func (m *IndexStatsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	l = len(m.Value)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	return dAtA[:n], nil
+}
+
+func (m *IndexStatsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *IndexStatsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Matchers) > 0 {
+		i -= len(m.Matchers)
+		copy(dAtA[i:], m.Matchers)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Matchers)))
+		i--
+		dAtA[i] = 0x1a
 	}
-	return n
+	if m.Through != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Through))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.From != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.From))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *LegacyLabelPair) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *IndexStatsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *IndexStatsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *IndexStatsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Name)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if m.Entries != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Entries))
+		i--
+		dAtA[i] = 0x20
 	}
-	l = len(m.Value)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if m.Bytes != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Bytes))
+		i--
+		dAtA[i] = 0x18
 	}
-	return n
+	if m.Chunks != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Chunks))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Streams != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Streams))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *Chunk) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *TopStreamsRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *TopStreamsRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TopStreamsRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Data)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if m.Limit != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Limit))
+		i--
+		dAtA[i] = 0x10
 	}
-	return n
+	if len(m.SortBy) > 0 {
+		i -= len(m.SortBy)
+		copy(dAtA[i:], m.SortBy)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.SortBy)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *TransferChunksResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *TopStreamsResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TopStreamsResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TopStreamsResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Streams) > 0 {
+		for iNdEx := len(m.Streams) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Streams[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintLogproto(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *TopStream) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *TopStream) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *TopStream) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	return n
+	if m.Chunks != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.Chunks))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.MemoryBytes != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.MemoryBytes))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.EntriesRate != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.EntriesRate))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.BytesRate != 0 {
+		i = encodeVarintLogproto(dAtA, i, uint64(m.BytesRate))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.Labels) > 0 {
+		i -= len(m.Labels)
+		copy(dAtA[i:], m.Labels)
+		i = encodeVarintLogproto(dAtA, i, uint64(len(m.Labels)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
 }
 
-func (m *TailersCountRequest) Size() (n int) {
+func encodeVarintLogproto(dAtA []byte, offset int, v uint64) int {
+	offset -= sovLogproto(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *StreamRatesRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -7210,148 +8466,187 @@ func (m *TailersCountRequest) Size() (n int) {
 	return n
 }
 
-func (m *TailersCountResponse) Size() (n int) {
+func (m *StreamRatesResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.Count != 0 {
-		n += 1 + sovLogproto(uint64(m.Count))
+	if len(m.StreamRates) > 0 {
+		for _, e := range m.StreamRates {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
 	}
 	return n
 }
 
-func (m *GetChunkIDsRequest) Size() (n int) {
+func (m *StreamRate) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.Matchers)
+	if m.StreamHash != 0 {
+		n += 1 + sovLogproto(uint64(m.StreamHash))
+	}
+	if m.StreamHashNoShard != 0 {
+		n += 1 + sovLogproto(uint64(m.StreamHashNoShard))
+	}
+	if m.Rate != 0 {
+		n += 1 + sovLogproto(uint64(m.Rate))
+	}
+	l = len(m.Tenant)
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
-	n += 1 + l + sovLogproto(uint64(l))
-	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
-	n += 1 + l + sovLogproto(uint64(l))
+	if m.EntriesRate != 0 {
+		n += 1 + sovLogproto(uint64(m.EntriesRate))
+	}
 	return n
 }
 
-func (m *GetChunkIDsResponse) Size() (n int) {
+func (m *TenantVolumesRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if len(m.ChunkIDs) > 0 {
-		for _, s := range m.ChunkIDs {
-			l = len(s)
+	return n
+}
+
+func (m *TenantVolumesResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.TenantVolumes) > 0 {
+		for _, e := range m.TenantVolumes {
+			l = e.Size()
 			n += 1 + l + sovLogproto(uint64(l))
 		}
 	}
 	return n
 }
 
-func (m *ChunkRef) Size() (n int) {
+func (m *TenantVolume) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.Fingerprint != 0 {
-		n += 1 + sovLogproto(uint64(m.Fingerprint))
-	}
-	l = len(m.UserID)
+	l = len(m.Tenant)
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
-	if m.From != 0 {
-		n += 1 + sovLogproto(uint64(m.From))
+	if m.Day != 0 {
+		n += 1 + sovLogproto(uint64(m.Day))
 	}
-	if m.Through != 0 {
-		n += 1 + sovLogproto(uint64(m.Through))
+	if m.DayBytes != 0 {
+		n += 1 + sovLogproto(uint64(m.DayBytes))
 	}
-	if m.Checksum != 0 {
-		n += 1 + sovLogproto(uint64(m.Checksum))
+	if m.Month != 0 {
+		n += 1 + sovLogproto(uint64(m.Month))
+	}
+	if m.MonthBytes != 0 {
+		n += 1 + sovLogproto(uint64(m.MonthBytes))
 	}
 	return n
 }
 
-func (m *LabelValuesForMetricNameRequest) Size() (n int) {
+func (m *PushStreamRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.MetricName)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	l = len(m.LabelName)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
-	}
-	if m.From != 0 {
-		n += 1 + sovLogproto(uint64(m.From))
+	if m.BatchID != 0 {
+		n += 1 + sovLogproto(uint64(m.BatchID))
 	}
-	if m.Through != 0 {
-		n += 1 + sovLogproto(uint64(m.Through))
-	}
-	l = len(m.Matchers)
-	if l > 0 {
+	if m.Request != nil {
+		l = m.Request.Size()
 		n += 1 + l + sovLogproto(uint64(l))
 	}
 	return n
 }
 
-func (m *LabelNamesForMetricNameRequest) Size() (n int) {
+func (m *PushStreamResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.MetricName)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if m.BatchID != 0 {
+		n += 1 + sovLogproto(uint64(m.BatchID))
 	}
-	if m.From != 0 {
-		n += 1 + sovLogproto(uint64(m.From))
+	if m.Code != 0 {
+		n += 1 + sovLogproto(uint64(m.Code))
 	}
-	if m.Through != 0 {
-		n += 1 + sovLogproto(uint64(m.Through))
+	l = len(m.Error)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
 	return n
 }
 
-func (m *GetChunkRefRequest) Size() (n int) {
+func (m *QueryRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.From != 0 {
-		n += 1 + sovLogproto(uint64(m.From))
-	}
-	if m.Through != 0 {
-		n += 1 + sovLogproto(uint64(m.Through))
-	}
-	l = len(m.Matchers)
+	l = len(m.Selector)
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
+	if m.Limit != 0 {
+		n += 1 + sovLogproto(uint64(m.Limit))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
+	n += 1 + l + sovLogproto(uint64(l))
+	if m.Direction != 0 {
+		n += 1 + sovLogproto(uint64(m.Direction))
+	}
+	if len(m.Shards) > 0 {
+		for _, s := range m.Shards {
+			l = len(s)
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	if len(m.Deletes) > 0 {
+		for _, e := range m.Deletes {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
 	return n
 }
 
-func (m *GetChunkRefResponse) Size() (n int) {
+func (m *SampleQueryRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if len(m.Refs) > 0 {
-		for _, e := range m.Refs {
+	l = len(m.Selector)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
+	n += 1 + l + sovLogproto(uint64(l))
+	if len(m.Shards) > 0 {
+		for _, s := range m.Shards {
+			l = len(s)
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	if len(m.Deletes) > 0 {
+		for _, e := range m.Deletes {
 			l = e.Size()
 			n += 1 + l + sovLogproto(uint64(l))
 		}
@@ -7359,744 +8654,1904 @@ func (m *GetChunkRefResponse) Size() (n int) {
 	return n
 }
 
-func (m *GetSeriesRequest) Size() (n int) {
+func (m *Delete) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.From != 0 {
-		n += 1 + sovLogproto(uint64(m.From))
-	}
-	if m.Through != 0 {
-		n += 1 + sovLogproto(uint64(m.Through))
-	}
-	l = len(m.Matchers)
+	l = len(m.Selector)
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
+	if m.Start != 0 {
+		n += 1 + sovLogproto(uint64(m.Start))
+	}
+	if m.End != 0 {
+		n += 1 + sovLogproto(uint64(m.End))
+	}
 	return n
 }
 
-func (m *GetSeriesResponse) Size() (n int) {
+func (m *QueryResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if len(m.Series) > 0 {
-		for _, e := range m.Series {
+	if len(m.Streams) > 0 {
+		for _, e := range m.Streams {
 			l = e.Size()
 			n += 1 + l + sovLogproto(uint64(l))
 		}
 	}
+	l = m.Stats.Size()
+	n += 1 + l + sovLogproto(uint64(l))
 	return n
 }
 
-func (m *IndexSeries) Size() (n int) {
+func (m *SampleQueryResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if len(m.Labels) > 0 {
-		for _, e := range m.Labels {
+	if len(m.Series) > 0 {
+		for _, e := range m.Series {
 			l = e.Size()
 			n += 1 + l + sovLogproto(uint64(l))
 		}
 	}
+	l = m.Stats.Size()
+	n += 1 + l + sovLogproto(uint64(l))
 	return n
 }
 
-func (m *QueryIndexResponse) Size() (n int) {
+func (m *LabelRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.QueryKey)
+	l = len(m.Name)
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
-	if len(m.Rows) > 0 {
-		for _, e := range m.Rows {
-			l = e.Size()
+	if m.Values {
+		n += 2
+	}
+	if m.Start != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.Start)
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	if m.End != nil {
+		l = github_com_gogo_protobuf_types.SizeOfStdTime(*m.End)
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	l = len(m.Query)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	return n
+}
+
+func (m *LabelResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Values) > 0 {
+		for _, s := range m.Values {
+			l = len(s)
 			n += 1 + l + sovLogproto(uint64(l))
 		}
 	}
 	return n
 }
 
-func (m *Row) Size() (n int) {
+func (m *Sample) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.RangeValue)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if m.Timestamp != 0 {
+		n += 1 + sovLogproto(uint64(m.Timestamp))
 	}
-	l = len(m.Value)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if m.Value != 0 {
+		n += 9
+	}
+	if m.Hash != 0 {
+		n += 1 + sovLogproto(uint64(m.Hash))
 	}
 	return n
 }
 
-func (m *QueryIndexRequest) Size() (n int) {
+func (m *LegacySample) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if len(m.Queries) > 0 {
-		for _, e := range m.Queries {
-			l = e.Size()
-			n += 1 + l + sovLogproto(uint64(l))
-		}
+	if m.Value != 0 {
+		n += 9
+	}
+	if m.TimestampMs != 0 {
+		n += 1 + sovLogproto(uint64(m.TimestampMs))
 	}
 	return n
 }
 
-func (m *IndexQuery) Size() (n int) {
+func (m *Series) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	l = len(m.TableName)
+	l = len(m.Labels)
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
-	l = len(m.HashValue)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if len(m.Samples) > 0 {
+		for _, e := range m.Samples {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
 	}
-	l = len(m.RangeValuePrefix)
-	if l > 0 {
-		n += 1 + l + sovLogproto(uint64(l))
+	if m.StreamHash != 0 {
+		n += 1 + sovLogproto(uint64(m.StreamHash))
 	}
-	l = len(m.RangeValueStart)
+	return n
+}
+
+func (m *TailRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Query)
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
-	l = len(m.ValueEqual)
+	if m.DelayFor != 0 {
+		n += 1 + sovLogproto(uint64(m.DelayFor))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovLogproto(uint64(m.Limit))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = len(m.Cursor)
 	if l > 0 {
 		n += 1 + l + sovLogproto(uint64(l))
 	}
 	return n
 }
 
-func (m *IndexStatsRequest) Size() (n int) {
+func (m *TailResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.From != 0 {
-		n += 1 + sovLogproto(uint64(m.From))
-	}
-	if m.Through != 0 {
-		n += 1 + sovLogproto(uint64(m.Through))
-	}
-	l = len(m.Matchers)
-	if l > 0 {
+	if m.Stream != nil {
+		l = m.Stream.Size()
 		n += 1 + l + sovLogproto(uint64(l))
 	}
+	if len(m.DroppedStreams) > 0 {
+		for _, e := range m.DroppedStreams {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
 	return n
 }
 
-func (m *IndexStatsResponse) Size() (n int) {
+func (m *SeriesRequest) Size() (n int) {
 	if m == nil {
 		return 0
 	}
 	var l int
 	_ = l
-	if m.Streams != 0 {
-		n += 1 + sovLogproto(uint64(m.Streams))
-	}
-	if m.Chunks != 0 {
-		n += 1 + sovLogproto(uint64(m.Chunks))
-	}
-	if m.Bytes != 0 {
-		n += 1 + sovLogproto(uint64(m.Bytes))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
+	n += 1 + l + sovLogproto(uint64(l))
+	if len(m.Groups) > 0 {
+		for _, s := range m.Groups {
+			l = len(s)
+			n += 1 + l + sovLogproto(uint64(l))
+		}
 	}
-	if m.Entries != 0 {
-		n += 1 + sovLogproto(uint64(m.Entries))
+	if len(m.Shards) > 0 {
+		for _, s := range m.Shards {
+			l = len(s)
+			n += 1 + l + sovLogproto(uint64(l))
+		}
 	}
 	return n
 }
 
-func sovLogproto(x uint64) (n int) {
-	return (math_bits.Len64(x|1) + 6) / 7
-}
-func sozLogproto(x uint64) (n int) {
-	return sovLogproto(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *StreamRatesRequest) String() string {
-	if this == nil {
-		return "nil"
+func (m *SeriesResponse) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&StreamRatesRequest{`,
-		`}`,
-	}, "")
-	return s
+	var l int
+	_ = l
+	if len(m.Series) > 0 {
+		for _, e := range m.Series {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
 }
-func (this *StreamRatesResponse) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *SeriesIdentifier) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	repeatedStringForStreamRates := "[]*StreamRate{"
-	for _, f := range this.StreamRates {
-		repeatedStringForStreamRates += strings.Replace(f.String(), "StreamRate", "StreamRate", 1) + ","
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for k, v := range m.Labels {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovLogproto(uint64(len(k))) + 1 + len(v) + sovLogproto(uint64(len(v)))
+			n += mapEntrySize + 1 + sovLogproto(uint64(mapEntrySize))
+		}
 	}
-	repeatedStringForStreamRates += "}"
-	s := strings.Join([]string{`&StreamRatesResponse{`,
-		`StreamRates:` + repeatedStringForStreamRates + `,`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *StreamRate) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *DroppedStream) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&StreamRate{`,
-		`StreamHash:` + fmt.Sprintf("%v", this.StreamHash) + `,`,
-		`StreamHashNoShard:` + fmt.Sprintf("%v", this.StreamHashNoShard) + `,`,
-		`Rate:` + fmt.Sprintf("%v", this.Rate) + `,`,
-		`Tenant:` + fmt.Sprintf("%v", this.Tenant) + `,`,
-		`}`,
-	}, "")
-	return s
+	var l int
+	_ = l
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.From)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.To)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = len(m.Labels)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	return n
 }
-func (this *QueryRequest) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *TimeSeriesChunk) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	repeatedStringForDeletes := "[]*Delete{"
-	for _, f := range this.Deletes {
-		repeatedStringForDeletes += strings.Replace(f.String(), "Delete", "Delete", 1) + ","
+	var l int
+	_ = l
+	l = len(m.FromIngesterId)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	repeatedStringForDeletes += "}"
-	s := strings.Join([]string{`&QueryRequest{`,
-		`Selector:` + fmt.Sprintf("%v", this.Selector) + `,`,
-		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
-		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`End:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`Direction:` + fmt.Sprintf("%v", this.Direction) + `,`,
-		`Shards:` + fmt.Sprintf("%v", this.Shards) + `,`,
-		`Deletes:` + repeatedStringForDeletes + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *SampleQueryRequest) String() string {
-	if this == nil {
-		return "nil"
+	l = len(m.UserId)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	repeatedStringForDeletes := "[]*Delete{"
-	for _, f := range this.Deletes {
-		repeatedStringForDeletes += strings.Replace(f.String(), "Delete", "Delete", 1) + ","
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
 	}
-	repeatedStringForDeletes += "}"
-	s := strings.Join([]string{`&SampleQueryRequest{`,
-		`Selector:` + fmt.Sprintf("%v", this.Selector) + `,`,
-		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`End:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`Shards:` + fmt.Sprintf("%v", this.Shards) + `,`,
-		`Deletes:` + repeatedStringForDeletes + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Delete) String() string {
-	if this == nil {
-		return "nil"
+	if len(m.Chunks) > 0 {
+		for _, e := range m.Chunks {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
 	}
-	s := strings.Join([]string{`&Delete{`,
-		`Selector:` + fmt.Sprintf("%v", this.Selector) + `,`,
-		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
-		`End:` + fmt.Sprintf("%v", this.End) + `,`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *QueryResponse) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *HandoffStreamsRequest) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&QueryResponse{`,
-		`Streams:` + fmt.Sprintf("%v", this.Streams) + `,`,
-		`Stats:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Stats), "Ingester", "stats.Ingester", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *SampleQueryResponse) String() string {
-	if this == nil {
-		return "nil"
+	var l int
+	_ = l
+	l = len(m.FromIngesterId)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	s := strings.Join([]string{`&SampleQueryResponse{`,
-		`Series:` + fmt.Sprintf("%v", this.Series) + `,`,
-		`Stats:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Stats), "Ingester", "stats.Ingester", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *LabelRequest) String() string {
-	if this == nil {
-		return "nil"
+	l = len(m.UserId)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	s := strings.Join([]string{`&LabelRequest{`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`Values:` + fmt.Sprintf("%v", this.Values) + `,`,
-		`Start:` + strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1) + `,`,
-		`End:` + strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1) + `,`,
-		`Query:` + fmt.Sprintf("%v", this.Query) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *LabelResponse) String() string {
-	if this == nil {
-		return "nil"
+	if m.Stream != nil {
+		l = m.Stream.Size()
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	s := strings.Join([]string{`&LabelResponse{`,
-		`Values:` + fmt.Sprintf("%v", this.Values) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *Sample) String() string {
-	if this == nil {
-		return "nil"
+	if len(m.Chunks) > 0 {
+		for _, e := range m.Chunks {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
 	}
-	s := strings.Join([]string{`&Sample{`,
-		`Timestamp:` + fmt.Sprintf("%v", this.Timestamp) + `,`,
-		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
-		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *LegacySample) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *HandoffStreamsResponse) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&LegacySample{`,
-		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
-		`TimestampMs:` + fmt.Sprintf("%v", this.TimestampMs) + `,`,
-		`}`,
-	}, "")
-	return s
+	var l int
+	_ = l
+	return n
 }
-func (this *Series) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *LabelPair) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	repeatedStringForSamples := "[]Sample{"
-	for _, f := range this.Samples {
-		repeatedStringForSamples += strings.Replace(strings.Replace(f.String(), "Sample", "Sample", 1), `&`, ``, 1) + ","
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	repeatedStringForSamples += "}"
-	s := strings.Join([]string{`&Series{`,
-		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
-		`Samples:` + repeatedStringForSamples + `,`,
-		`StreamHash:` + fmt.Sprintf("%v", this.StreamHash) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TailRequest) String() string {
-	if this == nil {
-		return "nil"
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	s := strings.Join([]string{`&TailRequest{`,
-		`Query:` + fmt.Sprintf("%v", this.Query) + `,`,
-		`DelayFor:` + fmt.Sprintf("%v", this.DelayFor) + `,`,
-		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
-		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *TailResponse) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *LegacyLabelPair) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	repeatedStringForDroppedStreams := "[]*DroppedStream{"
-	for _, f := range this.DroppedStreams {
-		repeatedStringForDroppedStreams += strings.Replace(f.String(), "DroppedStream", "DroppedStream", 1) + ","
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	repeatedStringForDroppedStreams += "}"
-	s := strings.Join([]string{`&TailResponse{`,
-		`Stream:` + fmt.Sprintf("%v", this.Stream) + `,`,
-		`DroppedStreams:` + repeatedStringForDroppedStreams + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *SeriesRequest) String() string {
-	if this == nil {
-		return "nil"
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	s := strings.Join([]string{`&SeriesRequest{`,
-		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`End:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`Groups:` + fmt.Sprintf("%v", this.Groups) + `,`,
-		`Shards:` + fmt.Sprintf("%v", this.Shards) + `,`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *SeriesResponse) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *Chunk) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	repeatedStringForSeries := "[]SeriesIdentifier{"
-	for _, f := range this.Series {
-		repeatedStringForSeries += strings.Replace(strings.Replace(f.String(), "SeriesIdentifier", "SeriesIdentifier", 1), `&`, ``, 1) + ","
+	var l int
+	_ = l
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	repeatedStringForSeries += "}"
-	s := strings.Join([]string{`&SeriesResponse{`,
-		`Series:` + repeatedStringForSeries + `,`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *SeriesIdentifier) String() string {
-	if this == nil {
-		return "nil"
-	}
-	keysForLabels := make([]string, 0, len(this.Labels))
-	for k, _ := range this.Labels {
-		keysForLabels = append(keysForLabels, k)
-	}
-	github_com_gogo_protobuf_sortkeys.Strings(keysForLabels)
-	mapStringForLabels := "map[string]string{"
-	for _, k := range keysForLabels {
-		mapStringForLabels += fmt.Sprintf("%v: %v,", k, this.Labels[k])
+
+func (m *TransferChunksResponse) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	mapStringForLabels += "}"
-	s := strings.Join([]string{`&SeriesIdentifier{`,
-		`Labels:` + mapStringForLabels + `,`,
-		`}`,
-	}, "")
-	return s
+	var l int
+	_ = l
+	return n
 }
-func (this *DroppedStream) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *TailersCountRequest) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&DroppedStream{`,
-		`From:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.From), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`To:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.To), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
-		`}`,
-	}, "")
-	return s
+	var l int
+	_ = l
+	return n
 }
-func (this *TimeSeriesChunk) String() string {
-	if this == nil {
-		return "nil"
-	}
-	repeatedStringForLabels := "[]*LabelPair{"
-	for _, f := range this.Labels {
-		repeatedStringForLabels += strings.Replace(f.String(), "LabelPair", "LabelPair", 1) + ","
+
+func (m *TailersCountResponse) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	repeatedStringForLabels += "}"
-	repeatedStringForChunks := "[]*Chunk{"
-	for _, f := range this.Chunks {
-		repeatedStringForChunks += strings.Replace(f.String(), "Chunk", "Chunk", 1) + ","
+	var l int
+	_ = l
+	if m.Count != 0 {
+		n += 1 + sovLogproto(uint64(m.Count))
 	}
-	repeatedStringForChunks += "}"
-	s := strings.Join([]string{`&TimeSeriesChunk{`,
-		`FromIngesterId:` + fmt.Sprintf("%v", this.FromIngesterId) + `,`,
-		`UserId:` + fmt.Sprintf("%v", this.UserId) + `,`,
-		`Labels:` + repeatedStringForLabels + `,`,
-		`Chunks:` + repeatedStringForChunks + `,`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *LabelPair) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *GetChunkIDsRequest) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&LabelPair{`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *LegacyLabelPair) String() string {
-	if this == nil {
-		return "nil"
+	var l int
+	_ = l
+	l = len(m.Matchers)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	s := strings.Join([]string{`&LegacyLabelPair{`,
-		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
-		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
-		`}`,
-	}, "")
-	return s
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
+	n += 1 + l + sovLogproto(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
+	n += 1 + l + sovLogproto(uint64(l))
+	return n
 }
-func (this *Chunk) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *GetChunkIDsResponse) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&Chunk{`,
-		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TransferChunksResponse) String() string {
-	if this == nil {
-		return "nil"
+	var l int
+	_ = l
+	if len(m.ChunkIDs) > 0 {
+		for _, s := range m.ChunkIDs {
+			l = len(s)
+			n += 1 + l + sovLogproto(uint64(l))
+		}
 	}
-	s := strings.Join([]string{`&TransferChunksResponse{`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *TailersCountRequest) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *ChunkRef) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&TailersCountRequest{`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *TailersCountResponse) String() string {
-	if this == nil {
-		return "nil"
+	var l int
+	_ = l
+	if m.Fingerprint != 0 {
+		n += 1 + sovLogproto(uint64(m.Fingerprint))
 	}
-	s := strings.Join([]string{`&TailersCountResponse{`,
-		`Count:` + fmt.Sprintf("%v", this.Count) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *GetChunkIDsRequest) String() string {
-	if this == nil {
-		return "nil"
+	l = len(m.UserID)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	s := strings.Join([]string{`&GetChunkIDsRequest{`,
-		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
-		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`End:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *GetChunkIDsResponse) String() string {
-	if this == nil {
-		return "nil"
+	if m.From != 0 {
+		n += 1 + sovLogproto(uint64(m.From))
 	}
-	s := strings.Join([]string{`&GetChunkIDsResponse{`,
-		`ChunkIDs:` + fmt.Sprintf("%v", this.ChunkIDs) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *ChunkRef) String() string {
-	if this == nil {
-		return "nil"
+	if m.Through != 0 {
+		n += 1 + sovLogproto(uint64(m.Through))
 	}
-	s := strings.Join([]string{`&ChunkRef{`,
-		`Fingerprint:` + fmt.Sprintf("%v", this.Fingerprint) + `,`,
-		`UserID:` + fmt.Sprintf("%v", this.UserID) + `,`,
-		`From:` + fmt.Sprintf("%v", this.From) + `,`,
-		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
-		`Checksum:` + fmt.Sprintf("%v", this.Checksum) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *LabelValuesForMetricNameRequest) String() string {
-	if this == nil {
-		return "nil"
+	if m.Checksum != 0 {
+		n += 1 + sovLogproto(uint64(m.Checksum))
 	}
-	s := strings.Join([]string{`&LabelValuesForMetricNameRequest{`,
-		`MetricName:` + fmt.Sprintf("%v", this.MetricName) + `,`,
-		`LabelName:` + fmt.Sprintf("%v", this.LabelName) + `,`,
-		`From:` + fmt.Sprintf("%v", this.From) + `,`,
-		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
-		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *LabelNamesForMetricNameRequest) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *LabelValuesForMetricNameRequest) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&LabelNamesForMetricNameRequest{`,
-		`MetricName:` + fmt.Sprintf("%v", this.MetricName) + `,`,
-		`From:` + fmt.Sprintf("%v", this.From) + `,`,
-		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *GetChunkRefRequest) String() string {
-	if this == nil {
-		return "nil"
+	var l int
+	_ = l
+	l = len(m.MetricName)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	s := strings.Join([]string{`&GetChunkRefRequest{`,
-		`From:` + fmt.Sprintf("%v", this.From) + `,`,
-		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
-		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *GetChunkRefResponse) String() string {
-	if this == nil {
-		return "nil"
+	l = len(m.LabelName)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	repeatedStringForRefs := "[]*ChunkRef{"
-	for _, f := range this.Refs {
-		repeatedStringForRefs += strings.Replace(f.String(), "ChunkRef", "ChunkRef", 1) + ","
+	if m.From != 0 {
+		n += 1 + sovLogproto(uint64(m.From))
 	}
-	repeatedStringForRefs += "}"
-	s := strings.Join([]string{`&GetChunkRefResponse{`,
-		`Refs:` + repeatedStringForRefs + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func (this *GetSeriesRequest) String() string {
-	if this == nil {
-		return "nil"
+	if m.Through != 0 {
+		n += 1 + sovLogproto(uint64(m.Through))
 	}
-	s := strings.Join([]string{`&GetSeriesRequest{`,
-		`From:` + fmt.Sprintf("%v", this.From) + `,`,
-		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
-		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
-		`}`,
-	}, "")
-	return s
+	l = len(m.Matchers)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	return n
 }
-func (this *GetSeriesResponse) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *LabelNamesForMetricNameRequest) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	repeatedStringForSeries := "[]IndexSeries{"
-	for _, f := range this.Series {
-		repeatedStringForSeries += strings.Replace(strings.Replace(f.String(), "IndexSeries", "IndexSeries", 1), `&`, ``, 1) + ","
+	var l int
+	_ = l
+	l = len(m.MetricName)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
 	}
-	repeatedStringForSeries += "}"
-	s := strings.Join([]string{`&GetSeriesResponse{`,
-		`Series:` + repeatedStringForSeries + `,`,
-		`}`,
-	}, "")
-	return s
+	if m.From != 0 {
+		n += 1 + sovLogproto(uint64(m.From))
+	}
+	if m.Through != 0 {
+		n += 1 + sovLogproto(uint64(m.Through))
+	}
+	return n
 }
-func (this *IndexSeries) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *GetChunkRefRequest) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&IndexSeries{`,
-		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
-		`}`,
-	}, "")
-	return s
+	var l int
+	_ = l
+	if m.From != 0 {
+		n += 1 + sovLogproto(uint64(m.From))
+	}
+	if m.Through != 0 {
+		n += 1 + sovLogproto(uint64(m.Through))
+	}
+	l = len(m.Matchers)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	return n
 }
-func (this *QueryIndexResponse) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *GetChunkRefResponse) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	repeatedStringForRows := "[]*Row{"
-	for _, f := range this.Rows {
-		repeatedStringForRows += strings.Replace(f.String(), "Row", "Row", 1) + ","
+	var l int
+	_ = l
+	if len(m.Refs) > 0 {
+		for _, e := range m.Refs {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
 	}
-	repeatedStringForRows += "}"
-	s := strings.Join([]string{`&QueryIndexResponse{`,
-		`QueryKey:` + fmt.Sprintf("%v", this.QueryKey) + `,`,
-		`Rows:` + repeatedStringForRows + `,`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *Row) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *GetSeriesRequest) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&Row{`,
-		`RangeValue:` + fmt.Sprintf("%v", this.RangeValue) + `,`,
-		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
-		`}`,
-	}, "")
-	return s
+	var l int
+	_ = l
+	if m.From != 0 {
+		n += 1 + sovLogproto(uint64(m.From))
+	}
+	if m.Through != 0 {
+		n += 1 + sovLogproto(uint64(m.Through))
+	}
+	l = len(m.Matchers)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	return n
 }
-func (this *QueryIndexRequest) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *GetSeriesResponse) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	repeatedStringForQueries := "[]*IndexQuery{"
-	for _, f := range this.Queries {
-		repeatedStringForQueries += strings.Replace(f.String(), "IndexQuery", "IndexQuery", 1) + ","
+	var l int
+	_ = l
+	if len(m.Series) > 0 {
+		for _, e := range m.Series {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
 	}
-	repeatedStringForQueries += "}"
-	s := strings.Join([]string{`&QueryIndexRequest{`,
-		`Queries:` + repeatedStringForQueries + `,`,
-		`}`,
-	}, "")
-	return s
+	return n
 }
-func (this *IndexQuery) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *IndexSeries) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&IndexQuery{`,
-		`TableName:` + fmt.Sprintf("%v", this.TableName) + `,`,
-		`HashValue:` + fmt.Sprintf("%v", this.HashValue) + `,`,
-		`RangeValuePrefix:` + fmt.Sprintf("%v", this.RangeValuePrefix) + `,`,
-		`RangeValueStart:` + fmt.Sprintf("%v", this.RangeValueStart) + `,`,
-		`ValueEqual:` + fmt.Sprintf("%v", this.ValueEqual) + `,`,
-		`}`,
-	}, "")
-	return s
+	var l int
+	_ = l
+	if len(m.Labels) > 0 {
+		for _, e := range m.Labels {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
 }
-func (this *IndexStatsRequest) String() string {
-	if this == nil {
-		return "nil"
+
+func (m *QueryIndexResponse) Size() (n int) {
+	if m == nil {
+		return 0
 	}
-	s := strings.Join([]string{`&IndexStatsRequest{`,
-		`From:` + fmt.Sprintf("%v", this.From) + `,`,
-		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
-		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
+	var l int
+	_ = l
+	l = len(m.QueryKey)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	if len(m.Rows) > 0 {
+		for _, e := range m.Rows {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Row) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.RangeValue)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	return n
+}
+
+func (m *QueryIndexRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Queries) > 0 {
+		for _, e := range m.Queries {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *IndexQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.TableName)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	l = len(m.HashValue)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	l = len(m.RangeValuePrefix)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	l = len(m.RangeValueStart)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	l = len(m.ValueEqual)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	return n
+}
+
+func (m *IndexStatsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.From != 0 {
+		n += 1 + sovLogproto(uint64(m.From))
+	}
+	if m.Through != 0 {
+		n += 1 + sovLogproto(uint64(m.Through))
+	}
+	l = len(m.Matchers)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	return n
+}
+
+func (m *IndexStatsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Streams != 0 {
+		n += 1 + sovLogproto(uint64(m.Streams))
+	}
+	if m.Chunks != 0 {
+		n += 1 + sovLogproto(uint64(m.Chunks))
+	}
+	if m.Bytes != 0 {
+		n += 1 + sovLogproto(uint64(m.Bytes))
+	}
+	if m.Entries != 0 {
+		n += 1 + sovLogproto(uint64(m.Entries))
+	}
+	return n
+}
+
+func (m *TopStreamsRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.SortBy)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	if m.Limit != 0 {
+		n += 1 + sovLogproto(uint64(m.Limit))
+	}
+	return n
+}
+
+func (m *TopStreamsResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Streams) > 0 {
+		for _, e := range m.Streams {
+			l = e.Size()
+			n += 1 + l + sovLogproto(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TopStream) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Labels)
+	if l > 0 {
+		n += 1 + l + sovLogproto(uint64(l))
+	}
+	if m.BytesRate != 0 {
+		n += 1 + sovLogproto(uint64(m.BytesRate))
+	}
+	if m.EntriesRate != 0 {
+		n += 1 + sovLogproto(uint64(m.EntriesRate))
+	}
+	if m.MemoryBytes != 0 {
+		n += 1 + sovLogproto(uint64(m.MemoryBytes))
+	}
+	if m.Chunks != 0 {
+		n += 1 + sovLogproto(uint64(m.Chunks))
+	}
+	return n
+}
+
+func sovLogproto(x uint64) (n int) {
+	return (math_bits.Len64(x|1) + 6) / 7
+}
+func sozLogproto(x uint64) (n int) {
+	return sovLogproto(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *StreamRatesRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamRatesRequest{`,
 		`}`,
 	}, "")
 	return s
 }
-func (this *IndexStatsResponse) String() string {
+func (this *StreamRatesResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForStreamRates := "[]*StreamRate{"
+	for _, f := range this.StreamRates {
+		repeatedStringForStreamRates += strings.Replace(f.String(), "StreamRate", "StreamRate", 1) + ","
+	}
+	repeatedStringForStreamRates += "}"
+	s := strings.Join([]string{`&StreamRatesResponse{`,
+		`StreamRates:` + repeatedStringForStreamRates + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StreamRate) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StreamRate{`,
+		`StreamHash:` + fmt.Sprintf("%v", this.StreamHash) + `,`,
+		`StreamHashNoShard:` + fmt.Sprintf("%v", this.StreamHashNoShard) + `,`,
+		`Rate:` + fmt.Sprintf("%v", this.Rate) + `,`,
+		`Tenant:` + fmt.Sprintf("%v", this.Tenant) + `,`,
+		`EntriesRate:` + fmt.Sprintf("%v", this.EntriesRate) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantVolumesRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TenantVolumesRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantVolumesResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForTenantVolumes := "[]*TenantVolume{"
+	for _, f := range this.TenantVolumes {
+		repeatedStringForTenantVolumes += strings.Replace(f.String(), "TenantVolume", "TenantVolume", 1) + ","
+	}
+	repeatedStringForTenantVolumes += "}"
+	s := strings.Join([]string{`&TenantVolumesResponse{`,
+		`TenantVolumes:` + repeatedStringForTenantVolumes + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TenantVolume) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TenantVolume{`,
+		`Tenant:` + fmt.Sprintf("%v", this.Tenant) + `,`,
+		`Day:` + fmt.Sprintf("%v", this.Day) + `,`,
+		`DayBytes:` + fmt.Sprintf("%v", this.DayBytes) + `,`,
+		`Month:` + fmt.Sprintf("%v", this.Month) + `,`,
+		`MonthBytes:` + fmt.Sprintf("%v", this.MonthBytes) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PushStreamRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PushStreamRequest{`,
+		`BatchID:` + fmt.Sprintf("%v", this.BatchID) + `,`,
+		`Request:` + strings.Replace(fmt.Sprintf("%v", this.Request), "PushRequest", "push.PushRequest", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *PushStreamResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&PushStreamResponse{`,
+		`BatchID:` + fmt.Sprintf("%v", this.BatchID) + `,`,
+		`Code:` + fmt.Sprintf("%v", this.Code) + `,`,
+		`Error:` + fmt.Sprintf("%v", this.Error) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryRequest) String() string {
 	if this == nil {
 		return "nil"
 	}
-	s := strings.Join([]string{`&IndexStatsResponse{`,
-		`Streams:` + fmt.Sprintf("%v", this.Streams) + `,`,
-		`Chunks:` + fmt.Sprintf("%v", this.Chunks) + `,`,
-		`Bytes:` + fmt.Sprintf("%v", this.Bytes) + `,`,
-		`Entries:` + fmt.Sprintf("%v", this.Entries) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringLogproto(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+	repeatedStringForDeletes := "[]*Delete{"
+	for _, f := range this.Deletes {
+		repeatedStringForDeletes += strings.Replace(f.String(), "Delete", "Delete", 1) + ","
+	}
+	repeatedStringForDeletes += "}"
+	s := strings.Join([]string{`&QueryRequest{`,
+		`Selector:` + fmt.Sprintf("%v", this.Selector) + `,`,
+		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
+		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`End:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Direction:` + fmt.Sprintf("%v", this.Direction) + `,`,
+		`Shards:` + fmt.Sprintf("%v", this.Shards) + `,`,
+		`Deletes:` + repeatedStringForDeletes + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SampleQueryRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForDeletes := "[]*Delete{"
+	for _, f := range this.Deletes {
+		repeatedStringForDeletes += strings.Replace(f.String(), "Delete", "Delete", 1) + ","
+	}
+	repeatedStringForDeletes += "}"
+	s := strings.Join([]string{`&SampleQueryRequest{`,
+		`Selector:` + fmt.Sprintf("%v", this.Selector) + `,`,
+		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`End:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Shards:` + fmt.Sprintf("%v", this.Shards) + `,`,
+		`Deletes:` + repeatedStringForDeletes + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Delete) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Delete{`,
+		`Selector:` + fmt.Sprintf("%v", this.Selector) + `,`,
+		`Start:` + fmt.Sprintf("%v", this.Start) + `,`,
+		`End:` + fmt.Sprintf("%v", this.End) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryResponse{`,
+		`Streams:` + fmt.Sprintf("%v", this.Streams) + `,`,
+		`Stats:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Stats), "Ingester", "stats.Ingester", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SampleQueryResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SampleQueryResponse{`,
+		`Series:` + fmt.Sprintf("%v", this.Series) + `,`,
+		`Stats:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Stats), "Ingester", "stats.Ingester", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LabelRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LabelRequest{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Values:` + fmt.Sprintf("%v", this.Values) + `,`,
+		`Start:` + strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1) + `,`,
+		`End:` + strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1) + `,`,
+		`Query:` + fmt.Sprintf("%v", this.Query) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LabelResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LabelResponse{`,
+		`Values:` + fmt.Sprintf("%v", this.Values) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Sample) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Sample{`,
+		`Timestamp:` + fmt.Sprintf("%v", this.Timestamp) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LegacySample) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LegacySample{`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`TimestampMs:` + fmt.Sprintf("%v", this.TimestampMs) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Series) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSamples := "[]Sample{"
+	for _, f := range this.Samples {
+		repeatedStringForSamples += strings.Replace(strings.Replace(f.String(), "Sample", "Sample", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForSamples += "}"
+	s := strings.Join([]string{`&Series{`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`Samples:` + repeatedStringForSamples + `,`,
+		`StreamHash:` + fmt.Sprintf("%v", this.StreamHash) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TailRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TailRequest{`,
+		`Query:` + fmt.Sprintf("%v", this.Query) + `,`,
+		`DelayFor:` + fmt.Sprintf("%v", this.DelayFor) + `,`,
+		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
+		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Cursor:` + fmt.Sprintf("%v", this.Cursor) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TailResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForDroppedStreams := "[]*DroppedStream{"
+	for _, f := range this.DroppedStreams {
+		repeatedStringForDroppedStreams += strings.Replace(f.String(), "DroppedStream", "DroppedStream", 1) + ","
+	}
+	repeatedStringForDroppedStreams += "}"
+	s := strings.Join([]string{`&TailResponse{`,
+		`Stream:` + fmt.Sprintf("%v", this.Stream) + `,`,
+		`DroppedStreams:` + repeatedStringForDroppedStreams + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SeriesRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SeriesRequest{`,
+		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`End:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Groups:` + fmt.Sprintf("%v", this.Groups) + `,`,
+		`Shards:` + fmt.Sprintf("%v", this.Shards) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SeriesResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSeries := "[]SeriesIdentifier{"
+	for _, f := range this.Series {
+		repeatedStringForSeries += strings.Replace(strings.Replace(f.String(), "SeriesIdentifier", "SeriesIdentifier", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForSeries += "}"
+	s := strings.Join([]string{`&SeriesResponse{`,
+		`Series:` + repeatedStringForSeries + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SeriesIdentifier) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForLabels := make([]string, 0, len(this.Labels))
+	for k, _ := range this.Labels {
+		keysForLabels = append(keysForLabels, k)
+	}
+	github_com_gogo_protobuf_sortkeys.Strings(keysForLabels)
+	mapStringForLabels := "map[string]string{"
+	for _, k := range keysForLabels {
+		mapStringForLabels += fmt.Sprintf("%v: %v,", k, this.Labels[k])
+	}
+	mapStringForLabels += "}"
+	s := strings.Join([]string{`&SeriesIdentifier{`,
+		`Labels:` + mapStringForLabels + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *DroppedStream) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&DroppedStream{`,
+		`From:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.From), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`To:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.To), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TimeSeriesChunk) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForLabels := "[]*LabelPair{"
+	for _, f := range this.Labels {
+		repeatedStringForLabels += strings.Replace(f.String(), "LabelPair", "LabelPair", 1) + ","
+	}
+	repeatedStringForLabels += "}"
+	repeatedStringForChunks := "[]*Chunk{"
+	for _, f := range this.Chunks {
+		repeatedStringForChunks += strings.Replace(f.String(), "Chunk", "Chunk", 1) + ","
+	}
+	repeatedStringForChunks += "}"
+	s := strings.Join([]string{`&TimeSeriesChunk{`,
+		`FromIngesterId:` + fmt.Sprintf("%v", this.FromIngesterId) + `,`,
+		`UserId:` + fmt.Sprintf("%v", this.UserId) + `,`,
+		`Labels:` + repeatedStringForLabels + `,`,
+		`Chunks:` + repeatedStringForChunks + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HandoffStreamsRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForChunks := "[]*Chunk{"
+	for _, f := range this.Chunks {
+		repeatedStringForChunks += strings.Replace(f.String(), "Chunk", "Chunk", 1) + ","
+	}
+	repeatedStringForChunks += "}"
+	s := strings.Join([]string{`&HandoffStreamsRequest{`,
+		`FromIngesterId:` + fmt.Sprintf("%v", this.FromIngesterId) + `,`,
+		`UserId:` + fmt.Sprintf("%v", this.UserId) + `,`,
+		`Stream:` + fmt.Sprintf("%v", this.Stream) + `,`,
+		`Chunks:` + repeatedStringForChunks + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *HandoffStreamsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&HandoffStreamsResponse{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LabelPair) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LabelPair{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LegacyLabelPair) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LegacyLabelPair{`,
+		`Name:` + fmt.Sprintf("%v", this.Name) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Chunk) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Chunk{`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TransferChunksResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TransferChunksResponse{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TailersCountRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TailersCountRequest{`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TailersCountResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TailersCountResponse{`,
+		`Count:` + fmt.Sprintf("%v", this.Count) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetChunkIDsRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetChunkIDsRequest{`,
+		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
+		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`End:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetChunkIDsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetChunkIDsResponse{`,
+		`ChunkIDs:` + fmt.Sprintf("%v", this.ChunkIDs) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ChunkRef) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ChunkRef{`,
+		`Fingerprint:` + fmt.Sprintf("%v", this.Fingerprint) + `,`,
+		`UserID:` + fmt.Sprintf("%v", this.UserID) + `,`,
+		`From:` + fmt.Sprintf("%v", this.From) + `,`,
+		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
+		`Checksum:` + fmt.Sprintf("%v", this.Checksum) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LabelValuesForMetricNameRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LabelValuesForMetricNameRequest{`,
+		`MetricName:` + fmt.Sprintf("%v", this.MetricName) + `,`,
+		`LabelName:` + fmt.Sprintf("%v", this.LabelName) + `,`,
+		`From:` + fmt.Sprintf("%v", this.From) + `,`,
+		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
+		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LabelNamesForMetricNameRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LabelNamesForMetricNameRequest{`,
+		`MetricName:` + fmt.Sprintf("%v", this.MetricName) + `,`,
+		`From:` + fmt.Sprintf("%v", this.From) + `,`,
+		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetChunkRefRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetChunkRefRequest{`,
+		`From:` + fmt.Sprintf("%v", this.From) + `,`,
+		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
+		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetChunkRefResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRefs := "[]*ChunkRef{"
+	for _, f := range this.Refs {
+		repeatedStringForRefs += strings.Replace(f.String(), "ChunkRef", "ChunkRef", 1) + ","
+	}
+	repeatedStringForRefs += "}"
+	s := strings.Join([]string{`&GetChunkRefResponse{`,
+		`Refs:` + repeatedStringForRefs + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetSeriesRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetSeriesRequest{`,
+		`From:` + fmt.Sprintf("%v", this.From) + `,`,
+		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
+		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetSeriesResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForSeries := "[]IndexSeries{"
+	for _, f := range this.Series {
+		repeatedStringForSeries += strings.Replace(strings.Replace(f.String(), "IndexSeries", "IndexSeries", 1), `&`, ``, 1) + ","
+	}
+	repeatedStringForSeries += "}"
+	s := strings.Join([]string{`&GetSeriesResponse{`,
+		`Series:` + repeatedStringForSeries + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *IndexSeries) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&IndexSeries{`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryIndexResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForRows := "[]*Row{"
+	for _, f := range this.Rows {
+		repeatedStringForRows += strings.Replace(f.String(), "Row", "Row", 1) + ","
+	}
+	repeatedStringForRows += "}"
+	s := strings.Join([]string{`&QueryIndexResponse{`,
+		`QueryKey:` + fmt.Sprintf("%v", this.QueryKey) + `,`,
+		`Rows:` + repeatedStringForRows + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Row) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Row{`,
+		`RangeValue:` + fmt.Sprintf("%v", this.RangeValue) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *QueryIndexRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForQueries := "[]*IndexQuery{"
+	for _, f := range this.Queries {
+		repeatedStringForQueries += strings.Replace(f.String(), "IndexQuery", "IndexQuery", 1) + ","
+	}
+	repeatedStringForQueries += "}"
+	s := strings.Join([]string{`&QueryIndexRequest{`,
+		`Queries:` + repeatedStringForQueries + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *IndexQuery) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&IndexQuery{`,
+		`TableName:` + fmt.Sprintf("%v", this.TableName) + `,`,
+		`HashValue:` + fmt.Sprintf("%v", this.HashValue) + `,`,
+		`RangeValuePrefix:` + fmt.Sprintf("%v", this.RangeValuePrefix) + `,`,
+		`RangeValueStart:` + fmt.Sprintf("%v", this.RangeValueStart) + `,`,
+		`ValueEqual:` + fmt.Sprintf("%v", this.ValueEqual) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *IndexStatsRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&IndexStatsRequest{`,
+		`From:` + fmt.Sprintf("%v", this.From) + `,`,
+		`Through:` + fmt.Sprintf("%v", this.Through) + `,`,
+		`Matchers:` + fmt.Sprintf("%v", this.Matchers) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *IndexStatsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&IndexStatsResponse{`,
+		`Streams:` + fmt.Sprintf("%v", this.Streams) + `,`,
+		`Chunks:` + fmt.Sprintf("%v", this.Chunks) + `,`,
+		`Bytes:` + fmt.Sprintf("%v", this.Bytes) + `,`,
+		`Entries:` + fmt.Sprintf("%v", this.Entries) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TopStreamsRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TopStreamsRequest{`,
+		`SortBy:` + fmt.Sprintf("%v", this.SortBy) + `,`,
+		`Limit:` + fmt.Sprintf("%v", this.Limit) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TopStreamsResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForStreams := "[]*TopStream{"
+	for _, f := range this.Streams {
+		repeatedStringForStreams += strings.Replace(f.String(), "TopStream", "TopStream", 1) + ","
+	}
+	repeatedStringForStreams += "}"
+	s := strings.Join([]string{`&TopStreamsResponse{`,
+		`Streams:` + repeatedStringForStreams + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TopStream) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TopStream{`,
+		`Labels:` + fmt.Sprintf("%v", this.Labels) + `,`,
+		`BytesRate:` + fmt.Sprintf("%v", this.BytesRate) + `,`,
+		`EntriesRate:` + fmt.Sprintf("%v", this.EntriesRate) + `,`,
+		`MemoryBytes:` + fmt.Sprintf("%v", this.MemoryBytes) + `,`,
+		`Chunks:` + fmt.Sprintf("%v", this.Chunks) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringLogproto(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *StreamRatesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamRatesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamRatesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StreamRatesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamRatesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamRatesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StreamRates", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.StreamRates = append(m.StreamRates, &StreamRate{})
+			if err := m.StreamRates[len(m.StreamRates)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StreamRate) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StreamRate: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StreamRate: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StreamHash", wireType)
+			}
+			m.StreamHash = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StreamHash |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StreamHashNoShard", wireType)
+			}
+			m.StreamHashNoShard = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StreamHashNoShard |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Rate", wireType)
+			}
+			m.Rate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Rate |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tenant", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tenant = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field EntriesRate", wireType)
+			}
+			m.EntriesRate = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.EntriesRate |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantVolumesRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantVolumesRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantVolumesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TenantVolumesResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TenantVolumesResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TenantVolumesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TenantVolumes", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TenantVolumes = append(m.TenantVolumes, &TenantVolume{})
+			if err := m.TenantVolumes[len(m.TenantVolumes)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *StreamRatesRequest) Unmarshal(dAtA []byte) error {
+func (m *TenantVolume) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8119,22 +10574,127 @@ func (m *StreamRatesRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StreamRatesRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TenantVolume: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StreamRatesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TenantVolume: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tenant", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tenant = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Day", wireType)
+			}
+			m.Day = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Day |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DayBytes", wireType)
+			}
+			m.DayBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DayBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Month", wireType)
+			}
+			m.Month = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Month |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MonthBytes", wireType)
+			}
+			m.MonthBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MonthBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -8149,7 +10709,7 @@ func (m *StreamRatesRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StreamRatesResponse) Unmarshal(dAtA []byte) error {
+func (m *PushStreamRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8172,15 +10732,34 @@ func (m *StreamRatesResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StreamRatesResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: PushStreamRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StreamRatesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PushStreamRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BatchID", wireType)
+			}
+			m.BatchID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BatchID |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StreamRates", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Request", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -8207,8 +10786,10 @@ func (m *StreamRatesResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.StreamRates = append(m.StreamRates, &StreamRate{})
-			if err := m.StreamRates[len(m.StreamRates)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if m.Request == nil {
+				m.Request = &push.PushRequest{}
+			}
+			if err := m.Request.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -8218,10 +10799,7 @@ func (m *StreamRatesResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -8236,7 +10814,7 @@ func (m *StreamRatesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *StreamRate) Unmarshal(dAtA []byte) error {
+func (m *PushStreamResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -8259,17 +10837,17 @@ func (m *StreamRate) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: StreamRate: wiretype end group for non-group")
+			return fmt.Errorf("proto: PushStreamResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: StreamRate: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: PushStreamResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StreamHash", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field BatchID", wireType)
 			}
-			m.StreamHash = 0
+			m.BatchID = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -8279,16 +10857,16 @@ func (m *StreamRate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StreamHash |= uint64(b&0x7F) << shift
+				m.BatchID |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field StreamHashNoShard", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Code", wireType)
 			}
-			m.StreamHashNoShard = 0
+			m.Code = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -8298,33 +10876,14 @@ func (m *StreamRate) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.StreamHashNoShard |= uint64(b&0x7F) << shift
+				m.Code |= int32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Rate", wireType)
-			}
-			m.Rate = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowLogproto
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Rate |= int64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Tenant", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Error", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -8352,7 +10911,7 @@ func (m *StreamRate) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Tenant = string(dAtA[iNdEx:postIndex])
+			m.Error = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -8360,10 +10919,7 @@ func (m *StreamRate) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -8615,10 +11171,7 @@ func (m *QueryRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -8832,10 +11385,7 @@ func (m *SampleQueryRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -8955,10 +11505,7 @@ func (m *Delete) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -9075,10 +11622,7 @@ func (m *QueryResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -9195,10 +11739,7 @@ func (m *SampleQueryResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -9404,10 +11945,7 @@ func (m *LabelRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -9489,10 +12027,7 @@ func (m *LabelResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -9591,10 +12126,7 @@ func (m *Sample) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -9674,10 +12206,7 @@ func (m *LegacySample) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -9812,10 +12341,7 @@ func (m *Series) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -9929,11 +12455,44 @@ func (m *TailRequest) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		case 5:
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Start, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Cursor", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -9943,24 +12502,23 @@ func (m *TailRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Start, dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Cursor = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -9968,10 +12526,7 @@ func (m *TailRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -10091,10 +12646,7 @@ func (m *TailResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -10274,10 +12826,7 @@ func (m *SeriesRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -10361,10 +12910,7 @@ func (m *SeriesResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -10524,7 +13070,7 @@ func (m *SeriesIdentifier) Unmarshal(dAtA []byte) error {
 					if err != nil {
 						return err
 					}
-					if skippy < 0 {
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
 						return ErrInvalidLengthLogproto
 					}
 					if (iNdEx + skippy) > postIndex {
@@ -10541,10 +13087,7 @@ func (m *SeriesIdentifier) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -10692,10 +13235,7 @@ func (m *DroppedStream) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -10877,10 +13417,241 @@ func (m *TimeSeriesChunk) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HandoffStreamsRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HandoffStreamsRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HandoffStreamsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FromIngesterId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FromIngesterId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field UserId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.UserId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stream", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Stream == nil {
+				m.Stream = &github_com_grafana_loki_pkg_push.Stream{}
+			}
+			if err := m.Stream.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Chunks = append(m.Chunks, &Chunk{})
+			if err := m.Chunks[len(m.Chunks)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			if (iNdEx + skippy) < 0 {
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *HandoffStreamsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: HandoffStreamsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: HandoffStreamsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -10994,10 +13765,7 @@ func (m *LabelPair) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11115,10 +13883,7 @@ func (m *LegacyLabelPair) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11202,10 +13967,7 @@ func (m *Chunk) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11255,10 +14017,7 @@ func (m *TransferChunksResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11308,10 +14067,7 @@ func (m *TailersCountRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11380,10 +14136,7 @@ func (m *TailersCountResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11531,10 +14284,7 @@ func (m *GetChunkIDsRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11616,10 +14366,7 @@ func (m *GetChunkIDsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11777,10 +14524,7 @@ func (m *ChunkRef) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11964,10 +14708,247 @@ func (m *LabelValuesForMetricNameRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LabelNamesForMetricNameRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LabelNamesForMetricNameRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LabelNamesForMetricNameRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MetricName", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.MetricName = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			m.From = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.From |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Through", wireType)
+			}
+			m.Through = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Through |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *GetChunkRefRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: GetChunkRefRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: GetChunkRefRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			m.From = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.From |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Through", wireType)
+			}
+			m.Through = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Through |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			if (iNdEx + skippy) < 0 {
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Matchers = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -11982,7 +14963,7 @@ func (m *LabelValuesForMetricNameRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LabelNamesForMetricNameRequest) Unmarshal(dAtA []byte) error {
+func (m *GetChunkRefResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12005,17 +14986,17 @@ func (m *LabelNamesForMetricNameRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LabelNamesForMetricNameRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: GetChunkRefResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LabelNamesForMetricNameRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: GetChunkRefResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MetricName", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Refs", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -12025,72 +15006,33 @@ func (m *LabelNamesForMetricNameRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.MetricName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
-			}
-			m.From = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowLogproto
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.From |= github_com_prometheus_common_model.Time(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Through", wireType)
-			}
-			m.Through = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowLogproto
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.Through |= github_com_prometheus_common_model.Time(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			m.Refs = append(m.Refs, &ChunkRef{})
+			if err := m.Refs[len(m.Refs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
 			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12105,7 +15047,7 @@ func (m *LabelNamesForMetricNameRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GetChunkRefRequest) Unmarshal(dAtA []byte) error {
+func (m *GetSeriesRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12128,10 +15070,10 @@ func (m *GetChunkRefRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetChunkRefRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: GetSeriesRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetChunkRefRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: GetSeriesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
@@ -12210,10 +15152,7 @@ func (m *GetChunkRefRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12228,7 +15167,7 @@ func (m *GetChunkRefRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GetChunkRefResponse) Unmarshal(dAtA []byte) error {
+func (m *GetSeriesResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12251,15 +15190,15 @@ func (m *GetChunkRefResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetChunkRefResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: GetSeriesResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetChunkRefResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: GetSeriesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Refs", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Series", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -12286,8 +15225,8 @@ func (m *GetChunkRefResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Refs = append(m.Refs, &ChunkRef{})
-			if err := m.Refs[len(m.Refs)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Series = append(m.Series, IndexSeries{})
+			if err := m.Series[len(m.Series)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -12297,10 +15236,7 @@ func (m *GetChunkRefResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12315,7 +15251,7 @@ func (m *GetChunkRefResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GetSeriesRequest) Unmarshal(dAtA []byte) error {
+func (m *IndexSeries) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12338,17 +15274,17 @@ func (m *GetSeriesRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetSeriesRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: IndexSeries: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetSeriesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: IndexSeries: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
 			}
-			m.From = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -12358,16 +15294,81 @@ func (m *GetSeriesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.From |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Through", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthLogproto
 			}
-			m.Through = 0
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = append(m.Labels, LabelAdapter{})
+			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *QueryIndexResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryIndexResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryIndexResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field QueryKey", wireType)
+			}
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -12377,16 +15378,29 @@ func (m *GetSeriesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Through |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.QueryKey = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Rows", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -12396,23 +15410,25 @@ func (m *GetSeriesRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Matchers = string(dAtA[iNdEx:postIndex])
+			m.Rows = append(m.Rows, &Row{})
+			if err := m.Rows[len(m.Rows)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -12420,10 +15436,7 @@ func (m *GetSeriesRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12438,7 +15451,7 @@ func (m *GetSeriesRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *GetSeriesResponse) Unmarshal(dAtA []byte) error {
+func (m *Row) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12461,17 +15474,17 @@ func (m *GetSeriesResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: GetSeriesResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: Row: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: GetSeriesResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Row: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Series", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RangeValue", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -12481,24 +15494,58 @@ func (m *GetSeriesResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				byteLen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Series = append(m.Series, IndexSeries{})
-			if err := m.Series[len(m.Series)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.RangeValue = append(m.RangeValue[:0], dAtA[iNdEx:postIndex]...)
+			if m.RangeValue == nil {
+				m.RangeValue = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -12507,10 +15554,7 @@ func (m *GetSeriesResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12525,7 +15569,7 @@ func (m *GetSeriesResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *IndexSeries) Unmarshal(dAtA []byte) error {
+func (m *QueryIndexRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12548,15 +15592,15 @@ func (m *IndexSeries) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: IndexSeries: wiretype end group for non-group")
+			return fmt.Errorf("proto: QueryIndexRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: IndexSeries: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: QueryIndexRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Queries", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -12583,8 +15627,8 @@ func (m *IndexSeries) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Labels = append(m.Labels, LabelAdapter{})
-			if err := m.Labels[len(m.Labels)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			m.Queries = append(m.Queries, &IndexQuery{})
+			if err := m.Queries[len(m.Queries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -12594,10 +15638,7 @@ func (m *IndexSeries) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12612,7 +15653,7 @@ func (m *IndexSeries) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryIndexResponse) Unmarshal(dAtA []byte) error {
+func (m *IndexQuery) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12635,15 +15676,15 @@ func (m *QueryIndexResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryIndexResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: IndexQuery: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryIndexResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: IndexQuery: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field QueryKey", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field TableName", wireType)
 			}
 			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
@@ -12671,13 +15712,13 @@ func (m *QueryIndexResponse) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.QueryKey = string(dAtA[iNdEx:postIndex])
+			m.TableName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Rows", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field HashValue", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -12687,82 +15728,61 @@ func (m *QueryIndexResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Rows = append(m.Rows, &Row{})
-			if err := m.Rows[len(m.Rows)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.HashValue = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipLogproto(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RangeValuePrefix", wireType)
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *Row) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowLogproto
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
+			m.RangeValuePrefix = append(m.RangeValuePrefix[:0], dAtA[iNdEx:postIndex]...)
+			if m.RangeValuePrefix == nil {
+				m.RangeValuePrefix = []byte{}
 			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: Row: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Row: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			iNdEx = postIndex
+		case 4:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RangeValue", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field RangeValueStart", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -12789,14 +15809,14 @@ func (m *Row) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.RangeValue = append(m.RangeValue[:0], dAtA[iNdEx:postIndex]...)
-			if m.RangeValue == nil {
-				m.RangeValue = []byte{}
+			m.RangeValueStart = append(m.RangeValueStart[:0], dAtA[iNdEx:postIndex]...)
+			if m.RangeValueStart == nil {
+				m.RangeValueStart = []byte{}
 			}
 			iNdEx = postIndex
-		case 2:
+		case 5:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ValueEqual", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -12823,9 +15843,9 @@ func (m *Row) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
-			if m.Value == nil {
-				m.Value = []byte{}
+			m.ValueEqual = append(m.ValueEqual[:0], dAtA[iNdEx:postIndex]...)
+			if m.ValueEqual == nil {
+				m.ValueEqual = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -12834,10 +15854,7 @@ func (m *Row) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12852,7 +15869,7 @@ func (m *Row) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *QueryIndexRequest) Unmarshal(dAtA []byte) error {
+func (m *IndexStatsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12875,17 +15892,55 @@ func (m *QueryIndexRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: QueryIndexRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: IndexStatsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: QueryIndexRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: IndexStatsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			}
+			m.From = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.From |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Through", wireType)
+			}
+			m.Through = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Through |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Queries", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -12895,25 +15950,23 @@ func (m *QueryIndexRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Queries = append(m.Queries, &IndexQuery{})
-			if err := m.Queries[len(m.Queries)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
+			m.Matchers = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -12921,10 +15974,7 @@ func (m *QueryIndexRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -12939,7 +15989,7 @@ func (m *QueryIndexRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *IndexQuery) Unmarshal(dAtA []byte) error {
+func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -12962,49 +16012,17 @@ func (m *IndexQuery) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: IndexQuery: wiretype end group for non-group")
+			return fmt.Errorf("proto: IndexStatsResponse: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: IndexQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: IndexStatsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field TableName", wireType)
-			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowLogproto
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.TableName = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field HashValue", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Streams", wireType)
 			}
-			var stringLen uint64
+			m.Streams = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13014,29 +16032,16 @@ func (m *IndexQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				m.Streams |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
-				}
-			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
+				}
 			}
-			m.HashValue = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
-		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RangeValuePrefix", wireType)
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
 			}
-			var byteLen int
+			m.Chunks = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13046,31 +16051,16 @@ func (m *IndexQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				m.Chunks |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.RangeValuePrefix = append(m.RangeValuePrefix[:0], dAtA[iNdEx:postIndex]...)
-			if m.RangeValuePrefix == nil {
-				m.RangeValuePrefix = []byte{}
-			}
-			iNdEx = postIndex
-		case 4:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RangeValueStart", wireType)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bytes", wireType)
 			}
-			var byteLen int
+			m.Bytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13080,31 +16070,16 @@ func (m *IndexQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				m.Bytes |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.RangeValueStart = append(m.RangeValueStart[:0], dAtA[iNdEx:postIndex]...)
-			if m.RangeValueStart == nil {
-				m.RangeValueStart = []byte{}
-			}
-			iNdEx = postIndex
-		case 5:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ValueEqual", wireType)
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Entries", wireType)
 			}
-			var byteLen int
+			m.Entries = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13114,36 +16089,18 @@ func (m *IndexQuery) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= int(b&0x7F) << shift
+				m.Entries |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.ValueEqual = append(m.ValueEqual[:0], dAtA[iNdEx:postIndex]...)
-			if m.ValueEqual == nil {
-				m.ValueEqual = []byte{}
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLogproto(dAtA[iNdEx:])
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13158,7 +16115,7 @@ func (m *IndexQuery) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *IndexStatsRequest) Unmarshal(dAtA []byte) error {
+func (m *TopStreamsRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -13181,17 +16138,17 @@ func (m *IndexStatsRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: IndexStatsRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: TopStreamsRequest: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: IndexStatsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TopStreamsRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field From", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SortBy", wireType)
 			}
-			m.From = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13201,16 +16158,29 @@ func (m *IndexStatsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.From |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SortBy = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Through", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Limit", wireType)
 			}
-			m.Through = 0
+			m.Limit = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13220,16 +16190,66 @@ func (m *IndexStatsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Through |= github_com_prometheus_common_model.Time(b&0x7F) << shift
+				m.Limit |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 3:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipLogproto(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TopStreamsResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowLogproto
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TopStreamsResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TopStreamsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Matchers", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Streams", wireType)
 			}
-			var stringLen uint64
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13239,23 +16259,25 @@ func (m *IndexStatsRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
+				msglen |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
+			if msglen < 0 {
 				return ErrInvalidLengthLogproto
 			}
-			postIndex := iNdEx + intStringLen
+			postIndex := iNdEx + msglen
 			if postIndex < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Matchers = string(dAtA[iNdEx:postIndex])
+			m.Streams = append(m.Streams, &TopStream{})
+			if err := m.Streams[len(m.Streams)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -13263,10 +16285,7 @@ func (m *IndexStatsRequest) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13281,7 +16300,7 @@ func (m *IndexStatsRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
+func (m *TopStream) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -13304,17 +16323,17 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: IndexStatsResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: TopStream: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: IndexStatsResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: TopStream: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Streams", wireType)
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Labels", wireType)
 			}
-			m.Streams = 0
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13324,16 +16343,29 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Streams |= uint64(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLogproto
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Labels = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field BytesRate", wireType)
 			}
-			m.Chunks = 0
+			m.BytesRate = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13343,16 +16375,16 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Chunks |= uint64(b&0x7F) << shift
+				m.BytesRate |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 3:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Bytes", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field EntriesRate", wireType)
 			}
-			m.Bytes = 0
+			m.EntriesRate = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13362,16 +16394,16 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Bytes |= uint64(b&0x7F) << shift
+				m.EntriesRate |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
 		case 4:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Entries", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MemoryBytes", wireType)
 			}
-			m.Entries = 0
+			m.MemoryBytes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowLogproto
@@ -13381,7 +16413,26 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Entries |= uint64(b&0x7F) << shift
+				m.MemoryBytes |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Chunks", wireType)
+			}
+			m.Chunks = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLogproto
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Chunks |= int64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
@@ -13392,10 +16443,7 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 			if err != nil {
 				return err
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthLogproto
-			}
-			if (iNdEx + skippy) < 0 {
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
 				return ErrInvalidLengthLogproto
 			}
 			if (iNdEx + skippy) > l {
@@ -13413,6 +16461,7 @@ func (m *IndexStatsResponse) Unmarshal(dAtA []byte) error {
 func skipLogproto(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
+	depth := 0
 	for iNdEx < l {
 		var wire uint64
 		for shift := uint(0); ; shift += 7 {
@@ -13444,10 +16493,8 @@ func skipLogproto(dAtA []byte) (n int, err error) {
 					break
 				}
 			}
-			return iNdEx, nil
 		case 1:
 			iNdEx += 8
-			return iNdEx, nil
 		case 2:
 			var length int
 			for shift := uint(0); ; shift += 7 {
@@ -13468,55 +16515,30 @@ func skipLogproto(dAtA []byte) (n int, err error) {
 				return 0, ErrInvalidLengthLogproto
 			}
 			iNdEx += length
-			if iNdEx < 0 {
-				return 0, ErrInvalidLengthLogproto
-			}
-			return iNdEx, nil
 		case 3:
-			for {
-				var innerWire uint64
-				var start int = iNdEx
-				for shift := uint(0); ; shift += 7 {
-					if shift >= 64 {
-						return 0, ErrIntOverflowLogproto
-					}
-					if iNdEx >= l {
-						return 0, io.ErrUnexpectedEOF
-					}
-					b := dAtA[iNdEx]
-					iNdEx++
-					innerWire |= (uint64(b) & 0x7F) << shift
-					if b < 0x80 {
-						break
-					}
-				}
-				innerWireType := int(innerWire & 0x7)
-				if innerWireType == 4 {
-					break
-				}
-				next, err := skipLogproto(dAtA[start:])
-				if err != nil {
-					return 0, err
-				}
-				iNdEx = start + next
-				if iNdEx < 0 {
-					return 0, ErrInvalidLengthLogproto
-				}
-			}
-			return iNdEx, nil
+			depth++
 		case 4:
-			return iNdEx, nil
+			if depth == 0 {
+				return 0, ErrUnexpectedEndOfGroupLogproto
+			}
+			depth--
 		case 5:
 			iNdEx += 4
-			return iNdEx, nil
 		default:
 			return 0, fmt.Errorf("proto: illegal wireType %d", wireType)
 		}
+		if iNdEx < 0 {
+			return 0, ErrInvalidLengthLogproto
+		}
+		if depth == 0 {
+			return iNdEx, nil
+		}
 	}
-	panic("unreachable")
+	return 0, io.ErrUnexpectedEOF
 }
 
 var (
-	ErrInvalidLengthLogproto = fmt.Errorf("proto: negative length found during unmarshaling")
-	ErrIntOverflowLogproto   = fmt.Errorf("proto: integer overflow")
+	ErrInvalidLengthLogproto        = fmt.Errorf("proto: negative length found during unmarshaling")
+	ErrIntOverflowLogproto          = fmt.Errorf("proto: integer overflow")
+	ErrUnexpectedEndOfGroupLogproto = fmt.Errorf("proto: unexpected end of group")
 )
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/prometheus/prometheus/promql"
+	"github.com/weaveworks/common/httpgrpc"
 
 	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logql/syntax"
@@ -16,6 +19,7 @@ import (
 	"github.com/grafana/loki/pkg/logqlmodel/metadata"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/querier/astmapper"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
 	"github.com/grafana/loki/pkg/util"
 	util_log "github.com/grafana/loki/pkg/util/log"
 )
@@ -207,9 +211,43 @@ type DownstreamEvaluator struct {
 	defaultEvaluator Evaluator
 }
 
+type partialResultsKey struct{}
+
+// WithPartialResults returns a context in which the DownstreamEvaluator leaves out the results of
+// failing sharded queries instead of failing the whole query. The missing shards are reported as
+// warnings of the query result.
+func WithPartialResults(ctx context.Context) context.Context {
+	return context.WithValue(ctx, partialResultsKey{}, true)
+}
+
+func partialResultsAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(partialResultsKey{}).(bool)
+	return allowed
+}
+
+// IsPartialResultError returns whether a part of a query that failed with the given error may be
+// left out of a partial result. Client errors, e.g. exceeded limits, still fail the whole query.
+func IsPartialResultError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if resp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+		return resp.Code/100 == 5
+	}
+	return true
+}
+
 // Downstream runs queries and collects stats from the embedded Downstreamer
 func (ev DownstreamEvaluator) Downstream(ctx context.Context, queries []DownstreamQuery) ([]logqlmodel.Result, error) {
-	results, err := ev.Downstreamer.Downstream(ctx, queries)
+	var (
+		results []logqlmodel.Result
+		err     error
+	)
+	if len(queries) > 1 && partialResultsAllowed(ctx) {
+		results, err = ev.downstreamPartial(ctx, queries)
+	} else {
+		results, err = ev.Downstreamer.Downstream(ctx, queries)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -233,6 +271,64 @@ func (ev DownstreamEvaluator) Downstream(ctx context.Context, queries []Downstre
 		}
 	}
 
+	for _, res := range results {
+		if err := metadata.JoinWarnings(ctx, res.Warnings); err != nil {
+			level.Warn(util_log.Logger).Log("msg", "unable to add warnings to results context", "error", err)
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// downstreamPartial runs each query separately, so that a failing query does not cancel the others.
+// The result of a failed query has no data and a warning describing the missing shard.
+// It fails if all queries fail or if any query fails with an error that does not allow partial results.
+func (ev DownstreamEvaluator) downstreamPartial(ctx context.Context, queries []DownstreamQuery) ([]logqlmodel.Result, error) {
+	var (
+		wg      sync.WaitGroup
+		results = make([]logqlmodel.Result, len(queries))
+		errs    = make([]error, len(queries))
+	)
+	for i := range queries {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := ev.Downstreamer.Downstream(ctx, queries[i:i+1])
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			results[i] = res[0]
+		}(i)
+	}
+	wg.Wait()
+
+	var failed int
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil || !IsPartialResultError(err) {
+			return nil, err
+		}
+		failed++
+
+		shards := strings.Join(queries[i].Shards.Encode(), ",")
+		level.Warn(util_log.Logger).Log("msg", "leaving out failed shard from partial result", "shards", shards, "err", err)
+		results[i] = logqlmodel.Result{
+			Warnings: []definitions.QueryWarning{{
+				Message: fmt.Sprintf("results of shard %s are missing: %s", shards, err),
+				Start:   queries[i].Params.Start(),
+				End:     queries[i].Params.End(),
+				Shard:   shards,
+			}},
+		}
+	}
+	if failed == len(queries) {
+		return nil, errs[0]
+	}
+
 	return results, nil
 }
 
@@ -300,6 +396,10 @@ func (ev *DownstreamEvaluator) StepEvaluator(
 
 		xs := make([]StepEvaluator, 0, len(queries))
 		for i, res := range results {
+			if res.Data == nil {
+				// The shard is missing from a partial result.
+				continue
+			}
 			stepper, err := ResultStepEvaluator(res, params)
 			if err != nil {
 				level.Warn(util_log.Logger).Log(
@@ -364,6 +464,10 @@ func (ev *DownstreamEvaluator) Iterator(
 
 		xs := make([]iter.EntryIterator, 0, len(results))
 		for i, res := range results {
+			if res.Data == nil {
+				// The shard is missing from a partial result.
+				continue
+			}
 			iter, err := ResultIterator(res, params)
 			if err != nil {
 				level.Warn(util_log.Logger).Log(
@@ -2,6 +2,7 @@ package logql
 
 import (
 	"context"
+	"errors"
 	"math"
 	"testing"
 	"time"
@@ -10,9 +11,11 @@ import (
 	"github.com/prometheus/prometheus/promql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
 	"github.com/weaveworks/common/user"
 
 	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logqlmodel"
 )
 
 var nilShardMetrics = NewShardMapperMetrics(nil)
@@ -163,6 +166,105 @@ func TestShardCounter(t *testing.T) {
 	}
 }
 
+// failingShardDownstreamer fails all queries of a given shard.
+type failingShardDownstreamer struct {
+	MockDownstreamer
+	shard string
+	err   error
+}
+
+func (m failingShardDownstreamer) Downstreamer(_ context.Context) Downstreamer { return m }
+
+func (m failingShardDownstreamer) Downstream(ctx context.Context, queries []DownstreamQuery) ([]logqlmodel.Result, error) {
+	for _, query := range queries {
+		for _, shard := range query.Shards.Encode() {
+			if shard == m.shard {
+				return nil, m.err
+			}
+		}
+	}
+	return m.MockDownstreamer.Downstream(ctx, queries)
+}
+
+func TestPartialResults(t *testing.T) {
+	var (
+		shards   = 3
+		nStreams = 60
+		rounds   = 20
+		streams  = randomStreams(nStreams, rounds+1, shards, []string{"a", "b", "c", "d"})
+		start    = time.Unix(0, 0)
+		end      = time.Unix(0, int64(time.Second*time.Duration(rounds)))
+	)
+
+	for _, tc := range []struct {
+		desc      string
+		query     string
+		err       error
+		partial   bool
+		expectErr bool
+	}{
+		{
+			desc:      "disabled",
+			query:     `sum by (a) (rate({a=~".+"}[1s]))`,
+			err:       errors.New("querier timeout"),
+			expectErr: true,
+		},
+		{
+			desc:    "metric query",
+			query:   `sum by (a) (rate({a=~".+"}[1s]))`,
+			err:     errors.New("querier timeout"),
+			partial: true,
+		},
+		{
+			desc:    "log query",
+			query:   `{a=~".+"}`,
+			err:     httpgrpc.Errorf(500, "querier timeout"),
+			partial: true,
+		},
+		{
+			desc:      "client error",
+			query:     `sum by (a) (rate({a=~".+"}[1s]))`,
+			err:       httpgrpc.Errorf(400, "limit exceeded"),
+			partial:   true,
+			expectErr: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			q := NewMockQuerier(shards, streams)
+			limits := &fakeLimits{maxSeries: math.MaxInt32, timeout: time.Minute}
+			regular := NewEngine(EngineOpts{}, q, limits, log.NewNopLogger())
+			downstreamer := failingShardDownstreamer{
+				MockDownstreamer: MockDownstreamer{regular},
+				shard:            "1_of_3",
+				err:              tc.err,
+			}
+			sharded := NewDownstreamEngine(EngineOpts{}, downstreamer, limits, log.NewNopLogger())
+
+			params := NewLiteralParams(tc.query, start, end, time.Second, 0, logproto.FORWARD, 1000, nil)
+			ctx := user.InjectOrgID(context.Background(), "fake")
+			if tc.partial {
+				ctx = WithPartialResults(ctx)
+			}
+
+			mapper := NewShardMapper(ConstantShards(shards), nilShardMetrics)
+			_, _, mapped, err := mapper.Parse(tc.query)
+			require.NoError(t, err)
+
+			res, err := sharded.Query(ctx, params, mapped).Exec(ctx)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, res.Data)
+			require.Len(t, res.Warnings, 1)
+			require.Equal(t, "1_of_3", res.Warnings[0].Shard)
+			require.Equal(t, start, res.Warnings[0].Start)
+			require.Equal(t, end, res.Warnings[0].End)
+		})
+	}
+}
+
 func TestRangeMappingEquivalence(t *testing.T) {
 	var (
 		shards   = 3
@@ -88,6 +88,10 @@ type LabelsBuilder struct {
 	currentResult LabelsResult
 	groupedResult LabelsResult
 
+	// The structured metadata of the entry being processed. It can be read by label filters and
+	// formatters like a label, but isn't part of the resulting labels.
+	metadata labels.Labels
+
 	*BaseLabelsBuilder
 }
 
@@ -139,6 +143,7 @@ func (b *BaseLabelsBuilder) ForLabels(lbs labels.Labels, hash uint64) *LabelsBui
 func (b *LabelsBuilder) Reset() {
 	b.del = b.del[:0]
 	b.add = b.add[:0]
+	b.metadata = nil
 	b.err = ""
 	b.errDetails = ""
 	b.parserKeyHints.Reset()
@@ -195,6 +200,7 @@ func (b *LabelsBuilder) BaseHas(key string) bool {
 }
 
 // Get returns the value of a labels key if it exists.
+// The structured metadata of the entry is returned for keys that aren't labels.
 func (b *LabelsBuilder) Get(key string) (string, bool) {
 	for _, a := range b.add {
 		if a.Name == key {
@@ -212,9 +218,23 @@ func (b *LabelsBuilder) Get(key string) (string, bool) {
 			return l.Value, true
 		}
 	}
+	for _, m := range b.metadata {
+		if m.Name == key {
+			return m.Value, true
+		}
+	}
 	return "", false
 }
 
+func (b *LabelsBuilder) deleted(name string) bool {
+	for _, d := range b.del {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
 // Del deletes the label of the given name.
 func (b *LabelsBuilder) Del(ns ...string) *LabelsBuilder {
 	for _, n := range ns {
@@ -244,6 +264,12 @@ func (b *LabelsBuilder) Set(n, v string) *LabelsBuilder {
 	return b
 }
 
+// SetMetadata sets the structured metadata of the entry being processed until the next Reset.
+func (b *LabelsBuilder) SetMetadata(metadata labels.Labels) *LabelsBuilder {
+	b.metadata = metadata
+	return b
+}
+
 // Labels returns the labels from the builder. If no modifications
 // were made, the original labels are returned.
 func (b *LabelsBuilder) labels() labels.Labels {
@@ -298,8 +324,10 @@ Outer:
 	return b.appendErrors(buf)
 }
 
+// Map returns the labels and the structured metadata of the entry as a map.
+// Labels take precedence over structured metadata of the same name.
 func (b *LabelsBuilder) Map() map[string]string {
-	if len(b.del) == 0 && len(b.add) == 0 && b.err == "" {
+	if len(b.del) == 0 && len(b.add) == 0 && b.err == "" && len(b.metadata) == 0 {
 		if b.baseMap == nil {
 			b.baseMap = b.base.Map()
 		}
@@ -308,7 +336,12 @@ func (b *LabelsBuilder) Map() map[string]string {
 	b.buf = b.UnsortedLabels(b.buf)
 	// todo should we also cache maps since limited by the result ?
 	// Maps also don't create a copy of the labels.
-	res := make(map[string]string, len(b.buf))
+	res := make(map[string]string, len(b.buf)+len(b.metadata))
+	for _, m := range b.metadata {
+		if !b.deleted(m.Name) {
+			res[m.Name] = m.Value
+		}
+	}
 	for _, l := range b.buf {
 		res[l.Name] = l.Value
 	}
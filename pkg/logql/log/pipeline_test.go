@@ -51,6 +51,28 @@ func TestPipeline(t *testing.T) {
 	require.Equal(t, false, matches)
 }
 
+func TestPipelineWithStructuredMetadata(t *testing.T) {
+	lbs := labels.Labels{{Name: "foo", Value: "bar"}}
+	metadata := labels.Labels{{Name: "trace_id", Value: "123"}}
+	p := NewPipeline([]Stage{
+		NewStringLabelFilter(labels.MustNewMatcher(labels.MatchEqual, "trace_id", "123")),
+		newMustLineFormatter("trace {{.trace_id}}"),
+	})
+
+	l, lbr, matches := p.ForStream(lbs).Process(0, []byte("line"), metadata...)
+	require.Equal(t, []byte("trace 123"), l)
+	require.Equal(t, NewLabelsResult(lbs, lbs.Hash()), lbr)
+	require.Equal(t, true, matches)
+
+	_, _, matches = p.ForStream(lbs).Process(0, []byte("line"))
+	require.Equal(t, false, matches)
+
+	l, lbr, matches = NewNoopPipeline().ForStream(lbs).Process(0, []byte("line"), metadata...)
+	require.Equal(t, []byte("line"), l)
+	require.Equal(t, NewLabelsResult(lbs, lbs.Hash()), lbr)
+	require.Equal(t, true, matches)
+}
+
 func TestFilteringPipeline(t *testing.T) {
 	p := NewFilteringPipeline([]PipelineFilter{
 		newPipelineFilter(2, 4, labels.Labels{{Name: "foo", Value: "bar"}, {Name: "bar", Value: "baz"}}, "e"),
@@ -119,11 +141,11 @@ func (p *stubStreamPipeline) BaseLabels() LabelsResult {
 	return nil
 }
 
-func (p *stubStreamPipeline) Process(ts int64, line []byte) ([]byte, LabelsResult, bool) {
+func (p *stubStreamPipeline) Process(ts int64, line []byte, _ ...labels.Label) ([]byte, LabelsResult, bool) {
 	return nil, nil, true
 }
 
-func (p *stubStreamPipeline) ProcessString(ts int64, line string) (string, LabelsResult, bool) {
+func (p *stubStreamPipeline) ProcessString(ts int64, line string, _ ...labels.Label) (string, LabelsResult, bool) {
 	return "", nil, true
 }
 
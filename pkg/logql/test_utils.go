@@ -101,7 +101,7 @@ func processStream(in []logproto.Stream, pipeline log.Pipeline) []logproto.Strea
 	for _, stream := range in {
 		for _, e := range stream.Entries {
 			sp := pipeline.ForStream(mustParseLabels(stream.Labels))
-			if l, out, matches := sp.Process(e.Timestamp.UnixNano(), []byte(e.Line)); matches {
+			if l, out, matches := sp.Process(e.Timestamp.UnixNano(), []byte(e.Line), logproto.FromStructuredMetadataToLabels(e.StructuredMetadata)...); matches {
 				var s *logproto.Stream
 				var found bool
 				s, found = resByStream[out.String()]
@@ -110,8 +110,9 @@ func processStream(in []logproto.Stream, pipeline log.Pipeline) []logproto.Strea
 					resByStream[out.String()] = s
 				}
 				s.Entries = append(s.Entries, logproto.Entry{
-					Timestamp: e.Timestamp,
-					Line:      string(l),
+					Timestamp:          e.Timestamp,
+					Line:               string(l),
+					StructuredMetadata: e.StructuredMetadata,
 				})
 			}
 		}
@@ -129,7 +130,7 @@ func processSeries(in []logproto.Stream, ex log.SampleExtractor) []logproto.Seri
 	for _, stream := range in {
 		for _, e := range stream.Entries {
 			exs := ex.ForStream(mustParseLabels(stream.Labels))
-			if f, lbs, ok := exs.Process(e.Timestamp.UnixNano(), []byte(e.Line)); ok {
+			if f, lbs, ok := exs.Process(e.Timestamp.UnixNano(), []byte(e.Line), logproto.FromStructuredMetadataToLabels(e.StructuredMetadata)...); ok {
 				var s *logproto.Series
 				var found bool
 				s, found = resBySeries[lbs.String()]
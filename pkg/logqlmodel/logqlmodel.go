@@ -20,6 +20,8 @@ type Result struct {
 	Data       parser.Value
 	Statistics stats.Result
 	Headers    []*definitions.PrometheusResponseHeader
+	// Warnings describe the parts of the query that are missing from a partial result.
+	Warnings []definitions.QueryWarning
 }
 
 // Streams is promql.Value
@@ -27,8 +27,9 @@ var (
 
 // Context is the metadata context. It is passed through the query path and accumulates metadata.
 type Context struct {
-	mtx     sync.Mutex
-	headers map[string][]string
+	mtx      sync.Mutex
+	headers  map[string][]string
+	warnings []definitions.QueryWarning
 }
 
 // NewContext creates a new metadata context
@@ -94,3 +95,31 @@ func ExtendHeaders(dst map[string][]string, src []*definitions.PrometheusRespons
 		dst[header.Name] = header.Values
 	}
 }
+
+// Warnings returns the warnings about missing parts of a partial result accumulated in the context so far.
+func (c *Context) Warnings() []definitions.QueryWarning {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if len(c.warnings) == 0 {
+		return nil
+	}
+	warnings := make([]definitions.QueryWarning, len(c.warnings))
+	copy(warnings, c.warnings)
+	return warnings
+}
+
+// JoinWarnings adds warnings to the embedded warnings in a context in a concurrency-safe manner.
+func JoinWarnings(ctx context.Context, warnings []definitions.QueryWarning) error {
+	context, ok := ctx.Value(metadataKey).(*Context)
+	if !ok {
+		return ErrNoCtxData
+	}
+
+	context.mtx.Lock()
+	defer context.mtx.Unlock()
+
+	context.warnings = append(context.warnings, warnings...)
+
+	return nil
+}
@@ -56,3 +56,18 @@ func TestHeadersNoKey(t *testing.T) {
 
 	require.True(t, errors.Is(err, ErrNoCtxData))
 }
+
+func TestWarnings(t *testing.T) {
+	w1 := definitions.QueryWarning{Message: "shard 0_of_2 is missing", Shard: "0_of_2"}
+	w2 := definitions.QueryWarning{Message: "shard 1_of_2 is missing", Shard: "1_of_2"}
+
+	metadata, ctx := NewContext(context.Background())
+	require.Nil(t, metadata.Warnings())
+
+	require.Nil(t, JoinWarnings(ctx, []definitions.QueryWarning{w1}))
+	require.Nil(t, JoinWarnings(ctx, []definitions.QueryWarning{w2}))
+	require.Equal(t, []definitions.QueryWarning{w1, w2}, metadata.Warnings())
+
+	err := JoinWarnings(context.Background(), []definitions.QueryWarning{w1})
+	require.True(t, errors.Is(err, ErrNoCtxData))
+}
@@ -228,6 +228,15 @@ func (c *Config) Validate() error {
 	if err := c.LimitsConfig.Validate(); err != nil {
 		return errors.Wrap(err, "invalid limits config")
 	}
+	if err := c.Distributor.CardinalityGuard.ValidateMaxLabelValues(c.LimitsConfig.CardinalityGuardMaxLabelValues); err != nil {
+		return errors.Wrap(err, "invalid limits config")
+	}
+	if err := c.QueryScheduler.Validate(); err != nil {
+		return errors.Wrap(err, "invalid query_scheduler config")
+	}
+	if err := c.Frontend.Audit.Validate(); err != nil {
+		return errors.Wrap(err, "invalid frontend audit config")
+	}
 	if err := c.Worker.Validate(util_log.Logger); err != nil {
 		return errors.Wrap(err, "invalid frontend-worker config")
 	}
@@ -402,6 +411,7 @@ func (t *Loki) setupAuthMiddleware() {
 			"/grpc.health.v1.Health/Check",
 			"/logproto.Ingester/TransferChunks",
 			"/logproto.StreamData/GetStreamRates",
+			"/logproto.TenantVolumes/GetTenantVolumes",
 			"/frontend.Frontend/Process",
 			"/frontend.Frontend/NotifyClientShutdown",
 			"/schedulerpb.SchedulerForFrontend/FrontendLoop",
@@ -2,6 +2,7 @@ package loki
 
 import (
 	"bytes"
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -15,7 +16,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/weaveworks/common/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 
+	"github.com/grafana/loki/pkg/logproto"
 	internalserver "github.com/grafana/loki/pkg/server"
 )
 
@@ -252,3 +257,45 @@ schema_config:
 	require.Equal(t, string(bBytes), "abc")
 	assert.True(t, customHandlerInvoked)
 }
+
+type fakeTenantVolumesServer struct{}
+
+func (fakeTenantVolumesServer) GetTenantVolumes(_ context.Context, _ *logproto.TenantVolumesRequest) (*logproto.TenantVolumesResponse, error) {
+	return &logproto.TenantVolumesResponse{TenantVolumes: []*logproto.TenantVolume{{Tenant: "tenant", DayBytes: 1}}}, nil
+}
+
+func (fakeTenantVolumesServer) Push(_ context.Context, _ *logproto.PushRequest) (*logproto.PushResponse, error) {
+	return &logproto.PushResponse{}, nil
+}
+
+func TestLoki_AuthMiddlewareAllowsTenantVolumesWithoutOrgID(t *testing.T) {
+	loki := &Loki{Cfg: Config{AuthEnabled: true}}
+	loki.setupAuthMiddleware()
+
+	listener := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(loki.Cfg.Server.GRPCMiddleware...),
+		grpc.ChainStreamInterceptor(loki.Cfg.Server.GRPCStreamMiddleware...),
+	)
+	logproto.RegisterTenantVolumesServer(srv, fakeTenantVolumesServer{})
+	logproto.RegisterPusherServer(srv, fakeTenantVolumesServer{})
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+	defer srv.Stop()
+
+	conn, err := grpc.DialContext(context.Background(), "", grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithContextDialer(func(_ context.Context, _ string) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The distributors poll the volumes of all tenants without an org ID, like the quota store does.
+	resp, err := logproto.NewTenantVolumesClient(conn).GetTenantVolumes(context.Background(), &logproto.TenantVolumesRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.TenantVolumes, 1)
+
+	// Methods that aren't exempt are still rejected without an org ID.
+	_, err = logproto.NewPusherClient(conn).Push(context.Background(), &logproto.PushRequest{})
+	require.Error(t, err)
+}
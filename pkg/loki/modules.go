@@ -34,8 +34,10 @@ import (
 
 	"github.com/grafana/loki/pkg/distributor"
 	"github.com/grafana/loki/pkg/ingester"
+	"github.com/grafana/loki/pkg/loghttp/push"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/lokifrontend/audit"
 	"github.com/grafana/loki/pkg/lokifrontend/frontend"
 	"github.com/grafana/loki/pkg/lokifrontend/frontend/transport"
 	"github.com/grafana/loki/pkg/lokifrontend/frontend/v1/frontendv1pb"
@@ -229,7 +231,7 @@ func (t *Loki) initRuntimeConfig() (services.Service, error) {
 		return nil, nil
 	}
 
-	t.Cfg.RuntimeConfig.Loader = loadRuntimeConfig
+	t.Cfg.RuntimeConfig.Loader = runtimeConfigLoader(t.Cfg)
 
 	// make sure to set default limits before we start loading configuration into memory
 	validation.SetDefaultLimitsForYAMLUnmarshalling(t.Cfg.LimitsConfig)
@@ -298,6 +300,7 @@ func (t *Loki) initDistributor() (services.Service, error) {
 	if !t.Cfg.isModuleEnabled(All) && !t.Cfg.isModuleEnabled(Write) && !t.Cfg.isModuleEnabled(Ingester) {
 		logproto.RegisterPusherServer(t.Server.GRPC, t.distributor)
 	}
+	logproto.RegisterStreamPusherServer(t.Server.GRPC, t.distributor)
 
 	// If the querier module is not part of this process we need to check if multi-tenant queries are enabled.
 	// If the querier module is part of this process the querier module will configure everything.
@@ -309,8 +312,31 @@ func (t *Loki) initDistributor() (services.Service, error) {
 		serverutil.RecoveryHTTPMiddleware,
 		t.HTTPAuthMiddleware,
 	).Wrap(http.HandlerFunc(t.distributor.PushHandler))
+	otlpPushHandler := middleware.Merge(
+		serverutil.RecoveryHTTPMiddleware,
+		t.HTTPAuthMiddleware,
+	).Wrap(http.HandlerFunc(t.distributor.OTLPPushHandler))
+	elasticsearchBulkHandler := middleware.Merge(
+		serverutil.RecoveryHTTPMiddleware,
+		t.HTTPAuthMiddleware,
+	).Wrap(http.HandlerFunc(t.distributor.ElasticsearchBulkHandler))
+	// Splunk HEC clients authenticate with HEC tokens, which are mapped to tenants by the HEC tokens file of the distributor.
+	splunkAuthMiddleware := t.HTTPAuthMiddleware
+	if t.Cfg.AuthEnabled {
+		splunkAuthMiddleware = middleware.Func(t.distributor.SplunkTokenAuthMiddleware)
+	}
+	splunkMiddleware := middleware.Merge(
+		serverutil.RecoveryHTTPMiddleware,
+		splunkAuthMiddleware,
+	)
+	splunkEventHandler := splunkMiddleware.Wrap(http.HandlerFunc(t.distributor.SplunkEventHandler))
+	splunkRawHandler := splunkMiddleware.Wrap(http.HandlerFunc(t.distributor.SplunkRawHandler))
+	cardinalityHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.distributor.CardinalityHandler))
+	quotaHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.distributor.QuotaHandler))
 
 	t.Server.HTTP.Path("/distributor/ring").Methods("GET", "POST").Handler(t.distributor)
+	t.Server.HTTP.Path("/distributor/cardinality").Methods("GET").Handler(cardinalityHandler)
+	t.Server.HTTP.Path("/distributor/quota").Methods("GET").Handler(quotaHandler)
 
 	if t.Cfg.InternalServer.Enable {
 		t.InternalServer.HTTP.Path("/distributor/ring").Methods("GET").Handler(t.distributor)
@@ -318,6 +344,16 @@ func (t *Loki) initDistributor() (services.Service, error) {
 
 	t.Server.HTTP.Path("/api/prom/push").Methods("POST").Handler(pushHandler)
 	t.Server.HTTP.Path("/loki/api/v1/push").Methods("POST").Handler(pushHandler)
+	t.Server.HTTP.Path("/otlp/v1/logs").Methods("POST").Handler(otlpPushHandler)
+	t.Server.HTTP.Path("/elasticsearch/").Methods("GET", "HEAD").HandlerFunc(push.WriteElasticsearchInfo)
+	t.Server.HTTP.Path("/elasticsearch/_bulk").Methods("POST", "PUT").Handler(elasticsearchBulkHandler)
+	t.Server.HTTP.Path("/elasticsearch/{index}/_bulk").Methods("POST", "PUT").Handler(elasticsearchBulkHandler)
+	t.Server.HTTP.Path("/services/collector").Methods("POST").Handler(splunkEventHandler)
+	t.Server.HTTP.Path("/services/collector/event").Methods("POST").Handler(splunkEventHandler)
+	t.Server.HTTP.Path("/services/collector/event/1.0").Methods("POST").Handler(splunkEventHandler)
+	t.Server.HTTP.Path("/services/collector/raw").Methods("POST").Handler(splunkRawHandler)
+	t.Server.HTTP.Path("/services/collector/raw/1.0").Methods("POST").Handler(splunkRawHandler)
+	t.Server.HTTP.Path("/services/collector/health").Methods("GET").HandlerFunc(push.WriteSplunkHealth)
 	return t.distributor, nil
 }
 
@@ -402,6 +438,7 @@ func (t *Loki) initQuerier() (services.Service, error) {
 
 		"/loki/api/v1/series":      querier.WrapQuerySpanAndTimeout("query.Series", t.querierAPI).Wrap(http.HandlerFunc(t.querierAPI.SeriesHandler)),
 		"/loki/api/v1/index/stats": querier.WrapQuerySpanAndTimeout("query.IndexStats", t.querierAPI).Wrap(http.HandlerFunc(t.querierAPI.IndexStatsHandler)),
+		"/loki/api/v1/streams/top": querier.WrapQuerySpanAndTimeout("query.TopStreams", t.querierAPI).Wrap(http.HandlerFunc(t.querierAPI.TopStreamsHandler)),
 
 		"/api/prom/query": middleware.Merge(
 			httpMiddleware,
@@ -462,6 +499,7 @@ func (t *Loki) initIngester() (_ services.Service, err error) {
 	logproto.RegisterQuerierServer(t.Server.GRPC, t.Ingester)
 	logproto.RegisterIngesterServer(t.Server.GRPC, t.Ingester)
 	logproto.RegisterStreamDataServer(t.Server.GRPC, t.Ingester)
+	logproto.RegisterTenantVolumesServer(t.Server.GRPC, t.Ingester)
 
 	httpMiddleware := middleware.Merge(
 		serverutil.RecoveryHTTPMiddleware,
@@ -478,6 +516,9 @@ func (t *Loki) initIngester() (_ services.Service, err error) {
 	t.Server.HTTP.Methods("POST").Path("/ingester/shutdown").Handler(
 		httpMiddleware.Wrap(http.HandlerFunc(t.Ingester.ShutdownHandler)),
 	)
+	t.Server.HTTP.Methods("GET").Path("/ingester/streams/top").Handler(
+		middleware.Merge(httpMiddleware, t.HTTPAuthMiddleware).Wrap(http.HandlerFunc(t.Ingester.TopStreamsHandler)),
+	)
 	return t.Ingester, nil
 }
 
@@ -669,7 +710,7 @@ func (t *Loki) initStore() (_ services.Service, err error) {
 }
 
 func (t *Loki) initIngesterQuerier() (_ services.Service, err error) {
-	t.ingesterQuerier, err = querier.NewIngesterQuerier(t.Cfg.IngesterClient, t.ring, t.Cfg.Querier.ExtraQueryDelay)
+	t.ingesterQuerier, err = querier.NewIngesterQuerier(t.Cfg.IngesterClient, t.ring, t.Cfg.Querier)
 	if err != nil {
 		return nil, err
 	}
@@ -685,6 +726,8 @@ func (disabledShuffleShardingLimits) MaxQueriersPerUser(userID string) int { ret
 func (t *Loki) initQueryFrontendTripperware() (_ services.Service, err error) {
 	level.Debug(util_log.Logger).Log("msg", "initializing query frontend tripperware")
 
+	t.Cfg.QueryRange.ContinuousAggregationsInterval = t.Cfg.Ingester.ContinuousAggregations.Interval
+
 	tripperware, stopper, err := queryrange.NewTripperware(
 		t.Cfg.QueryRange,
 		util_log.Logger,
@@ -796,7 +839,7 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 
 	toMerge := []middleware.Interface{
 		httpreq.ExtractQueryTagsMiddleware(),
-		httpreq.PropagateHeadersMiddleware(httpreq.LokiActorPathHeader),
+		httpreq.PropagateHeadersMiddleware(httpreq.LokiActorPathHeader, httpreq.LokiQueryUserHeader, httpreq.LokiQueryPriorityHeader),
 		serverutil.RecoveryHTTPMiddleware,
 		t.HTTPAuthMiddleware,
 		queryrange.StatsHTTPMiddleware,
@@ -804,6 +847,15 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 		serverutil.ResponseJSONMiddleware(),
 	}
 
+	var auditor *audit.Logger
+	if t.Cfg.Frontend.Audit.Enabled {
+		auditor, err = audit.New(t.Cfg.Frontend.Audit, util_log.Logger, prometheus.DefaultRegisterer)
+		if err != nil {
+			return nil, err
+		}
+		toMerge = append(toMerge, queryrange.AuditHTTPMiddleware(auditor))
+	}
+
 	if t.Cfg.Querier.PerRequestLimitsEnabled {
 		logger := log.With(util_log.Logger, "component", "query-limiter-middleware")
 		toMerge = append(toMerge, querylimits.NewQueryLimitsMiddleware(logger))
@@ -851,6 +903,7 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 	t.Server.HTTP.Path("/loki/api/v1/label/{name}/values").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/loki/api/v1/series").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/loki/api/v1/index/stats").Methods("GET", "POST").Handler(frontendHandler)
+	t.Server.HTTP.Path("/loki/api/v1/streams/top").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/query").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/label").Methods("GET", "POST").Handler(frontendHandler)
 	t.Server.HTTP.Path("/api/prom/label/{name}/values").Methods("GET", "POST").Handler(frontendHandler)
@@ -870,6 +923,9 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 				t.stopper.Stop()
 				t.stopper = nil
 			}
+			if auditor != nil {
+				auditor.Stop()
+			}
 			return nil
 		}), nil
 	}
@@ -886,6 +942,9 @@ func (t *Loki) initQueryFrontend() (_ services.Service, err error) {
 		if t.stopper != nil {
 			t.stopper.Stop()
 		}
+		if auditor != nil {
+			auditor.Stop()
+		}
 		return nil
 	}), nil
 }
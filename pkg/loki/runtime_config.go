@@ -1,12 +1,14 @@
 package loki
 
 import (
+	"context"
 	"fmt"
 	"io"
 
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/kv"
 	"github.com/grafana/dskit/runtimeconfig"
+	"github.com/grafana/dskit/services"
 	"gopkg.in/yaml.v2"
 
 	"github.com/grafana/loki/pkg/runtime"
@@ -38,6 +40,26 @@ func (r runtimeConfigValues) validate() error {
 	return nil
 }
 
+// runtimeConfigLoader returns the loader of the runtime config, which also validates the tenant limits
+// against the configuration of the components that enforce them.
+func runtimeConfigLoader(cfg Config) runtimeconfig.Loader {
+	return func(r io.Reader) (interface{}, error) {
+		values, err := loadRuntimeConfig(r)
+		if err != nil {
+			return nil, err
+		}
+		for t, l := range values.(*runtimeConfigValues).TenantLimits {
+			if l == nil {
+				continue
+			}
+			if err := cfg.Distributor.CardinalityGuard.ValidateMaxLabelValues(l.CardinalityGuardMaxLabelValues); err != nil {
+				return nil, fmt.Errorf("invalid override for tenant %s: %w", t, err)
+			}
+		}
+		return values, nil
+	}
+}
+
 func loadRuntimeConfig(r io.Reader) (interface{}, error) {
 	overrides := &runtimeConfigValues{}
 
@@ -78,6 +100,34 @@ func (t *tenantLimitsFromRuntimeConfig) TenantLimits(userID string) *validation.
 	return allByUserID[userID]
 }
 
+// LoadOverrides returns the limits of the tenants, loading the per tenant overrides of the runtime config once.
+// It is meant for tools that run outside of a Loki process, which don't reload the runtime config.
+func LoadOverrides(cfg Config) (*validation.Overrides, error) {
+	rc := cfg.RuntimeConfig
+	if len(rc.LoadPath) == 0 && len(cfg.LimitsConfig.PerTenantOverrideConfig) != 0 {
+		rc.LoadPath = []string{cfg.LimitsConfig.PerTenantOverrideConfig}
+	}
+	if len(rc.LoadPath) == 0 {
+		return validation.NewOverrides(cfg.LimitsConfig, nil)
+	}
+	rc.Loader = runtimeConfigLoader(cfg)
+
+	validation.SetDefaultLimitsForYAMLUnmarshalling(cfg.LimitsConfig)
+
+	manager, err := runtimeconfig.New(rc, nil, util_log.Logger)
+	if err != nil {
+		return nil, err
+	}
+	// the manager loads the runtime config when it starts and keeps it once stopped.
+	if err := services.StartAndAwaitRunning(context.Background(), manager); err != nil {
+		return nil, fmt.Errorf("load runtime config: %w", err)
+	}
+	if err := services.StopAndAwaitTerminated(context.Background(), manager); err != nil {
+		return nil, err
+	}
+	return validation.NewOverrides(cfg.LimitsConfig, newtenantLimitsFromRuntimeConfig(manager))
+}
+
 func newtenantLimitsFromRuntimeConfig(c *runtimeconfig.Manager) validation.TenantLimits {
 	return &tenantLimitsFromRuntimeConfig{c: c}
 }
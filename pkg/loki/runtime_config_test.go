@@ -5,6 +5,7 @@ import (
 	"flag"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -83,6 +84,26 @@ overrides:
 	require.Equal(t, "invalid override for tenant 29: retention period must be >= 24h was 5h", err.Error())
 }
 
+func Test_RuntimeConfigLoader(t *testing.T) {
+	var cfg Config
+	cfg.Distributor.CardinalityGuard.MaxTrackedValues = 100
+	load := runtimeConfigLoader(cfg)
+
+	_, err := load(strings.NewReader(`
+overrides:
+    "29":
+        cardinality_guard_max_label_values: 99
+`))
+	require.NoError(t, err)
+
+	_, err = load(strings.NewReader(`
+overrides:
+    "29":
+        cardinality_guard_max_label_values: 100
+`))
+	require.EqualError(t, err, "invalid override for tenant 29: cardinality_guard_max_label_values (100) must be lower than -distributor.cardinality-guard.max-tracked-values (100)")
+}
+
 func newTestOverrides(t *testing.T, yaml string) *validation.Overrides {
 	t.Helper()
 	f, err := os.CreateTemp(t.TempDir(), "bar")
@@ -130,3 +151,27 @@ func Test_NoOverrides(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, time.Duration(defaults.QuerySplitDuration), overrides.QuerySplitDuration("foo"))
 }
+
+func Test_LoadOverrides(t *testing.T) {
+	var cfg Config
+	flagset := flag.NewFlagSet("", flag.PanicOnError)
+	cfg.RegisterFlags(flagset)
+	require.NoError(t, flagset.Parse(nil))
+
+	overrides, err := LoadOverrides(cfg)
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(cfg.LimitsConfig.RetentionPeriod), overrides.RetentionPeriod("29"))
+
+	path := filepath.Join(t.TempDir(), "overrides.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+overrides:
+    "29":
+        retention_period: 24h
+`), 0o600))
+	cfg.RuntimeConfig.LoadPath = []string{path}
+
+	overrides, err = LoadOverrides(cfg)
+	require.NoError(t, err)
+	require.Equal(t, 24*time.Hour, overrides.RetentionPeriod("29"))
+	require.Equal(t, time.Duration(cfg.LimitsConfig.RetentionPeriod), overrides.RetentionPeriod("1"))
+}
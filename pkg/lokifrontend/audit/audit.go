@@ -0,0 +1,240 @@
+// Package audit records one structured record per query that passes the query frontend
+// and writes them to a configurable sink.
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	SinkFile = "file"
+	SinkLoki = "loki"
+)
+
+// Config configures the query audit log.
+type Config struct {
+	Enabled   bool          `yaml:"enabled"`
+	Sink      string        `yaml:"sink"`
+	QueueSize int           `yaml:"queue_size"`
+	BatchSize int           `yaml:"batch_size"`
+	BatchWait time.Duration `yaml:"batch_wait"`
+
+	File FileConfig `yaml:"file"`
+	Loki LokiConfig `yaml:"loki"`
+}
+
+// RegisterFlags adds the flags required to config this to the given FlagSet.
+func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, "frontend.audit.enabled", false, "Record an audit entry for every query that passes the query frontend.")
+	f.StringVar(&cfg.Sink, "frontend.audit.sink", SinkFile, "Where audit records are written to. Supported values are: file, loki.")
+	f.IntVar(&cfg.QueueSize, "frontend.audit.queue-size", 10000, "Maximum number of audit records that are buffered before they are written. Records are dropped if the queue is full.")
+	f.IntVar(&cfg.BatchSize, "frontend.audit.batch-size", 100, "Maximum number of audit records that are written at once.")
+	f.DurationVar(&cfg.BatchWait, "frontend.audit.batch-wait", time.Second, "Maximum amount of time a record waits before it is written.")
+	cfg.File.RegisterFlagsWithPrefix("frontend.audit.file.", f)
+	cfg.Loki.RegisterFlagsWithPrefix("frontend.audit.loki.", f)
+}
+
+// Validate validates the config.
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.QueueSize <= 0 {
+		return errors.New("the audit queue size must be greater than 0")
+	}
+	if cfg.BatchSize <= 0 {
+		return errors.New("the audit batch size must be greater than 0")
+	}
+	if cfg.BatchWait <= 0 {
+		return errors.New("the audit batch wait must be greater than 0")
+	}
+	switch cfg.Sink {
+	case SinkFile:
+		return cfg.File.Validate()
+	case SinkLoki:
+		return cfg.Loki.Validate()
+	default:
+		return fmt.Errorf("unsupported audit sink %q, expected one of: %s, %s", cfg.Sink, SinkFile, SinkLoki)
+	}
+}
+
+// Record describes a single query.
+type Record struct {
+	Timestamp      time.Time     `json:"ts"`
+	Tenant         string        `json:"tenant"`
+	User           string        `json:"user,omitempty"`
+	Path           string        `json:"path"`
+	Query          string        `json:"query,omitempty"`
+	Start          time.Time     `json:"start"`
+	End            time.Time     `json:"end"`
+	BytesProcessed int64         `json:"bytes_processed"`
+	Status         int           `json:"status"`
+	Duration       time.Duration `json:"-"`
+}
+
+// MarshalJSON encodes the record with a human readable duration.
+func (r Record) MarshalJSON() ([]byte, error) {
+	type plain Record
+	return json.Marshal(struct {
+		plain
+		Duration string `json:"duration"`
+	}{plain(r), r.Duration.String()})
+}
+
+// Sink writes audit records.
+type Sink interface {
+	Write(records []Record) error
+	Close() error
+}
+
+type metrics struct {
+	records prometheus.Counter
+	dropped prometheus.Counter
+	failed  prometheus.Counter
+}
+
+func newMetrics(r prometheus.Registerer) *metrics {
+	return &metrics{
+		records: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_frontend_audit_records_total",
+			Help:      "Total number of query audit records written to the sink.",
+		}),
+		dropped: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_frontend_audit_records_dropped_total",
+			Help:      "Total number of query audit records dropped because the queue was full.",
+		}),
+		failed: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_frontend_audit_records_failed_total",
+			Help:      "Total number of query audit records that could not be written to the sink.",
+		}),
+	}
+}
+
+// Logger buffers audit records and writes them in batches to its sink in the background.
+type Logger struct {
+	cfg     Config
+	sink    Sink
+	logger  log.Logger
+	metrics *metrics
+
+	mtx     sync.RWMutex
+	stopped bool
+	queue   chan Record
+	done    chan struct{}
+}
+
+// New creates a Logger for the configured sink and starts writing records.
+func New(cfg Config, logger log.Logger, reg prometheus.Registerer) (*Logger, error) {
+	var (
+		sink Sink
+		err  error
+	)
+	switch cfg.Sink {
+	case SinkFile:
+		sink, err = NewFileSink(cfg.File)
+	case SinkLoki:
+		sink, err = NewLokiSink(cfg.Loki)
+	default:
+		err = fmt.Errorf("unsupported audit sink %q", cfg.Sink)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newLogger(cfg, sink, logger, reg), nil
+}
+
+func newLogger(cfg Config, sink Sink, logger log.Logger, reg prometheus.Registerer) *Logger {
+	l := &Logger{
+		cfg:     cfg,
+		sink:    sink,
+		logger:  log.With(logger, "component", "query-audit"),
+		metrics: newMetrics(reg),
+		queue:   make(chan Record, cfg.QueueSize),
+		done:    make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// Log queues a record to be written. It never blocks: if the queue is full, the record is dropped.
+func (l *Logger) Log(r Record) {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	if l.stopped {
+		l.metrics.dropped.Inc()
+		return
+	}
+	select {
+	case l.queue <- r:
+	default:
+		l.metrics.dropped.Inc()
+	}
+}
+
+// Stop writes all queued records and closes the sink.
+func (l *Logger) Stop() {
+	l.mtx.Lock()
+	if l.stopped {
+		l.mtx.Unlock()
+		return
+	}
+	l.stopped = true
+	close(l.queue)
+	l.mtx.Unlock()
+
+	<-l.done
+}
+
+func (l *Logger) run() {
+	defer close(l.done)
+
+	ticker := time.NewTicker(l.cfg.BatchWait)
+	defer ticker.Stop()
+
+	batch := make([]Record, 0, l.cfg.BatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := l.sink.Write(batch); err != nil {
+			level.Error(l.logger).Log("msg", "failed to write query audit records", "records", len(batch), "err", err)
+			l.metrics.failed.Add(float64(len(batch)))
+		} else {
+			l.metrics.records.Add(float64(len(batch)))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case r, ok := <-l.queue:
+			if !ok {
+				flush()
+				if err := l.sink.Close(); err != nil {
+					level.Warn(l.logger).Log("msg", "failed to close query audit sink", "err", err)
+				}
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) >= l.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
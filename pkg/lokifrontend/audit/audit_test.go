@@ -0,0 +1,161 @@
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/util/flagext"
+)
+
+func testRecord(tenant, query string) Record {
+	return Record{
+		Timestamp:      time.Unix(100, 0).UTC(),
+		Tenant:         tenant,
+		User:           "alice",
+		Path:           "/loki/api/v1/query_range",
+		Query:          query,
+		Start:          time.Unix(0, 0).UTC(),
+		End:            time.Unix(3600, 0).UTC(),
+		BytesProcessed: 1024,
+		Status:         http.StatusOK,
+		Duration:       1500 * time.Millisecond,
+	}
+}
+
+func TestRecord_MarshalJSON(t *testing.T) {
+	buf, err := json.Marshal(testRecord("tenant-a", `{app="foo"}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"ts": "1970-01-01T00:01:40Z",
+		"tenant": "tenant-a",
+		"user": "alice",
+		"path": "/loki/api/v1/query_range",
+		"query": "{app=\"foo\"}",
+		"start": "1970-01-01T00:00:00Z",
+		"end": "1970-01-01T01:00:00Z",
+		"bytes_processed": 1024,
+		"status": 200,
+		"duration": "1.5s"
+	}`, string(buf))
+}
+
+func TestFileSink_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	line, err := json.Marshal(testRecord("tenant-a", `{app="foo"}`))
+	require.NoError(t, err)
+
+	sink, err := NewFileSink(FileConfig{
+		Path: path,
+		// Two records fit into a file.
+		MaxSize:    flagext.ByteSize(2*(len(line)+1) + 1),
+		MaxBackups: 2,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 7; i++ {
+		require.NoError(t, sink.Write([]Record{testRecord("tenant-a", `{app="foo"}`)}))
+	}
+	require.NoError(t, sink.Close())
+
+	countLines := func(p string) int {
+		buf, err := os.ReadFile(p)
+		require.NoError(t, err)
+		return strings.Count(string(buf), "\n")
+	}
+	require.Equal(t, 1, countLines(path))
+	require.Equal(t, 2, countLines(path+".1"))
+	require.Equal(t, 2, countLines(path+".2"))
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestLogger_LokiSink(t *testing.T) {
+	requests := make(chan *logproto.PushRequest, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, pushPath, r.URL.Path)
+		require.Equal(t, "audit", r.Header.Get("X-Scope-OrgID"))
+		require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+
+		compressed, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		buf, err := snappy.Decode(nil, compressed)
+		require.NoError(t, err)
+		var req logproto.PushRequest
+		require.NoError(t, proto.Unmarshal(buf, &req))
+		requests <- &req
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cfg := Config{
+		Enabled:   true,
+		Sink:      SinkLoki,
+		QueueSize: 10,
+		BatchSize: 3,
+		BatchWait: time.Minute,
+		Loki: LokiConfig{
+			URL:      server.URL + "/",
+			TenantID: "audit",
+			Timeout:  time.Second,
+		},
+	}
+	require.NoError(t, cfg.Validate())
+	l, err := New(cfg, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+
+	l.Log(testRecord("tenant-a", `{app="foo"}`))
+	l.Log(testRecord("tenant-b", `{app="bar"}`))
+	l.Log(testRecord("tenant-a", `{app="baz"}`))
+
+	req := <-requests
+	require.Len(t, req.Streams, 2)
+	require.Equal(t, `{job="loki/query-audit", tenant="tenant-a"}`, req.Streams[0].Labels)
+	require.Len(t, req.Streams[0].Entries, 2)
+	require.Equal(t, `{job="loki/query-audit", tenant="tenant-b"}`, req.Streams[1].Labels)
+	require.Len(t, req.Streams[1].Entries, 1)
+
+	var rec map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(req.Streams[1].Entries[0].Line), &rec))
+	require.Equal(t, `{app="bar"}`, rec["query"])
+
+	// Stopping the logger flushes the incomplete batch.
+	l.Log(testRecord("tenant-a", `{app="qux"}`))
+	l.Stop()
+	req = <-requests
+	require.Len(t, req.Streams, 1)
+	require.Len(t, req.Streams[0].Entries, 1)
+
+	// Records logged after stopping are dropped.
+	l.Log(testRecord("tenant-a", `{app="foo"}`))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := Config{}
+	require.NoError(t, cfg.Validate())
+
+	cfg = Config{Enabled: true, Sink: "stdout", QueueSize: 1, BatchSize: 1, BatchWait: time.Second}
+	require.Error(t, cfg.Validate())
+
+	cfg.Sink = SinkFile
+	require.Error(t, cfg.Validate())
+	cfg.File.Path = "audit.log"
+	require.NoError(t, cfg.Validate())
+
+	cfg.Sink = SinkLoki
+	require.Error(t, cfg.Validate())
+	cfg.Loki = LokiConfig{URL: "http://loki:3100", TenantID: "audit"}
+	require.NoError(t, cfg.Validate())
+}
@@ -0,0 +1,129 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/grafana/loki/pkg/util/flagext"
+)
+
+// FileConfig configures the file sink.
+type FileConfig struct {
+	Path       string           `yaml:"path"`
+	MaxSize    flagext.ByteSize `yaml:"max_size"`
+	MaxBackups int              `yaml:"max_backups"`
+}
+
+// RegisterFlagsWithPrefix adds the flags required to config this to the given FlagSet.
+func (cfg *FileConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	cfg.MaxSize = 100 << 20
+	f.StringVar(&cfg.Path, prefix+"path", "", "Path of the file the audit records are written to, one JSON object per line.")
+	f.Var(&cfg.MaxSize, prefix+"max-size", "Size at which the audit file is rotated. 0 disables rotation.")
+	f.IntVar(&cfg.MaxBackups, prefix+"max-backups", 5, "Number of rotated audit files that are kept.")
+}
+
+// Validate validates the config.
+func (cfg *FileConfig) Validate() error {
+	if cfg.Path == "" {
+		return errors.New("the audit file path is required when the file sink is used")
+	}
+	if cfg.MaxBackups < 0 {
+		return errors.New("the number of audit file backups must not be negative")
+	}
+	return nil
+}
+
+// fileSink writes records as JSON lines to a local file, which it rotates once it reaches its maximum size.
+// Rotated files get the suffixes .1 (newest) to .<max backups> (oldest).
+type fileSink struct {
+	cfg  FileConfig
+	file *os.File
+	size int64
+}
+
+// NewFileSink opens, or creates, the audit file.
+func NewFileSink(cfg FileConfig) (Sink, error) {
+	s := &fileSink{cfg: cfg}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) open() error {
+	f, err := os.OpenFile(s.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to stat audit file: %w", err)
+	}
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
+
+func (s *fileSink) Write(records []Record) error {
+	w := bufio.NewWriter(s.file)
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+
+		if s.cfg.MaxSize > 0 && s.size > 0 && s.size+int64(len(line)) > int64(s.cfg.MaxSize) {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if err := s.rotate(); err != nil {
+				return err
+			}
+			w.Reset(s.file)
+		}
+
+		n, err := w.Write(line)
+		s.size += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("failed to close audit file: %w", err)
+	}
+
+	if s.cfg.MaxBackups == 0 {
+		if err := os.Remove(s.cfg.Path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove audit file: %w", err)
+		}
+		return s.open()
+	}
+
+	for i := s.cfg.MaxBackups - 1; i > 0; i-- {
+		if err := os.Rename(backupPath(s.cfg.Path, i), backupPath(s.cfg.Path, i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit file: %w", err)
+		}
+	}
+	if err := os.Rename(s.cfg.Path, backupPath(s.cfg.Path, 1)); err != nil {
+		return fmt.Errorf("failed to rotate audit file: %w", err)
+	}
+	return s.open()
+}
+
+func (s *fileSink) Close() error {
+	return s.file.Close()
+}
+
+func backupPath(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
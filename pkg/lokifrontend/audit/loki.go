@@ -0,0 +1,153 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const (
+	pushPath = "/loki/api/v1/push"
+	// auditJob is the value of the job label of the audit streams.
+	auditJob = "loki/query-audit"
+
+	maxErrMsgLen = 1024
+)
+
+// LokiConfig configures the Loki sink.
+type LokiConfig struct {
+	URL      string        `yaml:"url"`
+	TenantID string        `yaml:"tenant_id"`
+	Timeout  time.Duration `yaml:"timeout"`
+}
+
+// RegisterFlagsWithPrefix adds the flags required to config this to the given FlagSet.
+func (cfg *LokiConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.URL, prefix+"url", "", "Base URL of the Loki the audit records are pushed to, e.g. http://loki:3100.")
+	f.StringVar(&cfg.TenantID, prefix+"tenant-id", "loki-audit", "Tenant the audit records are pushed as.")
+	f.DurationVar(&cfg.Timeout, prefix+"timeout", 10*time.Second, "Timeout of a push request.")
+}
+
+// Validate validates the config.
+func (cfg *LokiConfig) Validate() error {
+	if cfg.URL == "" {
+		return errors.New("the audit Loki URL is required when the loki sink is used")
+	}
+	if _, err := url.Parse(cfg.URL); err != nil {
+		return fmt.Errorf("invalid audit Loki URL: %w", err)
+	}
+	if cfg.TenantID == "" {
+		return errors.New("the audit tenant ID is required when the loki sink is used")
+	}
+	return nil
+}
+
+// lokiSink pushes records to Loki. The records of every queried tenant form their own stream
+// of the audit tenant, with the JSON encoded record as the log line.
+type lokiSink struct {
+	cfg    LokiConfig
+	url    string
+	client *http.Client
+}
+
+// NewLokiSink creates a sink that pushes records to the configured Loki.
+func NewLokiSink(cfg LokiConfig) (Sink, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &lokiSink{
+		cfg:    cfg,
+		url:    strings.TrimSuffix(cfg.URL, "/") + pushPath,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+func (s *lokiSink) Write(records []Record) error {
+	req, err := pushRequest(records)
+	if err != nil {
+		return err
+	}
+	buf, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal push request: %w", err)
+	}
+	buf = snappy.Encode(nil, buf)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(buf))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("X-Scope-OrgID", s.cfg.TenantID)
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to push audit records: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxErrMsgLen))
+		line := ""
+		if scanner.Scan() {
+			line = scanner.Text()
+		}
+		return fmt.Errorf("server returned HTTP status %s (%d): %s", resp.Status, resp.StatusCode, line)
+	}
+	return nil
+}
+
+func (s *lokiSink) Close() error {
+	return nil
+}
+
+// pushRequest groups the records by the tenant they belong to.
+func pushRequest(records []Record) (*logproto.PushRequest, error) {
+	var (
+		req     = &logproto.PushRequest{}
+		streams = map[string]int{}
+	)
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+
+		idx, ok := streams[r.Tenant]
+		if !ok {
+			lbs := model.LabelSet{"job": auditJob}
+			if r.Tenant != "" {
+				lbs["tenant"] = model.LabelValue(r.Tenant)
+			}
+			idx = len(req.Streams)
+			streams[r.Tenant] = idx
+			req.Streams = append(req.Streams, logproto.Stream{
+				Labels: lbs.String(),
+				Hash:   uint64(lbs.Fingerprint()),
+			})
+		}
+		req.Streams[idx].Entries = append(req.Streams[idx].Entries, logproto.Entry{
+			Timestamp: r.Timestamp,
+			Line:      string(line),
+		})
+	}
+	return req, nil
+}
@@ -129,7 +129,7 @@ func TestFrontendCheckReady(t *testing.T) {
 			qm := queue.NewMetrics("query_frontend", nil)
 			f := &Frontend{
 				log:          log.NewNopLogger(),
-				requestQueue: queue.NewRequestQueue(5, 0, qm),
+				requestQueue: queue.NewRequestQueue(5, 0, queue.PriorityConfig{}, qm),
 			}
 			for i := 0; i < tt.connectedClients; i++ {
 				f.requestQueue.RegisterQuerierConnection("test")
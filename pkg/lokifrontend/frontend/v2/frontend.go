@@ -208,7 +208,7 @@ func (f *Frontend) RoundTripGRPC(ctx context.Context, req *httpgrpc.HTTPRequest)
 		queryID:      f.lastQueryID.Inc(),
 		request:      req,
 		tenantID:     tenantID,
-		actor:        httpreq.ExtractActorPath(ctx),
+		actor:        httpreq.ExtractQueuePath(ctx),
 		statsEnabled: stats.IsEnabled(ctx),
 
 		cancel: cancel,
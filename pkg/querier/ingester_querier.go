@@ -2,6 +2,7 @@ package querier
 
 import (
 	"context"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -17,6 +18,7 @@ import (
 	"google.golang.org/grpc/codes"
 
 	"github.com/grafana/loki/pkg/distributor/clientpool"
+	"github.com/grafana/loki/pkg/ingester"
 	"github.com/grafana/loki/pkg/ingester/client"
 	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logproto"
@@ -25,6 +27,7 @@ import (
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	index_stats "github.com/grafana/loki/pkg/storage/stores/index/stats"
 	util_log "github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/util/math"
 )
 
 type responseFromIngesters struct {
@@ -34,26 +37,28 @@ type responseFromIngesters struct {
 
 // IngesterQuerier helps with querying the ingesters.
 type IngesterQuerier struct {
-	ring            ring.ReadRing
-	pool            *ring_client.Pool
-	extraQueryDelay time.Duration
+	ring                     ring.ReadRing
+	pool                     *ring_client.Pool
+	extraQueryDelay          time.Duration
+	minimizeIngesterRequests bool
 }
 
-func NewIngesterQuerier(clientCfg client.Config, ring ring.ReadRing, extraQueryDelay time.Duration) (*IngesterQuerier, error) {
+func NewIngesterQuerier(clientCfg client.Config, ring ring.ReadRing, cfg Config) (*IngesterQuerier, error) {
 	factory := func(addr string) (ring_client.PoolClient, error) {
 		return client.New(clientCfg, addr)
 	}
 
-	return newIngesterQuerier(clientCfg, ring, extraQueryDelay, factory)
+	return newIngesterQuerier(clientCfg, ring, cfg, factory)
 }
 
 // newIngesterQuerier creates a new IngesterQuerier and allows to pass a custom ingester client factory
 // used for testing purposes
-func newIngesterQuerier(clientCfg client.Config, ring ring.ReadRing, extraQueryDelay time.Duration, clientFactory ring_client.PoolFactory) (*IngesterQuerier, error) {
+func newIngesterQuerier(clientCfg client.Config, ring ring.ReadRing, cfg Config, clientFactory ring_client.PoolFactory) (*IngesterQuerier, error) {
 	iq := IngesterQuerier{
-		ring:            ring,
-		pool:            clientpool.NewPool(clientCfg.PoolConfig, ring, clientFactory, util_log.Logger),
-		extraQueryDelay: extraQueryDelay,
+		ring:                     ring,
+		pool:                     clientpool.NewPool(clientCfg.PoolConfig, ring, clientFactory, util_log.Logger),
+		extraQueryDelay:          cfg.ExtraQueryDelay,
+		minimizeIngesterRequests: cfg.MinimizeIngesterRequests,
 	}
 
 	err := services.StartAndAwaitRunning(context.Background(), iq.pool)
@@ -64,7 +69,8 @@ func newIngesterQuerier(clientCfg client.Config, ring ring.ReadRing, extraQueryD
 	return &iq, nil
 }
 
-// forAllIngesters runs f, in parallel, for all ingesters
+// forAllIngesters runs f, in parallel, for all ingesters, or only for the ingesters of the minimum number of zones
+// needed for a complete result when minimizing the ingester requests.
 // TODO taken from Cortex, see if we can refactor out an usable interface.
 func (q *IngesterQuerier) forAllIngesters(ctx context.Context, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
 	replicationSet, err := q.ring.GetReplicationSetForOperation(ring.Read)
@@ -72,13 +78,42 @@ func (q *IngesterQuerier) forAllIngesters(ctx context.Context, f func(context.Co
 		return nil, err
 	}
 
+	if q.minimizeIngesterRequests {
+		return q.forMinimumZones(ctx, replicationSet, f)
+	}
 	return q.forGivenIngesters(ctx, replicationSet, f)
 }
 
 // forGivenIngesters runs f, in parallel, for given ingesters
 // TODO taken from Cortex, see if we can refactor out an usable interface.
 func (q *IngesterQuerier) forGivenIngesters(ctx context.Context, replicationSet ring.ReplicationSet, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
-	results, err := replicationSet.Do(ctx, q.extraQueryDelay, func(ctx context.Context, ingester *ring.InstanceDesc) (interface{}, error) {
+	results, err := replicationSet.Do(ctx, q.extraQueryDelay, q.callIngester(f))
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponsesFromIngesters(results), nil
+}
+
+// forMinimumZones runs f, in parallel, for the ingesters of the minimum number of zones needed for a complete result,
+// which are all the zones of the replication set but the ones allowed to be unavailable. Whenever an ingester fails,
+// f is run for the ingesters of another zone. Without zone awareness, f is run for all the ingesters.
+func (q *IngesterQuerier) forMinimumZones(ctx context.Context, replicationSet ring.ReplicationSet, f func(context.Context, logproto.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
+	if replicationSet.MaxUnavailableZones == 0 {
+		return q.forGivenIngesters(ctx, replicationSet, f)
+	}
+
+	results, err := doMinimumZones(ctx, replicationSet, q.callIngester(f))
+	if err != nil {
+		return nil, err
+	}
+
+	return toResponsesFromIngesters(results), nil
+}
+
+// callIngester returns a function running f with the client of an ingester.
+func (q *IngesterQuerier) callIngester(f func(context.Context, logproto.QuerierClient) (interface{}, error)) func(context.Context, *ring.InstanceDesc) (interface{}, error) {
+	return func(ctx context.Context, ingester *ring.InstanceDesc) (interface{}, error) {
 		client, err := q.pool.GetClientFor(ingester.Addr)
 		if err != nil {
 			return nil, err
@@ -90,23 +125,26 @@ func (q *IngesterQuerier) forGivenIngesters(ctx context.Context, replicationSet
 		}
 
 		return responseFromIngesters{ingester.Addr, resp}, nil
-	})
-	if err != nil {
-		return nil, err
 	}
+}
 
+func toResponsesFromIngesters(results []interface{}) []responseFromIngesters {
 	responses := make([]responseFromIngesters, 0, len(results))
 	for _, result := range results {
 		responses = append(responses, result.(responseFromIngesters))
 	}
 
-	return responses, err
+	return responses
 }
 
 func (q *IngesterQuerier) SelectLogs(ctx context.Context, params logql.SelectLogParams) ([]iter.EntryIterator, error) {
 	resps, err := q.forAllIngesters(ctx, func(_ context.Context, client logproto.QuerierClient) (interface{}, error) {
 		stats.FromContext(ctx).AddIngesterReached(1)
-		return client.Query(ctx, params.QueryRequest)
+		queryClient, err := client.Query(ctx, params.QueryRequest)
+		if err != nil {
+			return nil, err
+		}
+		return peekQueryClient(queryClient)
 	})
 	if err != nil {
 		return nil, err
@@ -122,7 +160,11 @@ func (q *IngesterQuerier) SelectLogs(ctx context.Context, params logql.SelectLog
 func (q *IngesterQuerier) SelectSample(ctx context.Context, params logql.SelectSampleParams) ([]iter.SampleIterator, error) {
 	resps, err := q.forAllIngesters(ctx, func(_ context.Context, client logproto.QuerierClient) (interface{}, error) {
 		stats.FromContext(ctx).AddIngesterReached(1)
-		return client.QuerySample(ctx, params.SampleQueryRequest)
+		querySampleClient, err := client.QuerySample(ctx, params.SampleQueryRequest)
+		if err != nil {
+			return nil, err
+		}
+		return peekQuerySampleClient(querySampleClient)
 	})
 	if err != nil {
 		return nil, err
@@ -135,6 +177,60 @@ func (q *IngesterQuerier) SelectSample(ctx context.Context, params logql.SelectS
 	return iterators, nil
 }
 
+// peekQueryClient waits for the first response of an ingester, so that an ingester rejecting the query, for
+// instance because it sheds query load, counts as a failed replica and the query is served by the other replicas.
+// The following responses are streamed as the iterators read them.
+func peekQueryClient(c logproto.Querier_QueryClient) (logproto.Querier_QueryClient, error) {
+	resp, err := c.Recv()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return &peekedQueryClient{Querier_QueryClient: c, first: resp, firstErr: err}, nil
+}
+
+type peekedQueryClient struct {
+	logproto.Querier_QueryClient
+	first    *logproto.QueryResponse
+	firstErr error
+	peeked   bool
+}
+
+func (c *peekedQueryClient) Recv() (*logproto.QueryResponse, error) {
+	if !c.peeked {
+		c.peeked = true
+		resp, err := c.first, c.firstErr
+		c.first = nil
+		return resp, err
+	}
+	return c.Querier_QueryClient.Recv()
+}
+
+// peekQuerySampleClient is the peekQueryClient of sample queries.
+func peekQuerySampleClient(c logproto.Querier_QuerySampleClient) (logproto.Querier_QuerySampleClient, error) {
+	resp, err := c.Recv()
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return &peekedQuerySampleClient{Querier_QuerySampleClient: c, first: resp, firstErr: err}, nil
+}
+
+type peekedQuerySampleClient struct {
+	logproto.Querier_QuerySampleClient
+	first    *logproto.SampleQueryResponse
+	firstErr error
+	peeked   bool
+}
+
+func (c *peekedQuerySampleClient) Recv() (*logproto.SampleQueryResponse, error) {
+	if !c.peeked {
+		c.peeked = true
+		resp, err := c.first, c.firstErr
+		c.first = nil
+		return resp, err
+	}
+	return c.Querier_QuerySampleClient.Recv()
+}
+
 func (q *IngesterQuerier) Label(ctx context.Context, req *logproto.LabelRequest) ([][]string, error) {
 	resps, err := q.forAllIngesters(ctx, func(ctx context.Context, client logproto.QuerierClient) (interface{}, error) {
 		return client.Label(ctx, req)
@@ -152,7 +248,13 @@ func (q *IngesterQuerier) Label(ctx context.Context, req *logproto.LabelRequest)
 }
 
 func (q *IngesterQuerier) Tail(ctx context.Context, req *logproto.TailRequest) (map[string]logproto.Querier_TailClient, error) {
-	resps, err := q.forAllIngesters(ctx, func(_ context.Context, client logproto.QuerierClient) (interface{}, error) {
+	// tailers connect to all the ingesters, as the disconnected ones are reconnected anyway.
+	replicationSet, err := q.ring.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return nil, err
+	}
+
+	resps, err := q.forGivenIngesters(ctx, replicationSet, func(_ context.Context, client logproto.QuerierClient) (interface{}, error) {
 		return client.Tail(ctx, req)
 	})
 	if err != nil {
@@ -317,6 +419,42 @@ func (q *IngesterQuerier) Stats(ctx context.Context, userID string, from, throug
 	return &merged, nil
 }
 
+// TopStreams returns the top streams of the tenant across all ingesters. The values of a stream are the highest
+// reported by its replicas.
+func (q *IngesterQuerier) TopStreams(ctx context.Context, req *logproto.TopStreamsRequest) (*logproto.TopStreamsResponse, error) {
+	resps, err := q.forAllIngesters(ctx, func(ctx context.Context, querierClient logproto.QuerierClient) (interface{}, error) {
+		return querierClient.GetTopStreams(ctx, req)
+	})
+	if err != nil {
+		if isUnimplementedCallError(err) {
+			// Handle communication with older ingesters gracefully
+			return &logproto.TopStreamsResponse{}, nil
+		}
+		return nil, err
+	}
+
+	merged := map[string]*logproto.TopStream{}
+	for _, resp := range resps {
+		for _, s := range resp.response.(*logproto.TopStreamsResponse).Streams {
+			m, ok := merged[s.Labels]
+			if !ok {
+				merged[s.Labels] = s
+				continue
+			}
+			m.BytesRate = math.Max64(m.BytesRate, s.BytesRate)
+			m.EntriesRate = math.Max64(m.EntriesRate, s.EntriesRate)
+			m.MemoryBytes = math.Max64(m.MemoryBytes, s.MemoryBytes)
+			m.Chunks = math.Max64(m.Chunks, s.Chunks)
+		}
+	}
+
+	streams := make([]*logproto.TopStream, 0, len(merged))
+	for _, s := range merged {
+		streams = append(streams, s)
+	}
+	return &logproto.TopStreamsResponse{Streams: ingester.SortTopStreams(streams, req.SortBy, req.Limit)}, nil
+}
+
 func convertMatchersToString(matchers []*labels.Matcher) string {
 	out := strings.Builder{}
 	out.WriteRune('{')
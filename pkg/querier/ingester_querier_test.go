@@ -3,6 +3,7 @@ package querier
 import (
 	"context"
 	"errors"
+	"io"
 	"sync"
 	"testing"
 	"time"
@@ -99,7 +100,7 @@ func TestIngesterQuerier_earlyExitOnQuorum(t *testing.T) {
 				ingesterQuerier, err := newIngesterQuerier(
 					mockIngesterClientConfig(),
 					newReadRingMock(ringIngesters, 1),
-					mockQuerierConfig().ExtraQueryDelay,
+					mockQuerierConfig(),
 					newIngesterClientMockFactory(ingesterClient),
 				)
 				require.NoError(t, err)
@@ -139,7 +140,11 @@ func TestIngesterQuerier_earlyExitOnQuorum(t *testing.T) {
 				})
 				return err
 			},
-			retVal: newQueryClientMock(),
+			retVal: func() interface{} {
+				c := newQueryClientMock()
+				c.On("Recv").Return(nil, io.EOF)
+				return c
+			}(),
 		},
 		"select_sample": {
 			method: "QuerySample",
@@ -149,7 +154,11 @@ func TestIngesterQuerier_earlyExitOnQuorum(t *testing.T) {
 				})
 				return err
 			},
-			retVal: newQuerySampleClientMock(),
+			retVal: func() interface{} {
+				c := newQuerySampleClientMock()
+				c.On("Recv").Return(nil, io.EOF)
+				return c
+			}(),
 		},
 		"tail": {
 			method: "Tail",
@@ -198,7 +207,7 @@ func TestIngesterQuerier_earlyExitOnQuorum(t *testing.T) {
 				ingesterQuerier, err := newIngesterQuerier(
 					mockIngesterClientConfig(),
 					newReadRingMock(ringIngesters, 1),
-					mockQuerierConfig().ExtraQueryDelay,
+					mockQuerierConfig(),
 					newIngesterClientMockFactory(ingesterClient),
 				)
 				require.NoError(t, err)
@@ -295,7 +304,7 @@ func TestQuerier_tailDisconnectedIngesters(t *testing.T) {
 			ingesterQuerier, err := newIngesterQuerier(
 				mockIngesterClientConfig(),
 				newReadRingMock(testData.ringIngesters, 0),
-				mockQuerierConfig().ExtraQueryDelay,
+				mockQuerierConfig(),
 				newIngesterClientMockFactory(ingesterClient),
 			)
 			require.NoError(t, err)
@@ -316,6 +325,99 @@ func TestQuerier_tailDisconnectedIngesters(t *testing.T) {
 	}
 }
 
+func TestIngesterQuerier_TopStreams(t *testing.T) {
+	ingesterClient := newQuerierClientMock()
+	ingesterClient.On("GetTopStreams", mock.Anything, mock.Anything, mock.Anything).Return(&logproto.TopStreamsResponse{Streams: []*logproto.TopStream{
+		{Labels: `{app="a"}`, BytesRate: 10, EntriesRate: 1, MemoryBytes: 100, Chunks: 2},
+		{Labels: `{app="b"}`, BytesRate: 15, EntriesRate: 3, MemoryBytes: 10, Chunks: 1},
+	}}, nil).Once()
+	ingesterClient.On("GetTopStreams", mock.Anything, mock.Anything, mock.Anything).Return(&logproto.TopStreamsResponse{Streams: []*logproto.TopStream{
+		{Labels: `{app="a"}`, BytesRate: 20, EntriesRate: 2, MemoryBytes: 50, Chunks: 1},
+		{Labels: `{app="c"}`, BytesRate: 5, EntriesRate: 5, MemoryBytes: 20, Chunks: 1},
+	}}, nil).Once()
+
+	ingesterQuerier, err := newIngesterQuerier(
+		mockIngesterClientConfig(),
+		newReadRingMock([]ring.InstanceDesc{mockInstanceDesc("1.1.1.1", ring.ACTIVE), mockInstanceDesc("2.2.2.2", ring.ACTIVE)}, 0),
+		mockQuerierConfig(),
+		newIngesterClientMockFactory(ingesterClient),
+	)
+	require.NoError(t, err)
+
+	resp, err := ingesterQuerier.TopStreams(context.Background(), &logproto.TopStreamsRequest{SortBy: "bytes_rate", Limit: 2})
+	require.NoError(t, err)
+	require.Equal(t, []*logproto.TopStream{
+		{Labels: `{app="a"}`, BytesRate: 20, EntriesRate: 2, MemoryBytes: 100, Chunks: 2},
+		{Labels: `{app="b"}`, BytesRate: 15, EntriesRate: 3, MemoryBytes: 10, Chunks: 1},
+	}, resp.Streams)
+}
+
+func TestIngesterQuerier_SelectLogsSheddingIngester(t *testing.T) {
+	newHealthy := func() *queryClientMock {
+		c := newQueryClientMock()
+		c.On("Recv").Return(mockQueryResponse([]logproto.Stream{mockStream(1, 1)}), nil).Once()
+		c.On("Recv").Return(nil, io.EOF)
+		return c
+	}
+	newShedQuerier := func(t *testing.T, shedding *queryClientMock) *IngesterQuerier {
+		ingesterClient := newQuerierClientMock()
+		ingesterClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(shedding, nil).Once()
+		ingesterClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(newHealthy(), nil).Once()
+		ingesterClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(newHealthy(), nil).Once()
+
+		ingesterQuerier, err := newIngesterQuerier(
+			mockIngesterClientConfig(),
+			newReadRingMock([]ring.InstanceDesc{mockInstanceDesc("1.1.1.1", ring.ACTIVE), mockInstanceDesc("2.2.2.2", ring.ACTIVE), mockInstanceDesc("3.3.3.3", ring.ACTIVE)}, 1),
+			mockQuerierConfig(),
+			newIngesterClientMockFactory(ingesterClient),
+		)
+		require.NoError(t, err)
+		return ingesterQuerier
+	}
+
+	t.Run("rejected", func(t *testing.T) {
+		rejecting := newQueryClientMock()
+		rejecting.On("Recv").Return(nil, errors.New("ingester is shedding query load"))
+
+		// one of the three replicas rejects the query, which is served by the other two.
+		iterators, err := newShedQuerier(t, rejecting).SelectLogs(context.Background(), logql.SelectLogParams{QueryRequest: &logproto.QueryRequest{Direction: logproto.FORWARD}})
+		require.NoError(t, err)
+		require.Len(t, iterators, 2)
+
+		for _, it := range iterators {
+			require.True(t, it.Next())
+			require.Equal(t, mockStream(1, 1).Entries[0], it.Entry())
+			require.False(t, it.Next())
+			require.NoError(t, it.Error())
+		}
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		// an ingester may also stop the query once it sent its first response.
+		stopping := newQueryClientMock()
+		stopping.On("Recv").Return(mockQueryResponse([]logproto.Stream{mockStream(1, 1)}), nil).Once()
+		stopping.On("Recv").Return(nil, errors.New("ingester is shedding query load"))
+
+		ingesterClient := newQuerierClientMock()
+		ingesterClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(stopping, nil).Once()
+		ingesterQuerier, err := newIngesterQuerier(
+			mockIngesterClientConfig(),
+			newReadRingMock([]ring.InstanceDesc{mockInstanceDesc("1.1.1.1", ring.ACTIVE)}, 0),
+			mockQuerierConfig(),
+			newIngesterClientMockFactory(ingesterClient),
+		)
+		require.NoError(t, err)
+
+		// the responses following the first one are streamed, so the query fails with the error of the ingester.
+		iterators, err := ingesterQuerier.SelectLogs(context.Background(), logql.SelectLogParams{QueryRequest: &logproto.QueryRequest{Direction: logproto.FORWARD}})
+		require.NoError(t, err)
+		require.Len(t, iterators, 1)
+		require.True(t, iterators[0].Next())
+		require.False(t, iterators[0].Next())
+		require.ErrorContains(t, iterators[0].Error(), "ingester is shedding query load")
+	})
+}
+
 func TestConvertMatchersToString(t *testing.T) {
 	for _, tc := range []struct {
 		name     string
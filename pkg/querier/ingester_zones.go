@@ -0,0 +1,101 @@
+package querier
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+
+	"github.com/grafana/dskit/ring"
+)
+
+// doMinimumZones is the equivalent of ring.ReplicationSet.Do for zone aware replication sets, which only runs f for
+// the instances of the minimum number of zones needed for a complete result: a write succeeds once all the zones but
+// the ones allowed to be unavailable received it, so any set of as many zones holds every entry. The zones are picked
+// at random to spread the load, and whenever an instance fails, f is run for the instances of one of the remaining zones.
+// It returns the results of all the successful instances, or an error once more zones than allowed failed.
+func doMinimumZones(ctx context.Context, replicationSet ring.ReplicationSet, f func(context.Context, *ring.InstanceDesc) (interface{}, error)) ([]interface{}, error) {
+	instancesByZone := map[string][]*ring.InstanceDesc{}
+	for i := range replicationSet.Instances {
+		instance := &replicationSet.Instances[i]
+		instancesByZone[instance.Zone] = append(instancesByZone[instance.Zone], instance)
+	}
+
+	zones := make([]string, 0, len(instancesByZone))
+	for zone := range instancesByZone {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	rand.Shuffle(len(zones), func(i, j int) { zones[i], zones[j] = zones[j], zones[i] })
+
+	minSuccessfulZones := len(zones) - replicationSet.MaxUnavailableZones
+	if minSuccessfulZones <= 0 {
+		minSuccessfulZones = 1
+	}
+
+	type instanceResult struct {
+		res      interface{}
+		err      error
+		instance *ring.InstanceDesc
+	}
+
+	var (
+		ch          = make(chan instanceResult, len(replicationSet.Instances))
+		waiting     = map[string]int{}
+		failedZones = map[string]bool{}
+		nextZone    int
+	)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	startZone := func() {
+		zone := zones[nextZone]
+		nextZone++
+		waiting[zone] = len(instancesByZone[zone])
+		for _, instance := range instancesByZone[zone] {
+			go func(instance *ring.InstanceDesc) {
+				result, err := f(ctx, instance)
+				ch <- instanceResult{
+					res:      result,
+					err:      err,
+					instance: instance,
+				}
+			}(instance)
+		}
+	}
+	for nextZone < minSuccessfulZones && nextZone < len(zones) {
+		startZone()
+	}
+
+	results := make([]interface{}, 0, len(replicationSet.Instances))
+	successfulZones := 0
+
+	for successfulZones < minSuccessfulZones {
+		select {
+		case res := <-ch:
+			zone := res.instance.Zone
+			waiting[zone]--
+
+			if res.err != nil {
+				if failedZones[zone] {
+					continue
+				}
+				failedZones[zone] = true
+				if len(failedZones) > replicationSet.MaxUnavailableZones || nextZone == len(zones) {
+					return nil, res.err
+				}
+				startZone()
+				continue
+			}
+
+			results = append(results, res.res)
+			if waiting[zone] == 0 && !failedZones[zone] {
+				successfulZones++
+			}
+
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	return results, nil
+}
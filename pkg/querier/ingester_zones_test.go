@@ -0,0 +1,121 @@
+package querier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/grafana/dskit/ring"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func zoneAwareReplicationSet(zones, instancesPerZone int) ring.ReplicationSet {
+	var instances []ring.InstanceDesc
+	for z := 0; z < zones; z++ {
+		for i := 0; i < instancesPerZone; i++ {
+			instance := mockInstanceDesc(fmt.Sprintf("10.0.%d.%d", z, i), ring.ACTIVE)
+			instance.Zone = fmt.Sprintf("zone-%d", z)
+			instances = append(instances, instance)
+		}
+	}
+	return ring.ReplicationSet{Instances: instances, MaxUnavailableZones: zones / 2}
+}
+
+func TestDoMinimumZones(t *testing.T) {
+	for name, tc := range map[string]struct {
+		// the first instance called in each of the first failingZones zones fails.
+		failingZones  int
+		expectedZones int
+		expectedErr   bool
+	}{
+		"queries the minimum number of zones": {
+			expectedZones: 2,
+		},
+		"queries another zone when an instance fails": {
+			failingZones:  1,
+			expectedZones: 3,
+		},
+		"fails when more zones than allowed fail": {
+			failingZones: 2,
+			expectedErr:  true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var (
+				mtx    sync.Mutex
+				calls  int
+				zones  = map[string]struct{}{}
+				failed = map[string]struct{}{}
+			)
+			results, err := doMinimumZones(context.Background(), zoneAwareReplicationSet(3, 2), func(_ context.Context, instance *ring.InstanceDesc) (interface{}, error) {
+				mtx.Lock()
+				defer mtx.Unlock()
+				calls++
+				_, seen := zones[instance.Zone]
+				zones[instance.Zone] = struct{}{}
+
+				if !seen && len(failed) < tc.failingZones {
+					failed[instance.Zone] = struct{}{}
+					return nil, errors.New("failed")
+				}
+				return instance.Addr, nil
+			})
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			require.Len(t, zones, tc.expectedZones)
+			// all the instances of the successful zones returned a result.
+			require.GreaterOrEqual(t, len(results), 4)
+			if tc.failingZones == 0 {
+				require.Equal(t, 4, calls)
+				require.Len(t, results, 4)
+			}
+		})
+	}
+}
+
+func TestIngesterQuerier_MinimizeIngesterRequests(t *testing.T) {
+	for name, tc := range map[string]struct {
+		replicationSet ring.ReplicationSet
+		expectedCalls  int
+	}{
+		"zone aware": {
+			replicationSet: zoneAwareReplicationSet(3, 2),
+			expectedCalls:  4,
+		},
+		"not zone aware": {
+			replicationSet: ring.ReplicationSet{
+				Instances: []ring.InstanceDesc{mockInstanceDesc("1.1.1.1", ring.ACTIVE), mockInstanceDesc("2.2.2.2", ring.ACTIVE), mockInstanceDesc("3.3.3.3", ring.ACTIVE)},
+			},
+			expectedCalls: 3,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ingesterClient := newQuerierClientMock()
+			ingesterClient.On("Label", mock.Anything, mock.Anything, mock.Anything).Return(&logproto.LabelResponse{Values: []string{"foo"}}, nil)
+
+			readRing := newReadRingMock(nil, 0)
+			readRing.replicationSet = tc.replicationSet
+
+			cfg := mockQuerierConfig()
+			cfg.MinimizeIngesterRequests = true
+			ingesterQuerier, err := newIngesterQuerier(mockIngesterClientConfig(), readRing, cfg, newIngesterClientMockFactory(ingesterClient))
+			require.NoError(t, err)
+
+			values, err := ingesterQuerier.Label(context.Background(), &logproto.LabelRequest{})
+			require.NoError(t, err)
+			require.Len(t, values, tc.expectedCalls)
+			ingesterClient.AssertNumberOfCalls(t, "Label", tc.expectedCalls)
+		})
+	}
+}
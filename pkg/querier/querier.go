@@ -3,6 +3,7 @@ package querier
 import (
 	"context"
 	"flag"
+	"math"
 	"net/http"
 	"time"
 
@@ -56,12 +57,18 @@ type Config struct {
 	MultiTenantQueriesEnabled     bool             `yaml:"multi_tenant_queries_enabled"`
 	QueryTimeout                  time.Duration    `yaml:"query_timeout" doc:"hidden"`
 	PerRequestLimitsEnabled       bool             `yaml:"per_request_limits_enabled"`
+	MergeStreamShards             bool             `yaml:"merge_stream_shards"`
+	MinimizeIngesterRequests      bool             `yaml:"minimize_ingester_requests"`
+	TailMaxCatchUp                time.Duration    `yaml:"tail_max_catch_up"`
+	TailReplayLookback            time.Duration    `yaml:"tail_replay_lookback"`
 }
 
 // RegisterFlags register flags.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.Engine.RegisterFlagsWithPrefix("querier", f)
 	f.DurationVar(&cfg.TailMaxDuration, "querier.tail-max-duration", 1*time.Hour, "Maximum duration for which the live tailing requests are served.")
+	f.DurationVar(&cfg.TailMaxCatchUp, "querier.tail-max-catch-up", 10*time.Minute, "Maximum duration of the entries replayed when a live tailing request resumes from the cursor of a previous one. Older missed entries aren't replayed. 0 to disable resuming live tailing requests.")
+	f.DurationVar(&cfg.TailReplayLookback, "querier.tail-replay-lookback", 30*time.Second, "How long before the latest entry sent a live tailing request resuming from a cursor replays the entries, to send the entries the ingesters received late, for instance out of order. The entries already sent aren't sent again.")
 	f.DurationVar(&cfg.ExtraQueryDelay, "querier.extra-query-delay", 0, "Time to wait before sending more than the minimum successful query requests.")
 	f.DurationVar(&cfg.QueryIngestersWithin, "querier.query-ingesters-within", 3*time.Hour, "Maximum lookback beyond which queries are not sent to ingester. 0 means all queries are sent to ingester.")
 	f.IntVar(&cfg.MaxConcurrent, "querier.max-concurrent", 10, "The maximum number of concurrent queries allowed.")
@@ -69,6 +76,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.BoolVar(&cfg.QueryIngesterOnly, "querier.query-ingester-only", false, "When true, queriers only query the ingesters, and not stored data. This is useful when the object store is unavailable.")
 	f.BoolVar(&cfg.MultiTenantQueriesEnabled, "querier.multi-tenant-queries-enabled", false, "When true, allow queries to span multiple tenants.")
 	f.BoolVar(&cfg.PerRequestLimitsEnabled, "querier.per-request-limits-enabled", false, "When true, querier limits sent via a header are enforced.")
+	f.BoolVar(&cfg.MergeStreamShards, "querier.merge-stream-shards", true, "When true, the shards of streams sharded by the distributor are merged back into one stream in log query results and in the label and series APIs, unless the stream selector matches on the __stream_shard__ label.")
+	f.BoolVar(&cfg.MinimizeIngesterRequests, "querier.minimize-ingester-requests", false, "When true, and the ingesters are zone aware, only the ingesters of the minimum number of zones needed for a complete result are queried, instead of all of them. When an ingester fails, the ingesters of another zone are queried. Tail requests are still sent to all the ingesters.")
 }
 
 // Validate validates the config.
@@ -86,6 +95,7 @@ type Querier interface {
 	Series(ctx context.Context, req *logproto.SeriesRequest) (*logproto.SeriesResponse, error)
 	Tail(ctx context.Context, req *logproto.TailRequest) (*Tailer, error)
 	IndexStats(ctx context.Context, req *loghttp.RangeQuery) (*stats.Stats, error)
+	TopStreams(ctx context.Context, req *logproto.TopStreamsRequest) (*logproto.TopStreamsResponse, error)
 }
 
 type Limits interface {
@@ -172,10 +182,24 @@ func (q *SingleTenantQuerier) SelectLogs(ctx context.Context, params logql.Selec
 
 		iters = append(iters, storeIter)
 	}
+
+	var it iter.EntryIterator
 	if len(iters) == 1 {
-		return iters[0], nil
+		it = iters[0]
+	} else {
+		it = iter.NewMergeEntryIterator(ctx, iters, params.Direction)
 	}
-	return iter.NewMergeEntryIterator(ctx, iters, params.Direction), nil
+
+	if q.cfg.MergeStreamShards {
+		selector, err := params.LogSelector()
+		if err != nil {
+			return nil, err
+		}
+		if !matchesStreamShards(selector.Matchers()) {
+			it = newStreamShardsMergingIterator(it)
+		}
+	}
+	return it, nil
 }
 
 func (q *SingleTenantQuerier) SelectSamples(ctx context.Context, params logql.SelectSampleParams) (iter.SampleIterator, error) {
@@ -423,8 +447,12 @@ func (q *SingleTenantQuerier) Label(ctx context.Context, req *logproto.LabelRequ
 	}
 
 	results := append(ingesterValues, storeValues)
+	values := listutil.MergeStringLists(results...)
+	if q.cfg.MergeStreamShards && !req.Values && !matchesStreamShards(matchers) {
+		values = removeShardLabelName(values)
+	}
 	return &logproto.LabelResponse{
-		Values: listutil.MergeStringLists(results...),
+		Values: values,
 	}, nil
 }
 
@@ -440,6 +468,28 @@ func (q *SingleTenantQuerier) Tail(ctx context.Context, req *logproto.TailReques
 		return nil, err
 	}
 
+	tenantID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load tenant")
+	}
+
+	// The tail resumes after the entries sent before its cursor, and replays the entries missed since then.
+	delayFor := time.Duration(req.DelayFor) * time.Second
+	cursor := newTailCursor(time.Now().Add(-delayFor))
+	var resumed *tailCursor
+	if req.Cursor != "" && q.cfg.TailMaxCatchUp > 0 {
+		resumed, err = parseTailCursor(req.Cursor)
+		if err != nil {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, err.Error())
+		}
+		cursor = resumed.clone()
+
+		req.Start = resumed.start(q.cfg.TailReplayLookback)
+		if minStart := time.Now().Add(-q.cfg.TailMaxCatchUp); req.Start.Before(minStart) {
+			req.Start = minStart
+		}
+	}
+
 	deletes, err := q.deletesForUser(ctx, req.Start, time.Now())
 	if err != nil {
 		level.Error(spanlogger.FromContext(ctx)).Log("msg", "failed loading deletes for user", "err", err)
@@ -455,6 +505,14 @@ func (q *SingleTenantQuerier) Tail(ctx context.Context, req *logproto.TailReques
 			Deletes:   deletes,
 		},
 	}
+	if resumed != nil {
+		// the catch-up is read in pages of the maximum number of entries of a query.
+		histReq.Direction = logproto.FORWARD
+		histReq.Limit = math.MaxUint32
+		if limit := q.limits.MaxEntriesLimitPerQuery(ctx, tenantID); limit > 0 {
+			histReq.Limit = uint32(limit)
+		}
+	}
 
 	histReq.Start, histReq.End, err = q.validateQueryRequest(ctx, histReq)
 	if err != nil {
@@ -464,10 +522,6 @@ func (q *SingleTenantQuerier) Tail(ctx context.Context, req *logproto.TailReques
 	// Enforce the query timeout except when tailing, otherwise the tailing
 	// will be terminated once the query timeout is reached
 	tailCtx := ctx
-	tenantID, err := tenant.TenantID(tailCtx)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to load tenant")
-	}
 	queryTimeout := q.limits.QueryTimeout(tailCtx, tenantID)
 	// TODO: remove this clause once we remove the deprecated query-timeout flag.
 	if q.cfg.QueryTimeout != 0 { // querier YAML configuration.
@@ -482,20 +536,39 @@ func (q *SingleTenantQuerier) Tail(ctx context.Context, req *logproto.TailReques
 		return nil, err
 	}
 
-	histIterators, err := q.SelectLogs(queryCtx, histReq)
-	if err != nil {
-		return nil, err
-	}
-
-	reversedIterator, err := iter.NewReversedIter(histIterators, req.Limit, true)
-	if err != nil {
-		return nil, err
+	var historicEntries iter.EntryIterator
+	if resumed != nil {
+		// the pages after the first one are selected whilst tailing, each with its own query timeout.
+		selectPage := func(start time.Time, limit uint32) (iter.EntryIterator, uint32, error) {
+			pageCtx, cancel := context.WithDeadline(tailCtx, time.Now().Add(queryTimeout))
+			defer cancel()
+			page, size, err := q.selectTailCatchUpPage(pageCtx, histReq, start, limit)
+			if err != nil {
+				level.Error(util_log.Logger).Log("msg", "failed replaying the missed entries of a resumed tail", "start", start, "err", err)
+			}
+			return page, size, err
+		}
+		first, size, err := q.selectTailCatchUpPage(queryCtx, histReq, histReq.Start, histReq.Limit)
+		if err != nil {
+			return nil, err
+		}
+		historicEntries = newTailCatchUpIterator(first, size, selectPage, resumed, histReq.Limit)
+	} else {
+		histIterators, err := q.SelectLogs(queryCtx, histReq)
+		if err != nil {
+			return nil, err
+		}
+		historicEntries, err = iter.NewReversedIter(histIterators, req.Limit, true)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return newTailer(
-		time.Duration(req.DelayFor)*time.Second,
+		delayFor,
 		tailClients,
-		reversedIterator,
+		historicEntries,
+		cursor,
 		func(connectedIngestersAddr []string) (map[string]logproto.Querier_TailClient, error) {
 			return q.ingesterQuerier.TailDisconnectedIngesters(tailCtx, req, connectedIngestersAddr)
 		},
@@ -505,6 +578,26 @@ func (q *SingleTenantQuerier) Tail(ctx context.Context, req *logproto.TailReques
 	), nil
 }
 
+// selectTailCatchUpPage reads the at most limit first entries of the catch-up of a resumed tail from start, in the
+// order of their timestamps.
+func (q *SingleTenantQuerier) selectTailCatchUpPage(ctx context.Context, params logql.SelectLogParams, start time.Time, limit uint32) (iter.EntryIterator, uint32, error) {
+	req := *params.QueryRequest
+	req.Start = start
+	req.Limit = limit
+
+	it, err := q.SelectLogs(ctx, logql.SelectLogParams{QueryRequest: &req})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer it.Close()
+
+	resp, size, err := iter.ReadBatch(it, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return iter.NewQueryResponseIterator(resp, logproto.FORWARD), size, nil
+}
+
 // Series fetches any matching series for a list of matcher sets
 func (q *SingleTenantQuerier) Series(ctx context.Context, req *logproto.SeriesRequest) (*logproto.SeriesResponse, error) {
 	userID, err := tenant.TenantID(ctx)
@@ -600,6 +693,23 @@ func (q *SingleTenantQuerier) awaitSeries(ctx context.Context, req *logproto.Ser
 		response.Series = append(response.Series, s)
 	}
 
+	if q.cfg.MergeStreamShards {
+		mergeShards := true
+		for _, group := range req.GetGroups() {
+			matchers, err := syntax.ParseMatchers(group)
+			if err != nil {
+				return nil, err
+			}
+			if matchesStreamShards(matchers) {
+				mergeShards = false
+				break
+			}
+		}
+		if mergeShards {
+			response.Series = mergeSeriesShards(response.Series)
+		}
+	}
+
 	return response, nil
 }
 
@@ -764,3 +874,16 @@ func (q *SingleTenantQuerier) IndexStats(ctx context.Context, req *loghttp.Range
 	)
 
 }
+
+// TopStreams returns the top streams of the tenant held by the ingesters.
+func (q *SingleTenantQuerier) TopStreams(ctx context.Context, req *logproto.TopStreamsRequest) (*logproto.TopStreamsResponse, error) {
+	userID, err := tenant.TenantID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, time.Now().Add(q.limits.QueryTimeout(ctx, userID)))
+	defer cancel()
+
+	return q.ingesterQuerier.TopStreams(ctx, req)
+}
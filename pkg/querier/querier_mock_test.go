@@ -104,6 +104,15 @@ func (c *querierClientMock) GetChunkIDs(ctx context.Context, in *logproto.GetChu
 	return res.(*logproto.GetChunkIDsResponse), args.Error(1)
 }
 
+func (c *querierClientMock) GetTopStreams(ctx context.Context, in *logproto.TopStreamsRequest, opts ...grpc.CallOption) (*logproto.TopStreamsResponse, error) {
+	args := c.Called(ctx, in, opts)
+	res := args.Get(0)
+	if res == nil {
+		return (*logproto.TopStreamsResponse)(nil), args.Error(1)
+	}
+	return res.(*logproto.TopStreamsResponse), args.Error(1)
+}
+
 func (c *querierClientMock) Context() context.Context {
 	return context.Background()
 }
@@ -520,3 +529,7 @@ func (q *querierMock) Tail(ctx context.Context, req *logproto.TailRequest) (*Tai
 func (q *querierMock) IndexStats(ctx context.Context, req *loghttp.RangeQuery) (*stats.Stats, error) {
 	return nil, nil
 }
+
+func (q *querierMock) TopStreams(ctx context.Context, req *logproto.TopStreamsRequest) (*logproto.TopStreamsResponse, error) {
+	return nil, nil
+}
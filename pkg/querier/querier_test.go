@@ -3,6 +3,7 @@ package querier
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"testing"
@@ -19,6 +20,7 @@ import (
 	"github.com/weaveworks/common/user"
 
 	"github.com/grafana/loki/pkg/ingester/client"
+	"github.com/grafana/loki/pkg/iter"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/storage"
@@ -140,6 +142,125 @@ func TestQuerier_Tail_QueryTimeoutConfigFlag(t *testing.T) {
 	store.AssertExpectations(t)
 }
 
+func TestQuerier_Tail_ResumeFromCursor(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	stream := logproto.Stream{Labels: `{type="test"}`}
+	for i := 0; i < 5; i++ {
+		stream.Entries = append(stream.Entries, logproto.Entry{Timestamp: now.Add(time.Duration(i-5) * time.Minute), Line: fmt.Sprintf("line %d", i)})
+	}
+	// an entry at the same time as the last one sent, which wasn't sent.
+	stream.Entries = append(stream.Entries[:3], append([]logproto.Entry{{Timestamp: stream.Entries[2].Timestamp, Line: "line 2b"}}, stream.Entries[3:]...)...)
+
+	cursor := newTailCursor(now.Add(-time.Hour))
+	for _, e := range stream.Entries[:3] {
+		cursor.advance(stream.Labels, e)
+	}
+
+	// the store returns the entries from the start of each page of the catch-up.
+	from := func(i int) iter.EntryIterator {
+		return iter.NewStreamIterator(logproto.Stream{Labels: stream.Labels, Entries: stream.Entries[i:]})
+	}
+	store := newStoreMock()
+	store.On("SelectLogs", mock.Anything, mock.Anything).Return(from(2), nil).Once()
+	store.On("SelectLogs", mock.Anything, mock.Anything).Return(from(2), nil).Once()
+	store.On("SelectLogs", mock.Anything, mock.Anything).Return(from(4), nil).Once()
+	store.On("SelectLogs", mock.Anything, mock.Anything).Return(from(5), nil).Once()
+
+	queryClient := newQueryClientMock()
+	queryClient.On("Recv").Return(nil, io.EOF)
+
+	tailClient := newTailClientMock()
+	tailClient.On("Recv").Return(nil, io.EOF)
+
+	ingesterClient := newQuerierClientMock()
+	ingesterClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(queryClient, nil)
+	ingesterClient.On("Tail", mock.Anything, mock.Anything, mock.Anything).Return(tailClient, nil)
+	ingesterClient.On("TailersCount", mock.Anything, mock.Anything, mock.Anything).Return(&logproto.TailersCountResponse{}, nil)
+
+	// the catch-up is read in pages of 2 entries.
+	defaultLimits := defaultLimitsTestConfig()
+	defaultLimits.MaxEntriesLimitPerQuery = 2
+	limits, err := validation.NewOverrides(defaultLimits, nil)
+	require.NoError(t, err)
+
+	cfg := mockQuerierConfig()
+	cfg.TailMaxCatchUp = time.Hour
+	cfg.TailReplayLookback = 30 * time.Second
+	q, err := newQuerier(cfg, mockIngesterClientConfig(), newIngesterClientMockFactory(ingesterClient), mockReadRingWithOneActiveIngester(), &mockDeleteGettter{}, store, limits)
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	tailer, err := q.Tail(ctx, &logproto.TailRequest{
+		Query:  `{type="test"}`,
+		Limit:  10,
+		Start:  now,
+		Cursor: cursor.encode(),
+	})
+	require.NoError(t, err)
+	defer func() { require.NoError(t, tailer.close()) }()
+
+	// the missed entries are replayed from the position of the cursor.
+	calls := store.GetMockedCallsByMethod("SelectLogs")
+	require.Len(t, calls, 1)
+	params := calls[0].Arguments.Get(1).(logql.SelectLogParams)
+	require.Equal(t, logproto.FORWARD, params.Direction)
+	require.Equal(t, uint32(2), params.Limit)
+	require.Equal(t, stream.Entries[2].Timestamp.Add(-cfg.TailReplayLookback), params.Start)
+
+	var (
+		lines   []string
+		resumed *tailCursor
+	)
+	timeout := time.After(5 * time.Second)
+	for len(lines) < 3 {
+		select {
+		case resp := <-tailer.getResponseChan():
+			for _, s := range resp.Streams {
+				for _, e := range s.Entries {
+					lines = append(lines, e.Line)
+				}
+			}
+			resumed, err = parseTailCursor(resp.Cursor)
+			require.NoError(t, err)
+		case <-timeout:
+			t.Fatalf("timed out waiting for the replayed entries, got %v", lines)
+		}
+	}
+	require.Equal(t, []string{"line 2b", "line 3", "line 4"}, lines)
+
+	// the following pages start at the last entry of the previous one, or right after it when the whole page has the
+	// same timestamp.
+	calls = store.GetMockedCallsByMethod("SelectLogs")
+	require.Len(t, calls, 4)
+	for i, start := range []time.Time{stream.Entries[2].Timestamp, stream.Entries[2].Timestamp.Add(time.Nanosecond), stream.Entries[5].Timestamp} {
+		require.Equal(t, start, calls[i+1].Arguments.Get(1).(logql.SelectLogParams).Start)
+	}
+
+	// the cursor of the responses moves past the replayed entries.
+	for _, e := range stream.Entries {
+		require.True(t, resumed.sent(stream.Labels, e))
+	}
+}
+
+func TestQuerier_Tail_InvalidCursor(t *testing.T) {
+	limits, err := validation.NewOverrides(defaultLimitsTestConfig(), nil)
+	require.NoError(t, err)
+
+	ingesterClient := newQuerierClientMock()
+	ingesterClient.On("TailersCount", mock.Anything, mock.Anything, mock.Anything).Return(&logproto.TailersCountResponse{}, nil)
+
+	cfg := mockQuerierConfig()
+	cfg.TailMaxCatchUp = time.Hour
+	q, err := newQuerier(cfg, mockIngesterClientConfig(), newIngesterClientMockFactory(ingesterClient), mockReadRingWithOneActiveIngester(), &mockDeleteGettter{}, newStoreMock(), limits)
+	require.NoError(t, err)
+
+	_, err = q.Tail(user.InjectOrgID(context.Background(), "test"), &logproto.TailRequest{
+		Query:  `{type="test"}`,
+		Cursor: "invalid",
+	})
+	require.Equal(t, httpgrpc.Errorf(http.StatusBadRequest, errInvalidTailCursor.Error()), err)
+}
+
 func mockQuerierConfig() Config {
 	return Config{
 		TailMaxDuration: 1 * time.Minute,
@@ -152,6 +273,12 @@ func mockQueryResponse(streams []logproto.Stream) *logproto.QueryResponse {
 	}
 }
 
+func mockSampleQueryResponse(series []logproto.Series) *logproto.SampleQueryResponse {
+	return &logproto.SampleQueryResponse{
+		Series: series,
+	}
+}
+
 func mockLabelResponse(values []string) *logproto.LabelResponse {
 	return &logproto.LabelResponse{
 		Values: values,
@@ -971,7 +1098,7 @@ func setupIngesterQuerierMocks(conf Config, limits *validation.Overrides) (*quer
 	queryClient.On("Recv").Return(mockQueryResponse([]logproto.Stream{mockStream(1, 1)}), nil)
 
 	querySampleClient := newQuerySampleClientMock()
-	querySampleClient.On("Recv").Return(mockQueryResponse([]logproto.Stream{mockStream(1, 1)}), nil)
+	querySampleClient.On("Recv").Return(mockSampleQueryResponse([]logproto.Series{{Labels: `{type="test"}`, Samples: []logproto.Sample{{Timestamp: 1, Value: 1}}}}), nil)
 
 	ingesterClient := newQuerierClientMock()
 	ingesterClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(queryClient, nil)
@@ -1116,7 +1243,7 @@ func TestQuerier_SelectLogWithDeletes(t *testing.T) {
 
 func TestQuerier_SelectSamplesWithDeletes(t *testing.T) {
 	queryClient := newQuerySampleClientMock()
-	queryClient.On("Recv").Return(mockQueryResponse([]logproto.Stream{mockStream(1, 2)}), nil)
+	queryClient.On("Recv").Return(mockSampleQueryResponse([]logproto.Series{{Labels: `{type="test"}`, Samples: []logproto.Sample{{Timestamp: 1, Value: 1}}}}), nil)
 
 	store := newStoreMock()
 	store.On("SelectSamples", mock.Anything, mock.Anything).Return(mockSampleIterator(queryClient), nil)
@@ -1175,7 +1302,7 @@ func TestQuerier_SelectSamplesWithDeletes(t *testing.T) {
 }
 
 func newQuerier(cfg Config, clientCfg client.Config, clientFactory ring_client.PoolFactory, ring ring.ReadRing, dg *mockDeleteGettter, store storage.Store, limits *validation.Overrides) (*SingleTenantQuerier, error) {
-	iq, err := newIngesterQuerier(clientCfg, ring, cfg.ExtraQueryDelay, clientFactory)
+	iq, err := newIngesterQuerier(clientCfg, ring, cfg, clientFactory)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,47 @@
+package queryrange
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grafana/dskit/tenant"
+	"github.com/weaveworks/common/middleware"
+
+	"github.com/grafana/loki/pkg/lokifrontend/audit"
+	"github.com/grafana/loki/pkg/util/httpreq"
+)
+
+// AuditHTTPMiddleware records every query in the audit log. It relies on the data collected by the
+// StatsHTTPMiddleware, so it must be placed after it.
+func AuditHTTPMiddleware(auditor *audit.Logger) middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			interceptor := &interceptor{ResponseWriter: w, statusCode: http.StatusOK}
+			next.ServeHTTP(interceptor, r)
+
+			ctx := r.Context()
+			record := audit.Record{
+				Timestamp: time.Now(),
+				User:      httpreq.ExtractQueryUser(ctx),
+				Path:      r.URL.Path,
+				Status:    interceptor.statusCode,
+				Duration:  time.Since(start),
+			}
+			if tenantIDs, err := tenant.TenantIDs(ctx); err == nil {
+				record.Tenant = tenant.JoinTenantIDs(tenantIDs)
+			}
+			if data, ok := ctx.Value(ctxKey).(*queryData); ok {
+				if data.params != nil {
+					record.Query = data.params.Query()
+					record.Start = data.params.Start()
+					record.End = data.params.End()
+				}
+				if data.statistics != nil {
+					record.BytesProcessed = data.statistics.Summary.TotalBytesProcessed
+				}
+			}
+			auditor.Log(record)
+		})
+	})
+}
@@ -0,0 +1,97 @@
+package queryrange
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/middleware"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logqlmodel/stats"
+	"github.com/grafana/loki/pkg/lokifrontend/audit"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/util/httpreq"
+)
+
+func Test_AuditHTTP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditor, err := audit.New(audit.Config{
+		Enabled:   true,
+		Sink:      audit.SinkFile,
+		QueueSize: 10,
+		BatchSize: 10,
+		BatchWait: time.Minute,
+		File:      audit.FileConfig{Path: path},
+	}, log.NewNopLogger(), nil)
+	require.NoError(t, err)
+
+	req := &LokiRequest{
+		Query:     `{app="foo"}`,
+		Limit:     100,
+		StartTs:   time.Unix(0, 0),
+		EndTs:     time.Unix(3600, 0),
+		Direction: logproto.BACKWARD,
+		Path:      "/loki/api/v1/query_range",
+	}
+
+	handler := func(fail bool) http.Handler {
+		collector := StatsCollectorMiddleware().Wrap(queryrangebase.HandlerFunc(func(ctx context.Context, _ queryrangebase.Request) (queryrangebase.Response, error) {
+			if fail {
+				return nil, errors.New("querier unavailable")
+			}
+			return &LokiResponse{
+				Statistics: stats.Result{Querier: stats.Querier{Store: stats.Store{Chunk: stats.Chunk{DecompressedBytes: 2048}}}},
+			}, nil
+		}))
+		return middleware.Merge(
+			StatsHTTPMiddleware,
+			AuditHTTPMiddleware(auditor),
+		).Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, err := collector.Do(r.Context(), req); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+	}
+
+	for _, fail := range []bool{false, true} {
+		ctx := user.InjectOrgID(context.Background(), "tenant-a")
+		ctx = context.WithValue(ctx, httpreq.QueryTagsHTTPHeader, "user=alice")
+		r := httptest.NewRequest(http.MethodGet, "/loki/api/v1/query_range", nil).WithContext(ctx)
+		handler(fail).ServeHTTP(httptest.NewRecorder(), r)
+	}
+	auditor.Stop()
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	require.Len(t, lines, 2)
+
+	for i, expected := range []struct {
+		status int
+		bytes  float64
+	}{
+		{http.StatusOK, 2048},
+		{http.StatusInternalServerError, 0},
+	} {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[i]), &record))
+		require.Equal(t, "tenant-a", record["tenant"])
+		require.Equal(t, "alice", record["user"])
+		require.Equal(t, "/loki/api/v1/query_range", record["path"])
+		require.Equal(t, `{app="foo"}`, record["query"])
+		require.Equal(t, "1970-01-01T01:00:00Z", record["end"])
+		require.Equal(t, float64(expected.status), record["status"])
+		require.Equal(t, expected.bytes, record["bytes_processed"])
+	}
+}
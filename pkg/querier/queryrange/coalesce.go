@@ -0,0 +1,116 @@
+package queryrange
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/util/httpreq"
+)
+
+type CoalescingMetrics struct {
+	coalesced prometheus.Counter
+}
+
+func NewCoalescingMetrics(r prometheus.Registerer) *CoalescingMetrics {
+	return &CoalescingMetrics{
+		coalesced: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: "loki",
+			Name:      "query_frontend_coalesced_requests_total",
+			Help:      "Total number of (sub-)requests that shared the execution of an identical in-flight request.",
+		}),
+	}
+}
+
+// NewCoalescingMiddleware creates a middleware that lets concurrent identical requests share a single execution.
+// Requests are identical if they belong to the same tenants, are issued by the same query user and actor with the same
+// priority, and only differ in the formatting of their query. Each request gets its own copy of the shared response.
+// The group is shared between all requests that pass the middleware, so it must be created once per tripperware.
+func NewCoalescingMiddleware(metrics *CoalescingMetrics) queryrangebase.Middleware {
+	if metrics == nil {
+		metrics = NewCoalescingMetrics(nil)
+	}
+	group := &singleflight.Group{}
+
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		return &coalescer{
+			group:   group,
+			next:    next,
+			metrics: metrics,
+		}
+	})
+}
+
+type coalescer struct {
+	group   *singleflight.Group
+	next    queryrangebase.Handler
+	metrics *CoalescingMetrics
+}
+
+func (c *coalescer) Do(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+	key, ok := coalescingKey(ctx, r)
+	if !ok {
+		return c.next.Do(ctx, r)
+	}
+
+	var executed bool
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		executed = true
+		return c.next.Do(ctx, r)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if !executed {
+			c.metrics.coalesced.Inc()
+		}
+		if res.Err != nil {
+			// The request that executed the shared query was canceled, e.g. because its client went away.
+			// This request is still alive, so it executes the query on its own.
+			if !executed && errors.Is(res.Err, context.Canceled) && ctx.Err() == nil {
+				return c.next.Do(ctx, r)
+			}
+			return nil, res.Err
+		}
+		// The response is shared with the other coalesced requests, so each of them gets its own copy.
+		return proto.Clone(res.Val.(queryrangebase.Response)).(queryrangebase.Response), nil
+	}
+}
+
+// coalescingKey returns the key of a request, which consists of its tenants, the headers that determine how it is
+// queued and accounted for, and the request with the normalised query. It returns false if the key cannot be built.
+func coalescingKey(ctx context.Context, r queryrangebase.Request) (string, bool) {
+	tenantIDs, err := tenant.TenantIDs(ctx)
+	if err != nil {
+		return "", false
+	}
+
+	if expr, err := syntax.ParseExpr(r.GetQuery()); err == nil {
+		r = r.WithQuery(expr.String())
+	}
+	buf, err := proto.Marshal(r)
+	if err != nil {
+		return "", false
+	}
+
+	key := []string{
+		tenant.JoinTenantIDs(tenantIDs),
+		httpreq.ExtractQueryUser(ctx),
+		httpreq.ExtractHeader(ctx, httpreq.LokiActorPathHeader),
+		httpreq.ExtractHeader(ctx, httpreq.LokiQueryPriorityHeader),
+		strconv.FormatBool(httpreq.IsRulerQuery(getQueryTags(ctx))),
+		string(buf),
+	}
+	return strings.Join(key, "\x00"), true
+}
@@ -0,0 +1,171 @@
+package queryrange
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/util/httpreq"
+)
+
+func Test_Coalescing(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		release = make(chan struct{})
+	)
+	next := queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		calls.Inc()
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return &LokiResponse{Status: loghttp.QueryStatusSuccess}, nil
+	})
+
+	metrics := NewCoalescingMetrics(nil)
+	handler := NewCoalescingMiddleware(metrics).Wrap(next)
+
+	newRequest := func(query string) *LokiRequest {
+		return &LokiRequest{
+			Query:     query,
+			Limit:     100,
+			StartTs:   time.Unix(0, 0),
+			EndTs:     time.Unix(3600, 0),
+			Direction: logproto.BACKWARD,
+			Path:      "/loki/api/v1/query_range",
+		}
+	}
+
+	var wg sync.WaitGroup
+	do := func(tenant, query string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := user.InjectOrgID(context.Background(), tenant)
+			res, err := handler.Do(ctx, newRequest(query))
+			require.NoError(t, err)
+			require.Equal(t, loghttp.QueryStatusSuccess, res.(*LokiResponse).Status)
+		}()
+	}
+
+	// Identical queries of the same tenant, formatted differently.
+	do("a", `{app="foo"} |= "bar"`)
+	do("a", `{app="foo"}|="bar"`)
+	do("a", `{app="foo"} |= "bar"`)
+	// A different tenant and a different query.
+	do("b", `{app="foo"} |= "bar"`)
+	do("a", `{app="foo"} |= "baz"`)
+
+	require.Eventually(t, func() bool {
+		return calls.Load() == 3
+	}, time.Second, time.Millisecond)
+	// Give the remaining requests time to join the in-flight executions.
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(3), calls.Load())
+	require.Equal(t, float64(2), testutil.ToFloat64(metrics.coalesced))
+}
+
+func Test_CoalescingCanceledLeader(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		started = make(chan struct{})
+	)
+	next := queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		if calls.Inc() == 1 {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &LokiResponse{Status: loghttp.QueryStatusSuccess}, nil
+	})
+	handler := NewCoalescingMiddleware(nil).Wrap(next)
+	req := &LokiRequest{Query: `{app="foo"}`, StartTs: time.Unix(0, 0), EndTs: time.Unix(3600, 0)}
+
+	leaderCtx, cancel := context.WithCancel(user.InjectOrgID(context.Background(), "a"))
+	go func() {
+		_, _ = handler.Do(leaderCtx, req)
+	}()
+	<-started
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		res, err := handler.Do(user.InjectOrgID(context.Background(), "a"), req)
+		require.NoError(t, err)
+		require.Equal(t, loghttp.QueryStatusSuccess, res.(*LokiResponse).Status)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func Test_CoalescingCallers(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		release = make(chan struct{})
+	)
+	next := queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		calls.Inc()
+		<-release
+		return &LokiResponse{Status: loghttp.QueryStatusSuccess}, nil
+	})
+	handler := NewCoalescingMiddleware(nil).Wrap(next)
+	req := &LokiRequest{Query: `{app="foo"}`, StartTs: time.Unix(0, 0), EndTs: time.Unix(3600, 0)}
+
+	var (
+		wg        sync.WaitGroup
+		mtx       sync.Mutex
+		responses []queryrangebase.Response
+	)
+	do := func(ctx context.Context) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := handler.Do(user.InjectOrgID(ctx, "a"), req)
+			require.NoError(t, err)
+			mtx.Lock()
+			defer mtx.Unlock()
+			responses = append(responses, res)
+		}()
+	}
+
+	ctx := context.Background()
+	do(ctx)
+	do(ctx)
+	// Requests of other query users, actors or priorities are not coalesced.
+	do(httpreq.InjectHeader(ctx, httpreq.LokiQueryUserHeader, "alice"))
+	do(context.WithValue(ctx, httpreq.QueryTagsHTTPHeader, "user=bob"))
+	do(httpreq.InjectHeader(ctx, httpreq.LokiActorPathHeader, "dashboard"))
+	do(httpreq.InjectHeader(ctx, httpreq.LokiQueryPriorityHeader, "batch"))
+	do(context.WithValue(ctx, httpreq.QueryTagsHTTPHeader, "ruler"))
+
+	require.Eventually(t, func() bool {
+		return calls.Load() == 6
+	}, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(6), calls.Load())
+	// Each caller gets its own copy of the response.
+	for i := range responses {
+		for j := i + 1; j < len(responses); j++ {
+			require.NotSame(t, responses[i], responses[j])
+		}
+	}
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/logqlmodel/stats"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
 	indexStats "github.com/grafana/loki/pkg/storage/stores/index/stats"
 	"github.com/grafana/loki/pkg/util"
 	"github.com/grafana/loki/pkg/util/httpreq"
@@ -286,6 +287,12 @@ func (Codec) EncodeRequest(ctx context.Context, r queryrangebase.Request) (*http
 		header.Set(httpreq.LokiActorPathHeader, actor)
 	}
 
+	for _, h := range []string{httpreq.LokiQueryUserHeader, httpreq.LokiQueryPriorityHeader} {
+		if v := httpreq.ExtractHeader(ctx, h); v != "" {
+			header.Set(h, v)
+		}
+	}
+
 	switch request := r.(type) {
 	case *LokiRequest:
 		params := url.Values{
@@ -484,6 +491,7 @@ func (Codec) DecodeResponse(ctx context.Context, r *http.Response, req queryrang
 					Headers: convertPrometheusResponseHeadersToPointers(httpResponseHeadersToPromResponseHeaders(r.Header)),
 				},
 				Statistics: resp.Data.Statistics,
+				Warnings:   resp.Warnings,
 			}, nil
 		case loghttp.ResultTypeStream:
 			// This is the same as in querysharding.go
@@ -511,7 +519,8 @@ func (Codec) DecodeResponse(ctx context.Context, r *http.Response, req queryrang
 					ResultType: loghttp.ResultTypeStream,
 					Result:     resp.Data.Result.(loghttp.Streams).ToProto(),
 				},
-				Headers: httpResponseHeadersToPromResponseHeaders(r.Header),
+				Headers:  httpResponseHeadersToPromResponseHeaders(r.Header),
+				Warnings: resp.Warnings,
 			}, nil
 		case loghttp.ResultTypeVector:
 			return &LokiPromResponse{
@@ -524,6 +533,7 @@ func (Codec) DecodeResponse(ctx context.Context, r *http.Response, req queryrang
 					Headers: convertPrometheusResponseHeadersToPointers(httpResponseHeadersToPromResponseHeaders(r.Header)),
 				},
 				Statistics: resp.Data.Statistics,
+				Warnings:   resp.Warnings,
 			}, nil
 		case loghttp.ResultTypeScalar:
 			return &LokiPromResponse{
@@ -536,6 +546,7 @@ func (Codec) DecodeResponse(ctx context.Context, r *http.Response, req queryrang
 					Headers: convertPrometheusResponseHeadersToPointers(httpResponseHeadersToPromResponseHeaders(r.Header)),
 				},
 				Statistics: resp.Data.Statistics,
+				Warnings:   resp.Warnings,
 			}, nil
 		default:
 			return nil, httpgrpc.Errorf(http.StatusInternalServerError, "unsupported response type, got (%s)", string(resp.Data.ResultType))
@@ -563,6 +574,7 @@ func (Codec) EncodeResponse(ctx context.Context, res queryrangebase.Response) (*
 		result := logqlmodel.Result{
 			Data:       logqlmodel.Streams(streams),
 			Statistics: response.Statistics,
+			Warnings:   response.Warnings,
 		}
 		if loghttp.Version(response.Version) == loghttp.VersionLegacy {
 			if err := marshal_legacy.WriteQueryResponseJSON(result, &buf); err != nil {
@@ -623,9 +635,11 @@ func (Codec) MergeResponse(responses ...queryrangebase.Response) (queryrangebase
 	case *LokiPromResponse:
 
 		promResponses := make([]queryrangebase.Response, 0, len(responses))
+		var warnings []definitions.QueryWarning
 		for _, res := range responses {
 			mergedStats.MergeSplit(res.(*LokiPromResponse).Statistics)
 			promResponses = append(promResponses, res.(*LokiPromResponse).Response)
+			warnings = append(warnings, res.(*LokiPromResponse).Warnings...)
 		}
 		promRes, err := queryrangebase.PrometheusCodec.MergeResponse(promResponses...)
 		if err != nil {
@@ -634,6 +648,7 @@ func (Codec) MergeResponse(responses ...queryrangebase.Response) (queryrangebase
 		return &LokiPromResponse{
 			Response:   promRes.(*queryrangebase.PrometheusResponse),
 			Statistics: mergedStats,
+			Warnings:   warnings,
 		}, nil
 	case *LokiResponse:
 		return mergeLokiResponse(responses...), nil
@@ -1065,12 +1080,14 @@ func mergeLokiResponse(responses ...queryrangebase.Response) *LokiResponse {
 	var (
 		lokiRes       = responses[0].(*LokiResponse)
 		mergedStats   stats.Result
+		warnings      []definitions.QueryWarning
 		lokiResponses = make([]*LokiResponse, 0, len(responses))
 	)
 
 	for _, res := range responses {
 		lokiResult := res.(*LokiResponse)
 		mergedStats.MergeSplit(lokiResult.Statistics)
+		warnings = append(warnings, lokiResult.Warnings...)
 		lokiResponses = append(lokiResponses, lokiResult)
 	}
 
@@ -1086,5 +1103,6 @@ func mergeLokiResponse(responses ...queryrangebase.Response) *LokiResponse {
 			ResultType: loghttp.ResultTypeStream,
 			Result:     mergeOrderedNonOverlappingStreams(lokiResponses, lokiRes.Limit, lokiRes.Direction),
 		},
+		Warnings: warnings,
 	}
 }
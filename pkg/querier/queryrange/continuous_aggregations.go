@@ -0,0 +1,75 @@
+package queryrange
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/loki/pkg/ingester/aggregations"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/util"
+	"github.com/grafana/loki/pkg/util/spanlogger"
+)
+
+// NewContinuousAggregationsMiddleware creates a middleware that answers the parts of metric queries that are the
+// query of a continuous aggregation of the tenant from the streams the ingesters materialised for it. The interval
+// is the one the ingesters write the aggregations for; 0 disables the middleware.
+func NewContinuousAggregationsMiddleware(limits Limits, interval time.Duration) queryrangebase.Middleware {
+	return queryrangebase.MiddlewareFunc(func(next queryrangebase.Handler) queryrangebase.Handler {
+		return queryrangebase.HandlerFunc(func(ctx context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+			tenantIDs, err := tenant.TenantIDs(ctx)
+			// The materialised streams belong to a single tenant.
+			if err != nil || len(tenantIDs) != 1 || interval <= 0 {
+				return next.Do(ctx, r)
+			}
+
+			query, used, ok := aggregations.Rewrite(limits.ContinuousAggregations(tenantIDs[0]), r.GetQuery(), util.TimeFromMillis(r.GetStart()))
+			if !ok {
+				return next.Do(ctx, r)
+			}
+
+			log := spanlogger.FromContext(ctx)
+			defer log.Finish()
+			for _, agg := range used {
+				if !covered(ctx, next, r, agg, interval) {
+					level.Debug(log).Log("msg", "continuous aggregation has gaps in the range of the query", "aggregation", agg.Name, "query", r.GetQuery())
+					return next.Do(ctx, r)
+				}
+			}
+
+			level.Debug(log).Log("msg", "answering query from continuous aggregations", "query", r.GetQuery(), "rewritten", query)
+			return next.Do(ctx, r.WithQuery(query))
+		})
+	})
+}
+
+// covered returns whether the aggregation was written for all the intervals in its range at every step of the
+// request, by counting the entries of its coverage stream.
+func covered(ctx context.Context, next queryrangebase.Handler, r queryrangebase.Request, agg *aggregations.Aggregation, interval time.Duration) bool {
+	resp, err := next.Do(ctx, r.WithQuery(agg.CoverageQuery()))
+	if err != nil {
+		return false
+	}
+	prom, ok := resp.(*LokiPromResponse)
+	if !ok || prom.Response == nil || len(prom.Response.Data.Result) != 1 {
+		return false
+	}
+
+	// Steps without any entry in their range have no sample.
+	samples := prom.Response.Data.Result[0].Samples
+	steps := 1
+	if r.GetStep() > 0 {
+		steps = int((r.GetEnd()-r.GetStart())/r.GetStep()) + 1
+	}
+	if len(samples) != steps {
+		return false
+	}
+	for _, s := range samples {
+		if s.Value < float64(agg.Intervals(interval)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package queryrange
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/loki/pkg/ingester/aggregations"
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+)
+
+func TestContinuousAggregationsMiddleware(t *testing.T) {
+	aggs := []*aggregations.Aggregation{{Name: "nginx", Query: `sum(count_over_time({app="nginx"}[1m]))`}}
+	require.NoError(t, aggregations.Validate(aggs))
+
+	req := &LokiRequest{
+		Query:   `sum(count_over_time({app="nginx"}[1m])) > 1`,
+		StartTs: time.Unix(3600, 0),
+		EndTs:   time.Unix(7200, 0),
+		Step:    60000,
+	}
+	rewritten := `(sum(max without (__aggregation_replica__)(sum_over_time({__aggregation__="nginx"} | regexp "(?P<__aggregation_value__>.+)" | unwrap __aggregation_value__[1m]))) > 1)`
+
+	// The coverage stream has 4 entries of 15s intervals in the range of every step.
+	var (
+		query    string
+		coverage []logproto.LegacySample
+	)
+	for ts := req.StartTs; !ts.After(req.EndTs); ts = ts.Add(time.Minute) {
+		coverage = append(coverage, logproto.LegacySample{TimestampMs: ts.UnixMilli(), Value: 4})
+	}
+	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		if r.GetQuery() == aggs[0].CoverageQuery() {
+			return &LokiPromResponse{Response: &queryrangebase.PrometheusResponse{
+				Status: loghttp.QueryStatusSuccess,
+				Data: queryrangebase.PrometheusData{
+					ResultType: loghttp.ResultTypeMatrix,
+					Result:     []queryrangebase.SampleStream{{Samples: coverage}},
+				},
+			}}, nil
+		}
+		query = r.GetQuery()
+		return nil, nil
+	})
+	h := NewContinuousAggregationsMiddleware(fakeLimits{continuousAggregations: aggs}, 15*time.Second).Wrap(next)
+	ctx := user.InjectOrgID(context.Background(), "1")
+
+	_, err := h.Do(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, rewritten, query)
+
+	// Queries are not rewritten when an interval is missing in the range of a step.
+	coverage[10].Value = 3
+	_, err = h.Do(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, req.Query, query)
+
+	// Or when the range of a step has no interval at all.
+	coverage = append(coverage[:10], coverage[11:]...)
+	_, err = h.Do(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, req.Query, query)
+
+	// Or when the ingesters don't write aggregations.
+	coverage = coverage[:0]
+	for ts := req.StartTs; !ts.After(req.EndTs); ts = ts.Add(time.Minute) {
+		coverage = append(coverage, logproto.LegacySample{TimestampMs: ts.UnixMilli(), Value: 4})
+	}
+	_, err = NewContinuousAggregationsMiddleware(fakeLimits{continuousAggregations: aggs}, 0).Wrap(next).Do(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, req.Query, query)
+	_, err = h.Do(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, rewritten, query)
+
+	// Queries before the aggregation is materialised are not rewritten.
+	require.NoError(t, aggs[0].From.Set("1970-01-01T01:30:00Z"))
+	_, err = h.Do(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, req.Query, query)
+}
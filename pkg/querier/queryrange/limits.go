@@ -21,6 +21,7 @@ import (
 	"github.com/weaveworks/common/httpgrpc"
 	"github.com/weaveworks/common/user"
 
+	"github.com/grafana/loki/pkg/ingester/aggregations"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql"
 	"github.com/grafana/loki/pkg/logql/syntax"
@@ -63,6 +64,11 @@ type Limits interface {
 	RequiredNumberLabels(context.Context, string) int
 	MaxQueryBytesRead(context.Context, string) int
 	MaxQuerierBytesRead(context.Context, string) int
+	// AllowPartialQueryResults returns whether log and metric queries may return the results
+	// of their successful splits and shards when some of them fail.
+	AllowPartialQueryResults(context.Context, string) bool
+	// ContinuousAggregations returns the continuous aggregations that metric queries are answered from.
+	ContinuousAggregations(string) []*aggregations.Aggregation
 }
 
 type limits struct {
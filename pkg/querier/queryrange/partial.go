@@ -0,0 +1,61 @@
+package queryrange
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/loki/pkg/logql"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
+	"github.com/grafana/loki/pkg/util"
+)
+
+// partialResultHeader is added to responses that miss some of their splits or shards,
+// so that they are not stored in the results cache.
+var partialResultHeader = queryrangebase.PrometheusResponseHeader{
+	Name:   "Cache-Control",
+	Values: []string{"no-store"},
+}
+
+// partialResultsAllowed returns whether all tenants of a query allow partial results.
+func partialResultsAllowed(ctx context.Context, tenantIDs []string, limits Limits) bool {
+	if len(tenantIDs) == 0 {
+		return false
+	}
+	for _, id := range tenantIDs {
+		if !limits.AllowPartialQueryResults(ctx, id) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPartialResultError returns whether a split that failed with err may be left out of a partial result.
+func isPartialResultError(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && logql.IsPartialResultError(err)
+}
+
+// missingSplitWarning describes a split that is missing from a partial result.
+func missingSplitWarning(req queryrangebase.Request, err error) definitions.QueryWarning {
+	start, end := util.TimeFromMillis(req.GetStart()), util.TimeFromMillis(req.GetEnd())
+	return definitions.QueryWarning{
+		Message: fmt.Sprintf("results between %s and %s are missing: %s", start, end, err),
+		Start:   start,
+		End:     end,
+	}
+}
+
+// withWarnings adds warnings about missing splits or shards to a log or metric query response
+// and marks it as not cacheable.
+func withWarnings(resp queryrangebase.Response, warnings []definitions.QueryWarning) queryrangebase.Response {
+	switch r := resp.(type) {
+	case *LokiResponse:
+		r.Warnings = append(r.Warnings, warnings...)
+		r.Headers = append(r.Headers, partialResultHeader)
+	case *LokiPromResponse:
+		r.Warnings = append(r.Warnings, warnings...)
+		header := partialResultHeader
+		r.Response.Headers = append(r.Response.Headers, &header)
+	}
+	return resp
+}
@@ -15,7 +15,7 @@ import (
 	_ "github.com/grafana/loki/pkg/push"
 	github_com_grafana_loki_pkg_push "github.com/grafana/loki/pkg/push"
 	queryrangebase "github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
-	_ "github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
+	definitions "github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
 	github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions "github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
 	io "io"
 	math "math"
@@ -237,6 +237,7 @@ type LokiResponse struct {
 	Version    uint32                                                                                               `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
 	Statistics stats.Result                                                                                         `protobuf:"bytes,8,opt,name=statistics,proto3" json:"statistics"`
 	Headers    []github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,9,rep,name=Headers,proto3,customtype=github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
+	Warnings   []definitions.QueryWarning                                                                           `protobuf:"bytes,10,rep,name=Warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *LokiResponse) Reset()      { *m = LokiResponse{} }
@@ -327,6 +328,13 @@ func (m *LokiResponse) GetStatistics() stats.Result {
 	return stats.Result{}
 }
 
+func (m *LokiResponse) GetWarnings() []definitions.QueryWarning {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type LokiSeriesRequest struct {
 	Match   []string  `protobuf:"bytes,1,rep,name=match,proto3" json:"match,omitempty"`
 	StartTs time.Time `protobuf:"bytes,2,opt,name=startTs,proto3,stdtime" json:"startTs"`
@@ -653,6 +661,7 @@ func (m *LokiData) GetResultType() string {
 type LokiPromResponse struct {
 	Response   *queryrangebase.PrometheusResponse `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"`
 	Statistics stats.Result                       `protobuf:"bytes,2,opt,name=statistics,proto3" json:"statistics"`
+	Warnings   []definitions.QueryWarning         `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`
 }
 
 func (m *LokiPromResponse) Reset()      { *m = LokiPromResponse{} }
@@ -701,6 +710,13 @@ func (m *LokiPromResponse) GetStatistics() stats.Result {
 	return stats.Result{}
 }
 
+func (m *LokiPromResponse) GetWarnings() []definitions.QueryWarning {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
 type IndexStatsResponse struct {
 	Response *github_com_grafana_loki_pkg_logproto.IndexStatsResponse                                             `protobuf:"bytes,1,opt,name=response,proto3,customtype=github.com/grafana/loki/pkg/logproto.IndexStatsResponse" json:"response,omitempty"`
 	Headers  []github_com_grafana_loki_pkg_querier_queryrange_queryrangebase_definitions.PrometheusResponseHeader `protobuf:"bytes,2,rep,name=Headers,proto3,customtype=github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions.PrometheusResponseHeader" json:"-"`
@@ -756,70 +772,73 @@ func init() {
 }
 
 var fileDescriptor_51b9d53b40d11902 = []byte{
-	// 999 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x56, 0xdd, 0x6e, 0x23, 0xb5,
-	0x17, 0x8f, 0xf3, 0x1d, 0xf7, 0xbf, 0xfd, 0x83, 0x5b, 0x76, 0x47, 0x05, 0xcd, 0x44, 0x91, 0x60,
-	0x83, 0x04, 0x13, 0xd1, 0xe5, 0x43, 0x7c, 0x08, 0xb1, 0x43, 0x41, 0x54, 0x5a, 0x21, 0x34, 0x9b,
-	0x17, 0x70, 0x3a, 0x6e, 0x32, 0x74, 0xbe, 0x6a, 0x7b, 0x56, 0xf4, 0x8e, 0x07, 0x00, 0x69, 0x9f,
-	0x02, 0x21, 0xc1, 0x13, 0x20, 0x71, 0xdf, 0xcb, 0x5e, 0xae, 0x2a, 0x31, 0xd0, 0xf4, 0x06, 0x72,
-	0xd5, 0x47, 0x40, 0xb6, 0x67, 0x26, 0x4e, 0xb7, 0xed, 0x36, 0xdd, 0x9b, 0xbd, 0xe0, 0x26, 0x73,
-	0xce, 0xf1, 0xf9, 0xd9, 0x3e, 0x3f, 0xff, 0x8e, 0x1d, 0x78, 0x37, 0xd9, 0x1b, 0x0f, 0xf6, 0x53,
-	0x42, 0x7d, 0x42, 0xe5, 0xf7, 0x80, 0xe2, 0x68, 0x4c, 0x34, 0xd3, 0x4e, 0x68, 0xcc, 0x63, 0x04,
-	0xe7, 0x91, 0x8d, 0xf5, 0x71, 0x3c, 0x8e, 0x65, 0x78, 0x20, 0x2c, 0x95, 0xb1, 0x61, 0x8d, 0xe3,
-	0x78, 0x1c, 0x90, 0x81, 0xf4, 0x46, 0xe9, 0xee, 0x80, 0xfb, 0x21, 0x61, 0x1c, 0x87, 0x49, 0x9e,
-	0xf0, 0xaa, 0x58, 0x2b, 0x88, 0xc7, 0x0a, 0x59, 0x18, 0xf9, 0x60, 0x37, 0x1f, 0xdc, 0x0f, 0xc2,
-	0xd8, 0x23, 0xc1, 0x80, 0x71, 0xcc, 0x99, 0xfa, 0xcd, 0x33, 0xd6, 0x44, 0x46, 0x92, 0xb2, 0x89,
-	0xfc, 0xc9, 0x83, 0x9f, 0x3f, 0x73, 0xff, 0x23, 0xcc, 0xc8, 0xc0, 0x23, 0xbb, 0x7e, 0xe4, 0x73,
-	0x3f, 0x8e, 0x98, 0x6e, 0xe7, 0x93, 0xbc, 0x7f, 0xbd, 0x49, 0xce, 0x73, 0xd2, 0x3b, 0xaa, 0xc2,
-	0x95, 0x07, 0xf1, 0x9e, 0xef, 0x92, 0xfd, 0x94, 0x30, 0x8e, 0xd6, 0x61, 0x43, 0xe6, 0x18, 0xa0,
-	0x0b, 0xfa, 0x1d, 0x57, 0x39, 0x22, 0x1a, 0xf8, 0xa1, 0xcf, 0x8d, 0x6a, 0x17, 0xf4, 0x6f, 0xb9,
-	0xca, 0x41, 0x08, 0xd6, 0x19, 0x27, 0x89, 0x51, 0xeb, 0x82, 0x7e, 0xcd, 0x95, 0x36, 0xda, 0x80,
-	0x6d, 0x3f, 0xe2, 0x84, 0x3e, 0xc2, 0x81, 0xd1, 0x91, 0xf1, 0xd2, 0x47, 0x9f, 0xc2, 0x16, 0xe3,
-	0x98, 0xf2, 0x21, 0x33, 0xea, 0x5d, 0xd0, 0x5f, 0xd9, 0xdc, 0xb0, 0x15, 0xdf, 0x76, 0xc1, 0xb7,
-	0x3d, 0x2c, 0xf8, 0x76, 0xda, 0x87, 0x99, 0x55, 0x79, 0xfc, 0xa7, 0x05, 0xdc, 0x02, 0x84, 0x3e,
-	0x82, 0x0d, 0x12, 0x79, 0x43, 0x66, 0x34, 0x96, 0x40, 0x2b, 0x08, 0x7a, 0x07, 0x76, 0x3c, 0x9f,
-	0x92, 0x1d, 0xc1, 0x99, 0xd1, 0xec, 0x82, 0xfe, 0xea, 0xe6, 0x9a, 0x5d, 0x9e, 0xdf, 0x56, 0x31,
-	0xe4, 0xce, 0xb3, 0x44, 0x79, 0x09, 0xe6, 0x13, 0xa3, 0x25, 0x99, 0x90, 0x36, 0xea, 0xc1, 0x26,
-	0x9b, 0x60, 0xea, 0x31, 0xa3, 0xdd, 0xad, 0xf5, 0x3b, 0x0e, 0x9c, 0x65, 0x56, 0x1e, 0x71, 0xf3,
-	0x6f, 0xef, 0x1f, 0x00, 0x91, 0xa0, 0x74, 0x3b, 0x62, 0x1c, 0x47, 0xfc, 0x26, 0xcc, 0x7e, 0x02,
-	0x9b, 0x42, 0x79, 0x43, 0x26, 0xb9, 0xbd, 0x6e, 0xa9, 0x39, 0x66, 0xb1, 0xd6, 0xfa, 0x52, 0xb5,
-	0x36, 0x2e, 0xac, 0xb5, 0x79, 0x69, 0xad, 0xbf, 0xd4, 0xe1, 0xff, 0x94, 0x7c, 0x58, 0x12, 0x47,
-	0x8c, 0x08, 0xd0, 0x43, 0x8e, 0x79, 0xca, 0x54, 0x99, 0x39, 0x48, 0x46, 0xdc, 0x7c, 0x04, 0x7d,
-	0x06, 0xeb, 0x5b, 0x98, 0x63, 0x59, 0xf2, 0xca, 0xe6, 0xba, 0xad, 0x89, 0x52, 0xcc, 0x25, 0xc6,
-	0x9c, 0xdb, 0xa2, 0xaa, 0x59, 0x66, 0xad, 0x7a, 0x98, 0xe3, 0xb7, 0xe2, 0xd0, 0xe7, 0x24, 0x4c,
-	0xf8, 0x81, 0x2b, 0x91, 0xe8, 0x3d, 0xd8, 0xf9, 0x82, 0xd2, 0x98, 0x0e, 0x0f, 0x12, 0x22, 0x29,
-	0xea, 0x38, 0x77, 0x66, 0x99, 0xb5, 0x46, 0x8a, 0xa0, 0x86, 0x98, 0x67, 0xa2, 0x37, 0x61, 0x43,
-	0x3a, 0x92, 0x94, 0x8e, 0xb3, 0x36, 0xcb, 0xac, 0xff, 0x4b, 0x88, 0x96, 0xae, 0x32, 0x16, 0x39,
-	0x6c, 0x5c, 0x8b, 0xc3, 0xf2, 0x28, 0x9b, 0xfa, 0x51, 0x1a, 0xb0, 0xf5, 0x88, 0x50, 0x26, 0xa6,
-	0x69, 0xc9, 0x78, 0xe1, 0xa2, 0xfb, 0x10, 0x0a, 0x62, 0x7c, 0xc6, 0xfd, 0x1d, 0xa1, 0x27, 0x41,
-	0xc6, 0x2d, 0x5b, 0x5d, 0x17, 0x2e, 0x61, 0x69, 0xc0, 0x1d, 0x94, 0xb3, 0xa0, 0x25, 0xba, 0x9a,
-	0x8d, 0x7e, 0x05, 0xb0, 0xf5, 0x15, 0xc1, 0x1e, 0xa1, 0xcc, 0xe8, 0x74, 0x6b, 0xfd, 0x95, 0xcd,
-	0xd7, 0x6d, 0xfd, 0x6e, 0xf8, 0x86, 0xc6, 0x21, 0xe1, 0x13, 0x92, 0xb2, 0xe2, 0x80, 0x54, 0xb6,
-	0xb3, 0x77, 0x9c, 0x59, 0xa3, 0xb1, 0xcf, 0x27, 0xe9, 0xc8, 0xde, 0x89, 0xc3, 0xc1, 0x98, 0xe2,
-	0x5d, 0x1c, 0xe1, 0x41, 0x10, 0xef, 0xf9, 0x83, 0xa5, 0xef, 0xa3, 0x4b, 0xd7, 0x99, 0x65, 0x16,
-	0x78, 0xdb, 0x2d, 0xb6, 0xd8, 0xfb, 0x03, 0xc0, 0x97, 0xc5, 0x09, 0x3f, 0x14, 0x73, 0x33, 0xad,
-	0x31, 0x42, 0xcc, 0x77, 0x26, 0x06, 0x10, 0x32, 0x73, 0x95, 0xa3, 0x5f, 0x16, 0xd5, 0xe7, 0xba,
-	0x2c, 0x6a, 0xcb, 0x5f, 0x16, 0x45, 0x37, 0xd4, 0x2f, 0xec, 0x86, 0xc6, 0xa5, 0xdd, 0xf0, 0x43,
-	0x4d, 0x75, 0x7e, 0x51, 0xdf, 0x12, 0x3d, 0xf1, 0x65, 0xd9, 0x13, 0x35, 0xb9, 0xdb, 0x52, 0x6a,
-	0x6a, 0xae, 0x6d, 0x8f, 0x44, 0xdc, 0xdf, 0xf5, 0x09, 0x7d, 0x46, 0x67, 0x68, 0x72, 0xab, 0x2d,
-	0xca, 0x4d, 0xd7, 0x4a, 0xfd, 0x85, 0xd7, 0xca, 0xb9, 0xee, 0x68, 0xdc, 0xa0, 0x3b, 0x7a, 0xbf,
-	0x03, 0xf8, 0x8a, 0x38, 0x8e, 0x07, 0x78, 0x44, 0x82, 0xaf, 0x71, 0x38, 0x97, 0x9c, 0x26, 0x2e,
-	0xf0, 0x5c, 0xe2, 0xaa, 0xde, 0x5c, 0x5c, 0x35, 0x4d, 0x5c, 0xe5, 0xdb, 0x50, 0xd7, 0xde, 0x86,
-	0xde, 0x59, 0x15, 0xde, 0x3e, 0xbf, 0xff, 0x25, 0x24, 0xf5, 0x86, 0x26, 0xa9, 0x8e, 0x83, 0xfe,
-	0x93, 0xcc, 0x35, 0x24, 0xf3, 0x13, 0x80, 0xed, 0xe2, 0x0d, 0x42, 0x36, 0x84, 0x0a, 0x26, 0x9f,
-	0x19, 0x45, 0xf4, 0xaa, 0x00, 0xd3, 0x32, 0xea, 0x6a, 0x19, 0xe8, 0x5b, 0xd8, 0x54, 0x5e, 0xde,
-	0xc5, 0x77, 0xb4, 0x2e, 0xe6, 0x94, 0xe0, 0xf0, 0xbe, 0x87, 0x13, 0x4e, 0xa8, 0xf3, 0xa1, 0xd8,
-	0xc5, 0x71, 0x66, 0xdd, 0xbd, 0x8a, 0x22, 0xf9, 0x0f, 0x51, 0xe1, 0xc4, 0xe1, 0xaa, 0x35, 0xdd,
-	0x7c, 0x85, 0xde, 0x8f, 0x00, 0xbe, 0x24, 0x36, 0x2a, 0xa8, 0x29, 0x55, 0xb1, 0x05, 0xdb, 0x34,
-	0xb7, 0x73, 0x5d, 0xf7, 0xec, 0x45, 0x5a, 0x2f, 0xa0, 0xd2, 0xa9, 0x1f, 0x66, 0x16, 0x70, 0x4b,
-	0x24, 0xba, 0xb7, 0x40, 0x63, 0xf5, 0x22, 0x1a, 0x05, 0xa4, 0xb2, 0x40, 0xdc, 0x6f, 0x55, 0x88,
-	0xb6, 0x23, 0x8f, 0x7c, 0x27, 0xc4, 0x37, 0xd7, 0x69, 0xfa, 0xd4, 0x8e, 0x5e, 0x9b, 0x93, 0xf2,
-	0x74, 0xbe, 0xf3, 0xf1, 0x71, 0x66, 0x7d, 0x70, 0x15, 0x2b, 0x57, 0x80, 0xb5, 0x12, 0x74, 0xe1,
-	0x56, 0x5f, 0x78, 0xe1, 0x3a, 0xef, 0x1e, 0x9d, 0x98, 0x95, 0x27, 0x27, 0x66, 0xe5, 0xec, 0xc4,
-	0x04, 0xdf, 0x4f, 0x4d, 0xf0, 0xf3, 0xd4, 0x04, 0x87, 0x53, 0x13, 0x1c, 0x4d, 0x4d, 0xf0, 0xd7,
-	0xd4, 0x04, 0x7f, 0x4f, 0xcd, 0xca, 0xd9, 0xd4, 0x04, 0x8f, 0x4f, 0xcd, 0xca, 0xd1, 0xa9, 0x59,
-	0x79, 0x72, 0x6a, 0x56, 0x46, 0x4d, 0x49, 0xc4, 0xbd, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x02,
-	0x7e, 0x3a, 0x1c, 0x00, 0x0d, 0x00, 0x00,
+	// 1041 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x56, 0x4f, 0x6f, 0x23, 0x35,
+	0x14, 0x8f, 0x33, 0x49, 0x9a, 0xb8, 0x6c, 0x01, 0xb7, 0xec, 0x0e, 0x05, 0xcd, 0x44, 0x91, 0x60,
+	0x83, 0x04, 0x13, 0xd1, 0xe5, 0x8f, 0xf8, 0x23, 0xc4, 0x0e, 0x05, 0x51, 0x69, 0x85, 0xe8, 0x6c,
+	0x25, 0xce, 0x4e, 0xc7, 0x9d, 0x0c, 0xcd, 0xfc, 0xa9, 0xed, 0x2c, 0xf4, 0xc6, 0x07, 0xe0, 0xb0,
+	0x9f, 0x02, 0x71, 0xe0, 0x13, 0x20, 0x71, 0xef, 0xb1, 0xc7, 0x55, 0x25, 0x86, 0x6d, 0x7a, 0x81,
+	0x9c, 0xfa, 0x09, 0x10, 0xb2, 0xc7, 0x33, 0x71, 0x76, 0xdb, 0x6e, 0xd3, 0x5e, 0xf6, 0xc0, 0x25,
+	0xe3, 0xf7, 0xfc, 0x7e, 0xf6, 0x7b, 0x3f, 0xff, 0x9e, 0x1d, 0x78, 0x3b, 0xdd, 0x0d, 0x7a, 0x7b,
+	0x23, 0x42, 0x43, 0x42, 0xe5, 0x77, 0x9f, 0xe2, 0x38, 0x20, 0xda, 0xd0, 0x49, 0x69, 0xc2, 0x13,
+	0x04, 0xa7, 0x9e, 0xd5, 0x95, 0x20, 0x09, 0x12, 0xe9, 0xee, 0x89, 0x51, 0x1e, 0xb1, 0x6a, 0x07,
+	0x49, 0x12, 0x0c, 0x49, 0x4f, 0x5a, 0xfd, 0xd1, 0x4e, 0x8f, 0x87, 0x11, 0x61, 0x1c, 0x47, 0xa9,
+	0x0a, 0x78, 0x4d, 0xec, 0x35, 0x4c, 0x82, 0x1c, 0x59, 0x0c, 0xd4, 0x64, 0x5b, 0x4d, 0xee, 0x0d,
+	0xa3, 0xc4, 0x27, 0xc3, 0x1e, 0xe3, 0x98, 0xb3, 0xfc, 0x57, 0x45, 0x2c, 0x8b, 0x88, 0x74, 0xc4,
+	0x06, 0xf2, 0x47, 0x39, 0xbf, 0x78, 0x66, 0xfe, 0x7d, 0xcc, 0x48, 0xcf, 0x27, 0x3b, 0x61, 0x1c,
+	0xf2, 0x30, 0x89, 0x99, 0x3e, 0x56, 0x8b, 0x7c, 0x70, 0xb9, 0x45, 0x9e, 0xe4, 0xa4, 0x73, 0x58,
+	0x85, 0x8b, 0xf7, 0x92, 0xdd, 0xd0, 0x23, 0x7b, 0x23, 0xc2, 0x38, 0x5a, 0x81, 0x75, 0x19, 0x63,
+	0x82, 0x36, 0xe8, 0xb6, 0xbc, 0xdc, 0x10, 0xde, 0x61, 0x18, 0x85, 0xdc, 0xac, 0xb6, 0x41, 0xf7,
+	0x86, 0x97, 0x1b, 0x08, 0xc1, 0x1a, 0xe3, 0x24, 0x35, 0x8d, 0x36, 0xe8, 0x1a, 0x9e, 0x1c, 0xa3,
+	0x55, 0xd8, 0x0c, 0x63, 0x4e, 0xe8, 0x03, 0x3c, 0x34, 0x5b, 0xd2, 0x5f, 0xda, 0xe8, 0x33, 0xb8,
+	0xc0, 0x38, 0xa6, 0x7c, 0x8b, 0x99, 0xb5, 0x36, 0xe8, 0x2e, 0xae, 0xad, 0x3a, 0x39, 0xdf, 0x4e,
+	0xc1, 0xb7, 0xb3, 0x55, 0xf0, 0xed, 0x36, 0x0f, 0x32, 0xbb, 0xf2, 0xf0, 0x2f, 0x1b, 0x78, 0x05,
+	0x08, 0x7d, 0x0c, 0xeb, 0x24, 0xf6, 0xb7, 0x98, 0x59, 0x9f, 0x03, 0x9d, 0x43, 0xd0, 0xbb, 0xb0,
+	0xe5, 0x87, 0x94, 0x6c, 0x0b, 0xce, 0xcc, 0x46, 0x1b, 0x74, 0x97, 0xd6, 0x96, 0x9d, 0xf2, 0xfc,
+	0xd6, 0x8b, 0x29, 0x6f, 0x1a, 0x25, 0xca, 0x4b, 0x31, 0x1f, 0x98, 0x0b, 0x92, 0x09, 0x39, 0x46,
+	0x1d, 0xd8, 0x60, 0x03, 0x4c, 0x7d, 0x66, 0x36, 0xdb, 0x46, 0xb7, 0xe5, 0xc2, 0x49, 0x66, 0x2b,
+	0x8f, 0xa7, 0xbe, 0x9d, 0x7f, 0x00, 0x44, 0x82, 0xd2, 0x8d, 0x98, 0x71, 0x1c, 0xf3, 0xab, 0x30,
+	0xfb, 0x29, 0x6c, 0x08, 0xe5, 0x6d, 0x31, 0xd3, 0x98, 0xa3, 0x54, 0x85, 0x99, 0xad, 0xb5, 0x36,
+	0x57, 0xad, 0xf5, 0x33, 0x6b, 0x6d, 0x9c, 0x5b, 0xeb, 0xbf, 0x35, 0xf8, 0x42, 0x2e, 0x1f, 0x96,
+	0x26, 0x31, 0x23, 0x02, 0x74, 0x9f, 0x63, 0x3e, 0x62, 0x79, 0x99, 0x0a, 0x24, 0x3d, 0x9e, 0x9a,
+	0x41, 0x9f, 0xc3, 0xda, 0x3a, 0xe6, 0x58, 0x96, 0xbc, 0xb8, 0xb6, 0xe2, 0x68, 0xa2, 0x14, 0x6b,
+	0x89, 0x39, 0xf7, 0xa6, 0xa8, 0x6a, 0x92, 0xd9, 0x4b, 0x3e, 0xe6, 0xf8, 0xed, 0x24, 0x0a, 0x39,
+	0x89, 0x52, 0xbe, 0xef, 0x49, 0x24, 0x7a, 0x1f, 0xb6, 0xbe, 0xa4, 0x34, 0xa1, 0x5b, 0xfb, 0x29,
+	0x91, 0x14, 0xb5, 0xdc, 0x5b, 0x93, 0xcc, 0x5e, 0x26, 0x85, 0x53, 0x43, 0x4c, 0x23, 0xd1, 0x5b,
+	0xb0, 0x2e, 0x0d, 0x49, 0x4a, 0xcb, 0x5d, 0x9e, 0x64, 0xf6, 0x8b, 0x12, 0xa2, 0x85, 0xe7, 0x11,
+	0xb3, 0x1c, 0xd6, 0x2f, 0xc5, 0x61, 0x79, 0x94, 0x0d, 0xfd, 0x28, 0x4d, 0xb8, 0xf0, 0x80, 0x50,
+	0x26, 0x96, 0x59, 0x90, 0xfe, 0xc2, 0x44, 0x77, 0x21, 0x14, 0xc4, 0x84, 0x8c, 0x87, 0xdb, 0x42,
+	0x4f, 0x82, 0x8c, 0x1b, 0x4e, 0x7e, 0x5d, 0x78, 0x84, 0x8d, 0x86, 0xdc, 0x45, 0x8a, 0x05, 0x2d,
+	0xd0, 0xd3, 0xc6, 0xe8, 0x37, 0x00, 0x17, 0xbe, 0x26, 0xd8, 0x27, 0x94, 0x99, 0xad, 0xb6, 0xd1,
+	0x5d, 0x5c, 0x7b, 0xc3, 0xd1, 0xef, 0x86, 0x6f, 0x69, 0x12, 0x11, 0x3e, 0x20, 0x23, 0x56, 0x1c,
+	0x50, 0x1e, 0xed, 0xee, 0x1e, 0x65, 0x76, 0x3f, 0x08, 0xf9, 0x60, 0xd4, 0x77, 0xb6, 0x93, 0xa8,
+	0x17, 0x50, 0xbc, 0x83, 0x63, 0xdc, 0x1b, 0x26, 0xbb, 0x61, 0x6f, 0xee, 0xfb, 0xe8, 0xdc, 0x7d,
+	0x26, 0x99, 0x0d, 0xde, 0xf1, 0x8a, 0x14, 0xd1, 0x26, 0x6c, 0x7e, 0x87, 0x69, 0x1c, 0xc6, 0x01,
+	0x33, 0xa1, 0x4c, 0xf7, 0xd5, 0x99, 0x74, 0x37, 0xc5, 0x0e, 0x2a, 0xc2, 0x5d, 0x55, 0xb5, 0xa3,
+	0x1f, 0x14, 0x44, 0x3b, 0xa4, 0x72, 0x99, 0xce, 0x9f, 0x00, 0xbe, 0x2c, 0x44, 0x73, 0x5f, 0xa4,
+	0xcb, 0xb4, 0x5e, 0x8b, 0x30, 0xdf, 0x1e, 0x98, 0x40, 0x28, 0xd7, 0xcb, 0x0d, 0xfd, 0xfe, 0xa9,
+	0x5e, 0xeb, 0xfe, 0x31, 0xe6, 0xbf, 0x7f, 0x8a, 0x06, 0xab, 0x9d, 0xd9, 0x60, 0xf5, 0x73, 0x1b,
+	0xec, 0x67, 0x03, 0x22, 0xbd, 0xbe, 0x39, 0xda, 0xec, 0xab, 0xb2, 0xcd, 0x0c, 0x99, 0x6d, 0xa9,
+	0xde, 0x7c, 0xad, 0x0d, 0x9f, 0xc4, 0x3c, 0xdc, 0x09, 0x09, 0x7d, 0x46, 0xb3, 0x69, 0x0a, 0x36,
+	0x66, 0x15, 0xac, 0xcb, 0xaf, 0xf6, 0xfc, 0xcb, 0x6f, 0xb6, 0xe1, 0xea, 0x57, 0x68, 0xb8, 0xce,
+	0x1f, 0x00, 0xbe, 0x22, 0x8e, 0xe3, 0x1e, 0xee, 0x93, 0xe1, 0x37, 0x38, 0x9a, 0x4a, 0x4e, 0x13,
+	0x17, 0xb8, 0x96, 0xb8, 0xaa, 0x57, 0x17, 0x97, 0xa1, 0x89, 0xab, 0x7c, 0x6e, 0x6a, 0xda, 0x73,
+	0xd3, 0x39, 0xad, 0xc2, 0x9b, 0x4f, 0xe6, 0x3f, 0x87, 0xa4, 0xde, 0xd4, 0x24, 0xd5, 0x72, 0xd1,
+	0xff, 0x92, 0xb9, 0x84, 0x64, 0x7e, 0x01, 0xb0, 0x59, 0x3c, 0x6b, 0xc8, 0x81, 0x30, 0x87, 0xc9,
+	0x97, 0x2b, 0x27, 0x7a, 0x49, 0x80, 0x69, 0xe9, 0xf5, 0xb4, 0x08, 0xf4, 0x3d, 0x6c, 0xe4, 0x96,
+	0xea, 0xe2, 0x5b, 0x5a, 0x17, 0x73, 0x4a, 0x70, 0x74, 0xd7, 0xc7, 0x29, 0x27, 0xd4, 0xfd, 0x48,
+	0x64, 0x71, 0x94, 0xd9, 0xb7, 0x2f, 0xa2, 0x48, 0xfe, 0xe9, 0xcc, 0x71, 0xe2, 0x70, 0xf3, 0x3d,
+	0x3d, 0xb5, 0x43, 0xe7, 0x31, 0x80, 0x2f, 0x89, 0x44, 0x05, 0x35, 0xa5, 0x2a, 0xd6, 0x61, 0x93,
+	0xaa, 0xb1, 0xd2, 0x75, 0xc7, 0x99, 0xa5, 0xf5, 0x0c, 0x2a, 0xdd, 0xda, 0x41, 0x66, 0x03, 0xaf,
+	0x44, 0xa2, 0x3b, 0x33, 0x34, 0x56, 0xcf, 0xa2, 0x51, 0x40, 0x2a, 0x33, 0x8f, 0xdb, 0x26, 0x6c,
+	0x16, 0x57, 0xbf, 0x69, 0x5c, 0xeb, 0xb5, 0x28, 0x7c, 0x9d, 0xdf, 0xab, 0x10, 0x6d, 0xc4, 0x3e,
+	0xf9, 0x51, 0xe8, 0x79, 0x2a, 0xfd, 0xd1, 0x53, 0x45, 0xbe, 0x3e, 0xe5, 0xf9, 0xe9, 0x78, 0xf7,
+	0x93, 0xa3, 0xcc, 0xfe, 0xf0, 0x22, 0xa2, 0x2f, 0x00, 0x6b, 0xac, 0xe8, 0xbd, 0x50, 0x7d, 0xee,
+	0x7b, 0xc1, 0x7d, 0xef, 0xf0, 0xd8, 0xaa, 0x3c, 0x3a, 0xb6, 0x2a, 0xa7, 0xc7, 0x16, 0xf8, 0x69,
+	0x6c, 0x81, 0x5f, 0xc7, 0x16, 0x38, 0x18, 0x5b, 0xe0, 0x70, 0x6c, 0x81, 0xc7, 0x63, 0x0b, 0xfc,
+	0x3d, 0xb6, 0x2a, 0xa7, 0x63, 0x0b, 0x3c, 0x3c, 0xb1, 0x2a, 0x87, 0x27, 0x56, 0xe5, 0xd1, 0x89,
+	0x55, 0xe9, 0x37, 0x24, 0x11, 0x77, 0xfe, 0x1b, 0x00, 0x94, 0xbd, 0x9c, 0xb8, 0xa6, 0x0d, 0x00,
+	0x00,
 }
 
 func (this *LokiRequest) Equal(that interface{}) bool {
@@ -970,6 +989,14 @@ func (this *LokiResponse) Equal(that interface{}) bool {
 			return false
 		}
 	}
+	if len(this.Warnings) != len(that1.Warnings) {
+		return false
+	}
+	for i := range this.Warnings {
+		if !this.Warnings[i].Equal(&that1.Warnings[i]) {
+			return false
+		}
+	}
 	return true
 }
 func (this *LokiSeriesRequest) Equal(that interface{}) bool {
@@ -1200,6 +1227,14 @@ func (this *LokiPromResponse) Equal(that interface{}) bool {
 	if !this.Statistics.Equal(&that1.Statistics) {
 		return false
 	}
+	if len(this.Warnings) != len(that1.Warnings) {
+		return false
+	}
+	for i := range this.Warnings {
+		if !this.Warnings[i].Equal(&that1.Warnings[i]) {
+			return false
+		}
+	}
 	return true
 }
 func (this *IndexStatsResponse) Equal(that interface{}) bool {
@@ -1275,7 +1310,7 @@ func (this *LokiResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 13)
+	s := make([]string, 0, 14)
 	s = append(s, "&queryrange.LokiResponse{")
 	s = append(s, "Status: "+fmt.Sprintf("%#v", this.Status)+",\n")
 	s = append(s, "Data: "+strings.Replace(this.Data.GoString(), `&`, ``, 1)+",\n")
@@ -1286,6 +1321,13 @@ func (this *LokiResponse) GoString() string {
 	s = append(s, "Version: "+fmt.Sprintf("%#v", this.Version)+",\n")
 	s = append(s, "Statistics: "+strings.Replace(this.Statistics.GoString(), `&`, ``, 1)+",\n")
 	s = append(s, "Headers: "+fmt.Sprintf("%#v", this.Headers)+",\n")
+	if this.Warnings != nil {
+		vs := make([]*definitions.QueryWarning, len(this.Warnings))
+		for i := range vs {
+			vs[i] = &this.Warnings[i]
+		}
+		s = append(s, "Warnings: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1365,12 +1407,19 @@ func (this *LokiPromResponse) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 6)
+	s := make([]string, 0, 7)
 	s = append(s, "&queryrange.LokiPromResponse{")
 	if this.Response != nil {
 		s = append(s, "Response: "+fmt.Sprintf("%#v", this.Response)+",\n")
 	}
 	s = append(s, "Statistics: "+strings.Replace(this.Statistics.GoString(), `&`, ``, 1)+",\n")
+	if this.Warnings != nil {
+		vs := make([]*definitions.QueryWarning, len(this.Warnings))
+		for i := range vs {
+			vs[i] = &this.Warnings[i]
+		}
+		s = append(s, "Warnings: "+fmt.Sprintf("%#v", vs)+",\n")
+	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1559,6 +1608,20 @@ func (m *LokiResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Warnings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x52
+		}
+	}
 	if len(m.Headers) > 0 {
 		for iNdEx := len(m.Headers) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -1954,6 +2017,20 @@ func (m *LokiPromResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Warnings) > 0 {
+		for iNdEx := len(m.Warnings) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Warnings[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintQueryrange(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	{
 		size, err := m.Statistics.MarshalToSizedBuffer(dAtA[:i])
 		if err != nil {
@@ -2146,6 +2223,12 @@ func (m *LokiResponse) Size() (n int) {
 			n += 1 + l + sovQueryrange(uint64(l))
 		}
 	}
+	if len(m.Warnings) > 0 {
+		for _, e := range m.Warnings {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2290,6 +2373,12 @@ func (m *LokiPromResponse) Size() (n int) {
 	}
 	l = m.Statistics.Size()
 	n += 1 + l + sovQueryrange(uint64(l))
+	if len(m.Warnings) > 0 {
+		for _, e := range m.Warnings {
+			l = e.Size()
+			n += 1 + l + sovQueryrange(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2355,6 +2444,11 @@ func (this *LokiResponse) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForWarnings := "[]QueryWarning{"
+	for _, f := range this.Warnings {
+		repeatedStringForWarnings += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForWarnings += "}"
 	s := strings.Join([]string{`&LokiResponse{`,
 		`Status:` + fmt.Sprintf("%v", this.Status) + `,`,
 		`Data:` + strings.Replace(strings.Replace(this.Data.String(), "LokiData", "LokiData", 1), `&`, ``, 1) + `,`,
@@ -2365,6 +2459,7 @@ func (this *LokiResponse) String() string {
 		`Version:` + fmt.Sprintf("%v", this.Version) + `,`,
 		`Statistics:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Statistics), "Result", "stats.Result", 1), `&`, ``, 1) + `,`,
 		`Headers:` + fmt.Sprintf("%v", this.Headers) + `,`,
+		`Warnings:` + repeatedStringForWarnings + `,`,
 		`}`,
 	}, "")
 	return s
@@ -2444,9 +2539,15 @@ func (this *LokiPromResponse) String() string {
 	if this == nil {
 		return "nil"
 	}
+	repeatedStringForWarnings := "[]QueryWarning{"
+	for _, f := range this.Warnings {
+		repeatedStringForWarnings += fmt.Sprintf("%v", f) + ","
+	}
+	repeatedStringForWarnings += "}"
 	s := strings.Join([]string{`&LokiPromResponse{`,
 		`Response:` + strings.Replace(fmt.Sprintf("%v", this.Response), "PrometheusResponse", "queryrangebase.PrometheusResponse", 1) + `,`,
 		`Statistics:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Statistics), "Result", "stats.Result", 1), `&`, ``, 1) + `,`,
+		`Warnings:` + repeatedStringForWarnings + `,`,
 		`}`,
 	}, "")
 	return s
@@ -3263,6 +3364,40 @@ func (m *LokiResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, definitions.QueryWarning{})
+			if err := m.Warnings[len(m.Warnings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
@@ -4310,6 +4445,40 @@ func (m *LokiPromResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Warnings", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowQueryrange
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthQueryrange
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Warnings = append(m.Warnings, definitions.QueryWarning{})
+			if err := m.Warnings[len(m.Warnings)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipQueryrange(dAtA[iNdEx:])
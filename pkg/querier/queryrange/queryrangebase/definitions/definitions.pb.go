@@ -7,17 +7,21 @@ import (
 	fmt "fmt"
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/gogo/protobuf/proto"
+	_ "github.com/gogo/protobuf/types"
+	github_com_gogo_protobuf_types "github.com/gogo/protobuf/types"
 	io "io"
 	math "math"
 	math_bits "math/bits"
 	reflect "reflect"
 	strings "strings"
+	time "time"
 )
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
+var _ = time.Kitchen
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the proto package it is being compiled against.
@@ -171,10 +175,79 @@ func (m *PrometheusResponseHeader) GetValues() []string {
 	return nil
 }
 
+// QueryWarning describes a part of a query that is missing from a partial result.
+type QueryWarning struct {
+	Message string    `protobuf:"bytes,1,opt,name=Message,proto3" json:"message"`
+	Start   time.Time `protobuf:"bytes,2,opt,name=Start,proto3,stdtime" json:"start"`
+	End     time.Time `protobuf:"bytes,3,opt,name=End,proto3,stdtime" json:"end"`
+	Shard   string    `protobuf:"bytes,4,opt,name=Shard,proto3" json:"shard,omitempty"`
+}
+
+func (m *QueryWarning) Reset()      { *m = QueryWarning{} }
+func (*QueryWarning) ProtoMessage() {}
+func (*QueryWarning) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d1a37772b6ae2c5c, []int{3}
+}
+func (m *QueryWarning) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *QueryWarning) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_QueryWarning.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *QueryWarning) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryWarning.Merge(m, src)
+}
+func (m *QueryWarning) XXX_Size() int {
+	return m.Size()
+}
+func (m *QueryWarning) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryWarning.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryWarning proto.InternalMessageInfo
+
+func (m *QueryWarning) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *QueryWarning) GetStart() time.Time {
+	if m != nil {
+		return m.Start
+	}
+	return time.Time{}
+}
+
+func (m *QueryWarning) GetEnd() time.Time {
+	if m != nil {
+		return m.End
+	}
+	return time.Time{}
+}
+
+func (m *QueryWarning) GetShard() string {
+	if m != nil {
+		return m.Shard
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*CachingOptions)(nil), "definitions.CachingOptions")
 	proto.RegisterType((*PrometheusRequestHeader)(nil), "definitions.PrometheusRequestHeader")
 	proto.RegisterType((*PrometheusResponseHeader)(nil), "definitions.PrometheusResponseHeader")
+	proto.RegisterType((*QueryWarning)(nil), "definitions.QueryWarning")
 }
 
 func init() {
@@ -182,26 +255,35 @@ func init() {
 }
 
 var fileDescriptor_d1a37772b6ae2c5c = []byte{
-	// 294 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x91, 0x31, 0x4e, 0x33, 0x31,
-	0x10, 0x85, 0xed, 0xff, 0x87, 0x28, 0x18, 0x89, 0x62, 0x85, 0x44, 0x88, 0xc4, 0x10, 0xa5, 0xa2,
-	0x80, 0xb8, 0xe0, 0x06, 0x49, 0x03, 0x0d, 0xa0, 0x80, 0x28, 0xe8, 0xbc, 0xd9, 0x89, 0x63, 0x25,
-	0xb1, 0x37, 0xf6, 0x6e, 0x41, 0x05, 0x47, 0xe0, 0x18, 0x1c, 0x85, 0x72, 0xcb, 0x54, 0x88, 0xf5,
-	0x36, 0x88, 0x2a, 0x47, 0x40, 0x18, 0x04, 0xdb, 0x22, 0xaa, 0x79, 0xf3, 0xcd, 0x9b, 0x57, 0xcc,
-	0xb0, 0x41, 0x3a, 0x95, 0x7c, 0x91, 0xa3, 0x55, 0x68, 0x43, 0xbd, 0xb5, 0x42, 0x4b, 0xac, 0xc9,
-	0x58, 0x38, 0xe4, 0x09, 0x8e, 0x95, 0x56, 0x99, 0x32, 0xda, 0xd5, 0x75, 0x2f, 0xb5, 0x26, 0x33,
-	0xd1, 0x66, 0x0d, 0xb5, 0xb7, 0xa5, 0x91, 0x26, 0x70, 0xfe, 0xa1, 0x3e, 0x2d, 0xdd, 0x43, 0xb6,
-	0x35, 0x10, 0xa3, 0x89, 0xd2, 0xf2, 0x3c, 0x0d, 0xbe, 0xa8, 0xcd, 0x9a, 0x89, 0x72, 0x22, 0x9e,
-	0x61, 0xd2, 0xa2, 0x1d, 0x7a, 0xd0, 0x1c, 0x7e, 0xf7, 0xdd, 0x4b, 0xb6, 0x73, 0x61, 0xcd, 0x1c,
-	0xb3, 0x09, 0xe6, 0x6e, 0x88, 0x8b, 0x1c, 0x5d, 0x76, 0x82, 0x22, 0x41, 0x1b, 0xed, 0xb2, 0xb5,
-	0x33, 0x31, 0xc7, 0xb0, 0xb2, 0xd1, 0x5f, 0x7f, 0x7b, 0xde, 0xa7, 0x47, 0xc3, 0x80, 0xa2, 0x3d,
-	0xd6, 0xb8, 0x16, 0xb3, 0x1c, 0x5d, 0xeb, 0x5f, 0xe7, 0xff, 0xcf, 0xf0, 0x0b, 0x76, 0xaf, 0x58,
-	0xab, 0x1e, 0xea, 0x52, 0xa3, 0x1d, 0xfe, 0x35, 0xb5, 0x7f, 0x57, 0x94, 0x40, 0x96, 0x25, 0x90,
-	0x55, 0x09, 0xf4, 0xde, 0x03, 0x7d, 0xf4, 0x40, 0x9f, 0x3c, 0xd0, 0xc2, 0x03, 0x7d, 0xf1, 0x40,
-	0x5f, 0x3d, 0x90, 0x95, 0x07, 0xfa, 0x50, 0x01, 0x29, 0x2a, 0x20, 0xcb, 0x0a, 0xc8, 0xcd, 0xa9,
-	0x54, 0xd9, 0x24, 0x8f, 0x7b, 0x23, 0x33, 0xe7, 0xd2, 0x8a, 0xb1, 0xd0, 0x82, 0xcf, 0xcc, 0x54,
-	0xf1, 0x5f, 0xbf, 0x23, 0x6e, 0x84, 0x03, 0x1f, 0xbf, 0x07, 0x00, 0x00, 0xff, 0xff, 0x09, 0x36,
-	0xa9, 0xa5, 0xca, 0x01, 0x00, 0x00,
+	// 439 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x92, 0x4f, 0x6f, 0xd3, 0x30,
+	0x18, 0xc6, 0xe3, 0x75, 0xdd, 0x86, 0x8b, 0x98, 0x08, 0x48, 0x84, 0x4a, 0x38, 0x55, 0x25, 0xa4,
+	0x21, 0x8d, 0x44, 0x82, 0x33, 0x97, 0x56, 0x48, 0x70, 0xe0, 0x5f, 0x3a, 0x81, 0xc4, 0xcd, 0x59,
+	0xde, 0xba, 0xd6, 0x6a, 0x3b, 0xb3, 0x9d, 0xc3, 0x4e, 0xf0, 0x11, 0xf6, 0x31, 0xf8, 0x28, 0x3b,
+	0xf6, 0xb8, 0x53, 0xa0, 0x29, 0x07, 0xd4, 0xd3, 0x3e, 0x02, 0x8a, 0xb3, 0x42, 0x6e, 0x08, 0xed,
+	0xe4, 0xf7, 0x7d, 0xde, 0xf7, 0xf9, 0xc9, 0x7e, 0x64, 0x3c, 0xce, 0x4f, 0x58, 0x7c, 0x5a, 0x80,
+	0xe6, 0xa0, 0xdd, 0x79, 0xa6, 0xa9, 0x64, 0xd0, 0x2a, 0x53, 0x6a, 0x20, 0xce, 0x60, 0xca, 0x25,
+	0xb7, 0x5c, 0x49, 0xd3, 0xae, 0xa3, 0x5c, 0x2b, 0xab, 0xfc, 0x5e, 0x4b, 0xea, 0xdf, 0x67, 0x8a,
+	0x29, 0xa7, 0xc7, 0x75, 0xd5, 0xac, 0xf4, 0x43, 0xa6, 0x14, 0x9b, 0x43, 0xec, 0xba, 0xb4, 0x98,
+	0xc6, 0x96, 0x0b, 0x30, 0x96, 0x8a, 0xbc, 0x59, 0x18, 0x1e, 0xe2, 0x3b, 0x63, 0x7a, 0x3c, 0xe3,
+	0x92, 0xbd, 0xcb, 0x1d, 0xc8, 0xef, 0xe3, 0xbd, 0x8c, 0x1b, 0x9a, 0xce, 0x21, 0x0b, 0xd0, 0x00,
+	0x1d, 0xec, 0x25, 0x7f, 0xfa, 0xe1, 0x04, 0x3f, 0x78, 0xaf, 0x95, 0x00, 0x3b, 0x83, 0xc2, 0x24,
+	0x70, 0x5a, 0x80, 0xb1, 0xaf, 0x80, 0x66, 0xa0, 0xfd, 0x87, 0x78, 0xfb, 0x2d, 0x15, 0xe0, 0x2c,
+	0xb7, 0x46, 0xdd, 0x75, 0x19, 0xa2, 0xa7, 0x89, 0x93, 0xfc, 0x47, 0x78, 0xe7, 0x23, 0x9d, 0x17,
+	0x60, 0x82, 0xad, 0x41, 0xe7, 0xef, 0xf0, 0x5a, 0x1c, 0x1e, 0xe1, 0xa0, 0x0d, 0x35, 0xb9, 0x92,
+	0x06, 0x6e, 0x4c, 0xfd, 0x89, 0xf0, 0xed, 0x0f, 0x75, 0x9a, 0x9f, 0xa8, 0x96, 0x5c, 0x32, 0xff,
+	0x31, 0xde, 0x7d, 0x03, 0xc6, 0x50, 0xb6, 0xa1, 0xf5, 0xd6, 0x65, 0xb8, 0x2b, 0x1a, 0x29, 0xd9,
+	0xcc, 0xfc, 0x31, 0xee, 0x4e, 0x2c, 0xd5, 0x36, 0xd8, 0x1a, 0xa0, 0x83, 0xde, 0xb3, 0x7e, 0xd4,
+	0x24, 0x18, 0x6d, 0x12, 0x8c, 0x8e, 0x36, 0x09, 0x8e, 0xee, 0x5e, 0x94, 0xa1, 0xb7, 0x2e, 0xc3,
+	0xae, 0xa9, 0x0d, 0xe7, 0xdf, 0x43, 0x94, 0x34, 0x5e, 0xff, 0x05, 0xee, 0xbc, 0x94, 0x59, 0xd0,
+	0xf9, 0x27, 0x62, 0xff, 0x1a, 0xd1, 0x01, 0x99, 0x39, 0x40, 0xed, 0xf3, 0x9f, 0xe0, 0xee, 0x64,
+	0x46, 0x75, 0x16, 0x6c, 0xbb, 0x8b, 0xde, 0x5b, 0x97, 0xe1, 0xbe, 0xa9, 0x85, 0x43, 0x25, 0xb8,
+	0x05, 0x91, 0xdb, 0xb3, 0xa4, 0xd9, 0x18, 0x7d, 0x59, 0x2c, 0x89, 0x77, 0xb9, 0x24, 0xde, 0xd5,
+	0x92, 0xa0, 0xaf, 0x15, 0x41, 0xdf, 0x2a, 0x82, 0x2e, 0x2a, 0x82, 0x16, 0x15, 0x41, 0x3f, 0x2a,
+	0x82, 0x7e, 0x55, 0xc4, 0xbb, 0xaa, 0x08, 0x3a, 0x5f, 0x11, 0x6f, 0xb1, 0x22, 0xde, 0xe5, 0x8a,
+	0x78, 0x9f, 0x5f, 0x33, 0x6e, 0x67, 0x45, 0x1a, 0x1d, 0x2b, 0x11, 0x33, 0x4d, 0xa7, 0x54, 0xd2,
+	0x78, 0xae, 0x4e, 0x78, 0xfc, 0xdf, 0xdf, 0x32, 0xdd, 0x71, 0xaf, 0x7a, 0xfe, 0x7b, 0x00, 0x2b,
+	0xf3, 0xf8, 0x3b, 0xd2, 0x02, 0x00, 0x00,
 }
 
 func (this *CachingOptions) Equal(that interface{}) bool {
@@ -292,6 +374,39 @@ func (this *PrometheusResponseHeader) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *QueryWarning) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*QueryWarning)
+	if !ok {
+		that2, ok := that.(QueryWarning)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Message != that1.Message {
+		return false
+	}
+	if !this.Start.Equal(that1.Start) {
+		return false
+	}
+	if !this.End.Equal(that1.End) {
+		return false
+	}
+	if this.Shard != that1.Shard {
+		return false
+	}
+	return true
+}
 func (this *CachingOptions) GoString() string {
 	if this == nil {
 		return "nil"
@@ -324,6 +439,19 @@ func (this *PrometheusResponseHeader) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
+func (this *QueryWarning) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&definitions.QueryWarning{")
+	s = append(s, "Message: "+fmt.Sprintf("%#v", this.Message)+",\n")
+	s = append(s, "Start: "+fmt.Sprintf("%#v", this.Start)+",\n")
+	s = append(s, "End: "+fmt.Sprintf("%#v", this.End)+",\n")
+	s = append(s, "Shard: "+fmt.Sprintf("%#v", this.Shard)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
 func valueToGoStringDefinitions(v interface{}, typ string) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -443,6 +571,59 @@ func (m *PrometheusResponseHeader) MarshalToSizedBuffer(dAtA []byte) (int, error
 	return len(dAtA) - i, nil
 }
 
+func (m *QueryWarning) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *QueryWarning) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *QueryWarning) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Shard) > 0 {
+		i -= len(m.Shard)
+		copy(dAtA[i:], m.Shard)
+		i = encodeVarintDefinitions(dAtA, i, uint64(len(m.Shard)))
+		i--
+		dAtA[i] = 0x22
+	}
+	n1, err1 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.End, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.End):])
+	if err1 != nil {
+		return 0, err1
+	}
+	i -= n1
+	i = encodeVarintDefinitions(dAtA, i, uint64(n1))
+	i--
+	dAtA[i] = 0x1a
+	n2, err2 := github_com_gogo_protobuf_types.StdTimeMarshalTo(m.Start, dAtA[i-github_com_gogo_protobuf_types.SizeOfStdTime(m.Start):])
+	if err2 != nil {
+		return 0, err2
+	}
+	i -= n2
+	i = encodeVarintDefinitions(dAtA, i, uint64(n2))
+	i--
+	dAtA[i] = 0x12
+	if len(m.Message) > 0 {
+		i -= len(m.Message)
+		copy(dAtA[i:], m.Message)
+		i = encodeVarintDefinitions(dAtA, i, uint64(len(m.Message)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintDefinitions(dAtA []byte, offset int, v uint64) int {
 	offset -= sovDefinitions(v)
 	base := offset
@@ -504,6 +685,27 @@ func (m *PrometheusResponseHeader) Size() (n int) {
 	return n
 }
 
+func (m *QueryWarning) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovDefinitions(uint64(l))
+	}
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.Start)
+	n += 1 + l + sovDefinitions(uint64(l))
+	l = github_com_gogo_protobuf_types.SizeOfStdTime(m.End)
+	n += 1 + l + sovDefinitions(uint64(l))
+	l = len(m.Shard)
+	if l > 0 {
+		n += 1 + l + sovDefinitions(uint64(l))
+	}
+	return n
+}
+
 func sovDefinitions(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -542,6 +744,19 @@ func (this *PrometheusResponseHeader) String() string {
 	}, "")
 	return s
 }
+func (this *QueryWarning) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&QueryWarning{`,
+		`Message:` + fmt.Sprintf("%v", this.Message) + `,`,
+		`Start:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.Start), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`End:` + strings.Replace(strings.Replace(fmt.Sprintf("%v", this.End), "Timestamp", "types.Timestamp", 1), `&`, ``, 1) + `,`,
+		`Shard:` + fmt.Sprintf("%v", this.Shard) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func valueToStringDefinitions(v interface{}) string {
 	rv := reflect.ValueOf(v)
 	if rv.IsNil() {
@@ -857,6 +1072,189 @@ func (m *PrometheusResponseHeader) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *QueryWarning) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowDefinitions
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: QueryWarning: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: QueryWarning: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDefinitions
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDefinitions
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDefinitions
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Start", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDefinitions
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDefinitions
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthDefinitions
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.Start, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field End", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDefinitions
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthDefinitions
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthDefinitions
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := github_com_gogo_protobuf_types.StdTimeUnmarshal(&m.End, dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Shard", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowDefinitions
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthDefinitions
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthDefinitions
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Shard = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipDefinitions(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthDefinitions
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthDefinitions
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipDefinitions(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -198,6 +198,9 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 	default:
 		return nil, fmt.Errorf("expected *LokiRequest or *LokiInstantRequest, got (%T)", r)
 	}
+	if partialResultsAllowed(ctx, tenants, ast.limits) {
+		ctx = logql.WithPartialResults(ctx)
+	}
 	query := ast.ng.Query(ctx, params, parsed)
 
 	res, err := query.Exec(ctx)
@@ -210,9 +213,10 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 		return nil, err
 	}
 
+	var resp queryrangebase.Response
 	switch res.Data.Type() {
 	case parser.ValueTypeMatrix:
-		return &LokiPromResponse{
+		resp = &LokiPromResponse{
 			Response: &queryrangebase.PrometheusResponse{
 				Status: loghttp.QueryStatusSuccess,
 				Data: queryrangebase.PrometheusData{
@@ -222,14 +226,14 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 				Headers: res.Headers,
 			},
 			Statistics: res.Statistics,
-		}, nil
+		}
 	case logqlmodel.ValueTypeStreams:
 		respHeaders := make([]queryrangebase.PrometheusResponseHeader, 0, len(res.Headers))
 		for i := range res.Headers {
 			respHeaders = append(respHeaders, *res.Headers[i])
 		}
 
-		return &LokiResponse{
+		resp = &LokiResponse{
 			Status:     loghttp.QueryStatusSuccess,
 			Direction:  params.Direction(),
 			Limit:      params.Limit(),
@@ -240,9 +244,9 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 				Result:     value.(loghttp.Streams).ToProto(),
 			},
 			Headers: respHeaders,
-		}, nil
+		}
 	case parser.ValueTypeVector:
-		return &LokiPromResponse{
+		resp = &LokiPromResponse{
 			Statistics: res.Statistics,
 			Response: &queryrangebase.PrometheusResponse{
 				Status: loghttp.QueryStatusSuccess,
@@ -252,10 +256,15 @@ func (ast *astMapperware) Do(ctx context.Context, r queryrangebase.Request) (que
 				},
 				Headers: res.Headers,
 			},
-		}, nil
+		}
 	default:
 		return nil, fmt.Errorf("unexpected downstream response type (%T)", res.Data.Type())
 	}
+
+	if len(res.Warnings) > 0 {
+		resp = withWarnings(resp, res.Warnings)
+	}
+	return resp, nil
 }
 
 // shardSplitter middleware will only shard appropriate requests that do not extend past the MinShardingLookback interval.
@@ -28,11 +28,16 @@ import (
 type Config struct {
 	queryrangebase.Config `yaml:",inline"`
 	Transformer           UserIDTransformer `yaml:"-"`
+	CoalesceQueries       bool              `yaml:"coalesce_identical_queries"`
+
+	// The interval the ingesters write continuous aggregations for, taken from the ingester config.
+	ContinuousAggregationsInterval time.Duration `yaml:"-"`
 }
 
 // RegisterFlags adds the flags required to configure this flag set.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	cfg.Config.RegisterFlags(f)
+	f.BoolVar(&cfg.CoalesceQueries, "frontend.coalesce-identical-queries", false, "Let concurrent identical log and metric (sub-)queries of the same tenant share a single execution after splitting, e.g. when many users open the same dashboard. Only the queries of the same query user, actor and priority are coalesced.")
 }
 
 // Stopper gracefully shutdown resources created
@@ -309,6 +314,9 @@ func NewLogFilterTripperware(
 		return nil, err
 	}
 
+	// The middleware is shared by all requests, so that concurrent requests can be coalesced.
+	coalesceMiddleware := NewCoalescingMiddleware(metrics.CoalescingMetrics)
+
 	return func(next http.RoundTripper) http.RoundTripper {
 		statsHandler := queryrangebase.NewRoundTripperHandler(indexStatsTripperware(next), codec)
 
@@ -320,6 +328,14 @@ func NewLogFilterTripperware(
 			SplitByIntervalMiddleware(schema.Configs, limits, codec, splitByTime, metrics.SplitByMetrics),
 		}
 
+		if cfg.CoalesceQueries {
+			queryRangeMiddleware = append(
+				queryRangeMiddleware,
+				queryrangebase.InstrumentMiddleware("coalesce", metrics.InstrumentMiddlewareMetrics),
+				coalesceMiddleware,
+			)
+		}
+
 		if cfg.CacheResults {
 			queryCacheMiddleware := NewLogResultCache(
 				log,
@@ -544,12 +560,16 @@ func NewMetricTripperware(
 		}
 	}
 
+	// The middleware is shared by all requests, so that concurrent requests can be coalesced.
+	coalesceMiddleware := NewCoalescingMiddleware(metrics.CoalescingMetrics)
+
 	return func(next http.RoundTripper) http.RoundTripper {
 		statsHandler := queryrangebase.NewRoundTripperHandler(indexStatsTripperware(next), codec)
 
 		queryRangeMiddleware := []queryrangebase.Middleware{
 			StatsCollectorMiddleware(),
 			NewLimitsMiddleware(limits),
+			NewContinuousAggregationsMiddleware(limits, cfg.ContinuousAggregationsInterval),
 		}
 
 		if cfg.AlignQueriesWithStep {
@@ -567,6 +587,14 @@ func NewMetricTripperware(
 			SplitByIntervalMiddleware(schema.Configs, limits, codec, splitMetricByTime, metrics.SplitByMetrics),
 		)
 
+		if cfg.CoalesceQueries {
+			queryRangeMiddleware = append(
+				queryRangeMiddleware,
+				queryrangebase.InstrumentMiddleware("coalesce", metrics.InstrumentMiddlewareMetrics),
+				coalesceMiddleware,
+			)
+		}
+
 		if cfg.CacheResults {
 			queryRangeMiddleware = append(
 				queryRangeMiddleware,
@@ -631,15 +659,27 @@ func NewInstantMetricTripperware(
 		return nil, err
 	}
 
+	// The middleware is shared by all requests, so that concurrent requests can be coalesced.
+	coalesceMiddleware := NewCoalescingMiddleware(metrics.CoalescingMetrics)
+
 	return func(next http.RoundTripper) http.RoundTripper {
 		statsHandler := queryrangebase.NewRoundTripperHandler(indexStatsTripperware(next), codec)
 
 		queryRangeMiddleware := []queryrangebase.Middleware{
 			StatsCollectorMiddleware(),
 			NewLimitsMiddleware(limits),
+			NewContinuousAggregationsMiddleware(limits, cfg.ContinuousAggregationsInterval),
 			NewQuerySizeLimiterMiddleware(schema.Configs, log, limits, codec, statsHandler),
 		}
 
+		if cfg.CoalesceQueries {
+			queryRangeMiddleware = append(
+				queryRangeMiddleware,
+				queryrangebase.InstrumentMiddleware("coalesce", metrics.InstrumentMiddlewareMetrics),
+				coalesceMiddleware,
+			)
+		}
+
 		if cfg.ShardedQueries {
 			queryRangeMiddleware = append(queryRangeMiddleware,
 				NewSplitByRangeMiddleware(log, limits, metrics.MiddlewareMapperMetrics.rangeMapper),
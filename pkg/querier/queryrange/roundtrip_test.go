@@ -24,6 +24,7 @@ import (
 	"github.com/weaveworks/common/middleware"
 	"github.com/weaveworks/common/user"
 
+	"github.com/grafana/loki/pkg/ingester/aggregations"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logqlmodel"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
@@ -49,7 +50,7 @@ var (
 				},
 			},
 		},
-	}, nil}
+	}, nil, false, 0}
 	matrix = promql.Matrix{
 		{
 			Points: []promql.Point{
@@ -906,6 +907,8 @@ type fakeLimits struct {
 	requiredNumberLabels    int
 	maxQueryBytesRead       int
 	maxQuerierBytesRead     int
+	allowPartialResults     bool
+	continuousAggregations  []*aggregations.Aggregation
 }
 
 func (f fakeLimits) QuerySplitDuration(key string) time.Duration {
@@ -962,6 +965,14 @@ func (f fakeLimits) MaxQuerierBytesRead(context.Context, string) int {
 	return f.maxQuerierBytesRead
 }
 
+func (f fakeLimits) AllowPartialQueryResults(context.Context, string) bool {
+	return f.allowPartialResults
+}
+
+func (f fakeLimits) ContinuousAggregations(string) []*aggregations.Aggregation {
+	return f.continuousAggregations
+}
+
 func (f fakeLimits) QueryTimeout(context.Context, string) time.Duration {
 	return f.queryTimeout
 }
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
 	"github.com/grafana/loki/pkg/storage/config"
 	"github.com/grafana/loki/pkg/util"
 	"github.com/grafana/loki/pkg/util/validation"
@@ -95,14 +96,21 @@ func (h *splitByInterval) Feed(ctx context.Context, input []*lokiResult) chan *l
 	return ch
 }
 
+// Process runs the split requests and returns their responses. If partial is true, splits that fail
+// with a server error are left out of the responses and described by the returned warnings instead.
 func (h *splitByInterval) Process(
 	ctx context.Context,
 	parallelism int,
 	threshold int64,
 	input []*lokiResult,
 	maxSeries int,
-) ([]queryrangebase.Response, error) {
-	var responses []queryrangebase.Response
+	partial bool,
+) ([]queryrangebase.Response, []definitions.QueryWarning, error) {
+	var (
+		responses []queryrangebase.Response
+		warnings  []definitions.QueryWarning
+		lastErr   error
+	)
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -130,10 +138,15 @@ func (h *splitByInterval) Process(
 	for _, x := range input {
 		select {
 		case <-ctx.Done():
-			return nil, ctx.Err()
+			return nil, nil, ctx.Err()
 		case data := <-x.ch:
 			if data.err != nil {
-				return nil, data.err
+				if !partial || !isPartialResultError(ctx, data.err) {
+					return nil, nil, data.err
+				}
+				warnings = append(warnings, missingSplitWarning(x.req, data.err))
+				lastErr = data.err
+				continue
 			}
 
 			responses = append(responses, data.resp)
@@ -143,7 +156,7 @@ func (h *splitByInterval) Process(
 				threshold -= casted.Count()
 
 				if threshold <= 0 {
-					return responses, nil
+					return responses, warnings, nil
 				}
 
 			}
@@ -151,7 +164,12 @@ func (h *splitByInterval) Process(
 		}
 	}
 
-	return responses, nil
+	// A partial result needs at least one successful split.
+	if len(responses) == 0 && lastErr != nil {
+		return nil, nil, lastErr
+	}
+
+	return responses, warnings, nil
 }
 
 func (h *splitByInterval) loop(ctx context.Context, ch <-chan *lokiResult, next queryrangebase.Handler) {
@@ -230,11 +248,18 @@ func (h *splitByInterval) Do(ctx context.Context, r queryrangebase.Request) (que
 	maxSeriesCapture := func(id string) int { return h.limits.MaxQuerySeries(ctx, id) }
 	maxSeries := validation.SmallestPositiveIntPerTenant(tenantIDs, maxSeriesCapture)
 	maxParallelism := MinWeightedParallelism(ctx, tenantIDs, h.configs, h.limits, model.Time(r.GetStart()), model.Time(r.GetEnd()))
-	resps, err := h.Process(ctx, maxParallelism, limit, input, maxSeries)
+	// Partial results are only supported for log and metric queries.
+	_, isQuery := r.(*LokiRequest)
+	partial := isQuery && partialResultsAllowed(ctx, tenantIDs, h.limits)
+	resps, warnings, err := h.Process(ctx, maxParallelism, limit, input, maxSeries, partial)
 	if err != nil {
 		return nil, err
 	}
-	return h.merger.MergeResponse(resps...)
+	merged, err := h.merger.MergeResponse(resps...)
+	if err != nil || len(warnings) == 0 {
+		return merged, err
+	}
+	return withWarnings(merged, warnings), nil
 }
 
 func splitByTime(req queryrangebase.Request, interval time.Duration) ([]queryrangebase.Request, error) {
@@ -3,6 +3,7 @@ package queryrange
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"runtime"
 	"strconv"
 	"sync"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
 	"github.com/weaveworks/common/user"
 	"gopkg.in/yaml.v2"
 
@@ -1005,3 +1007,76 @@ func Test_DoesntDeadlock(t *testing.T) {
 	// Allow for 1% increase in goroutines
 	require.LessOrEqual(t, endingGoroutines, startingGoroutines*101/100)
 }
+
+func Test_splitByInterval_PartialResults(t *testing.T) {
+	failingStart := time.Unix(0, 0).Add(time.Hour)
+	next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+		req := r.(*LokiRequest)
+		if req.StartTs.Equal(failingStart) {
+			return nil, httpgrpc.Errorf(http.StatusGatewayTimeout, "querier timeout")
+		}
+		return &LokiResponse{
+			Status:    loghttp.QueryStatusSuccess,
+			Direction: req.Direction,
+			Limit:     req.Limit,
+			Version:   uint32(loghttp.VersionV1),
+			Data: LokiData{
+				ResultType: loghttp.ResultTypeStream,
+				Result: []logproto.Stream{
+					{
+						Labels: `{foo="bar", level="debug"}`,
+						Entries: []logproto.Entry{
+							{Timestamp: req.StartTs, Line: fmt.Sprintf("%d", req.StartTs.UnixNano())},
+						},
+					},
+				},
+			},
+		}, nil
+	})
+
+	req := &LokiRequest{
+		StartTs:   time.Unix(0, 0),
+		EndTs:     time.Unix(0, (3 * time.Hour).Nanoseconds()),
+		Query:     `{foo="bar"}`,
+		Limit:     100,
+		Step:      1,
+		Direction: logproto.FORWARD,
+		Path:      "/loki/api/v1/query_range",
+	}
+	ctx := user.InjectOrgID(context.Background(), "1")
+
+	t.Run("disabled", func(t *testing.T) {
+		l := WithSplitByLimits(fakeLimits{maxQueryParallelism: 1}, time.Hour)
+		split := SplitByIntervalMiddleware(testSchemas, l, LokiCodec, splitByTime, nilMetrics).Wrap(next)
+
+		_, err := split.Do(ctx, req)
+		require.Error(t, err)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		l := WithSplitByLimits(fakeLimits{maxQueryParallelism: 1, allowPartialResults: true}, time.Hour)
+		split := SplitByIntervalMiddleware(testSchemas, l, LokiCodec, splitByTime, nilMetrics).Wrap(next)
+
+		res, err := split.Do(ctx, req)
+		require.NoError(t, err)
+
+		resp := res.(*LokiResponse)
+		require.Len(t, resp.Data.Result, 1)
+		require.Len(t, resp.Data.Result[0].Entries, 2)
+		require.Len(t, resp.Warnings, 1)
+		require.Equal(t, failingStart, resp.Warnings[0].Start)
+		require.Equal(t, failingStart.Add(time.Hour), resp.Warnings[0].End)
+		require.Contains(t, resp.GetHeaders(), &partialResultHeader)
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		next := queryrangebase.HandlerFunc(func(_ context.Context, r queryrangebase.Request) (queryrangebase.Response, error) {
+			return nil, httpgrpc.Errorf(http.StatusBadRequest, "max series limit exceeded")
+		})
+		l := WithSplitByLimits(fakeLimits{maxQueryParallelism: 1, allowPartialResults: true}, time.Hour)
+		split := SplitByIntervalMiddleware(testSchemas, l, LokiCodec, splitByTime, nilMetrics).Wrap(next)
+
+		_, err := split.Do(ctx, req)
+		require.Error(t, err)
+	})
+}
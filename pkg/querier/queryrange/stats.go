@@ -113,6 +113,13 @@ func StatsCollectorMiddleware() queryrangebase.Middleware {
 			// start a new statistics context to be used by middleware, which we will merge with the response's statistics
 			st, statsCtx := stats.NewContext(ctx)
 
+			// record the parameters upfront, so that failed requests can be audited too
+			if data, ok := ctx.Value(ctxKey).(*queryData); ok {
+				if p, errReq := paramsFromRequest(req); errReq == nil {
+					data.params = p
+				}
+			}
+
 			// execute the request
 			resp, err := next.Do(statsCtx, req)
 			if err != nil {
@@ -0,0 +1,115 @@
+package querier
+
+import (
+	"strings"
+
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/ingester"
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/loghttp"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+// matchesStreamShards returns whether the matchers select on the shard label added by the distributor
+// to automatically sharded streams, in which case the shards are not merged back into one stream.
+func matchesStreamShards(matchers []*labels.Matcher) bool {
+	for _, m := range matchers {
+		if m.Name == ingester.ShardLbName {
+			return true
+		}
+	}
+	return false
+}
+
+type unshardedLabels struct {
+	labels string
+	hash   uint64
+}
+
+// streamShardsMergingIterator removes the shard label from the labels of the streams of the wrapped iterator,
+// so the shards of a stream are returned as the one stream they were pushed as.
+// Entries are merged in the order of the wrapped iterator, which is ordered across all streams.
+type streamShardsMergingIterator struct {
+	iter.EntryIterator
+
+	cache map[string]unshardedLabels
+	cur   unshardedLabels
+}
+
+func newStreamShardsMergingIterator(it iter.EntryIterator) iter.EntryIterator {
+	return &streamShardsMergingIterator{
+		EntryIterator: it,
+		cache:         map[string]unshardedLabels{},
+	}
+}
+
+func (it *streamShardsMergingIterator) Next() bool {
+	if !it.EntryIterator.Next() {
+		return false
+	}
+
+	lbs := it.EntryIterator.Labels()
+	if !strings.Contains(lbs, ingester.ShardLbName) {
+		it.cur = unshardedLabels{labels: lbs, hash: it.EntryIterator.StreamHash()}
+		return true
+	}
+
+	cur, ok := it.cache[lbs]
+	if !ok {
+		cur = unshardedLabels{labels: lbs, hash: it.EntryIterator.StreamHash()}
+		if parsed, err := syntax.ParseLabels(lbs); err == nil {
+			unsharded := labels.NewBuilder(parsed).Del(ingester.ShardLbName).Labels(nil)
+			cur = unshardedLabels{labels: unsharded.String(), hash: unsharded.Hash()}
+		}
+		it.cache[lbs] = cur
+	}
+	it.cur = cur
+	return true
+}
+
+func (it *streamShardsMergingIterator) Labels() string {
+	return it.cur.labels
+}
+
+func (it *streamShardsMergingIterator) StreamHash() uint64 {
+	return it.cur.hash
+}
+
+// mergeSeriesShards removes the shard label from the series, and deduplicates the series
+// that only differed by their shard.
+func mergeSeriesShards(series []logproto.SeriesIdentifier) []logproto.SeriesIdentifier {
+	seen := make(map[string]struct{}, len(series))
+	result := series[:0]
+	for _, s := range series {
+		if _, ok := s.Labels[ingester.ShardLbName]; ok {
+			unsharded := make(map[string]string, len(s.Labels)-1)
+			for name, value := range s.Labels {
+				if name != ingester.ShardLbName {
+					unsharded[name] = value
+				}
+			}
+			s.Labels = unsharded
+		}
+
+		key := loghttp.LabelSet(s.Labels).String()
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, s)
+	}
+	return result
+}
+
+// removeShardLabelName removes the shard label from a list of label names.
+func removeShardLabelName(names []string) []string {
+	result := names[:0]
+	for _, name := range names {
+		if name != ingester.ShardLbName {
+			result = append(result, name)
+		}
+	}
+	return result
+}
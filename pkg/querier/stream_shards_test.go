@@ -0,0 +1,92 @@
+package querier
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/syntax"
+)
+
+func TestMatchesStreamShards(t *testing.T) {
+	for _, tc := range []struct {
+		query    string
+		expected bool
+	}{
+		{query: `{app="foo"}`, expected: false},
+		{query: `{app="foo", __stream_shard__="1"}`, expected: true},
+		{query: `{app="foo", __stream_shard__=~".+"}`, expected: true},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			matchers, err := syntax.ParseMatchers(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, matchesStreamShards(matchers))
+		})
+	}
+}
+
+func TestStreamShardsMergingIterator(t *testing.T) {
+	it := newStreamShardsMergingIterator(iter.NewSortEntryIterator([]iter.EntryIterator{
+		iter.NewStreamIterator(logproto.Stream{
+			Labels: `{__stream_shard__="0", app="foo"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(1, 0), Line: "1"},
+				{Timestamp: time.Unix(3, 0), Line: "3"},
+			},
+		}),
+		iter.NewStreamIterator(logproto.Stream{
+			Labels: `{__stream_shard__="1", app="foo"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(2, 0), Line: "2"},
+			},
+		}),
+		iter.NewStreamIterator(logproto.Stream{
+			Labels: `{app="bar"}`,
+			Entries: []logproto.Entry{
+				{Timestamp: time.Unix(4, 0), Line: "4"},
+			},
+		}),
+	}, logproto.FORWARD))
+	defer it.Close()
+
+	fooHash := labels.Labels{{Name: "app", Value: "foo"}}.Hash()
+	for _, expected := range []struct {
+		labels string
+		hash   uint64
+		line   string
+	}{
+		{labels: `{app="foo"}`, hash: fooHash, line: "1"},
+		{labels: `{app="foo"}`, hash: fooHash, line: "2"},
+		{labels: `{app="foo"}`, hash: fooHash, line: "3"},
+		{labels: `{app="bar"}`, line: "4"},
+	} {
+		require.True(t, it.Next())
+		require.Equal(t, expected.labels, it.Labels())
+		if expected.hash != 0 {
+			require.Equal(t, expected.hash, it.StreamHash())
+		}
+		require.Equal(t, expected.line, it.Entry().Line)
+	}
+	require.False(t, it.Next())
+	require.NoError(t, it.Error())
+}
+
+func TestMergeSeriesShards(t *testing.T) {
+	series := mergeSeriesShards([]logproto.SeriesIdentifier{
+		{Labels: map[string]string{"app": "foo", "__stream_shard__": "0"}},
+		{Labels: map[string]string{"app": "foo", "__stream_shard__": "1"}},
+		{Labels: map[string]string{"app": "bar"}},
+	})
+	require.Equal(t, []logproto.SeriesIdentifier{
+		{Labels: map[string]string{"app": "foo"}},
+		{Labels: map[string]string{"app": "bar"}},
+	}, series)
+}
+
+func TestRemoveShardLabelName(t *testing.T) {
+	require.Equal(t, []string{"app", "env"}, removeShardLabelName([]string{"__stream_shard__", "app", "env"}))
+}
@@ -41,6 +41,9 @@ type Tailer struct {
 	currEntry  logproto.Entry
 	currLabels string
 
+	// position of the entries sent, returned in the responses to resume the tail from
+	cursor *tailCursor
+
 	// keep track of the streams for metrics about active streams
 	seenStreams    map[uint64]struct{}
 	seenStreamsMtx sync.Mutex
@@ -119,6 +122,7 @@ func (t *Tailer) loop() {
 				Labels:  t.currLabels,
 				Entries: []logproto.Entry{t.currEntry},
 			})
+			t.cursor.advance(t.currLabels, t.currEntry)
 		}
 
 		// If all consumed entries have been dropped because the response channel is blocked
@@ -157,6 +161,7 @@ func (t *Tailer) loop() {
 		if len(droppedEntries) > 0 {
 			tailResponse.DroppedEntries = droppedEntries
 		}
+		tailResponse.Cursor = t.cursor.encode()
 
 		select {
 		case t.responseChan <- tailResponse:
@@ -302,6 +307,7 @@ func newTailer(
 	delayFor time.Duration,
 	querierTailClients map[string]logproto.Querier_TailClient,
 	historicEntries iter.EntryIterator,
+	cursor *tailCursor,
 	tailDisconnectedIngesters func([]string) (map[string]logproto.Querier_TailClient, error),
 	tailMaxDuration time.Duration,
 	waitEntryThrottle time.Duration,
@@ -310,6 +316,7 @@ func newTailer(
 	t := Tailer{
 		openStreamIterator:        iter.NewMergeEntryIterator(context.Background(), []iter.EntryIterator{historicEntries}, logproto.FORWARD),
 		querierTailClients:        querierTailClients,
+		cursor:                    cursor,
 		delayFor:                  delayFor,
 		responseChan:              make(chan *loghttp.TailResponse, maxBufferedTailResponses),
 		closeErrChan:              make(chan error),
@@ -0,0 +1,343 @@
+package querier
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+const (
+	tailCursorVersion = 1
+
+	// the maximum number of streams whose position is kept in a tail cursor, which bounds its size.
+	maxTailCursorStreams = 128
+
+	// the maximum number of line hashes kept for the last timestamp of a stream.
+	maxTailCursorLinesPerStream = 32
+)
+
+var errInvalidTailCursor = errors.New("invalid tail cursor")
+
+// tailCursor is the position of a tail: the timestamp of the last entry sent for each stream, and the hashes of the
+// lines sent at that timestamp. A tail resumed from a cursor only sends the entries that come after.
+// The entries of streams without a position are considered sent up to from, the start of the tail. When a tail sees
+// more streams than the cursor keeps, the positions of the least recently active streams are evicted, and their entries
+// after from may be sent again by a resumed tail.
+type tailCursor struct {
+	from    int64
+	streams map[uint64]*tailStreamPosition
+}
+
+type tailStreamPosition struct {
+	ts    int64
+	lines []uint64
+}
+
+func newTailCursor(from time.Time) *tailCursor {
+	return &tailCursor{
+		from:    from.UnixNano(),
+		streams: map[uint64]*tailStreamPosition{},
+	}
+}
+
+// advance records that an entry of the stream with the given labels was sent.
+func (c *tailCursor) advance(labels string, entry logproto.Entry) {
+	ts, line := entry.Timestamp.UnixNano(), lineHash(entry.Line)
+
+	key := xxhash.Sum64String(labels)
+	p, ok := c.streams[key]
+	if !ok {
+		if len(c.streams) >= maxTailCursorStreams {
+			c.evictOldest()
+		}
+		p = &tailStreamPosition{ts: ts}
+		c.streams[key] = p
+	}
+
+	switch {
+	case ts > p.ts:
+		p.ts = ts
+		p.lines = append(p.lines[:0], line)
+	case ts == p.ts:
+		if !containsLine(p.lines, line) && len(p.lines) < maxTailCursorLinesPerStream {
+			p.lines = append(p.lines, line)
+		}
+	}
+	// entries older than the position of their stream, received out of order, don't move it.
+}
+
+func (c *tailCursor) evictOldest() {
+	var (
+		oldest    uint64
+		oldestTs  int64
+		hasOldest bool
+	)
+	for key, p := range c.streams {
+		if !hasOldest || p.ts < oldestTs {
+			oldest, oldestTs, hasOldest = key, p.ts, true
+		}
+	}
+	if hasOldest {
+		delete(c.streams, oldest)
+	}
+}
+
+// sent returns whether the entry of the stream with the given labels was already sent before the cursor.
+func (c *tailCursor) sent(labels string, entry logproto.Entry) bool {
+	ts := entry.Timestamp.UnixNano()
+
+	p, ok := c.streams[xxhash.Sum64String(labels)]
+	if !ok {
+		return ts <= c.from
+	}
+	if ts == p.ts {
+		return containsLine(p.lines, lineHash(entry.Line))
+	}
+	return ts < p.ts
+}
+
+// start returns the time from which a resumed tail replays the entries. Tails send the entries in order, so the
+// entries missed after the cursor come after the latest entry sent, but for the ones received late by the ingesters
+// which are replayed from lookback before it.
+func (c *tailCursor) start(lookback time.Duration) time.Time {
+	latest := c.from
+	for _, p := range c.streams {
+		if p.ts > latest {
+			latest = p.ts
+		}
+	}
+	return time.Unix(0, latest).Add(-lookback)
+}
+
+func (c *tailCursor) clone() *tailCursor {
+	cloned := &tailCursor{
+		from:    c.from,
+		streams: make(map[uint64]*tailStreamPosition, len(c.streams)),
+	}
+	for key, p := range c.streams {
+		cloned.streams[key] = &tailStreamPosition{ts: p.ts, lines: append([]uint64(nil), p.lines...)}
+	}
+	return cloned
+}
+
+// encode returns the cursor as an opaque URL safe string.
+func (c *tailCursor) encode() string {
+	keys := make([]uint64, 0, len(c.streams))
+	for key := range c.streams {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	buf := make([]byte, 0, 1+2*binary.MaxVarintLen64+len(keys)*(8+binary.MaxVarintLen64+1+8))
+	buf = append(buf, tailCursorVersion)
+	buf = binary.AppendVarint(buf, c.from)
+	buf = binary.AppendUvarint(buf, uint64(len(keys)))
+	for _, key := range keys {
+		p := c.streams[key]
+		buf = binary.BigEndian.AppendUint64(buf, key)
+		// timestamps are encoded relative to from, which they are close to.
+		buf = binary.AppendVarint(buf, p.ts-c.from)
+		buf = binary.AppendUvarint(buf, uint64(len(p.lines)))
+		for _, line := range p.lines {
+			buf = binary.BigEndian.AppendUint64(buf, line)
+		}
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// parseTailCursor decodes a cursor returned by encode.
+func parseTailCursor(s string) (*tailCursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(buf) == 0 || buf[0] != tailCursorVersion {
+		return nil, errInvalidTailCursor
+	}
+	d := tailCursorDecoder{buf: buf[1:]}
+
+	c := &tailCursor{from: d.varint()}
+	streams := d.uvarint()
+	if d.err != nil || streams > maxTailCursorStreams {
+		return nil, errInvalidTailCursor
+	}
+	c.streams = make(map[uint64]*tailStreamPosition, streams)
+	for i := uint64(0); i < streams; i++ {
+		key := d.uint64()
+		p := &tailStreamPosition{ts: c.from + d.varint()}
+		lines := d.uvarint()
+		if d.err != nil || lines > maxTailCursorLinesPerStream {
+			return nil, errInvalidTailCursor
+		}
+		for j := uint64(0); j < lines; j++ {
+			p.lines = append(p.lines, d.uint64())
+		}
+		c.streams[key] = p
+	}
+	if d.err != nil || len(d.buf) > 0 {
+		return nil, errInvalidTailCursor
+	}
+	return c, nil
+}
+
+type tailCursorDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *tailCursorDecoder) varint() int64 {
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errInvalidTailCursor
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *tailCursorDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errInvalidTailCursor
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *tailCursorDecoder) uint64() uint64 {
+	if len(d.buf) < 8 {
+		d.err = errInvalidTailCursor
+		return 0
+	}
+	v := binary.BigEndian.Uint64(d.buf)
+	d.buf = d.buf[8:]
+	return v
+}
+
+func lineHash(line string) uint64 {
+	return xxhash.Sum64String(line)
+}
+
+func containsLine(lines []uint64, line uint64) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+// tailCatchUpPage selects the entries of a page of the catch-up of a resumed tail, the at most limit first entries from
+// start. It returns the entries read in the order of their timestamps, and their number.
+type tailCatchUpPage func(start time.Time, limit uint32) (iter.EntryIterator, uint32, error)
+
+// tailCatchUpIterator replays the entries of the catch-up window of a resumed tail which were not sent before its
+// cursor. The window is read in pages of at most limit entries, the next page being selected once the previous one
+// was replayed, so that the whole window is replayed whatever the number of entries missed.
+type tailCatchUpIterator struct {
+	selectPage tailCatchUpPage
+	cursor     *tailCursor
+	limit      uint32
+
+	page     iter.EntryIterator
+	lastPage bool
+	err      error
+
+	// the timestamp of the last entry of the page and the entries at that timestamp, which the next page, starting at
+	// that timestamp, selects again.
+	pageEnd      time.Time
+	pageEndLines map[tailCatchUpLine]struct{}
+	prevEnd      time.Time
+	prevEndLines map[tailCatchUpLine]struct{}
+}
+
+type tailCatchUpLine struct {
+	labels, line string
+}
+
+// newTailCatchUpIterator returns an iterator replaying the entries of the first page, and the following ones.
+func newTailCatchUpIterator(first iter.EntryIterator, size uint32, selectPage tailCatchUpPage, cursor *tailCursor, limit uint32) *tailCatchUpIterator {
+	return &tailCatchUpIterator{
+		selectPage:   selectPage,
+		cursor:       cursor,
+		limit:        limit,
+		page:         first,
+		lastPage:     size < limit,
+		pageEndLines: map[tailCatchUpLine]struct{}{},
+	}
+}
+
+func (it *tailCatchUpIterator) Next() bool {
+	for {
+		for it.page.Next() {
+			entry, labels := it.page.Entry(), it.page.Labels()
+			it.trackPageEnd(labels, entry)
+			if it.cursor.sent(labels, entry) || it.selectedAgain(labels, entry) {
+				continue
+			}
+			return true
+		}
+		if it.lastPage || it.err != nil || !it.nextPage() {
+			return false
+		}
+	}
+}
+
+func (it *tailCatchUpIterator) trackPageEnd(labels string, entry logproto.Entry) {
+	if !entry.Timestamp.Equal(it.pageEnd) {
+		it.pageEnd = entry.Timestamp
+		it.pageEndLines = map[tailCatchUpLine]struct{}{}
+	}
+	it.pageEndLines[tailCatchUpLine{labels: labels, line: entry.Line}] = struct{}{}
+}
+
+// selectedAgain returns whether the entry was already replayed at the end of the previous page.
+func (it *tailCatchUpIterator) selectedAgain(labels string, entry logproto.Entry) bool {
+	if !entry.Timestamp.Equal(it.prevEnd) {
+		return false
+	}
+	_, ok := it.prevEndLines[tailCatchUpLine{labels: labels, line: entry.Line}]
+	return ok
+}
+
+func (it *tailCatchUpIterator) nextPage() bool {
+	start := it.pageEnd
+	if it.pageEnd.Equal(it.prevEnd) {
+		// the whole page is at the same timestamp as the end of the previous one, move past it.
+		start = start.Add(time.Nanosecond)
+	}
+	it.prevEnd, it.prevEndLines = it.pageEnd, it.pageEndLines
+	it.pageEndLines = map[tailCatchUpLine]struct{}{}
+
+	if err := it.page.Close(); err != nil {
+		it.err = err
+		return false
+	}
+	page, size, err := it.selectPage(start, it.limit)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page, it.lastPage = page, size < it.limit
+	return true
+}
+
+func (it *tailCatchUpIterator) Entry() logproto.Entry { return it.page.Entry() }
+func (it *tailCatchUpIterator) Labels() string        { return it.page.Labels() }
+func (it *tailCatchUpIterator) StreamHash() uint64    { return it.page.StreamHash() }
+
+func (it *tailCatchUpIterator) Error() error {
+	if it.err != nil {
+		return it.err
+	}
+	return it.page.Error()
+}
+
+func (it *tailCatchUpIterator) Close() error {
+	return it.page.Close()
+}
@@ -0,0 +1,141 @@
+package querier
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/iter"
+	"github.com/grafana/loki/pkg/logproto"
+)
+
+func TestTailCursor(t *testing.T) {
+	start := time.Unix(100, 0)
+	entry := func(sec int64, line string) logproto.Entry {
+		return logproto.Entry{Timestamp: time.Unix(sec, 0), Line: line}
+	}
+
+	c := newTailCursor(start)
+	c.advance(`{app="foo"}`, entry(101, "a"))
+	c.advance(`{app="foo"}`, entry(102, "b"))
+	c.advance(`{app="foo"}`, entry(102, "c"))
+	// out of order entries don't move the position of the stream.
+	c.advance(`{app="foo"}`, entry(101, "d"))
+	c.advance(`{app="bar"}`, entry(99, "e"))
+
+	for _, c := range []*tailCursor{c, mustParseTailCursor(t, c.encode())} {
+		require.True(t, c.sent(`{app="foo"}`, entry(101, "a")))
+		require.True(t, c.sent(`{app="foo"}`, entry(102, "b")))
+		require.True(t, c.sent(`{app="foo"}`, entry(102, "c")))
+		require.False(t, c.sent(`{app="foo"}`, entry(102, "f")))
+		require.False(t, c.sent(`{app="foo"}`, entry(103, "b")))
+		require.True(t, c.sent(`{app="bar"}`, entry(99, "e")))
+		require.False(t, c.sent(`{app="bar"}`, entry(100, "g")))
+
+		// the entries of streams without a position are considered sent up to the start of the cursor.
+		require.True(t, c.sent(`{app="baz"}`, entry(100, "h")))
+		require.False(t, c.sent(`{app="baz"}`, entry(101, "h")))
+
+		require.Equal(t, time.Unix(102, 0).Add(-30*time.Second), c.start(30*time.Second))
+	}
+}
+
+func TestTailCursor_Eviction(t *testing.T) {
+	c := newTailCursor(time.Unix(0, 0))
+	for i := 0; i < maxTailCursorStreams+10; i++ {
+		c.advance(fmt.Sprintf(`{i="%d"}`, i), logproto.Entry{Timestamp: time.Unix(int64(i+1), 0), Line: "line"})
+	}
+	require.Len(t, c.streams, maxTailCursorStreams)
+
+	// the least recently active streams are evicted, their entries after the start of the cursor may be sent again
+	// but the entries of the other streams without a position are never considered sent.
+	require.Equal(t, time.Unix(maxTailCursorStreams+10, 0), c.start(0))
+	require.True(t, c.sent(`{i="0"}`, logproto.Entry{Timestamp: time.Unix(0, 0), Line: "line"}))
+	require.False(t, c.sent(`{i="0"}`, logproto.Entry{Timestamp: time.Unix(1, 0), Line: "line"}))
+	require.False(t, c.sent(`{i="new"}`, logproto.Entry{Timestamp: time.Unix(5, 0), Line: "line"}))
+	require.True(t, c.sent(`{i="137"}`, logproto.Entry{Timestamp: time.Unix(138, 0), Line: "line"}))
+
+	parsed := mustParseTailCursor(t, c.encode())
+	require.Equal(t, c, parsed)
+}
+
+func TestParseTailCursor_Invalid(t *testing.T) {
+	valid := newTailCursor(time.Now())
+	valid.advance(`{app="foo"}`, logproto.Entry{Timestamp: time.Now(), Line: "line"})
+	encoded := valid.encode()
+
+	for _, s := range []string{
+		"",
+		"not base64!",
+		"AA",                     // unknown version
+		encoded[:len(encoded)-2], // truncated
+		encoded + "AA",           // trailing data
+	} {
+		_, err := parseTailCursor(s)
+		require.ErrorIs(t, err, errInvalidTailCursor, s)
+	}
+}
+
+func TestTailCatchUpIterator(t *testing.T) {
+	entry := func(sec int64, line string) logproto.Entry {
+		return logproto.Entry{Timestamp: time.Unix(sec, 0), Line: line}
+	}
+	stream := logproto.Stream{Labels: `{app="foo"}`, Entries: []logproto.Entry{
+		entry(1, "a"), entry(2, "b"), entry(2, "c"), entry(2, "d"), entry(3, "e"), entry(4, "f"), entry(5, "g"),
+	}}
+	cursor := newTailCursor(time.Unix(0, 0))
+	cursor.advance(stream.Labels, stream.Entries[0])
+
+	// selects the at most limit first entries from start, as the store does.
+	var starts []time.Time
+	selectPage := func(start time.Time, limit uint32) (iter.EntryIterator, uint32, error) {
+		starts = append(starts, start)
+		page := logproto.Stream{Labels: stream.Labels}
+		for _, e := range stream.Entries {
+			if !e.Timestamp.Before(start) && uint32(len(page.Entries)) < limit {
+				page.Entries = append(page.Entries, e)
+			}
+		}
+		return iter.NewStreamIterator(page), uint32(len(page.Entries)), nil
+	}
+
+	for _, tc := range []struct {
+		limit  uint32
+		starts []time.Time
+	}{
+		{limit: 10, starts: []time.Time{time.Unix(0, 0)}},
+		{limit: 3, starts: []time.Time{time.Unix(0, 0), time.Unix(2, 0), time.Unix(2, 1), time.Unix(5, 0)}},
+		// the entries at the end of a page are selected again by the next one, or skipped when they fill the page.
+		{limit: 2, starts: []time.Time{time.Unix(0, 0), time.Unix(2, 0), time.Unix(2, 1), time.Unix(4, 0), time.Unix(5, 0)}},
+	} {
+		t.Run(fmt.Sprintf("limit %d", tc.limit), func(t *testing.T) {
+			starts = nil
+			first, size, err := selectPage(time.Unix(0, 0), tc.limit)
+			require.NoError(t, err)
+			it := newTailCatchUpIterator(first, size, selectPage, cursor, tc.limit)
+
+			var lines []string
+			for it.Next() {
+				lines = append(lines, it.Entry().Line)
+			}
+			require.NoError(t, it.Error())
+			require.NoError(t, it.Close())
+			require.Equal(t, tc.starts, starts)
+			if tc.limit == 2 {
+				// more entries than a page at the same timestamp can't all be replayed.
+				require.Equal(t, []string{"b", "c", "e", "f", "g"}, lines)
+				return
+			}
+			require.Equal(t, []string{"b", "c", "d", "e", "f", "g"}, lines)
+		})
+	}
+}
+
+func mustParseTailCursor(t *testing.T, s string) *tailCursor {
+	t.Helper()
+	c, err := parseTailCursor(s)
+	require.NoError(t, err)
+	return c
+}
@@ -42,7 +42,7 @@ func enqueueRequestsForActor(t testing.TB, actor []string, useActor bool, queue
 			if !useActor {
 				actor = nil
 			}
-			err := queue.Enqueue("tenant", actor, r, 0, nil)
+			err := queue.Enqueue("tenant", actor, r, 0, 0, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -56,11 +56,11 @@ func BenchmarkQueryFairness(t *testing.B) {
 
 	for _, useActor := range []bool{false, true} {
 		t.Run(fmt.Sprintf("use hierarchical queues = %v", useActor), func(t *testing.B) {
-			requestQueue := NewRequestQueue(1024, 0, NewMetrics("query_scheduler", nil))
+			requestQueue := NewRequestQueue(1024, 0, PriorityConfig{}, NewMetrics("query_scheduler", nil))
 			enqueueRequestsForActor(t, []string{}, useActor, requestQueue, numSubRequestsActorA, 50*time.Millisecond)
 			enqueueRequestsForActor(t, []string{"a"}, useActor, requestQueue, numSubRequestsActorA, 100*time.Millisecond)
 			enqueueRequestsForActor(t, []string{"b"}, useActor, requestQueue, numSubRequestsActorB, 50*time.Millisecond)
-			requestQueue.queues.recomputeUserQueriers()
+			requestQueue.queues[PriorityInteractive].recomputeUserQueriers()
 
 			// set timeout to minize impact on overall test run duration in case something goes wrong
 			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -131,19 +131,19 @@ func TestQueryFairnessAcrossSameLevel(t *testing.T) {
 			  456: [210]
 	**/
 
-	requestQueue := NewRequestQueue(1024, 0, NewMetrics("query_scheduler", nil))
-	_ = requestQueue.Enqueue("tenant1", []string{}, r(0), 0, nil)
-	_ = requestQueue.Enqueue("tenant1", []string{}, r(1), 0, nil)
-	_ = requestQueue.Enqueue("tenant1", []string{}, r(2), 0, nil)
-	_ = requestQueue.Enqueue("tenant1", []string{"abc"}, r(10), 0, nil)
-	_ = requestQueue.Enqueue("tenant1", []string{"abc"}, r(11), 0, nil)
-	_ = requestQueue.Enqueue("tenant1", []string{"abc"}, r(12), 0, nil)
-	_ = requestQueue.Enqueue("tenant1", []string{"xyz"}, r(20), 0, nil)
-	_ = requestQueue.Enqueue("tenant1", []string{"xyz"}, r(21), 0, nil)
-	_ = requestQueue.Enqueue("tenant1", []string{"xyz"}, r(22), 0, nil)
-	_ = requestQueue.Enqueue("tenant1", []string{"xyz", "123"}, r(200), 0, nil)
-	_ = requestQueue.Enqueue("tenant1", []string{"xyz", "456"}, r(210), 0, nil)
-	requestQueue.queues.recomputeUserQueriers()
+	requestQueue := NewRequestQueue(1024, 0, PriorityConfig{}, NewMetrics("query_scheduler", nil))
+	_ = requestQueue.Enqueue("tenant1", []string{}, r(0), 0, 0, nil)
+	_ = requestQueue.Enqueue("tenant1", []string{}, r(1), 0, 0, nil)
+	_ = requestQueue.Enqueue("tenant1", []string{}, r(2), 0, 0, nil)
+	_ = requestQueue.Enqueue("tenant1", []string{"abc"}, r(10), 0, 0, nil)
+	_ = requestQueue.Enqueue("tenant1", []string{"abc"}, r(11), 0, 0, nil)
+	_ = requestQueue.Enqueue("tenant1", []string{"abc"}, r(12), 0, 0, nil)
+	_ = requestQueue.Enqueue("tenant1", []string{"xyz"}, r(20), 0, 0, nil)
+	_ = requestQueue.Enqueue("tenant1", []string{"xyz"}, r(21), 0, 0, nil)
+	_ = requestQueue.Enqueue("tenant1", []string{"xyz"}, r(22), 0, 0, nil)
+	_ = requestQueue.Enqueue("tenant1", []string{"xyz", "123"}, r(200), 0, 0, nil)
+	_ = requestQueue.Enqueue("tenant1", []string{"xyz", "456"}, r(210), 0, 0, nil)
+	requestQueue.queues[PriorityInteractive].recomputeUserQueriers()
 
 	items := make([]int, 0)
 
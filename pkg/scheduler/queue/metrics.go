@@ -9,6 +9,7 @@ type Metrics struct {
 	queueLength       *prometheus.GaugeVec   // Per tenant
 	discardedRequests *prometheus.CounterVec // Per tenant
 	enqueueCount      *prometheus.CounterVec // Per tenant and level
+	dequeueCount      *prometheus.CounterVec // Per priority class
 }
 
 func NewMetrics(subsystem string, registerer prometheus.Registerer) *Metrics {
@@ -31,6 +32,12 @@ func NewMetrics(subsystem string, registerer prometheus.Registerer) *Metrics {
 			Name:      "enqueue_count",
 			Help:      "Total number of enqueued (sub-)queries.",
 		}, []string{"user", "level"}),
+		dequeueCount: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "loki",
+			Subsystem: subsystem,
+			Name:      "dequeue_count",
+			Help:      "Total number of dequeued (sub-)queries per priority class.",
+		}, []string{"priority"}),
 	}
 }
 
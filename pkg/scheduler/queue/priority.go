@@ -0,0 +1,176 @@
+package queue
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Priority is the class of a request that determines how it is dequeued
+// relative to requests of other classes.
+type Priority int
+
+const (
+	// PriorityInteractive is the class of queries issued by users, e.g. from Grafana.
+	PriorityInteractive Priority = iota
+	// PriorityBatch is the class of long running queries, such as exports.
+	PriorityBatch
+	// PriorityRuler is the class of queries issued by the ruler during rule evaluation.
+	PriorityRuler
+
+	numPriorities
+)
+
+var priorityNames = [numPriorities]string{"interactive", "batch", "ruler"}
+
+func (p Priority) String() string {
+	if p < 0 || p >= numPriorities {
+		return fmt.Sprintf("unknown(%d)", int(p))
+	}
+	return priorityNames[p]
+}
+
+// ParsePriority returns the priority class with the given name.
+func ParsePriority(s string) (Priority, error) {
+	for i, name := range priorityNames {
+		if strings.EqualFold(s, name) {
+			return Priority(i), nil
+		}
+	}
+	return PriorityInteractive, fmt.Errorf("unknown priority class %q", s)
+}
+
+// PrioritizedRequest is implemented by requests that belong to a priority class.
+// Requests that do not implement it are treated as interactive.
+type PrioritizedRequest interface {
+	Priority() Priority
+}
+
+func priorityOf(req Request) Priority {
+	if pr, ok := req.(PrioritizedRequest); ok {
+		if p := pr.Priority(); p >= 0 && p < numPriorities {
+			return p
+		}
+	}
+	return PriorityInteractive
+}
+
+// PriorityConfig configures how requests of the different priority classes are dequeued.
+type PriorityConfig struct {
+	InteractiveWeight           int     `yaml:"interactive_weight"`
+	BatchWeight                 int     `yaml:"batch_weight"`
+	RulerWeight                 int     `yaml:"ruler_weight"`
+	ReservedInteractiveCapacity float64 `yaml:"reserved_interactive_capacity"`
+}
+
+// RegisterFlagsWithPrefix registers flags for the priority classes.
+func (cfg *PriorityConfig) RegisterFlagsWithPrefix(prefix string, f *flag.FlagSet) {
+	f.IntVar(&cfg.InteractiveWeight, prefix+"interactive-weight", 1, "Relative weight of interactive queries when requests of multiple priority classes are waiting in the queue.")
+	f.IntVar(&cfg.BatchWeight, prefix+"batch-weight", 1, "Relative weight of batch queries, selected with the X-Loki-Query-Priority header, when requests of multiple priority classes are waiting in the queue.")
+	f.IntVar(&cfg.RulerWeight, prefix+"ruler-weight", 1, "Relative weight of queries issued by the ruler when requests of multiple priority classes are waiting in the queue.")
+	f.Float64Var(&cfg.ReservedInteractiveCapacity, prefix+"reserved-interactive-capacity", 0, "Fraction of the connected querier workers that only handle interactive queries. Batch and ruler queries are not dequeued while they occupy the remaining querier workers, of which there is always at least one. 0 means no capacity is reserved.")
+}
+
+// Validate validates the priority configuration.
+func (cfg *PriorityConfig) Validate() error {
+	if cfg.InteractiveWeight < 0 || cfg.BatchWeight < 0 || cfg.RulerWeight < 0 {
+		return errors.New("priority class weights must not be negative")
+	}
+	if cfg.ReservedInteractiveCapacity < 0 || cfg.ReservedInteractiveCapacity >= 1 {
+		return errors.New("reserved interactive capacity must be in the range [0, 1)")
+	}
+	return nil
+}
+
+// weights returns the weight of each priority class. A weight of zero
+// falls back to one, so that no class can starve.
+func (cfg PriorityConfig) weights() [numPriorities]int {
+	w := [numPriorities]int{cfg.InteractiveWeight, cfg.BatchWeight, cfg.RulerWeight}
+	for i := range w {
+		if w[i] <= 0 {
+			w[i] = 1
+		}
+	}
+	return w
+}
+
+// priorityScheduler picks the priority class of the next request using
+// smooth weighted round-robin over the classes that have pending requests.
+type priorityScheduler struct {
+	weights  [numPriorities]int
+	current  [numPriorities]int
+	reserved float64
+
+	// Number of in-flight requests of each class. Interactive requests are not tracked.
+	inflight [numPriorities]int
+}
+
+func newPriorityScheduler(cfg PriorityConfig) *priorityScheduler {
+	return &priorityScheduler{
+		weights:  cfg.weights(),
+		reserved: cfg.ReservedInteractiveCapacity,
+	}
+}
+
+// candidates returns the classes that may be dequeued from, in order of
+// preference. The pending function returns whether a class has pending requests.
+func (s *priorityScheduler) candidates(connectedWorkers int, pending func(Priority) bool) []Priority {
+	candidates := make([]Priority, 0, numPriorities)
+	for p := Priority(0); p < numPriorities; p++ {
+		if pending(p) && s.hasCapacity(p, connectedWorkers) {
+			candidates = append(candidates, p)
+		}
+	}
+	// Insertion sort by descending weight, the list holds at most numPriorities items.
+	score := func(p Priority) int { return s.current[p] + s.weights[p] }
+	for i := 1; i < len(candidates); i++ {
+		for j := i; j > 0 && score(candidates[j]) > score(candidates[j-1]); j-- {
+			candidates[j], candidates[j-1] = candidates[j-1], candidates[j]
+		}
+	}
+	return candidates
+}
+
+// hasCapacity returns whether a request of the given class may be dequeued
+// without using querier capacity reserved for interactive requests.
+func (s *priorityScheduler) hasCapacity(p Priority, connectedWorkers int) bool {
+	if p == PriorityInteractive || s.reserved <= 0 {
+		return true
+	}
+	nonInteractive := 0
+	for c := Priority(0); c < numPriorities; c++ {
+		if c != PriorityInteractive {
+			nonInteractive += s.inflight[c]
+		}
+	}
+	// At least one querier worker is left to the other classes, so that they can't starve.
+	available := int(float64(connectedWorkers) * (1 - s.reserved))
+	if available < 1 {
+		available = 1
+	}
+	return nonInteractive < available
+}
+
+// dequeued records that a request of the given class was dequeued after
+// choosing from the given candidates.
+func (s *priorityScheduler) dequeued(p Priority, candidates []Priority) {
+	total := 0
+	for _, c := range candidates {
+		s.current[c] += s.weights[c]
+		total += s.weights[c]
+	}
+	s.current[p] -= total
+
+	if p != PriorityInteractive {
+		s.inflight[p]++
+	}
+}
+
+// released records that a request of the given class is done.
+func (s *priorityScheduler) released(p Priority) {
+	if p != PriorityInteractive && s.inflight[p] > 0 {
+		s.inflight[p]--
+	}
+}
@@ -53,20 +53,34 @@ type RequestQueue struct {
 
 	connectedQuerierWorkers *atomic.Int32
 
-	mtx     sync.Mutex
-	cond    contextCond // Notified when request is enqueued or dequeued, or querier is disconnected.
-	queues  *tenantQueues
-	stopped bool
+	mtx        sync.Mutex
+	cond       contextCond // Notified when request is enqueued or dequeued, or querier is disconnected.
+	queues     [numPriorities]*tenantQueues
+	priorities *priorityScheduler
+	stopped    bool
+
+	// The limit of queued requests of a tenant applies to its requests of all priority classes.
+	maxOutstandingPerTenant int
+	queueLen                intPointerMap
+
+	// Position of each querier in the tenant order of each priority class.
+	cursors map[string]*querierCursor
 
 	metrics *Metrics
 }
 
-func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, metrics *Metrics) *RequestQueue {
+func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, priorities PriorityConfig, metrics *Metrics) *RequestQueue {
 	q := &RequestQueue{
-		queues:                  newTenantQueues(maxOutstandingPerTenant, forgetDelay),
+		priorities:              newPriorityScheduler(priorities),
+		maxOutstandingPerTenant: maxOutstandingPerTenant,
+		queueLen:                make(intPointerMap),
+		cursors:                 map[string]*querierCursor{},
 		connectedQuerierWorkers: atomic.NewInt32(0),
 		metrics:                 metrics,
 	}
+	for p := range q.queues {
+		q.queues[p] = newTenantQueues(maxOutstandingPerTenant, forgetDelay)
+	}
 
 	q.cond = contextCond{Cond: sync.NewCond(&q.mtx)}
 	q.Service = services.NewTimerService(forgetCheckPeriod, nil, q.forgetDisconnectedQueriers, q.stopping).WithName("request queue")
@@ -75,11 +89,16 @@ func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, met
 }
 
 // Enqueue puts the request into the queue. MaxQueries is tenant-specific value that specifies how many queriers can
-// this tenant use (zero or negative = all queriers). It is passed to each Enqueue, because it can change
-// between calls.
+// this tenant use (zero or negative = all queriers). MaxConcurrentPerActor is tenant-specific value that specifies
+// how many requests of each query user, see QueryUserRequest, can be in-flight at the same time
+// (zero or negative = unlimited). Requests without a query user in front of their path aren't limited.
+// Both are passed to each Enqueue, because they can change between calls.
+//
+// Requests are queued separately for each priority class, see PrioritizedRequest, but the maximum number of
+// outstanding requests of a tenant applies to all classes together.
 //
 // If request is successfully enqueued, successFn is called with the lock held, before any querier can receive the request.
-func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, maxQueriers int, successFn func()) error {
+func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, maxQueriers, maxConcurrentPerActor int, successFn func()) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
@@ -87,7 +106,8 @@ func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, maxQue
 		return ErrStopped
 	}
 
-	queue := q.queues.getOrAddQueue(tenant, path, maxQueriers)
+	queues := q.queues[priorityOf(req)]
+	queue := queues.getOrAddQueue(tenant, path, maxQueriers, maxConcurrentPerActor)
 	if queue == nil {
 		// This can only happen if tenant is "".
 		return errors.New("no queue found")
@@ -99,11 +119,11 @@ func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, maxQue
 	// We need to keep track of queue length separately because the size of the
 	// buffered channel is the same across all sub-queues which would allow
 	// enqueuing more items than there are allowed at tenant level.
-	queueLen := q.queues.perUserQueueLen.Inc(tenant)
-	if queueLen > q.queues.maxUserQueueSize {
+	queueLen := q.queueLen.Inc(tenant)
+	if queueLen > q.maxOutstandingPerTenant {
 		q.metrics.discardedRequests.WithLabelValues(tenant).Inc()
 		// decrement, because we already optimistically increased the counter
-		q.queues.perUserQueueLen.Dec(tenant)
+		q.queueLen.Dec(tenant)
 		return ErrTooManyRequests
 	}
 
@@ -120,7 +140,7 @@ func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, maxQue
 	default:
 		q.metrics.discardedRequests.WithLabelValues(tenant).Inc()
 		// decrement, because we already optimistically increased the counter
-		q.queues.perUserQueueLen.Dec(tenant)
+		q.queueLen.Dec(tenant)
 		return ErrTooManyRequests
 	}
 }
@@ -128,6 +148,9 @@ func (q *RequestQueue) Enqueue(tenant string, path []string, req Request, maxQue
 // Dequeue find next tenant queue and takes the next request off of it. Will block if there are no requests.
 // By passing tenant index from previous call of this method, querier guarantees that it iterates over all tenants fairly.
 // If querier finds that request from the tenant is already expired, it can get a request for the same tenant by using UserIndex.ReuseLastUser.
+// The priority class is chosen by weighted round-robin over the classes with pending requests. Each class has its own
+// order of tenants, so the queue keeps the index of the querier in each class: the index passed by the querier applies
+// to the class of the request it dequeued last.
 func (q *RequestQueue) Dequeue(ctx context.Context, last QueueIndex, querierID string) (Request, QueueIndex, error) {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
@@ -136,7 +159,7 @@ func (q *RequestQueue) Dequeue(ctx context.Context, last QueueIndex, querierID s
 
 FindQueue:
 	// We need to wait if there are no tenants, or no pending requests for given querier.
-	for (q.queues.hasTenantQueues() || querierWait) && ctx.Err() == nil && !q.stopped {
+	for (!q.hasPendingRequests() || querierWait) && ctx.Err() == nil && !q.stopped {
 		querierWait = false
 		q.cond.Wait(ctx)
 	}
@@ -149,27 +172,46 @@ FindQueue:
 		return nil, last, err
 	}
 
-	for {
-		queue, tenant, idx := q.queues.getNextQueueForQuerier(last, querierID)
-		last = idx
-		if queue == nil {
-			break
-		}
+	cursor := q.cursor(querierID)
+	cursor.last[cursor.priority] = last
+
+	candidates := q.priorities.candidates(int(q.connectedQuerierWorkers.Load()), func(p Priority) bool {
+		return !q.queues[p].hasTenantQueues()
+	})
+	for _, priority := range candidates {
+		queues := q.queues[priority]
+		idx := cursor.last[priority]
+
+		// Each tenant is visited at most once, because a tenant queue may hold requests
+		// that cannot be dequeued while its actors are at their concurrency limit.
+		maxIters := queues.mapping.Len() + 1
+		for iters := 0; iters < maxIters; iters++ {
+			queue, tenant, nextIdx := queues.getNextQueueForQuerier(idx, querierID)
+			idx = nextIdx
+			if queue == nil {
+				break
+			}
 
-		// Pick next request from the queue.
-		for {
+			// Pick next request from the queue.
 			request := queue.Dequeue()
+			if request == nil {
+				continue
+			}
 			if queue.Len() == 0 {
-				q.queues.deleteQueue(tenant)
+				queues.deleteQueue(tenant)
 			}
 
-			q.queues.perUserQueueLen.Dec(tenant)
+			q.queueLen.Dec(tenant)
+			q.priorities.dequeued(priority, candidates)
+			cursor.priority = priority
+			cursor.last[priority] = idx
 			q.metrics.queueLength.WithLabelValues(tenant).Dec()
+			q.metrics.dequeueCount.WithLabelValues(priority.String()).Inc()
 
 			// Tell close() we've processed a request.
 			q.cond.Broadcast()
 
-			return request, last, nil
+			return request, idx, nil
 		}
 	}
 
@@ -179,11 +221,71 @@ FindQueue:
 	goto FindQueue
 }
 
+// querierCursor is the position of a querier in the tenant order of each priority class.
+type querierCursor struct {
+	// The class of the request the querier dequeued last.
+	priority Priority
+	last     [numPriorities]QueueIndex
+}
+
+func (q *RequestQueue) cursor(querierID string) *querierCursor {
+	c, ok := q.cursors[querierID]
+	if !ok {
+		c = &querierCursor{}
+		for p := range c.last {
+			c.last[p] = StartIndex
+		}
+		q.cursors[querierID] = c
+	}
+	return c
+}
+
+// forgetCursors removes the cursors of the queriers that are no longer registered.
+func (q *RequestQueue) forgetCursors() {
+	for querierID := range q.cursors {
+		// All the priority classes track the same queriers.
+		if _, ok := q.queues[PriorityInteractive].queriers[querierID]; !ok {
+			delete(q.cursors, querierID)
+		}
+	}
+}
+
+// ReleaseRequest marks a request that was enqueued with the given path and
+// returned by Dequeue as done, which allows further requests of the same query user
+// and priority class to be dequeued.
+func (q *RequestQueue) ReleaseRequest(tenant string, path []string, req Request) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	priority := priorityOf(req)
+	q.priorities.released(priority)
+	if len(path) > 0 && isQueryUserLevel(req, path[0]) {
+		q.queues[priority].releaseActorRequest(tenant, path[0])
+	}
+	// Wake up queriers that may wait for the query user's or class' requests.
+	q.cond.Broadcast()
+}
+
+// hasPendingRequests returns whether any priority class has queued requests.
+func (q *RequestQueue) hasPendingRequests() bool {
+	for _, queues := range q.queues {
+		if !queues.hasTenantQueues() {
+			return true
+		}
+	}
+	return false
+}
+
 func (q *RequestQueue) forgetDisconnectedQueriers(_ context.Context) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
-	if q.queues.forgetDisconnectedQueriers(time.Now()) > 0 {
+	forgotten := 0
+	for _, queues := range q.queues {
+		forgotten += queues.forgetDisconnectedQueriers(time.Now())
+	}
+	if forgotten > 0 {
+		q.forgetCursors()
 		// We need to notify goroutines cause having removed some queriers
 		// may have caused a resharding.
 		q.cond.Broadcast()
@@ -196,7 +298,7 @@ func (q *RequestQueue) stopping(_ error) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
-	for !q.queues.hasTenantQueues() && q.connectedQuerierWorkers.Load() > 0 {
+	for q.hasPendingRequests() && q.connectedQuerierWorkers.Load() > 0 {
 		q.cond.Wait(context.Background())
 	}
 
@@ -214,7 +316,9 @@ func (q *RequestQueue) RegisterQuerierConnection(querier string) {
 
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
-	q.queues.addQuerierConnection(querier)
+	for _, queues := range q.queues {
+		queues.addQuerierConnection(querier)
+	}
 }
 
 func (q *RequestQueue) UnregisterQuerierConnection(querier string) {
@@ -222,13 +326,19 @@ func (q *RequestQueue) UnregisterQuerierConnection(querier string) {
 
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
-	q.queues.removeQuerierConnection(querier, time.Now())
+	for _, queues := range q.queues {
+		queues.removeQuerierConnection(querier, time.Now())
+	}
+	q.forgetCursors()
 }
 
 func (q *RequestQueue) NotifyQuerierShutdown(querierID string) {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
-	q.queues.notifyQuerierShutdown(querierID)
+	for _, queues := range q.queues {
+		queues.notifyQuerierShutdown(querierID)
+	}
+	q.forgetCursors()
 }
 
 func (q *RequestQueue) GetConnectedQuerierWorkersMetric() float64 {
@@ -45,7 +45,7 @@ func BenchmarkGetNextRequest(b *testing.B) {
 
 			queues := make([]*RequestQueue, 0, b.N)
 			for n := 0; n < b.N; n++ {
-				queue := NewRequestQueue(maxOutstandingPerTenant, 0, NewMetrics("query_scheduler", nil))
+				queue := NewRequestQueue(maxOutstandingPerTenant, 0, PriorityConfig{}, NewMetrics("query_scheduler", nil))
 				queues = append(queues, queue)
 
 				for ix := 0; ix < queriers; ix++ {
@@ -55,7 +55,7 @@ func BenchmarkGetNextRequest(b *testing.B) {
 				for i := 0; i < maxOutstandingPerTenant; i++ {
 					for j := 0; j < numTenants; j++ {
 						userID := strconv.Itoa(j)
-						err := queue.Enqueue(userID, benchCase.fn(j), "request", 0, nil)
+						err := queue.Enqueue(userID, benchCase.fn(j), "request", 0, 0, nil)
 						if err != nil {
 							b.Fatal(err)
 						}
@@ -103,7 +103,7 @@ func BenchmarkQueueRequest(b *testing.B) {
 	requests := make([]string, 0, numTenants)
 
 	for n := 0; n < b.N; n++ {
-		q := NewRequestQueue(maxOutstandingPerTenant, 0, NewMetrics("query_scheduler", nil))
+		q := NewRequestQueue(maxOutstandingPerTenant, 0, PriorityConfig{}, NewMetrics("query_scheduler", nil))
 
 		for ix := 0; ix < queriers; ix++ {
 			q.RegisterQuerierConnection(fmt.Sprintf("querier-%d", ix))
@@ -121,7 +121,7 @@ func BenchmarkQueueRequest(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		for i := 0; i < maxOutstandingPerTenant; i++ {
 			for j := 0; j < numTenants; j++ {
-				err := queues[n].Enqueue(users[j], nil, requests[j], 0, nil)
+				err := queues[n].Enqueue(users[j], nil, requests[j], 0, 0, nil)
 				if err != nil {
 					b.Fatal(err)
 				}
@@ -133,7 +133,7 @@ func BenchmarkQueueRequest(b *testing.B) {
 func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBecauseQuerierHasBeenForgotten(t *testing.T) {
 	const forgetDelay = 3 * time.Second
 
-	queue := NewRequestQueue(1, forgetDelay, NewMetrics("query_scheduler", nil))
+	queue := NewRequestQueue(1, forgetDelay, PriorityConfig{}, NewMetrics("query_scheduler", nil))
 
 	// Start the queue service.
 	ctx := context.Background()
@@ -160,7 +160,7 @@ func TestRequestQueue_GetNextRequestForQuerier_ShouldGetRequestAfterReshardingBe
 
 	// Enqueue a request from an user which would be assigned to querier-1.
 	// NOTE: "user-1" hash falls in the querier-1 shard.
-	require.NoError(t, queue.Enqueue("user-1", nil, "request", 1, nil))
+	require.NoError(t, queue.Enqueue("user-1", nil, "request", 1, 0, nil))
 
 	startTime := time.Now()
 	querier2wg.Wait()
@@ -304,17 +304,17 @@ func TestContextCond(t *testing.T) {
 func TestMaxQueueSize(t *testing.T) {
 	t.Run("queue size is tracked per tenant", func(t *testing.T) {
 		maxSize := 3
-		queue := NewRequestQueue(maxSize, 0, NewMetrics("query_scheduler", nil))
+		queue := NewRequestQueue(maxSize, 0, PriorityConfig{}, NewMetrics("query_scheduler", nil))
 		queue.RegisterQuerierConnection("querier")
 
 		// enqueue maxSize items with different actors
 		// different actors have individual channels with maxSize length
-		assert.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, 1, 0, nil))
-		assert.NoError(t, queue.Enqueue("tenant", []string{"user-b"}, 2, 0, nil))
-		assert.NoError(t, queue.Enqueue("tenant", []string{"user-c"}, 3, 0, nil))
+		assert.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, 1, 0, 0, nil))
+		assert.NoError(t, queue.Enqueue("tenant", []string{"user-b"}, 2, 0, 0, nil))
+		assert.NoError(t, queue.Enqueue("tenant", []string{"user-c"}, 3, 0, 0, nil))
 
 		// max queue length per tenant is tracked globally for all actors within a tenant
-		err := queue.Enqueue("tenant", []string{"user-a"}, 4, 0, nil)
+		err := queue.Enqueue("tenant", []string{"user-a"}, 4, 0, 0, nil)
 		assert.Equal(t, err, ErrTooManyRequests)
 
 		// dequeue and enqueue some items
@@ -323,14 +323,196 @@ func TestMaxQueueSize(t *testing.T) {
 		_, _, err = queue.Dequeue(context.Background(), StartIndexWithLocalQueue, "querier")
 		assert.NoError(t, err)
 
-		assert.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, 4, 0, nil))
-		assert.NoError(t, queue.Enqueue("tenant", []string{"user-b"}, 5, 0, nil))
+		assert.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, 4, 0, 0, nil))
+		assert.NoError(t, queue.Enqueue("tenant", []string{"user-b"}, 5, 0, 0, nil))
 
-		err = queue.Enqueue("tenant", []string{"user-c"}, 6, 0, nil)
+		err = queue.Enqueue("tenant", []string{"user-c"}, 6, 0, 0, nil)
 		assert.Equal(t, err, ErrTooManyRequests)
 	})
 }
 
+type queryUserRequest struct {
+	id   int
+	user string
+}
+
+func (r queryUserRequest) QueryUser() string {
+	return r.user
+}
+
+func TestMaxConcurrentPerActor(t *testing.T) {
+	queue := NewRequestQueue(10, 0, PriorityConfig{}, NewMetrics("query_scheduler", nil))
+	queue.RegisterQuerierConnection("querier")
+
+	r1 := queryUserRequest{id: 1, user: "user-a"}
+	assert.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, r1, 0, 1, nil))
+	assert.NoError(t, queue.Enqueue("tenant", []string{"user-a"}, queryUserRequest{id: 2, user: "user-a"}, 0, 1, nil))
+	assert.NoError(t, queue.Enqueue("tenant", []string{"user-b"}, queryUserRequest{id: 3, user: "user-b"}, 0, 1, nil))
+
+	idx := StartIndexWithLocalQueue
+	r, idx, err := queue.Dequeue(context.Background(), idx, "querier")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, r.(queryUserRequest).id)
+
+	r, idx, err = queue.Dequeue(context.Background(), idx, "querier")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, r.(queryUserRequest).id)
+
+	// user-a is at its concurrency limit, so dequeuing blocks until the request is released
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, err = queue.Dequeue(ctx, idx, "querier")
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	queue.ReleaseRequest("tenant", []string{"user-a"}, r1)
+
+	r, _, err = queue.Dequeue(context.Background(), idx, "querier")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, r.(queryUserRequest).id)
+}
+
+func TestMaxConcurrentPerActor_PathWithoutQueryUser(t *testing.T) {
+	queue := NewRequestQueue(10, 0, PriorityConfig{}, NewMetrics("query_scheduler", nil))
+	queue.RegisterQuerierConnection("querier")
+
+	// The first level of the path isn't a query user, neither for requests without
+	// a query user nor for requests of a query user that isn't in front of the path.
+	assert.NoError(t, queue.Enqueue("tenant", []string{"dashboard", "panel-1"}, queryUserRequest{id: 1}, 0, 1, nil))
+	assert.NoError(t, queue.Enqueue("tenant", []string{"dashboard", "panel-2"}, queryUserRequest{id: 2}, 0, 1, nil))
+	assert.NoError(t, queue.Enqueue("tenant", []string{"dashboard"}, queryUserRequest{id: 3, user: "user-a"}, 0, 1, nil))
+	assert.NoError(t, queue.Enqueue("tenant", []string{"dashboard"}, 4, 0, 1, nil))
+
+	// None of the requests is limited, although none of them is released.
+	ids := map[int]struct{}{}
+	idx := StartIndexWithLocalQueue
+	for i := 0; i < 4; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		r, nextIdx, err := queue.Dequeue(ctx, idx, "querier")
+		cancel()
+		require.NoError(t, err)
+		idx = nextIdx
+		if ur, ok := r.(queryUserRequest); ok {
+			ids[ur.id] = struct{}{}
+		} else {
+			ids[r.(int)] = struct{}{}
+		}
+	}
+	assert.Len(t, ids, 4)
+	// No request is tracked as in-flight, so the tracker of the tenant is removed with its queue.
+	assert.Nil(t, queue.queues[PriorityInteractive].actors["tenant"])
+}
+
+type prioritizedRequest struct {
+	id       int
+	priority Priority
+}
+
+func (r prioritizedRequest) Priority() Priority {
+	return r.priority
+}
+
+func TestWeightedPriorityClasses(t *testing.T) {
+	queue := NewRequestQueue(10, 0, PriorityConfig{InteractiveWeight: 2, BatchWeight: 1}, NewMetrics("query_scheduler", nil))
+	queue.RegisterQuerierConnection("querier")
+
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, queue.Enqueue("tenant", nil, prioritizedRequest{id: i, priority: PriorityInteractive}, 0, 0, nil))
+		assert.NoError(t, queue.Enqueue("tenant", nil, prioritizedRequest{id: 10 + i, priority: PriorityBatch}, 0, 0, nil))
+	}
+
+	items := make([]int, 0, 8)
+	idx := StartIndexWithLocalQueue
+	for i := 0; i < 8; i++ {
+		r, newIdx, err := queue.Dequeue(context.Background(), idx, "querier")
+		assert.NoError(t, err)
+		idx = newIdx
+		items = append(items, r.(prioritizedRequest).id)
+	}
+	assert.Equal(t, []int{0, 10, 1, 2, 11, 3, 12, 13}, items)
+}
+
+func TestReservedInteractiveCapacity(t *testing.T) {
+	queue := NewRequestQueue(10, 0, PriorityConfig{ReservedInteractiveCapacity: 0.5}, NewMetrics("query_scheduler", nil))
+	queue.RegisterQuerierConnection("querier")
+	queue.RegisterQuerierConnection("querier")
+
+	batch := func(id int) prioritizedRequest { return prioritizedRequest{id: id, priority: PriorityBatch} }
+	assert.NoError(t, queue.Enqueue("tenant", nil, batch(1), 0, 0, nil))
+	assert.NoError(t, queue.Enqueue("tenant", nil, batch(2), 0, 0, nil))
+
+	r, idx, err := queue.Dequeue(context.Background(), StartIndexWithLocalQueue, "querier")
+	assert.NoError(t, err)
+	assert.Equal(t, batch(1), r)
+
+	// the second querier worker is reserved for interactive requests
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, err = queue.Dequeue(ctx, idx, "querier")
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	assert.NoError(t, queue.Enqueue("tenant", nil, prioritizedRequest{id: 3}, 0, 0, nil))
+	r, idx, err = queue.Dequeue(context.Background(), idx, "querier")
+	assert.NoError(t, err)
+	assert.Equal(t, prioritizedRequest{id: 3}, r)
+
+	queue.ReleaseRequest("tenant", nil, batch(1))
+	r, _, err = queue.Dequeue(context.Background(), idx, "querier")
+	assert.NoError(t, err)
+	assert.Equal(t, batch(2), r)
+}
+
+func TestMaxQueueSizeAcrossPriorityClasses(t *testing.T) {
+	queue := NewRequestQueue(2, 0, PriorityConfig{}, NewMetrics("query_scheduler", nil))
+	queue.RegisterQuerierConnection("querier")
+
+	assert.NoError(t, queue.Enqueue("tenant", nil, prioritizedRequest{id: 1, priority: PriorityInteractive}, 0, 0, nil))
+	assert.NoError(t, queue.Enqueue("tenant", nil, prioritizedRequest{id: 2, priority: PriorityBatch}, 0, 0, nil))
+	assert.Equal(t, ErrTooManyRequests, queue.Enqueue("tenant", nil, prioritizedRequest{id: 3, priority: PriorityRuler}, 0, 0, nil))
+	assert.NoError(t, queue.Enqueue("other", nil, prioritizedRequest{id: 4, priority: PriorityRuler}, 0, 0, nil))
+
+	_, _, err := queue.Dequeue(context.Background(), StartIndexWithLocalQueue, "querier")
+	assert.NoError(t, err)
+	assert.NoError(t, queue.Enqueue("tenant", nil, prioritizedRequest{id: 3, priority: PriorityRuler}, 0, 0, nil))
+}
+
+func TestTenantOrderPerPriorityClass(t *testing.T) {
+	queue := NewRequestQueue(10, 0, PriorityConfig{}, NewMetrics("query_scheduler", nil))
+	queue.RegisterQuerierConnection("querier")
+
+	for i, tenant := range []string{"tenant-a", "tenant-b"} {
+		for j := 0; j < 2; j++ {
+			assert.NoError(t, queue.Enqueue(tenant, nil, prioritizedRequest{id: 10*i + j, priority: PriorityInteractive}, 0, 0, nil))
+			assert.NoError(t, queue.Enqueue(tenant, nil, prioritizedRequest{id: 100 + 10*i + j, priority: PriorityBatch}, 0, 0, nil))
+		}
+	}
+
+	items := make([]int, 0, 8)
+	idx := StartIndexWithLocalQueue
+	for i := 0; i < 8; i++ {
+		r, newIdx, err := queue.Dequeue(context.Background(), idx, "querier")
+		assert.NoError(t, err)
+		idx = newIdx
+		items = append(items, r.(prioritizedRequest).id)
+	}
+	// the tenants of each class are dequeued in a round-robin fashion, regardless of the other class
+	assert.Equal(t, []int{0, 100, 10, 110, 1, 101, 11, 111}, items)
+}
+
+func TestReservedInteractiveCapacity_SingleWorker(t *testing.T) {
+	queue := NewRequestQueue(10, 0, PriorityConfig{ReservedInteractiveCapacity: 0.9}, NewMetrics("query_scheduler", nil))
+	queue.RegisterQuerierConnection("querier")
+
+	batch := prioritizedRequest{id: 1, priority: PriorityBatch}
+	assert.NoError(t, queue.Enqueue("tenant", nil, batch, 0, 0, nil))
+
+	// at least one querier worker handles the other classes
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	r, _, err := queue.Dequeue(ctx, StartIndexWithLocalQueue, "querier")
+	assert.NoError(t, err)
+	assert.Equal(t, batch, r)
+}
+
 func assertChanReceived(t *testing.T, c chan struct{}, timeout time.Duration, msg string) {
 	t.Helper()
 
@@ -38,6 +38,47 @@ func (tqs intPointerMap) Dec(key string) int {
 	return *ptr
 }
 
+// QueryUserRequest is implemented by requests that are issued by a query user of a tenant.
+// The query user of such a request is expected to be the first element of its path.
+type QueryUserRequest interface {
+	QueryUser() string
+}
+
+// isQueryUserLevel returns whether the sub-queue with given name holds the requests of the
+// query user of req, i.e. whether the first level of the hierarchy of req is its query user.
+func isQueryUserLevel(req Request, name string) bool {
+	ur, ok := req.(QueryUserRequest)
+	if !ok {
+		return false
+	}
+	user := ur.QueryUser()
+	return user != "" && user == name
+}
+
+// actorConcurrency tracks the in-flight requests of the query users of a tenant,
+// which are the first level sub-queues of the tenant queue. Requests that have
+// no query user in front of their path aren't tracked, nor limited.
+// It implements subQueueLimiter.
+type actorConcurrency struct {
+	// Maximum number of in-flight requests per query user. Zero or negative means unlimited.
+	max      int
+	inflight intPointerMap
+}
+
+func (c *actorConcurrency) canDequeue(name string) bool {
+	if c.max <= 0 {
+		return true
+	}
+	ptr, ok := c.inflight[name]
+	return !ok || *ptr < c.max
+}
+
+func (c *actorConcurrency) dequeued(name string, req Request) {
+	if isQueryUserLevel(req, name) {
+		c.inflight.Inc(name)
+	}
+}
+
 // querier holds information about a querier registered in the queue.
 type querier struct {
 	// Number of active connections.
@@ -56,7 +97,10 @@ type tenantQueues struct {
 	mapping *Mapping[*tenantQueue]
 
 	maxUserQueueSize int
-	perUserQueueLen  intPointerMap
+
+	// Tracks in-flight requests per query user of each tenant. Entries outlive the
+	// tenant queue as long as the tenant has in-flight requests.
+	actors map[string]*actorConcurrency
 
 	// How long to wait before removing a querier which has got disconnected
 	// but hasn't notified about a graceful shutdown.
@@ -102,7 +146,7 @@ func newTenantQueues(maxUserQueueSize int, forgetDelay time.Duration) *tenantQue
 	return &tenantQueues{
 		mapping:          mm,
 		maxUserQueueSize: maxUserQueueSize,
-		perUserQueueLen:  make(intPointerMap),
+		actors:           map[string]*actorConcurrency{},
 		forgetDelay:      forgetDelay,
 		queriers:         map[string]*querier{},
 		sortedQueriers:   nil,
@@ -115,13 +159,30 @@ func (q *tenantQueues) hasTenantQueues() bool {
 
 func (q *tenantQueues) deleteQueue(tenant string) {
 	q.mapping.Remove(tenant)
+	if ac, ok := q.actors[tenant]; ok && len(ac.inflight) == 0 {
+		delete(q.actors, tenant)
+	}
+}
+
+// releaseActorRequest marks an in-flight request of the query user of a tenant as done.
+func (q *tenantQueues) releaseActorRequest(tenant, user string) {
+	ac, ok := q.actors[tenant]
+	if !ok {
+		return
+	}
+	ac.inflight.Dec(user)
+	if len(ac.inflight) == 0 && q.mapping.GetByKey(tenant) == nil {
+		delete(q.actors, tenant)
+	}
 }
 
 // Returns existing or new queue for a tenant.
 // MaxQueriers is used to compute which queriers should handle requests for this tenant.
 // If maxQueriers is <= 0, all queriers can handle this tenant's requests.
 // If maxQueriers has changed since the last call, queriers for this are recomputed.
-func (q *tenantQueues) getOrAddQueue(tenant string, path []string, maxQueriers int) Queue {
+// MaxConcurrentPerActor limits how many requests of each query user of the tenant can be
+// in-flight at the same time. If maxConcurrentPerActor is <= 0, there is no limit.
+func (q *tenantQueues) getOrAddQueue(tenant string, path []string, maxQueriers, maxConcurrentPerActor int) Queue {
 	// Empty tenant is not allowed, as that would break our tenants list ("" is used for free spot).
 	if tenant == "" {
 		return nil
@@ -131,12 +192,20 @@ func (q *tenantQueues) getOrAddQueue(tenant string, path []string, maxQueriers i
 		maxQueriers = 0
 	}
 
+	ac := q.actors[tenant]
+	if ac == nil {
+		ac = &actorConcurrency{inflight: make(intPointerMap)}
+		q.actors[tenant] = ac
+	}
+	ac.max = maxConcurrentPerActor
+
 	uq := q.mapping.GetByKey(tenant)
 	if uq == nil {
 		uq = &tenantQueue{
 			seed: util.ShuffleShardSeed(tenant, ""),
 		}
 		uq.TreeQueue = newTreeQueue(q.maxUserQueueSize, tenant)
+		uq.TreeQueue.limiter = ac
 		q.mapping.Put(tenant, uq)
 	}
 
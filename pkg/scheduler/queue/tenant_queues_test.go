@@ -196,7 +196,7 @@ func TestQueuesConsistency(t *testing.T) {
 			for i := 0; i < 10000; i++ {
 				switch r.Int() % 6 {
 				case 0:
-					assert.NotNil(t, uq.getOrAddQueue(generateTenant(r), generateActor(r), 3))
+					assert.NotNil(t, uq.getOrAddQueue(generateTenant(r), generateActor(r), 3, 0))
 				case 1:
 					qid := generateQuerier(r)
 					_, _, luid := uq.getNextQueueForQuerier(lastUserIndexes[qid], qid)
@@ -403,10 +403,10 @@ func generateQuerier(r *rand.Rand) string {
 
 func getOrAdd(t *testing.T, uq *tenantQueues, tenant string, maxQueriers int) Queue {
 	actor := []string{}
-	q := uq.getOrAddQueue(tenant, actor, maxQueriers)
+	q := uq.getOrAddQueue(tenant, actor, maxQueriers, 0)
 	assert.NotNil(t, q)
 	assert.NoError(t, isConsistent(uq))
-	assert.Equal(t, q, uq.getOrAddQueue(tenant, actor, maxQueriers))
+	assert.Equal(t, q, uq.getOrAddQueue(tenant, actor, maxQueriers, 0))
 	return q
 }
 
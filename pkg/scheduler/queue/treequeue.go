@@ -7,6 +7,15 @@ import (
 
 type QueuePath []string //nolint:revive
 
+// subQueueLimiter restricts how many requests of a direct sub-queue can be
+// dequeued until they are released again.
+type subQueueLimiter interface {
+	// canDequeue returns whether another request can be dequeued from the sub-queue with given name.
+	canDequeue(name string) bool
+	// dequeued is called after req was dequeued from the sub-queue with given name.
+	dequeued(name string, req Request)
+}
+
 // TreeQueue is an hierarchical queue implementation where each sub-queue
 // has the same guarantees to be chosen from.
 // Each queue has also a local queue, which gets chosen with equal preference as the sub-queues.
@@ -23,6 +32,8 @@ type TreeQueue struct {
 	name string
 	// maximum queue size of the local queue
 	size int
+	// optional limiter for the direct sub-queues
+	limiter subQueueLimiter
 }
 
 // newTreeQueue creates a new TreeQueue instance
@@ -98,8 +109,14 @@ func (q *TreeQueue) Dequeue() Request {
 		}
 		if subq != nil {
 			q.current = subq.pos
+			if q.limiter != nil && !q.limiter.canDequeue(subq.name) {
+				continue
+			}
 			item := subq.Dequeue()
 			if item != nil {
+				if q.limiter != nil {
+					q.limiter.dequeued(subq.name, item)
+				}
 				if subq.Len() == 0 {
 					q.mapping.Remove(subq.name)
 				}
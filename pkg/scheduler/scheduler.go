@@ -121,10 +121,11 @@ type connectedFrontend struct {
 }
 
 type Config struct {
-	MaxOutstandingPerTenant int               `yaml:"max_outstanding_requests_per_tenant"`
-	MaxQueueHierarchyLevels int               `yaml:"max_queue_hierarchy_levels"`
-	QuerierForgetDelay      time.Duration     `yaml:"querier_forget_delay"`
-	GRPCClientConfig        grpcclient.Config `yaml:"grpc_client_config" doc:"description=This configures the gRPC client used to report errors back to the query-frontend."`
+	MaxOutstandingPerTenant int                  `yaml:"max_outstanding_requests_per_tenant"`
+	MaxQueueHierarchyLevels int                  `yaml:"max_queue_hierarchy_levels"`
+	QuerierForgetDelay      time.Duration        `yaml:"querier_forget_delay"`
+	PriorityClasses         queue.PriorityConfig `yaml:"priority_classes" doc:"description=Configures how queries of the priority classes interactive, batch and ruler are dequeued. The priority class is selected by the X-Loki-Query-Priority header. Queries issued by the ruler are of class ruler, all other queries are of class interactive by default."`
+	GRPCClientConfig        grpcclient.Config    `yaml:"grpc_client_config" doc:"description=This configures the gRPC client used to report errors back to the query-frontend."`
 	// Schedulers ring
 	UseSchedulerRing bool            `yaml:"use_scheduler_ring"`
 	SchedulerRing    util.RingConfig `yaml:"scheduler_ring,omitempty" doc:"description=The hash ring configuration. This option is required only if use_scheduler_ring is true."`
@@ -132,13 +133,22 @@ type Config struct {
 
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.IntVar(&cfg.MaxOutstandingPerTenant, "query-scheduler.max-outstanding-requests-per-tenant", 100, "Maximum number of outstanding requests per tenant per query-scheduler. In-flight requests above this limit will fail with HTTP response status code 429.")
-	f.IntVar(&cfg.MaxQueueHierarchyLevels, "query-scheduler.max-queue-hierarchy-levels", 3, "Maximum number of levels of nesting of hierarchical queues. 0 means that hierarchical queues are disabled.")
+	f.IntVar(&cfg.MaxQueueHierarchyLevels, "query-scheduler.max-queue-hierarchy-levels", 3, "Maximum number of levels of nesting of hierarchical queues. The level of the query user isn't counted. 0 means that hierarchical queues are disabled.")
 	f.DurationVar(&cfg.QuerierForgetDelay, "query-scheduler.querier-forget-delay", 0, "If a querier disconnects without sending notification about graceful shutdown, the query-scheduler will keep the querier in the tenant's shard until the forget delay has passed. This feature is useful to reduce the blast radius when shuffle-sharding is enabled.")
+	cfg.PriorityClasses.RegisterFlagsWithPrefix("query-scheduler.priority-classes.", f)
 	cfg.GRPCClientConfig.RegisterFlagsWithPrefix("query-scheduler.grpc-client-config", f)
 	f.BoolVar(&cfg.UseSchedulerRing, "query-scheduler.use-scheduler-ring", false, "Set to true to have the query schedulers create and place themselves in a ring. If no frontend_address or scheduler_address are present anywhere else in the configuration, Loki will toggle this value to true.")
 	cfg.SchedulerRing.RegisterFlagsWithPrefix("query-scheduler.", "collectors/", f)
 }
 
+// Validate validates the query-scheduler config.
+func (cfg *Config) Validate() error {
+	if err := cfg.PriorityClasses.Validate(); err != nil {
+		return errors.Wrap(err, "invalid priority classes config")
+	}
+	return nil
+}
+
 // NewScheduler creates a new Scheduler.
 func NewScheduler(cfg Config, limits Limits, log log.Logger, registerer prometheus.Registerer) (*Scheduler, error) {
 	queueMetrics := queue.NewMetrics("query_scheduler", registerer)
@@ -151,7 +161,7 @@ func NewScheduler(cfg Config, limits Limits, log log.Logger, registerer promethe
 		connectedFrontends: map[string]*connectedFrontend{},
 		queueMetrics:       queueMetrics,
 
-		requestQueue: queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, queueMetrics),
+		requestQueue: queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QuerierForgetDelay, cfg.PriorityClasses, queueMetrics),
 	}
 
 	s.queueDuration = promauto.With(registerer).NewHistogram(prometheus.HistogramOpts{
@@ -239,6 +249,9 @@ func NewScheduler(cfg Config, limits Limits, log log.Logger, registerer promethe
 type Limits interface {
 	// MaxQueriersPerUser returns max queriers to use per tenant, or 0 if shuffle sharding is disabled.
 	MaxQueriersPerUser(user string) int
+
+	// MaxConcurrentRequestsPerQueryUser returns max in-flight requests of a single query user of a tenant, or 0 if unlimited.
+	MaxConcurrentRequestsPerQueryUser(user string) int
 }
 
 type schedulerRequest struct {
@@ -247,6 +260,9 @@ type schedulerRequest struct {
 	queryID         uint64
 	request         *httpgrpc.HTTPRequest
 	statsEnabled    bool
+	queuePath       []string
+	queryUser       string
+	priority        queue.Priority
 
 	queueTime time.Time
 
@@ -258,6 +274,67 @@ type schedulerRequest struct {
 	parentSpanContext opentracing.SpanContext
 }
 
+// Priority implements queue.PrioritizedRequest.
+func (r *schedulerRequest) Priority() queue.Priority {
+	return r.priority
+}
+
+// QueryUser implements queue.QueryUserRequest.
+func (r *schedulerRequest) QueryUser() string {
+	return r.queryUser
+}
+
+// requestPriority returns the priority class of a request. The class is taken
+// from the X-Loki-Query-Priority header if present, otherwise requests issued
+// by the ruler are of class ruler and all other requests are interactive.
+func requestPriority(req *httpgrpc.HTTPRequest) queue.Priority {
+	var tags string
+	for _, h := range req.GetHeaders() {
+		if len(h.Values) == 0 {
+			continue
+		}
+		switch textproto.CanonicalMIMEHeaderKey(h.Key) {
+		case textproto.CanonicalMIMEHeaderKey(lokihttpreq.LokiQueryPriorityHeader):
+			if p, err := queue.ParsePriority(h.Values[0]); err == nil {
+				return p
+			}
+		case textproto.CanonicalMIMEHeaderKey(string(lokihttpreq.QueryTagsHTTPHeader)):
+			tags = h.Values[0]
+		}
+	}
+	if lokihttpreq.IsRulerQuery(tags) {
+		return queue.PriorityRuler
+	}
+	return queue.PriorityInteractive
+}
+
+// requestQueryUser returns the user issuing the request, see httpreq.ExtractQueryUser.
+func requestQueryUser(req *httpgrpc.HTTPRequest) string {
+	var user, tags string
+	for _, h := range req.GetHeaders() {
+		if len(h.Values) == 0 {
+			continue
+		}
+		switch textproto.CanonicalMIMEHeaderKey(h.Key) {
+		case textproto.CanonicalMIMEHeaderKey(lokihttpreq.LokiQueryUserHeader):
+			user = h.Values[0]
+		case textproto.CanonicalMIMEHeaderKey(string(lokihttpreq.QueryTagsHTTPHeader)):
+			tags = h.Values[0]
+		}
+	}
+	return lokihttpreq.QueryUser(user, tags)
+}
+
+// queueHierarchyLevels returns the number of levels of the queue path that count against
+// -query-scheduler.max-queue-hierarchy-levels. The query user the frontend puts in front of the actor path
+// isn't counted, so that it doesn't reduce the depth allowed for the actor path.
+func queueHierarchyLevels(queuePath []string, user string) int {
+	if user != "" && len(queuePath) > 0 && queuePath[0] == user {
+		return len(queuePath) - 1
+	}
+	return len(queuePath)
+}
+
 // FrontendLoop handles connection from frontend.
 func (s *Scheduler) FrontendLoop(frontend schedulerpb.SchedulerForFrontend_FrontendLoopServer) error {
 	frontendAddress, frontendCtx, err := s.frontendConnected(frontend)
@@ -389,6 +466,8 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 		queryID:         msg.QueryID,
 		request:         msg.HttpRequest,
 		statsEnabled:    msg.StatsEnabled,
+		queryUser:       requestQueryUser(msg.HttpRequest),
+		priority:        requestPriority(msg.HttpRequest),
 	}
 
 	now := time.Now()
@@ -404,26 +483,29 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 		return err
 	}
 	maxQueriers := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, s.limits.MaxQueriersPerUser)
+	maxConcurrentPerActor := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, s.limits.MaxConcurrentRequestsPerQueryUser)
 
 	var queuePath []string
 	if s.cfg.MaxQueueHierarchyLevels > 0 {
 		queuePath = msg.QueuePath
-		if len(queuePath) > s.cfg.MaxQueueHierarchyLevels {
+		if levels := queueHierarchyLevels(queuePath, req.queryUser); levels > s.cfg.MaxQueueHierarchyLevels {
 			msg := fmt.Sprintf(
 				"The header %s with value '%s' would result in a sub-queue which is "+
 					"nested %d levels deep, however only %d levels are allowed based on the "+
 					"configuration setting -query-scheduler.max-queue-hierarchy-levels",
 				lokihttpreq.LokiActorPathHeader,
-				strings.Join(queuePath, lokihttpreq.LokiActorPathDelimiter),
-				len(queuePath),
+				strings.Join(queuePath[len(queuePath)-levels:], lokihttpreq.LokiActorPathDelimiter),
+				levels,
 				s.cfg.MaxQueueHierarchyLevels,
 			)
 			return fmt.Errorf("desired queue level exceeds maxium depth of queue hierarchy: %s", msg)
 		}
 	}
 
+	req.queuePath = queuePath
+
 	s.activeUsers.UpdateUserTimestamp(req.tenantID, now)
-	return s.requestQueue.Enqueue(req.tenantID, queuePath, req, maxQueriers, func() {
+	return s.requestQueue.Enqueue(req.tenantID, queuePath, req, maxQueriers, maxConcurrentPerActor, func() {
 		shouldCancel = false
 
 		s.pendingRequestsMu.Lock()
@@ -499,12 +581,15 @@ func (s *Scheduler) QuerierLoop(querier schedulerpb.SchedulerForQuerier_QuerierL
 		if r.ctx.Err() != nil {
 			// Remove from pending requests.
 			s.cancelRequestAndRemoveFromPending(r.frontendAddress, r.queryID)
+			s.requestQueue.ReleaseRequest(r.tenantID, r.queuePath, r)
 
 			lastIndex = lastIndex.ReuseLastIndex()
 			continue
 		}
 
-		if err := s.forwardRequestToQuerier(querier, r); err != nil {
+		err = s.forwardRequestToQuerier(querier, r)
+		s.requestQueue.ReleaseRequest(r.tenantID, r.queuePath, r)
+		if err != nil {
 			return err
 		}
 	}
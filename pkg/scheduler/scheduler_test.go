@@ -8,8 +8,11 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/httpgrpc"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/grafana/loki/pkg/scheduler/queue"
 	"github.com/grafana/loki/pkg/scheduler/schedulerpb"
 	util_log "github.com/grafana/loki/pkg/util/log"
 )
@@ -99,3 +102,102 @@ func (m mockSchedulerForFrontendFrontendLoopServer) SendMsg(msg interface{}) err
 func (m mockSchedulerForFrontendFrontendLoopServer) RecvMsg(msg interface{}) error {
 	panic("implement me")
 }
+
+func TestRequestPriority(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		headers []*httpgrpc.Header
+		exp     queue.Priority
+	}{
+		{
+			name: "no headers",
+			exp:  queue.PriorityInteractive,
+		},
+		{
+			name:    "priority header",
+			headers: []*httpgrpc.Header{{Key: "X-Loki-Query-Priority", Values: []string{"batch"}}},
+			exp:     queue.PriorityBatch,
+		},
+		{
+			name:    "invalid priority header",
+			headers: []*httpgrpc.Header{{Key: "X-Loki-Query-Priority", Values: []string{"urgent"}}},
+			exp:     queue.PriorityInteractive,
+		},
+		{
+			name:    "ruler query tag",
+			headers: []*httpgrpc.Header{{Key: "X-Query-Tags", Values: []string{"ruler"}}},
+			exp:     queue.PriorityRuler,
+		},
+		{
+			name: "priority header takes precedence over query tags",
+			headers: []*httpgrpc.Header{
+				{Key: "X-Query-Tags", Values: []string{"source=ruler"}},
+				{Key: "X-Loki-Query-Priority", Values: []string{"interactive"}},
+			},
+			exp: queue.PriorityInteractive,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.exp, requestPriority(&httpgrpc.HTTPRequest{Headers: tc.headers}))
+		})
+	}
+}
+
+type fakeLimits struct{}
+
+func (fakeLimits) MaxQueriersPerUser(_ string) int { return 0 }
+
+func (fakeLimits) MaxConcurrentRequestsPerQueryUser(_ string) int { return 0 }
+
+func TestScheduler_enqueueRequest_MaxQueueHierarchyLevels(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		queuePath []string
+		headers   []*httpgrpc.Header
+		expErr    bool
+	}{
+		{
+			name:      "full depth actor path",
+			queuePath: []string{"a", "b", "c"},
+		},
+		{
+			name:      "full depth actor path of a query user",
+			queuePath: []string{"alice", "a", "b", "c"},
+			headers:   []*httpgrpc.Header{{Key: "X-Loki-User", Values: []string{"alice"}}},
+		},
+		{
+			name:      "full depth actor path of a query user from query tags",
+			queuePath: []string{"bob", "a", "b", "c"},
+			headers:   []*httpgrpc.Header{{Key: "X-Query-Tags", Values: []string{"user=bob"}}},
+		},
+		{
+			name:      "actor path too deep",
+			queuePath: []string{"a", "b", "c", "d"},
+			expErr:    true,
+		},
+		{
+			name:      "actor path of a query user too deep",
+			queuePath: []string{"alice", "a", "b", "c", "d"},
+			headers:   []*httpgrpc.Header{{Key: "X-Loki-User", Values: []string{"alice"}}},
+			expErr:    true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{MaxOutstandingPerTenant: 10, MaxQueueHierarchyLevels: 3}
+			s, err := NewScheduler(cfg, fakeLimits{}, util_log.Logger, prometheus.NewRegistry())
+			require.NoError(t, err)
+
+			err = s.enqueueRequest(context.Background(), "frontend", &schedulerpb.FrontendToScheduler{
+				UserID:      "tenant",
+				QueryID:     1,
+				HttpRequest: &httpgrpc.HTTPRequest{Headers: tc.headers},
+				QueuePath:   tc.queuePath,
+			})
+			if tc.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package tsdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/storage/config"
+	"github.com/grafana/loki/pkg/storage/stores/indexshipper/compactor/retention"
+	shipper_storage "github.com/grafana/loki/pkg/storage/stores/indexshipper/storage"
+	"github.com/grafana/loki/pkg/storage/stores/tsdb/index"
+	"github.com/grafana/loki/pkg/util"
+)
+
+// BackfillConfig controls how the Backfiller cuts chunks and where it builds index files.
+type BackfillConfig struct {
+	Encoding        chunkenc.Encoding
+	BlockSize       int
+	TargetChunkSize int
+	// WorkingDir is used to build the index files before they are uploaded.
+	WorkingDir string
+}
+
+// StreamValidator validates the streams to backfill with the limits of their tenant.
+// It returns the labels and the valid entries of a stream, and an error describing why entries were dropped.
+type StreamValidator interface {
+	ValidateBackfillStream(now time.Time, tenant string, stream logproto.Stream) (labels.Labels, []logproto.Entry, error)
+}
+
+// Backfiller writes historical streams directly to storage, bypassing the ingesters.
+// Streams are validated first, invalid entries and entries already out of the retention period of their stream are
+// dropped. The entries of every stream are cut into chunks which are written with the chunk client of their schema period,
+// then a per tenant TSDB index referencing them is uploaded for each table, the same way the compactor uploads its
+// compacted indexes. The data becomes queryable once queriers and index gateways sync the new index files.
+type Backfiller struct {
+	cfg          BackfillConfig
+	schemaCfg    config.SchemaConfig
+	chunkClients []client.Client
+	indexClient  shipper_storage.Client
+	validator    StreamValidator
+	retention    *retention.TenantsRetention
+	logger       log.Logger
+}
+
+// NewBackfiller creates a Backfiller. chunkClients must hold one chunk client per period of schemaCfg, in the same order.
+// retentionLimits may be nil when retention is disabled, in which case entries of any age are written.
+func NewBackfiller(cfg BackfillConfig, schemaCfg config.SchemaConfig, chunkClients []client.Client, indexClient shipper_storage.Client, validator StreamValidator, retentionLimits retention.Limits, logger log.Logger) (*Backfiller, error) {
+	if len(chunkClients) != len(schemaCfg.Configs) {
+		return nil, fmt.Errorf("expected %d chunk clients, one per schema period, got %d", len(schemaCfg.Configs), len(chunkClients))
+	}
+
+	b := &Backfiller{
+		cfg:          cfg,
+		schemaCfg:    schemaCfg,
+		chunkClients: chunkClients,
+		indexClient:  indexClient,
+		validator:    validator,
+		logger:       logger,
+	}
+	if retentionLimits != nil {
+		b.retention = retention.NewTenantsRetention(retentionLimits)
+	}
+	return b, nil
+}
+
+// Backfill writes the given streams of a tenant to storage.
+// All the chunks are written before any index file is uploaded so that an index never references a missing chunk.
+func (b *Backfiller) Backfill(ctx context.Context, tenant string, streams []logproto.Stream) error {
+	builders := map[string]*Builder{}
+	chunks := make([][]chunk.Chunk, len(b.schemaCfg.Configs))
+
+	now := time.Now()
+	var entries, dropped, expired int
+	for _, s := range streams {
+		ls, valid, err := b.validator.ValidateBackfillStream(now, tenant, s)
+		if err != nil {
+			level.Warn(b.logger).Log("msg", "dropped invalid entries", "tenant", tenant, "stream", s.Labels, "dropped", len(s.Entries)-len(valid), "err", err)
+			dropped += len(s.Entries) - len(valid)
+		}
+
+		n := len(valid)
+		valid = b.dropExpired(now, tenant, ls, valid)
+		expired += n - len(valid)
+		if len(valid) == 0 {
+			continue
+		}
+
+		streamChunks, err := b.cutChunks(tenant, ls, valid)
+		if err != nil {
+			return err
+		}
+
+		for _, c := range streamChunks {
+			chunks[c.period] = append(chunks[c.period], c.chunk)
+
+			builder, ok := builders[c.table]
+			if !ok {
+				builder = NewBuilder()
+				builders[c.table] = builder
+			}
+			builder.AddSeries(ls, model.Fingerprint(c.chunk.Fingerprint), []index.ChunkMeta{{
+				Checksum: c.chunk.Checksum,
+				MinTime:  int64(c.chunk.From),
+				MaxTime:  int64(c.chunk.Through),
+				KB:       uint32(c.chunk.Size()) / (1 << 10),
+				Entries:  uint32(c.chunk.Data.Entries()),
+			}})
+		}
+		entries += len(valid)
+	}
+
+	var chunksCount int
+	for period, periodChunks := range chunks {
+		if len(periodChunks) == 0 {
+			continue
+		}
+		if err := b.chunkClients[period].PutChunks(ctx, periodChunks); err != nil {
+			return fmt.Errorf("put chunks: %w", err)
+		}
+		chunksCount += len(periodChunks)
+	}
+
+	tables := make([]string, 0, len(builders))
+	for table := range builders {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		if err := b.uploadIndex(ctx, table, tenant, builders[table]); err != nil {
+			return fmt.Errorf("upload index for table %s: %w", table, err)
+		}
+	}
+
+	level.Info(b.logger).Log("msg", "backfilled streams", "tenant", tenant, "streams", len(streams), "entries", entries, "dropped", dropped, "expired", expired, "chunks", chunksCount, "tables", len(tables))
+	return nil
+}
+
+// dropExpired drops the entries which are out of the retention period of the stream, the compactor would delete
+// their chunks right away.
+func (b *Backfiller) dropExpired(now time.Time, tenant string, ls labels.Labels, entries []logproto.Entry) []logproto.Entry {
+	if b.retention == nil {
+		return entries
+	}
+	// The 0 value disables retention.
+	period := b.retention.RetentionPeriodFor(tenant, ls)
+	if period <= 0 {
+		return entries
+	}
+
+	cutoff := now.Add(-period)
+	kept := entries[:0]
+	for _, e := range entries {
+		if !e.Timestamp.Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+type backfillChunk struct {
+	period int
+	table  string
+	chunk  chunk.Chunk
+}
+
+// cutChunks cuts the entries of a stream into encoded chunks. A new chunk is cut whenever the current one is full or
+// the entries cross into another index table, so that every chunk is referenced by the index of a single table.
+func (b *Backfiller) cutChunks(tenant string, ls labels.Labels, entries []logproto.Entry) ([]backfillChunk, error) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	headBlockFmt := chunkenc.UnorderedHeadBlockFmt
+	for _, e := range entries {
+		if len(e.StructuredMetadata) > 0 {
+			headBlockFmt = chunkenc.UnorderedWithMetadataHeadBlockFmt
+			break
+		}
+	}
+
+	metricBuilder := labels.NewBuilder(ls)
+	metricBuilder.Set(labels.MetricName, "logs")
+	metric := metricBuilder.Labels(nil)
+	fp := model.Fingerprint(ls.Hash())
+
+	var (
+		result  []backfillChunk
+		current *chunkenc.MemChunk
+		period  int
+		table   string
+	)
+	cut := func() error {
+		if current == nil {
+			return nil
+		}
+		if err := current.Close(); err != nil {
+			return err
+		}
+		from, through := util.RoundToMilliseconds(current.Bounds())
+		c := chunk.NewChunk(tenant, fp, metric, chunkenc.NewFacade(current, b.cfg.BlockSize, b.cfg.TargetChunkSize), from, through)
+		if err := c.Encode(); err != nil {
+			return fmt.Errorf("encode chunk: %w", err)
+		}
+		result = append(result, backfillChunk{period: period, table: table, chunk: c})
+		current = nil
+		return nil
+	}
+
+	for i := range entries {
+		ts := model.TimeFromUnixNano(entries[i].Timestamp.UnixNano())
+		entryPeriod, err := b.periodFor(ts)
+		if err != nil {
+			return nil, err
+		}
+		entryTable := b.schemaCfg.Configs[entryPeriod].IndexTables.TableFor(ts)
+
+		if current != nil && (entryTable != table || !current.SpaceFor(&entries[i])) {
+			if err := cut(); err != nil {
+				return nil, err
+			}
+		}
+		if current == nil {
+			current = chunkenc.NewMemChunk(b.cfg.Encoding, headBlockFmt, b.cfg.BlockSize, b.cfg.TargetChunkSize)
+			period, table = entryPeriod, entryTable
+		}
+		if err := current.Append(&entries[i]); err != nil {
+			return nil, fmt.Errorf("append entry: %w", err)
+		}
+	}
+
+	if err := cut(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// periodFor returns the index of the schema period of the given time, which must use the TSDB index.
+func (b *Backfiller) periodFor(t model.Time) (int, error) {
+	for i := len(b.schemaCfg.Configs) - 1; i >= 0; i-- {
+		if t < b.schemaCfg.Configs[i].From.Time {
+			continue
+		}
+		if b.schemaCfg.Configs[i].IndexType != config.TSDBType {
+			return 0, fmt.Errorf("can't backfill entries at %v: schema period starting at %s uses the %s index, only %s is supported",
+				t.Time().UTC(), b.schemaCfg.Configs[i].From, b.schemaCfg.Configs[i].IndexType, config.TSDBType)
+		}
+		return i, nil
+	}
+	return 0, fmt.Errorf("no schema config found for time %v", t.Time().UTC())
+}
+
+// uploadIndex builds the index of a table and uploads it compressed as a per tenant index file.
+func (b *Backfiller) uploadIndex(ctx context.Context, table, tenant string, builder *Builder) error {
+	dir := filepath.Join(b.cfg.WorkingDir, table, tenant)
+	id, err := builder.Build(ctx, dir, func(from, through model.Time, checksum uint32) Identifier {
+		id := SingleTenantTSDBIdentifier{
+			TS:       time.Now(),
+			From:     from,
+			Through:  through,
+			Checksum: checksum,
+		}
+		return newPrefixedIdentifier(id, dir, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := os.Remove(id.Path()); err != nil {
+			level.Error(b.logger).Log("msg", "failed to remove index file", "path", id.Path(), "err", err)
+		}
+	}()
+
+	compressedPath := fmt.Sprintf("%s.gz", id.Path())
+	f, err := os.Create(compressedPath)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			level.Error(b.logger).Log("msg", "failed to close compressed index file", "path", compressedPath, "err", err)
+		}
+		if err := os.Remove(compressedPath); err != nil {
+			level.Error(b.logger).Log("msg", "failed to remove compressed index file", "path", compressedPath, "err", err)
+		}
+	}()
+
+	if err := compressFile(id.Path(), f); err != nil {
+		return err
+	}
+
+	if _, err := f.Seek(0, 0); err != nil {
+		return err
+	}
+
+	return b.indexClient.PutUserFile(ctx, table, tenant, fmt.Sprintf("%s.gz", id.Name()), f)
+}
+
+func compressFile(src string, dst *os.File) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	compressedWriter := chunkenc.Gzip.GetWriter(dst)
+	defer chunkenc.Gzip.PutWriter(compressedWriter)
+
+	if _, err := io.Copy(compressedWriter, in); err != nil {
+		return err
+	}
+	if err := compressedWriter.Close(); err != nil {
+		return err
+	}
+
+	// flush the file to disk before it is read back for the upload.
+	return dst.Sync()
+}
@@ -0,0 +1,218 @@
+package tsdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/loki/pkg/chunkenc"
+	"github.com/grafana/loki/pkg/logproto"
+	"github.com/grafana/loki/pkg/logql/log"
+	"github.com/grafana/loki/pkg/logql/syntax"
+	"github.com/grafana/loki/pkg/storage/chunk"
+	"github.com/grafana/loki/pkg/storage/chunk/client"
+	"github.com/grafana/loki/pkg/storage/chunk/client/local"
+	"github.com/grafana/loki/pkg/storage/config"
+	shipper_storage "github.com/grafana/loki/pkg/storage/stores/indexshipper/storage"
+	util_log "github.com/grafana/loki/pkg/util/log"
+	"github.com/grafana/loki/pkg/validation"
+)
+
+// fakeStreamValidator drops the entries whose line is "invalid".
+type fakeStreamValidator struct{}
+
+func (fakeStreamValidator) ValidateBackfillStream(_ time.Time, _ string, stream logproto.Stream) (labels.Labels, []logproto.Entry, error) {
+	ls, err := syntax.ParseLabels(stream.Labels)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		entries []logproto.Entry
+		lastErr error
+	)
+	for _, e := range stream.Entries {
+		if e.Line == "invalid" {
+			lastErr = fmt.Errorf("invalid entry at %v", e.Timestamp)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return ls, entries, lastErr
+}
+
+func TestBackfiller(t *testing.T) {
+	dir := t.TempDir()
+	objectClient, err := local.NewFSObjectClient(local.FSConfig{Directory: filepath.Join(dir, "storage")})
+	require.NoError(t, err)
+
+	schemaCfg := config.SchemaConfig{
+		Configs: []config.PeriodConfig{
+			{
+				From:       config.DayTime{Time: model.Time(0)},
+				IndexType:  config.BoltDBShipperType,
+				ObjectType: config.StorageTypeFileSystem,
+				Schema:     "v11",
+				IndexTables: config.PeriodicTableConfig{
+					Prefix: "index_",
+					Period: 24 * time.Hour,
+				},
+			},
+			{
+				From:       config.DayTime{Time: model.TimeFromUnix(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC).Unix())},
+				IndexType:  config.TSDBType,
+				ObjectType: config.StorageTypeFileSystem,
+				Schema:     "v12",
+				IndexTables: config.PeriodicTableConfig{
+					Prefix: "index_",
+					Period: 24 * time.Hour,
+				},
+			},
+		},
+	}
+	chunkClient := client.NewClient(objectClient, client.FSEncoder, schemaCfg)
+	indexClient := shipper_storage.NewIndexStorageClient(objectClient, "index/")
+
+	backfiller, err := NewBackfiller(BackfillConfig{
+		Encoding:        chunkenc.EncSnappy,
+		BlockSize:       256 * 1024,
+		TargetChunkSize: 1500 * 1024,
+		WorkingDir:      filepath.Join(dir, "working"),
+	}, schemaCfg, []client.Client{chunkClient, chunkClient}, indexClient, fakeStreamValidator{}, nil, util_log.Logger)
+	require.NoError(t, err)
+
+	// entries spanning two days end up in two tables, given out of order.
+	start := time.Date(2022, 3, 1, 20, 0, 0, 0, time.UTC)
+	var entries []logproto.Entry
+	for i := 7; i >= 0; i-- {
+		entries = append(entries, logproto.Entry{Timestamp: start.Add(time.Duration(i) * time.Hour), Line: fmt.Sprintf("line %d", i)})
+	}
+	ctx := context.Background()
+	require.NoError(t, backfiller.Backfill(ctx, "fake", []logproto.Stream{{Labels: `{app="foo"}`, Entries: entries}}))
+
+	tables, err := indexClient.ListTables(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"index_19052", "index_19053"}, tables)
+
+	ls := labels.FromStrings("app", "foo")
+	var lines []string
+	for _, table := range tables {
+		files, err := indexClient.ListUserFiles(ctx, table, "fake", true)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+
+		// download the index the way queriers do and resolve the chunks it references.
+		dst := filepath.Join(t.TempDir(), strings.TrimSuffix(files[0].Name, ".gz"))
+		require.NoError(t, shipper_storage.DownloadFileFromStorage(dst, true, false, util_log.Logger, func() (io.ReadCloser, error) {
+			return indexClient.GetUserFile(ctx, table, "fake", files[0].Name)
+		}))
+		idx, err := OpenShippableTSDB(dst)
+		require.NoError(t, err)
+
+		refs, err := idx.(*TSDBFile).GetChunkRefs(ctx, "fake", 0, model.Latest, nil, nil, labels.MustNewMatcher(labels.MatchEqual, "app", "foo"))
+		require.NoError(t, err)
+		require.Len(t, refs, 1)
+		require.Equal(t, model.Fingerprint(ls.Hash()), refs[0].Fingerprint)
+		require.NoError(t, idx.Close())
+
+		chks, err := chunkClient.GetChunks(ctx, []chunk.Chunk{{ChunkRef: logproto.ChunkRef{
+			Fingerprint: uint64(refs[0].Fingerprint),
+			UserID:      "fake",
+			From:        refs[0].Start,
+			Through:     refs[0].End,
+			Checksum:    refs[0].Checksum,
+		}}})
+		require.NoError(t, err)
+		require.Len(t, chks, 1)
+
+		it, err := chks[0].Data.(*chunkenc.Facade).LokiChunk().Iterator(ctx, time.Unix(0, 0), time.Unix(0, math.MaxInt64), logproto.FORWARD, log.NewNoopPipeline().ForStream(ls))
+		require.NoError(t, err)
+		for it.Next() {
+			lines = append(lines, it.Entry().Line)
+		}
+		require.NoError(t, it.Close())
+	}
+	require.Equal(t, []string{"line 0", "line 1", "line 2", "line 3", "line 4", "line 5", "line 6", "line 7"}, lines)
+
+	// periods that don't use the TSDB index are rejected.
+	err = backfiller.Backfill(ctx, "fake", []logproto.Stream{{Labels: `{app="foo"}`, Entries: []logproto.Entry{{Timestamp: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), Line: "old"}}}})
+	require.Error(t, err)
+}
+
+func TestBackfillerDropsInvalidAndExpiredEntries(t *testing.T) {
+	dir := t.TempDir()
+	objectClient, err := local.NewFSObjectClient(local.FSConfig{Directory: filepath.Join(dir, "storage")})
+	require.NoError(t, err)
+
+	schemaCfg := config.SchemaConfig{
+		Configs: []config.PeriodConfig{
+			{
+				From:       config.DayTime{Time: model.Time(0)},
+				IndexType:  config.TSDBType,
+				ObjectType: config.StorageTypeFileSystem,
+				Schema:     "v12",
+				IndexTables: config.PeriodicTableConfig{
+					Prefix: "index_",
+					Period: 24 * time.Hour,
+				},
+			},
+		},
+	}
+	chunkClient := client.NewClient(objectClient, client.FSEncoder, schemaCfg)
+	indexClient := shipper_storage.NewIndexStorageClient(objectClient, "index/")
+
+	limits := validation.Limits{RetentionPeriod: model.Duration(24 * time.Hour)}
+	overrides, err := validation.NewOverrides(limits, nil)
+	require.NoError(t, err)
+
+	backfiller, err := NewBackfiller(BackfillConfig{
+		Encoding:        chunkenc.EncSnappy,
+		BlockSize:       256 * 1024,
+		TargetChunkSize: 1500 * 1024,
+		WorkingDir:      filepath.Join(dir, "working"),
+	}, schemaCfg, []client.Client{chunkClient}, indexClient, fakeStreamValidator{}, overrides, util_log.Logger)
+	require.NoError(t, err)
+
+	// chunk bounds have a millisecond precision.
+	now := time.Now().Truncate(time.Millisecond)
+	ctx := context.Background()
+	require.NoError(t, backfiller.Backfill(ctx, "fake", []logproto.Stream{
+		{Labels: `{app="foo"}`, Entries: []logproto.Entry{
+			{Timestamp: now.Add(-48 * time.Hour), Line: "expired"},
+			{Timestamp: now.Add(-time.Hour), Line: "invalid"},
+			{Timestamp: now.Add(-time.Hour), Line: "valid"},
+		}},
+		// streams without any entry left are skipped.
+		{Labels: `{app="bar"}`, Entries: []logproto.Entry{{Timestamp: now.Add(-48 * time.Hour), Line: "expired"}}},
+	}))
+
+	tables, err := indexClient.ListTables(ctx)
+	require.NoError(t, err)
+	require.Len(t, tables, 1)
+
+	files, err := indexClient.ListUserFiles(ctx, tables[0], "fake", true)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	dst := filepath.Join(t.TempDir(), strings.TrimSuffix(files[0].Name, ".gz"))
+	require.NoError(t, shipper_storage.DownloadFileFromStorage(dst, true, false, util_log.Logger, func() (io.ReadCloser, error) {
+		return indexClient.GetUserFile(ctx, tables[0], "fake", files[0].Name)
+	}))
+	idx, err := OpenShippableTSDB(dst)
+	require.NoError(t, err)
+	defer idx.Close()
+
+	refs, err := idx.(*TSDBFile).GetChunkRefs(ctx, "fake", 0, model.Latest, nil, nil, labels.MustNewMatcher(labels.MatchRegexp, "app", ".+"))
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	require.Equal(t, model.Fingerprint(labels.FromStrings("app", "foo").Hash()), refs[0].Fingerprint)
+	require.Equal(t, model.TimeFromUnixNano(now.Add(-time.Hour).UnixNano()), refs[0].Start)
+}
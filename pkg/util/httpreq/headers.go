@@ -16,6 +16,15 @@ var (
 
 	// LokiActorPathDelimiter is the delimiter used to serialise the hierarchy of the actor.
 	LokiActorPathDelimiter = "|"
+
+	// LokiQueryUserHeader is the name of the header that identifies the user issuing a query within a tenant.
+	LokiQueryUserHeader = "X-Loki-User"
+
+	// LokiQueryPriorityHeader is the name of the header that selects the priority class of a query in the query-scheduler.
+	LokiQueryPriorityHeader = "X-Loki-Query-Priority"
+
+	// queryUserTag is the key of the query tag that is used as fallback to identify the query user.
+	queryUserTag = "user"
 )
 
 func PropagateHeadersMiddleware(headers ...string) middleware.Interface {
@@ -34,6 +43,11 @@ func PropagateHeadersMiddleware(headers ...string) middleware.Interface {
 	})
 }
 
+// InjectHeader returns a copy of ctx that carries the value of the header, as done by PropagateHeadersMiddleware.
+func InjectHeader(ctx context.Context, name, value string) context.Context {
+	return context.WithValue(ctx, headerContextKey(name), value)
+}
+
 func ExtractHeader(ctx context.Context, name string) string {
 	s, _ := ctx.Value(headerContextKey(name)).(string)
 	return s
@@ -46,3 +60,39 @@ func ExtractActorPath(ctx context.Context) []string {
 	}
 	return strings.Split(value, LokiActorPathDelimiter)
 }
+
+// ExtractQueryUser returns the user issuing the query. The user is taken from
+// the X-Loki-User header, or from the "user" key of the query tags if the
+// header is not set.
+func ExtractQueryUser(ctx context.Context) string {
+	tags, _ := ctx.Value(QueryTagsHTTPHeader).(string)
+	return QueryUser(ExtractHeader(ctx, LokiQueryUserHeader), tags)
+}
+
+// QueryUser returns the user issuing a query given the values of its
+// X-Loki-User and X-Query-Tags headers.
+func QueryUser(user, tags string) string {
+	if user != "" {
+		return user
+	}
+	for _, tag := range strings.Split(tags, ",") {
+		k, v, ok := strings.Cut(tag, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(k), queryUserTag) {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// ExtractQueuePath returns the path of the sub-queue a query is enqueued in
+// by the query scheduler. The query user, if any, always forms the first
+// level of the hierarchy, so that queries of different users of the same
+// tenant are dequeued in a fair fashion.
+func ExtractQueuePath(ctx context.Context) []string {
+	path := ExtractActorPath(ctx)
+	user := ExtractQueryUser(ctx)
+	if user == "" {
+		return path
+	}
+	return append([]string{user}, path...)
+}
@@ -0,0 +1,54 @@
+package httpreq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractQueuePath(t *testing.T) {
+	for _, tc := range []struct {
+		desc    string
+		headers map[string]string
+		tags    string
+		exp     []string
+	}{
+		{
+			desc: "no user and no actor",
+			exp:  nil,
+		},
+		{
+			desc:    "actor path only",
+			headers: map[string]string{LokiActorPathHeader: "a|b"},
+			exp:     []string{"a", "b"},
+		},
+		{
+			desc:    "user header",
+			headers: map[string]string{LokiActorPathHeader: "a|b", LokiQueryUserHeader: "alice"},
+			exp:     []string{"alice", "a", "b"},
+		},
+		{
+			desc: "user from query tags",
+			tags: "Source=grafana, User=bob",
+			exp:  []string{"bob"},
+		},
+		{
+			desc:    "user header takes precedence over query tags",
+			headers: map[string]string{LokiQueryUserHeader: "alice"},
+			tags:    "user=bob",
+			exp:     []string{"alice"},
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := context.Background()
+			for k, v := range tc.headers {
+				ctx = context.WithValue(ctx, headerContextKey(k), v)
+			}
+			if tc.tags != "" {
+				ctx = context.WithValue(ctx, QueryTagsHTTPHeader, tc.tags)
+			}
+			require.Equal(t, tc.exp, ExtractQueuePath(ctx))
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/weaveworks/common/middleware"
@@ -21,6 +22,9 @@ var (
 	QueryQueueTimeHTTPHeader ctxKey = "X-Query-Queue-Time"
 )
 
+// rulerQueryTag is the query tag the ruler sets on queries it sends to the query-frontend.
+const rulerQueryTag = "ruler"
+
 func ExtractQueryTagsMiddleware() middleware.Interface {
 	return middleware.Func(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -37,6 +41,20 @@ func ExtractQueryTagsMiddleware() middleware.Interface {
 	})
 }
 
+// IsRulerQuery returns whether the query tags identify a query issued by the ruler.
+func IsRulerQuery(tags string) bool {
+	for _, tag := range strings.Split(tags, ",") {
+		tag = strings.TrimSpace(tag)
+		if strings.EqualFold(tag, rulerQueryTag) {
+			return true
+		}
+		if k, v, ok := strings.Cut(tag, "="); ok && strings.EqualFold(strings.TrimSpace(k), "source") && strings.EqualFold(strings.TrimSpace(v), rulerQueryTag) {
+			return true
+		}
+	}
+	return false
+}
+
 func ExtractQueryMetricsMiddleware() middleware.Interface {
 	return middleware.Func(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -19,6 +19,7 @@ import (
 	legacy "github.com/grafana/loki/pkg/loghttp/legacy"
 	"github.com/grafana/loki/pkg/logproto"
 	"github.com/grafana/loki/pkg/logqlmodel"
+	"github.com/grafana/loki/pkg/querier/queryrange/queryrangebase/definitions"
 )
 
 // covers responses from /loki/api/v1/query_range and /loki/api/v1/query
@@ -576,6 +577,23 @@ func Test_QueryResponseMarshalLoop(t *testing.T) {
 	}
 }
 
+func Test_WriteQueryResponseJSONWithWarnings(t *testing.T) {
+	warnings := []definitions.QueryWarning{{
+		Message: "results of shard 1_of_2 are missing: querier timeout",
+		Start:   time.Unix(0, 0).UTC(),
+		End:     time.Unix(3600, 0).UTC(),
+		Shard:   "1_of_2",
+	}}
+
+	var b bytes.Buffer
+	err := WriteQueryResponseJSON(logqlmodel.Result{Data: logqlmodel.Streams{}, Warnings: warnings}, &b)
+	require.NoError(t, err)
+
+	var resp loghttp.QueryResponse
+	require.NoError(t, json.Unmarshal(b.Bytes(), &resp))
+	require.Equal(t, warnings, resp.Warnings)
+}
+
 func Test_QueryResponseResultType(t *testing.T) {
 	for i, queryTest := range queryTests {
 		value, err := NewResultValue(queryTest.actual)
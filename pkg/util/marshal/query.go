@@ -105,8 +105,9 @@ func NewStream(s logproto.Stream) (loghttp.Stream, error) {
 // NewEntry constructs an Entry from a logproto.Entry
 func NewEntry(e logproto.Entry) loghttp.Entry {
 	return loghttp.Entry{
-		Timestamp: e.Timestamp,
-		Line:      e.Line,
+		Timestamp:          e.Timestamp,
+		Line:               e.Line,
+		StructuredMetadata: e.StructuredMetadata,
 	}
 }
 
@@ -190,6 +191,12 @@ func EncodeResult(v logqlmodel.Result, s *jsoniter.Stream) error {
 		return err
 	}
 
+	if len(v.Warnings) > 0 {
+		s.WriteMore()
+		s.WriteObjectField("warnings")
+		s.WriteVal(v.Warnings)
+	}
+
 	s.WriteObjectEnd()
 	return nil
 }
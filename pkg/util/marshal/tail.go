@@ -11,6 +11,7 @@ func NewTailResponse(r legacy.TailResponse) (loghttp.TailResponse, error) {
 	ret := loghttp.TailResponse{
 		Streams:        make([]loghttp.Stream, len(r.Streams)),
 		DroppedStreams: make([]loghttp.DroppedStream, len(r.DroppedEntries)),
+		Cursor:         r.Cursor,
 	}
 
 	for i, s := range r.Streams {
@@ -19,7 +19,10 @@ import (
 	"golang.org/x/time/rate"
 	"gopkg.in/yaml.v2"
 
+	"github.com/grafana/loki/pkg/distributor/ingestrules"
 	"github.com/grafana/loki/pkg/distributor/shardstreams"
+	"github.com/grafana/loki/pkg/ingester/aggregations"
+	"github.com/grafana/loki/pkg/loghttp/push"
 	"github.com/grafana/loki/pkg/logql/syntax"
 	ruler_config "github.com/grafana/loki/pkg/ruler/config"
 	"github.com/grafana/loki/pkg/ruler/util"
@@ -45,6 +48,15 @@ const (
 	// is used to keep track of the current number of healthy distributor replicas.
 	GlobalIngestionRateStrategy = "global"
 
+	// The actions of the cardinality guard for streams with a label that has too many values.
+	CardinalityGuardReport = "report"
+	CardinalityGuardStrip  = "strip"
+	CardinalityGuardReject = "reject"
+
+	// The actions of the ingesters for entries that are older than the out-of-order window.
+	OutOfOrderReject   = "reject"
+	OutOfOrderBackfill = "backfill"
+
 	bytesInMB = 1048576
 
 	defaultPerStreamRateLimit  = 3 << 20 // 3MB
@@ -59,19 +71,32 @@ const (
 // to support user-friendly duration format (e.g: "1h30m45s") in JSON value.
 type Limits struct {
 	// Distributor enforced limits.
-	IngestionRateStrategy       string           `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
-	IngestionRateMB             float64          `yaml:"ingestion_rate_mb" json:"ingestion_rate_mb"`
-	IngestionBurstSizeMB        float64          `yaml:"ingestion_burst_size_mb" json:"ingestion_burst_size_mb"`
-	MaxLabelNameLength          int              `yaml:"max_label_name_length" json:"max_label_name_length"`
-	MaxLabelValueLength         int              `yaml:"max_label_value_length" json:"max_label_value_length"`
-	MaxLabelNamesPerSeries      int              `yaml:"max_label_names_per_series" json:"max_label_names_per_series"`
-	RejectOldSamples            bool             `yaml:"reject_old_samples" json:"reject_old_samples"`
-	RejectOldSamplesMaxAge      model.Duration   `yaml:"reject_old_samples_max_age" json:"reject_old_samples_max_age"`
-	CreationGracePeriod         model.Duration   `yaml:"creation_grace_period" json:"creation_grace_period"`
-	EnforceMetricName           bool             `yaml:"enforce_metric_name" json:"enforce_metric_name"`
-	MaxLineSize                 flagext.ByteSize `yaml:"max_line_size" json:"max_line_size"`
-	MaxLineSizeTruncate         bool             `yaml:"max_line_size_truncate" json:"max_line_size_truncate"`
-	IncrementDuplicateTimestamp bool             `yaml:"increment_duplicate_timestamp" json:"increment_duplicate_timestamp"`
+	IngestionRateStrategy       string                   `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
+	IngestionRateMB             float64                  `yaml:"ingestion_rate_mb" json:"ingestion_rate_mb"`
+	IngestionBurstSizeMB        float64                  `yaml:"ingestion_burst_size_mb" json:"ingestion_burst_size_mb"`
+	MaxLabelNameLength          int                      `yaml:"max_label_name_length" json:"max_label_name_length"`
+	MaxLabelValueLength         int                      `yaml:"max_label_value_length" json:"max_label_value_length"`
+	MaxLabelNamesPerSeries      int                      `yaml:"max_label_names_per_series" json:"max_label_names_per_series"`
+	RejectOldSamples            bool                     `yaml:"reject_old_samples" json:"reject_old_samples"`
+	RejectOldSamplesMaxAge      model.Duration           `yaml:"reject_old_samples_max_age" json:"reject_old_samples_max_age"`
+	CreationGracePeriod         model.Duration           `yaml:"creation_grace_period" json:"creation_grace_period"`
+	EnforceMetricName           bool                     `yaml:"enforce_metric_name" json:"enforce_metric_name"`
+	MaxLineSize                 flagext.ByteSize         `yaml:"max_line_size" json:"max_line_size"`
+	MaxLineSizeTruncate         bool                     `yaml:"max_line_size_truncate" json:"max_line_size_truncate"`
+	IncrementDuplicateTimestamp bool                     `yaml:"increment_duplicate_timestamp" json:"increment_duplicate_timestamp"`
+	OTLPConfig                  push.OTLPConfig          `yaml:"otlp_config" json:"otlp_config" doc:"description=Mapping of OTLP attributes to stream labels of logs pushed to /otlp/v1/logs."`
+	ElasticsearchConfig         push.ElasticsearchConfig `yaml:"elasticsearch_config" json:"elasticsearch_config" doc:"description=Mapping of document fields to stream labels, timestamp and line of logs pushed to the Elasticsearch bulk API at /elasticsearch/_bulk."`
+	SplunkConfig                push.SplunkConfig        `yaml:"splunk_config" json:"splunk_config" doc:"description=Mapping of HEC events to stream labels of logs pushed to the Splunk HEC API at /services/collector."`
+	IngestRules                 []*ingestrules.Rule      `yaml:"ingest_rules,omitempty" json:"ingest_rules,omitempty" doc:"nocli|description=Rules that the distributor applies in order to the streams of the tenant before validation. Each rule has a name, an optional stream selector, and exactly one of the actions relabel_configs, drop, replace, labels, labeldrop and tenant."`
+	AllowStructuredMetadata     bool                     `yaml:"allow_structured_metadata" json:"allow_structured_metadata"`
+	MaxStructuredMetadataSize   flagext.ByteSize         `yaml:"max_structured_metadata_size" json:"max_structured_metadata_size"`
+
+	CardinalityGuardMaxLabelValues int    `yaml:"cardinality_guard_max_label_values" json:"cardinality_guard_max_label_values"`
+	CardinalityGuardAction         string `yaml:"cardinality_guard_action" json:"cardinality_guard_action"`
+
+	IngestionQuotaDaily        flagext.ByteSize `yaml:"ingestion_quota_daily" json:"ingestion_quota_daily"`
+	IngestionQuotaMonthly      flagext.ByteSize `yaml:"ingestion_quota_monthly" json:"ingestion_quota_monthly"`
+	IngestionQuotaWarningRatio float64          `yaml:"ingestion_quota_warning_ratio" json:"ingestion_quota_warning_ratio"`
 
 	// Ingester enforced limits.
 	MaxLocalStreamsPerUser  int              `yaml:"max_streams_per_user" json:"max_streams_per_user"`
@@ -79,6 +104,11 @@ type Limits struct {
 	UnorderedWrites         bool             `yaml:"unordered_writes" json:"unordered_writes"`
 	PerStreamRateLimit      flagext.ByteSize `yaml:"per_stream_rate_limit" json:"per_stream_rate_limit"`
 	PerStreamRateLimitBurst flagext.ByteSize `yaml:"per_stream_rate_limit_burst" json:"per_stream_rate_limit_burst"`
+	DedupWindow             model.Duration   `yaml:"dedup_window" json:"dedup_window"`
+	OutOfOrderWindow        model.Duration   `yaml:"out_of_order_window" json:"out_of_order_window"`
+	OutOfOrderAction        string           `yaml:"out_of_order_action" json:"out_of_order_action"`
+
+	ContinuousAggregations []*aggregations.Aggregation `yaml:"continuous_aggregations,omitempty" json:"continuous_aggregations,omitempty" doc:"nocli|description=Metric queries that the ingesters evaluate continuously on the entries of the tenant. Each aggregation has a name, a query that is a sum of a count_over_time, rate, bytes_over_time, bytes_rate or sum_over_time range aggregation, and an optional from time. The query frontend answers the queries that contain the query of an aggregation from its results."`
 
 	// Querier enforced limits.
 	MaxChunksPerQuery          int            `yaml:"max_chunks_per_query" json:"max_chunks_per_query"`
@@ -94,6 +124,7 @@ type Limits struct {
 	MaxEntriesLimitPerQuery    int            `yaml:"max_entries_limit_per_query" json:"max_entries_limit_per_query"`
 	MaxCacheFreshness          model.Duration `yaml:"max_cache_freshness_per_query" json:"max_cache_freshness_per_query"`
 	MaxQueriersPerTenant       int            `yaml:"max_queriers_per_tenant" json:"max_queriers_per_tenant"`
+	MaxConcurrentPerQueryUser  int            `yaml:"max_concurrent_requests_per_query_user" json:"max_concurrent_requests_per_query_user"`
 	QueryReadyIndexNumDays     int            `yaml:"query_ready_index_num_days" json:"query_ready_index_num_days"`
 	QueryTimeout               model.Duration `yaml:"query_timeout" json:"query_timeout"`
 
@@ -102,6 +133,7 @@ type Limits struct {
 	MinShardingLookback model.Duration   `yaml:"min_sharding_lookback" json:"min_sharding_lookback"`
 	MaxQueryBytesRead   flagext.ByteSize `yaml:"max_query_bytes_read" json:"max_query_bytes_read"`
 	MaxQuerierBytesRead flagext.ByteSize `yaml:"max_querier_bytes_read" json:"max_querier_bytes_read"`
+	AllowPartialResults bool             `yaml:"allow_partial_query_results" json:"allow_partial_query_results"`
 
 	// Ruler defaults and limits.
 
@@ -206,6 +238,17 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	_ = l.CreationGracePeriod.Set("10m")
 	f.Var(&l.CreationGracePeriod, "validation.create-grace-period", "Duration which table will be created/deleted before/after it's needed; we won't accept sample from before this time.")
 	f.BoolVar(&l.EnforceMetricName, "validation.enforce-metric-name", true, "Enforce every sample has a metric name.")
+	l.OTLPConfig.RegisterFlagsWithPrefix("distributor.otlp.", f)
+	l.ElasticsearchConfig.RegisterFlagsWithPrefix("distributor.elasticsearch.", f)
+	l.SplunkConfig.RegisterFlagsWithPrefix("distributor.splunk.", f)
+	f.BoolVar(&l.AllowStructuredMetadata, "validation.allow-structured-metadata", false, "Allow pushing structured metadata, key-value pairs that are stored with each log line without being indexed as stream labels. Entries with structured metadata are rejected when disabled. Requires unordered writes.")
+	_ = l.MaxStructuredMetadataSize.Set("64KB")
+	f.Var(&l.MaxStructuredMetadataSize, "validation.max-structured-metadata-size", "Maximum size of the names and values of the structured metadata of a log line. There is no limit when set to 0.")
+	f.IntVar(&l.CardinalityGuardMaxLabelValues, "distributor.cardinality-guard.max-label-values", 0, "Maximum number of distinct values of a single label of the tenant within the window of the cardinality guard. Labels with more values are reported in the distributor logs, the loki_distributor_cardinality_guard_label_values metric and the /distributor/cardinality API, and handled according to cardinality_guard_action. 0 to disable the cardinality guard.")
+	f.StringVar(&l.CardinalityGuardAction, "distributor.cardinality-guard.action", CardinalityGuardReport, "What the cardinality guard does with streams that have a label with more values than cardinality_guard_max_label_values. Supported values are report, which only reports the offending labels, strip, which removes the offending labels from the streams, and reject, which rejects the streams that have an offending label.")
+	f.Var(&l.IngestionQuotaDaily, "distributor.ingestion-quota.daily", "Maximum number of bytes of log lines that the tenant can ingest per UTC day, across the cluster. Pushes that exceed it are rejected until the next day. Example: 500GB. There is no quota when set to 0.")
+	f.Var(&l.IngestionQuotaMonthly, "distributor.ingestion-quota.monthly", "Maximum number of bytes of log lines that the tenant can ingest per UTC month, across the cluster. Pushes that exceed it are rejected until the next month. There is no quota when set to 0.")
+	f.Float64Var(&l.IngestionQuotaWarningRatio, "distributor.ingestion-quota.warning-ratio", 0.8, "Ratio of the daily and monthly ingestion quotas at which the distributors log a warning and increment the loki_distributor_ingestion_quota_warnings_total metric. 0 to disable the warnings.")
 	f.IntVar(&l.MaxEntriesLimitPerQuery, "validation.max-entries-limit", 5000, "Maximum number of log entries that will be returned for a query.")
 
 	f.IntVar(&l.MaxLocalStreamsPerUser, "ingester.max-streams-per-user", 0, "Maximum number of active streams per user, per ingester. 0 to disable.")
@@ -216,6 +259,9 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&l.PerStreamRateLimit, "ingester.per-stream-rate-limit", "Maximum byte rate per second per stream, also expressible in human readable forms (1MB, 256KB, etc).")
 	_ = l.PerStreamRateLimitBurst.Set(strconv.Itoa(defaultPerStreamBurstLimit))
 	f.Var(&l.PerStreamRateLimitBurst, "ingester.per-stream-rate-limit-burst", "Maximum burst bytes per stream, also expressible in human readable forms (1MB, 256KB, etc). This is how far above the rate limit a stream can 'burst' before the stream is limited.")
+	f.Var(&l.DedupWindow, "ingester.dedup-window", "Entries whose line already appeared in the same stream within this time window of their timestamp are dropped by the ingester. It catches duplicates sent by redundant agents with slightly different timestamps. The lines are remembered in memory only, up to ingester.dedup-max-lines-per-stream per stream, and are not recovered from the WAL, so duplicates of lines received before an ingester restarts are accepted. 0 to disable.")
+	f.Var(&l.OutOfOrderWindow, "ingester.out-of-order-window", "How far behind the newest entry of a stream the ingester accepts entries when unordered writes are enabled. 0 to accept entries up to half of the ingester max chunk age behind. The window is capped at the ingester max chunk age.")
+	f.StringVar(&l.OutOfOrderAction, "ingester.out-of-order-action", OutOfOrderReject, "What the ingester does with entries that are older than the out-of-order window. Supported values are reject, which rejects the entries, and backfill, which queues them to be written directly to storage in chunks of their own, without keeping them in memory. Backfilled entries are subject to the per-stream rate limit and the dedup window.")
 
 	f.IntVar(&l.MaxChunksPerQuery, "store.query-chunk-limit", 2e6, "Maximum number of chunks that can be fetched in a single query.")
 
@@ -240,11 +286,13 @@ func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 
 	f.Var(&l.MaxQueryBytesRead, "frontend.max-query-bytes-read", "Max number of bytes a query can fetch. Enforced in log and metric queries only when TSDB is used. The default value of 0 disables this limit.")
 	f.Var(&l.MaxQuerierBytesRead, "frontend.max-querier-bytes-read", "Max number of bytes a query can fetch after splitting and sharding. Enforced in log and metric queries only when TSDB is used. The default value of 0 disables this limit.")
+	f.BoolVar(&l.AllowPartialResults, "frontend.allow-partial-query-results", false, "Return the results of the successful splits and shards of a log or metric query when some of them fail with a server error, instead of failing the whole query. The missing time ranges and shards are listed in the warnings of the response. Partial results are not cached.")
 
 	_ = l.MaxCacheFreshness.Set("1m")
 	f.Var(&l.MaxCacheFreshness, "frontend.max-cache-freshness", "Most recent allowed cacheable result per-tenant, to prevent caching very recent results that might still be in flux.")
 
 	f.IntVar(&l.MaxQueriersPerTenant, "frontend.max-queriers-per-tenant", 0, "Maximum number of queriers that can handle requests for a single tenant. If set to 0 or value higher than number of available queriers, *all* queriers will handle requests for the tenant. Each frontend (or query-scheduler, if used) will select the same set of queriers for the same tenant (given that all queriers are connected to all frontends / query-schedulers). This option only works with queriers connecting to the query-frontend / query-scheduler, not when using downstream URL.")
+	f.IntVar(&l.MaxConcurrentPerQueryUser, "query-scheduler.max-concurrent-requests-per-query-user", 0, "Maximum number of requests of a single query user of a tenant that are handled by queriers at the same time. The query user is identified by the X-Loki-User header or the 'user' key of the X-Query-Tags header. Requests of different query users of the same tenant are dequeued in a round-robin fashion. 0 means unlimited. This option only works with the query-scheduler.")
 	f.IntVar(&l.QueryReadyIndexNumDays, "store.query-ready-index-num-days", 0, "Number of days of index to be kept always downloaded for queries. Applies only to per user index in boltdb-shipper index store. 0 to disable.")
 
 	_ = l.RulerEvaluationDelay.Set("0s")
@@ -309,10 +357,42 @@ func (l *Limits) Validate() error {
 		}
 	}
 
+	if err := ingestrules.Validate(l.IngestRules); err != nil {
+		return err
+	}
+
+	if err := aggregations.Validate(l.ContinuousAggregations); err != nil {
+		return err
+	}
+
+	if l.IngestionQuotaWarningRatio < 0 || l.IngestionQuotaWarningRatio > 1 {
+		return fmt.Errorf("ingestion quota warning ratio must be between 0 and 1, was %v", l.IngestionQuotaWarningRatio)
+	}
+
+	switch l.CardinalityGuardAction {
+	case "", CardinalityGuardReport, CardinalityGuardStrip, CardinalityGuardReject:
+	default:
+		return fmt.Errorf("invalid cardinality guard action %q, must be one of %s, %s or %s", l.CardinalityGuardAction, CardinalityGuardReport, CardinalityGuardStrip, CardinalityGuardReject)
+	}
+
+	if l.OutOfOrderWindow < 0 {
+		return fmt.Errorf("out-of-order window must not be negative, was %s", l.OutOfOrderWindow)
+	}
+
+	switch l.OutOfOrderAction {
+	case "", OutOfOrderReject, OutOfOrderBackfill:
+	default:
+		return fmt.Errorf("invalid out-of-order action %q, must be one of %s or %s", l.OutOfOrderAction, OutOfOrderReject, OutOfOrderBackfill)
+	}
+
 	if _, err := deletionmode.ParseMode(l.DeletionMode); err != nil {
 		return err
 	}
 
+	if l.AllowStructuredMetadata && !l.UnorderedWrites {
+		return errors.New("structured metadata requires unordered writes to be enabled")
+	}
+
 	if l.CompactorDeletionEnabled {
 		level.Warn(util_log.Logger).Log("msg", "The compactor.allow-deletes configuration option has been deprecated and will be ignored. Instead, use deletion_mode in the limits_configs to adjust deletion functionality")
 	}
@@ -455,6 +535,11 @@ func (o *Overrides) MaxQueriersPerUser(userID string) int {
 	return o.getOverridesForUser(userID).MaxQueriersPerTenant
 }
 
+// MaxConcurrentRequestsPerQueryUser returns the maximum number of in-flight requests of a single query user of this tenant.
+func (o *Overrides) MaxConcurrentRequestsPerQueryUser(userID string) int {
+	return o.getOverridesForUser(userID).MaxConcurrentPerQueryUser
+}
+
 // QueryReadyIndexNumDays returns the number of days for which we have to be query ready for a user.
 func (o *Overrides) QueryReadyIndexNumDays(userID string) int {
 	return o.getOverridesForUser(userID).QueryReadyIndexNumDays
@@ -507,6 +592,11 @@ func (o *Overrides) MaxQuerierBytesRead(_ context.Context, userID string) int {
 	return o.getOverridesForUser(userID).MaxQuerierBytesRead.Val()
 }
 
+// AllowPartialQueryResults returns whether a query may return partial results when some of its splits or shards fail.
+func (o *Overrides) AllowPartialQueryResults(_ context.Context, userID string) bool {
+	return o.getOverridesForUser(userID).AllowPartialResults
+}
+
 // MaxConcurrentTailRequests returns the limit to number of concurrent tail requests.
 func (o *Overrides) MaxConcurrentTailRequests(ctx context.Context, userID string) int {
 	return o.getOverridesForUser(userID).MaxConcurrentTailRequests
@@ -690,6 +780,42 @@ func (o *Overrides) DeletionMode(userID string) string {
 	return o.getOverridesForUser(userID).DeletionMode
 }
 
+func (o *Overrides) OTLPConfig(userID string) push.OTLPConfig {
+	return o.getOverridesForUser(userID).OTLPConfig
+}
+
+func (o *Overrides) ElasticsearchConfig(userID string) push.ElasticsearchConfig {
+	return o.getOverridesForUser(userID).ElasticsearchConfig
+}
+
+func (o *Overrides) SplunkConfig(userID string) push.SplunkConfig {
+	return o.getOverridesForUser(userID).SplunkConfig
+}
+
+func (o *Overrides) IngestRules(userID string) []*ingestrules.Rule {
+	return o.getOverridesForUser(userID).IngestRules
+}
+
+func (o *Overrides) CardinalityGuardMaxLabelValues(userID string) int {
+	return o.getOverridesForUser(userID).CardinalityGuardMaxLabelValues
+}
+
+func (o *Overrides) CardinalityGuardAction(userID string) string {
+	return o.getOverridesForUser(userID).CardinalityGuardAction
+}
+
+func (o *Overrides) IngestionQuotaDaily(userID string) int {
+	return o.getOverridesForUser(userID).IngestionQuotaDaily.Val()
+}
+
+func (o *Overrides) IngestionQuotaMonthly(userID string) int {
+	return o.getOverridesForUser(userID).IngestionQuotaMonthly.Val()
+}
+
+func (o *Overrides) IngestionQuotaWarningRatio(userID string) float64 {
+	return o.getOverridesForUser(userID).IngestionQuotaWarningRatio
+}
+
 func (o *Overrides) ShardStreams(userID string) *shardstreams.Config {
 	return o.getOverridesForUser(userID).ShardStreams
 }
@@ -719,10 +845,34 @@ func (o *Overrides) PerStreamRateLimit(userID string) RateLimit {
 	}
 }
 
+func (o *Overrides) DedupWindow(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).DedupWindow)
+}
+
+func (o *Overrides) OutOfOrderWindow(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).OutOfOrderWindow)
+}
+
+func (o *Overrides) OutOfOrderAction(userID string) string {
+	return o.getOverridesForUser(userID).OutOfOrderAction
+}
+
+func (o *Overrides) ContinuousAggregations(userID string) []*aggregations.Aggregation {
+	return o.getOverridesForUser(userID).ContinuousAggregations
+}
+
 func (o *Overrides) IncrementDuplicateTimestamps(userID string) bool {
 	return o.getOverridesForUser(userID).IncrementDuplicateTimestamp
 }
 
+func (o *Overrides) AllowStructuredMetadata(userID string) bool {
+	return o.getOverridesForUser(userID).AllowStructuredMetadata
+}
+
+func (o *Overrides) MaxStructuredMetadataSize(userID string) int {
+	return o.getOverridesForUser(userID).MaxStructuredMetadataSize.Val()
+}
+
 func (o *Overrides) getOverridesForUser(userID string) *Limits {
 	if o.tenantLimits != nil {
 		l := o.tenantLimits.TenantLimits(userID)
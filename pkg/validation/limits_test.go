@@ -300,4 +300,10 @@ func TestLimitsValidation(t *testing.T) {
 		limits := Limits{DeletionMode: tc.mode}
 		require.True(t, errors.Is(limits.Validate(), tc.expected))
 	}
+
+	limits := Limits{DeletionMode: "disabled", CardinalityGuardAction: "drop"}
+	require.EqualError(t, limits.Validate(), `invalid cardinality guard action "drop", must be one of report, strip or reject`)
+
+	limits = Limits{DeletionMode: "disabled", OutOfOrderAction: "drop"}
+	require.EqualError(t, limits.Validate(), `invalid out-of-order action "drop", must be one of reject or backfill`)
 }
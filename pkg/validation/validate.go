@@ -60,6 +60,23 @@ const (
 	// DuplicateLabelNames is a reason for discarding a log line which has duplicate label names
 	DuplicateLabelNames         = "duplicate_label_names"
 	DuplicateLabelNamesErrorMsg = "stream '%s' has duplicate label name: '%s'"
+	// DisallowedStructuredMetadata is a reason for discarding a log line with structured metadata
+	// when the tenant is not allowed to send structured metadata.
+	DisallowedStructuredMetadata         = "disallowed_structured_metadata"
+	DisallowedStructuredMetadataErrorMsg = "stream '%s' includes structured metadata, but this feature is disallowed. Please see `limits_config.allow_structured_metadata` or contact your Loki administrator to enable it."
+	// InvalidStructuredMetadata is a reason for discarding a log line with structured metadata names
+	// that are not valid label names.
+	InvalidStructuredMetadata         = "invalid_structured_metadata"
+	InvalidStructuredMetadataErrorMsg = "stream '%s' has invalid structured metadata name: '%s'"
+	// StructuredMetadataTooLarge is a reason for discarding a log line with too much structured metadata
+	StructuredMetadataTooLarge         = "structured_metadata_too_large"
+	StructuredMetadataTooLargeErrorMsg = "stream '%s' has structured metadata too large: '%d' bytes, limit: '%d' bytes. Please see `limits_config.max_structured_metadata_size` or contact your Loki administrator to increase it."
+	// QuotaExceeded is a reason for discarding log lines when the daily or monthly ingestion quota of the tenant is exceeded.
+	QuotaExceeded         = "quota_exceeded"
+	QuotaExceededErrorMsg = "Ingestion quota exceeded for user %s (%s limit: %d bytes, used: %d bytes) while attempting to ingest '%d' bytes, wait for the quota to reset or contact your Loki administrator to see if the quota can be increased"
+	// CardinalityGuard is a reason for discarding log lines of a stream with a label that has too many distinct values.
+	CardinalityGuard         = "cardinality_guard"
+	CardinalityGuardErrorMsg = "stream '%s' has label '%s' with more than %d distinct values. Remove the label from the stream, or see `limits_config.cardinality_guard_max_label_values` or contact your Loki administrator to increase the limit."
 )
 
 type ErrStreamRateLimit struct {